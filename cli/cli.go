@@ -71,15 +71,19 @@ func init() {
 
 	cockroachCmd.AddCommand(
 		startCmd,
+		initCmd,
 		certCmd,
 		freezeClusterCmd,
 		quitCmd,
 
 		sqlShellCmd,
+		demoCmd,
 		userCmd,
 		zoneCmd,
 		nodeCmd,
 		dumpCmd,
+		userfileCmd,
+		workloadCmd,
 
 		// Miscellaneous commands.
 		// TODO(pmattis): stats
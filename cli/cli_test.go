@@ -829,15 +829,19 @@ Usage:
 
 Available Commands:
   start          start a node
+  init           initialize a cluster
   cert           create ca, node, and client certs
   freeze-cluster freeze the cluster in preparation for an update
   quit           drain and shutdown node
 
   sql            open a sql shell
+  demo           open a sql shell to a temporary, in-memory cluster
   user           get, set, list and remove users
   zone           get, set, list and remove zones
   node           list nodes and show their status
   dump           dump sql tables
+  userfile       upload, list and delete files stored in the cluster
+  workload       generate data and load for benchmarks and demos
 
   gen            generate manpages and bash completion file
   version        output version information
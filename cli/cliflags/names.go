@@ -24,7 +24,10 @@ const (
 	DatabaseName          = "database"
 	DepsName              = "deps"
 	ExecuteName           = "execute"
+	WatchName             = "watch"
+	SafeUpdatesName       = "safe-updates"
 	PrettyName            = "pretty"
+	FormatName            = "format"
 	JoinName              = "join"
 	HostName              = "host"
 	InsecureName          = "insecure"
@@ -48,4 +51,11 @@ const (
 	SizesName             = "sizes"
 	RaftTickIntervalName  = "raft-tick-interval"
 	UndoFreezeClusterName = "undo"
+	ConcurrencyName       = "concurrency"
+	DurationName          = "duration"
+	MaxOpsName            = "max-ops"
+	RowsName              = "rows"
+	ReadPercentName       = "read-percent"
+	NodesName             = "nodes"
+	DatasetName           = "dataset"
 )
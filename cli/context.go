@@ -18,7 +18,9 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/base"
 	"github.com/cockroachdb/cockroach/keys"
@@ -47,13 +49,93 @@ type cliContext struct {
 	// Embed the base context.
 	*base.Context
 
-	// prettyFmt indicates whether tables should be pretty-formatted in
-	// the output during non-interactive execution.
-	prettyFmt bool
+	// tableDisplayFormat indicates how query results should be
+	// formatted in the output.
+	tableDisplayFormat tableDisplayFormat
 }
 
 func (ctx *cliContext) InitCLIDefaults() {
-	ctx.prettyFmt = false
+	ctx.tableDisplayFormat = tableDisplayTSV
+}
+
+// tableDisplayFormat identifies the format with which SQL tables are
+// printed.
+type tableDisplayFormat int
+
+const (
+	// tableDisplayTSV prints tab-separated values, preceded by a row count.
+	tableDisplayTSV tableDisplayFormat = iota
+	// tableDisplayCSV prints comma-separated values.
+	tableDisplayCSV
+	// tableDisplayPretty prints tables using ASCII art.
+	tableDisplayPretty
+	// tableDisplayRecords prints one "column | value" line per column,
+	// with one block per row. This is more legible than tableDisplayPretty
+	// for wide rows.
+	tableDisplayRecords
+	// tableDisplayJSON prints a JSON array with one object per row.
+	tableDisplayJSON
+)
+
+var tableDisplayFormatNames = [...]string{
+	tableDisplayTSV:     "tsv",
+	tableDisplayCSV:     "csv",
+	tableDisplayPretty:  "pretty",
+	tableDisplayRecords: "records",
+	tableDisplayJSON:    "json",
+}
+
+// String implements the pflag.Value interface.
+func (f *tableDisplayFormat) String() string {
+	return tableDisplayFormatNames[*f]
+}
+
+// Type implements the pflag.Value interface.
+func (f *tableDisplayFormat) Type() string {
+	return "string"
+}
+
+// Set implements the pflag.Value interface.
+func (f *tableDisplayFormat) Set(s string) error {
+	for i, name := range tableDisplayFormatNames {
+		if strings.EqualFold(s, name) {
+			*f = tableDisplayFormat(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid table display format: %s (possible values: %s)",
+		s, strings.Join(tableDisplayFormatNames[:], ", "))
+}
+
+// prettyValue implements the --pretty flag as an alias for
+// --format=pretty (or --format=tsv if false).
+type prettyValue struct {
+	format *tableDisplayFormat
+}
+
+func (p prettyValue) IsBoolFlag() bool {
+	return true
+}
+
+func (p prettyValue) String() string {
+	return strconv.FormatBool(*p.format == tableDisplayPretty)
+}
+
+func (p prettyValue) Type() string {
+	return "bool"
+}
+
+func (p prettyValue) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	if v {
+		*p.format = tableDisplayPretty
+	} else {
+		*p.format = tableDisplayTSV
+	}
+	return nil
 }
 
 type sqlContext struct {
@@ -62,6 +144,14 @@ type sqlContext struct {
 
 	// execStmts is a list of statements to execute.
 	execStmts statementsValue
+
+	// watchInterval, if non-zero, causes execStmts to be re-executed
+	// at this interval until interrupted.
+	watchInterval time.Duration
+
+	// safeUpdates, if set, causes the interactive shell to reject the
+	// statements which are likely to be typos (see sql_safe_updates).
+	safeUpdates bool
 }
 
 type keyType int
@@ -140,6 +230,22 @@ func (k *mvccKey) Type() string {
 	return "engine.MVCCKey"
 }
 
+type demoContext struct {
+	// nodes is the number of nodes of the demo cluster.
+	nodes int
+	// dataset is the name of the workload whose tables are loaded into the
+	// demo cluster, if any.
+	dataset string
+}
+
+type workloadContext struct {
+	concurrency int
+	duration    time.Duration
+	maxOps      int64
+	rows        int
+	readPercent int
+}
+
 type debugContext struct {
 	startKey, endKey engine.MVCCKey
 	values           bool
@@ -340,7 +340,7 @@ func tryRangeDescriptor(kv engine.MVCCKeyValue) (string, error) {
 
 func printRangeDescriptor(kv engine.MVCCKeyValue) (bool, error) {
 	if out, err := tryRangeDescriptor(kv); err == nil {
-		fmt.Printf("%s %q: %s\n", kv.Key.Timestamp, kv.Key.Key, out)
+		fmt.Printf("%s %s: %s\n", kv.Key.Timestamp, kv.Key.Key, out)
 	}
 	return false, nil
 }
@@ -454,9 +454,9 @@ func tryRaftLogEntry(kv engine.MVCCKeyValue) (string, error) {
 
 func printRaftLogEntry(kv engine.MVCCKeyValue) (bool, error) {
 	if out, err := tryRaftLogEntry(kv); err != nil {
-		fmt.Printf("%q: %v\n\n", kv.Key.Key, err)
+		fmt.Printf("%s: %v\n\n", kv.Key.Key, err)
 	} else {
-		fmt.Printf("%q: %s\n", kv.Key.Key, out)
+		fmt.Printf("%s: %s\n", kv.Key.Key, out)
 	}
 	return false, nil
 }
@@ -767,6 +767,7 @@ var debugCmds = []*cobra.Command{
 	debugCheckStoreCmd,
 	debugCompactCmd,
 	debugSSTablesCmd,
+	debugDoctorCmd,
 	kvCmd,
 	rangeCmd,
 	debugEnvCmd,
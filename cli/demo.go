@@ -0,0 +1,162 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/server"
+	"github.com/cockroachdb/cockroach/storage/engine"
+	"github.com/cockroachdb/cockroach/util/stop"
+)
+
+// demoStoreSize is the size of the in-memory store of each demo node.
+const demoStoreSize = 512 << 20
+
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "open a sql shell to a temporary, in-memory cluster",
+	Long: `
+Start a temporary cluster of --nodes in-memory nodes, optionally load the
+tables of one of the built-in workloads given with --dataset, and open an
+interactive SQL shell to it. All data is lost when the shell exits.
+`,
+	RunE:         runDemo,
+	SilenceUsage: true,
+}
+
+// demoCluster is a set of in-memory nodes started by the demo command.
+type demoCluster struct {
+	stoppers []*stop.Stopper
+	servers  []*server.Server
+}
+
+// startDemoCluster starts the given number of insecure in-memory nodes. The
+// first node bootstraps the cluster and the others join it.
+func startDemoCluster(nodes int) (*demoCluster, error) {
+	if nodes < 1 {
+		return nil, errors.Errorf("invalid number of nodes %d", nodes)
+	}
+	c := &demoCluster{}
+	var joinAddr string
+	for i := 0; i < nodes; i++ {
+		stopper := stop.NewStopper()
+		c.stoppers = append(c.stoppers, stopper)
+
+		ctx := server.MakeContext()
+		ctx.Insecure = true
+		ctx.User = security.NodeUser
+		ctx.Addr = "127.0.0.1:0"
+		ctx.HTTPAddr = "127.0.0.1:0"
+		if joinAddr != "" {
+			ctx.JoinList = []string{joinAddr}
+		}
+		ctx.Engines = []engine.Engine{engine.NewInMem(roachpb.Attributes{}, demoStoreSize, stopper)}
+		if err := ctx.InitNode(); err != nil {
+			c.stop()
+			return nil, err
+		}
+		s, err := server.NewServer(ctx, stopper)
+		if err != nil {
+			c.stop()
+			return nil, err
+		}
+		if err := s.Start(); err != nil {
+			c.stop()
+			return nil, err
+		}
+		c.servers = append(c.servers, s)
+		if i == 0 {
+			u, err := s.PGURL(security.RootUser)
+			if err != nil {
+				c.stop()
+				return nil, err
+			}
+			joinAddr = u.Host
+		}
+	}
+	return c, nil
+}
+
+// stop shuts down the nodes of the cluster, last started first.
+func (c *demoCluster) stop() {
+	for i := len(c.stoppers) - 1; i >= 0; i-- {
+		c.stoppers[i].Stop()
+	}
+}
+
+func runDemo(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		mustUsage(cmd)
+		return errMissingParams
+	}
+	var w workload
+	if demoCtx.dataset != "" {
+		var ok bool
+		if w, ok = workloads[demoCtx.dataset]; !ok {
+			return errors.Errorf("unknown dataset %q (available: %s)",
+				demoCtx.dataset, strings.Join(workloadNames(), ", "))
+		}
+	}
+
+	c, err := startDemoCluster(demoCtx.nodes)
+	if err != nil {
+		return errors.Wrap(err, "failed to start demo cluster")
+	}
+	defer c.stop()
+
+	pgURL, err := c.servers[0].PGURL(security.RootUser)
+	if err != nil {
+		return err
+	}
+	if w.name != "" {
+		pgURL.Path = w.name
+	}
+	conn := makeSQLConn(pgURL.String())
+	defer conn.Close()
+
+	if w.name != "" {
+		if err := initWorkload(conn, w); err != nil {
+			return errors.Wrapf(err, "failed to load dataset %s", w.name)
+		}
+	}
+
+	if len(sqlCtx.execStmts) > 0 {
+		return runStatements(conn, sqlCtx.execStmts, cliCtx.tableDisplayFormat)
+	}
+
+	fmt.Printf(`#
+# Welcome to the CockroachDB demo.
+#
+# This is a temporary, in-memory cluster of %d node(s) which is shut down,
+# and all of its data discarded, when this shell exits.
+#
+# admin: %s
+# sql:   %s
+#
+`, demoCtx.nodes, c.servers[0].AdminURL(), pgURL)
+	if w.name != "" {
+		fmt.Printf("# The tables of the %s workload have been loaded into database %s.\n#\n",
+			w.name, w.name)
+	}
+	return runInteractive(conn, &readline.Config{DisableAutoSaveHistory: true})
+}
@@ -0,0 +1,57 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/testutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestDemo starts demo clusters with a dataset and runs a statement against
+// them.
+func TestDemo(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func() {
+		sqlCtx.execStmts = nil
+		demoCtx = demoContext{}
+	}()
+
+	for _, nodes := range []string{"1", "3"} {
+		sqlCtx.execStmts = nil
+		var runErr error
+		out, err := captureOutput(func() {
+			runErr = Run([]string{"demo", "--nodes=" + nodes, "--dataset=bank", "--rows=10",
+				"-e", "SELECT count(*), sum(balance) FROM accounts"})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if runErr != nil {
+			t.Fatalf("%s nodes: %s", nodes, runErr)
+		}
+		if !strings.Contains(out, "10\t10000") {
+			t.Errorf("%s nodes: expected 10 accounts with a total balance of 10000, got:\n%s", nodes, out)
+		}
+	}
+
+	sqlCtx.execStmts = nil
+	if err := Run([]string{"demo", "--nodes=1", "--dataset=unknown"}); !testutils.IsError(err, `unknown dataset "unknown"`) {
+		t.Errorf("expected unknown dataset error, got %v", err)
+	}
+}
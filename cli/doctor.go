@@ -0,0 +1,435 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/storage/engine"
+	"github.com/cockroachdb/cockroach/util/encoding"
+	"github.com/cockroachdb/cockroach/util/hlc"
+	"github.com/cockroachdb/cockroach/util/stop"
+)
+
+var debugDoctorCmd = &cobra.Command{
+	Use:   "doctor [directory]",
+	Short: "check the consistency of the SQL descriptors",
+	Long: `
+Check the consistency of the database and table descriptors and of the
+namespace, and suggest repairs for the problems found.
+
+If a store directory is given, the descriptors are read from the store
+directly, which must not be in use by a running node; otherwise they are
+read from the cluster over a SQL connection.
+
+Capable of detecting the following errors:
+* Namespace entries referring to missing descriptors, or descriptors
+  without a matching namespace entry
+* Tables belonging to missing databases
+* Malformed table descriptors
+* Orphaned schema change mutations
+* Foreign keys and interleaves referring to missing tables or indexes,
+  and back-references (ReferencedBy, InterleavedBy) without a matching
+  reference
+`,
+	RunE: runDebugDoctor,
+}
+
+// doctorNamespaceEntry is a row of the system.namespace table.
+type doctorNamespaceEntry struct {
+	parentID sqlbase.ID
+	name     string
+	id       sqlbase.ID
+}
+
+// doctorProblem is an inconsistency found in the descriptors, together
+// with a suggested repair.
+type doctorProblem struct {
+	id      sqlbase.ID
+	problem string
+	repair  string
+}
+
+func runDebugDoctor(cmd *cobra.Command, args []string) error {
+	var descs map[sqlbase.ID]*sqlbase.Descriptor
+	var namespace []doctorNamespaceEntry
+	switch len(args) {
+	case 0:
+		conn, err := makeSQLClient()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if descs, namespace, err = readDescriptorsFromCluster(conn); err != nil {
+			return err
+		}
+	case 1:
+		stopper := stop.NewStopper()
+		defer stopper.Stop()
+		db, err := openStore(cmd, args[0], stopper)
+		if err != nil {
+			return err
+		}
+		if descs, namespace, err = readDescriptorsFromStore(db); err != nil {
+			return err
+		}
+	default:
+		mustUsage(cmd)
+		return errMissingParams
+	}
+
+	problems := checkDescriptors(descs, namespace)
+	printDoctorProblems(os.Stdout, problems)
+	if len(problems) > 0 {
+		return errors.Errorf("found %d problems in %d descriptors", len(problems), len(descs))
+	}
+	fmt.Printf("no problems found in %d descriptors\n", len(descs))
+	return nil
+}
+
+func printDoctorProblems(w io.Writer, problems []doctorProblem) {
+	for _, p := range problems {
+		fmt.Fprintf(w, "descriptor %d: %s\n  repair: %s\n", p.id, p.problem, p.repair)
+	}
+}
+
+// readDescriptorsFromCluster reads the descriptors and the namespace over
+// a SQL connection.
+func readDescriptorsFromCluster(
+	conn *sqlConn,
+) (map[sqlbase.ID]*sqlbase.Descriptor, []doctorNamespaceEntry, error) {
+	descs := make(map[sqlbase.ID]*sqlbase.Descriptor)
+	if err := scanRows(conn, `SELECT id, descriptor FROM system.descriptor`, func(vals []driver.Value) error {
+		var desc sqlbase.Descriptor
+		if err := proto.Unmarshal(vals[1].([]byte), &desc); err != nil {
+			return err
+		}
+		descs[sqlbase.ID(vals[0].(int64))] = &desc
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	var namespace []doctorNamespaceEntry
+	if err := scanRows(conn, `SELECT parentID, name, id FROM system.namespace`, func(vals []driver.Value) error {
+		namespace = append(namespace, doctorNamespaceEntry{
+			parentID: sqlbase.ID(vals[0].(int64)),
+			name:     vals[1].(string),
+			id:       sqlbase.ID(vals[2].(int64)),
+		})
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	return descs, namespace, nil
+}
+
+// scanRows runs the query and calls fn with the values of each row.
+func scanRows(conn *sqlConn, query string, fn func([]driver.Value) error) error {
+	rows, err := conn.Query(query, nil)
+	if err != nil {
+		return err
+	}
+	vals := make([]driver.Value, len(rows.Columns()))
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if err := fn(vals); err != nil {
+			_ = rows.Close()
+			return err
+		}
+	}
+	return rows.Close()
+}
+
+// readDescriptorsFromStore reads the descriptors and the namespace from
+// the latest values of the system tables in a store.
+func readDescriptorsFromStore(
+	db engine.Engine,
+) (map[sqlbase.ID]*sqlbase.Descriptor, []doctorNamespaceEntry, error) {
+	descs := make(map[sqlbase.ID]*sqlbase.Descriptor)
+	start := roachpb.Key(keys.MakeTablePrefix(keys.DescriptorTableID))
+	if _, err := engine.MVCCIterate(context.Background(), db, start, start.PrefixEnd(), hlc.MaxTimestamp,
+		false /* !consistent */, nil, /* txn */
+		false /* !reverse */, func(kv roachpb.KeyValue) (bool, error) {
+			// The key is /Table/<DescriptorTableID>/<index>/<id>/<family>.
+			_, id, err := decodeSystemTableKey(kv.Key, keys.DescriptorTableID)
+			if err != nil {
+				return false, err
+			}
+			var desc sqlbase.Descriptor
+			if err := kv.Value.GetProto(&desc); err != nil {
+				return false, err
+			}
+			descs[sqlbase.ID(id)] = &desc
+			return false, nil
+		}); err != nil {
+		return nil, nil, err
+	}
+
+	var namespace []doctorNamespaceEntry
+	start = roachpb.Key(keys.MakeTablePrefix(keys.NamespaceTableID))
+	if _, err := engine.MVCCIterate(context.Background(), db, start, start.PrefixEnd(), hlc.MaxTimestamp,
+		false /* !consistent */, nil, /* txn */
+		false /* !reverse */, func(kv roachpb.KeyValue) (bool, error) {
+			// The key is /Table/<NamespaceTableID>/<index>/<parentID>/<name>/<family>.
+			rest, parentID, err := decodeSystemTableKey(kv.Key, keys.NamespaceTableID)
+			if err != nil {
+				return false, err
+			}
+			_, name, err := encoding.DecodeBytesAscending(rest, nil)
+			if err != nil {
+				return false, err
+			}
+			id, err := kv.Value.GetInt()
+			if err != nil {
+				return false, err
+			}
+			namespace = append(namespace, doctorNamespaceEntry{
+				parentID: sqlbase.ID(parentID),
+				name:     string(name),
+				id:       sqlbase.ID(id),
+			})
+			return false, nil
+		}); err != nil {
+		return nil, nil, err
+	}
+	return descs, namespace, nil
+}
+
+// decodeSystemTableKey decodes the table and index prefix of a key of the
+// given system table, and the first column of the primary key.
+func decodeSystemTableKey(key roachpb.Key, tableID uint32) ([]byte, uint64, error) {
+	rest, id, err := keys.DecodeTablePrefix(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	if id != uint64(tableID) {
+		return nil, 0, errors.Errorf("key %s is not in table %d", key, tableID)
+	}
+	rest, _, err = encoding.DecodeUvarintAscending(rest)
+	if err != nil {
+		return nil, 0, err
+	}
+	return encoding.DecodeUvarintAscending(rest)
+}
+
+// checkDescriptors validates the descriptors and the namespace against
+// each other and returns the problems found, ordered by descriptor ID.
+func checkDescriptors(
+	descs map[sqlbase.ID]*sqlbase.Descriptor, namespace []doctorNamespaceEntry,
+) []doctorProblem {
+	var problems []doctorProblem
+	report := func(id sqlbase.ID, repair string, format string, args ...interface{}) {
+		problems = append(problems, doctorProblem{id: id, problem: fmt.Sprintf(format, args...), repair: repair})
+	}
+	tables := make(map[sqlbase.ID]*sqlbase.TableDescriptor)
+	for id, desc := range descs {
+		if table := desc.GetTable(); table != nil {
+			tables[id] = table
+		}
+	}
+
+	// Check the namespace against the descriptors.
+	named := make(map[sqlbase.ID]bool)
+	for _, e := range namespace {
+		named[e.id] = true
+		desc, ok := descs[e.id]
+		if !ok {
+			report(e.id, fmt.Sprintf("DELETE FROM system.namespace WHERE parentID = %d AND name = %s",
+				e.parentID, parser.NewDString(e.name).String()),
+				"namespace entry %q in parent %d refers to a missing descriptor", e.name, e.parentID)
+			continue
+		}
+		var name string
+		var parentID sqlbase.ID
+		if table := desc.GetTable(); table != nil {
+			name, parentID = table.Name, table.ParentID
+		} else if db := desc.GetDatabase(); db != nil {
+			name = db.Name
+		}
+		if name != e.name || parentID != e.parentID {
+			report(e.id, "rename the namespace entry or the descriptor so that they match",
+				"namespace entry %q in parent %d does not match descriptor %q in parent %d",
+				e.name, e.parentID, name, parentID)
+		}
+	}
+
+	ids := make([]int, 0, len(descs))
+	for id := range descs {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	for _, i := range ids {
+		id := sqlbase.ID(i)
+		desc := descs[id]
+		// Tables being dropped have no namespace entry.
+		if table := desc.GetTable(); table != nil && table.State == sqlbase.TableDescriptor_DROP {
+			continue
+		}
+		if !named[id] && id > keys.MaxReservedDescID {
+			name := desc.GetName()
+			var parentID sqlbase.ID
+			if table := desc.GetTable(); table != nil {
+				parentID = table.ParentID
+			}
+			report(id, fmt.Sprintf("INSERT INTO system.namespace VALUES (%d, %s, %d)",
+				parentID, parser.NewDString(name).String(), id),
+				"descriptor %q has no namespace entry", name)
+		}
+		if table := desc.GetTable(); table != nil {
+			if parent, ok := descs[table.ParentID]; !ok || parent.GetDatabase() == nil {
+				report(id, "drop the table, or move it to an existing database",
+					"table %q belongs to %d, which is not a database", table.Name, table.ParentID)
+			}
+			problems = append(problems, checkTableDescriptor(table, tables)...)
+		}
+	}
+	return problems
+}
+
+// checkTableDescriptor validates a table descriptor and its references to
+// other tables.
+func checkTableDescriptor(
+	table *sqlbase.TableDescriptor, tables map[sqlbase.ID]*sqlbase.TableDescriptor,
+) []doctorProblem {
+	var problems []doctorProblem
+	report := func(repair string, format string, args ...interface{}) {
+		problems = append(problems, doctorProblem{
+			id:      table.ID,
+			problem: fmt.Sprintf("table %q: %s", table.Name, fmt.Sprintf(format, args...)),
+			repair:  repair,
+		})
+	}
+
+	if err := table.Validate(); err != nil {
+		report("correct the descriptor by hand; it cannot be used until it is valid",
+			"invalid descriptor: %s", err)
+	}
+
+	for _, m := range table.Mutations {
+		if m.Descriptor_ == nil {
+			report("remove the mutation from the descriptor",
+				"mutation %d has neither a column nor an index", m.MutationID)
+		} else if m.MutationID >= table.NextMutationID {
+			report("remove the mutation from the descriptor, or increase NextMutationID",
+				"mutation %d is orphaned: the next mutation ID is %d", m.MutationID, table.NextMutationID)
+		}
+	}
+
+	// findIndex returns the index of the given table, or an explanation of
+	// why it cannot be found.
+	findIndex := func(tableID sqlbase.ID, indexID sqlbase.IndexID) (*sqlbase.TableDescriptor, *sqlbase.IndexDescriptor, string) {
+		other, ok := tables[tableID]
+		if !ok {
+			return nil, nil, fmt.Sprintf("missing table %d", tableID)
+		}
+		idx, err := other.FindIndexByID(indexID)
+		if err != nil {
+			return other, nil, fmt.Sprintf("missing index %d of table %q", indexID, other.Name)
+		}
+		return other, idx, ""
+	}
+	// hasRef returns whether refs contains a reference to idx.
+	hasRef := func(refs []sqlbase.ForeignKeyReference, idx sqlbase.IndexDescriptor) bool {
+		for _, ref := range refs {
+			if ref.Table == table.ID && ref.Index == idx.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, idx := range table.AllNonDropIndexes() {
+		if fk := idx.ForeignKey; fk != nil {
+			other, otherIdx, missing := findIndex(fk.Table, fk.Index)
+			if missing != "" {
+				report(fmt.Sprintf("remove the foreign key from index %s", parser.Name(idx.Name).String()),
+					"foreign key %q of index %q refers to %s", fk.Name, idx.Name, missing)
+			} else if !hasRef(derefForeignKeyReferences(otherIdx.ReferencedBy), idx) {
+				report(fmt.Sprintf("add a ReferencedBy entry for table %d index %d to index %s of table %s",
+					table.ID, idx.ID, parser.Name(otherIdx.Name).String(), parser.Name(other.Name).String()),
+					"foreign key %q of index %q is missing from the back-references of %q",
+					fk.Name, idx.Name, other.Name)
+			}
+		}
+		for _, ref := range idx.ReferencedBy {
+			other, otherIdx, missing := findIndex(ref.Table, ref.Index)
+			if missing != "" {
+				report(fmt.Sprintf("remove the ReferencedBy entry from index %s", parser.Name(idx.Name).String()),
+					"back-reference of index %q refers to %s", idx.Name, missing)
+			} else if fk := otherIdx.ForeignKey; fk == nil || fk.Table != table.ID || fk.Index != idx.ID {
+				report(fmt.Sprintf("remove the ReferencedBy entry from index %s", parser.Name(idx.Name).String()),
+					"back-reference of index %q refers to index %q of %q which has no matching foreign key",
+					idx.Name, otherIdx.Name, other.Name)
+			}
+		}
+
+		if n := len(idx.Interleave.Ancestors); n > 0 {
+			for _, ancestor := range idx.Interleave.Ancestors {
+				if _, _, missing := findIndex(ancestor.TableID, ancestor.IndexID); missing != "" {
+					report(fmt.Sprintf("remove the interleave from index %s", parser.Name(idx.Name).String()),
+						"interleave ancestor of index %q refers to %s", idx.Name, missing)
+				}
+			}
+			parent := idx.Interleave.Ancestors[n-1]
+			if other, parentIdx, missing := findIndex(parent.TableID, parent.IndexID); missing == "" &&
+				!hasRef(parentIdx.InterleavedBy, idx) {
+				report(fmt.Sprintf("add an InterleavedBy entry for table %d index %d to index %s of table %s",
+					table.ID, idx.ID, parser.Name(parentIdx.Name).String(), parser.Name(other.Name).String()),
+					"interleave of index %q is missing from the back-references of %q", idx.Name, other.Name)
+			}
+		}
+		for _, ref := range idx.InterleavedBy {
+			other, otherIdx, missing := findIndex(ref.Table, ref.Index)
+			if missing != "" {
+				report(fmt.Sprintf("remove the InterleavedBy entry from index %s", parser.Name(idx.Name).String()),
+					"interleave back-reference of index %q refers to %s", idx.Name, missing)
+				continue
+			}
+			ancestors := otherIdx.Interleave.Ancestors
+			if n := len(ancestors); n == 0 || ancestors[n-1].TableID != table.ID || ancestors[n-1].IndexID != idx.ID {
+				report(fmt.Sprintf("remove the InterleavedBy entry from index %s", parser.Name(idx.Name).String()),
+					"interleave back-reference of index %q refers to index %q of %q which is not interleaved into it",
+					idx.Name, otherIdx.Name, other.Name)
+			}
+		}
+	}
+	return problems
+}
+
+func derefForeignKeyReferences(refs []*sqlbase.ForeignKeyReference) []sqlbase.ForeignKeyReference {
+	res := make([]sqlbase.ForeignKeyReference, len(refs))
+	for i, ref := range refs {
+		res[i] = *ref
+	}
+	return res
+}
@@ -0,0 +1,104 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+func TestDoctor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	url, cleanup := sqlutils.PGUrl(t, s.ServingAddr(), security.RootUser, "TestDoctor")
+	defer cleanup()
+
+	conn := makeSQLConn(url.String())
+	defer conn.Close()
+
+	if err := conn.Exec(`
+		CREATE DATABASE d;
+		CREATE TABLE d.p (id INT PRIMARY KEY);
+		CREATE TABLE d.c (id INT PRIMARY KEY, p INT REFERENCES d.p, INDEX (p));
+		CREATE TABLE d.i (id INT PRIMARY KEY) INTERLEAVE IN PARENT d.p (id);
+	`, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	descs, namespace, err := readDescriptorsFromCluster(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := checkDescriptors(descs, namespace); len(problems) != 0 {
+		var buf bytes.Buffer
+		printDoctorProblems(&buf, problems)
+		t.Fatalf("expected no problems, got:\n%s", buf.String())
+	}
+
+	find := func(name string) *sqlbase.TableDescriptor {
+		for _, desc := range descs {
+			if table := desc.GetTable(); table != nil && table.Name == name {
+				return table
+			}
+		}
+		t.Fatalf("table %s not found", name)
+		return nil
+	}
+
+	// Break the descriptors: remove the back-references of p, add an
+	// orphaned mutation to c and remove the namespace entry of i.
+	p, c, i := find("p"), find("c"), find("i")
+	p.PrimaryIndex.ReferencedBy = nil
+	p.PrimaryIndex.InterleavedBy = nil
+	c.Mutations = append(c.Mutations, sqlbase.DescriptorMutation{
+		Descriptor_: &sqlbase.DescriptorMutation_Index{Index: &sqlbase.IndexDescriptor{Name: "x"}},
+		MutationID:  c.NextMutationID,
+	})
+	for j, e := range namespace {
+		if e.id == i.ID {
+			namespace = append(namespace[:j], namespace[j+1:]...)
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	printDoctorProblems(&buf, checkDescriptors(descs, namespace))
+	out := buf.String()
+	for _, expected := range []string{
+		`table "c": foreign key "fk_p_ref_p_id" of index "c_p_idx" is missing from the back-references of "p"`,
+		`is orphaned: the next mutation ID is`,
+		`descriptor "i" has no namespace entry`,
+		`table "i": interleave of index "primary" is missing from the back-references of "p"`,
+		// The names in the repairs are quoted as SQL identifiers and strings.
+		fmt.Sprintf(`repair: INSERT INTO system.namespace VALUES (%d, 'i', %d)`, i.ParentID, i.ID),
+		`to index "primary" of table p`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected problem %q, got:\n%s", expected, out)
+		}
+	}
+}
@@ -34,17 +34,20 @@ import (
 
 // dumpCmd dumps SQL tables.
 var dumpCmd = &cobra.Command{
-	Use:   "dump [options] <database> <table>",
-	Short: "dump sql tables\n",
+	Use:   "dump [options] <database> [<table> ...]",
+	Short: "dump sql tables",
 	Long: `
-Dump SQL tables of a cockroach database.
+Dump the schema and data of the SQL tables of a cockroach database. If
+no tables are given, all the tables of the database are dumped. Tables
+are dumped after the tables they reference, so that the output can be
+replayed in order.
 `,
 	RunE:         runDump,
 	SilenceUsage: true,
 }
 
 func runDump(cmd *cobra.Command, args []string) error {
-	if len(args) != 2 {
+	if len(args) < 1 {
 		mustUsage(cmd)
 		return errMissingParams
 	}
@@ -55,15 +58,28 @@ func runDump(cmd *cobra.Command, args []string) error {
 	}
 	defer conn.Close()
 
-	return dumpTable(os.Stdout, conn, args[0], args[1])
+	return dumpTables(os.Stdout, conn, args[0], args[1:])
 }
 
+// tableMetadata describes a table to dump.
+type tableMetadata struct {
+	name   string
+	desc   *sqlbase.TableDescriptor
+	create string
+}
+
+// dumpTable dumps the schema and data of a single table.
 func dumpTable(w io.Writer, conn *sqlConn, origDBName, origTableName string) error {
-	const limit = 100
+	return dumpTables(w, conn, origDBName, []string{origTableName})
+}
 
+// dumpTables dumps the schema and then the data of the given tables, or
+// of all the tables of the database if tableNames is empty. Tables are
+// ordered so that the tables referenced by a table's foreign keys or
+// interleaves are dumped first.
+func dumpTables(w io.Writer, conn *sqlConn, origDBName string, tableNames []string) error {
 	// Escape names since they can't be used in placeholders.
 	dbname := parser.Name(origDBName).String()
-	tablename := parser.Name(origTableName).String()
 
 	if err := conn.Exec(fmt.Sprintf("SET DATABASE = %s", dbname), nil); err != nil {
 		return err
@@ -82,30 +98,171 @@ func dumpTable(w io.Writer, conn *sqlConn, origDBName, origTableName string) err
 	clusterTSStart := vals[0].(int64)
 	clusterTS := time.Unix(0, clusterTSStart).Format(time.RFC3339Nano)
 
-	// Fetch table descriptor.
-	vals, err = conn.QueryRow(`
-		SELECT descriptor
+	mds, err := getTableMetadata(conn, origDBName, tableNames)
+	if err != nil {
+		return err
+	}
+	for i := range mds {
+		vals, err = conn.QueryRow(fmt.Sprintf("SHOW CREATE TABLE %s", parser.Name(mds[i].name)), nil)
+		if err != nil {
+			return err
+		}
+		mds[i].create = vals[1].(string)
+	}
+
+	if err := conn.Exec("COMMIT", nil); err != nil {
+		return err
+	}
+
+	mds, err = sortTableMetadata(mds)
+	if err != nil {
+		return err
+	}
+
+	for i, md := range mds {
+		if i > 0 {
+			if _, err := w.Write([]byte("\n")); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write([]byte(md.create)); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte(";\n")); err != nil {
+			return err
+		}
+	}
+	for _, md := range mds {
+		if err := dumpTableData(w, conn, clusterTS, md); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTableMetadata fetches the descriptors of the given tables of the
+// database, or of all its tables if tableNames is empty. It must be run
+// within the dump's transaction.
+func getTableMetadata(conn *sqlConn, origDBName string, tableNames []string) ([]tableMetadata, error) {
+	rows, err := conn.Query(`
+		SELECT tables.name, descriptor
 		FROM system.descriptor
 		JOIN system.namespace tables
 			ON tables.id = descriptor.id
 		JOIN system.namespace dbs
 			ON dbs.id = tables.parentid
-		WHERE tables.name = $1 AND dbs.name = $2`,
-		[]driver.Value{origTableName, origDBName})
-	if err == io.EOF {
-		return errors.Errorf("unknown database or table %s.%s", origTableName, origDBName)
-	} else if err != nil {
-		return err
+		WHERE dbs.name = $1
+		ORDER BY tables.name`,
+		[]driver.Value{origDBName})
+	if err != nil {
+		return nil, err
 	}
-	b := vals[0].([]byte)
-	var desc sqlbase.Descriptor
-	if err := proto.Unmarshal(b, &desc); err != nil {
-		return err
+
+	byName := make(map[string]tableMetadata)
+	var mds []tableMetadata
+	vals := make([]driver.Value, 2)
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		var desc sqlbase.Descriptor
+		if err := proto.Unmarshal(vals[1].([]byte), &desc); err != nil {
+			return nil, err
+		}
+		table := desc.GetTable()
+		if table == nil {
+			return nil, errors.New("internal error: expected table descriptor")
+		}
+		md := tableMetadata{name: vals[0].(string), desc: table}
+		byName[md.name] = md
+		mds = append(mds, md)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if len(tableNames) == 0 {
+		if len(mds) == 0 {
+			return nil, errors.Errorf("unknown database or empty database %s", origDBName)
+		}
+		return mds, nil
+	}
+	mds = mds[:0]
+	for _, name := range tableNames {
+		md, ok := byName[name]
+		if !ok {
+			return nil, errors.Errorf("unknown database or table %s.%s", origDBName, name)
+		}
+		mds = append(mds, md)
+	}
+	return mds, nil
+}
+
+// sortTableMetadata orders tables so that each table comes after the
+// tables it references through foreign keys or interleaves. Tables
+// outside of mds are ignored, and the order of mds is preserved where
+// there are no dependencies.
+func sortTableMetadata(mds []tableMetadata) ([]tableMetadata, error) {
+	byID := make(map[sqlbase.ID]int, len(mds))
+	for i, md := range mds {
+		byID[md.desc.ID] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(mds))
+	sorted := make([]tableMetadata, 0, len(mds))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("cyclic dependency involving table %s", mds[i].name)
+		}
+		state[i] = visiting
+		var deps []sqlbase.ID
+		for _, idx := range mds[i].desc.AllNonDropIndexes() {
+			if idx.ForeignKey != nil {
+				deps = append(deps, idx.ForeignKey.Table)
+			}
+			for _, ancestor := range idx.Interleave.Ancestors {
+				deps = append(deps, ancestor.TableID)
+			}
+		}
+		for _, dep := range deps {
+			if j, ok := byID[dep]; ok && j != i {
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = visited
+		sorted = append(sorted, mds[i])
+		return nil
 	}
-	table := desc.GetTable()
-	if table == nil {
-		return errors.New("internal error: expected table descriptor")
+
+	for i := range mds {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
 	}
+	return sorted, nil
+}
+
+// dumpTableData dumps the data of a table as INSERT statements, reading
+// it at the given cluster timestamp.
+func dumpTableData(w io.Writer, conn *sqlConn, clusterTS string, md tableMetadata) error {
+	const limit = 100
+
+	table := md.desc
+	tablename := parser.Name(md.name).String()
 
 	coltypes := make(map[string]string)
 	for _, c := range table.Columns {
@@ -133,22 +290,6 @@ func dumpTable(w io.Writer, conn *sqlConn, origDBName, origTableName string) err
 	fmt.Fprintf(&sbuf, "%%s ORDER BY %s LIMIT %d", indexes, limit)
 	bs := sbuf.String()
 
-	vals, err = conn.QueryRow(fmt.Sprintf("SHOW CREATE TABLE %s", tablename), nil)
-	if err != nil {
-		return err
-	}
-	create := vals[1].(string)
-	if _, err := w.Write([]byte(create)); err != nil {
-		return err
-	}
-	if _, err := w.Write([]byte(";\n")); err != nil {
-		return err
-	}
-
-	if err := conn.Exec("COMMIT", nil); err != nil {
-		return err
-	}
-
 	// pk holds the last values of the fetched primary keys
 	var pk []driver.Value
 	q := fmt.Sprintf(bs, "")
@@ -124,6 +124,56 @@ INSERT INTO t VALUES
 	}
 }
 
+// TestDumpFKOrder verifies that a database dump lists tables after the
+// tables they reference.
+func TestDumpFKOrder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := newCLITest()
+	defer c.stop()
+
+	const create = `
+	CREATE DATABASE d;
+	CREATE TABLE d.p (id INT PRIMARY KEY);
+	CREATE TABLE d.c (id INT PRIMARY KEY, p INT REFERENCES d.p, INDEX (p));
+	INSERT INTO d.p VALUES (1);
+	INSERT INTO d.c VALUES (2, 1);
+`
+
+	c.RunWithArgs([]string{"sql", "-e", create})
+
+	out, err := c.RunWithCapture("dump d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expect = `dump d
+CREATE TABLE p (
+	id INT NOT NULL,
+	CONSTRAINT "primary" PRIMARY KEY (id),
+	FAMILY "primary" (id)
+);
+
+CREATE TABLE c (
+	id INT NOT NULL,
+	p INT NULL CONSTRAINT fk_p_ref_p_id REFERENCES p (id),
+	CONSTRAINT "primary" PRIMARY KEY (id),
+	INDEX c_p_idx (p),
+	FAMILY "primary" (id, p)
+);
+
+INSERT INTO p VALUES
+	(1);
+
+INSERT INTO c VALUES
+	(2, 1);
+`
+
+	if string(out) != expect {
+		t.Fatalf("expected: %s\ngot: %s", expect, out)
+	}
+}
+
 func TestDumpBytes(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -48,6 +48,8 @@ var serverCtx = server.MakeContext()
 var baseCtx = serverCtx.Context
 var cliCtx = cliContext{Context: baseCtx}
 var sqlCtx = sqlContext{cliContext: &cliCtx}
+var workloadCtx workloadContext
+var demoCtx demoContext
 var debugCtx = debugContext{
 	startKey: engine.NilKey,
 	endKey:   engine.MVCCKeyMax,
@@ -108,9 +110,48 @@ separated statements. If an error occurs in any statement, the command exits
 with a non-zero status code and further statements are not executed. The
 results of each SQL statement are printed on the standard output.`),
 
+	cliflags.WatchName: wrapText(`
+Repeatedly execute the SQL statement(s) given with --execute at the specified
+interval (e.g. 5s), re-rendering the results each time, until interrupted.
+Execution stops at the first error.`),
+
+	cliflags.SafeUpdatesName: wrapText(`
+Disallow SQL statements which are likely to cause accidental data loss, such as
+DELETE or UPDATE without a WHERE clause, in the interactive shell. This sets
+the sql_safe_updates session variable, which can also be changed with SET.`),
+
 	cliflags.PrettyName: wrapText(`
 Causes table rows to be formatted as tables using ASCII art.
-When not specified, table rows are printed as tab-separated values (TSV).`),
+When not specified, table rows are printed as tab-separated values (TSV).
+This is an alias for --format=pretty.`),
+
+	cliflags.FormatName: wrapText(`
+Selects how table rows are printed. Possible values: tsv, csv, pretty,
+records, json. The default is pretty on terminals and tsv otherwise.`),
+
+	cliflags.ConcurrencyName: wrapText(`
+The number of concurrent connections running the workload.`),
+
+	cliflags.DurationName: wrapText(`
+How long to run the workload for. Zero means no limit.`),
+
+	cliflags.MaxOpsName: wrapText(`
+The maximum number of operations to run. Zero means no limit.`),
+
+	cliflags.RowsName: wrapText(`
+The number of initial rows of the workload's main table. Operations choose
+their keys among these rows.`),
+
+	cliflags.ReadPercentName: wrapText(`
+The percentage of operations of the kv workload which are reads; the others
+are writes.`),
+
+	cliflags.NodesName: wrapText(`
+The number of in-memory nodes of the demo cluster.`),
+
+	cliflags.DatasetName: wrapText(`
+The name of a built-in workload whose tables are loaded into the demo cluster
+before the SQL shell opens. No data is loaded by default.`),
 
 	cliflags.JoinName: wrapText(`
 The address of node which acts as bootstrap when a new node is
@@ -125,6 +166,12 @@ or both forms can be used together, for example:`) + `
 
   --join=localhost:1234,localhost:2345 --join=localhost:3456
 
+` + wrapText(`
+An uninitialized node started with --join waits until it has joined an
+existing cluster. To create a new cluster, start its nodes with --join and
+run "cockroach init" against one of them. A node started without --join
+creates a new cluster by itself.`) + `
+
 ` + wrapText(`
 Each address in the list has an optional type: [type=]<address>.
 An unspecified type means ip address or dns. Type is one of:`) + `
@@ -449,13 +496,22 @@ func init() {
 	setUserCmd.Flags().StringVar(&password, cliflags.PasswordName, envutil.EnvOrDefaultString(cliflags.PasswordName, ""), usageEnv(cliflags.PasswordName))
 
 	clientCmds := []*cobra.Command{
-		sqlShellCmd, quitCmd, freezeClusterCmd, dumpCmd, /* startCmd is covered above */
+		sqlShellCmd, initCmd, quitCmd, freezeClusterCmd, dumpCmd, /* startCmd is covered above */
 	}
 	clientCmds = append(clientCmds, kvCmds...)
 	clientCmds = append(clientCmds, rangeCmds...)
 	clientCmds = append(clientCmds, userCmds...)
 	clientCmds = append(clientCmds, zoneCmds...)
 	clientCmds = append(clientCmds, nodeCmds...)
+	clientCmds = append(clientCmds, workloadCmds...)
+	clientCmds = append(clientCmds, userfileCmds...)
+	clientCmds = append(clientCmds, debugDoctorCmd)
+	// By default, client commands print their output as
+	// pretty-formatted tables on terminals, and TSV when redirected
+	// to a file. The user can override with --format or --pretty.
+	if isInteractive {
+		cliCtx.tableDisplayFormat = tableDisplayPretty
+	}
 	for _, cmd := range clientCmds {
 		f := cmd.PersistentFlags()
 		f.StringVar(&connHost, cliflags.HostName, envutil.EnvOrDefaultString(cliflags.HostName, ""), usageEnv(forClient(cliflags.HostName)))
@@ -468,15 +524,16 @@ func init() {
 		f.StringVar(&baseCtx.SSLCert, cliflags.CertName, envutil.EnvOrDefaultString(cliflags.CertName, baseCtx.SSLCert), usageEnv(cliflags.CertName))
 		f.StringVar(&baseCtx.SSLCertKey, cliflags.KeyName, envutil.EnvOrDefaultString(cliflags.KeyName, baseCtx.SSLCertKey), usageEnv(cliflags.KeyName))
 
-		// By default, client commands print their output as
-		// pretty-formatted tables on terminals, and TSV when redirected
-		// to a file. The user can override with --pretty.
-		f.BoolVar(&cliCtx.prettyFmt, cliflags.PrettyName, isInteractive, usageNoEnv(cliflags.PrettyName))
+		f.Var(&cliCtx.tableDisplayFormat, cliflags.FormatName, usageNoEnv(cliflags.FormatName))
+		prettyF := f.VarPF(prettyValue{format: &cliCtx.tableDisplayFormat}, cliflags.PrettyName, "", usageNoEnv(cliflags.PrettyName))
+		prettyF.NoOptDefVal = "true"
 	}
 
 	{
 		f := sqlShellCmd.Flags()
 		f.VarP(&sqlCtx.execStmts, cliflags.ExecuteName, "e", usageNoEnv(cliflags.ExecuteName))
+		f.DurationVar(&sqlCtx.watchInterval, cliflags.WatchName, 0, usageNoEnv(cliflags.WatchName))
+		f.BoolVar(&sqlCtx.safeUpdates, cliflags.SafeUpdatesName, true, usageNoEnv(cliflags.SafeUpdatesName))
 	}
 	{
 		f := freezeClusterCmd.PersistentFlags()
@@ -484,7 +541,7 @@ func init() {
 	}
 
 	// Commands that need the cockroach port.
-	simpleCmds := []*cobra.Command{quitCmd, freezeClusterCmd}
+	simpleCmds := []*cobra.Command{initCmd, quitCmd, freezeClusterCmd}
 	simpleCmds = append(simpleCmds, kvCmds...)
 	simpleCmds = append(simpleCmds, rangeCmds...)
 	simpleCmds = append(simpleCmds, nodeCmds...)
@@ -497,6 +554,9 @@ func init() {
 	sqlCmds := []*cobra.Command{sqlShellCmd, dumpCmd}
 	sqlCmds = append(sqlCmds, zoneCmds...)
 	sqlCmds = append(sqlCmds, userCmds...)
+	sqlCmds = append(sqlCmds, workloadCmds...)
+	sqlCmds = append(sqlCmds, userfileCmds...)
+	sqlCmds = append(sqlCmds, debugDoctorCmd)
 	for _, cmd := range sqlCmds {
 		f := cmd.PersistentFlags()
 		f.StringVar(&connURL, cliflags.URLName, envutil.EnvOrDefaultString(cliflags.URLName, ""), usageEnv(cliflags.URLName))
@@ -521,6 +581,29 @@ func init() {
 		f.BoolVar(&debugCtx.sizes, cliflags.SizesName, false, usageNoEnv(cliflags.SizesName))
 	}
 
+	// Demo command.
+	{
+		f := demoCmd.Flags()
+		f.IntVar(&demoCtx.nodes, cliflags.NodesName, 1, usageNoEnv(cliflags.NodesName))
+		f.StringVar(&demoCtx.dataset, cliflags.DatasetName, "", usageNoEnv(cliflags.DatasetName))
+		f.IntVar(&workloadCtx.rows, cliflags.RowsName, 1000, usageNoEnv(cliflags.RowsName))
+		f.VarP(&sqlCtx.execStmts, cliflags.ExecuteName, "e", usageNoEnv(cliflags.ExecuteName))
+		f.Var(&cliCtx.tableDisplayFormat, cliflags.FormatName, usageNoEnv(cliflags.FormatName))
+	}
+
+	// Workload commands.
+	{
+		f := workloadCmd.PersistentFlags()
+		f.IntVar(&workloadCtx.rows, cliflags.RowsName, 1000, usageNoEnv(cliflags.RowsName))
+	}
+	{
+		f := workloadRunCmd.Flags()
+		f.IntVar(&workloadCtx.concurrency, cliflags.ConcurrencyName, 4, usageNoEnv(cliflags.ConcurrencyName))
+		f.DurationVar(&workloadCtx.duration, cliflags.DurationName, 0, usageNoEnv(cliflags.DurationName))
+		f.Int64Var(&workloadCtx.maxOps, cliflags.MaxOpsName, 0, usageNoEnv(cliflags.MaxOpsName))
+		f.IntVar(&workloadCtx.readPercent, cliflags.ReadPercentName, 95, usageNoEnv(cliflags.ReadPercentName))
+	}
+
 	{
 		f := versionCmd.Flags()
 		f.BoolVar(&versionIncludesDeps, cliflags.DepsName, false, usageNoEnv(cliflags.DepsName))
@@ -0,0 +1,56 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cockroachdb/cockroach/server/serverpb"
+)
+
+// initCmd command bootstraps a new cluster.
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "initialize a cluster",
+	Long: `
+Create a new cluster using the node at --host and --port, which must have
+been started with --join and not yet be part of a cluster. The other nodes
+started with --join then join the new cluster. It is an error to initialize
+a cluster twice.
+`,
+	SilenceUsage: true,
+	RunE:         runInit,
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		mustUsage(cmd)
+		return errMissingParams
+	}
+
+	c, stopper, err := getAdminClient()
+	if err != nil {
+		return err
+	}
+	defer stopper.Stop()
+
+	if _, err := c.Bootstrap(stopperContext(stopper), &serverpb.BootstrapRequest{}); err != nil {
+		return err
+	}
+	fmt.Println("cluster successfully initialized")
+	return nil
+}
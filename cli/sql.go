@@ -23,14 +23,20 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/chzyer/readline"
+	"github.com/cockroachdb/cockroach/cli/cliflags"
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/util/envutil"
 	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/timeutil"
 	"github.com/spf13/cobra"
 )
 
@@ -64,6 +70,11 @@ func printCliHelp() {
 Type: \q to exit (Ctrl+C/Ctrl+D also supported)
       \! to run an external command and print its results on standard output.
       \| to run an external command and run its output as SQL statements.
+      \l to list all databases.
+      \dt to list the tables in the current database.
+      \d [table] to list the tables, or the columns of a table.
+      \du to list the users.
+      \set display_format <format> to print results as tsv, csv, pretty, records or json.
       \? or "help" to print this help.
 
 More documentation about our SQL dialect is available online:
@@ -102,35 +113,50 @@ func handleInputLine(ins *readline.Instance, stmt *[]string, line string, syntax
 			printCliHelp()
 			return cliNextLine, false
 		}
+	}
 
-		if len(line) > 0 && line[0] == '\\' {
-			// Client-side commands: process locally.
-
-			addHistory(ins, line)
-
-			cmd := strings.Fields(line)
-			switch cmd[0] {
-			case `\q`:
-				return cliExit, false
-			case `\!`:
-				return runSyscmd(line), false
-			case `\|`:
-				status = pipeSyscmd(stmt, line)
-				_, hasSet = isEndOfStatement(syntax, stmt)
-				return status, hasSet
-			case `\`, `\?`:
-				printCliHelp()
-			default:
-				fmt.Fprintf(osStderr, "Invalid command: %s. Try \\? for help.\n", line)
-			}
+	if len(line) > 0 && line[0] == '\\' {
+		// Client-side commands: process locally. A command is not part of
+		// the statement being entered, if any, which is discarded.
+		if len(*stmt) > 0 {
+			fmt.Fprintln(osStderr, "incomplete statement discarded")
+			*stmt = (*stmt)[:0]
+		}
 
-			if strings.HasPrefix(line, `\d`) {
-				// Unrecognized command for now, but we want to be helpful.
-				fmt.Fprint(osStderr, "Suggestion: use the SQL SHOW statement to inspect your schema.\n")
+		addHistory(ins, line)
+
+		cmd := strings.Fields(line)
+		switch cmd[0] {
+		case `\q`:
+			return cliExit, false
+		case `\!`:
+			return runSyscmd(line), false
+		case `\|`:
+			status = pipeSyscmd(stmt, line)
+			_, hasSet = isEndOfStatement(syntax, stmt)
+			return status, hasSet
+		case `\l`, `\dt`, `\d`, `\du`:
+			query, ok := metaCommandQuery(cmd)
+			if !ok {
+				fmt.Fprintf(osStderr, "Usage:\n  %s\n", metaCommandUsage[cmd[0]])
+				return cliNextLine, false
 			}
+			*stmt = append(*stmt, query)
+			return cliProcessQuery, false
+		case `\set`:
+			handleSet(cmd[1:])
+		case `\`, `\?`:
+			printCliHelp()
+		default:
+			fmt.Fprintf(osStderr, "Invalid command: %s. Try \\? for help.\n", line)
+		}
 
-			return cliNextLine, false
+		if strings.HasPrefix(line, `\d`) {
+			// Unrecognized command for now, but we want to be helpful.
+			fmt.Fprint(osStderr, "Suggestion: use the SQL SHOW statement to inspect your schema.\n")
 		}
+
+		return cliNextLine, false
 	}
 
 	*stmt = append(*stmt, line)
@@ -143,6 +169,147 @@ func handleInputLine(ins *readline.Instance, stmt *[]string, line string, syntax
 	return status, hasSet
 }
 
+// handleSet processes the client-side \set command, which changes
+// the options of the interactive shell.
+func handleSet(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintf(osStderr, "Usage:\n  \\set display_format <format>\n")
+		return
+	}
+	switch args[0] {
+	case "display_format":
+		if err := cliCtx.tableDisplayFormat.Set(args[1]); err != nil {
+			fmt.Fprintln(osStderr, err)
+		}
+	default:
+		fmt.Fprintf(osStderr, "unknown option: %s\n", args[0])
+	}
+}
+
+var metaCommandUsage = map[string]string{
+	`\l`:  `\l`,
+	`\dt`: `\dt`,
+	`\d`:  `\d [table]`,
+	`\du`: `\du`,
+}
+
+// metaCommandQuery returns the SQL query that a psql-style schema
+// inspection command expands to, or false if the command was not
+// invoked with the right number of arguments.
+func metaCommandQuery(cmd []string) (string, bool) {
+	switch {
+	case cmd[0] == `\l` && len(cmd) == 1:
+		return "SHOW DATABASES;", true
+	case cmd[0] == `\dt` && len(cmd) == 1, cmd[0] == `\d` && len(cmd) == 1:
+		return "SHOW TABLES;", true
+	case cmd[0] == `\d` && len(cmd) == 2:
+		return fmt.Sprintf("SHOW COLUMNS FROM %s;", cmd[1]), true
+	case cmd[0] == `\du` && len(cmd) == 1:
+		return "SELECT username FROM system.users;", true
+	}
+	return "", false
+}
+
+// sqlCompleter implements readline.AutoCompleter. It completes the
+// word under the cursor with the names of the tables in the current
+// database, and the names of the columns of any of those tables
+// mentioned elsewhere on the line. The names are quoted where needed.
+//
+// The completer queries the server on its own connection, so that
+// completion does not run statements in the middle of the shell's
+// transactions.
+type sqlCompleter struct {
+	conn *sqlConn
+	// database is the current database of the shell, if it was changed
+	// from the one of the connection URL.
+	database string
+}
+
+// isIdentChar returns whether r can be part of an unquoted identifier.
+func isIdentChar(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Do implements the readline.AutoCompleter interface.
+func (c *sqlCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	start := pos
+	for start > 0 && (isIdentChar(line[start-1]) || line[start-1] == '"') {
+		start--
+	}
+	prefix := strings.ToLower(string(line[start:pos]))
+	if prefix == "" {
+		return nil, 0
+	}
+
+	var candidates []string
+	for _, name := range c.completions(string(line)) {
+		if strings.HasPrefix(strings.ToLower(name), prefix) && len(name) > len(prefix) {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+
+	newLine := make([][]rune, len(candidates))
+	for i, name := range candidates {
+		newLine[i] = []rune(name[len(prefix):])
+	}
+	return newLine, len(prefix)
+}
+
+// completions returns the table names in the current database, plus
+// the column names of those tables which appear in line. Errors are
+// not reported: completion simply offers fewer candidates.
+func (c *sqlCompleter) completions(line string) []string {
+	query := "SHOW TABLES"
+	if c.database != "" {
+		query = fmt.Sprintf("SHOW TABLES FROM %s", parser.Name(c.database))
+	}
+	_, rows, _, err := runQuery(c.conn, makeQuery(query), false)
+	if err != nil {
+		if log.V(2) {
+			log.Warningf("cannot retrieve table names for completion: %s", err)
+		}
+		return nil
+	}
+	words := make(map[string]struct{})
+	for _, w := range strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+		return !isIdentChar(r)
+	}) {
+		words[w] = struct{}{}
+	}
+	// mentioned returns whether the table name appears in line.
+	mentioned := func(table string) bool {
+		if strings.HasPrefix(table, `"`) {
+			return strings.Contains(line, table)
+		}
+		_, ok := words[strings.ToLower(table)]
+		return ok
+	}
+
+	var names []string
+	for _, row := range rows {
+		table := parser.Name(row[0]).String()
+		names = append(names, table)
+		if !mentioned(table) {
+			continue
+		}
+		if c.database != "" {
+			table = fmt.Sprintf("%s.%s", parser.Name(c.database), table)
+		}
+		_, cols, _, err := runQuery(c.conn, makeQuery(fmt.Sprintf("SHOW COLUMNS FROM %s", table)), false)
+		if err != nil {
+			if log.V(2) {
+				log.Warningf("cannot retrieve column names for completion: %s", err)
+			}
+			continue
+		}
+		for _, col := range cols {
+			names = append(names, parser.Name(col[0]).String())
+		}
+	}
+	return names
+}
+
 func isEndOfStatement(syntax parser.Syntax, stmt *[]string) (isEnd, hasSet bool) {
 	fullStmt := strings.Join(*stmt, "\n")
 	sc := parser.MakeScanner(fullStmt, syntax)
@@ -244,6 +411,8 @@ func runInteractive(conn *sqlConn, config *readline.Config) (exitErr error) {
 		return err
 	}
 
+	var completer *sqlCompleter
+
 	if isInteractive {
 		// We only enable history management when the terminal is actually
 		// interactive. This saves on memory when e.g. piping a large SQL
@@ -260,10 +429,22 @@ func runInteractive(conn *sqlConn, config *readline.Config) (exitErr error) {
 			cfg.HistoryFile = histFile
 			ins.SetConfig(cfg)
 		}
+
+		completer = &sqlCompleter{conn: makeSQLConn(conn.url)}
+		defer completer.conn.Close()
+		cfg := ins.Config.Clone()
+		cfg.AutoComplete = completer
+		ins.SetConfig(cfg)
 	}
 
 	if isInteractive {
 		fmt.Print(infoMessage)
+
+		if sqlCtx.safeUpdates {
+			if err := conn.Exec("SET sql_safe_updates = TRUE", nil); err != nil {
+				return err
+			}
+		}
 	}
 
 	var stmt []string
@@ -326,7 +507,7 @@ func runInteractive(conn *sqlConn, config *readline.Config) (exitErr error) {
 			addHistory(ins, fullStmt)
 		}
 
-		if exitErr = runQueryAndFormatResults(conn, os.Stdout, makeQuery(fullStmt), cliCtx.prettyFmt); exitErr != nil {
+		if exitErr = runQueryAndFormatResults(conn, os.Stdout, makeQuery(fullStmt), cliCtx.tableDisplayFormat); exitErr != nil {
 			fmt.Fprintln(osStderr, exitErr)
 		}
 
@@ -336,10 +517,18 @@ func runInteractive(conn *sqlConn, config *readline.Config) (exitErr error) {
 		if hasSet {
 			newSyntax, err := getSyntax(conn)
 			if err != nil {
-				fmt.Fprintf(osStderr, "could not get session syntax: %s", err)
+				fmt.Fprintf(osStderr, "could not get session syntax: %s\n", err)
 			} else {
 				syntax = newSyntax
 			}
+			if completer != nil {
+				database, err := getDatabase(conn)
+				if err != nil {
+					fmt.Fprintf(osStderr, "could not get session database: %s\n", err)
+				} else {
+					completer.database = database
+				}
+			}
 		}
 	}
 
@@ -360,32 +549,100 @@ func getSyntax(conn *sqlConn) (parser.Syntax, error) {
 	return 0, fmt.Errorf("unknown syntax: %s", rows[0][0])
 }
 
+func getDatabase(conn *sqlConn) (string, error) {
+	_, rows, _, err := runQuery(conn, makeQuery("SHOW DATABASE"), false)
+	if err != nil {
+		return "", err
+	}
+	return rows[0][0], nil
+}
+
 // runOneStatement executes one statement and terminates
 // on error.
-func runStatements(conn *sqlConn, stmts []string, pretty bool) error {
+func runStatements(conn *sqlConn, stmts []string, displayFormat tableDisplayFormat) error {
 	for _, stmt := range stmts {
-		if err := runQueryAndFormatResults(conn, os.Stdout, makeQuery(stmt), pretty); err != nil {
+		if err := runQueryAndFormatResults(conn, os.Stdout, makeQuery(stmt), displayFormat); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// watchStatements repeatedly executes the given statements every interval,
+// writing the results to w preceded by a header naming the statements and the
+// time they were run. If clearScreen is set, the terminal is cleared before
+// each execution so that the results are re-rendered in place. It returns on
+// the first error, when a signal is received on interrupt, or after
+// maxIterations executions if maxIterations is positive.
+func watchStatements(
+	conn *sqlConn,
+	w io.Writer,
+	stmts []string,
+	displayFormat tableDisplayFormat,
+	interval time.Duration,
+	clearScreen bool,
+	maxIterations int,
+	interrupt <-chan os.Signal,
+) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for i := 0; maxIterations <= 0 || i < maxIterations; i++ {
+		if i > 0 {
+			select {
+			case <-ticker.C:
+			case <-interrupt:
+				return nil
+			}
+		}
+		if clearScreen {
+			// Move the cursor home and erase the display.
+			fmt.Fprint(w, "\x1b[H\x1b[2J")
+		} else if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Every %s: %s\t%s\n\n",
+			interval, strings.Join(stmts, "; "), timeutil.Now().Format(time.RFC1123))
+		for _, stmt := range stmts {
+			if err := runQueryAndFormatResults(conn, w, makeQuery(stmt), displayFormat); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func runTerm(cmd *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		mustUsage(cmd)
 		return errMissingParams
 	}
 
+	if sqlCtx.watchInterval != 0 {
+		if len(sqlCtx.execStmts) == 0 {
+			return fmt.Errorf("--%s requires --%s", cliflags.WatchName, cliflags.ExecuteName)
+		}
+		if sqlCtx.watchInterval < 0 {
+			return fmt.Errorf("invalid --%s interval: %s", cliflags.WatchName, sqlCtx.watchInterval)
+		}
+	}
+
 	conn, err := makeSQLClient()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
+	if sqlCtx.watchInterval != 0 {
+		// Stop cleanly between two executions on Ctrl-C.
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		defer signal.Stop(interrupt)
+		return watchStatements(conn, os.Stdout, sqlCtx.execStmts,
+			cliCtx.tableDisplayFormat, sqlCtx.watchInterval, isInteractive, 0, interrupt)
+	}
 	if len(sqlCtx.execStmts) > 0 {
 		// Single-line sql; run as simple as possible, without noise on stdout.
-		return runStatements(conn, sqlCtx.execStmts, cliCtx.prettyFmt)
+		return runStatements(conn, sqlCtx.execStmts, cliCtx.tableDisplayFormat)
 	}
 	// Use the same as the default global readline config.
 	conf := readline.Config{
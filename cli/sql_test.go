@@ -17,13 +17,18 @@
 package cli
 
 import (
+	"bytes"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/chzyer/readline"
 	"github.com/cockroachdb/cockroach/base"
 	"github.com/cockroachdb/cockroach/server"
 	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/testutils"
 	"github.com/cockroachdb/cockroach/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 )
@@ -98,7 +103,7 @@ SET
 	}
 
 	// Some other tests (TestDumpRow) mess with this, so make sure it's set.
-	cliCtx.prettyFmt = true
+	cliCtx.tableDisplayFormat = tableDisplayPretty
 
 	for _, test := range tests {
 		conf.Stdin = strings.NewReader(test.in)
@@ -170,3 +175,142 @@ func TestIsEndOfStatement(t *testing.T) {
 		}
 	}
 }
+
+func TestMetaCommandQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{`\l`, "SHOW DATABASES;"},
+		{`\dt`, "SHOW TABLES;"},
+		{`\d`, "SHOW TABLES;"},
+		{`\d foo`, "SHOW COLUMNS FROM foo;"},
+		{`\du`, "SELECT username FROM system.users;"},
+		{`\l foo`, ""},
+		{`\d foo bar`, ""},
+	}
+
+	for _, test := range tests {
+		query, ok := metaCommandQuery(strings.Fields(test.in))
+		if ok != (test.expect != "") || query != test.expect {
+			t.Errorf("%s: expected %q, got %q (ok %t)", test.in, test.expect, query, ok)
+		}
+	}
+}
+
+// TestSQLCompleter tests the completion of table and column names in the
+// interactive shell.
+func TestSQLCompleter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{Insecure: true})
+	defer s.Stopper().Stop()
+
+	pgurl, err := s.(*server.TestServer).Ctx.PGURL("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := makeSQLConn(pgurl.String())
+	defer conn.Close()
+
+	for _, stmt := range []string{
+		`CREATE DATABASE t`,
+		`SET DATABASE = t`,
+		`CREATE TABLE t.kv (key INT PRIMARY KEY, value INT)`,
+		`CREATE TABLE t.kw (k INT)`,
+		`CREATE TABLE t."my table" ("Value" INT)`,
+		`BEGIN`,
+	} {
+		if err := conn.Exec(stmt, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The completer uses its own connection, so it works while the
+	// transaction of the shell is aborted.
+	if err := conn.Exec(`SELECT * FROM t.nonexistent`, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	c := &sqlCompleter{conn: makeSQLConn(pgurl.String()), database: "t"}
+	defer c.conn.Close()
+	tests := []struct {
+		line   string
+		expect []string
+		length int
+	}{
+		{"SELECT * FROM k", []string{"v", "w"}, 1},
+		{"SELECT * FROM kv", nil, 2},
+		{"SELECT va| FROM kv", []string{"lue"}, 2},
+		{"SELECT va| FROM kw", nil, 2},
+		{"SELECT ", nil, 0},
+		{`SELECT * FROM "my`, []string{` table"`}, 3},
+		{`SELECT "V| FROM "my table"`, []string{`alue"`}, 2},
+	}
+
+	for _, test := range tests {
+		// Complete the word before the "|" marking the cursor if there is
+		// one, or else the last word on the line.
+		line := []rune(strings.Replace(test.line, "|", "", 1))
+		pos := len(line)
+		if i := strings.Index(test.line, "|"); i >= 0 {
+			pos = i
+		}
+		newLine, length := c.Do(line, pos)
+		var got []string
+		for _, r := range newLine {
+			got = append(got, string(r))
+		}
+		if !reflect.DeepEqual(got, test.expect) || length != test.length {
+			t.Errorf("%q: expected %v (%d), got %v (%d)", test.line, test.expect, test.length, got, length)
+		}
+	}
+}
+
+// TestWatchStatements tests that watchStatements re-executes its statements
+// and stops at the first error.
+func TestWatchStatements(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{Insecure: true})
+	defer s.Stopper().Stop()
+
+	pgurl, err := s.(*server.TestServer).Ctx.PGURL("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := makeSQLConn(pgurl.String())
+	defer conn.Close()
+
+	var buf bytes.Buffer
+	if err := watchStatements(conn, &buf, []string{"SELECT 1"},
+		tableDisplayTSV, time.Millisecond, false, 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if n := strings.Count(out, "Every 1ms: SELECT 1\t"); n != 3 {
+		t.Errorf("expected 3 headers, got %d:\n%s", n, out)
+	}
+	if n := strings.Count(out, "1 row\n1\n1\n"); n != 3 {
+		t.Errorf("expected 3 results, got %d:\n%s", n, out)
+	}
+
+	buf.Reset()
+	if err := watchStatements(conn, &buf, []string{"SELECT * FROM nonexistent"},
+		tableDisplayTSV, time.Millisecond, false, 0, nil); !testutils.IsError(err, "does not exist") {
+		t.Errorf("expected table not found error, got %v", err)
+	}
+
+	// An interrupt stops the executions without an error.
+	buf.Reset()
+	interrupt := make(chan os.Signal, 1)
+	interrupt <- os.Interrupt
+	if err := watchStatements(conn, &buf, []string{"SELECT 1"},
+		tableDisplayTSV, time.Hour, false, 0, interrupt); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "Every 1h0m0s: SELECT 1\t"); n != 1 {
+		t.Errorf("expected 1 header, got %d:\n%s", n, buf.String())
+	}
+}
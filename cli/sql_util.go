@@ -19,6 +19,8 @@ package cli
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -218,10 +220,14 @@ func runQuery(conn *sqlConn, fn queryFunc, pretty bool) ([]string, [][]string, s
 }
 
 // runQueryAndFormatResults takes a 'query' with optional 'parameters'.
-// It runs the sql query and writes output to 'w'.
+// It runs the sql query and writes output to 'w' using the given
+// display format.
 func runQueryAndFormatResults(
-	conn *sqlConn, w io.Writer, fn queryFunc, pretty bool,
+	conn *sqlConn, w io.Writer, fn queryFunc, displayFormat tableDisplayFormat,
 ) error {
+	// Only TSV output escapes non-printable characters and newlines
+	// itself; the other formats either show them or quote them.
+	pretty := displayFormat != tableDisplayTSV
 	for {
 		cols, allRows, result, err := runQuery(conn, fn, pretty)
 		if err != nil {
@@ -230,7 +236,7 @@ func runQueryAndFormatResults(
 			}
 			return err
 		}
-		printQueryOutput(w, cols, allRows, result, pretty)
+		printQueryOutput(w, cols, allRows, result, displayFormat)
 		fn = nextResult
 	}
 }
@@ -300,9 +306,10 @@ func expandTabsAndNewLines(s string) string {
 }
 
 // printQueryOutput takes a list of column names and a list of row contents
-// writes a pretty table to 'w', or "OK" if empty.
+// writes a table to 'w' in the given display format, or the statement tag
+// if the statement did not return rows.
 func printQueryOutput(
-	w io.Writer, cols []string, allRows [][]string, tag string, pretty bool,
+	w io.Writer, cols []string, allRows [][]string, tag string, displayFormat tableDisplayFormat,
 ) {
 	if len(cols) == 0 {
 		// This operation did not return rows, just show the tag.
@@ -310,7 +317,8 @@ func printQueryOutput(
 		return
 	}
 
-	if pretty {
+	switch displayFormat {
+	case tableDisplayPretty:
 		// Initialize tablewriter and set column names as the header row.
 		table := tablewriter.NewWriter(w)
 		table.SetAutoFormatHeaders(false)
@@ -325,20 +333,66 @@ func printQueryOutput(
 		table.Render()
 		nRows := len(allRows)
 		fmt.Fprintf(w, "(%d row%s)\n", nRows, util.Pluralize(int64(nRows)))
-	} else {
-		if len(cols) == 0 {
-			// No result selected, inform the user.
-			fmt.Fprintln(w, tag)
-		} else {
-			// Some results selected, inform the user about how much data to expect.
-			fmt.Fprintf(w, "%d row%s\n", len(allRows),
-				util.Pluralize(int64(len(allRows))))
-
-			// Then print the results themselves.
-			fmt.Fprintln(w, strings.Join(cols, "\t"))
-			for _, row := range allRows {
-				fmt.Fprintln(w, strings.Join(row, "\t"))
+
+	case tableDisplayCSV:
+		csvWriter := csv.NewWriter(w)
+		_ = csvWriter.Write(cols)
+		_ = csvWriter.WriteAll(allRows)
+
+	case tableDisplayRecords:
+		maxColWidth := 0
+		for _, col := range cols {
+			if n := utf8.RuneCountInString(col); n > maxColWidth {
+				maxColWidth = n
+			}
+		}
+		for i, row := range allRows {
+			fmt.Fprintf(w, "-[ RECORD %d ]\n", i+1)
+			for j, r := range row {
+				padding := strings.Repeat(" ", maxColWidth-utf8.RuneCountInString(cols[j]))
+				lines := strings.Split(r, "\n")
+				fmt.Fprintf(w, "%s%s | %s\n", cols[j], padding, lines[0])
+				// Align the continuation lines of multi-line values.
+				for _, l := range lines[1:] {
+					fmt.Fprintf(w, "%s | %s\n", strings.Repeat(" ", maxColWidth), l)
+				}
+			}
+		}
+		nRows := len(allRows)
+		fmt.Fprintf(w, "(%d row%s)\n", nRows, util.Pluralize(int64(nRows)))
+
+	case tableDisplayJSON:
+		// Columns are printed in the order of the result set, which
+		// encoding/json does not preserve when marshaling maps.
+		encode := func(s string) string {
+			b, _ := json.Marshal(s)
+			return string(b)
+		}
+		fmt.Fprint(w, "[")
+		for i, row := range allRows {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprint(w, "\n  {")
+			for j, r := range row {
+				if j > 0 {
+					fmt.Fprint(w, ", ")
+				}
+				fmt.Fprintf(w, "%s: %s", encode(cols[j]), encode(r))
 			}
+			fmt.Fprint(w, "}")
+		}
+		fmt.Fprintln(w, "\n]")
+
+	default:
+		// Some results selected, inform the user about how much data to expect.
+		fmt.Fprintf(w, "%d row%s\n", len(allRows),
+			util.Pluralize(int64(len(allRows))))
+
+		// Then print the results themselves.
+		fmt.Fprintln(w, strings.Join(cols, "\t"))
+		for _, row := range allRows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
 		}
 	}
 }
@@ -43,7 +43,7 @@ func TestRunQuery(t *testing.T) {
 	var b bytes.Buffer
 
 	// Non-query statement.
-	if err := runQueryAndFormatResults(conn, &b, makeQuery(`SET DATABASE=system`), true); err != nil {
+	if err := runQueryAndFormatResults(conn, &b, makeQuery(`SET DATABASE=system`), tableDisplayPretty); err != nil {
 		t.Fatal(err)
 	}
 
@@ -76,7 +76,7 @@ SET
 	}
 
 	if err := runQueryAndFormatResults(conn, &b,
-		makeQuery(`SHOW COLUMNS FROM system.namespace`), true); err != nil {
+		makeQuery(`SHOW COLUMNS FROM system.namespace`), tableDisplayPretty); err != nil {
 		t.Fatal(err)
 	}
 
@@ -98,7 +98,7 @@ SET
 
 	// Test placeholders.
 	if err := runQueryAndFormatResults(conn, &b,
-		makeQuery(`SELECT * FROM system.namespace WHERE name=$1`, "descriptor"), true); err != nil {
+		makeQuery(`SELECT * FROM system.namespace WHERE name=$1`, "descriptor"), tableDisplayPretty); err != nil {
 		t.Fatal(err)
 	}
 
@@ -117,7 +117,7 @@ SET
 
 	// Test multiple results.
 	if err := runQueryAndFormatResults(conn, &b,
-		makeQuery(`SELECT 1; SELECT 2, 3; SELECT 'hello'`), true); err != nil {
+		makeQuery(`SELECT 1; SELECT 2, 3; SELECT 'hello'`), tableDisplayPretty); err != nil {
 		t.Fatal(err)
 	}
 
@@ -147,3 +147,53 @@ SET
 	}
 	b.Reset()
 }
+
+func TestPrintQueryOutputFormats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	cols := []string{"k", "value"}
+	rows := [][]string{{"1", "a,b"}, {"2", "c\nd"}}
+
+	tests := []struct {
+		format   tableDisplayFormat
+		expected string
+	}{
+		{tableDisplayTSV, `
+2 rows
+k	value
+1	a,b
+2	c
+d
+`},
+		{tableDisplayCSV, `
+k,value
+1,"a,b"
+2,"c
+d"
+`},
+		{tableDisplayRecords, `
+-[ RECORD 1 ]
+k     | 1
+value | a,b
+-[ RECORD 2 ]
+k     | 2
+value | c
+      | d
+(2 rows)
+`},
+		{tableDisplayJSON, `
+[
+  {"k": "1", "value": "a,b"},
+  {"k": "2", "value": "c\nd"}
+]
+`},
+	}
+
+	for _, test := range tests {
+		var b bytes.Buffer
+		printQueryOutput(&b, cols, rows, "", test.format)
+		if a, e := b.String(), test.expected[1:]; a != e {
+			t.Errorf("%s: expected output:\n%s\ngot:\n%s", test.format.String(), e, a)
+		}
+	}
+}
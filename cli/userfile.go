@@ -0,0 +1,385 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/cockroachdb/cockroach/sql"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/util/uuid"
+)
+
+// userfileChunkSize is the size of the chunks in which the contents of a
+// file are stored. Each chunk is a row of the payload table.
+var userfileChunkSize = 1 << 20
+
+// createUserfileTables creates the database holding the uploaded files and
+// the tables of the given user in it, unless they exist. Only the users
+// allowed to create databases can create it, so the database and the tables
+// are looked up first: the other users can use the tables of a database
+// created for them, given the CREATE privilege on the database.
+func createUserfileTables(conn *sqlConn, user string) error {
+	dbs, err := queryStrings(conn, `SHOW DATABASES`)
+	if err != nil {
+		return err
+	}
+	if _, ok := dbs[sql.UserfileDatabase]; !ok {
+		if err := conn.Exec(fmt.Sprintf(`CREATE DATABASE IF NOT EXISTS %s`,
+			parser.Name(sql.UserfileDatabase)), nil); err != nil {
+			return errors.Wrapf(err, "database %s must be created by a user allowed to create databases, "+
+				"and the CREATE privilege on it granted to %s", sql.UserfileDatabase, user)
+		}
+	}
+	tables, err := queryStrings(conn, fmt.Sprintf(`SHOW TABLES FROM %s`, parser.Name(sql.UserfileDatabase)))
+	if err != nil {
+		return err
+	}
+	files, payload := sql.UserfileTables(user)
+	for _, table := range []struct {
+		name, qname, schema string
+	}{
+		{user + "_upload_files", files, `(
+	filename STRING PRIMARY KEY,
+	file_id BYTES NOT NULL,
+	file_size INT NOT NULL,
+	upload_time TIMESTAMP NOT NULL DEFAULT now()
+)`},
+		{user + "_upload_payload", payload, `(
+	file_id BYTES,
+	byte_offset INT,
+	payload BYTES,
+	PRIMARY KEY (file_id, byte_offset)
+)`},
+	} {
+		if _, ok := tables[table.name]; ok {
+			continue
+		}
+		if err := conn.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s %s`, table.qname, table.schema),
+			nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryStrings returns the values of the first column of the results of a
+// query.
+func queryStrings(conn *sqlConn, query string) (map[string]struct{}, error) {
+	_, rows, _, err := runQuery(conn, makeQuery(query), false)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		values[row[0]] = struct{}{}
+	}
+	return values, nil
+}
+
+var userfileUploadCmd = &cobra.Command{
+	Use:   "upload <source> [<destination>]",
+	Short: "upload a local file to the cluster",
+	Long: `
+Upload the local file <source> into storage managed by the cluster, where it
+is stored in SQL tables owned by the current user in the "userfiles"
+database. The file is named <destination>, or the base name of <source> if no
+destination is given, and can afterwards be referred to as
+userfile://<destination>, e.g. to read it in SQL with
+crdb_internal.userfile('userfile://<destination>'). Existing files are not
+overwritten.
+`,
+	RunE:         runUserfileUpload,
+	SilenceUsage: true,
+}
+
+func runUserfileUpload(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		mustUsage(cmd)
+		return errMissingParams
+	}
+	source := args[0]
+	dest := filepath.Base(source)
+	if len(args) == 2 {
+		dest = args[1]
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	conn, err := makeSQLClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	name, err := uploadUserfile(conn, connUser, f, dest)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("successfully uploaded to %s%s\n", sql.UserfileURIScheme, name)
+	return nil
+}
+
+// uploadUserfile stores the contents of r as the file dest of the given user
+// and returns the name under which it was stored. The chunks of the file are
+// stored under a new file ID, each in a transaction of its own, and the file
+// is published by inserting its row in the files table once all the chunks
+// are stored. A failed upload deletes the chunks it stored.
+func uploadUserfile(conn *sqlConn, user string, r io.Reader, dest string) (string, error) {
+	name, err := sql.UserfileName(dest)
+	if err != nil {
+		return "", err
+	}
+	if err := createUserfileTables(conn, user); err != nil {
+		return "", err
+	}
+	files, payload := sql.UserfileTables(user)
+
+	if _, err := conn.QueryRow(fmt.Sprintf(`SELECT 1 FROM %s WHERE filename = $1`, files),
+		[]driver.Value{name}); err == nil {
+		return "", errors.Errorf("file %s already exists", name)
+	} else if err != io.EOF {
+		return "", err
+	}
+
+	fileID := uuid.MakeV4().GetBytes()
+	err = func() error {
+		buf := make([]byte, userfileChunkSize)
+		var offset int64
+		for {
+			n, err := io.ReadFull(r, buf)
+			if n > 0 {
+				if err := conn.Exec(fmt.Sprintf(`INSERT INTO %s VALUES ($1, $2, $3)`, payload),
+					[]driver.Value{fileID, offset, buf[:n]}); err != nil {
+					return err
+				}
+				offset += int64(n)
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			} else if err != nil {
+				return err
+			}
+		}
+		return conn.Exec(fmt.Sprintf(`INSERT INTO %s (filename, file_id, file_size) VALUES ($1, $2, $3)`,
+			files), []driver.Value{name, fileID, offset})
+	}()
+	if err != nil {
+		if dErr := conn.Exec(fmt.Sprintf(`DELETE FROM %s WHERE file_id = $1`, payload),
+			[]driver.Value{fileID}); dErr != nil {
+			return "", errors.Wrapf(err, "and deleting the uploaded chunks failed: %s", dErr)
+		}
+		return "", err
+	}
+	return name, nil
+}
+
+var userfileGetCmd = &cobra.Command{
+	Use:   "get <file> [<destination>]",
+	Short: "download an uploaded file",
+	Long: `
+Download the uploaded file <file> to the local file <destination>, or to a
+file with the same base name in the current directory if no destination is
+given.
+`,
+	RunE:         runUserfileGet,
+	SilenceUsage: true,
+}
+
+func runUserfileGet(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		mustUsage(cmd)
+		return errMissingParams
+	}
+	name, err := sql.UserfileName(args[0])
+	if err != nil {
+		return err
+	}
+	dest := path.Base(name)
+	if len(args) == 2 {
+		dest = args[1]
+	}
+
+	conn, err := makeSQLClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if err := getUserfile(conn, connUser, name, f); err != nil {
+		// Don't leave a partial download behind.
+		_ = f.Close()
+		_ = os.Remove(dest)
+		return err
+	}
+	return f.Close()
+}
+
+// getUserfile writes the contents of the given user's file to w.
+func getUserfile(conn *sqlConn, user string, name string, w io.Writer) error {
+	files, payload := sql.UserfileTables(user)
+	vals, err := conn.QueryRow(fmt.Sprintf(`SELECT file_id, file_size FROM %s WHERE filename = $1`, files),
+		[]driver.Value{name})
+	if err == io.EOF {
+		return errors.Errorf("file %s does not exist", name)
+	} else if err != nil {
+		return err
+	}
+	fileID := vals[0]
+	size, ok := vals[1].(int64)
+	if !ok {
+		return errors.Errorf("unexpected file size %v", vals[1])
+	}
+
+	rows, err := makeQuery(fmt.Sprintf(`SELECT byte_offset, payload FROM %s WHERE file_id = $1 ORDER BY byte_offset`,
+		payload), fileID)(conn)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	var written int64
+	vals = make([]driver.Value, 2)
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		offset, _ := vals[0].(int64)
+		chunk, ok := vals[1].([]byte)
+		if !ok || offset != written {
+			return errors.Errorf("file %s is corrupt at offset %d", name, written)
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+		written += int64(len(chunk))
+	}
+	if written != size {
+		return errors.Errorf("file %s is truncated: expected %d bytes, found %d", name, size, written)
+	}
+	return nil
+}
+
+var userfileListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "list uploaded files",
+	Long: `
+List the files uploaded by the current user.
+`,
+	RunE:         runUserfileList,
+	SilenceUsage: true,
+}
+
+func runUserfileList(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		mustUsage(cmd)
+		return errMissingParams
+	}
+	conn, err := makeSQLClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := createUserfileTables(conn, connUser); err != nil {
+		return err
+	}
+	files, _ := sql.UserfileTables(connUser)
+	return runQueryAndFormatResults(conn, os.Stdout,
+		makeQuery(fmt.Sprintf(`SELECT filename, file_size, upload_time FROM %s ORDER BY filename`, files)),
+		cliCtx.tableDisplayFormat)
+}
+
+var userfileDeleteCmd = &cobra.Command{
+	Use:   "delete <file>",
+	Short: "delete an uploaded file",
+	Long: `
+Delete the uploaded file <file>.
+`,
+	RunE:         runUserfileDelete,
+	SilenceUsage: true,
+}
+
+func runUserfileDelete(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		mustUsage(cmd)
+		return errMissingParams
+	}
+	name, err := sql.UserfileName(args[0])
+	if err != nil {
+		return err
+	}
+	conn, err := makeSQLClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := deleteUserfile(conn, connUser, name); err != nil {
+		return err
+	}
+	fmt.Printf("successfully deleted %s%s\n", sql.UserfileURIScheme, name)
+	return nil
+}
+
+// deleteUserfile removes the given user's file. The file is unpublished
+// before its chunks are deleted.
+func deleteUserfile(conn *sqlConn, user string, name string) error {
+	files, payload := sql.UserfileTables(user)
+	vals, err := conn.QueryRow(fmt.Sprintf(`DELETE FROM %s WHERE filename = $1 RETURNING file_id`, files),
+		[]driver.Value{name})
+	if err == io.EOF {
+		return errors.Errorf("file %s does not exist", name)
+	} else if err != nil {
+		return err
+	}
+	return conn.Exec(fmt.Sprintf(`DELETE FROM %s WHERE file_id = $1`, payload), []driver.Value{vals[0]})
+}
+
+var userfileCmds = []*cobra.Command{
+	userfileUploadCmd,
+	userfileGetCmd,
+	userfileListCmd,
+	userfileDeleteCmd,
+}
+
+var userfileCmd = &cobra.Command{
+	Use:   "userfile [command]",
+	Short: "upload, list and delete files stored in the cluster",
+	Long: `
+Upload local files into storage managed by the cluster, so that they can be
+used as the source of bulk operations without access to external storage.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mustUsage(cmd)
+	},
+}
+
+func init() {
+	userfileCmd.AddCommand(userfileCmds...)
+}
@@ -0,0 +1,98 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestUserfile uploads a file in several chunks, then lists, downloads and
+// deletes it.
+func TestUserfile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(size int) { userfileChunkSize = size }(userfileChunkSize)
+	userfileChunkSize = 7
+
+	dir, err := ioutil.TempDir("", "TestUserfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Error(err)
+		}
+	}()
+	contents := []byte("the quick brown fox jumps over the lazy dog")
+	source := filepath.Join(dir, "source.csv")
+	if err := ioutil.WriteFile(source, contents, 0644); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(dir, "dest.csv")
+
+	c := newCLITest()
+	defer c.stop()
+
+	for _, test := range []struct {
+		line     string
+		expected string
+	}{
+		{"userfile upload " + source + " data/a.csv", "successfully uploaded to userfile://data/a.csv"},
+		{"userfile upload " + source + " data/a.csv", "file data/a.csv already exists"},
+		{"userfile ls", "data/a.csv\t43\t"},
+		{"userfile get userfile://data/a.csv " + dest, ""},
+		{"userfile get missing.csv " + filepath.Join(dir, "missing.csv"), "file missing.csv does not exist"},
+		{"userfile delete data/a.csv", "successfully deleted userfile://data/a.csv"},
+		{"userfile ls", "0 rows"},
+	} {
+		out, err := c.RunWithCapture(test.line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, test.expected) {
+			t.Errorf("%s: expected %q, got:\n%s", test.line, test.expected, out)
+		}
+	}
+
+	// The file can be read by the statements of the user, until it is
+	// deleted.
+	if _, err := c.RunWithCapture("userfile upload " + source + " b.csv"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := captureOutput(func() {
+		c.RunWithArgs([]string{"sql", "-e", "SELECT LENGTH(crdb_internal.userfile('userfile://b.csv'))"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "43") {
+		t.Errorf("expected the length of the file, got:\n%s", out)
+	}
+
+	downloaded, err := ioutil.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, contents) {
+		t.Errorf("expected %q, got %q", contents, downloaded)
+	}
+}
@@ -0,0 +1,442 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/util/randutil"
+	"github.com/cockroachdb/cockroach/util/timeutil"
+	"github.com/cockroachdb/pq"
+)
+
+// workloadTable describes a table of a workload and how to generate its
+// initial data.
+type workloadTable struct {
+	name string
+	// schema is the parenthesized list of column and index definitions of
+	// the table.
+	schema string
+	// rows is the number of initial rows.
+	rows int
+	// row returns the SQL literals of the values of the i-th initial row.
+	row func(i int) []string
+}
+
+// workload is a named set of tables together with the operation which
+// is run repeatedly against them.
+type workload struct {
+	name        string
+	description string
+	tables      func() []workloadTable
+	// op runs one operation of the workload. Each worker has its own
+	// connection and random number generator.
+	op func(conn *sqlConn, rng *rand.Rand) error
+}
+
+// workloadBatchSize is the number of rows inserted per statement when
+// loading initial data.
+const workloadBatchSize = 100
+
+var workloads = map[string]workload{
+	"kv": {
+		name: "kv",
+		description: "reads and writes of random keys in a single table; " +
+			"use --read-percent to choose the mix",
+		tables: func() []workloadTable {
+			return []workloadTable{{
+				name:   "kv",
+				schema: "(k INT PRIMARY KEY, v BYTES)",
+				rows:   workloadCtx.rows,
+				row: func(i int) []string {
+					return []string{fmt.Sprint(i), parser.NewDBytes(parser.DBytes(fmt.Sprintf("v%d", i))).String()}
+				},
+			}}
+		},
+		op: func(conn *sqlConn, rng *rand.Rand) error {
+			k := rng.Intn(workloadKeySpace())
+			if rng.Intn(100) < workloadCtx.readPercent {
+				_, err := conn.QueryRow(`SELECT v FROM kv.kv WHERE k = $1`, []driver.Value{int64(k)})
+				if err == io.EOF {
+					err = nil
+				}
+				return err
+			}
+			v := randutil.RandBytes(rng, 64)
+			return conn.Exec(`UPSERT INTO kv.kv VALUES ($1, $2)`, []driver.Value{int64(k), v})
+		},
+	},
+	"bank": {
+		name:        "bank",
+		description: "transfers between random accounts; the total balance never changes",
+		tables: func() []workloadTable {
+			return []workloadTable{{
+				name:   "accounts",
+				schema: "(id INT PRIMARY KEY, balance INT NOT NULL)",
+				rows:   workloadCtx.rows,
+				row: func(i int) []string {
+					return []string{fmt.Sprint(i), "1000"}
+				},
+			}}
+		},
+		op: func(conn *sqlConn, rng *rand.Rand) error {
+			from, to := rng.Intn(workloadKeySpace()), rng.Intn(workloadKeySpace())
+			if from == to {
+				return nil
+			}
+			amount := rng.Intn(100)
+			// The transfer runs as a single statement and thus in a single
+			// implicit transaction, which the server retries on conflicts.
+			return conn.Exec(fmt.Sprintf(`
+UPDATE bank.accounts
+  SET balance = CASE id WHEN %[1]d THEN balance - %[3]d WHEN %[2]d THEN balance + %[3]d END
+  WHERE id IN (%[1]d, %[2]d)`, from, to, amount), nil)
+		},
+	},
+	"tpcc": tpccWorkload,
+}
+
+// runWorkloadTxn runs fn in a transaction on conn. The transaction is
+// retried from its start when the server returns a retryable error. See
+// Executor.execStmtInAbortedTxn for the protocol of the retries.
+func runWorkloadTxn(conn *sqlConn, fn func() error) (err error) {
+	if err := conn.Exec(`BEGIN; SAVEPOINT cockroach_restart`, nil); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = conn.Exec(`ROLLBACK`, nil)
+		}
+	}()
+	for {
+		err = fn()
+		if err == nil {
+			err = conn.Exec(`RELEASE SAVEPOINT cockroach_restart`, nil)
+		}
+		if pqErr, ok := err.(*pq.Error); !ok || pqErr.Code != pgerror.CodeSerializationFailureError {
+			break
+		}
+		if err = conn.Exec(`ROLLBACK TO SAVEPOINT cockroach_restart`, nil); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return conn.Exec(`COMMIT`, nil)
+}
+
+// workloadKeySpace returns the number of keys the operations of a workload
+// choose from.
+func workloadKeySpace() int {
+	if workloadCtx.rows < 1 {
+		return 1
+	}
+	return workloadCtx.rows
+}
+
+func getWorkload(cmd *cobra.Command, args []string) (workload, error) {
+	if len(args) != 1 {
+		mustUsage(cmd)
+		return workload{}, errMissingParams
+	}
+	w, ok := workloads[args[0]]
+	if !ok {
+		return workload{}, errors.Errorf("unknown workload %q (available: %s)",
+			args[0], strings.Join(workloadNames(), ", "))
+	}
+	return w, nil
+}
+
+func workloadNames() []string {
+	var names []string
+	for name := range workloads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeWorkloadFixture writes the SQL statements which create the
+// database and tables of the workload and insert their initial data.
+func writeWorkloadFixture(out io.Writer, w workload) error {
+	dbName := parser.Name(w.name).String()
+	if _, err := fmt.Fprintf(out, "CREATE DATABASE IF NOT EXISTS %s;\n", dbName); err != nil {
+		return err
+	}
+	for _, t := range w.tables() {
+		name := fmt.Sprintf("%s.%s", dbName, parser.Name(t.name))
+		if _, err := fmt.Fprintf(out, "CREATE TABLE IF NOT EXISTS %s %s;\n", name, t.schema); err != nil {
+			return err
+		}
+		for start := 0; start < t.rows; start += workloadBatchSize {
+			if err := writeWorkloadInsert(out, name, t, start); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeWorkloadInsert writes a single INSERT statement for the batch of
+// initial rows of the table starting at row start.
+func writeWorkloadInsert(out io.Writer, name string, t workloadTable, start int) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "INSERT INTO %s VALUES", name)
+	for i := start; i < t.rows && i < start+workloadBatchSize; i++ {
+		if i > start {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, "\n\t(%s)", strings.Join(t.row(i), ", "))
+	}
+	buf.WriteString(";\n")
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+var workloadFixturesCmd = &cobra.Command{
+	Use:   "fixtures <workload>",
+	Short: "print the SQL statements which load the initial data of a workload",
+	Long: `
+Print the SQL statements which create the tables of a workload and load
+their initial data. The output can be saved and loaded later with
+"cockroach sql".
+`,
+	RunE:         runWorkloadFixtures,
+	SilenceUsage: true,
+}
+
+func runWorkloadFixtures(cmd *cobra.Command, args []string) error {
+	w, err := getWorkload(cmd, args)
+	if err != nil {
+		return err
+	}
+	return writeWorkloadFixture(os.Stdout, w)
+}
+
+var workloadInitCmd = &cobra.Command{
+	Use:   "init <workload>",
+	Short: "create the tables of a workload and load their initial data",
+	Long: `
+Create the database and tables of a workload and load their initial data.
+Tables which already exist are left untouched, but their initial data
+is inserted again.
+`,
+	RunE:         runWorkloadInit,
+	SilenceUsage: true,
+}
+
+func runWorkloadInit(cmd *cobra.Command, args []string) error {
+	w, err := getWorkload(cmd, args)
+	if err != nil {
+		return err
+	}
+	conn, err := makeSQLClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return initWorkload(conn, w)
+}
+
+func initWorkload(conn *sqlConn, w workload) error {
+	dbName := parser.Name(w.name).String()
+	if err := conn.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", dbName), nil); err != nil {
+		return err
+	}
+	for _, t := range w.tables() {
+		name := fmt.Sprintf("%s.%s", dbName, parser.Name(t.name))
+		if err := conn.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s %s", name, t.schema), nil); err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		for start := 0; start < t.rows; start += workloadBatchSize {
+			buf.Reset()
+			if err := writeWorkloadInsert(&buf, name, t, start); err != nil {
+				return err
+			}
+			// Use UPSERT so that initializing twice is not an error.
+			stmt := "UPSERT" + strings.TrimPrefix(buf.String(), "INSERT")
+			if err := conn.Exec(stmt, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var workloadRunCmd = &cobra.Command{
+	Use:   "run <workload>",
+	Short: "run a workload against the cluster",
+	Long: `
+Run the operations of a workload against the cluster, using --concurrency
+connections, until --duration has elapsed or --max-ops operations have
+run. The tables must have been created with "cockroach workload init".
+Throughput is printed every second, and a summary at the end.
+`,
+	RunE:         runWorkloadRun,
+	SilenceUsage: true,
+}
+
+func runWorkloadRun(cmd *cobra.Command, args []string) error {
+	w, err := getWorkload(cmd, args)
+	if err != nil {
+		return err
+	}
+	return runWorkload(os.Stdout, w)
+}
+
+// workloadStats accumulates the results of the operations of a workload.
+type workloadStats struct {
+	ops, errors int64
+	// latency is the total latency of the operations, in nanoseconds.
+	latency int64
+}
+
+func runWorkload(out io.Writer, w workload) error {
+	if workloadCtx.concurrency < 1 {
+		return errors.Errorf("invalid concurrency %d", workloadCtx.concurrency)
+	}
+
+	var stats workloadStats
+	done := make(chan struct{})
+	var doneOnce sync.Once
+	stop := func() { doneOnce.Do(func() { close(done) }) }
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, workloadCtx.concurrency)
+	for i := 0; i < workloadCtx.concurrency; i++ {
+		conn, err := makeSQLClient()
+		if err != nil {
+			stop()
+			wg.Wait()
+			return err
+		}
+		rng, _ := randutil.NewPseudoRand()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				ops := atomic.AddInt64(&stats.ops, 1)
+				if n := workloadCtx.maxOps; n > 0 && ops > n {
+					stop()
+					return
+				}
+				start := timeutil.Now()
+				err := w.op(conn, rng)
+				atomic.AddInt64(&stats.latency, int64(timeutil.Since(start)))
+				if err != nil {
+					if atomic.AddInt64(&stats.errors, 1) == 1 {
+						// Report the first error; later ones are only counted.
+						errCh <- err
+					}
+				}
+			}
+		}()
+	}
+
+	var timeout <-chan time.Time
+	if workloadCtx.duration > 0 {
+		timeout = time.After(workloadCtx.duration)
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	start := timeutil.Now()
+	var lastOps int64
+	fmt.Fprintln(out, "_elapsed___ops/sec___ops(total)___errors")
+	for running := true; running; {
+		select {
+		case <-ticker.C:
+			ops := atomic.LoadInt64(&stats.ops)
+			fmt.Fprintf(out, "%8s %9d %12d %8d\n", timeutil.Since(start)/time.Second*time.Second,
+				ops-lastOps, ops, atomic.LoadInt64(&stats.errors))
+			lastOps = ops
+		case err := <-errCh:
+			fmt.Fprintf(osStderr, "error: %s\n", err)
+		case <-timeout:
+			stop()
+			running = false
+		case <-done:
+			running = false
+		}
+	}
+	wg.Wait()
+
+	elapsed := timeutil.Since(start)
+	ops := atomic.LoadInt64(&stats.ops)
+	if n := workloadCtx.maxOps; n > 0 && ops > n {
+		ops = n
+	}
+	var avgLatency time.Duration
+	if ops > 0 {
+		avgLatency = time.Duration(atomic.LoadInt64(&stats.latency) / ops)
+	}
+	fmt.Fprintf(out, "\n%s: %d ops in %s (%.1f ops/sec), %d errors, %s average latency\n",
+		w.name, ops, elapsed, float64(ops)/elapsed.Seconds(), atomic.LoadInt64(&stats.errors), avgLatency)
+	return nil
+}
+
+var workloadCmds = []*cobra.Command{
+	workloadInitCmd,
+	workloadRunCmd,
+	workloadFixturesCmd,
+}
+
+var workloadCmd = &cobra.Command{
+	Use:   "workload [command]",
+	Short: "generate data and load for benchmarks and demos\n",
+	Long: `
+Initialize and run built-in workloads against a cluster. The available
+workloads are:
+` + workloadDescriptions(),
+	Run: func(cmd *cobra.Command, args []string) {
+		mustUsage(cmd)
+	},
+}
+
+func workloadDescriptions() string {
+	var buf bytes.Buffer
+	for _, name := range workloadNames() {
+		fmt.Fprintf(&buf, "\n  %-6s %s", name, workloads[name].description)
+	}
+	buf.WriteString("\n")
+	return buf.String()
+}
+
+func init() {
+	workloadCmd.AddCommand(workloadCmds...)
+}
@@ -0,0 +1,116 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+func TestWorkloadFixture(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(rows int) { workloadCtx.rows = rows }(workloadCtx.rows)
+	workloadCtx.rows = 3
+
+	var buf bytes.Buffer
+	if err := writeWorkloadFixture(&buf, workloads["bank"]); err != nil {
+		t.Fatal(err)
+	}
+	const expected = `CREATE DATABASE IF NOT EXISTS bank;
+CREATE TABLE IF NOT EXISTS bank.accounts (id INT PRIMARY KEY, balance INT NOT NULL);
+INSERT INTO bank.accounts VALUES
+	(0, 1000),
+	(1, 1000),
+	(2, 1000);
+`
+	if out := buf.String(); out != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+// TestWorkloadBank runs the bank workload and verifies that transfers
+// preserve the total balance.
+func TestWorkloadBank(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := newCLITest()
+	defer c.stop()
+
+	for _, line := range []string{
+		"workload init bank --rows=10",
+		// Initializing twice must not fail.
+		"workload init bank --rows=10",
+		"workload run bank --rows=10 --concurrency=2 --max-ops=50",
+	} {
+		out, err := c.RunWithCapture(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(out, "error:") {
+			t.Fatalf("%s: unexpected error:\n%s", line, out)
+		}
+	}
+
+	out, err := captureOutput(func() {
+		c.RunWithArgs([]string{"sql", "-e", "select count(*), sum(balance) from bank.accounts"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "10\t10000") {
+		t.Fatalf("expected 10 accounts with a total balance of 10000, got:\n%s", out)
+	}
+}
+
+// TestWorkloadTPCC runs the tpcc workload and verifies the consistency
+// conditions of TPC-C which apply to it.
+func TestWorkloadTPCC(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := newCLITest()
+	defer c.stop()
+
+	for _, line := range []string{
+		"workload init tpcc --rows=20",
+		"workload run tpcc --rows=20 --concurrency=2 --max-ops=50",
+	} {
+		out, err := c.RunWithCapture(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(out, "error:") {
+			t.Fatalf("%s: unexpected error:\n%s", line, out)
+		}
+	}
+
+	// The year-to-date amount of the warehouse is the sum of those of its
+	// districts, and the order IDs of each district are allocated without
+	// gaps.
+	out, err := captureOutput(func() {
+		c.RunWithArgs([]string{"sql", "-e", `
+SELECT (SELECT sum(w_ytd) FROM tpcc.warehouse) = (SELECT sum(d_ytd) FROM tpcc.district),
+       (SELECT sum(1) FROM tpcc.orders) = (SELECT sum(d_next_o_id - 1) FROM tpcc.district)`})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "true\ttrue") {
+		t.Fatalf("expected the tpcc tables to be consistent, got:\n%s", out)
+	}
+}
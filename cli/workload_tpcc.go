@@ -0,0 +1,219 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+// The tpcc workload is modeled after the TPC-C benchmark, scaled down to a
+// single warehouse: the districts, customers, items and stock of the
+// warehouse are not keyed by the warehouse, and --rows is the number of
+// customers and of items. Only the new-order, payment and order-status
+// transactions are run.
+const (
+	// tpccDistricts is the number of districts of the warehouse. Customer
+	// i belongs to district i % tpccDistricts.
+	tpccDistricts = 10
+	// tpccNewOrderPercent and tpccPaymentPercent are the percentages of the
+	// new-order and payment transactions; the others are order-status
+	// transactions.
+	tpccNewOrderPercent = 45
+	tpccPaymentPercent  = 43
+	// tpccMinOrderLines and tpccMaxOrderLines bound the number of lines of
+	// a new order.
+	tpccMinOrderLines = 5
+	tpccMaxOrderLines = 15
+)
+
+var tpccWorkload = workload{
+	name: "tpcc",
+	description: "a TPC-C-like mix of new-order, payment and order-status " +
+		"transactions against a single warehouse",
+	tables: func() []workloadTable {
+		return []workloadTable{
+			{
+				name:   "warehouse",
+				schema: "(w_id INT PRIMARY KEY, w_ytd INT NOT NULL)",
+				rows:   1,
+				row: func(i int) []string {
+					return []string{fmt.Sprint(i), "0"}
+				},
+			},
+			{
+				name:   "district",
+				schema: "(d_id INT PRIMARY KEY, d_ytd INT NOT NULL, d_next_o_id INT NOT NULL)",
+				rows:   tpccDistricts,
+				row: func(i int) []string {
+					return []string{fmt.Sprint(i), "0", "1"}
+				},
+			},
+			{
+				name: "customer",
+				schema: "(c_id INT PRIMARY KEY, c_d_id INT NOT NULL, " +
+					"c_balance INT NOT NULL, c_payment_cnt INT NOT NULL)",
+				rows: workloadCtx.rows,
+				row: func(i int) []string {
+					return []string{fmt.Sprint(i), fmt.Sprint(i % tpccDistricts), "0", "0"}
+				},
+			},
+			{
+				name:   "item",
+				schema: "(i_id INT PRIMARY KEY, i_price INT NOT NULL)",
+				rows:   workloadCtx.rows,
+				row: func(i int) []string {
+					return []string{fmt.Sprint(i), fmt.Sprint(100 + i%9900)}
+				},
+			},
+			{
+				name: "stock",
+				schema: "(s_i_id INT PRIMARY KEY, s_quantity INT NOT NULL, " +
+					"s_ytd INT NOT NULL, s_order_cnt INT NOT NULL)",
+				rows: workloadCtx.rows,
+				row: func(i int) []string {
+					return []string{fmt.Sprint(i), "100", "0", "0"}
+				},
+			},
+			{
+				name: "orders",
+				schema: "(o_d_id INT, o_id INT, o_c_id INT NOT NULL, o_ol_cnt INT NOT NULL, " +
+					"PRIMARY KEY (o_d_id, o_id), INDEX (o_d_id, o_c_id, o_id))",
+			},
+			{
+				name: "order_line",
+				schema: "(ol_d_id INT, ol_o_id INT, ol_number INT, ol_i_id INT NOT NULL, " +
+					"ol_quantity INT NOT NULL, ol_amount INT NOT NULL, " +
+					"PRIMARY KEY (ol_d_id, ol_o_id, ol_number))",
+			},
+		}
+	},
+	op: func(conn *sqlConn, rng *rand.Rand) error {
+		c := rng.Intn(workloadKeySpace())
+		d := c % tpccDistricts
+		switch p := rng.Intn(100); {
+		case p < tpccNewOrderPercent:
+			return tpccNewOrder(conn, rng, d, c)
+		case p < tpccNewOrderPercent+tpccPaymentPercent:
+			return tpccPayment(conn, d, c, 1+rng.Intn(5000))
+		default:
+			return tpccOrderStatus(conn, d, c)
+		}
+	},
+}
+
+// tpccNewOrder enters an order of customer c of district d, with a random
+// number of lines of random items.
+func tpccNewOrder(conn *sqlConn, rng *rand.Rand, d, c int) error {
+	type orderLine struct {
+		item, quantity int
+	}
+	lines := make([]orderLine, tpccMinOrderLines+rng.Intn(tpccMaxOrderLines-tpccMinOrderLines+1))
+	for i := range lines {
+		lines[i] = orderLine{item: rng.Intn(workloadKeySpace()), quantity: 1 + rng.Intn(10)}
+	}
+	return runWorkloadTxn(conn, func() error {
+		vals, err := conn.QueryRow(fmt.Sprintf(
+			`SELECT d_next_o_id FROM tpcc.district WHERE d_id = %d`, d), nil)
+		if err != nil {
+			return err
+		}
+		o, ok := vals[0].(int64)
+		if !ok {
+			return errors.Errorf("unexpected next order ID %v", vals[0])
+		}
+		if err := conn.Exec(fmt.Sprintf(
+			`UPDATE tpcc.district SET d_next_o_id = d_next_o_id + 1 WHERE d_id = %d`, d), nil); err != nil {
+			return err
+		}
+		if err := conn.Exec(fmt.Sprintf(
+			`INSERT INTO tpcc.orders VALUES (%d, %d, %d, %d)`, d, o, c, len(lines)), nil); err != nil {
+			return err
+		}
+		for i, l := range lines {
+			vals, err := conn.QueryRow(fmt.Sprintf(
+				`SELECT i_price FROM tpcc.item WHERE i_id = %d`, l.item), nil)
+			if err != nil {
+				return err
+			}
+			price, ok := vals[0].(int64)
+			if !ok {
+				return errors.Errorf("unexpected price %v", vals[0])
+			}
+			// As in TPC-C, the stock is replenished when it runs low.
+			if err := conn.Exec(fmt.Sprintf(`
+UPDATE tpcc.stock
+  SET s_quantity = CASE WHEN s_quantity >= %[2]d + 10 THEN s_quantity - %[2]d ELSE s_quantity - %[2]d + 91 END,
+      s_ytd = s_ytd + %[2]d,
+      s_order_cnt = s_order_cnt + 1
+  WHERE s_i_id = %[1]d`, l.item, l.quantity), nil); err != nil {
+				return err
+			}
+			if err := conn.Exec(fmt.Sprintf(
+				`INSERT INTO tpcc.order_line VALUES (%d, %d, %d, %d, %d, %d)`,
+				d, o, i+1, l.item, l.quantity, int64(l.quantity)*price), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// tpccPayment records a payment of customer c of district d. The
+// year-to-date amounts of the warehouse and of the districts always add up.
+func tpccPayment(conn *sqlConn, d, c, amount int) error {
+	return runWorkloadTxn(conn, func() error {
+		if err := conn.Exec(fmt.Sprintf(
+			`UPDATE tpcc.warehouse SET w_ytd = w_ytd + %d WHERE w_id = 0`, amount), nil); err != nil {
+			return err
+		}
+		if err := conn.Exec(fmt.Sprintf(
+			`UPDATE tpcc.district SET d_ytd = d_ytd + %d WHERE d_id = %d`, amount, d), nil); err != nil {
+			return err
+		}
+		return conn.Exec(fmt.Sprintf(`
+UPDATE tpcc.customer
+  SET c_balance = c_balance - %d, c_payment_cnt = c_payment_cnt + 1
+  WHERE c_id = %d`, amount, c), nil)
+	})
+}
+
+// tpccOrderStatus reads the balance of customer c of district d and the
+// lines of their last order, if any.
+func tpccOrderStatus(conn *sqlConn, d, c int) error {
+	return runWorkloadTxn(conn, func() error {
+		if _, err := conn.QueryRow(fmt.Sprintf(
+			`SELECT c_balance FROM tpcc.customer WHERE c_id = %d`, c), nil); err != nil {
+			return err
+		}
+		vals, err := conn.QueryRow(fmt.Sprintf(`
+SELECT o_id FROM tpcc.orders
+  WHERE o_d_id = %d AND o_c_id = %d
+  ORDER BY o_id DESC LIMIT 1`, d, c), nil)
+		if err == io.EOF {
+			// The customer has no orders yet.
+			return nil
+		} else if err != nil {
+			return err
+		}
+		_, err = conn.QueryRow(fmt.Sprintf(`
+SELECT count(*), sum(ol_amount) FROM tpcc.order_line
+  WHERE ol_d_id = %d AND ol_o_id = %d`, d, vals[0]), nil)
+		return err
+	})
+}
@@ -388,7 +388,7 @@ func runRmZone(cmd *cobra.Command, args []string) error {
 	}
 
 	if err := runQueryAndFormatResults(conn, os.Stdout,
-		makeQuery(`DELETE FROM system.zones WHERE id=$1`, id), cliCtx.prettyFmt); err != nil {
+		makeQuery(`DELETE FROM system.zones WHERE id=$1`, id), cliCtx.tableDisplayFormat); err != nil {
 		return err
 	}
 	return conn.Exec(`COMMIT`, nil)
@@ -486,10 +486,10 @@ func runSetZone(cmd *cobra.Command, args []string) error {
 	id := path[len(path)-1]
 	if id == zoneID {
 		err = runQueryAndFormatResults(conn, os.Stdout,
-			makeQuery(`UPDATE system.zones SET config = $2 WHERE id = $1`, id, buf), cliCtx.prettyFmt)
+			makeQuery(`UPDATE system.zones SET config = $2 WHERE id = $1`, id, buf), cliCtx.tableDisplayFormat)
 	} else {
 		err = runQueryAndFormatResults(conn, os.Stdout,
-			makeQuery(`INSERT INTO system.zones VALUES ($1, $2)`, id, buf), cliCtx.prettyFmt)
+			makeQuery(`INSERT INTO system.zones VALUES ($1, $2)`, id, buf), cliCtx.tableDisplayFormat)
 	}
 	if err != nil {
 		return err
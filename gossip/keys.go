@@ -18,6 +18,7 @@ package gossip
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/roachpb"
@@ -64,6 +65,13 @@ const (
 	// The value if a config.SystemConfig which holds all key/value
 	// pairs in the system DB span.
 	KeySystemConfig = "system-db"
+
+	// KeyTableLeaseInvalidationPrefix is the key prefix for gossiping that a
+	// new version of a table descriptor was published, and that the unused
+	// leases on its previous versions must be released. The suffix is the
+	// table ID and the value is the decimal representation of the new
+	// version.
+	KeyTableLeaseInvalidationPrefix = "table-lease-invalidation"
 )
 
 // MakeKey creates a canonical key under which to gossip a piece of
@@ -86,6 +94,12 @@ func MakeNodeIDKey(nodeID roachpb.NodeID) string {
 	return MakeKey(KeyNodeIDPrefix, nodeID.String())
 }
 
+// MakeTableLeaseInvalidationKey returns the gossip key for invalidating the
+// leases on the previous versions of a table descriptor.
+func MakeTableLeaseInvalidationKey(tableID uint32) string {
+	return MakeKey(KeyTableLeaseInvalidationPrefix, strconv.FormatUint(uint64(tableID), 10))
+}
+
 // MakeStoreKey returns the gossip key for the given store.
 func MakeStoreKey(storeID roachpb.StoreID) string {
 	return MakeKey(KeyStorePrefix, storeID.String())
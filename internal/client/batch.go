@@ -258,6 +258,9 @@ func (b *Batch) fillResults() error {
 			case *roachpb.RequestLeaseRequest:
 			case *roachpb.CheckConsistencyRequest:
 			case *roachpb.ChangeFrozenRequest:
+			case *roachpb.ExportRequest:
+			case *roachpb.AddSSTableRequest:
+			case *roachpb.ClearRangeRequest:
 			}
 		}
 		offset += result.calls
@@ -507,6 +510,26 @@ func (b *Batch) CheckConsistency(s, e interface{}, withDiff bool) {
 	b.initResult(1, 0, notRaw, nil)
 }
 
+// ClearRange creates a batch request to remove all of the data in the span
+// of keys from s to e, without leaving MVCC deletion tombstones behind. It
+// must only be used on spans which are no longer in use.
+func (b *Batch) ClearRange(s, e interface{}) {
+	begin, err := marshalKey(s)
+	if err != nil {
+		b.initResult(0, 0, notRaw, err)
+		return
+	}
+	end, err := marshalKey(e)
+	if err != nil {
+		b.initResult(0, 0, notRaw, err)
+		return
+	}
+	b.appendReqs(&roachpb.ClearRangeRequest{
+		Span: roachpb.Span{Key: roachpb.Key(begin), EndKey: roachpb.Key(end)},
+	})
+	b.initResult(1, 0, notRaw, nil)
+}
+
 // Del deletes one or more keys.
 //
 // A new result will be appended to the batch and each key will have a
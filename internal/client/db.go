@@ -379,6 +379,16 @@ func (db *DB) CheckConsistency(begin, end interface{}, withDiff bool) error {
 	return err
 }
 
+// ClearRange removes all of the data in the key span, without leaving MVCC
+// deletion tombstones behind. It is not transactional and must only be used
+// on spans which are no longer read or written.
+func (db *DB) ClearRange(begin, end interface{}) error {
+	b := db.NewBatch()
+	b.ClearRange(begin, end)
+	_, err := runOneResult(db, b)
+	return err
+}
+
 // sendAndFill is a helper which sends the given batch and fills its results,
 // returning the appropriate error which is either from the first failing call,
 // or an "internal" error.
@@ -357,6 +357,7 @@ func TestCommonMethods(t *testing.T) {
 		key{dbType, "GetProto"}:                {},
 		key{txnType, "GetProto"}:               {},
 		key{batchType, "CheckConsistency"}:     {},
+		key{batchType, "ClearRange"}:           {},
 		key{batchType, "AddRawRequest"}:        {},
 		key{batchType, "PutInline"}:            {},
 		key{batchType, "RawResponse"}:          {},
@@ -364,6 +365,7 @@ func TestCommonMethods(t *testing.T) {
 		key{dbType, "AdminMerge"}:              {},
 		key{dbType, "AdminSplit"}:              {},
 		key{dbType, "CheckConsistency"}:        {},
+		key{dbType, "ClearRange"}:              {},
 		key{dbType, "NewBatch"}:                {},
 		key{dbType, "Run"}:                     {},
 		key{dbType, "Txn"}:                     {},
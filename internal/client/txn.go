@@ -17,6 +17,7 @@
 package client
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gogo/protobuf/proto"
@@ -83,8 +84,10 @@ func (ts *txnSender) Send(ctx context.Context, ba roachpb.BatchRequest) (*roachp
 		if pErr.GetTxn() != nil {
 			ts.Proto.Priority = pErr.GetTxn().Priority
 		}
+		(*Txn)(ts).resetRefreshSpans()
 	} else if pErr.TransactionRestart != roachpb.TransactionRestart_NONE {
 		ts.Proto.Update(pErr.GetTxn())
+		(*Txn)(ts).resetRefreshSpans()
 	}
 	return nil, pErr
 }
@@ -106,8 +109,20 @@ type Txn struct {
 	deadline *hlc.Timestamp
 	// see IsFinalized()
 	finalized bool
+	// refreshSpans are the spans read by the current epoch of the
+	// transaction. They are refreshed when committing after the timestamp of
+	// the transaction was pushed, see maybeRefreshReads.
+	refreshSpans []roachpb.Span
+	// refreshSpansOverflow is set when the current epoch read more than
+	// maxTxnRefreshSpans distinct spans. They are no longer tracked, and the
+	// transaction restarts if its timestamp is pushed.
+	refreshSpansOverflow bool
 }
 
+// maxTxnRefreshSpans is the maximum number of distinct spans read by a
+// transaction which are tracked to be refreshed.
+const maxTxnRefreshSpans = 1000
+
 // NewTxn returns a new txn.
 func NewTxn(ctx context.Context, db DB) *Txn {
 	txn := &Txn{
@@ -591,6 +606,18 @@ func (txn *Txn) Exec(
 	return err
 }
 
+// GenerateForcedRetryableError returns a RetryableTxnError that causes the
+// transaction to be retried, as if the KV layer had requested it. If the
+// transaction has already sent requests, it is restarted at a new epoch so
+// that the next attempt ignores the intents written by this one.
+func (txn *Txn) GenerateForcedRetryableError(msg string) error {
+	if txn.Proto.IsInitialized() {
+		txn.Proto.Restart(txn.UserPriority, txn.Proto.Priority, txn.Proto.Timestamp)
+		txn.resetRefreshSpans()
+	}
+	return roachpb.NewRetryableTxnError(msg, txn.Proto.ID)
+}
+
 // send runs the specified calls synchronously in a single batch and
 // returns any errors. If the transaction is read-only or has already
 // been successfully committed or aborted, a potential trailing
@@ -667,9 +694,16 @@ func (txn *Txn) send(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.
 
 	if elideEndTxn {
 		ba.Requests = ba.Requests[:lastIndex]
+	} else if haveEndTxn && endTxnRequest.Commit {
+		if pErr := txn.maybeRefreshReads(); pErr != nil {
+			return nil, pErr
+		}
 	}
 
 	br, pErr := txn.db.send(ba)
+	if pErr == nil {
+		txn.trackReads(ba)
+	}
 	if elideEndTxn && pErr == nil {
 		// Check that read only transactions do not violate their deadline. This can NOT
 		// happen since the txn deadline is normally updated when it is about to expire
@@ -710,3 +744,83 @@ func (txn *Txn) send(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.
 	}
 	return br, pErr
 }
+
+// resetRefreshSpans forgets the spans read by the transaction, which is
+// called when a new epoch starts.
+func (txn *Txn) resetRefreshSpans() {
+	txn.refreshSpans = nil
+	txn.refreshSpansOverflow = false
+}
+
+// trackReads records the spans read by the requests of a batch. Once more
+// than maxTxnRefreshSpans distinct spans were read, they are dropped and the
+// transaction won't be able to refresh its reads.
+func (txn *Txn) trackReads(ba roachpb.BatchRequest) {
+	if txn.refreshSpansOverflow {
+		return
+	}
+	for _, union := range ba.Requests {
+		args := union.GetInner()
+		if !roachpb.IsReadOnly(args) {
+			continue
+		}
+		span := args.Header()
+		if len(span.EndKey) == 0 {
+			span.EndKey = span.Key.Next()
+		}
+		txn.refreshSpans = append(txn.refreshSpans, span)
+	}
+	if len(txn.refreshSpans) > maxTxnRefreshSpans {
+		roachpb.MergeSpans(&txn.refreshSpans)
+		if len(txn.refreshSpans) > maxTxnRefreshSpans {
+			txn.refreshSpans = nil
+			txn.refreshSpansOverflow = true
+		}
+	}
+}
+
+// maybeRefreshReads is called before committing. A SERIALIZABLE transaction
+// whose timestamp was pushed past its original timestamp, for instance by
+// writing keys read by other transactions, fails to commit with a
+// TransactionRetryError. However, if none of the spans it read were written
+// in the meantime, its reads are still valid at the pushed timestamp and it
+// can commit there instead of restarting. This is verified by refreshing the
+// reads with RefreshRange requests, after which the original timestamp is
+// moved forward. If the refresh fails, the original timestamp is left alone
+// and the commit returns the retryable error.
+//
+// Transactions which read too many spans to track them, and pushes of the
+// transaction record, which are only discovered by the commit itself, still
+// restart the transaction.
+func (txn *Txn) maybeRefreshReads() *roachpb.Error {
+	if txn.Proto.Isolation != enginepb.SERIALIZABLE || txn.Proto.WriteTooOld ||
+		txn.Proto.RetryOnPush || !txn.Proto.OrigTimestamp.Less(txn.Proto.Timestamp) {
+		return nil
+	}
+	if txn.refreshSpansOverflow {
+		log.Trace(txn.Context, "not refreshing reads: too many spans were read")
+		return nil
+	}
+	origTimestamp := txn.Proto.OrigTimestamp
+	txn.Proto.OrigTimestamp = txn.Proto.Timestamp
+	if len(txn.refreshSpans) == 0 {
+		return nil
+	}
+
+	roachpb.MergeSpans(&txn.refreshSpans)
+	var ba roachpb.BatchRequest
+	for _, span := range txn.refreshSpans {
+		ba.Add(&roachpb.RefreshRangeRequest{Span: span, Timestamp: origTimestamp})
+	}
+	if _, pErr := txn.db.send(ba); pErr != nil {
+		if pErr.TransactionRestart != roachpb.TransactionRestart_NONE {
+			return pErr
+		}
+		log.Trace(txn.Context, fmt.Sprintf("failed to refresh reads: %s", pErr))
+		txn.Proto.OrigTimestamp = origTimestamp
+		return nil
+	}
+	log.Trace(txn.Context, fmt.Sprintf("refreshed reads from %s to %s", origTimestamp,
+		txn.Proto.OrigTimestamp))
+	return nil
+}
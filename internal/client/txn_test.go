@@ -17,6 +17,7 @@
 package client
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -807,6 +808,32 @@ func TestWrongTxnRetry(t *testing.T) {
 	}
 }
 
+// TestForcedRetry verifies that a txn retries on the errors generated by
+// GenerateForcedRetryableError, and that it restarts at a new epoch.
+func TestForcedRetry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	db := NewDB(newTestSender(nil, nil))
+
+	var epochs []uint32
+	err := db.Txn(func(txn *Txn) error {
+		// Ensure the KV transaction is created.
+		if err := txn.Put("a", "b"); err != nil {
+			t.Fatal(err)
+		}
+		epochs = append(epochs, txn.Proto.Epoch)
+		if len(epochs) == 1 {
+			return txn.GenerateForcedRetryableError("forced")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(epochs, []uint32{0, 1}) {
+		t.Fatalf("expected attempts at epochs [0 1], got %v", epochs)
+	}
+}
+
 func TestBatchMixRawRequest(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	db := NewDB(newTestSender(nil, nil))
@@ -852,3 +879,93 @@ func TestUpdateDeadlineMaybe(t *testing.T) {
 		t.Errorf("unexpected deadline: %s", txn.deadline)
 	}
 }
+
+// TestTxnRefreshReads verifies that a transaction whose timestamp was pushed
+// refreshes the spans it read before committing, and commits at the pushed
+// timestamp only if the refresh succeeds. A transaction which read too many
+// spans to track them doesn't refresh them, and restarts.
+func TestTxnRefreshReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	pushedTS := hlc.Timestamp{WallTime: 10}
+	for _, tc := range []struct {
+		refreshErr, tooManyReads bool
+	}{
+		{false, false},
+		{true, false},
+		{false, true},
+	} {
+		var refreshed []roachpb.Span
+		var commitTS hlc.Timestamp
+		db := NewDB(newTestSender(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+			if _, ok := ba.GetArg(roachpb.RefreshRange); ok {
+				for _, union := range ba.Requests {
+					args := union.GetInner().(*roachpb.RefreshRangeRequest)
+					if !args.Timestamp.Equal(testTS) {
+						t.Errorf("expected refresh from %s; got %s", testTS, args.Timestamp)
+					}
+					refreshed = append(refreshed, args.Span)
+				}
+				if tc.refreshErr {
+					return nil, roachpb.NewErrorf("encountered recently written key")
+				}
+			}
+			if _, ok := ba.GetArg(roachpb.EndTransaction); ok {
+				commitTS = ba.Txn.OrigTimestamp
+			}
+			return ba.CreateReply(), nil
+		}, func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+			br := ba.CreateReply()
+			txnClone := ba.Txn.Clone()
+			br.Txn = &txnClone
+			// Writes are pushed, as if the keys had been read by other
+			// transactions at a later timestamp.
+			if _, ok := ba.GetArg(roachpb.Put); ok {
+				br.Txn.Writing = true
+				br.Txn.Timestamp.Forward(pushedTS)
+			}
+			if _, ok := ba.GetArg(roachpb.EndTransaction); ok {
+				br.Txn.Status = roachpb.COMMITTED
+			}
+			return br, nil
+		}))
+
+		if err := db.Txn(func(txn *Txn) error {
+			txn.Proto.OrigTimestamp = testTS
+			txn.Proto.Timestamp = testTS
+			if _, err := txn.Get("a"); err != nil {
+				return err
+			}
+			if _, err := txn.Scan("b", "c", 0); err != nil {
+				return err
+			}
+			if tc.tooManyReads {
+				for i := 0; i < maxTxnRefreshSpans; i++ {
+					if _, err := txn.Get(fmt.Sprintf("d%04d", i)); err != nil {
+						return err
+					}
+				}
+			}
+			return txn.Put("x", "y")
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		expRefreshed := []roachpb.Span{
+			{Key: roachpb.Key("a"), EndKey: roachpb.Key("a").Next()},
+			{Key: roachpb.Key("b"), EndKey: roachpb.Key("c")},
+		}
+		if tc.tooManyReads {
+			expRefreshed = nil
+		}
+		if !reflect.DeepEqual(refreshed, expRefreshed) {
+			t.Errorf("expected refreshed spans %s; got %s", expRefreshed, refreshed)
+		}
+		expCommitTS := pushedTS
+		if tc.refreshErr || tc.tooManyReads {
+			expCommitTS = testTS
+		}
+		if !commitTS.Equal(expCommitTS) {
+			t.Errorf("%+v: expected commit at %s; got %s", tc, expCommitTS, commitTS)
+		}
+	}
+}
@@ -180,6 +180,10 @@ var (
 	// StoreIDGenerator is the global store ID generator sequence.
 	StoreIDGenerator = roachpb.Key(makeKey(SystemPrefix, roachpb.RKey("store-idgen")))
 
+	// ProtectedTimestampPrefix is the key prefix for protected timestamp
+	// records, which prevent the garbage collection of MVCC history.
+	ProtectedTimestampPrefix = roachpb.Key(makeKey(SystemPrefix, roachpb.RKey("pts-")))
+
 	// StatusPrefix specifies the key prefix to store all status details.
 	StatusPrefix = roachpb.Key(makeKey(SystemPrefix, roachpb.RKey("status-")))
 	// StatusNodePrefix stores all status info for nodes.
@@ -237,8 +241,14 @@ const (
 	// Reserved IDs for other system tables. If you're adding a new system table,
 	// it probably belongs here.
 	// NOTE: IDs must be <= MaxReservedDescID.
-	LeaseTableID      = 11
-	EventLogTableID   = 12
-	RangeEventTableID = 13
-	UITableID         = 14
+	LeaseTableID            = 11
+	EventLogTableID         = 12
+	RangeEventTableID       = 13
+	UITableID               = 14
+	CommentsTableID         = 15
+	IndexUsageTableID       = 16
+	StatementStatsTableID   = 17
+	TransactionStatsTableID = 18
+	TableStatisticsTableID  = 19
+	SettingsTableID         = 20
 )
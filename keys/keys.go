@@ -52,6 +52,14 @@ func StoreGossipKey() roachpb.Key {
 	return MakeStoreKey(localStoreGossipSuffix, nil)
 }
 
+// ProtectedTimestampKey returns the key for the protected timestamp record
+// with the given ID.
+func ProtectedTimestampKey(id uuid.UUID) roachpb.Key {
+	key := make(roachpb.Key, 0, len(ProtectedTimestampPrefix)+len(id.GetBytes())+2)
+	key = append(key, ProtectedTimestampPrefix...)
+	return encoding.EncodeBytesAscending(key, id.GetBytes())
+}
+
 // NodeStatusKey returns the key for accessing the node status for the
 // specified node ID.
 func NodeStatusKey(nodeID int32) roachpb.Key {
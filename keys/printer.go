@@ -105,6 +105,10 @@ var (
 			}},
 		},
 		{name: "/System", start: SystemPrefix, end: SystemMax, entries: []dictEntry{
+			{name: "/ProtectedTimestamp", prefix: ProtectedTimestampPrefix,
+				ppFunc: decodeKeyPrint,
+				psFunc: parseUnsupported,
+			},
 			{name: "/StatusNode", prefix: StatusNodePrefix,
 				ppFunc: decodeKeyPrint,
 				psFunc: parseUnsupported,
@@ -115,7 +119,7 @@ var (
 			},
 		}},
 		{name: "/Table", start: TableDataMin, end: TableDataMax, entries: []dictEntry{
-			{name: "", prefix: nil, ppFunc: decodeKeyPrint,
+			{name: "", prefix: nil, ppFunc: decodeTableKey,
 				psFunc: parseUnsupported},
 		}},
 	}
@@ -417,6 +421,41 @@ func decodeKeyPrint(key roachpb.Key) string {
 	return encoding.PrettyPrintValue(key, "/")
 }
 
+// decodeTableKey prints a SQL key as /[tableid]/[indexid]/[values...]. The
+// table and index IDs are decoded as the uvarints they were encoded with;
+// everything after them is decoded as a sequence of key column values. Keys
+// which do not start with a table ID (for example, keys below the first table
+// prefix) are printed as a plain sequence of values.
+func decodeTableKey(key roachpb.Key) string {
+	rest, tableID, err := decodeTableKeyID(key)
+	if err != nil {
+		return decodeKeyPrint(key)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "/%d", tableID)
+	if len(rest) == 0 {
+		return buf.String()
+	}
+	indexRest, indexID, err := decodeTableKeyID(rest)
+	if err != nil {
+		buf.WriteString(decodeKeyPrint(rest))
+		return buf.String()
+	}
+	fmt.Fprintf(&buf, "/%d", indexID)
+	buf.WriteString(decodeKeyPrint(indexRest))
+	return buf.String()
+}
+
+// decodeTableKeyID decodes a table or index ID. Unlike DecodeTablePrefix it
+// rejects negative varints, which sort before the first table prefix and
+// would otherwise be decoded as very large IDs.
+func decodeTableKeyID(key roachpb.Key) ([]byte, uint64, error) {
+	if len(key) == 0 || key[0] < MakeTablePrefix(0)[0] {
+		return key, 0, errors.Errorf("invalid ID prefix: %q", []byte(key))
+	}
+	return DecodeTablePrefix(key)
+}
+
 func decodeTimeseriesKey(key roachpb.Key) string {
 	return PrettyPrintTimeseriesKey(key)
 }
@@ -483,7 +522,7 @@ func prettyPrintInternal(key roachpb.Key) (string, bool) {
 //		/StatusNode/[key]                           "\x04status-node-"+[key]
 // /System/Max                                    "\x05"
 //
-// /Table/[key]                                   [key]
+// /Table/[tableid]/[indexid]/[key]              [tableid]+[indexid]+[key]
 //
 // /Min                                           ""
 // /Max                                           "\xff\xff"
@@ -90,6 +90,16 @@ func TestPrettyPrint(t *testing.T) {
 		{UserTableDataMin, "/Table/50"},
 		{MakeTablePrefix(111), "/Table/111"},
 		{makeKey(MakeTablePrefix(42), roachpb.RKey("foo")), `/Table/42/"foo"`},
+		{makeKey(MakeTablePrefix(52),
+			roachpb.RKey(encoding.EncodeUvarintAscending(nil, 1)),
+			roachpb.RKey(encoding.EncodeStringAscending(nil, "foo")),
+			roachpb.RKey(MakeFamilyKey(nil, 0))),
+			`/Table/52/1/"foo"/0`},
+		{makeKey(MakeTablePrefix(1000),
+			roachpb.RKey(encoding.EncodeUvarintAscending(nil, 2)),
+			roachpb.RKey(encoding.EncodeVarintAscending(nil, -7)),
+			roachpb.RKey(encoding.EncodeNullAscending(nil))),
+			`/Table/1000/2/-7/NULL`},
 		{makeKey(MakeTablePrefix(42),
 			roachpb.RKey(encoding.EncodeFloatAscending(nil, float64(233.221112)))),
 			"/Table/42/233.221112"},
@@ -149,6 +159,11 @@ func TestPrettyPrint(t *testing.T) {
 		{Meta1KeyMax, "/Meta1/Max"},
 		{Meta2KeyMax, "/Meta2/Max"},
 		{makeKey(MakeTablePrefix(42), roachpb.RKey([]byte{0x12, 'a', 0x00, 0x02})), "/Table/42/<unknown escape sequence: 0x0 0x2>"},
+		{makeKey(MakeTablePrefix(52),
+			roachpb.RKey(encoding.EncodeUvarintAscending(nil, 1)),
+			roachpb.RKey([]byte{0x12, 'a', 0x00, 0x02}),
+			roachpb.RKey(encoding.EncodeVarintAscending(nil, 5))),
+			"/Table/52/1/<unknown escape sequence: 0x0 0x2>"},
 	}
 	for i, test := range testCases {
 		keyInfo := MassagePrettyPrintedSpanForTest(PrettyPrint(test.key), nil)
@@ -660,6 +660,9 @@ func (ds *DistSender) sendChunk(ctx context.Context, ba roachpb.BatchRequest) (*
 			}
 
 			if needAnother && br == nil {
+				if ba.IsUnsplittable() {
+					return nil, roachpb.NewErrorf("%s spans multiple ranges", ba), false
+				}
 				// TODO(tschottdorf): we should have a mechanism for discovering
 				// range merges (descriptor staleness will mostly go unnoticed),
 				// or we'll be turning single-range queries into multi-range
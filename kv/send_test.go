@@ -73,6 +73,10 @@ func (n Node) Reserve(_ context.Context, _ *roachpb.ReservationRequest) (*roachp
 	panic("unimplemented")
 }
 
+func (n Node) CollectChecksum(_ context.Context, _ *roachpb.CollectChecksumRequest) (*roachpb.CollectChecksumResponse, error) {
+	panic("unimplemented")
+}
+
 func TestInvalidAddrLength(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -73,14 +73,15 @@ func (r RangeIDSlice) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
 func (r RangeIDSlice) Less(i, j int) bool { return r[i] < r[j] }
 
 const (
-	isAdmin    = 1 << iota // admin cmds don't go through raft, but run on lease holder
-	isRead                 // read-only cmds don't go through raft, but may run on lease holder
-	isWrite                // write cmds go through raft and must be proposed on lease holder
-	isTxn                  // txn commands may be part of a transaction
-	isTxnWrite             // txn write cmds start heartbeat and are marked for intent resolution
-	isRange                // range commands may span multiple keys
-	isReverse              // reverse commands traverse ranges in descending direction
-	isAlone                // requests which must be alone in a batch
+	isAdmin        = 1 << iota // admin cmds don't go through raft, but run on lease holder
+	isRead                     // read-only cmds don't go through raft, but may run on lease holder
+	isWrite                    // write cmds go through raft and must be proposed on lease holder
+	isTxn                      // txn commands may be part of a transaction
+	isTxnWrite                 // txn write cmds start heartbeat and are marked for intent resolution
+	isRange                    // range commands may span multiple keys
+	isReverse                  // reverse commands traverse ranges in descending direction
+	isAlone                    // requests which must be alone in a batch
+	isUnsplittable             // range commands that must not be split across ranges
 )
 
 // GetTxnID returns the transaction ID if the header has a transaction
@@ -213,6 +214,7 @@ func (cc *CheckConsistencyResponse) combine(c combinable) error {
 		if err := cc.ResponseHeader.combine(otherCC.Header()); err != nil {
 			return err
 		}
+		cc.Results = append(cc.Results, otherCC.Results...)
 	}
 	return nil
 }
@@ -242,6 +244,20 @@ func (af *ChangeFrozenResponse) combine(c combinable) error {
 
 var _ combinable = &ChangeFrozenResponse{}
 
+// combine implements the combinable interface.
+func (er *ExportResponse) combine(c combinable) error {
+	otherER := c.(*ExportResponse)
+	if er != nil {
+		if err := er.ResponseHeader.combine(otherER.Header()); err != nil {
+			return err
+		}
+		er.Files = append(er.Files, otherER.Files...)
+	}
+	return nil
+}
+
+var _ combinable = &ExportResponse{}
+
 // Header implements the Request interface.
 func (rh Span) Header() Span {
 	return rh
@@ -420,6 +436,18 @@ func (*CheckConsistencyRequest) Method() Method { return CheckConsistency }
 // Method implements the Request interface.
 func (*ChangeFrozenRequest) Method() Method { return ChangeFrozen }
 
+// Method implements the Request interface.
+func (*ExportRequest) Method() Method { return Export }
+
+// Method implements the Request interface.
+func (*AddSSTableRequest) Method() Method { return AddSSTable }
+
+// Method implements the Request interface.
+func (*ClearRangeRequest) Method() Method { return ClearRange }
+
+// Method implements the Request interface.
+func (*RefreshRangeRequest) Method() Method { return RefreshRange }
+
 // Method implements the Request interface.
 func (*BeginTransactionRequest) Method() Method { return BeginTransaction }
 
@@ -639,6 +667,30 @@ func (vcr *VerifyChecksumRequest) ShallowCopy() Request {
 	return &shallowCopy
 }
 
+// ShallowCopy implements the Request interface.
+func (ekr *ExportRequest) ShallowCopy() Request {
+	shallowCopy := *ekr
+	return &shallowCopy
+}
+
+// ShallowCopy implements the Request interface.
+func (r *AddSSTableRequest) ShallowCopy() Request {
+	shallowCopy := *r
+	return &shallowCopy
+}
+
+// ShallowCopy implements the Request interface.
+func (crr *ClearRangeRequest) ShallowCopy() Request {
+	shallowCopy := *crr
+	return &shallowCopy
+}
+
+// ShallowCopy implements the Request interface.
+func (rrr *RefreshRangeRequest) ShallowCopy() Request {
+	shallowCopy := *rrr
+	return &shallowCopy
+}
+
 func (*GetRequest) createReply() Response                { return &GetResponse{} }
 func (*PutRequest) createReply() Response                { return &PutResponse{} }
 func (*ConditionalPutRequest) createReply() Response     { return &ConditionalPutResponse{} }
@@ -667,6 +719,10 @@ func (*RequestLeaseRequest) createReply() Response       { return &RequestLeaseR
 func (*TransferLeaseRequest) createReply() Response      { return &RequestLeaseResponse{} }
 func (*ComputeChecksumRequest) createReply() Response    { return &ComputeChecksumResponse{} }
 func (*VerifyChecksumRequest) createReply() Response     { return &VerifyChecksumResponse{} }
+func (*ExportRequest) createReply() Response             { return &ExportResponse{} }
+func (*AddSSTableRequest) createReply() Response         { return &AddSSTableResponse{} }
+func (*ClearRangeRequest) createReply() Response         { return &ClearRangeResponse{} }
+func (*RefreshRangeRequest) createReply() Response       { return &RefreshRangeResponse{} }
 
 // NewGet returns a Request initialized to get the value at key.
 func NewGet(key Key) Request {
@@ -844,3 +900,7 @@ func (*ComputeChecksumRequest) flags() int  { return isWrite }
 func (*VerifyChecksumRequest) flags() int   { return isWrite }
 func (*CheckConsistencyRequest) flags() int { return isAdmin | isRange }
 func (*ChangeFrozenRequest) flags() int     { return isWrite | isRange }
+func (*ExportRequest) flags() int           { return isRead | isRange }
+func (*AddSSTableRequest) flags() int       { return isWrite | isRange | isAlone | isUnsplittable }
+func (*ClearRangeRequest) flags() int       { return isWrite | isRange | isAlone }
+func (*RefreshRangeRequest) flags() int     { return isRead | isRange | isTxn }
@@ -111,6 +111,18 @@ func TestCombinable(t *testing.T) {
 	if !reflect.DeepEqual(cf1, wantedCF) {
 		t.Errorf("wanted %v, got %v", wantedCF, cf1)
 	}
+
+	ef1 := ExportResponse_File{Span: Span{Key: Key("a"), EndKey: Key("b")}, Path: "1.sst"}
+	ef2 := ExportResponse_File{Span: Span{Key: Key("b"), EndKey: Key("c")}, Path: "2.sst"}
+	er1 := &ExportResponse{Files: []ExportResponse_File{ef1}}
+	er2 := &ExportResponse{Files: []ExportResponse_File{ef2}}
+	wantedER := &ExportResponse{Files: []ExportResponse_File{ef1, ef2}}
+	if err := er1.combine(er2); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(er1, wantedER) {
+		t.Errorf("wanted %v, got %v", wantedER, er1)
+	}
 }
 
 // TestMustSetInner makes sure that calls to MustSetInner correctly reset the
@@ -83,6 +83,12 @@ func (ba *BatchRequest) IsReverse() bool {
 	return ba.hasFlag(isReverse)
 }
 
+// IsUnsplittable returns true iff the BatchRequest contains a range request
+// which must not be split across ranges.
+func (ba *BatchRequest) IsUnsplittable() bool {
+	return ba.hasFlag(isUnsplittable)
+}
+
 // IsPossibleTransaction returns true iff the BatchRequest contains
 // requests that can be part of a transaction.
 func (ba *BatchRequest) IsPossibleTransaction() bool {
@@ -241,6 +247,10 @@ func (ba *BatchRequest) CreateReply() *BatchResponse {
 		checkConsistency   int
 		noop               int
 		changeFrozen       int
+		export             int
+		addSSTable         int
+		clearRange         int
+		refreshRange       int
 	}
 	for _, union := range ba.Requests {
 		switch union.GetInner().(type) {
@@ -300,6 +310,14 @@ func (ba *BatchRequest) CreateReply() *BatchResponse {
 			counts.noop++
 		case *ChangeFrozenRequest:
 			counts.changeFrozen++
+		case *ExportRequest:
+			counts.export++
+		case *AddSSTableRequest:
+			counts.addSSTable++
+		case *ClearRangeRequest:
+			counts.clearRange++
+		case *RefreshRangeRequest:
+			counts.refreshRange++
 		default:
 			panic(fmt.Sprintf("unsupported type %T", union.GetInner()))
 		}
@@ -334,6 +352,10 @@ func (ba *BatchRequest) CreateReply() *BatchResponse {
 		checkConsistency   []CheckConsistencyResponse
 		noop               []NoopResponse
 		changeFrozen       []ChangeFrozenResponse
+		export             []ExportResponse
+		addSSTable         []AddSSTableResponse
+		clearRange         []ClearRangeResponse
+		refreshRange       []RefreshRangeResponse
 	}
 	for i, union := range ba.Requests {
 		var reply Response
@@ -478,6 +500,26 @@ func (ba *BatchRequest) CreateReply() *BatchResponse {
 				bufs.changeFrozen = make([]ChangeFrozenResponse, counts.changeFrozen)
 			}
 			reply, bufs.changeFrozen = &bufs.changeFrozen[0], bufs.changeFrozen[1:]
+		case *ExportRequest:
+			if bufs.export == nil {
+				bufs.export = make([]ExportResponse, counts.export)
+			}
+			reply, bufs.export = &bufs.export[0], bufs.export[1:]
+		case *AddSSTableRequest:
+			if bufs.addSSTable == nil {
+				bufs.addSSTable = make([]AddSSTableResponse, counts.addSSTable)
+			}
+			reply, bufs.addSSTable = &bufs.addSSTable[0], bufs.addSSTable[1:]
+		case *ClearRangeRequest:
+			if bufs.clearRange == nil {
+				bufs.clearRange = make([]ClearRangeResponse, counts.clearRange)
+			}
+			reply, bufs.clearRange = &bufs.clearRange[0], bufs.clearRange[1:]
+		case *RefreshRangeRequest:
+			if bufs.refreshRange == nil {
+				bufs.refreshRange = make([]RefreshRangeResponse, counts.refreshRange)
+			}
+			reply, bufs.refreshRange = &bufs.refreshRange[0], bufs.refreshRange[1:]
 		default:
 			panic(fmt.Sprintf("unsupported type %T", union.GetInner()))
 		}
@@ -49,6 +49,12 @@ func (e *RetryableTxnError) Error() string {
 
 var _ error = &RetryableTxnError{}
 
+// NewRetryableTxnError creates a RetryableTxnError for the transaction with
+// the given ID, which must already be prepared for its next attempt.
+func NewRetryableTxnError(msg string, txnID *uuid.UUID) *RetryableTxnError {
+	return &RetryableTxnError{message: msg, TxnID: txnID}
+}
+
 // ResponseWithError is a tuple of a BatchResponse and an error. It is used to
 // pass around a BatchResponse with its associated error where that
 // entanglement is necessary (e.g. channels, methods that need to return
@@ -120,4 +120,17 @@ const (
 	// ChangeFrozen freezes or unfreezes all Ranges with StartKey in a given
 	// key span.
 	ChangeFrozen
+	// Export writes the MVCC data in a key span to SSTable-formatted files in
+	// external storage.
+	Export
+	// AddSSTable links an SSTable into the storage engine of each replica of
+	// a single range.
+	AddSSTable
+	// ClearRange removes all of the data in a key span using storage engine
+	// range tombstones.
+	ClearRange
+	// RefreshRange verifies that no values were written in a key span since
+	// a transaction read it, allowing the transaction to move its timestamp
+	// forward without restarting.
+	RefreshRange
 )
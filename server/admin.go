@@ -654,6 +654,18 @@ func (s *adminServer) Health(ctx context.Context, req *serverpb.HealthRequest) (
 	return &serverpb.HealthResponse{}, nil
 }
 
+// Bootstrap implements the serverpb.AdminServer interface.
+func (s *adminServer) Bootstrap(
+	ctx context.Context, req *serverpb.BootstrapRequest,
+) (*serverpb.BootstrapResponse, error) {
+	if err := s.server.node.requestBootstrap(ctx); err == errClusterInitialized {
+		return nil, grpc.Errorf(codes.FailedPrecondition, "%s", err)
+	} else if err != nil {
+		return nil, err
+	}
+	return &serverpb.BootstrapResponse{}, nil
+}
+
 func (s *adminServer) Drain(req *serverpb.DrainRequest, stream serverpb.Admin_DrainServer) error {
 	on := make([]serverpb.DrainMode, len(req.On))
 	for i := range req.On {
@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/base"
 	"github.com/cockroachdb/cockroach/gossip/resolver"
 	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql"
 	"github.com/cockroachdb/cockroach/storage/engine"
 	"github.com/cockroachdb/cockroach/util/envutil"
 	"github.com/cockroachdb/cockroach/util/humanizeutil"
@@ -58,6 +59,10 @@ const (
 	defaultTimeUntilStoreDead       = 5 * time.Minute
 	defaultStorePath                = "cockroach-data"
 	defaultReservationsEnabled      = true
+	defaultEventLogRetention        = 90 * 24 * time.Hour
+	defaultStatementStatsRetention  = 7 * 24 * time.Hour
+	defaultMergeQueueEnabled        = false
+	defaultLoadSplitQPSThreshold    = 2500
 
 	minimumNetworkFileDescriptors     = 256
 	recommendedNetworkFileDescriptors = 5000
@@ -156,6 +161,32 @@ type Context struct {
 	// reservation system.
 	ReservationsEnabled bool
 
+	// LoadSplitQPSThreshold is the rate of requests per second above which a
+	// range is split to divide its load. Disabled if <= 0.
+	// Environment Variable: COCKROACH_LOAD_SPLIT_QPS_THRESHOLD
+	LoadSplitQPSThreshold int
+
+	// MergeQueueEnabled is a switch used to enable the merging of under-sized
+	// ranges into their right hand neighbor.
+	// Environment Variable: COCKROACH_MERGE_QUEUE_ENABLED
+	MergeQueueEnabled bool
+
+	// EventLogRetention is the duration for which entries in the system event
+	// log are kept before being garbage collected. Disabled if <= 0.
+	// Environment Variable: COCKROACH_EVENT_LOG_RETENTION
+	EventLogRetention time.Duration
+
+	// StatementStatsRetention is the duration for which the hourly statement
+	// and transaction statistics are kept in the system tables before being
+	// garbage collected. Disabled if <= 0.
+	// Environment Variable: COCKROACH_STATEMENT_STATS_RETENTION
+	StatementStatsRetention time.Duration
+
+	// PGServerVersion is the PostgreSQL version reported to clients as
+	// server_version, for tools which refuse to connect to older servers.
+	// Environment Variable: COCKROACH_PG_SERVER_VERSION
+	PGServerVersion string
+
 	// TestingKnobs is used for internal test controls only.
 	TestingKnobs base.TestingKnobs
 }
@@ -324,6 +355,11 @@ func MakeContext() Context {
 		MetricsSampleInterval:    defaultMetricsSampleInterval,
 		TimeUntilStoreDead:       defaultTimeUntilStoreDead,
 		ReservationsEnabled:      defaultReservationsEnabled,
+		EventLogRetention:        defaultEventLogRetention,
+		StatementStatsRetention:  defaultStatementStatsRetention,
+		MergeQueueEnabled:        defaultMergeQueueEnabled,
+		LoadSplitQPSThreshold:    defaultLoadSplitQPSThreshold,
+		PGServerVersion:          sql.DefaultPGServerVersion,
 		Stores: StoreSpecList{
 			Specs: []StoreSpec{{Path: defaultStorePath}},
 		},
@@ -403,6 +439,10 @@ func (ctx *Context) InitStores(stopper *stop.Stopper) error {
 func (ctx *Context) InitNode() error {
 	ctx.readEnvironmentVariables()
 
+	if _, err := sql.PGServerVersionNum(ctx.PGServerVersion); err != nil {
+		return err
+	}
+
 	// Initialize attributes.
 	ctx.NodeAttributes = parseAttributes(ctx.Attrs)
 
@@ -431,6 +471,13 @@ func (ctx *Context) readEnvironmentVariables() {
 	ctx.ScanMaxIdleTime = envutil.EnvOrDefaultDuration("scan_max_idle_time", ctx.ScanMaxIdleTime)
 	ctx.TimeUntilStoreDead = envutil.EnvOrDefaultDuration("time_until_store_dead", ctx.TimeUntilStoreDead)
 	ctx.ConsistencyCheckInterval = envutil.EnvOrDefaultDuration("consistency_check_interval", ctx.ConsistencyCheckInterval)
+	// The merge queue is disabled by default until merges have seen more
+	// testing.
+	ctx.MergeQueueEnabled = envutil.EnvOrDefaultBool("merge_queue_enabled", ctx.MergeQueueEnabled)
+	ctx.LoadSplitQPSThreshold = envutil.EnvOrDefaultInt("load_split_qps_threshold", ctx.LoadSplitQPSThreshold)
+	ctx.EventLogRetention = envutil.EnvOrDefaultDuration("event_log_retention", ctx.EventLogRetention)
+	ctx.StatementStatsRetention = envutil.EnvOrDefaultDuration("statement_stats_retention", ctx.StatementStatsRetention)
+	ctx.PGServerVersion = envutil.EnvOrDefaultString("pg_server_version", ctx.PGServerVersion)
 	// TODO(bram): remove ReservationsEnabled once we've completed testing the
 	// feature.
 	ctx.ReservationsEnabled = envutil.EnvOrDefaultBool("reservations_enabled", ctx.ReservationsEnabled)
@@ -117,6 +117,21 @@ func TestReadEnvironmentVariables(t *testing.T) {
 		if err := os.Unsetenv("COCKROACH_RESERVATIONS_ENABLED"); err != nil {
 			t.Fatal(err)
 		}
+		if err := os.Unsetenv("COCKROACH_EVENT_LOG_RETENTION"); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Unsetenv("COCKROACH_STATEMENT_STATS_RETENTION"); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Unsetenv("COCKROACH_MERGE_QUEUE_ENABLED"); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Unsetenv("COCKROACH_LOAD_SPLIT_QPS_THRESHOLD"); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Unsetenv("COCKROACH_PG_SERVER_VERSION"); err != nil {
+			t.Fatal(err)
+		}
 	}
 	defer resetEnvVar()
 
@@ -172,6 +187,26 @@ func TestReadEnvironmentVariables(t *testing.T) {
 		t.Fatal(err)
 	}
 	ctxExpected.ReservationsEnabled = false
+	if err := os.Setenv("COCKROACH_EVENT_LOG_RETENTION", "240h"); err != nil {
+		t.Fatal(err)
+	}
+	ctxExpected.EventLogRetention = time.Hour * 240
+	if err := os.Setenv("COCKROACH_STATEMENT_STATS_RETENTION", "48h"); err != nil {
+		t.Fatal(err)
+	}
+	ctxExpected.StatementStatsRetention = time.Hour * 48
+	if err := os.Setenv("COCKROACH_MERGE_QUEUE_ENABLED", "true"); err != nil {
+		t.Fatal(err)
+	}
+	ctxExpected.MergeQueueEnabled = true
+	if err := os.Setenv("COCKROACH_LOAD_SPLIT_QPS_THRESHOLD", "100"); err != nil {
+		t.Fatal(err)
+	}
+	ctxExpected.LoadSplitQPSThreshold = 100
+	if err := os.Setenv("COCKROACH_PG_SERVER_VERSION", "9.6.1"); err != nil {
+		t.Fatal(err)
+	}
+	ctxExpected.PGServerVersion = "9.6.1"
 
 	envutil.ClearEnvCache()
 	ctx.readEnvironmentVariables()
@@ -214,6 +249,18 @@ func TestReadEnvironmentVariables(t *testing.T) {
 	if err := os.Setenv("COCKROACH_RESERVATIONS_ENABLED", "abcd"); err != nil {
 		t.Fatal(err)
 	}
+	if err := os.Setenv("COCKROACH_EVENT_LOG_RETENTION", "abcd"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("COCKROACH_STATEMENT_STATS_RETENTION", "abcd"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("COCKROACH_MERGE_QUEUE_ENABLED", "abcd"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("COCKROACH_LOAD_SPLIT_QPS_THRESHOLD", "abcd"); err != nil {
+		t.Fatal(err)
+	}
 
 	envutil.ClearEnvCache()
 	ctx.readEnvironmentVariables()
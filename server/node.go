@@ -75,6 +75,14 @@ var errNeedsBootstrap = errors.New("node has no initialized stores and no instru
 // progress in this state.
 var errCannotJoinSelf = errors.New("an uninitialized node cannot specify its own address to join a cluster")
 
+// errClusterInitialized indicates that a bootstrap was requested from a node
+// which is already part of a cluster.
+var errClusterInitialized = errors.New("cluster has already been initialized")
+
+// errStoppedWaitingToJoin indicates that the node was stopped while it was
+// waiting to join a cluster.
+var errStoppedWaitingToJoin = errors.New("node stopped while waiting to join a cluster")
+
 type nodeMetrics struct {
 	registry *metric.Registry
 	latency  metric.Histograms
@@ -125,6 +133,14 @@ type Node struct {
 	startedAt   int64
 	initialBoot bool // True if this is the first time this node has started.
 	txnMetrics  *kv.TxnMetrics
+
+	// bootstrapC receives requests to bootstrap a new cluster while the node
+	// waits to join one. Each request carries the channel on which the
+	// outcome of the bootstrap is reported.
+	bootstrapC chan chan<- error
+	// bootstrapErrC, if set, is the channel of the bootstrap request being
+	// served by this node's start.
+	bootstrapErrC chan<- error
 }
 
 // allocateNodeID increments the node id generator key to allocate
@@ -154,6 +170,10 @@ func GetBootstrapSchema() sqlbase.MetadataSchema {
 	schema := sqlbase.MakeMetadataSchema()
 	AddEventLogToMetadataSchema(&schema)
 	sql.AddEventLogToMetadataSchema(&schema)
+	sql.AddIndexUsageToMetadataSchema(&schema)
+	sql.AddStatementStatsToMetadataSchema(&schema)
+	sql.AddTableStatisticsToMetadataSchema(&schema)
+	sql.AddSettingsToMetadataSchema(&schema)
 	return schema
 }
 
@@ -248,6 +268,7 @@ func NewNode(
 		stores:      storage.NewStores(ctx.Clock),
 		txnMetrics:  txnMetrics,
 		eventLogger: eventLogger,
+		bootstrapC:  make(chan chan<- error),
 	}
 	n.recorder.AddNodeRegistry("exec.%s", n.metrics.registry)
 	return n
@@ -312,27 +333,18 @@ func (n *Node) start(addr net.Addr, engines []engine.Engine, attrs roachpb.Attri
 
 	// Initialize stores, including bootstrapping new ones.
 	if err := n.initStores(engines, n.stopper); err != nil {
-		if err == errNeedsBootstrap {
-			n.initialBoot = true
-			// This node has no initialized stores and no way to connect to
-			// an existing cluster, so we bootstrap it.
-			clusterID, err := bootstrapCluster(engines, n.txnMetrics)
-			if err != nil {
-				return err
-			}
-			log.Infof("**** cluster %s has been created", clusterID)
-			log.Infof("**** add additional nodes by specifying --join=%s", addr)
-			// Make sure we add the node as a resolver.
-			selfResolver, err := resolver.NewResolverFromAddress(addr)
-			if err != nil {
-				return err
-			}
-			n.ctx.Gossip.SetResolvers([]resolver.Resolver{selfResolver})
-			// After bootstrapping, try again to initialize the stores.
-			if err := n.initStores(engines, n.stopper); err != nil {
-				return err
-			}
-		} else {
+		if err != errNeedsBootstrap {
+			return err
+		}
+		// This node has no initialized stores and either no way to connect
+		// to an existing cluster or an explicit request to create one, so we
+		// bootstrap it.
+		err = n.bootstrap(addr, engines)
+		if n.bootstrapErrC != nil {
+			n.bootstrapErrC <- err
+			n.bootstrapErrC = nil
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -352,6 +364,74 @@ func (n *Node) start(addr net.Addr, engines []engine.Engine, attrs roachpb.Attri
 	return nil
 }
 
+// bootstrap creates a new cluster using the given engines and initializes
+// the node's stores from them.
+func (n *Node) bootstrap(addr net.Addr, engines []engine.Engine) error {
+	n.initialBoot = true
+	clusterID, err := bootstrapCluster(engines, n.txnMetrics)
+	if err != nil {
+		return err
+	}
+	log.Infof("**** cluster %s has been created", clusterID)
+	log.Infof("**** add additional nodes by specifying --join=%s", addr)
+	// Make sure we add the node as a resolver if it has no other way of
+	// reaching the gossip network.
+	if len(n.ctx.Gossip.GetResolvers()) == 0 {
+		selfResolver, err := resolver.NewResolverFromAddress(addr)
+		if err != nil {
+			return err
+		}
+		n.ctx.Gossip.SetResolvers([]resolver.Resolver{selfResolver})
+	}
+	// After bootstrapping, try again to initialize the stores.
+	return n.initStores(engines, n.stopper)
+}
+
+// waitForJoinOrBootstrap blocks until the gossip network has connected the
+// node to an initialized cluster, in which case it returns nil, or until a
+// request to bootstrap a new cluster arrives, in which case it returns the
+// channel on which the outcome of the bootstrap is to be reported. It returns
+// errStoppedWaitingToJoin if the node is stopped in the meantime.
+func (n *Node) waitForJoinOrBootstrap(stopper *stop.Stopper) (chan<- error, error) {
+	log.Infof("waiting to join an existing cluster or for a new one to be created with \"cockroach init\"")
+	select {
+	case <-n.ctx.Gossip.Connected:
+		return nil, nil
+	case errC := <-n.bootstrapC:
+		// Both could have been ready at once; never bootstrap a second
+		// cluster once we've heard from an existing one.
+		select {
+		case <-n.ctx.Gossip.Connected:
+			errC <- errClusterInitialized
+			return nil, nil
+		default:
+			return errC, nil
+		}
+	case <-stopper.ShouldStop():
+		return nil, errStoppedWaitingToJoin
+	}
+}
+
+// requestBootstrap asks the node, which must be waiting to join a cluster,
+// to bootstrap a new cluster instead, and waits for the outcome. It returns
+// errClusterInitialized if the node is already part of a cluster.
+func (n *Node) requestBootstrap(ctx context.Context) error {
+	errC := make(chan error, 1)
+	select {
+	case n.bootstrapC <- errC:
+	case <-n.ctx.Gossip.Connected:
+		return errClusterInitialized
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-errC:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // IsDraining returns true if at least one Store housed on this Node is not
 // currently allowing range leases to be procured or extended.
 func (n *Node) IsDraining() bool {
@@ -424,6 +504,16 @@ func (n *Node) initStores(engines []engine.Engine, stopper *stop.Stopper) error
 				return errCannotJoinSelf
 			}
 		}
+		// The node was told how to join an existing cluster. Wait until it
+		// has done so, or until it is asked to create a new cluster instead.
+		errC, err := n.waitForJoinOrBootstrap(stopper)
+		if err != nil {
+			return err
+		}
+		if errC != nil {
+			n.bootstrapErrC = errC
+			return errNeedsBootstrap
+		}
 	}
 
 	// Verify all initialized stores agree on cluster and node IDs.
@@ -848,3 +938,17 @@ func (n *Node) Reserve(
 		})
 	return resp, err
 }
+
+// CollectChecksum implements the roachpb.InternalServer interface.
+func (n *Node) CollectChecksum(
+	ctx context.Context, req *roachpb.CollectChecksumRequest,
+) (*roachpb.CollectChecksumResponse, error) {
+	resp := &roachpb.CollectChecksumResponse{}
+	err := n.execStoreCommand(req.StoreRequestHeader,
+		func(s *storage.Store) error {
+			var err error
+			resp.Checksum, err = s.CollectChecksum(ctx, req.RangeID, req.ChecksumID)
+			return err
+		})
+	return resp, err
+}
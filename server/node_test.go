@@ -284,6 +284,62 @@ func TestNodeJoinSelf(t *testing.T) {
 	}
 }
 
+// TestNodeInit verifies that an uninitialized node started with a join
+// address waits until it is asked to bootstrap a new cluster, that other
+// nodes can then join it, and that the cluster cannot be bootstrapped twice.
+func TestNodeInit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Join an address at which no node is running.
+	ln, err := net.Listen("tcp", util.TestAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	engineStopper := stop.NewStopper()
+	defer engineStopper.Stop()
+	engines1 := []engine.Engine{engine.NewInMem(roachpb.Attributes{}, 1<<20, engineStopper)}
+	_, server1Addr, _, node1, stopper1 := createTestNode(util.TestAddr, engines1, ln.Addr(), t)
+	defer stopper1.Stop()
+	startErrC := make(chan error, 1)
+	go func() {
+		startErrC <- node1.start(server1Addr, engines1, roachpb.Attributes{})
+	}()
+
+	select {
+	case err := <-startErrC:
+		t.Fatalf("node started without being initialized: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := node1.requestBootstrap(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-startErrC; err != nil {
+		t.Fatal(err)
+	}
+	if sc := node1.stores.GetStoreCount(); sc != 1 {
+		t.Fatalf("GetStoreCount() expected 1; got %d", sc)
+	}
+
+	engines2 := []engine.Engine{engine.NewInMem(roachpb.Attributes{}, 1<<20, engineStopper)}
+	_, _, node2, stopper2 := createAndStartTestNode(util.TestAddr, engines2, server1Addr, t)
+	defer stopper2.Stop()
+	util.SucceedsSoon(t, func() error {
+		if sc := node2.stores.GetStoreCount(); sc != 1 {
+			return errors.Errorf("GetStoreCount() expected 1; got %d", sc)
+		}
+		return nil
+	})
+
+	for _, n := range []*Node{node1, node2} {
+		if err := n.requestBootstrap(context.Background()); err != errClusterInitialized {
+			t.Errorf("%s: expected err %s; got %v", n, errClusterInitialized, err)
+		}
+	}
+}
+
 // TestCorruptedClusterID verifies that a node fails to start when a
 // store's cluster ID is empty.
 func TestCorruptedClusterID(t *testing.T) {
@@ -24,6 +24,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"sync"
@@ -99,6 +100,7 @@ type Server struct {
 	stopper       *stop.Stopper
 	sqlExecutor   *sql.Executor
 	leaseMgr      *sql.LeaseManager
+	indexUsage    *sql.IndexUsageStats
 }
 
 // NewServer creates a Server from a server.Context.
@@ -190,12 +192,28 @@ func NewServer(ctx Context, stopper *stop.Stopper) (*Server, error) {
 	distsql.RegisterDistSQLServer(s.grpc, s.distSQLServer)
 
 	// Set up Executor
+	s.indexUsage = sql.NewIndexUsageStats()
 	eCtx := sql.ExecutorContext{
 		DB:           s.db,
 		Gossip:       s.gossip,
 		LeaseManager: s.leaseMgr,
 		Clock:        s.clock,
 		DistSQLSrv:   s.distSQLServer,
+
+		PGServerVersion: ctx.PGServerVersion,
+		IndexUsage:      s.indexUsage,
+		// The status server is created below, before the executor is used.
+		SpanStats: func(span roachpb.RSpan) (sql.SpanStats, error) {
+			stats, replicas, err := s.status.clusterSpanStats(context.TODO(), span)
+			if err != nil {
+				return sql.SpanStats{}, err
+			}
+			ranges, err := s.distSender.CountRanges(span)
+			if err != nil {
+				return sql.SpanStats{}, err
+			}
+			return sql.SpanStats{Stats: stats, Replicas: replicas, Ranges: int(ranges)}, nil
+		},
 	}
 	if ctx.TestingKnobs.SQLExecutor != nil {
 		eCtx.TestingKnobs = ctx.TestingKnobs.SQLExecutor.(*sql.ExecutorTestingKnobs)
@@ -219,8 +237,10 @@ func NewServer(ctx Context, stopper *stop.Stopper) (*Server, error) {
 		ScanMaxIdleTime:                s.ctx.ScanMaxIdleTime,
 		ConsistencyCheckInterval:       s.ctx.ConsistencyCheckInterval,
 		ConsistencyCheckPanicOnFailure: s.ctx.ConsistencyCheckPanicOnFailure,
-		Tracer:    s.Tracer,
-		StorePool: s.storePool,
+		MergeQueueEnabled:              s.ctx.MergeQueueEnabled,
+		LoadSplitQPSThreshold:          s.ctx.LoadSplitQPSThreshold,
+		Tracer:                         s.Tracer,
+		StorePool:                      s.storePool,
 		SQLExecutor: sql.InternalExecutor{
 			LeaseManager: s.leaseMgr,
 		},
@@ -341,6 +361,72 @@ func (s *Server) Start() error {
 		}
 	})
 
+	s.gossip.Start(s.grpc, unresolvedAddr)
+
+	// Serve the RPCs before starting the node: a node started with --join and
+	// no initialized stores waits in start until it has joined a cluster or
+	// until it receives a Bootstrap RPC. The postgres connections accepted by
+	// the listener wait until pgL is served, once the node has started.
+	s.stopper.RunWorker(func() {
+		netutil.FatalIfUnexpected(s.grpc.Serve(anyL))
+	})
+
+	s.stopper.RunWorker(func() {
+		netutil.FatalIfUnexpected(m.Serve())
+	})
+
+	if err := s.node.start(unresolvedAddr, s.ctx.Engines, s.ctx.NodeAttributes); err != nil {
+		return err
+	}
+
+	// Bring the system schema of clusters bootstrapped by earlier versions up
+	// to date.
+	if err := sql.RunMigrations(s.leaseMgr); err != nil {
+		return err
+	}
+
+	// Begin recording runtime statistics.
+	s.startSampleEnvironment(s.ctx.MetricsSampleInterval)
+
+	// Begin recording time series data collected by the status monitor.
+	s.tsDB.PollSource(s.recorder, s.ctx.MetricsSampleInterval, ts.Resolution10s, s.stopper)
+
+	// Begin recording status summaries.
+	s.node.startWriteSummaries(s.ctx.MetricsSampleInterval)
+
+	s.sqlExecutor.SetNodeID(s.node.Descriptor.NodeID)
+
+	// Drop the temporary tables left behind by the sessions of the node's
+	// previous process.
+	s.stopper.RunWorker(func() {
+		if err := s.sqlExecutor.DropOrphanedTempDatabases(); err != nil {
+			log.Warningf("unable to drop orphaned temporary databases: %s", err)
+		}
+	})
+
+	// Create and start the schema change manager only after a NodeID
+	// has been assigned.
+	testingKnobs := new(sql.SchemaChangeManagerTestingKnobs)
+	if s.ctx.TestingKnobs.SQLSchemaChangeManager != nil {
+		testingKnobs = s.ctx.TestingKnobs.SQLSchemaChangeManager.(*sql.SchemaChangeManagerTestingKnobs)
+	}
+	sql.NewSchemaChangeManager(testingKnobs, *s.db, s.gossip, s.leaseMgr).Start(s.stopper)
+
+	// Periodically delete events which have exceeded the event log retention.
+	sql.MakeEventLogger(s.leaseMgr).StartGC(*s.db, s.ctx.EventLogRetention, s.stopper)
+
+	// Periodically persist the index usage statistics.
+	s.indexUsage.Start(*s.db, s.leaseMgr, s.stopper)
+
+	// Periodically persist the statement and transaction statistics.
+	s.sqlExecutor.StartStatementStatsFlusher(s.ctx.StatementStatsRetention, s.stopper)
+
+	log.Infof("starting %s server at %s", s.ctx.HTTPRequestScheme(), unresolvedHTTPAddr)
+	log.Infof("starting grpc/postgres server at %s", unresolvedAddr)
+	if len(s.ctx.SocketFile) != 0 {
+		log.Infof("starting postgres server at unix:%s", s.ctx.SocketFile)
+	}
+
 	if tlsConfig != nil {
 		httpMux := cmux.New(httpLn)
 		clearL := httpMux.Match(cmux.HTTP1())
@@ -361,10 +447,6 @@ func (s *Server) Start() error {
 		netutil.FatalIfUnexpected(httpServer.Serve(httpLn))
 	})
 
-	s.stopper.RunWorker(func() {
-		netutil.FatalIfUnexpected(s.grpc.Serve(anyL))
-	})
-
 	s.stopper.RunWorker(func() {
 		netutil.FatalIfUnexpected(httpServer.ServeWith(s.stopper, pgL, func(conn net.Conn) {
 			if err := s.pgServer.ServeConn(conn); err != nil && !netutil.IsClosedConnection(err) {
@@ -397,41 +479,6 @@ func (s *Server) Start() error {
 		})
 	}
 
-	s.gossip.Start(s.grpc, unresolvedAddr)
-
-	if err := s.node.start(unresolvedAddr, s.ctx.Engines, s.ctx.NodeAttributes); err != nil {
-		return err
-	}
-
-	// Begin recording runtime statistics.
-	s.startSampleEnvironment(s.ctx.MetricsSampleInterval)
-
-	// Begin recording time series data collected by the status monitor.
-	s.tsDB.PollSource(s.recorder, s.ctx.MetricsSampleInterval, ts.Resolution10s, s.stopper)
-
-	// Begin recording status summaries.
-	s.node.startWriteSummaries(s.ctx.MetricsSampleInterval)
-
-	s.sqlExecutor.SetNodeID(s.node.Descriptor.NodeID)
-
-	// Create and start the schema change manager only after a NodeID
-	// has been assigned.
-	testingKnobs := new(sql.SchemaChangeManagerTestingKnobs)
-	if s.ctx.TestingKnobs.SQLSchemaChangeManager != nil {
-		testingKnobs = s.ctx.TestingKnobs.SQLSchemaChangeManager.(*sql.SchemaChangeManagerTestingKnobs)
-	}
-	sql.NewSchemaChangeManager(testingKnobs, *s.db, s.gossip, s.leaseMgr).Start(s.stopper)
-
-	log.Infof("starting %s server at %s", s.ctx.HTTPRequestScheme(), unresolvedHTTPAddr)
-	log.Infof("starting grpc/postgres server at %s", unresolvedAddr)
-	if len(s.ctx.SocketFile) != 0 {
-		log.Infof("starting postgres server at unix:%s", s.ctx.SocketFile)
-	}
-
-	s.stopper.RunWorker(func() {
-		netutil.FatalIfUnexpected(m.Serve())
-	})
-
 	// Initialize grpc-gateway mux and context.
 	jsonpb := &util.JSONPb{
 		EnumsAsInts:  true,
@@ -589,6 +636,19 @@ func (s *Server) Stop() {
 	s.stopper.Stop()
 }
 
+// AdminURL returns the URL of the admin UI. After Start, it reflects the
+// port which was bound if an ephemeral HTTP port was requested.
+func (s *Server) AdminURL() string {
+	return s.ctx.AdminURL()
+}
+
+// PGURL returns the URL at which SQL clients can connect as the given user.
+// After Start, it reflects the port which was bound if an ephemeral port was
+// requested.
+func (s *Server) PGURL(user string) (*url.URL, error) {
+	return s.ctx.PGURL(user)
+}
+
 // ServeHTTP is necessary to implement the http.Handler interface.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// This is our base handler, so catch all panics and make sure they stick.
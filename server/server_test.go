@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
+	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/base"
 	"github.com/cockroachdb/cockroach/config"
@@ -36,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/keys"
 	"github.com/cockroachdb/cockroach/kv"
 	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/rpc"
 	"github.com/cockroachdb/cockroach/server/serverpb"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/testutils"
@@ -44,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 	"github.com/cockroachdb/cockroach/util/metric"
+	"github.com/cockroachdb/cockroach/util/stop"
 	"github.com/cockroachdb/cockroach/util/tracing"
 )
 
@@ -60,6 +63,117 @@ func TestSelfBootstrap(t *testing.T) {
 	defer s.Stopper().Stop()
 }
 
+// TestInitOverRPC verifies that a server started with a join address and no
+// initialized stores serves RPCs while it waits to join a cluster, so that it
+// can be asked to bootstrap a new cluster through the Bootstrap RPC.
+func TestInitOverRPC(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Join an address at which no node is running.
+	joinLn, err := net.Listen("tcp", util.TestAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = joinLn.Close() }()
+
+	// Pick the address of the server ahead of time, since it's only known to
+	// the server once Start, which blocks, has been called.
+	ln, err := net.Listen("tcp", util.TestAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	if err := ln.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop()
+	ctx := makeTestContext()
+	ctx.Addr = addr
+	ctx.JoinList = []string{joinLn.Addr().String()}
+	s := &TestServer{Ctx: &ctx}
+	startErrC := make(chan error, 1)
+	go func() {
+		startErrC <- s.Start(base.TestServerArgs{Stopper: stopper})
+	}()
+
+	rpcContext := rpc.NewContext(nodeTestBaseContext, hlc.NewClock(hlc.UnixNano), stopper)
+	conn, err := rpcContext.GRPCDial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := serverpb.NewAdminClient(conn)
+	util.SucceedsSoon(t, func() error {
+		select {
+		case err := <-startErrC:
+			t.Fatalf("server started without being initialized: %v", err)
+		default:
+		}
+		_, err := c.Bootstrap(context.Background(), &serverpb.BootstrapRequest{})
+		return err
+	})
+	if err := <-startErrC; err != nil {
+		t.Fatal(err)
+	}
+
+	// The cluster can't be bootstrapped twice.
+	if _, err := c.Bootstrap(context.Background(), &serverpb.BootstrapRequest{}); !testutils.IsError(err, errClusterInitialized.Error()) {
+		t.Fatalf("expected %q error, got %v", errClusterInitialized, err)
+	}
+}
+
+// TestStopWhileWaitingToJoin verifies that a server waiting to join a cluster
+// stops waiting when it is stopped.
+func TestStopWhileWaitingToJoin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Join an address at which no node is running.
+	joinLn, err := net.Listen("tcp", util.TestAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = joinLn.Close() }()
+
+	ln, err := net.Listen("tcp", util.TestAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	if err := ln.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	stopper := stop.NewStopper()
+	ctx := makeTestContext()
+	ctx.Addr = addr
+	ctx.JoinList = []string{joinLn.Addr().String()}
+	s := &TestServer{Ctx: &ctx}
+	startErrC := make(chan error, 1)
+	go func() {
+		startErrC <- s.Start(base.TestServerArgs{Stopper: stopper})
+	}()
+
+	// Wait until the server serves RPCs, i.e. until it waits to join.
+	clientStopper := stop.NewStopper()
+	defer clientStopper.Stop()
+	rpcContext := rpc.NewContext(nodeTestBaseContext, hlc.NewClock(hlc.UnixNano), clientStopper)
+	conn, err := rpcContext.GRPCDial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := serverpb.NewAdminClient(conn)
+	util.SucceedsSoon(t, func() error {
+		_, err := c.Health(context.Background(), &serverpb.HealthRequest{})
+		return err
+	})
+
+	stopper.Stop()
+	if err := <-startErrC; err != errStoppedWaitingToJoin {
+		t.Fatalf("expected %q error, got %v", errStoppedWaitingToJoin, err)
+	}
+}
+
 // TestHealth verifies that health endpoint returns an empty JSON response.
 func TestHealth(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/server/serverpb"
 	"github.com/cockroachdb/cockroach/server/status"
 	"github.com/cockroachdb/cockroach/storage"
+	"github.com/cockroachdb/cockroach/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/util"
 	"github.com/cockroachdb/cockroach/util/log"
 	"github.com/cockroachdb/cockroach/util/timeutil"
@@ -716,7 +717,10 @@ func (s *statusServer) SpanStats(ctx context.Context, req *serverpb.SpanStatsReq
 
 	output := &serverpb.SpanStatsResponse{}
 	err = s.stores.VisitStores(func(store *storage.Store) error {
-		stats, count := store.ComputeStatsForKeySpan(req.StartKey.Next(), req.EndKey)
+		stats, count, err := store.ComputeStatsForKeySpan(req.StartKey, req.EndKey)
+		if err != nil {
+			return err
+		}
 		output.TotalStats.Add(stats)
 		output.RangeCount += int32(count)
 		return nil
@@ -727,6 +731,39 @@ func (s *statusServer) SpanStats(ctx context.Context, req *serverpb.SpanStatsReq
 	return output, nil
 }
 
+// clusterSpanStats returns the total statistics of the replicas of a key span
+// stored on all the nodes of the cluster, and the number of these replicas.
+// The nodes are the ones which recorded a status summary, along with the local
+// node.
+func (s *statusServer) clusterSpanStats(
+	ctx context.Context, span roachpb.RSpan,
+) (enginepb.MVCCStats, int, error) {
+	nodes, err := s.Nodes(ctx, &serverpb.NodesRequest{})
+	if err != nil {
+		return enginepb.MVCCStats{}, 0, err
+	}
+	nodeIDs := map[roachpb.NodeID]struct{}{s.gossip.GetNodeID(): {}}
+	for _, ns := range nodes.Nodes {
+		nodeIDs[ns.Desc.NodeID] = struct{}{}
+	}
+
+	var total enginepb.MVCCStats
+	var count int
+	for nodeID := range nodeIDs {
+		resp, err := s.SpanStats(ctx, &serverpb.SpanStatsRequest{
+			NodeId:   nodeID.String(),
+			StartKey: span.Key,
+			EndKey:   span.EndKey,
+		})
+		if err != nil {
+			return enginepb.MVCCStats{}, 0, errors.Wrapf(err, "node %d", nodeID)
+		}
+		total.Add(resp.TotalStats)
+		count += int(resp.RangeCount)
+	}
+	return total, count, nil
+}
+
 // jsonWrapper provides a wrapper on any slice data type being
 // marshaled to JSON. This prevents a security vulnerability
 // where a phishing attack can trick a user's browser into
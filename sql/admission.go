@@ -0,0 +1,234 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/util/envutil"
+	"github.com/cockroachdb/cockroach/util/metric"
+	"github.com/pkg/errors"
+)
+
+// maxConcurrentLargeStmts is the number of large statements (see isLargePlan)
+// executed concurrently by a node. The other ones are queued, so that a burst
+// of analytical queries doesn't starve the rest of the traffic of CPU and
+// memory. Zero, the default, disables the limit.
+var maxConcurrentLargeStmts = envutil.EnvOrDefaultInt("max_concurrent_large_stmts", 0)
+
+// largeStmtRows is the estimated cost (see estimatePlanCost) above which a
+// statement is large.
+var largeStmtRows = float64(envutil.EnvOrDefaultInt("large_stmt_rows", 10000))
+
+// largeStmtQueueTimeout is how long a large statement can be queued before
+// it is rejected.
+var largeStmtQueueTimeout = envutil.EnvOrDefaultDuration("large_stmt_queue_timeout", time.Minute)
+
+// largeStmtPollInterval is the interval at which the queued statements check
+// whether they were cancelled.
+const largeStmtPollInterval = 100 * time.Millisecond
+
+// constrainedScanSelectivity is the fraction of the rows of a table assumed
+// to be read by a scan constrained by a filter.
+const constrainedScanSelectivity = 0.1
+
+// admissionQueue limits the number of large statements executed
+// concurrently.
+type admissionQueue struct {
+	sem chan struct{}
+
+	running, queued            int64
+	runningGauge, queuedGauge  *metric.Gauge
+	admittedCount, rejectCount *metric.Counter
+}
+
+func makeAdmissionQueue(limit int, registry *metric.Registry) admissionQueue {
+	q := admissionQueue{
+		runningGauge:  registry.Gauge("large.running"),
+		queuedGauge:   registry.Gauge("large.queued"),
+		admittedCount: registry.Counter("large.admitted.count"),
+		rejectCount:   registry.Counter("large.rejected.count"),
+	}
+	if limit > 0 {
+		q.sem = make(chan struct{}, limit)
+	}
+	return q
+}
+
+// admitStmt admits the statement of a transaction through the queue of large
+// statements. A transaction is admitted once, and keeps its place in the queue
+// until it finishes. Only the statements executed before the transaction wrote
+// anything are queued: the other ones would make the transactions conflicting
+// with its intents wait along with it, so they are executed right away.
+func (e *Executor) admitStmt(p *planner, plan planNode) error {
+	txnState := &p.session.TxnState
+	if e.largeStmts.sem == nil || txnState.releaseAdmission != nil || p.txn.Proto.Writing {
+		return nil
+	}
+	release, err := e.largeStmts.admit(p, plan)
+	if err != nil {
+		return err
+	}
+	txnState.releaseAdmission = release
+	return nil
+}
+
+// admit waits until the plan of a statement can be executed, and returns the
+// function to call once it is done. The plans which aren't large are
+// admitted right away, and a nil function is returned.
+func (q *admissionQueue) admit(p *planner, plan planNode) (func(), error) {
+	if q.sem == nil {
+		return nil, nil
+	}
+	if large, err := isLargePlan(p, plan); err != nil || !large {
+		return nil, err
+	}
+	select {
+	case q.sem <- struct{}{}:
+	default:
+		if err := q.wait(p); err != nil {
+			q.rejectCount.Inc(1)
+			return nil, err
+		}
+	}
+	q.admittedCount.Inc(1)
+	q.runningGauge.Update(atomic.AddInt64(&q.running, 1))
+	return func() {
+		q.runningGauge.Update(atomic.AddInt64(&q.running, -1))
+		<-q.sem
+	}, nil
+}
+
+// wait queues a large statement until it can be executed, is cancelled, or
+// times out.
+func (q *admissionQueue) wait(p *planner) error {
+	q.queuedGauge.Update(atomic.AddInt64(&q.queued, 1))
+	defer func() {
+		q.queuedGauge.Update(atomic.AddInt64(&q.queued, -1))
+	}()
+
+	timeout := time.NewTimer(largeStmtQueueTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(largeStmtPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case q.sem <- struct{}{}:
+			return nil
+		case <-ticker.C:
+			if err := p.checkCancelled(); err != nil {
+				return err
+			}
+		case <-timeout.C:
+			return errors.Errorf("statement not admitted after waiting %s for %d large "+
+				"statements to complete", largeStmtQueueTimeout, cap(q.sem))
+		}
+	}
+}
+
+// isLargePlan returns whether the estimated cost of a plan exceeds
+// largeStmtRows.
+func isLargePlan(p *planner, plan planNode) (bool, error) {
+	cost, _, err := estimatePlanCost(p, plan)
+	if err != nil {
+		return false, err
+	}
+	return cost > largeStmtRows, nil
+}
+
+// estimatePlanCost returns the estimated number of rows processed by a plan,
+// which are the rows read by its scans, the rows sorted by its sorts and the
+// pairs of rows compared by its joins, along with the estimated number of rows
+// it returns. The plan is estimated before it is started.
+func estimatePlanCost(p *planner, plan planNode) (cost, rows float64, err error) {
+	switch n := plan.(type) {
+	case *scanNode:
+		rows, err := estimateScanRows(p, n)
+		return rows, rows, err
+	case *indexJoinNode:
+		// The table scan looks up the rows found by the index scan.
+		rows, err := estimateScanRows(p, n.index)
+		return 2 * rows, rows, err
+	}
+
+	_, _, children := plan.ExplainPlan(false)
+	childRows := make([]float64, len(children))
+	for i, child := range children {
+		c, r, err := estimatePlanCost(p, child)
+		if err != nil {
+			return 0, 0, err
+		}
+		cost += c
+		childRows[i] = r
+		rows = math.Max(rows, r)
+	}
+
+	switch n := plan.(type) {
+	case *sortNode:
+		if _, ok := n.sortStrategy.(*sortTopKStrategy); n.needSort && !ok {
+			cost += rows
+		}
+	case *joinNode:
+		if len(childRows) == 2 {
+			pairs := childRows[0] * childRows[1]
+			if math.IsNaN(pairs) {
+				// An unknown number of rows joined with no rows.
+				pairs = 0
+			}
+			cost += pairs
+		}
+	}
+	return cost, rows, nil
+}
+
+// estimateScanRows returns the estimated number of rows read by a scan. It
+// is derived from the row count of the most recent statistic of the table. A
+// scan of a table without statistics is only deemed large if it reads a
+// whole index.
+func estimateScanRows(p *planner, n *scanNode) (float64, error) {
+	if n.pointLookup {
+		return float64(len(n.spans)), nil
+	}
+	fullScan := n.isFullScan()
+	if !fullScan && n.index.Unique && len(n.ordering.exactMatchCols) >= len(n.index.ColumnIDs) {
+		// Each span looks up a single row.
+		return float64(len(n.spans)), nil
+	}
+
+	count, err := p.estimatedRowCount(&n.desc)
+	if err != nil {
+		return 0, err
+	}
+	var rows float64
+	if count == parser.DNull {
+		if fullScan {
+			rows = math.Inf(1)
+		} else {
+			rows = float64(len(n.spans))
+		}
+	} else {
+		rows = float64(*count.(*parser.DInt))
+		if !fullScan {
+			rows *= constrainedScanSelectivity
+		}
+	}
+	if n.limitHint > 0 && !n.limitSoft {
+		rows = math.Min(rows, float64(n.limitHint))
+	}
+	return rows, nil
+}
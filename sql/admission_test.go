@@ -0,0 +1,178 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/testutils"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+	"github.com/cockroachdb/cockroach/util/metric"
+)
+
+func TestAdmissionQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	leaseManager := s.LeaseManager().(*LeaseManager)
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (k INT PRIMARY KEY, v INT, INDEX t_v (v));
+CREATE TABLE d.u (k INT PRIMARY KEY);
+CREATE TABLE d.s (k INT PRIMARY KEY, v INT, INDEX s_v (v));
+INSERT INTO d.s VALUES (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8),
+  (9, 9), (10, 10), (11, 11), (12, 12), (13, 13), (14, 14), (15, 15), (16, 16),
+  (17, 17), (18, 18), (19, 19), (20, 20);
+CREATE STATISTICS sk ON k FROM d.s;
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(timeout time.Duration) { largeStmtQueueTimeout = timeout }(largeStmtQueueTimeout)
+	largeStmtQueueTimeout = 10 * time.Millisecond
+	defer func(rows float64) { largeStmtRows = rows }(largeStmtRows)
+	largeStmtRows = 5
+
+	testData := []struct {
+		sql   string
+		large bool
+	}{
+		// Without statistics, only the scans of whole indexes are large.
+		{`SELECT * FROM d.t WHERE k = 1`, false},
+		{`SELECT * FROM d.t WHERE v > 1 AND v < 10`, false},
+		{`SELECT * FROM d.t LIMIT 10`, false},
+		{`SELECT * FROM d.t ORDER BY k`, true},
+		{`SELECT * FROM d.t WHERE k > 1 ORDER BY v`, false},
+		{`SELECT * FROM d.t WHERE k IN (SELECT k FROM d.u)`, true},
+		{`SELECT * FROM d.t JOIN d.u USING (k) WHERE k = 1`, false},
+		{`SELECT COUNT(*) FROM d.t`, true},
+		{`INSERT INTO d.t VALUES (1, 1)`, false},
+		{`DELETE FROM d.t WHERE true`, true},
+		// The table d.s has 20 rows according to its statistics.
+		{`SELECT * FROM d.s WHERE k = 1`, false},
+		{`SELECT * FROM d.s WHERE k IN (1, 2, 3)`, false},
+		{`SELECT * FROM d.s WHERE v > 1 AND v < 10`, false},
+		{`SELECT * FROM d.s LIMIT 4`, false},
+		{`SELECT * FROM d.s ORDER BY v LIMIT 4`, true},
+		{`SELECT COUNT(*) FROM d.s`, true},
+		{`SELECT * FROM d.s AS a JOIN d.s AS b ON a.v = b.v WHERE a.k = 1 AND b.k = 1`, false},
+		{`SELECT * FROM d.s AS a JOIN d.s AS b ON a.v = b.v WHERE a.k > 1`, true},
+	}
+	if err := kvDB.Txn(func(txn *client.Txn) error {
+		p := makeInternalPlanner(txn, security.RootUser)
+		p.leaseMgr = leaseManager
+		for _, d := range testData {
+			plan, err := p.query(d.sql)
+			if err != nil {
+				return err
+			}
+			if large, err := isLargePlan(p, plan); err != nil {
+				return err
+			} else if large != d.large {
+				t.Errorf("%s: expected large=%t, but found %t", d.sql, d.large, large)
+			}
+
+			// A single large statement is admitted at a time.
+			q := makeAdmissionQueue(1, metric.NewRegistry())
+			release, err := q.admit(p, plan)
+			if err != nil {
+				return err
+			}
+			_, err = q.admit(p, plan)
+			if !d.large {
+				if err != nil {
+					t.Errorf("%s: %v", d.sql, err)
+				}
+				continue
+			} else if !testutils.IsError(err, "statement not admitted after waiting") {
+				t.Errorf("%s: expected the statement to be queued, but found %v", d.sql, err)
+			}
+			release()
+			if _, err := q.admit(p, plan); err != nil {
+				t.Errorf("%s: %v", d.sql, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestAdmissionQueueTxn tests that a transaction keeps its place in the queue
+// of large statements until it finishes, and that the statements executed
+// after a transaction wrote aren't queued.
+func TestAdmissionQueueTxn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(limit int) { maxConcurrentLargeStmts = limit }(maxConcurrentLargeStmts)
+	maxConcurrentLargeStmts = 1
+	defer func(timeout time.Duration) { largeStmtQueueTimeout = timeout }(largeStmtQueueTimeout)
+	largeStmtQueueTimeout = 10 * time.Millisecond
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (k INT PRIMARY KEY);
+`); err != nil {
+		t.Fatal(err)
+	}
+	const largeStmt = `SELECT COUNT(*) FROM d.t`
+
+	admitted, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := admitted.Exec(largeStmt); err != nil {
+		t.Fatal(err)
+	}
+	// The place of the transaction isn't released between its statements.
+	if _, err := db.Exec(largeStmt); !testutils.IsError(err, "statement not admitted") {
+		t.Fatalf("expected the statement to be rejected, but found %v", err)
+	}
+	if _, err := admitted.Exec(largeStmt); err != nil {
+		t.Fatal(err)
+	}
+
+	// A transaction which wrote isn't queued.
+	writer, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Exec(`INSERT INTO d.t VALUES (1)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Exec(largeStmt); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := admitted.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(largeStmt); err != nil {
+		t.Fatal(err)
+	}
+}
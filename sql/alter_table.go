@@ -19,6 +19,7 @@ package sql
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/security"
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/sql/privilege"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
@@ -35,7 +36,7 @@ type alterTableNode struct {
 //   notes: postgres requires CREATE on the table.
 //          mysql requires ALTER, CREATE, INSERT on the table.
 func (p *planner) AlterTable(n *parser.AlterTable) (planNode, error) {
-	if err := n.Table.NormalizeTableName(p.session.Database); err != nil {
+	if err := p.normalizeTableName(n.Table); err != nil {
 		return nil, err
 	}
 
@@ -65,16 +66,31 @@ func (n *alterTableNode) expandPlan() error {
 }
 
 func (n *alterTableNode) Start() error {
+	if err := checkPrimaryKeyChange(n.tableDesc); err != nil {
+		return err
+	}
+
 	// Commands can either change the descriptor directly (for
 	// alterations that don't require a backfill) or add a mutation to
 	// the list.
 	descriptorChanged := false
 	origNumMutations := len(n.tableDesc.Mutations)
+	// Columns added with a foreign key, which is resolved once the IDs of the
+	// column and its index have been allocated, and the positions of their
+	// mutations.
+	var fkDefs []*parser.ColumnTableDef
+	var fkMutations []int
+	// Foreign keys added to existing columns, which are resolved along with
+	// the ones above and then validated against the existing rows.
+	var fkConstraints []*parser.ForeignKeyConstraintTableDef
 
 	for _, cmd := range n.n.Cmds {
 		switch t := cmd.(type) {
 		case *parser.AlterTableAddColumn:
 			d := t.ColumnDef
+			if d.Computed.Computed {
+				return fmt.Errorf("computed column %q cannot be added to an existing table", d.Name)
+			}
 			col, idx, err := sqlbase.MakeColumnDefDescs(d)
 			if err != nil {
 				return err
@@ -90,7 +106,30 @@ func (n *alterTableNode) Start() error {
 				}
 			}
 
+			if d.References.Table != nil {
+				if d.DefaultExpr.Expr != nil {
+					return fmt.Errorf("foreign key column %q cannot have a default value "+
+						"when added to an existing table", col.Name)
+				}
+				if idx == nil {
+					// The foreign key needs an index on the column, which is
+					// added along with it.
+					idx = &sqlbase.IndexDescriptor{
+						ColumnNames:      []string{col.Name},
+						ColumnDirections: []sqlbase.IndexDescriptor_Direction{sqlbase.IndexDescriptor_ASC},
+					}
+				}
+				fkDefs = append(fkDefs, d)
+				fkMutations = append(fkMutations, len(n.tableDesc.Mutations))
+			}
+
 			n.tableDesc.AddColumnMutation(*col, sqlbase.DescriptorMutation_ADD)
+			if err := n.p.addSerialSequences(n.tableDesc, d); err != nil {
+				return err
+			}
+			if err := n.p.writeSequenceOwners(n.tableDesc); err != nil {
+				return err
+			}
 			if idx != nil {
 				n.tableDesc.AddIndexMutation(*idx, sqlbase.DescriptorMutation_ADD)
 			}
@@ -115,9 +154,24 @@ func (n *alterTableNode) Start() error {
 					Unique:           true,
 					StoreColumnNames: d.Storing,
 				}
-				if err := idx.FillColumns(d.Columns); err != nil {
+				cols := d.Columns
+				if d.Sharded != nil {
+					var err error
+					cols, idx.ShardBuckets, err = n.tableDesc.AddShardColumn(cols, d.Sharded)
+					if err != nil {
+						return err
+					}
+				}
+				if err := idx.FillColumns(cols); err != nil {
 					return err
 				}
+				if d.PartitionBy != nil {
+					part, err := sqlbase.MakePartitioning(n.tableDesc, &idx, d.PartitionBy)
+					if err != nil {
+						return err
+					}
+					idx.Partitioning = part
+				}
 				status, i, err := n.tableDesc.FindIndexByName(name)
 				if err == nil {
 					if status == sqlbase.DescriptorIncomplete &&
@@ -127,6 +181,31 @@ func (n *alterTableNode) Start() error {
 				}
 				n.tableDesc.AddIndexMutation(idx, sqlbase.DescriptorMutation_ADD)
 
+			case *parser.ForeignKeyConstraintTableDef:
+				fkConstraints = append(fkConstraints, d)
+				descriptorChanged = true
+
+			case *parser.CheckConstraintTableDef:
+				check, err := sqlbase.MakeCheckConstraint(n.tableDesc, d)
+				if err != nil {
+					return err
+				}
+				if check.Name == "" {
+					check.Name = makeCheckName(n.tableDesc)
+				} else if findCheck(n.tableDesc, check.Name) >= 0 {
+					return fmt.Errorf("duplicate constraint name: %q", check.Name)
+				}
+				// As for foreign keys, the constraint is enforced on the rows
+				// written once all the nodes know about it, and the existing
+				// rows are then checked by the schema changer, unless NOT
+				// VALID skips them.
+				check.Validity = sqlbase.ConstraintValidity_VALIDATING
+				if d.NotValid {
+					check.Validity = sqlbase.ConstraintValidity_UNVALIDATED
+				}
+				n.tableDesc.Checks = append(n.tableDesc.Checks, check)
+				descriptorChanged = true
+
 			default:
 				return fmt.Errorf("unsupported constraint: %T", t.ConstraintDef)
 			}
@@ -151,6 +230,12 @@ func (n *alterTableNode) Start() error {
 						return fmt.Errorf("column %q is referenced by existing index %q", col.Name, idx.Name)
 					}
 				}
+				if err := n.p.checkComputedColumnDependencies(n.tableDesc, col); err != nil {
+					return err
+				}
+				if err := n.p.removeComment(columnCommentType, n.tableDesc.ID, int(col.ID)); err != nil {
+					return err
+				}
 				n.tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_DROP)
 				n.tableDesc.Columns = append(n.tableDesc.Columns[:i], n.tableDesc.Columns[i+1:]...)
 
@@ -175,6 +260,10 @@ func (n *alterTableNode) Start() error {
 			}
 			switch status {
 			case sqlbase.DescriptorActive:
+				idx := n.tableDesc.Indexes[i]
+				if err := n.p.removeComment(indexCommentType, n.tableDesc.ID, int(idx.ID)); err != nil {
+					return err
+				}
 				n.tableDesc.AddIndexMutation(n.tableDesc.Indexes[i], sqlbase.DescriptorMutation_DROP)
 				n.tableDesc.Indexes = append(n.tableDesc.Indexes[:i], n.tableDesc.Indexes[i+1:]...)
 
@@ -188,6 +277,34 @@ func (n *alterTableNode) Start() error {
 				}
 			}
 
+		case *parser.AlterTableValidateConstraint:
+			changed, err := validateConstraint(n.tableDesc, string(t.Constraint))
+			if err != nil {
+				return err
+			}
+			if changed {
+				descriptorChanged = true
+			}
+
+		case *parser.AlterTableDropInterleave:
+			if err := n.p.removeInterleave(n.tableDesc, string(t.Index)); err != nil {
+				return err
+			}
+
+		case *parser.AlterTableAlterPrimaryKey:
+			if err := alterPrimaryKey(n.tableDesc, t); err != nil {
+				return err
+			}
+
+		case *parser.AlterTableAlterColumnType:
+			changed, err := n.p.alterColumnType(n.tableDesc, n.n.Table, t)
+			if err != nil {
+				return err
+			}
+			if changed {
+				descriptorChanged = true
+			}
+
 		case parser.ColumnMutationCmd:
 			// Column mutations
 			status, i, err := n.tableDesc.FindColumnByName(t.GetColumn())
@@ -242,6 +359,48 @@ func (n *alterTableNode) Start() error {
 		return err
 	}
 
+	var fkTargets []fkTargetUpdate
+	for i, d := range fkDefs {
+		col := n.tableDesc.Mutations[fkMutations[i]].GetColumn()
+		modified, err := n.p.resolveColFK(n.tableDesc, n.n.Table.Database(), n.tableDesc.ParentID,
+			*col, d.References.Table, d.References.Col, d.References.ConstraintName,
+			d.References.Actions, d.References.Timing)
+		if err != nil {
+			return err
+		}
+		fkTargets = append(fkTargets, modified)
+	}
+	for _, d := range fkConstraints {
+		from, to, err := singleColumnFK(d)
+		if err != nil {
+			return err
+		}
+		col, err := n.tableDesc.FindActiveColumnByName(string(from))
+		if err != nil {
+			return err
+		}
+		modified, err := n.p.resolveColFK(n.tableDesc, n.n.Table.Database(), n.tableDesc.ParentID,
+			col, d.Table, to, d.Name, d.Actions, d.Timing)
+		if err != nil {
+			return err
+		}
+		// The foreign key is enforced on the rows written once all the nodes
+		// know about it, and the existing rows are then checked by the schema
+		// changer, unless NOT VALID skips them.
+		idx, err := n.tableDesc.FindIndexByID(modified.srcIdx)
+		if err != nil {
+			return err
+		}
+		idx.ForeignKey.Validity = sqlbase.ConstraintValidity_VALIDATING
+		if d.Timing.NotValid {
+			idx.ForeignKey.Validity = sqlbase.ConstraintValidity_UNVALIDATED
+		}
+		fkTargets = append(fkTargets, modified)
+	}
+	if err := n.p.addFKBackReferences(n.tableDesc, fkTargets); err != nil {
+		return err
+	}
+
 	if err := n.p.writeTableDesc(n.tableDesc); err != nil {
 		return err
 	}
@@ -280,6 +439,52 @@ func (n *alterTableNode) ExplainPlan(v bool) (string, string, []planNode) {
 	return "alter table", "", nil
 }
 
+// makeCheckName returns a name for a CHECK constraint added to a table
+// without one, which is not used by another CHECK constraint of the table.
+func makeCheckName(tableDesc *sqlbase.TableDescriptor) string {
+	name := fmt.Sprintf("%s_check", tableDesc.Name)
+	for i := 1; findCheck(tableDesc, name) >= 0; i++ {
+		name = fmt.Sprintf("%s_check%d", tableDesc.Name, i)
+	}
+	return name
+}
+
+// validateConstraint makes the schema changer validate a CHECK or FOREIGN KEY
+// constraint of a table against its existing rows, if it isn't validated
+// already, in which case it returns false. The constraints of the unique
+// indexes always hold for all the rows.
+func validateConstraint(tableDesc *sqlbase.TableDescriptor, name string) (bool, error) {
+	var validity *sqlbase.ConstraintValidity
+	if i := findCheck(tableDesc, name); i >= 0 {
+		validity = &tableDesc.Checks[i].Validity
+	} else {
+		normName := sqlbase.NormalizeName(name)
+		indexes := []*sqlbase.IndexDescriptor{&tableDesc.PrimaryIndex}
+		for i := range tableDesc.Indexes {
+			indexes = append(indexes, &tableDesc.Indexes[i])
+		}
+		for _, idx := range indexes {
+			if idx.ForeignKey != nil && sqlbase.NormalizeName(idx.ForeignKey.Name) == normName {
+				validity = &idx.ForeignKey.Validity
+				break
+			}
+			if idx.Unique && sqlbase.NormalizeName(idx.Name) == normName {
+				return false, nil
+			}
+		}
+	}
+	if validity == nil {
+		return false, fmt.Errorf("constraint %q does not exist", name)
+	}
+	switch *validity {
+	case sqlbase.ConstraintValidity_VALIDATED:
+		return false, nil
+	case sqlbase.ConstraintValidity_UNVALIDATED:
+		*validity = sqlbase.ConstraintValidity_VALIDATING_UNVALIDATED
+	}
+	return true, nil
+}
+
 func applyColumnMutation(col *sqlbase.ColumnDescriptor, mut parser.ColumnMutationCmd) error {
 	switch t := mut.(type) {
 	case *parser.AlterTableSetDefault:
@@ -299,3 +504,327 @@ func applyColumnMutation(col *sqlbase.ColumnDescriptor, mut parser.ColumnMutatio
 	}
 	return nil
 }
+
+// alterColumnType changes the type of a column. The conversions which keep
+// the existing values valid, such as widening an INT or a STRING, only change
+// the column descriptor, and true is returned. The other ones replace the
+// column with a new column of the new type, whose values are converted from
+// the old ones by the column backfill: the column is not available until the
+// schema change completes, and then becomes the last column of the table.
+func (p *planner) alterColumnType(
+	tableDesc *sqlbase.TableDescriptor,
+	tableName *parser.QualifiedName,
+	t *parser.AlterTableAlterColumnType,
+) (bool, error) {
+	status, i, err := tableDesc.FindColumnByName(t.Column)
+	if err != nil {
+		return false, err
+	}
+	if status == sqlbase.DescriptorIncomplete {
+		switch tableDesc.Mutations[i].Direction {
+		case sqlbase.DescriptorMutation_ADD:
+			return false, fmt.Errorf("column %q in the middle of being added, try again later", t.Column)
+		default:
+			return false, fmt.Errorf("column %q in the middle of being dropped", t.Column)
+		}
+	}
+	col := tableDesc.Columns[i]
+
+	if typ, ok := t.ToType.(*parser.IntColType); ok && typ.IsSerial() {
+		return false, fmt.Errorf("cannot change the type of column %q to %s",
+			col.Name, parser.AsString(t.ToType))
+	}
+	newCol, _, err := sqlbase.MakeColumnDefDescs(&parser.ColumnTableDef{
+		Name: parser.Name(col.Name),
+		Type: t.ToType,
+	})
+	if err != nil {
+		return false, err
+	}
+	if newCol.Type == col.Type {
+		return false, nil
+	}
+	if col.ComputedExpr != nil {
+		return false, fmt.Errorf("cannot change the type of computed column %q", col.Name)
+	}
+	if columnTypeWidened(col.Type, newCol.Type) {
+		tableDesc.Columns[i].Type = newCol.Type
+		return true, nil
+	}
+
+	// The encoding of the values changes, so the column is rewritten, which
+	// isn't supported for the columns which other parts of the table depend
+	// on.
+	if tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
+		return false, fmt.Errorf("column %q is referenced by the primary key", col.Name)
+	}
+	for _, idx := range tableDesc.AllNonDropIndexes() {
+		if idx.ContainsColumnID(col.ID) {
+			return false, fmt.Errorf("column %q is referenced by existing index %q", col.Name, idx.Name)
+		}
+	}
+	if err := p.checkComputedColumnDependencies(tableDesc, col); err != nil {
+		return false, err
+	}
+	if err := p.checkConstraintDependencies(tableDesc, col); err != nil {
+		return false, err
+	}
+
+	newCol.Nullable = col.Nullable
+	if col.DefaultExpr != nil {
+		expr, err := parser.ParseExprTraditional(*col.DefaultExpr)
+		if err != nil {
+			return false, err
+		}
+		if err := sqlbase.SanitizeVarFreeExpr(expr, newCol.Type.ToDatumType(), "DEFAULT"); err != nil {
+			return false, err
+		}
+		newCol.DefaultExpr = col.DefaultExpr
+	}
+	if err := p.checkColumnConversion(tableName, col, *newCol, t.ToType); err != nil {
+		return false, err
+	}
+
+	newCol.ID = tableDesc.NextColumnID
+	tableDesc.NextColumnID++
+	if err := p.moveColumnComment(tableDesc.ID, col.ID, newCol.ID); err != nil {
+		return false, err
+	}
+	tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_DROP)
+	tableDesc.Columns = append(tableDesc.Columns[:i], tableDesc.Columns[i+1:]...)
+	tableDesc.AddColumnMutation(*newCol, sqlbase.DescriptorMutation_ADD)
+	return false, nil
+}
+
+// columnTypeWidened returns true if all the values of a column of type from
+// are valid values of type to, with the same encoding.
+func columnTypeWidened(from, to sqlbase.ColumnType) bool {
+	if from.Kind != to.Kind {
+		return false
+	}
+	switch from.Kind {
+	case sqlbase.ColumnType_INT, sqlbase.ColumnType_STRING:
+		return to.Width == 0 || (from.Width != 0 && to.Width >= from.Width)
+	case sqlbase.ColumnType_COLLATEDSTRING:
+		return *from.Locale == *to.Locale &&
+			(to.Width == 0 || (from.Width != 0 && to.Width >= from.Width))
+	case sqlbase.ColumnType_FLOAT:
+		return to.Precision == 0 || (from.Precision != 0 && to.Precision >= from.Precision)
+	case sqlbase.ColumnType_DECIMAL:
+		return from.Width == to.Width &&
+			(to.Precision == 0 || (from.Precision != 0 && to.Precision >= from.Precision))
+	}
+	return true
+}
+
+// checkColumnConversion returns an error if a value of a column can't be
+// converted to the type of the column replacing it.
+func (p *planner) checkColumnConversion(
+	tableName *parser.QualifiedName,
+	col, newCol sqlbase.ColumnDescriptor,
+	toType parser.ColumnType,
+) error {
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	defer ip.releaseLeases()
+	plan, err := ip.query(fmt.Sprintf(`SELECT CAST(%s AS %s) FROM %s`,
+		parser.AsString(parser.Name(col.Name)), parser.AsString(toType), tableName))
+	if err != nil {
+		return err
+	}
+	if err := plan.Start(); err != nil {
+		return err
+	}
+	for {
+		next, err := plan.Next()
+		if err != nil {
+			return err
+		}
+		if !next {
+			return nil
+		}
+		if err := sqlbase.CheckValueWidth(newCol, plan.Values()[0]); err != nil {
+			return err
+		}
+	}
+}
+
+// convertColumnValue converts a value of a column whose type is changed to
+// the type of the column replacing it. The values written since the
+// statement checked the conversion can fail it, which is reported as an
+// ErrColumnConversion so that the schema change is reversed.
+func convertColumnValue(
+	d parser.Datum, newCol sqlbase.ColumnDescriptor, evalCtx *parser.EvalContext,
+) (parser.Datum, error) {
+	typ, err := parser.DatumTypeToColumnType(newCol.Type.ToDatumType())
+	if err != nil {
+		return nil, err
+	}
+	v, err := (&parser.CastExpr{Expr: d, Type: typ}).Eval(evalCtx)
+	if err != nil {
+		return nil, sqlbase.NewColumnConversionError(newCol.Name, err)
+	}
+	if err := sqlbase.CheckValueWidth(newCol, v); err != nil {
+		return nil, sqlbase.NewColumnConversionError(newCol.Name, err)
+	}
+	return v, nil
+}
+
+// removeInterleave queues the mutations which move the named index out of the
+// table it is interleaved into. The index is replaced by a copy that is not
+// interleaved: the copy is backfilled into its own key span under a new index
+// ID, and the interleaved entries are deleted once it is public.
+func (p *planner) removeInterleave(tableDesc *sqlbase.TableDescriptor, idxName string) error {
+	if sqlbase.NormalizeName(tableDesc.PrimaryIndex.Name) == sqlbase.NormalizeName(idxName) {
+		return p.removePrimaryInterleave(tableDesc)
+	}
+	status, i, err := tableDesc.FindIndexByName(idxName)
+	if err != nil {
+		return err
+	}
+	if status == sqlbase.DescriptorIncomplete {
+		switch tableDesc.Mutations[i].Direction {
+		case sqlbase.DescriptorMutation_ADD:
+			return fmt.Errorf("index %q in the middle of being added, try again later", idxName)
+
+		case sqlbase.DescriptorMutation_DROP:
+			return fmt.Errorf("index %q in the middle of being dropped", idxName)
+		}
+	}
+
+	idx := tableDesc.Indexes[i]
+	if len(idx.Interleave.Ancestors) == 0 {
+		return fmt.Errorf("index %q is not interleaved", idx.Name)
+	}
+	// Foreign key references are by index ID, which changes.
+	if idx.ForeignKey != nil || len(idx.ReferencedBy) > 0 {
+		return fmt.Errorf("index %q is in use as a foreign key constraint", idx.Name)
+	}
+	if err := p.removeInterleaveBackReference(tableDesc, idx); err != nil {
+		return err
+	}
+
+	newIdx := idx
+	newIdx.ID = 0
+	newIdx.Interleave = sqlbase.InterleaveDescriptor{}
+
+	tableDesc.AddIndexMutation(idx, sqlbase.DescriptorMutation_DROP)
+	tableDesc.Indexes = append(tableDesc.Indexes[:i], tableDesc.Indexes[i+1:]...)
+	tableDesc.AddIndexMutation(newIdx, sqlbase.DescriptorMutation_ADD)
+	return nil
+}
+
+// removePrimaryInterleave queues the mutations which move the rows of a table
+// out of the table its primary index is interleaved into, by replacing the
+// primary index with a copy that is not interleaved, as when the primary key
+// is changed. The secondary indexes must not be interleaved, and no table
+// must be interleaved into the primary index, as their entries would stay in
+// the key span of the old primary index.
+func (p *planner) removePrimaryInterleave(tableDesc *sqlbase.TableDescriptor) error {
+	idx := tableDesc.PrimaryIndex
+	if len(idx.Interleave.Ancestors) == 0 {
+		return fmt.Errorf("index %q is not interleaved", idx.Name)
+	}
+	if len(idx.InterleavedBy) > 0 {
+		return fmt.Errorf("cannot remove the interleave of primary index %q, "+
+			"which other tables are interleaved into", idx.Name)
+	}
+	for _, secondary := range tableDesc.Indexes {
+		if len(secondary.Interleave.Ancestors) > 0 {
+			return fmt.Errorf("the interleave of index %q must be removed first", secondary.Name)
+		}
+	}
+
+	newPrimary := idx
+	newPrimary.ID = 0
+	newPrimary.Interleave = sqlbase.InterleaveDescriptor{}
+	if err := replacePrimaryIndex(tableDesc, newPrimary); err != nil {
+		return err
+	}
+	return p.removeInterleaveBackReference(tableDesc, idx)
+}
+
+// alterPrimaryKey queues the mutations changing the primary key of a table.
+func alterPrimaryKey(tableDesc *sqlbase.TableDescriptor, t *parser.AlterTableAlterPrimaryKey) error {
+	if tableDesc.IsInterleaved() {
+		return fmt.Errorf("cannot change the primary key of interleaved table %q", tableDesc.Name)
+	}
+
+	newPrimary := sqlbase.IndexDescriptor{
+		Name:   tableDesc.PrimaryIndex.Name,
+		Unique: true,
+	}
+	if err := newPrimary.FillColumns(t.Columns); err != nil {
+		return err
+	}
+	for _, name := range newPrimary.ColumnNames {
+		col, err := tableDesc.FindActiveColumnByName(name)
+		if err != nil {
+			return err
+		}
+		if col.Nullable {
+			return fmt.Errorf("primary key column %q must not be nullable", col.Name)
+		}
+		if col.IsVirtual() {
+			return fmt.Errorf("virtual computed column %q cannot be part of the primary key", col.Name)
+		}
+		for _, family := range tableDesc.Families[1:] {
+			for _, colID := range family.ColumnIDs {
+				if colID == col.ID {
+					return fmt.Errorf("primary key column %q must be in family %q, was in %q",
+						col.Name, tableDesc.Families[0].Name, family.Name)
+				}
+			}
+		}
+	}
+	return replacePrimaryIndex(tableDesc, newPrimary)
+}
+
+// replacePrimaryIndex queues the mutations replacing the primary index of a
+// table. The new primary index is written with the encoding of a primary index
+// and backfilled like a secondary index, and so are the copies of the
+// secondary indexes, whose entries refer to the rows by the new primary key.
+// Once they are all backfilled, they replace the old indexes, which are then
+// dropped.
+func replacePrimaryIndex(tableDesc *sqlbase.TableDescriptor, newPrimary sqlbase.IndexDescriptor) error {
+	// The mutations of the other commands of the statement are part of the
+	// same schema change, but not the ones of earlier statements.
+	for _, m := range tableDesc.Mutations {
+		if m.MutationID != tableDesc.NextMutationID {
+			return fmt.Errorf("table %q has schema changes in progress, try again later", tableDesc.Name)
+		}
+	}
+	if tableDesc.PrimaryKeyChangeInProgress() {
+		return fmt.Errorf("the primary key of table %q can only be changed once per statement",
+			tableDesc.Name)
+	}
+	for _, idx := range tableDesc.AllNonDropIndexes() {
+		// Foreign key references are by index ID, which changes.
+		if idx.ForeignKey != nil || len(idx.ReferencedBy) > 0 {
+			return fmt.Errorf("index %q is in use as a foreign key constraint", idx.Name)
+		}
+	}
+
+	newPrimary.PrimaryEncoding = true
+	newPrimary.ReplacesID = tableDesc.PrimaryIndex.ID
+	tableDesc.AddIndexMutation(newPrimary, sqlbase.DescriptorMutation_ADD)
+
+	for _, idx := range tableDesc.Indexes {
+		newIdx := idx
+		newIdx.ID = 0
+		newIdx.ImplicitColumnIDs = nil
+		newIdx.ReplacesID = idx.ID
+		tableDesc.AddIndexMutation(newIdx, sqlbase.DescriptorMutation_ADD)
+	}
+	return nil
+}
+
+// checkPrimaryKeyChange returns an error if the primary key of the table is
+// being changed: the other schema changes of the table, whose indexes would
+// refer to the rows by the old primary key, wait for it to be done.
+func checkPrimaryKeyChange(tableDesc *sqlbase.TableDescriptor) error {
+	if tableDesc.PrimaryKeyChangeInProgress() {
+		return fmt.Errorf("primary key of table %q is being changed, try again later", tableDesc.Name)
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/protoutil"
 	"github.com/cockroachdb/cockroach/util/timeutil"
 	"github.com/pkg/errors"
 )
@@ -201,7 +202,7 @@ func (sc *SchemaChanger) truncateAndBackfillColumns(
 ) error {
 	// Set the eval context timestamps.
 	pTime := timeutil.Now()
-	sc.evalCtx = parser.EvalContext{}
+	sc.evalCtx = parser.EvalContext{Sequences: dbSequences{db: &sc.db}}
 	sc.evalCtx.SetTxnTimestamp(pTime)
 	sc.evalCtx.SetStmtTimestamp(pTime)
 	defaultExprs, err := makeDefaultExprs(added, &parser.Parser{}, &sc.evalCtx)
@@ -218,6 +219,22 @@ func (sc *SchemaChanger) truncateAndBackfillColumns(
 		}
 	}
 
+	// A column added with the name of a column dropped by the same schema
+	// change replaces it with another type, and its values are converted from
+	// the dropped column's ones: converted maps the position of each added
+	// column to the position of the column it replaces, or -1.
+	var converted []int
+	for _, col := range added {
+		k := -1
+		for j := range dropped {
+			if sqlbase.NormalizeName(dropped[j].Name) == sqlbase.NormalizeName(col.Name) {
+				k = j
+				break
+			}
+		}
+		converted = append(converted, k)
+	}
+
 	// Add or Drop a column.
 	if len(dropped) > 0 || addingNonNullableColumn || len(defaultExprs) > 0 {
 		// Initialize start and end to represent a span of keys.
@@ -226,6 +243,18 @@ func (sc *SchemaChanger) truncateAndBackfillColumns(
 			return err
 		}
 
+		// The values of the replaced columns are truncated as they are
+		// converted, so they are all checked first: a value which can't be
+		// converted reverses the schema change, which needs them.
+		checkOnly := false
+		for _, k := range converted {
+			if k >= 0 {
+				checkOnly = true
+				break
+			}
+		}
+		start := sp.Start
+
 		// Run through the entire table key space adding and deleting columns.
 		for done := false; !done; {
 			// First extend the schema change lease.
@@ -237,11 +266,15 @@ func (sc *SchemaChanger) truncateAndBackfillColumns(
 
 			// Add and delete columns for a chunk of the key space.
 			sp.Start, done, err = sc.truncateAndBackfillColumnsChunk(
-				added, dropped, defaultExprs, &sc.evalCtx, sp,
+				added, dropped, converted, defaultExprs, &sc.evalCtx, sp, checkOnly,
 			)
 			if err != nil {
 				return err
 			}
+			if done && checkOnly {
+				checkOnly, done = false, false
+				sp.Start = start
+			}
 		}
 	}
 	return nil
@@ -250,9 +283,11 @@ func (sc *SchemaChanger) truncateAndBackfillColumns(
 func (sc *SchemaChanger) truncateAndBackfillColumnsChunk(
 	added []sqlbase.ColumnDescriptor,
 	dropped []sqlbase.ColumnDescriptor,
+	converted []int,
 	defaultExprs []parser.TypedExpr,
 	evalCtx *parser.EvalContext,
 	sp sqlbase.Span,
+	checkOnly bool,
 ) (roachpb.Key, bool, error) {
 	var curIndexKey roachpb.Key
 	done := false
@@ -297,15 +332,26 @@ func (sc *SchemaChanger) truncateAndBackfillColumnsChunk(
 		// the scan and applying the changes in many transactions is
 		// fine because the schema change is in the correct state to
 		// handle intermediate OLTP commands which delete and add
-		// values during the scan.
+		// values during the scan. The values of the added and dropped
+		// columns are also fetched, to convert the values of the replaced
+		// columns.
+		fetchCols := tableDesc.Columns
+		if len(dropped) > 0 {
+			fetchCols = make([]sqlbase.ColumnDescriptor, 0, len(tableDesc.Columns)+len(updateCols))
+			fetchCols = append(fetchCols, tableDesc.Columns...)
+			fetchCols = append(fetchCols, updateCols...)
+		}
 		var rf sqlbase.RowFetcher
-		colIDtoRowIndex := colIDtoRowIndexFromCols(tableDesc.Columns)
-		valNeededForCol := make([]bool, len(tableDesc.Columns))
-		for i := range valNeededForCol {
+		colIDtoRowIndex := colIDtoRowIndexFromCols(fetchCols)
+		valNeededForCol := make([]bool, len(fetchCols))
+		for i := range tableDesc.Columns {
 			_, valNeededForCol[i] = ru.fetchColIDtoRowIndex[tableDesc.Columns[i].ID]
 		}
+		for i := len(tableDesc.Columns); i < len(fetchCols); i++ {
+			valNeededForCol[i] = true
+		}
 		err = rf.Init(tableDesc, colIDtoRowIndex, &tableDesc.PrimaryIndex, false, false,
-			tableDesc.Columns, valNeededForCol)
+			fetchCols, valNeededForCol)
 		if err != nil {
 			return err
 		}
@@ -333,7 +379,19 @@ func (sc *SchemaChanger) truncateAndBackfillColumnsChunk(
 				tableDesc, &tableDesc.PrimaryIndex, colIDtoRowIndex, row, indexKeyPrefix)
 
 			for j, col := range added {
-				if defaultExprs == nil || defaultExprs[j] == nil {
+				if k := converted[j]; k >= 0 {
+					// The value written to the added column since the start of
+					// the schema change is kept if the dropped one has none,
+					// so that the rows can be backfilled again.
+					if d := row[len(tableDesc.Columns)+len(added)+k]; d != parser.DNull {
+						updateValues[j], err = convertColumnValue(d, col, evalCtx)
+						if err != nil {
+							return err
+						}
+					} else {
+						updateValues[j] = row[len(tableDesc.Columns)+j]
+					}
+				} else if defaultExprs == nil || defaultExprs[j] == nil {
 					updateValues[j] = parser.DNull
 				} else {
 					updateValues[j], err = defaultExprs[j].Eval(evalCtx)
@@ -345,12 +403,15 @@ func (sc *SchemaChanger) truncateAndBackfillColumnsChunk(
 					return sqlbase.NewNonNullViolationError(col.Name)
 				}
 			}
+			if checkOnly {
+				continue
+			}
 			for j := range dropped {
 				updateValues[j+len(added)] = parser.DNull
 			}
 
-			copy(oldValues, row)
-			for j := len(row); j < len(oldValues); j++ {
+			copy(oldValues, row[:len(tableDesc.Columns)])
+			for j := len(tableDesc.Columns); j < len(oldValues); j++ {
 				oldValues[j] = parser.DNull
 			}
 			if _, err := ru.updateRow(writeBatch, oldValues, updateValues); err != nil {
@@ -360,6 +421,9 @@ func (sc *SchemaChanger) truncateAndBackfillColumnsChunk(
 		if i < ColumnTruncateAndBackfillChunkSize {
 			done = true
 		}
+		if checkOnly {
+			return nil
+		}
 
 		if err := txn.Run(writeBatch); err != nil {
 			return convertBackfillError(tableDesc, writeBatch)
@@ -374,6 +438,12 @@ func (sc *SchemaChanger) truncateIndexes(
 	dropped []sqlbase.IndexDescriptor,
 ) error {
 	for _, desc := range dropped {
+		if len(desc.Interleave.Ancestors) > 0 {
+			if err := sc.truncateInterleavedIndex(lease, desc); err != nil {
+				return err
+			}
+			continue
+		}
 		// First extend the schema change lease.
 		l, err := sc.ExtendLease(*lease)
 		if err != nil {
@@ -412,6 +482,110 @@ func (sc *SchemaChanger) truncateIndexes(
 	return nil
 }
 
+// truncateInterleavedIndex deletes the entries of a dropped index which is
+// interleaved into another table. The entries are stored in the key span of
+// the index's root ancestor, so they are found by scanning the table's rows.
+func (sc *SchemaChanger) truncateInterleavedIndex(
+	lease *sqlbase.TableDescriptor_SchemaChangeLease,
+	desc sqlbase.IndexDescriptor,
+) error {
+	sp, err := sc.getTableSpan()
+	if err != nil {
+		return err
+	}
+	for done := false; !done; {
+		// First extend the schema change lease.
+		l, err := sc.ExtendLease(*lease)
+		if err != nil {
+			return err
+		}
+		*lease = l
+
+		sp.Start, done, err = sc.truncateInterleavedIndexChunk(desc, sp)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *SchemaChanger) truncateInterleavedIndexChunk(
+	desc sqlbase.IndexDescriptor,
+	sp sqlbase.Span,
+) (roachpb.Key, bool, error) {
+	var nextKey roachpb.Key
+	done := false
+	err := sc.db.Txn(func(txn *client.Txn) error {
+		tableDesc, err := getTableDescFromID(txn, sc.tableID)
+		if err != nil {
+			return err
+		}
+		// Short circuit the truncation if the table has been deleted, or if
+		// the index's data has been removed along with its ancestor.
+		if tableDesc.Deleted() {
+			done = true
+			return nil
+		}
+		if dropped, err := interleavedAncestorDropped(txn, desc); err != nil {
+			return err
+		} else if dropped {
+			done = true
+			return nil
+		}
+
+		planner := makePlanner()
+		planner.setTxn(txn)
+		scan := planner.Scan()
+		scan.desc = *tableDesc
+		scan.spans = []sqlbase.Span{sp}
+		scan.initDescDefaults(publicAndNonPublicColumns)
+		rows, err := selectIndex(scan, nil, false)
+		if err != nil {
+			return err
+		}
+		if err := rows.Start(); err != nil {
+			return err
+		}
+		colIDtoRowIndex, err := makeColIDtoRowIndex(rows, tableDesc)
+		if err != nil {
+			return err
+		}
+
+		b := &client.Batch{}
+		numRows := 0
+		for ; numRows < IndexBackfillChunkSize; numRows++ {
+			if next, err := rows.Next(); !next {
+				if err != nil {
+					return err
+				}
+				break
+			}
+			secondaryIndexEntries := make([][]sqlbase.IndexEntry, 1)
+			if err := sqlbase.EncodeSecondaryIndexes(
+				tableDesc, []sqlbase.IndexDescriptor{desc}, colIDtoRowIndex,
+				rows.Values(), secondaryIndexEntries); err != nil {
+				return err
+			}
+			for _, secondaryIndexEntry := range secondaryIndexEntries[0] {
+				if log.V(2) {
+					log.Infof("Del %s", secondaryIndexEntry.Key)
+				}
+				b.Del(secondaryIndexEntry.Key)
+			}
+		}
+		if err := txn.Run(b); err != nil {
+			return err
+		}
+		if numRows < IndexBackfillChunkSize {
+			done = true
+			return nil
+		}
+		nextKey = scan.fetcher.Key()
+		return nil
+	})
+	return nextKey, done, err
+}
+
 // IndexBackfillChunkSize is the maximum number of rows processed per chunk
 // during the index backfill.
 //
@@ -511,14 +685,14 @@ func (sc *SchemaChanger) backfillIndexesChunk(
 			rowVals := rows.Values()
 
 			for _, desc := range added {
-				secondaryIndexEntries := make([]sqlbase.IndexEntry, 1)
+				secondaryIndexEntries := make([][]sqlbase.IndexEntry, 1)
 				err := sqlbase.EncodeSecondaryIndexes(
 					tableDesc, []sqlbase.IndexDescriptor{desc}, colIDtoRowIndex,
 					rowVals, secondaryIndexEntries)
 				if err != nil {
 					return err
 				}
-				for _, secondaryIndexEntry := range secondaryIndexEntries {
+				for _, secondaryIndexEntry := range secondaryIndexEntries[0] {
 					if log.V(2) {
 						log.Infof("InitPut %s -> %v", secondaryIndexEntry.Key,
 							secondaryIndexEntry.Value)
@@ -542,3 +716,394 @@ func (sc *SchemaChanger) backfillIndexesChunk(
 	})
 	return nextKey, done, err
 }
+
+// ForeignKeyValidationChunkSize is the maximum number of rows checked per
+// chunk during the validation of a foreign key added to an existing table.
+const ForeignKeyValidationChunkSize = 100
+
+// CheckValidationChunkSize is the maximum number of rows checked per chunk
+// during the validation of a CHECK constraint.
+const CheckValidationChunkSize = 100
+
+// hasValidatingConstraints returns whether the schema changer has constraints
+// of the table to validate against the existing rows.
+func hasValidatingConstraints(tableDesc *sqlbase.TableDescriptor) bool {
+	return len(validatingForeignKeys(tableDesc)) > 0 || len(validatingChecks(tableDesc)) > 0
+}
+
+// isValidating returns whether a constraint is being validated against the
+// existing rows by the schema changer.
+func isValidating(validity sqlbase.ConstraintValidity) bool {
+	return validity == sqlbase.ConstraintValidity_VALIDATING ||
+		validity == sqlbase.ConstraintValidity_VALIDATING_UNVALIDATED
+}
+
+// isUnvalidated returns whether a constraint was added with NOT VALID and
+// hasn't been validated since.
+func isUnvalidated(validity sqlbase.ConstraintValidity) bool {
+	return validity == sqlbase.ConstraintValidity_UNVALIDATED ||
+		validity == sqlbase.ConstraintValidity_VALIDATING_UNVALIDATED
+}
+
+// validatingForeignKeys returns the IDs of the indexes of a table whose
+// foreign key still has to be validated against the existing rows.
+func validatingForeignKeys(tableDesc *sqlbase.TableDescriptor) []sqlbase.IndexID {
+	var ids []sqlbase.IndexID
+	for _, idx := range tableDesc.AllNonDropIndexes() {
+		if idx.ForeignKey != nil && isValidating(idx.ForeignKey.Validity) {
+			ids = append(ids, idx.ID)
+		}
+	}
+	return ids
+}
+
+// validateForeignKeys checks that the existing rows of the table satisfy the
+// foreign keys of the given indexes, and marks them as validated. The rows
+// written since the foreign keys were added are checked when they are
+// written, so the rows are checked in chunks, each in its own transaction. A
+// foreign key violated by a row is removed, unless it was added with NOT
+// VALID, and the violation returned.
+func (sc *SchemaChanger) validateForeignKeys(
+	lease *sqlbase.TableDescriptor_SchemaChangeLease, idxIDs []sqlbase.IndexID,
+) error {
+	for _, idxID := range idxIDs {
+		sp, err := sc.getTableSpan()
+		if err != nil {
+			return err
+		}
+		for done := false; !done; {
+			// First extend the schema change lease.
+			l, err := sc.ExtendLease(*lease)
+			if err != nil {
+				return err
+			}
+			*lease = l
+
+			sp.Start, done, err = sc.validateForeignKeyChunk(idxID, sp)
+			if err != nil {
+				if sqlbase.IsIntegrityConstraintError(err) {
+					if rmErr := sc.abortForeignKeyValidation(idxID, err); rmErr != nil {
+						return rmErr
+					}
+				}
+				return err
+			}
+		}
+
+		_, err = sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+			idx, err := desc.FindIndexByID(idxID)
+			if err != nil || idx.ForeignKey == nil {
+				// The index or its foreign key were dropped since.
+				return errDidntUpdateDescriptor
+			}
+			idx.ForeignKey.Validity = sqlbase.ConstraintValidity_VALIDATED
+			return nil
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *SchemaChanger) validateForeignKeyChunk(
+	idxID sqlbase.IndexID, sp sqlbase.Span,
+) (roachpb.Key, bool, error) {
+	var nextKey roachpb.Key
+	done := false
+	err := sc.db.Txn(func(txn *client.Txn) error {
+		tableDesc, err := getTableDescFromID(txn, sc.tableID)
+		if err != nil {
+			return err
+		}
+		// Short circuit the validation if the table has been deleted, or the
+		// foreign key dropped.
+		if tableDesc.Deleted() {
+			done = true
+			return nil
+		}
+		if idx, err := tableDesc.FindIndexByID(idxID); err != nil || idx.ForeignKey == nil {
+			done = true
+			return nil
+		}
+
+		fkTables := TablesNeededForFKs(*tableDesc, CheckInserts)
+		for id := range fkTables {
+			if fkTables[id], err = getTableDescFromID(txn, id); err != nil {
+				return err
+			}
+		}
+
+		planner := makePlanner()
+		planner.setTxn(txn)
+		scan := planner.Scan()
+		scan.desc = *tableDesc
+		scan.spans = []sqlbase.Span{sp}
+		scan.initDescDefaults(publicColumns)
+		rows, err := selectIndex(scan, nil, false)
+		if err != nil {
+			return err
+		}
+		if err := rows.Start(); err != nil {
+			return err
+		}
+
+		colIDtoRowIndex, err := makeColIDtoRowIndex(rows, tableDesc)
+		if err != nil {
+			return err
+		}
+		fks, err := makeFKInsertHelper(txn, *tableDesc, fkTables, colIDtoRowIndex)
+		if err != nil {
+			return err
+		}
+		numRows := 0
+		for ; numRows < ForeignKeyValidationChunkSize; numRows++ {
+			if next, err := rows.Next(); !next {
+				if err != nil {
+					return err
+				}
+				break
+			}
+			if err := fks.checkIdx(idxID, rows.Values()); err != nil {
+				return err
+			}
+		}
+		if err := fks.runChecks(); err != nil {
+			return err
+		}
+		// Have we checked all the table rows?
+		if numRows < ForeignKeyValidationChunkSize {
+			done = true
+			return nil
+		}
+		// Keep track of the next key.
+		nextKey = scan.fetcher.Key()
+		return nil
+	})
+	return nextKey, done, err
+}
+
+// validatingChecks returns the names of the CHECK constraints of a table
+// which still have to be validated against the existing rows.
+func validatingChecks(tableDesc *sqlbase.TableDescriptor) []string {
+	var names []string
+	for _, check := range tableDesc.Checks {
+		if isValidating(check.Validity) {
+			names = append(names, check.Name)
+		}
+	}
+	return names
+}
+
+// findCheck returns the index of the CHECK constraint of a table with the
+// given name, or -1 if there is none.
+func findCheck(tableDesc *sqlbase.TableDescriptor, name string) int {
+	normName := sqlbase.NormalizeName(name)
+	for i, check := range tableDesc.Checks {
+		if sqlbase.NormalizeName(check.Name) == normName {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateChecks checks that the existing rows of the table satisfy the CHECK
+// constraints with the given names, and marks them as validated. As for
+// foreign keys, the rows are checked in chunks, each in its own transaction,
+// and a constraint violated by a row is removed unless it was added with NOT
+// VALID.
+func (sc *SchemaChanger) validateChecks(
+	lease *sqlbase.TableDescriptor_SchemaChangeLease, names []string,
+) error {
+	for _, name := range names {
+		sp, err := sc.getTableSpan()
+		if err != nil {
+			return err
+		}
+		for done := false; !done; {
+			// First extend the schema change lease.
+			l, err := sc.ExtendLease(*lease)
+			if err != nil {
+				return err
+			}
+			*lease = l
+
+			sp.Start, done, err = sc.validateCheckChunk(name, sp)
+			if err != nil {
+				if sqlbase.IsIntegrityConstraintError(err) {
+					if rmErr := sc.abortCheckValidation(name, err); rmErr != nil {
+						return rmErr
+					}
+				}
+				return err
+			}
+		}
+
+		_, err = sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+			i := findCheck(desc, name)
+			if i < 0 {
+				// The constraint was dropped since.
+				return errDidntUpdateDescriptor
+			}
+			desc.Checks[i].Validity = sqlbase.ConstraintValidity_VALIDATED
+			return nil
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *SchemaChanger) validateCheckChunk(name string, sp sqlbase.Span) (roachpb.Key, bool, error) {
+	var nextKey roachpb.Key
+	done := false
+	err := sc.db.Txn(func(txn *client.Txn) error {
+		tableDesc, err := getTableDescFromID(txn, sc.tableID)
+		if err != nil {
+			return err
+		}
+		// Short circuit the validation if the table has been deleted, or the
+		// constraint dropped.
+		if tableDesc.Deleted() {
+			done = true
+			return nil
+		}
+		i := findCheck(tableDesc, name)
+		if i < 0 {
+			done = true
+			return nil
+		}
+
+		planner := makePlanner()
+		planner.setTxn(txn)
+		scan := planner.Scan()
+		scan.desc = *tableDesc
+		scan.spans = []sqlbase.Span{sp}
+		scan.initDescDefaults(publicColumns)
+		rows, err := selectIndex(scan, nil, false)
+		if err != nil {
+			return err
+		}
+		if err := rows.Start(); err != nil {
+			return err
+		}
+
+		colIDtoRowIndex, err := makeColIDtoRowIndex(rows, tableDesc)
+		if err != nil {
+			return err
+		}
+		// Only the validated constraint is evaluated.
+		checkDesc := *tableDesc
+		checkDesc.Checks = tableDesc.Checks[i : i+1]
+		var checks checkHelper
+		if err := checks.init(planner, &checkDesc); err != nil {
+			return err
+		}
+		numRows := 0
+		for ; numRows < CheckValidationChunkSize; numRows++ {
+			if next, err := rows.Next(); !next {
+				if err != nil {
+					return err
+				}
+				break
+			}
+			checks.loadRow(colIDtoRowIndex, rows.Values(), false)
+			if err := checks.check(&planner.evalCtx); err != nil {
+				return err
+			}
+		}
+		// Have we checked all the table rows?
+		if numRows < CheckValidationChunkSize {
+			done = true
+			return nil
+		}
+		// Keep track of the next key.
+		nextKey = scan.fetcher.Key()
+		return nil
+	})
+	return nextKey, done, err
+}
+
+// abortCheckValidation handles the violation of a CHECK constraint by an
+// existing row, like abortForeignKeyValidation.
+func (sc *SchemaChanger) abortCheckValidation(name string, violation error) error {
+	unvalidated := false
+	_, err := sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+		i := findCheck(desc, name)
+		if i < 0 || desc.Checks[i].Validity != sqlbase.ConstraintValidity_VALIDATING_UNVALIDATED {
+			return errDidntUpdateDescriptor
+		}
+		desc.Checks[i].Validity = sqlbase.ConstraintValidity_UNVALIDATED
+		unvalidated = true
+		return nil
+	}, nil)
+	if err != nil || unvalidated {
+		return err
+	}
+	log.Warningf("removing check constraint due to irrecoverable error: %s", violation)
+	return sc.removeCheck(name)
+}
+
+// removeCheck removes a CHECK constraint of the table.
+func (sc *SchemaChanger) removeCheck(name string) error {
+	_, err := sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+		i := findCheck(desc, name)
+		if i < 0 {
+			return errDidntUpdateDescriptor
+		}
+		desc.Checks = append(desc.Checks[:i], desc.Checks[i+1:]...)
+		return nil
+	}, nil)
+	return err
+}
+
+// abortForeignKeyValidation handles the violation of the foreign key of an
+// index by an existing row: a foreign key validated by VALIDATE CONSTRAINT
+// becomes unvalidated again, and one being added is removed.
+func (sc *SchemaChanger) abortForeignKeyValidation(idxID sqlbase.IndexID, violation error) error {
+	unvalidated := false
+	_, err := sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+		idx, err := desc.FindIndexByID(idxID)
+		if err != nil || idx.ForeignKey == nil ||
+			idx.ForeignKey.Validity != sqlbase.ConstraintValidity_VALIDATING_UNVALIDATED {
+			return errDidntUpdateDescriptor
+		}
+		idx.ForeignKey.Validity = sqlbase.ConstraintValidity_UNVALIDATED
+		unvalidated = true
+		return nil
+	}, nil)
+	if err != nil || unvalidated {
+		return err
+	}
+	log.Warningf("removing foreign key due to irrecoverable error: %s", violation)
+	return sc.removeForeignKey(idxID)
+}
+
+// removeForeignKey removes the foreign key of an index of the table, along
+// with its back-reference from the referenced table.
+func (sc *SchemaChanger) removeForeignKey(idxID sqlbase.IndexID) error {
+	var removed *sqlbase.IndexDescriptor
+	_, err := sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+		removed = nil
+		idx, err := desc.FindIndexByID(idxID)
+		if err != nil || idx.ForeignKey == nil {
+			return errDidntUpdateDescriptor
+		}
+		removed = protoutil.Clone(idx).(*sqlbase.IndexDescriptor)
+		if idx.ForeignKey.Table == desc.ID {
+			if err := removeFKBackReferenceFromTable(desc, desc.ID, *idx); err != nil {
+				return err
+			}
+		}
+		idx.ForeignKey = nil
+		return nil
+	}, nil)
+	if err != nil || removed == nil || removed.ForeignKey.Table == sc.tableID {
+		return err
+	}
+	_, err = sc.leaseMgr.Publish(removed.ForeignKey.Table, func(desc *sqlbase.TableDescriptor) error {
+		return removeFKBackReferenceFromTable(desc, sc.tableID, *removed)
+	}, nil)
+	return err
+}
@@ -84,7 +84,32 @@ func (c *checkHelper) check(ctx *parser.EvalContext) error {
 			return err
 		} else if !res && d != parser.DNull {
 			// Failed to satisfy CHECK constraint.
-			return fmt.Errorf("failed to satisfy CHECK constraint (%s)", expr.String())
+			return sqlbase.NewCheckViolationError(expr.String())
+		}
+	}
+	return nil
+}
+
+// checkConstraintDependencies returns an error if a CHECK constraint of the
+// table references the column.
+func (p *planner) checkConstraintDependencies(
+	tableDesc *sqlbase.TableDescriptor, col sqlbase.ColumnDescriptor,
+) error {
+	sourceInfo := newSourceInfoForSingleTable(tableDesc.Name, makeResultColumns(tableDesc.Columns))
+	for _, check := range tableDesc.Checks {
+		raw, err := parser.ParseExprTraditional(check.Expr)
+		if err != nil {
+			return err
+		}
+		qvals := make(qvalMap)
+		if _, err := p.analyzeExpr(raw, multiSourceInfo{sourceInfo}, qvals,
+			parser.TypeBool, false, ""); err != nil {
+			return err
+		}
+		for ref := range qvals {
+			if tableDesc.Columns[ref.colIdx].ID == col.ID {
+				return fmt.Errorf("column %q is referenced by check constraint %q", col.Name, check.Expr)
+			}
 		}
 	}
 	return nil
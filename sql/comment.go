@@ -0,0 +1,229 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/privilege"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// commentType is the type of the object a comment in system.comments is set
+// on.
+type commentType int
+
+const (
+	databaseCommentType commentType = iota
+	tableCommentType
+	columnCommentType
+	indexCommentType
+)
+
+// CommentOnDatabase sets or removes the comment on a database.
+// Privileges: CREATE on database.
+//   Notes: postgres requires ownership of the database.
+func (p *planner) CommentOnDatabase(n *parser.CommentOnDatabase) (planNode, error) {
+	dbDesc, err := p.mustGetDatabaseDesc(string(n.Name))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPrivilege(dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if err := p.setComment(databaseCommentType, dbDesc.ID, 0, n.Comment); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// CommentOnTable sets or removes the comment on a table.
+// Privileges: CREATE on table.
+//   Notes: postgres requires ownership of the table.
+func (p *planner) CommentOnTable(n *parser.CommentOnTable) (planNode, error) {
+	if err := p.normalizeTableName(n.Table); err != nil {
+		return nil, err
+	}
+	tableDesc, err := p.mustGetTableDesc(n.Table)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPrivilege(tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if err := p.setComment(tableCommentType, tableDesc.ID, 0, n.Comment); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// CommentOnColumn sets or removes the comment on a column.
+// Privileges: CREATE on table.
+//   Notes: postgres requires ownership of the table.
+func (p *planner) CommentOnColumn(n *parser.CommentOnColumn) (planNode, error) {
+	// The column name is qualified by its table: [database.]table.column.
+	name := n.ColumnName
+	if len(name.Indirect) == 0 || len(name.Indirect) > 2 {
+		return nil, fmt.Errorf("invalid column name: %s", name)
+	}
+	colName, ok := name.Indirect[len(name.Indirect)-1].(parser.NameIndirection)
+	if !ok {
+		return nil, fmt.Errorf("invalid column name: %s", name)
+	}
+	tableName := &parser.QualifiedName{
+		Base:     name.Base,
+		Indirect: append(parser.Indirection(nil), name.Indirect[:len(name.Indirect)-1]...),
+	}
+	if err := p.normalizeTableName(tableName); err != nil {
+		return nil, err
+	}
+	tableDesc, err := p.mustGetTableDesc(tableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPrivilege(tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	col, err := tableDesc.FindActiveColumnByName(string(colName))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.setComment(columnCommentType, tableDesc.ID, int(col.ID), n.Comment); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// CommentOnIndex sets or removes the comment on an index.
+// Privileges: CREATE on table.
+//   Notes: postgres requires ownership of the index.
+func (p *planner) CommentOnIndex(n *parser.CommentOnIndex) (planNode, error) {
+	if err := p.normalizeTableName(n.Index.Table); err != nil {
+		return nil, err
+	}
+	tableDesc, err := p.mustGetTableDesc(n.Index.Table)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPrivilege(tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	status, i, err := tableDesc.FindIndexByName(string(n.Index.Index))
+	if err != nil {
+		return nil, err
+	}
+	if status != sqlbase.DescriptorActive {
+		return nil, fmt.Errorf("index %q is being added or dropped, try again later", n.Index.Index)
+	}
+	idx := tableDesc.Indexes[i]
+	if err := p.setComment(indexCommentType, tableDesc.ID, int(idx.ID), n.Comment); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// commentExecutor returns the executor used to access system.comments, which
+// only the root user has privileges on.
+func (p *planner) commentExecutor() InternalExecutor {
+	return InternalExecutor{LeaseManager: p.leaseMgr}
+}
+
+// setComment sets the comment on an object, or removes it if comment is nil.
+func (p *planner) setComment(
+	typ commentType, objID sqlbase.ID, subID int, comment *string,
+) error {
+	ie := p.commentExecutor()
+	if comment == nil {
+		_, err := ie.ExecuteStatementInTransaction(p.txn,
+			`DELETE FROM system.comments WHERE type = $1 AND objectID = $2 AND subID = $3`,
+			int(typ), int(objID), subID)
+		return err
+	}
+	_, err := ie.ExecuteStatementInTransaction(p.txn,
+		`UPSERT INTO system.comments (type, objectID, subID, comment) VALUES ($1, $2, $3, $4)`,
+		int(typ), int(objID), subID, *comment)
+	return err
+}
+
+// removeComment removes the comment on an object, if any.
+func (p *planner) removeComment(typ commentType, objID sqlbase.ID, subID int) error {
+	return p.setComment(typ, objID, subID, nil)
+}
+
+// removeTableComments removes the comments on a table and its columns and
+// indexes.
+func (p *planner) removeTableComments(tableID sqlbase.ID) error {
+	_, err := p.commentExecutor().ExecuteStatementInTransaction(p.txn,
+		`DELETE FROM system.comments WHERE type IN ($1, $2, $3) AND objectID = $4`,
+		int(tableCommentType), int(columnCommentType), int(indexCommentType), int(tableID))
+	return err
+}
+
+// moveTableComments moves the comments on a table and its columns and
+// indexes to the table replacing it.
+func (p *planner) moveTableComments(fromID, toID sqlbase.ID) error {
+	_, err := p.commentExecutor().ExecuteStatementInTransaction(p.txn,
+		`UPDATE system.comments SET objectID = $1 WHERE type IN ($2, $3, $4) AND objectID = $5`,
+		int(toID), int(tableCommentType), int(columnCommentType), int(indexCommentType), int(fromID))
+	return err
+}
+
+// moveColumnComment moves the comment on a column to the column replacing it.
+func (p *planner) moveColumnComment(tableID sqlbase.ID, fromID, toID sqlbase.ColumnID) error {
+	_, err := p.commentExecutor().ExecuteStatementInTransaction(p.txn,
+		`UPDATE system.comments SET subID = $1 WHERE type = $2 AND objectID = $3 AND subID = $4`,
+		int(toID), int(columnCommentType), int(tableID), int(fromID))
+	return err
+}
+
+// getComment returns the comment on an object, or DNull if there is none.
+func (p *planner) getComment(typ commentType, objID sqlbase.ID, subID int) (parser.Datum, error) {
+	if p.txn == nil {
+		return nil, errors.New("comments can only be read in a transaction")
+	}
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	row, err := ip.queryRow(
+		`SELECT comment FROM system.comments WHERE type = $1 AND objectID = $2 AND subID = $3`,
+		int(typ), int(objID), subID)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return parser.DNull, nil
+	}
+	return row[0], nil
+}
+
+var _ parser.CommentLookup = &planner{}
+
+// ObjectComment implements the parser.CommentLookup interface. Databases and
+// tables share the descriptor ID space, so the ID identifies at most one of
+// them.
+func (p *planner) ObjectComment(id int64) (parser.Datum, error) {
+	comment, err := p.getComment(tableCommentType, sqlbase.ID(id), 0)
+	if err != nil || comment != parser.DNull {
+		return comment, err
+	}
+	return p.getComment(databaseCommentType, sqlbase.ID(id), 0)
+}
+
+// ColumnComment implements the parser.CommentLookup interface.
+func (p *planner) ColumnComment(tableID, columnID int64) (parser.Datum, error) {
+	return p.getComment(columnCommentType, sqlbase.ID(tableID), int(columnID))
+}
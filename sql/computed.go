@@ -0,0 +1,203 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+)
+
+// computedHelper evaluates the expressions of the computed columns of a table.
+// Their values are computed from the other columns of a row whenever the row is
+// written. The values of the virtual computed columns are not stored in the
+// primary index, so they are also computed whenever the row is read from it.
+type computedHelper struct {
+	cols []sqlbase.ColumnDescriptor
+	// computedCols, exprs and qvals are parallel: the computed columns of the
+	// table, their expressions and the qvalues used by each expression.
+	computedCols []sqlbase.ColumnDescriptor
+	exprs        []parser.TypedExpr
+	qvals        []qvalMap
+}
+
+// init sets up the evaluation of the computed columns of the table, or only of
+// the virtual ones if virtualOnly is true.
+func (c *computedHelper) init(
+	p *planner, tableDesc *sqlbase.TableDescriptor, virtualOnly bool,
+) error {
+	var exprStrings []string
+	for _, col := range tableDesc.Columns {
+		if col.ComputedExpr != nil && (col.IsVirtual() || !virtualOnly) {
+			c.computedCols = append(c.computedCols, col)
+			exprStrings = append(exprStrings, *col.ComputedExpr)
+		}
+	}
+	if len(c.computedCols) == 0 {
+		return nil
+	}
+
+	c.cols = tableDesc.Columns
+	sourceInfo := newSourceInfoForSingleTable(tableDesc.Name, makeResultColumns(tableDesc.Columns))
+
+	exprs, err := parser.ParseExprsTraditional(exprStrings)
+	if err != nil {
+		return err
+	}
+
+	c.exprs = make([]parser.TypedExpr, len(exprs))
+	c.qvals = make([]qvalMap, len(exprs))
+	for i, raw := range exprs {
+		c.qvals[i] = make(qvalMap)
+		typedExpr, err := p.analyzeExpr(raw, multiSourceInfo{sourceInfo}, c.qvals[i],
+			c.computedCols[i].Type.ToDatumType(), false, "")
+		if err != nil {
+			return err
+		}
+		c.exprs[i] = typedExpr
+	}
+	return nil
+}
+
+// dependencies returns the IDs of the columns referenced by the expression of
+// the i-th computed column.
+func (c *computedHelper) dependencies(i int) []sqlbase.ColumnID {
+	ids := make([]sqlbase.ColumnID, 0, len(c.qvals[i]))
+	for ref := range c.qvals[i] {
+		ids = append(ids, c.cols[ref.colIdx].ID)
+	}
+	return ids
+}
+
+// dependsOn returns true if the expression of any computed column references
+// one of the given columns.
+func (c *computedHelper) dependsOn(cols []sqlbase.ColumnDescriptor) bool {
+	for i := range c.computedCols {
+		for _, id := range c.dependencies(i) {
+			for _, col := range cols {
+				if col.ID == id {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Set values in the qvalues used by the computed column exprs.
+// Any value not passed is set to NULL, unless `merge` is true, in which
+// case it is left unchanged (allowing updating a subset of a row's values).
+func (c *computedHelper) loadRow(colIdx map[sqlbase.ColumnID]int, row parser.DTuple, merge bool) {
+	for _, qvals := range c.qvals {
+		for ref, qval := range qvals {
+			ri, has := colIdx[c.cols[ref.colIdx].ID]
+			if has {
+				qval.datum = row[ri]
+			} else if !merge {
+				qval.datum = parser.DNull
+			}
+		}
+	}
+}
+
+// fill evaluates the computed columns which have a position in row according
+// to colIdx and stores their values there.
+func (c *computedHelper) fill(
+	ctx *parser.EvalContext, colIdx map[sqlbase.ColumnID]int, row parser.DTuple,
+) error {
+	for i, expr := range c.exprs {
+		ri, ok := colIdx[c.computedCols[i].ID]
+		if !ok {
+			continue
+		}
+		d, err := expr.Eval(ctx)
+		if err != nil {
+			return err
+		}
+		row[ri] = d
+	}
+	return nil
+}
+
+// checkComputedColumnDependencies returns an error if the given column is
+// referenced by the expression of a computed column of the table.
+func (p *planner) checkComputedColumnDependencies(
+	tableDesc *sqlbase.TableDescriptor, col sqlbase.ColumnDescriptor,
+) error {
+	var c computedHelper
+	if err := c.init(p, tableDesc, false); err != nil {
+		return err
+	}
+	for i, computed := range c.computedCols {
+		for _, id := range c.dependencies(i) {
+			if id != col.ID {
+				continue
+			}
+			if computed.IsIndexExpr() {
+				for _, idx := range tableDesc.AllNonDropIndexes() {
+					if idx.ContainsColumnID(computed.ID) {
+						return fmt.Errorf("column %q is referenced by existing index %q", col.Name, idx.Name)
+					}
+				}
+			}
+			return fmt.Errorf("column %q is referenced by computed column %q", col.Name, computed.Name)
+		}
+	}
+	return nil
+}
+
+// replaceComputedExprs returns the filter of a scan with the expressions of the
+// computed columns of the table replaced by the columns, so that the indexes on
+// these columns, which include the expression indexes, are used to constrain
+// the scan. The replaced columns are marked as needed.
+func (n *scanNode) replaceComputedExprs(filter parser.TypedExpr) parser.TypedExpr {
+	v := computedExprVisitor{n: n, cols: make(map[string]int)}
+	for _, col := range n.desc.Columns {
+		if col.ComputedExpr != nil {
+			v.cols[*col.ComputedExpr] = n.colIdxMap[col.ID]
+		}
+	}
+	if len(v.cols) == 0 {
+		return filter
+	}
+	expr, _ := parser.WalkExpr(&v, filter)
+	return expr.(parser.TypedExpr)
+}
+
+// computedExprVisitor replaces the expressions of computed columns with the
+// columns, matching the expressions by their string representation.
+type computedExprVisitor struct {
+	n *scanNode
+	// cols maps the expressions of the computed columns to the indexes of the
+	// columns in the scan.
+	cols map[string]int
+}
+
+var _ parser.Visitor = &computedExprVisitor{}
+
+func (v *computedExprVisitor) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	switch expr.(type) {
+	case *parser.IndexedVar, parser.Datum:
+		return false, expr
+	}
+	if idx, ok := v.cols[expr.String()]; ok {
+		v.n.valNeededForCol[idx] = true
+		return false, v.n.filterVars.IndexedVar(idx)
+	}
+	return true, expr
+}
+
+func (*computedExprVisitor) VisitPost(expr parser.Expr) parser.Expr { return expr }
@@ -0,0 +1,231 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/timeutil"
+	"github.com/pkg/errors"
+)
+
+// crdbInternalName is the name of the database holding the virtual tables
+// which expose the internal state of the node executing the statement.
+const crdbInternalName = "crdb_internal"
+
+// virtualTable is a table of crdb_internal. Its rows are generated when it
+// is planned.
+type virtualTable struct {
+	columns  []ResultColumn
+	populate func(p *planner, addRow func(...parser.Datum)) error
+}
+
+var crdbInternalTables = map[string]virtualTable{
+	"node_runtime_info": {
+		columns: []ResultColumn{
+			{Name: "node_id", Typ: parser.TypeInt},
+			{Name: "name", Typ: parser.TypeString},
+			{Name: "value", Typ: parser.TypeInt},
+		},
+		populate: populateRuntimeInfo,
+	},
+	"node_goroutines": {
+		columns: []ResultColumn{
+			{Name: "node_id", Typ: parser.TypeInt},
+			{Name: "id", Typ: parser.TypeInt},
+			{Name: "state", Typ: parser.TypeString},
+			{Name: "stack", Typ: parser.TypeString},
+		},
+		populate: populateGoroutines,
+	},
+	"eventlog": {
+		columns: []ResultColumn{
+			{Name: "timestamp", Typ: parser.TypeTimestamp},
+			{Name: "event_type", Typ: parser.TypeString},
+			{Name: "target_id", Typ: parser.TypeInt},
+			{Name: "reporting_id", Typ: parser.TypeInt},
+			{Name: "info", Typ: parser.TypeString},
+		},
+		populate: populateEventLog,
+	},
+}
+
+// getVirtualTable returns the plan generating the rows of a table of
+// crdb_internal, or nil if the name doesn't refer to one. Only root can read
+// them.
+func (p *planner) getVirtualTable(qname *parser.QualifiedName) (planNode, error) {
+	if len(qname.Indirect) != 1 || sqlbase.NormalizeName(string(qname.Base)) != crdbInternalName {
+		return nil, nil
+	}
+	if err := qname.NormalizeTableName(crdbInternalName); err != nil {
+		return nil, err
+	}
+	table, ok := crdbInternalTables[sqlbase.NormalizeName(qname.Table())]
+	if !ok {
+		return nil, sqlbase.NewUndefinedTableError(qname.String())
+	}
+	if p.session.User != security.RootUser {
+		return nil, errors.Errorf("only %s is allowed to read %s", security.RootUser, qname)
+	}
+
+	v := &valuesNode{columns: table.columns}
+	if err := table.populate(p, func(row ...parser.Datum) {
+		v.rows = append(v.rows, row)
+	}); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func populateRuntimeInfo(p *planner, addRow func(...parser.Datum)) error {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	nodeID := parser.NewDInt(parser.DInt(p.evalCtx.NodeID))
+	for _, stat := range []struct {
+		name  string
+		value int64
+	}{
+		{"goroutines", int64(runtime.NumGoroutine())},
+		{"cgo_calls", runtime.NumCgoCall()},
+		{"cpus", int64(runtime.NumCPU())},
+		{"gomaxprocs", int64(runtime.GOMAXPROCS(0))},
+		{"heap_alloc_bytes", int64(ms.HeapAlloc)},
+		{"heap_sys_bytes", int64(ms.HeapSys)},
+		{"heap_objects", int64(ms.HeapObjects)},
+		{"total_alloc_bytes", int64(ms.TotalAlloc)},
+		{"sys_bytes", int64(ms.Sys)},
+		{"gc_count", int64(ms.NumGC)},
+		{"gc_pause_total_ns", int64(ms.PauseTotalNs)},
+		{"gc_last_unix_ns", int64(ms.LastGC)},
+	} {
+		addRow(nodeID, parser.NewDString(stat.name), parser.NewDInt(parser.DInt(stat.value)))
+	}
+	return nil
+}
+
+// populateEventLog returns the events of system.eventlog, oldest first.
+func populateEventLog(p *planner, addRow func(...parser.Datum)) error {
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	defer ip.releaseLeases()
+	rows, err := ip.queryRows(`SELECT timestamp, eventType, targetID, reportingID, info
+FROM system.eventlog ORDER BY timestamp, uniqueID`)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		addRow(row...)
+	}
+	return nil
+}
+
+// populateGoroutines returns a row for each goroutine, whose stack trace
+// starts with a header such as "goroutine 1 [running]:".
+func populateGoroutines(p *planner, addRow func(...parser.Datum)) error {
+	nodeID := parser.NewDInt(parser.DInt(p.evalCtx.NodeID))
+	for _, trace := range strings.Split(string(allStacks()), "\n\n") {
+		trace = strings.TrimSpace(trace)
+		if trace == "" {
+			continue
+		}
+		header := trace
+		stack := ""
+		if i := strings.IndexByte(trace, '\n'); i >= 0 {
+			header, stack = trace[:i], trace[i+1:]
+		}
+		var id int64
+		var state string
+		if fields := strings.SplitN(strings.TrimPrefix(header, "goroutine "), " ", 2); len(fields) == 2 {
+			id, _ = strconv.ParseInt(fields[0], 10, 64)
+			state = strings.TrimSuffix(strings.TrimPrefix(fields[1], "["), "]:")
+		}
+		addRow(nodeID, parser.NewDInt(parser.DInt(id)), parser.NewDString(state),
+			parser.NewDString(stack))
+	}
+	return nil
+}
+
+// allStacks returns the stack traces of all the goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+var _ parser.Profiler = &planner{}
+
+// maxCPUProfileDuration bounds the duration of the CPU profiles collected
+// from SQL, during which the statement holds its session and the profiler of
+// the node.
+const maxCPUProfileDuration = time.Minute
+
+// cpuProfileCancelCheckInterval is how often the collection of a CPU profile
+// checks whether its statement was cancelled.
+const cpuProfileCancelCheckInterval = 100 * time.Millisecond
+
+// CPUProfile implements the parser.Profiler interface. The collection of the
+// profile stops early, with an error, if the statement is cancelled.
+func (p *planner) CPUProfile(d time.Duration) (parser.Datum, error) {
+	if p.session.User != security.RootUser {
+		return nil, errors.Errorf("only %s is allowed to collect profiles", security.RootUser)
+	}
+	if d > maxCPUProfileDuration {
+		return nil, errors.Errorf("profile duration %s exceeds the maximum of %s",
+			d, maxCPUProfileDuration)
+	}
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		return nil, fmt.Errorf("cannot start CPU profile: %v", err)
+	}
+	end := timeutil.Now().Add(d)
+	for wait := d; wait > 0; wait = end.Sub(timeutil.Now()) {
+		if wait > cpuProfileCancelCheckInterval {
+			wait = cpuProfileCancelCheckInterval
+		}
+		time.Sleep(wait)
+		if err := p.checkCancelled(); err != nil {
+			pprof.StopCPUProfile()
+			return nil, err
+		}
+	}
+	pprof.StopCPUProfile()
+	return parser.NewDBytes(parser.DBytes(buf.String())), nil
+}
+
+// HeapProfile implements the parser.Profiler interface.
+func (p *planner) HeapProfile() (parser.Datum, error) {
+	if p.session.User != security.RootUser {
+		return nil, errors.Errorf("only %s is allowed to collect profiles", security.RootUser)
+	}
+	var buf bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&buf, 0); err != nil {
+		return nil, err
+	}
+	return parser.NewDBytes(parser.DBytes(buf.String())), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/testutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+	"github.com/cockroachdb/cockroach/util/timeutil"
+)
+
+// isCPUProfile returns whether b starts with the header of a CPU profile. The
+// legacy format starts with the words 0 (the header count) and 3 (the number
+// of words of the header), in the byte order of the machine, which is
+// assumed to be little-endian. The newer format is gzipped.
+func isCPUProfile(b []byte) bool {
+	if bytes.HasPrefix(b, []byte{0x1f, 0x8b}) {
+		return true
+	}
+	wordSize := strconv.IntSize / 8
+	if len(b) < 2*wordSize {
+		return false
+	}
+	var words [2]uint64
+	for i := range words {
+		for j := 0; j < wordSize; j++ {
+			words[i] |= uint64(b[i*wordSize+j]) << (8 * uint(j))
+		}
+	}
+	return words[0] == 0 && words[1] == 3
+}
+
+func TestCPUProfile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	p := makePlanner()
+	p.session.User = security.RootUser
+
+	d, err := p.CPUProfile(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile := []byte(*d.(*parser.DBytes)); !isCPUProfile(profile) {
+		t.Fatalf("expected a CPU profile, but found %q", profile)
+	}
+
+	if _, err := p.CPUProfile(2 * maxCPUProfileDuration); !testutils.IsError(err,
+		"profile duration 2m0s exceeds the maximum of 1m0s") {
+		t.Fatalf("expected the profile duration to be capped, but found %v", err)
+	}
+
+	// Cancelling the statement stops the collection of the profile.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		p.session.CancelQuery()
+	}()
+	start := timeutil.Now()
+	_, err = p.CPUProfile(maxCPUProfileDuration)
+	if _, ok := err.(*sqlbase.ErrQueryCanceled); !ok {
+		t.Fatalf("expected the profile to be cancelled, but found %v", err)
+	}
+	if elapsed := timeutil.Since(start); elapsed > maxCPUProfileDuration/2 {
+		t.Fatalf("the cancelled profile took %s", elapsed)
+	}
+}
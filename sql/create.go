@@ -17,6 +17,7 @@
 package sql
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 
@@ -25,7 +26,6 @@ import (
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/sql/privilege"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
-	"github.com/cockroachdb/cockroach/util"
 	"github.com/pkg/errors"
 )
 
@@ -34,8 +34,9 @@ type createDatabaseNode struct {
 	n *parser.CreateDatabase
 }
 
-// CreateDatabase creates a database.
-// Privileges: security.RootUser user.
+// CreateDatabase creates a database. The user creating it is granted all the
+// privileges on it.
+// Privileges: security.RootUser user, or a user with the CREATEDB option.
 //   Notes: postgres requires superuser or "CREATEDB".
 //          mysql uses the mysqladmin command.
 func (p *planner) CreateDatabase(n *parser.CreateDatabase) (planNode, error) {
@@ -57,8 +58,19 @@ func (p *planner) CreateDatabase(n *parser.CreateDatabase) (planNode, error) {
 		}
 	}
 
-	if p.session.User != security.RootUser {
-		return nil, errors.Errorf("only %s is allowed to create databases", security.RootUser)
+	// New databases are always empty, which is what copying either of the
+	// postgres template databases results in.
+	if n.Template != "" && !(strings.EqualFold(n.Template, "template0") ||
+		strings.EqualFold(n.Template, "template1")) {
+		return nil, fmt.Errorf("%s is not a supported template", parser.Name(n.Template))
+	}
+
+	// OWNER, LC_COLLATE and LC_CTYPE are emitted by pg_dump but we silently
+	// ignore them: databases have no owner, and strings are always compared
+	// bytewise.
+
+	if err := p.checkCreateDBPrivilege(); err != nil {
+		return nil, err
 	}
 
 	return &createDatabaseNode{p: p, n: n}, nil
@@ -70,11 +82,17 @@ func (n *createDatabaseNode) expandPlan() error {
 
 func (n *createDatabaseNode) Start() error {
 	desc := makeDatabaseDesc(n.n)
+	if user := n.p.session.User; user != security.RootUser {
+		desc.Privileges.Grant(user, privilege.List{privilege.ALL})
+	}
 
 	created, err := n.p.createDescriptor(databaseKey{string(n.n.Name)}, &desc, n.n.IfNotExists)
 	if err != nil {
 		return err
 	}
+	if !created {
+		n.p.notice(NoticeSeverityNotice, "database %q already exists, skipping", string(n.n.Name))
+	}
 	if created {
 		// Log Create Database event. This is an auditable log event and is
 		// recorded in the same transaction as the table descriptor update.
@@ -134,6 +152,9 @@ func (n *createIndexNode) expandPlan() error {
 }
 
 func (n *createIndexNode) Start() error {
+	if err := checkPrimaryKeyChange(n.tableDesc); err != nil {
+		return err
+	}
 	status, i, err := n.tableDesc.FindIndexByName(string(n.n.Name))
 	if err == nil {
 		if status == sqlbase.DescriptorIncomplete {
@@ -146,6 +167,7 @@ func (n *createIndexNode) Start() error {
 			}
 		}
 		if n.n.IfNotExists {
+			n.p.notice(NoticeSeverityNotice, "index %q already exists, skipping", string(n.n.Name))
 			return nil
 		}
 	}
@@ -155,9 +177,29 @@ func (n *createIndexNode) Start() error {
 		Unique:           n.n.Unique,
 		StoreColumnNames: n.n.Storing,
 	}
-	if err := indexDesc.FillColumns(n.n.Columns); err != nil {
+	if n.n.Inverted {
+		indexDesc.Type = sqlbase.IndexDescriptor_INVERTED
+	}
+	cols, err := n.tableDesc.AddIndexExprColumns(n.n.Columns)
+	if err != nil {
+		return err
+	}
+	if n.n.Sharded != nil {
+		cols, indexDesc.ShardBuckets, err = n.tableDesc.AddShardColumn(cols, n.n.Sharded)
+		if err != nil {
+			return err
+		}
+	}
+	if err := indexDesc.FillColumns(cols); err != nil {
 		return err
 	}
+	if n.n.PartitionBy != nil {
+		part, err := sqlbase.MakePartitioning(n.tableDesc, &indexDesc, n.n.PartitionBy)
+		if err != nil {
+			return err
+		}
+		indexDesc.Partitioning = part
+	}
 
 	mutationIdx := len(n.tableDesc.Mutations)
 	n.tableDesc.AddIndexMutation(indexDesc, sqlbase.DescriptorMutation_ADD)
@@ -168,6 +210,11 @@ func (n *createIndexNode) Start() error {
 	if err := n.tableDesc.AllocateIDs(); err != nil {
 		return err
 	}
+	if n.n.Inverted {
+		if err := n.tableDesc.Validate(); err != nil {
+			return err
+		}
+	}
 
 	if n.n.Interleave != nil {
 		index := n.tableDesc.Mutations[mutationIdx].GetIndex()
@@ -223,17 +270,28 @@ type createTableNode struct {
 	p      *planner
 	n      *parser.CreateTable
 	dbDesc *sqlbase.DatabaseDescriptor
+	// viewQuery is the query of the materialized view the table is created
+	// for, if any.
+	viewQuery string
+	// sourcePlan is the plan of the query whose results are the initial rows
+	// of the table, if any.
+	sourcePlan planNode
 }
 
 // CreateTable creates a table.
 // Privileges: CREATE on database.
 //   Notes: postgres/mysql require CREATE on database.
 func (p *planner) CreateTable(n *parser.CreateTable) (planNode, error) {
-	if err := n.Table.NormalizeTableName(p.session.Database); err != nil {
-		return nil, err
+	var dbDesc *sqlbase.DatabaseDescriptor
+	var err error
+	if n.Temporary {
+		dbDesc, err = p.createTempTable(n)
+	} else {
+		if err := n.Table.NormalizeTableName(p.session.Database); err != nil {
+			return nil, err
+		}
+		dbDesc, err = p.mustGetDatabaseDesc(n.Table.Database())
 	}
-
-	dbDesc, err := p.mustGetDatabaseDesc(n.Table.Database())
 	if err != nil {
 		return nil, err
 	}
@@ -242,7 +300,203 @@ func (p *planner) CreateTable(n *parser.CreateTable) (planNode, error) {
 		return nil, err
 	}
 
-	return &createTableNode{p: p, n: n, dbDesc: dbDesc}, nil
+	if err := p.expandLikeTableDefs(n); err != nil {
+		return nil, err
+	}
+
+	var sourcePlan planNode
+	if n.AsSource != nil {
+		if sourcePlan, err = p.makePlan(n.AsSource, false); err != nil {
+			return nil, err
+		}
+		if n.Defs, err = makeTableDefsFromQuery(
+			sourcePlan, n.AsColumnNames, "table", n.Table.Table()); err != nil {
+			return nil, err
+		}
+	}
+
+	return &createTableNode{p: p, n: n, dbDesc: dbDesc, sourcePlan: sourcePlan}, nil
+}
+
+// expandLikeTableDefs replaces the LIKE definitions of a CREATE TABLE
+// statement with the definitions copied from the tables they refer to. No
+// privilege is required on these tables, as for SHOW CREATE TABLE.
+func (p *planner) expandLikeTableDefs(n *parser.CreateTable) error {
+	var defs parser.TableDefs
+	for _, def := range n.Defs {
+		like, ok := def.(*parser.LikeTableDef)
+		if !ok {
+			defs = append(defs, def)
+			continue
+		}
+		desc, err := p.mustGetTableDesc(like.Name)
+		if err != nil {
+			return err
+		}
+		likeDefs, err := makeLikeTableDefs(desc, like.Opts())
+		if err != nil {
+			return err
+		}
+		defs = append(defs, likeDefs...)
+	}
+	n.Defs = defs
+	return nil
+}
+
+// makeLikeTableDefs returns the definitions of the visible columns of a
+// table with their types and nullability, and of the defaults, check
+// constraints and indexes requested by opts. Foreign keys, interleaving and
+// families are not copied, nor are the implicit primary key.
+func makeLikeTableDefs(
+	desc *sqlbase.TableDescriptor, opts parser.LikeTableOpt,
+) (parser.TableDefs, error) {
+	// The definitions are written as a CREATE TABLE statement, which is parsed.
+	var buf bytes.Buffer
+	buf.WriteString("CREATE TABLE like_table (")
+	sep := ""
+	for _, col := range desc.VisibleColumns() {
+		fmt.Fprintf(&buf, "%s%s %s", sep, quoteNames(col.Name), col.Type.SQLString())
+		sep = ", "
+		if !col.Nullable {
+			buf.WriteString(" NOT NULL")
+		}
+		if col.DefaultExpr != nil && opts&parser.LikeTableOptDefaults != 0 {
+			fmt.Fprintf(&buf, " DEFAULT %s", *col.DefaultExpr)
+		}
+		if col.ComputedExpr != nil {
+			fmt.Fprintf(&buf, " AS (%s) %s", *col.ComputedExpr, computedColumnStorage[col.Stored])
+		}
+	}
+	if opts&parser.LikeTableOptIndexes != 0 {
+		indexCols := func(idx sqlbase.IndexDescriptor) (string, error) {
+			cols := make([]string, len(idx.ColumnNames))
+			for i := range idx.ColumnNames {
+				elem, err := indexElem(desc, &idx, i)
+				if err != nil {
+					return "", err
+				}
+				cols[i] = fmt.Sprintf("%s %s", parser.AsString(elem), idx.ColumnDirections[i])
+			}
+			return strings.Join(cols, ", "), nil
+		}
+		if col, err := desc.FindColumnByID(desc.PrimaryIndex.ColumnIDs[0]); err != nil {
+			return nil, err
+		} else if !col.Hidden {
+			cols, err := indexCols(desc.PrimaryIndex)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&buf, "%sCONSTRAINT %s PRIMARY KEY (%s)",
+				sep, quoteNames(desc.PrimaryIndex.Name), cols)
+			sep = ", "
+		}
+		for _, idx := range desc.Indexes {
+			cols, err := indexCols(idx)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&buf, "%s%s%sINDEX %s (%s)",
+				sep, isUnique[idx.Unique], indexTypeName[idx.Type], quoteNames(idx.Name), cols)
+			sep = ", "
+			if len(idx.StoreColumnNames) > 0 {
+				fmt.Fprintf(&buf, " STORING (%s)", quoteNames(idx.StoreColumnNames...))
+			}
+		}
+	}
+	if opts&parser.LikeTableOptConstraints != 0 {
+		for _, check := range desc.Checks {
+			buf.WriteString(sep)
+			sep = ", "
+			if check.Name != "" {
+				fmt.Fprintf(&buf, "CONSTRAINT %s ", quoteNames(check.Name))
+			}
+			fmt.Fprintf(&buf, "CHECK (%s)", check.Expr)
+		}
+	}
+	buf.WriteString(")")
+
+	stmt, err := parser.ParseOneTraditional(buf.String())
+	if err != nil {
+		return nil, err
+	}
+	return stmt.(*parser.CreateTable).Defs, nil
+}
+
+// makeTableDefsFromQuery returns the definitions of the columns of a table
+// holding the results of a query, which are named after the result columns
+// of the query unless colNames is set. The kind and name of the table are
+// used in the errors.
+func makeTableDefsFromQuery(
+	plan planNode, colNames parser.NameList, kind, name string,
+) (parser.TableDefs, error) {
+	cols := plan.Columns()
+	if len(colNames) > 0 && len(colNames) != len(cols) {
+		return nil, fmt.Errorf("%s %q has %d columns but the query returns %d",
+			kind, name, len(colNames), len(cols))
+	}
+	var defs parser.TableDefs
+	for i, col := range cols {
+		colName := parser.Name(col.Name)
+		if len(colNames) > 0 {
+			colName = colNames[i]
+		}
+		typ, err := parser.DatumTypeToColumnType(col.Typ)
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine the type of column %q of %s %q",
+				string(colName), kind, name)
+		}
+		defs = append(defs, &parser.ColumnTableDef{Name: colName, Type: typ})
+	}
+	return defs, nil
+}
+
+// insertQueryRows writes the results of a query to a table created by the
+// transaction, whose columns are the result columns of the query followed by
+// columns which have a default value, such as the implicit primary key. The
+// rows are written directly rather than through an INSERT statement, as the
+// table can't be leased before the transaction commits.
+func (p *planner) insertQueryRows(tableDesc *sqlbase.TableDescriptor, plan planNode) error {
+	if err := plan.Start(); err != nil {
+		return err
+	}
+
+	defaultExprs, err := makeDefaultExprs(tableDesc.Columns, &p.parser, &p.evalCtx)
+	if err != nil {
+		return err
+	}
+	ri, err := makeRowInserter(p.txn, tableDesc, nil, tableDesc.Columns, skipFKs)
+	if err != nil {
+		return err
+	}
+	ti := tableInserter{ri: ri}
+	if err := ti.init(p.txn); err != nil {
+		return err
+	}
+
+	numQueryCols := len(plan.Columns())
+	next, err := plan.Next()
+	for ; next; next, err = plan.Next() {
+		if err := p.checkCancelled(); err != nil {
+			return err
+		}
+		row := make(parser.DTuple, len(tableDesc.Columns))
+		copy(row, plan.Values())
+		for i := numQueryCols; i < len(row); i++ {
+			row[i] = parser.DNull
+			if defaultExprs != nil {
+				if row[i], err = defaultExprs[i].Eval(&p.evalCtx); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := ti.row(row); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return ti.finalize()
 }
 
 func hoistConstraints(n *parser.CreateTable) {
@@ -272,6 +526,17 @@ func (n *createTableNode) Start() error {
 	}
 	// Inherit permissions from the database descriptor.
 	desc.Privileges = n.dbDesc.GetPrivileges()
+	desc.ViewQuery = n.viewQuery
+
+	var colDefs []*parser.ColumnTableDef
+	for _, def := range n.n.Defs {
+		if d, ok := def.(*parser.ColumnTableDef); ok {
+			colDefs = append(colDefs, d)
+		}
+	}
+	if err := n.p.addSerialSequences(&desc, colDefs...); err != nil {
+		return err
+	}
 
 	if len(desc.PrimaryIndex.ColumnNames) == 0 {
 		// Ensure a Primary Key exists.
@@ -296,6 +561,28 @@ func (n *createTableNode) Start() error {
 		}
 	}
 
+	// Foreign key checks on the referenced table look up the referencing rows
+	// by the referencing column, so it needs to be the first column of an
+	// index. Add one for the columns which have none.
+	for _, def := range n.n.Defs {
+		switch d := def.(type) {
+		case *parser.ColumnTableDef:
+			if d.References.Table != nil {
+				if err := addFKIndex(&desc, string(d.Name)); err != nil {
+					return err
+				}
+			}
+		case *parser.ForeignKeyConstraintTableDef:
+			from, _, err := singleColumnFK(d)
+			if err != nil {
+				return err
+			}
+			if err := addFKIndex(&desc, string(from)); err != nil {
+				return err
+			}
+		}
+	}
+
 	if err := desc.AllocateIDs(); err != nil {
 		return err
 	}
@@ -310,15 +597,37 @@ func (n *createTableNode) Start() error {
 	// been allocated since the FKs will reference those IDs.
 	var fkTargets []fkTargetUpdate
 	for _, def := range n.n.Defs {
-		if col, ok := def.(*parser.ColumnTableDef); ok {
-			if col.References.Table != nil {
-				modified, err := n.resolveColFK(&desc, col.Name, col.References.Table, col.References.Col, col.References.ConstraintName)
-				if err != nil {
-					return err
-				}
-				fkTargets = append(fkTargets, modified)
+		var from, to, name parser.Name
+		var table *parser.QualifiedName
+		var actions parser.ReferenceActions
+		var timing parser.ConstraintTiming
+		switch d := def.(type) {
+		case *parser.ColumnTableDef:
+			if d.References.Table == nil {
+				continue
+			}
+			from, to, name, table = d.Name, d.References.Col, d.References.ConstraintName, d.References.Table
+			actions, timing = d.References.Actions, d.References.Timing
+		case *parser.ForeignKeyConstraintTableDef:
+			var err error
+			if from, to, err = singleColumnFK(d); err != nil {
+				return err
 			}
+			name, table, actions, timing = d.Name, d.Table, d.Actions, d.Timing
+		default:
+			continue
 		}
+		src, err := desc.FindActiveColumnByName(string(from))
+		if err != nil {
+			return err
+		}
+		modified, err := n.p.resolveColFK(&desc, n.n.Table.Database(), n.dbDesc.ID,
+			src, table, to, name, actions, timing)
+		if err != nil {
+			return err
+		}
+		fkTargets = append(fkTargets, modified)
+		desc.State = sqlbase.TableDescriptor_ADD
 	}
 
 	// We need to validate again after adding the FKs, but the desc still doesn't
@@ -338,6 +647,14 @@ func (n *createTableNode) Start() error {
 	if err != nil {
 		return err
 	}
+	if !created {
+		n.p.notice(NoticeSeverityNotice, "table %q already exists, skipping", n.n.Table.Table())
+		return nil
+	}
+
+	if err := n.p.writeSequenceOwners(&desc); err != nil {
+		return err
+	}
 
 	if err := n.finalizeFKs(&desc, fkTargets); err != nil {
 		return err
@@ -351,6 +668,12 @@ func (n *createTableNode) Start() error {
 		}
 	}
 
+	if n.sourcePlan != nil {
+		if err := n.p.insertQueryRows(&desc, n.sourcePlan); err != nil {
+			return err
+		}
+	}
+
 	if created {
 		// Log Create Table event. This is an auditable log event and is
 		// recorded in the same transaction as the table descriptor update.
@@ -383,6 +706,22 @@ func (n *createTableNode) ExplainPlan(v bool) (string, string, []planNode) {
 	return "create table", "", nil
 }
 
+// addFKIndex adds an index on the column named col to desc unless it is
+// already the first column of one of its indexes.
+func addFKIndex(desc *sqlbase.TableDescriptor, col string) error {
+	normName := sqlbase.NormalizeName(col)
+	for _, idx := range append([]sqlbase.IndexDescriptor{desc.PrimaryIndex}, desc.Indexes...) {
+		if idx.Type == sqlbase.IndexDescriptor_FORWARD && len(idx.ColumnNames) > 0 &&
+			sqlbase.NormalizeName(idx.ColumnNames[0]) == normName {
+			return nil
+		}
+	}
+	return desc.AddIndex(sqlbase.IndexDescriptor{
+		ColumnNames:      []string{col},
+		ColumnDirections: []sqlbase.IndexDescriptor_Direction{sqlbase.IndexDescriptor_ASC},
+	}, false)
+}
+
 // FK resolution runs before the referencing (child) table is created, meaning
 // its ID, which needs to be noted on the referenced tables, is not yet
 // determined. This struct accumulates the information needed to edit a
@@ -393,29 +732,49 @@ type fkTargetUpdate struct {
 	targetIdx sqlbase.IndexID          // ID of target (referenced) index
 }
 
-func (n *createTableNode) resolveColFK(
+// resolveColFK resolves the table and index referenced by the foreign key on
+// column src of tbl, a table of database (with ID dbID), and sets the foreign
+// key on the index of tbl which has src as its first column.
+func (p *planner) resolveColFK(
 	tbl *sqlbase.TableDescriptor,
-	fromCol parser.Name,
+	database string,
+	dbID sqlbase.ID,
+	src sqlbase.ColumnDescriptor,
 	targetTable *parser.QualifiedName,
 	targetColName parser.Name,
 	constraintName parser.Name,
+	actions parser.ReferenceActions,
+	timing parser.ConstraintTiming,
 ) (fkTargetUpdate, error) {
 	var ret fkTargetUpdate
-	src, err := tbl.FindActiveColumnByName(string(fromCol))
-	if err != nil {
+	fromCol := parser.Name(src.Name)
+
+	// An unqualified name refers to a table in the database of the
+	// referencing table, which need not be the session's database.
+	if err := targetTable.NormalizeTableName(database); err != nil {
 		return ret, err
 	}
-
-	target, err := n.p.getTableDesc(targetTable)
+	target, err := p.getTableDesc(targetTable)
 	if err != nil {
 		return ret, err
 	}
 	if target == nil {
-		if targetTable.String() == tbl.Name {
+		if targetTable.Database() == database && targetTable.Table() == tbl.Name {
 			target = tbl
 		} else {
 			return ret, fmt.Errorf("referenced table %q not found", targetTable.String())
 		}
+	} else if target.ID == tbl.ID {
+		target = tbl
+	} else if target.ParentID != dbID {
+		enabled, err := p.getClusterSetting(crossDatabaseFKsSetting)
+		if err != nil {
+			return ret, err
+		}
+		if !enabled {
+			return ret, fmt.Errorf("foreign key references table %q in another database, "+
+				"which is disabled (see cluster setting %s)", targetTable.String(), crossDatabaseFKsSetting)
+		}
 	}
 	ret.target = target
 	// If a column isn't specified, attempt to default to PK.
@@ -451,38 +810,102 @@ func (n *createTableNode) resolveColFK(
 		}
 	}
 	if !found {
-		return ret, fmt.Errorf("foreign key requires a unique index on %s.%s", targetTable.String(), targetCol.Name)
+		return ret, fmt.Errorf("foreign key requires a unique index on %s.%s: "+
+			"neither the primary key nor a unique index of %q starts with column %q",
+			targetTable.String(), targetCol.Name, target.Name, targetCol.Name)
 	}
 
 	if constraintName == "" {
 		constraintName = parser.Name(fmt.Sprintf("fk_%s_ref_%s_%s", fromCol, target.Name, targetColName))
 	}
 
-	ref := &sqlbase.ForeignKeyReference{Table: target.ID, Index: ret.targetIdx, Name: string(constraintName)}
+	ref := &sqlbase.ForeignKeyReference{
+		Table:             target.ID,
+		Index:             ret.targetIdx,
+		Name:              string(constraintName),
+		Deferrable:        timing.Deferrable,
+		InitiallyDeferred: timing.InitiallyDeferred,
+	}
+	if ref.OnDelete, err = foreignKeyAction(actions.Delete, "DELETE", constraintName, src); err != nil {
+		return ret, err
+	}
+	if ref.OnUpdate, err = foreignKeyAction(actions.Update, "UPDATE", constraintName, src); err != nil {
+		return ret, err
+	}
 
-	found = false
+	var srcIdx *sqlbase.IndexDescriptor
 	if tbl.PrimaryIndex.ColumnIDs[0] == src.ID {
-		tbl.PrimaryIndex.ForeignKey = ref
-		ret.srcIdx = tbl.PrimaryIndex.ID
-		found = true
+		srcIdx = &tbl.PrimaryIndex
 	} else {
-		for i, idx := range tbl.Indexes {
-			if tbl.Indexes[i].ColumnIDs[0] == src.ID {
-				tbl.Indexes[i].ForeignKey = ref
-				ret.srcIdx = idx.ID
-				found = true
+		for i := range tbl.Indexes {
+			if tbl.Indexes[i].ColumnIDs[0] == src.ID &&
+				tbl.Indexes[i].Type == sqlbase.IndexDescriptor_FORWARD {
+				srcIdx = &tbl.Indexes[i]
 				break
 			}
 		}
 	}
-	if !found {
-		return ret, fmt.Errorf("foreign key column %q must be the prefix of an index", src.Name)
+	if srcIdx == nil {
+		// The index may be added along with the column.
+		for _, m := range tbl.Mutations {
+			if idx := m.GetIndex(); idx != nil && m.Direction == sqlbase.DescriptorMutation_ADD &&
+				idx.ColumnIDs[0] == src.ID && idx.Type == sqlbase.IndexDescriptor_FORWARD {
+				srcIdx = idx
+				break
+			}
+		}
 	}
-
-	tbl.State = sqlbase.TableDescriptor_ADD
+	if srcIdx == nil {
+		return ret, fmt.Errorf("foreign key column %q must be the prefix of an index: "+
+			"no index of %q starts with column %q", src.Name, tbl.Name, src.Name)
+	}
+	if srcIdx.ForeignKey != nil {
+		return ret, fmt.Errorf("index %q already has foreign key %q", srcIdx.Name, srcIdx.ForeignKey.Name)
+	}
+	srcIdx.ForeignKey = ref
+	ret.srcIdx = srcIdx.ID
 	return ret, nil
 }
 
+// foreignKeyAction returns the referential action of the foreign key on
+// column src run on the given event, checking that the column can be set as
+// the action requires.
+func foreignKeyAction(
+	a parser.ReferenceAction, event string, constraintName parser.Name, src sqlbase.ColumnDescriptor,
+) (sqlbase.ForeignKeyReference_Action, error) {
+	switch a {
+	case parser.Cascade:
+		return sqlbase.ForeignKeyReference_CASCADE, nil
+	case parser.SetNull:
+		if !src.Nullable {
+			return 0, fmt.Errorf("cannot add ON %s SET NULL to foreign key %q: "+
+				"column %q is NOT NULL", event, constraintName, src.Name)
+		}
+		return sqlbase.ForeignKeyReference_SET_NULL, nil
+	case parser.SetDefault:
+		if !src.Nullable && src.DefaultExpr == nil {
+			return 0, fmt.Errorf("cannot add ON %s SET DEFAULT to foreign key %q: "+
+				"column %q is NOT NULL and has no default value", event, constraintName, src.Name)
+		}
+		return sqlbase.ForeignKeyReference_SET_DEFAULT, nil
+	}
+	return sqlbase.ForeignKeyReference_RESTRICT, nil
+}
+
+// singleColumnFK returns the referencing column of a FOREIGN KEY constraint
+// and the referenced one, which is empty if the constraint references the
+// primary key. Foreign keys with several columns are not supported.
+func singleColumnFK(d *parser.ForeignKeyConstraintTableDef) (parser.Name, parser.Name, error) {
+	if len(d.FromCols) != 1 || len(d.ToCols) > 1 {
+		return "", "", errors.Errorf("foreign keys with multiple columns are not supported")
+	}
+	var to parser.Name
+	if len(d.ToCols) == 1 {
+		to = parser.Name(d.ToCols[0])
+	}
+	return parser.Name(d.FromCols[0]), to, nil
+}
+
 func (p *planner) saveNonmutationAndNotify(td *sqlbase.TableDescriptor) error {
 	if err := td.SetUpVersion(); err != nil {
 		return err
@@ -502,9 +925,12 @@ func (p *planner) saveNonmutationAndNotify(td *sqlbase.TableDescriptor) error {
 func (p *planner) addInterleave(
 	desc *sqlbase.TableDescriptor, index *sqlbase.IndexDescriptor, interleave *parser.InterleaveDef,
 ) error {
-	if interleave.DropBehavior != parser.DropDefault {
-		return util.UnimplementedWithIssueErrorf(
-			7854, "unsupported shorthand %s", interleave.DropBehavior)
+	var dropBehavior sqlbase.InterleaveDescriptor_DropBehavior
+	switch interleave.DropBehavior {
+	case parser.DropCascade:
+		dropBehavior = sqlbase.InterleaveDescriptor_CASCADE
+	case parser.DropRestrict:
+		dropBehavior = sqlbase.InterleaveDescriptor_RESTRICT
 	}
 
 	parentTable, err := p.mustGetTableDesc(interleave.Parent)
@@ -548,12 +974,32 @@ func (p *planner) addInterleave(
 	for _, ancestor := range ancestorPrefix {
 		intl.SharedPrefixLen -= uint32(ancestor.SharedPrefixLen)
 	}
-	index.Interleave = sqlbase.InterleaveDescriptor{Ancestors: append(ancestorPrefix, intl)}
+	index.Interleave = sqlbase.InterleaveDescriptor{
+		Ancestors:    append(ancestorPrefix, intl),
+		DropBehavior: dropBehavior,
+	}
 
 	return nil
 }
 
 func (n *createTableNode) finalizeFKs(desc *sqlbase.TableDescriptor, fkTargets []fkTargetUpdate) error {
+	if err := n.p.addFKBackReferences(desc, fkTargets); err != nil {
+		return err
+	}
+
+	if desc.State == sqlbase.TableDescriptor_ADD {
+		desc.State = sqlbase.TableDescriptor_PUBLIC
+
+		if err := n.p.saveNonmutationAndNotify(desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFKBackReferences notes the foreign keys of desc resolved by resolveColFK
+// on the referenced tables, saving those that are not desc itself.
+func (p *planner) addFKBackReferences(desc *sqlbase.TableDescriptor, fkTargets []fkTargetUpdate) error {
 	for _, t := range fkTargets {
 		targetIdx, err := t.target.FindIndexByID(t.targetIdx)
 		if err != nil {
@@ -572,15 +1018,7 @@ func (n *createTableNode) finalizeFKs(desc *sqlbase.TableDescriptor, fkTargets [
 		}
 
 		// TODO(dt): Only save each referenced table once.
-		if err := n.p.saveNonmutationAndNotify(t.target); err != nil {
-			return err
-		}
-	}
-
-	if desc.State == sqlbase.TableDescriptor_ADD {
-		desc.State = sqlbase.TableDescriptor_PUBLIC
-
-		if err := n.p.saveNonmutationAndNotify(desc); err != nil {
+		if err := p.saveNonmutationAndNotify(t.target); err != nil {
 			return err
 		}
 	}
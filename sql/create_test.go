@@ -17,6 +17,7 @@
 package sql_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/keys"
@@ -146,3 +147,67 @@ func TestDatabaseDescriptor(t *testing.T) {
 		t.Fatal("key is missing")
 	}
 }
+
+func TestCrossDatabaseFKs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	params, _ := createTestServerParams()
+	s, sqlDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop()
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE a;
+CREATE DATABASE b;
+CREATE TABLE a.parent (id INT PRIMARY KEY);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cross-database foreign keys are disabled by default.
+	if _, err := sqlDB.Exec(
+		`CREATE TABLE b.child (id INT PRIMARY KEY, p INT REFERENCES a.parent)`,
+	); !testutils.IsError(err, `references table "parent" in another database`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err := sqlDB.Exec(`
+SET CLUSTER SETTING sql.cross_db_fks.enabled = true;
+CREATE TABLE b.child (id INT PRIMARY KEY, p INT REFERENCES a.parent, INDEX (p));
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	var enabled bool
+	if err := sqlDB.QueryRow(
+		`SHOW CLUSTER SETTING sql.cross_db_fks.enabled`,
+	).Scan(&enabled); err != nil {
+		t.Fatal(err)
+	}
+	if !enabled {
+		t.Fatal("expected cross-database foreign keys to be enabled")
+	}
+
+	var name, create string
+	if err := sqlDB.QueryRow(`SHOW CREATE TABLE b.child`).Scan(&name, &create); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(create, "REFERENCES a.parent (id)") {
+		t.Fatalf("expected qualified reference, got %s", create)
+	}
+
+	if _, err := sqlDB.Exec(`INSERT INTO b.child VALUES (1, 1)`); !testutils.IsError(err, "foreign key violation") {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err := sqlDB.Exec(
+		`DROP DATABASE a`,
+	); !testutils.IsError(err, `"parent" is referenced by foreign key from table "child"`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err := sqlDB.Exec(`
+DROP TABLE b.child;
+DROP DATABASE a;
+`); err != nil {
+		t.Fatal(err)
+	}
+}
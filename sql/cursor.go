@@ -0,0 +1,187 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+)
+
+// A Cursor returns the remaining rows of a statement executed by
+// ExecutePortal on demand, keeping the plan of the statement open in
+// between.
+//
+// The plan shares the session's planner, so it is read to completion before
+// the session prepares or executes another statement (see
+// Session.materializeCursors); the cursor then returns the buffered rows.
+type Cursor struct {
+	e       *Executor
+	session *Session
+	// plan is nil once all its rows were read.
+	plan planNode
+	// stmtTimestamp is the timestamp of the statement, restored in the
+	// planner's evaluation context while reading rows.
+	stmtTimestamp time.Time
+	// rows are the rows read from the plan but not yet returned. The plan is
+	// read one row ahead, so that a cursor which has no rows left is known to
+	// be done.
+	rows  []ResultRow
+	alloc resultRowAlloc
+	// err is the error encountered while reading the plan, if any.
+	err error
+}
+
+// Fetch returns up to limit of the remaining rows of the cursor, or all of
+// them if limit is 0, and whether rows remain afterwards. An error reading
+// the rows aborts the transaction of the cursor.
+func (c *Cursor) Fetch(limit int) ([]ResultRow, bool, error) {
+	if c.err != nil {
+		return nil, false, c.err
+	}
+	if c.plan != nil {
+		// A cancellation requested before this request only applied to the
+		// previous one.
+		atomic.StoreInt32(&c.session.queryCancelled, 0)
+		n := 0
+		if limit != 0 {
+			n = limit + 1
+		}
+		if err := c.fill(n); err != nil {
+			return nil, false, err
+		}
+	}
+	rows := c.rows
+	if limit != 0 && len(rows) > limit {
+		rows = rows[:limit:limit]
+	}
+	c.rows = c.rows[len(rows):]
+	return rows, len(c.rows) > 0, nil
+}
+
+// Close releases the plan of the cursor. The cursor returns no more rows.
+func (c *Cursor) Close() {
+	c.plan = nil
+	c.rows = nil
+	c.session.removeCursor(c)
+}
+
+// fill reads rows from the plan until the cursor holds n rows, or until the
+// plan has no more rows if n is 0.
+func (c *Cursor) fill(n int) error {
+	p := &c.session.planner
+	txnState := &c.session.TxnState
+	p.setTxn(txnState.txn)
+	p.evalCtx.SetTxnTimestamp(txnState.sqlTimestamp)
+	p.evalCtx.SetStmtTimestamp(c.stmtTimestamp)
+	defer p.resetTxn()
+
+	rows, done, err := readRows(p, c.plan, c.rows, n, &c.alloc)
+	c.rows = rows
+	if err != nil {
+		c.err = err
+		c.Close()
+		txnState.updateStateAndCleanupOnErr(err, c.e)
+		return err
+	}
+	if done {
+		c.session.removeCursor(c)
+		c.plan = nil
+	}
+	return nil
+}
+
+// materializeCursors reads the plans of the open cursors of the session to
+// completion, so that the planner can be used by another statement. An error
+// is returned by the next Fetch of its cursor.
+func (s *Session) materializeCursors() {
+	// A cancellation requested before this request only applied to the
+	// previous one.
+	atomic.StoreInt32(&s.queryCancelled, 0)
+	for len(s.cursors) > 0 {
+		// fill removes the cursor from the list.
+		_ = s.cursors[0].fill(0)
+	}
+}
+
+// removeCursor removes a cursor from the open cursors of the session.
+func (s *Session) removeCursor(c *Cursor) {
+	for i := range s.cursors {
+		if s.cursors[i] == c {
+			s.cursors = append(s.cursors[:i], s.cursors[i+1:]...)
+			return
+		}
+	}
+}
+
+// resultRowAlloc allocates the backing storage for the ResultRow.Values
+// slices in chunks.
+type resultRowAlloc struct {
+	values    []parser.Datum
+	chunkSize int
+}
+
+// newRow returns a ResultRow holding a copy of values, which the plan may
+// reuse for its next row.
+func (a *resultRowAlloc) newRow(values parser.DTuple) (ResultRow, error) {
+	const maxChunkSize = 64 // Arbitrary, could use tuning.
+	n := len(values)
+	if len(a.values) < n {
+		if a.chunkSize == 0 {
+			a.chunkSize = 4 // Arbitrary as well.
+		}
+		a.values = make([]parser.Datum, n*a.chunkSize)
+		if a.chunkSize < maxChunkSize {
+			a.chunkSize *= 2
+		}
+	}
+	row := ResultRow{Values: a.values[:0:n]}
+	a.values = a.values[n:]
+
+	for _, val := range values {
+		if err := checkResultDatum(val); err != nil {
+			return ResultRow{}, err
+		}
+		row.Values = append(row.Values, val)
+	}
+	return row, nil
+}
+
+// readRows appends the rows of a started plan to rows, until rows holds n of
+// them or, if n is 0, until the plan has no more rows. It returns whether the
+// plan has no more rows.
+func readRows(
+	p *planner, plan planNode, rows []ResultRow, n int, alloc *resultRowAlloc,
+) ([]ResultRow, bool, error) {
+	for n == 0 || len(rows) < n {
+		next, err := plan.Next()
+		if err != nil {
+			return rows, false, err
+		}
+		if !next {
+			return rows, true, nil
+		}
+		if err := p.checkCancelled(); err != nil {
+			return rows, false, err
+		}
+		row, err := alloc.newRow(plan.Values())
+		if err != nil {
+			return rows, false, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, false, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestCursor verifies that the rows of a portal executed with a row limit in
+// an explicit transaction are read as they are fetched, and that its plan is
+// read to completion before another statement is executed.
+func TestCursor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	e := s.Executor().(*Executor)
+	session := NewSession(SessionArgs{User: security.RootUser}, e, nil)
+	defer session.Finish()
+	ctx := context.Background()
+
+	exec := func(sql string) {
+		for _, res := range e.ExecuteStatements(ctx, session, sql, nil).ResultList {
+			if res.Err != nil {
+				t.Fatal(res.Err)
+			}
+		}
+	}
+	checkRows := func(rows []ResultRow, expected ...int) {
+		if len(rows) != len(expected) {
+			t.Fatalf("expected %d rows, got %d", len(expected), len(rows))
+		}
+		for i, row := range rows {
+			if k := int(*row.Values[0].(*parser.DInt)); k != expected[i] {
+				t.Fatalf("expected row %d to be %d, got %d", i, expected[i], k)
+			}
+		}
+	}
+	executePortal := func(limit int) Result {
+		res := e.ExecutePortal(ctx, session, `SELECT k FROM t.kv`, nil, limit).ResultList[0]
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+		return res
+	}
+
+	exec(`
+CREATE DATABASE t;
+CREATE TABLE t.kv (k INT PRIMARY KEY);
+INSERT INTO t.kv VALUES (0), (1), (2), (3), (4);
+`)
+
+	// Outside of a transaction, all the rows are returned.
+	res := executePortal(2)
+	if res.Cursor != nil {
+		t.Fatal("expected no cursor outside of a transaction")
+	}
+	checkRows(res.Rows, 0, 1, 2, 3, 4)
+
+	exec(`BEGIN`)
+	res = executePortal(2)
+	checkRows(res.Rows, 0, 1)
+	c := res.Cursor
+	if c == nil {
+		t.Fatal("expected a cursor")
+	}
+	// Only the row telling that rows remain was read ahead.
+	if len(c.rows) != 1 || c.plan == nil {
+		t.Fatalf("expected an open plan and 1 row read ahead, got %d rows", len(c.rows))
+	}
+	rows, more, err := c.Fetch(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(rows, 2, 3)
+	if !more {
+		t.Fatal("expected more rows")
+	}
+
+	// Another statement reads the plan to completion.
+	exec(`SELECT 1`)
+	if c.plan != nil || len(session.cursors) != 0 {
+		t.Fatal("expected the plan of the cursor to be read to completion")
+	}
+	rows, more, err = c.Fetch(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(rows, 4)
+	if more {
+		t.Fatal("expected no more rows")
+	}
+	exec(`COMMIT`)
+
+	// A closed cursor is not read when another statement is executed.
+	exec(`BEGIN`)
+	res = executePortal(1)
+	res.Cursor.Close()
+	if len(session.cursors) != 0 {
+		t.Fatal("expected the cursor to be removed from the session")
+	}
+	exec(`COMMIT`)
+}
@@ -197,12 +197,27 @@ func (p *planner) getDataSource(
 ) (planDataSource, error) {
 	switch t := src.(type) {
 	case *parser.QualifiedName:
+		// The tables of crdb_internal are generated in memory.
+		virtual, err := p.getVirtualTable(t)
+		if err != nil {
+			return planDataSource{}, err
+		}
+		if virtual != nil {
+			return planDataSource{
+				info: newSourceInfoForSingleTable(t.Table(), virtual.Columns()),
+				plan: virtual,
+			}, nil
+		}
+
 		// Usual case: a table.
 		scan := p.Scan()
 		tableName, err := scan.initTable(p, t, hints, scanVisibility)
 		if err != nil {
 			return planDataSource{}, err
 		}
+		if err := scan.initPolicies(p); err != nil {
+			return planDataSource{}, err
+		}
 
 		return planDataSource{
 			info: newSourceInfoForSingleTable(tableName, scan.Columns()),
@@ -295,7 +310,9 @@ func (src *dataSourceInfo) expandStar(
 		col := src.sourceColumns[idx]
 		if !col.hidden {
 			qval := qvals.getQVal(columnRef{src, idx})
-			columns = append(columns, ResultColumn{Name: col.Name, Typ: qval.datum})
+			columns = append(columns, ResultColumn{
+				Name: col.Name, Typ: qval.datum, TypeModifier: col.TypeModifier,
+			})
 			exprs = append(exprs, qval)
 		}
 	}
@@ -436,7 +453,7 @@ func (sources multiSourceInfo) findColumn(
 	}
 
 	if colIdx == invalidColIdx {
-		return nil, invalidColIdx, fmt.Errorf("qualified name \"%s\" not found", qname)
+		return nil, invalidColIdx, sqlbase.NewUnresolvedNameError(qname.String())
 	}
 
 	return info, colIdx, nil
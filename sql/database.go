@@ -69,6 +69,15 @@ func (s *databaseCache) setID(name string, id sqlbase.ID) {
 	s.databases[name] = id
 }
 
+func (s *databaseCache) deleteID(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.databases, name)
+}
+
 func makeDatabaseDesc(p *parser.CreateDatabase) sqlbase.DatabaseDescriptor {
 	return sqlbase.DatabaseDescriptor{
 		Name:       string(p.Name),
@@ -76,6 +85,23 @@ func makeDatabaseDesc(p *parser.CreateDatabase) sqlbase.DatabaseDescriptor {
 	}
 }
 
+// getDatabaseDescFromID retrieves the database descriptor for the database
+// ID passed in using an existing txn. Returns an error if the descriptor
+// doesn't exist or if it exists and is not a database.
+func getDatabaseDescFromID(txn *client.Txn, id sqlbase.ID) (*sqlbase.DatabaseDescriptor, error) {
+	desc := &sqlbase.Descriptor{}
+	descKey := sqlbase.MakeDescMetadataKey(id)
+
+	if err := txn.GetProto(descKey, desc); err != nil {
+		return nil, err
+	}
+	db := desc.GetDatabase()
+	if db == nil {
+		return nil, errDescriptorNotFound
+	}
+	return db, nil
+}
+
 // getKeysForDatabaseDescriptor retrieves the KV keys corresponding to
 // the zone, name and descriptor of a database.
 func getKeysForDatabaseDescriptor(
@@ -0,0 +1,217 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/metric"
+)
+
+// databaseMetricsPrefix is the prefix under which the per-database metrics
+// are registered in the executor's registry. The metrics of database "foo"
+// are exported as "db.foo.<metric>", e.g. "db.foo.select.count", and those
+// of its table "bar" as "db.foo.table.bar.<metric>".
+const databaseMetricsPrefix = "db."
+
+// otherDatabasesMetricsPrefix is the prefix of the metrics aggregating the
+// statements against the databases beyond maxDatabaseMetrics.
+const otherDatabasesMetricsPrefix = "otherdbs."
+
+const (
+	// maxDatabaseMetrics is the number of databases whose metrics are broken
+	// down, which bounds the number of metrics exported by a node.
+	maxDatabaseMetrics = 100
+	// maxTableMetrics is the number of tables, over all databases, whose
+	// metrics are broken down. The statements against further tables are only
+	// counted in the metrics of their database.
+	maxTableMetrics = 1000
+)
+
+// stmtMetrics holds the statement counts and latencies attributed to a
+// database or a table.
+type stmtMetrics struct {
+	latency     metric.Histograms
+	queryCount  *metric.Counter
+	selectCount *metric.Counter
+	updateCount *metric.Counter
+	insertCount *metric.Counter
+	deleteCount *metric.Counter
+	ddlCount    *metric.Counter
+	miscCount   *metric.Counter
+}
+
+func makeStmtMetrics(registry *metric.Registry) *stmtMetrics {
+	return &stmtMetrics{
+		latency:     registry.Latency("latency"),
+		queryCount:  registry.Counter("query.count"),
+		selectCount: registry.Counter("select.count"),
+		updateCount: registry.Counter("update.count"),
+		insertCount: registry.Counter("insert.count"),
+		deleteCount: registry.Counter("delete.count"),
+		ddlCount:    registry.Counter("ddl.count"),
+		miscCount:   registry.Counter("misc.count"),
+	}
+}
+
+// record counts a statement which took the given duration.
+func (sm *stmtMetrics) record(stmt parser.Statement, latency time.Duration) {
+	sm.queryCount.Inc(1)
+	sm.latency.RecordValue(latency.Nanoseconds())
+	switch stmt.(type) {
+	case *parser.Select:
+		sm.selectCount.Inc(1)
+	case *parser.Update:
+		sm.updateCount.Inc(1)
+	case *parser.Insert:
+		sm.insertCount.Inc(1)
+	case *parser.Delete:
+		sm.deleteCount.Inc(1)
+	default:
+		if stmt.StatementType() == parser.DDL {
+			sm.ddlCount.Inc(1)
+		} else {
+			sm.miscCount.Inc(1)
+		}
+	}
+}
+
+// databaseMetrics holds the metrics of a database and of its tables.
+type databaseMetrics struct {
+	*stmtMetrics
+	registry *metric.Registry
+	tables   map[string]*stmtMetrics
+}
+
+// databaseMetricsMap lazily creates the per-database and per-table metrics
+// as statements against new databases and tables are executed.
+type databaseMetricsMap struct {
+	mu        sync.Mutex
+	databases map[string]*databaseMetrics
+	numTables int
+	// other aggregates the statements against the databases beyond
+	// maxDatabaseMetrics.
+	other *stmtMetrics
+}
+
+// metricsFormat returns the registry format string of the metrics of the
+// database or table with the given name. The format string is passed through
+// fmt.Sprintf, so any '%' in the name needs to be escaped.
+func metricsFormat(prefix, name string) string {
+	return prefix + strings.Replace(name, "%", "%%", -1) + ".%s"
+}
+
+// get returns the metrics of the given database and of the given table,
+// creating and registering them in registry if necessary. The metrics of the
+// table are nil if table is empty or if the tables are beyond
+// maxTableMetrics. The databases beyond maxDatabaseMetrics share aggregated
+// metrics, and have no table metrics.
+func (m *databaseMetricsMap) get(
+	registry *metric.Registry, database, table string,
+) (*stmtMetrics, *stmtMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dm, ok := m.databases[database]
+	if !ok {
+		if len(m.databases) >= maxDatabaseMetrics {
+			if m.other == nil {
+				otherRegistry := metric.NewRegistry()
+				m.other = makeStmtMetrics(otherRegistry)
+				registry.MustAdd(otherDatabasesMetricsPrefix+"%s", otherRegistry)
+			}
+			return m.other, nil
+		}
+		if m.databases == nil {
+			m.databases = make(map[string]*databaseMetrics)
+		}
+		dbRegistry := metric.NewRegistry()
+		dm = &databaseMetrics{
+			stmtMetrics: makeStmtMetrics(dbRegistry),
+			registry:    dbRegistry,
+			tables:      make(map[string]*stmtMetrics),
+		}
+		registry.MustAdd(metricsFormat(databaseMetricsPrefix, database), dbRegistry)
+		m.databases[database] = dm
+	}
+	if table == "" {
+		return dm.stmtMetrics, nil
+	}
+	tm, ok := dm.tables[table]
+	if !ok && m.numTables < maxTableMetrics {
+		tableRegistry := metric.NewRegistry()
+		tm = makeStmtMetrics(tableRegistry)
+		dm.registry.MustAdd(metricsFormat("table.", table), tableRegistry)
+		dm.tables[table] = tm
+		m.numTables++
+	}
+	return dm.stmtMetrics, tm
+}
+
+// stmtTable returns the database and the table a statement is attributed to
+// for the purpose of per-database and per-table metrics. Data modification
+// statements are attributed to their target table, and SELECT statements
+// to the table they read if there is a single one; everything else is
+// attributed to the session's current database and no table. An empty
+// database is returned if no database can be determined.
+func stmtTable(stmt parser.Statement, sessionDatabase string) (database, table string) {
+	var tableExpr parser.TableExpr
+	switch s := stmt.(type) {
+	case *parser.Insert:
+		tableExpr = s.Table
+	case *parser.Update:
+		tableExpr = s.Table
+	case *parser.Delete:
+		tableExpr = s.Table
+	case *parser.Select:
+		if sel, ok := s.Select.(*parser.SelectClause); ok && len(sel.From) == 1 {
+			tableExpr = sel.From[0]
+		}
+	}
+	if ate, ok := tableExpr.(*parser.AliasedTableExpr); ok {
+		if qname, ok := ate.Expr.(*parser.QualifiedName); ok {
+			switch len(qname.Indirect) {
+			case 0:
+				if sessionDatabase != "" {
+					return sessionDatabase, sqlbase.NormalizeName(string(qname.Base))
+				}
+			case 1:
+				if name, ok := qname.Indirect[0].(parser.NameIndirection); ok && qname.Base != "" {
+					return sqlbase.NormalizeName(string(qname.Base)), sqlbase.NormalizeName(string(name))
+				}
+			}
+		}
+	}
+	return sessionDatabase, ""
+}
+
+// updateDatabaseStmtCounts updates the per-database and per-table metrics for
+// a statement executed against the given database and table, which took the
+// given duration.
+func (e *Executor) updateDatabaseStmtCounts(
+	stmt parser.Statement, database, table string, latency time.Duration,
+) {
+	if database == "" {
+		return
+	}
+	dm, tm := e.dbMetrics.get(e.registry, database, table)
+	dm.record(stmt, latency)
+	if tm != nil {
+		tm.record(stmt, latency)
+	}
+}
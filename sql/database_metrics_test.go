@@ -0,0 +1,72 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/util/leaktest"
+	"github.com/cockroachdb/cockroach/util/metric"
+)
+
+// TestDatabaseMetricsLimits verifies that the databases beyond
+// maxDatabaseMetrics share aggregated metrics, and that the tables beyond
+// maxTableMetrics aren't broken down.
+func TestDatabaseMetricsLimits(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	registry := metric.NewRegistry()
+	var m databaseMetricsMap
+	dbs := make(map[*stmtMetrics]struct{})
+	for i := 0; i < maxDatabaseMetrics; i++ {
+		dm, tm := m.get(registry, fmt.Sprintf("db%d", i), "")
+		if tm != nil {
+			t.Fatalf("expected no table metrics without a table")
+		}
+		dbs[dm] = struct{}{}
+	}
+	if len(dbs) != maxDatabaseMetrics {
+		t.Fatalf("expected %d distinct database metrics, got %d", maxDatabaseMetrics, len(dbs))
+	}
+	other, tm := m.get(registry, "extra1", "t")
+	if _, ok := dbs[other]; ok || tm != nil {
+		t.Fatalf("expected aggregated metrics without table metrics for a database beyond the limit")
+	}
+	if again, _ := m.get(registry, "extra2", ""); again != other {
+		t.Fatalf("expected the databases beyond the limit to share their metrics")
+	}
+	if dm, _ := m.get(registry, "db0", ""); dm == other {
+		t.Fatalf("expected the known databases to keep their metrics")
+	}
+
+	tables := make(map[*stmtMetrics]struct{})
+	for i := 0; i < maxTableMetrics; i++ {
+		_, tm := m.get(registry, fmt.Sprintf("db%d", i%maxDatabaseMetrics), fmt.Sprintf("t%d", i))
+		if tm == nil {
+			t.Fatalf("expected metrics for table %d", i)
+		}
+		tables[tm] = struct{}{}
+	}
+	if len(tables) != maxTableMetrics {
+		t.Fatalf("expected %d distinct table metrics, got %d", maxTableMetrics, len(tables))
+	}
+	if _, tm := m.get(registry, "db0", "extra"); tm != nil {
+		t.Fatalf("expected no metrics for a table beyond the limit")
+	}
+	if _, tm := m.get(registry, "db0", "t0"); tm == nil {
+		t.Fatalf("expected the known tables to keep their metrics")
+	}
+}
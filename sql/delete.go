@@ -30,7 +30,8 @@ type deleteNode struct {
 	editNodeBase
 	n *parser.Delete
 
-	tw tableDeleter
+	tw       tableDeleter
+	triggers triggerHelper
 
 	run struct {
 		// The following fields are populated during Start().
@@ -45,13 +46,23 @@ type deleteNode struct {
 //   Notes: postgres requires DELETE. Also requires SELECT for "USING" and "WHERE" with tables.
 //          mysql requires DELETE. Also requires SELECT if a table is used in the "WHERE" clause.
 func (p *planner) Delete(n *parser.Delete, desiredTypes []parser.Datum, autoCommit bool) (planNode, error) {
+	if n.Where == nil && p.session.SafeUpdates {
+		return nil, errUnsafeUpdate("DELETE without WHERE clause")
+	}
+
 	en, err := p.makeEditNode(n.Table, autoCommit, privilege.DELETE)
 	if err != nil {
 		return nil, err
 	}
 
+	// The triggers are given the values of all the columns.
+	var triggers triggerHelper
+	if err := triggers.init(p, en.tableDesc, parser.TriggerDelete); err != nil {
+		return nil, err
+	}
+
 	var requestedCols []sqlbase.ColumnDescriptor
-	if len(n.Returning) > 0 {
+	if len(n.Returning) > 0 || triggers.active() {
 		// TODO(dan): This could be made tighter, just the rows needed for RETURNING
 		// exprs.
 		requestedCols = en.tableDesc.Columns
@@ -65,6 +76,11 @@ func (p *planner) Delete(n *parser.Delete, desiredTypes []parser.Datum, autoComm
 	if err != nil {
 		return nil, err
 	}
+	if err := rd.fks.initActions(p, CheckDeletes); err != nil {
+		return nil, err
+	}
+	autoCommit = p.deferFKChecks(rd.fks.checker, autoCommit)
+	autoCommit = autoCommit && !triggers.firesAfter()
 	tw := tableDeleter{rd: rd, autoCommit: autoCommit}
 
 	// TODO(knz): Until we split the creation of the node from Start()
@@ -85,6 +101,7 @@ func (p *planner) Delete(n *parser.Delete, desiredTypes []parser.Datum, autoComm
 		n:            n,
 		editNodeBase: en,
 		tw:           tw,
+		triggers:     triggers,
 	}
 
 	if err := dn.run.initEditNode(&dn.editNodeBase, rows, n.Returning, desiredTypes); err != nil {
@@ -112,7 +129,8 @@ func (d *deleteNode) Start() error {
 		// (When explain == explainDebug, we use the slow path so that
 		// each debugVal gets a chance to be reported via Next().)
 		sel := d.run.rows.(*selectTopNode).source.(*selectNode)
-		if scan, ok := sel.source.plan.(*scanNode); ok && canDeleteWithoutScan(d.n, scan, &d.tw) {
+		if scan, ok := sel.source.plan.(*scanNode); ok && !d.triggers.active() &&
+			canDeleteWithoutScan(d.n, scan, &d.tw) {
 			d.run.fastPath = true
 			err := d.fastDelete(scan)
 			return err
@@ -140,6 +158,9 @@ func (d *deleteNode) Next() (bool, error) {
 			// We're done. Finish the batch.
 			err = d.tw.finalize()
 		}
+		if err == nil {
+			err = d.triggers.fireAfter()
+		}
 		return false, err
 	}
 
@@ -149,10 +170,18 @@ func (d *deleteNode) Next() (bool, error) {
 
 	rowVals := d.run.rows.Values()
 
+	oldVals := d.triggers.rowValues(d.tw.rd.fetchColIDtoRowIndex, rowVals, nil)
+	if _, err := d.triggers.fireBefore(oldVals, nil); err != nil {
+		return false, err
+	}
+
 	_, err = d.tw.row(rowVals)
 	if err != nil {
 		return false, err
 	}
+	if err := d.triggers.queueAfter(oldVals, nil); err != nil {
+		return false, err
+	}
 
 	resultRow, err := d.rh.cookResultRow(rowVals)
 	if err != nil {
@@ -228,7 +228,10 @@ func hackPlanToUseDistSQL(plan planNode, syncMode bool) error {
 	plan.SetLimitHint(math.MaxInt64, true)
 
 	if sel, ok := plan.(*selectNode); ok {
-		if scan, ok := sel.source.plan.(*scanNode); ok {
+		// The table readers don't evaluate virtual computed columns nor decode
+		// collated strings, so the scans reading them are kept local.
+		if scan, ok := sel.source.plan.(*scanNode); ok && !scan.needsComputed() &&
+			!scan.needsCollation() {
 			distNode, err := scanNodeToDistSQL(scan, syncMode)
 			if err != nil {
 				return err
@@ -84,9 +84,55 @@ func (n *distinctNode) expandPlan() error {
 			n.columnsInOrder[c.ColIdx] = true
 		}
 	}
+	n.initSkipScan()
 	return nil
 }
 
+// initSkipScan turns the scan underneath the distinctNode into a skip-scan
+// when all the rendered columns are part of a prefix of the scanned index: the
+// rows which share this prefix with a row that was already returned are
+// duplicates and can be skipped.
+//
+// TODO: MIN/MAX over the columns following the grouping columns of an
+// index could skip in the same way.
+func (n *distinctNode) initSkipScan() {
+	plan := n.plan
+	if sort, ok := plan.(*sortNode); ok {
+		// Sorting doesn't change the set of rows.
+		plan = sort.plan
+	}
+	sel, ok := plan.(*selectNode)
+	if !ok {
+		return
+	}
+	scan, ok := sel.source.plan.(*scanNode)
+	if !ok {
+		return
+	}
+	prefixLen := 0
+	for _, r := range sel.render {
+		qval, ok := r.(*qvalue)
+		if !ok {
+			return
+		}
+		pos := -1
+		colID := scan.cols[qval.colRef.colIdx].ID
+		for i, id := range scan.index.ColumnIDs {
+			if id == colID {
+				pos = i
+				break
+			}
+		}
+		if pos == -1 {
+			return
+		}
+		if pos >= prefixLen {
+			prefixLen = pos + 1
+		}
+	}
+	scan.setSkipPrefix(prefixLen)
+}
+
 func (n *distinctNode) Start() error {
 	n.suffixSeen = make(map[string]struct{})
 	return n.plan.Start()
@@ -18,14 +18,19 @@ package sql
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/cockroachdb/cockroach/base"
 	"github.com/cockroachdb/cockroach/config"
 	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
 	"github.com/cockroachdb/cockroach/roachpb"
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/sql/privilege"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/retry"
+	"github.com/cockroachdb/cockroach/util/stop"
 	"github.com/pkg/errors"
 )
 
@@ -34,16 +39,22 @@ type dropDatabaseNode struct {
 	n      *parser.DropDatabase
 	dbDesc *sqlbase.DatabaseDescriptor
 	td     []*sqlbase.TableDescriptor
+	// interleaved are the secondary indexes of tables in other databases
+	// which are interleaved into the dropped tables, and are dropped along
+	// with them.
+	interleaved []sqlbase.ForeignKeyReference
 }
 
-// DropDatabase drops a database.
+// DropDatabase drops a database. With RESTRICT, only an empty database can be
+// dropped. The objects of other databases depending on its tables restrict
+// the drop, unless CASCADE is given: the tables interleaved into them are then
+// dropped too, and the foreign keys referencing them removed.
 // Privileges: DROP on database.
 //   Notes: postgres allows only the database owner to DROP a database.
 //          mysql requires the DROP privileges on the database.
 // TODO(XisiHuang): our DROP DATABASE is like the postgres DROP SCHEMA
 // (cockroach database == postgres schema). the postgres default of not
-// dropping the schema if there are dependent objects is more sensible
-// (see the RESTRICT and CASCADE options).
+// dropping the schema if there are dependent objects is more sensible.
 func (p *planner) DropDatabase(n *parser.DropDatabase) (planNode, error) {
 	if n.Name == "" {
 		return nil, errEmptyDatabaseName
@@ -56,7 +67,7 @@ func (p *planner) DropDatabase(n *parser.DropDatabase) (planNode, error) {
 	}
 	if dbDesc == nil {
 		if n.IfExists {
-			// Noop.
+			p.notice(NoticeSeverityNotice, "database %q does not exist, skipping", string(n.Name))
 			return &emptyNode{}, nil
 		}
 		return nil, sqlbase.NewUndefinedDatabaseError(string(n.Name))
@@ -70,6 +81,12 @@ func (p *planner) DropDatabase(n *parser.DropDatabase) (planNode, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(tbNames) > 0 && n.DropBehavior == parser.DropRestrict {
+		return nil, fmt.Errorf("database %q is not empty and RESTRICT was specified", dbDesc.Name)
+	}
+	if len(tbNames) > 0 && p.session.SafeUpdates {
+		return nil, errUnsafeUpdate("DROP DATABASE of non-empty database")
+	}
 
 	td := make([]*sqlbase.TableDescriptor, len(tbNames))
 	for i, tbName := range tbNames {
@@ -85,7 +102,27 @@ func (p *planner) DropDatabase(n *parser.DropDatabase) (planNode, error) {
 		td[i] = tbDesc
 	}
 
-	return &dropDatabaseNode{n: n, p: p, dbDesc: dbDesc, td: td}, nil
+	// Data interleaved into the database's tables from tables in other
+	// databases, and the tables in other databases referencing the database's
+	// tables through foreign keys, are dependents of the database. They are
+	// only removed with CASCADE, whatever the interleaves were created with.
+	behavior := n.DropBehavior
+	if behavior == parser.DropDefault {
+		behavior = parser.DropRestrict
+	}
+	var deps dependents
+	td, interleaved, err := p.addInterleavedDrops(td, behavior, &deps)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkFKDrops(td, behavior, &deps); err != nil {
+		return nil, err
+	}
+	if err := deps.err(); err != nil {
+		return nil, err
+	}
+
+	return &dropDatabaseNode{n: n, p: p, dbDesc: dbDesc, td: td, interleaved: interleaved}, nil
 }
 
 func (n *dropDatabaseNode) expandPlan() error {
@@ -100,6 +137,9 @@ func (n *dropDatabaseNode) Start() error {
 		}
 		tbNameStrings[i] = tbDesc.Name
 	}
+	if err := n.p.dropInterleavedIndexes(n.interleaved, n.n.DropBehavior, n.n.String()); err != nil {
+		return err
+	}
 
 	zoneKey, nameKey, descKey := getKeysForDatabaseDescriptor(n.dbDesc)
 
@@ -127,6 +167,10 @@ func (n *dropDatabaseNode) Start() error {
 		return err
 	}
 
+	if err := n.p.removeComment(databaseCommentType, n.dbDesc.ID, 0); err != nil {
+		return err
+	}
+
 	// Log Drop Database event. This is an auditable log event and is recorded
 	// in the same transaction as the table descriptor update.
 	if err := MakeEventLogger(n.p.leaseMgr).InsertEventRecord(n.p.txn,
@@ -168,7 +212,7 @@ type dropIndexNode struct {
 //          mysql requires the INDEX privilege on the table.
 func (p *planner) DropIndex(n *parser.DropIndex) (planNode, error) {
 	for _, index := range n.IndexList {
-		if err := index.Table.NormalizeTableName(p.session.Database); err != nil {
+		if err := p.normalizeTableName(index.Table); err != nil {
 			return nil, err
 		}
 
@@ -202,80 +246,107 @@ func (n *dropIndexNode) Start() error {
 			panic(fmt.Sprintf("table descriptor for %s became unavailable within same txn", index.Table))
 		}
 		idxName := string(index.Index)
-		status, i, err := tableDesc.FindIndexByName(idxName)
-		if err != nil {
+		if _, _, err := tableDesc.FindIndexByName(idxName); err != nil {
 			if n.n.IfExists {
-				// Noop.
+				n.p.notice(NoticeSeverityNotice, "index %q does not exist, skipping", idxName)
 				continue
 			}
 			// Index does not exist, but we want it to: error out.
 			return err
 		}
-		// Queue the mutation.
-		switch status {
-		case sqlbase.DescriptorActive:
-			idx := tableDesc.Indexes[i]
+		if err := n.p.dropIndexByName(tableDesc, idxName, n.n.DropBehavior, n.n.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			if idx.ForeignKey != nil {
-				if n.n.DropBehavior != parser.DropCascade {
-					return fmt.Errorf("index %q is in use as a foreign key constraint", idx.Name)
-				}
-				if err := n.p.removeFKBackReference(tableDesc, idx); err != nil {
-					return err
-				}
-			}
+// dropIndexByName queues the mutation dropping the named index of the table
+// and records the drop in the event log.
+func (p *planner) dropIndexByName(
+	tableDesc *sqlbase.TableDescriptor, idxName string, behavior parser.DropBehavior, stmt string,
+) error {
+	if err := checkPrimaryKeyChange(tableDesc); err != nil {
+		return err
+	}
+	status, i, err := tableDesc.FindIndexByName(idxName)
+	if err != nil {
+		return err
+	}
+	// Queue the mutation.
+	switch status {
+	case sqlbase.DescriptorActive:
+		idx := tableDesc.Indexes[i]
 
-			for _, ref := range idx.ReferencedBy {
-				fetched, err := n.p.canRemoveFK(idx.Name, ref, n.n.DropBehavior)
-				if err != nil {
-					return err
-				}
-				if err := n.p.removeFK(ref, fetched); err != nil {
-					return err
-				}
+		if idx.ForeignKey != nil {
+			if behavior != parser.DropCascade {
+				return fmt.Errorf("index %q is in use as a foreign key constraint", idx.Name)
 			}
-
-			tableDesc.AddIndexMutation(tableDesc.Indexes[i], sqlbase.DescriptorMutation_DROP)
-			tableDesc.Indexes = append(tableDesc.Indexes[:i], tableDesc.Indexes[i+1:]...)
-
-		case sqlbase.DescriptorIncomplete:
-			switch tableDesc.Mutations[i].Direction {
-			case sqlbase.DescriptorMutation_ADD:
-				return fmt.Errorf("index %q in the middle of being added, try again later", idxName)
-
-			case sqlbase.DescriptorMutation_DROP:
-				continue
+			if err := p.removeFKBackReference(tableDesc, idx); err != nil {
+				return err
 			}
 		}
-		mutationID, err := tableDesc.FinalizeMutation()
-		if err != nil {
-			return err
+
+		for _, ref := range idx.ReferencedBy {
+			fetched, err := p.canRemoveFK(idx.Name, ref, behavior)
+			if err != nil {
+				return err
+			}
+			if err := p.removeFK(ref, fetched); err != nil {
+				return err
+			}
 		}
-		if err := tableDesc.Validate(); err != nil {
-			return err
+
+		if len(idx.Interleave.Ancestors) > 0 {
+			if err := p.removeInterleaveBackReference(tableDesc, idx); err != nil {
+				return err
+			}
 		}
-		if err := n.p.writeTableDesc(tableDesc); err != nil {
+
+		if err := p.removeComment(indexCommentType, tableDesc.ID, int(idx.ID)); err != nil {
 			return err
 		}
-		// Record index drop in the event log. This is an auditable log event
-		// and is recorded in the same transaction as the table descriptor
-		// update.
-		if err := MakeEventLogger(n.p.leaseMgr).InsertEventRecord(n.p.txn,
-			EventLogDropIndex,
-			int32(tableDesc.ID),
-			int32(n.p.evalCtx.NodeID),
-			struct {
-				TableName  string
-				IndexName  string
-				Statement  string
-				User       string
-				MutationID uint32
-			}{tableDesc.Name, idxName, n.n.String(), n.p.session.User, uint32(mutationID)},
-		); err != nil {
-			return err
+
+		tableDesc.AddIndexMutation(tableDesc.Indexes[i], sqlbase.DescriptorMutation_DROP)
+		tableDesc.Indexes = append(tableDesc.Indexes[:i], tableDesc.Indexes[i+1:]...)
+
+	case sqlbase.DescriptorIncomplete:
+		switch tableDesc.Mutations[i].Direction {
+		case sqlbase.DescriptorMutation_ADD:
+			return fmt.Errorf("index %q in the middle of being added, try again later", idxName)
+
+		case sqlbase.DescriptorMutation_DROP:
+			return nil
 		}
-		n.p.notifySchemaChange(tableDesc.ID, mutationID)
 	}
+	mutationID, err := tableDesc.FinalizeMutation()
+	if err != nil {
+		return err
+	}
+	if err := tableDesc.Validate(); err != nil {
+		return err
+	}
+	if err := p.writeTableDesc(tableDesc); err != nil {
+		return err
+	}
+	// Record index drop in the event log. This is an auditable log event
+	// and is recorded in the same transaction as the table descriptor
+	// update.
+	if err := MakeEventLogger(p.leaseMgr).InsertEventRecord(p.txn,
+		EventLogDropIndex,
+		int32(tableDesc.ID),
+		int32(p.evalCtx.NodeID),
+		struct {
+			TableName  string
+			IndexName  string
+			Statement  string
+			User       string
+			MutationID uint32
+		}{tableDesc.Name, idxName, stmt, p.session.User, uint32(mutationID)},
+	); err != nil {
+		return err
+	}
+	p.notifySchemaChange(tableDesc.ID, mutationID)
 	return nil
 }
 
@@ -295,6 +366,9 @@ type dropTableNode struct {
 	p  *planner
 	n  *parser.DropTable
 	td []*sqlbase.TableDescriptor
+	// interleaved are the secondary indexes of other tables which are
+	// interleaved into the dropped tables, and are dropped along with them.
+	interleaved []sqlbase.ForeignKeyReference
 }
 
 // DropTable drops a table.
@@ -310,17 +384,19 @@ func (p *planner) DropTable(n *parser.DropTable) (planNode, error) {
 		}
 		if droppedDesc == nil {
 			if n.IfExists {
+				p.notice(NoticeSeverityNotice, "table %q does not exist, skipping", name.String())
 				continue
 			}
 			// Table does not exist, but we want it to: error out.
 			return nil, sqlbase.NewUndefinedTableError(name.String())
 		}
-
-		for _, idx := range droppedDesc.AllNonDropIndexes() {
-			for _, ref := range idx.ReferencedBy {
-				if _, err := p.canRemoveFK(droppedDesc.Name, ref, n.DropBehavior); err != nil {
-					return nil, err
-				}
+		if p.session.SafeUpdates && n.DropBehavior != parser.DropCascade {
+			empty, err := p.tableIsEmpty(name)
+			if err != nil {
+				return nil, err
+			}
+			if !empty {
+				return nil, errUnsafeUpdate("DROP TABLE of non-empty table without CASCADE")
 			}
 		}
 		td = append(td, droppedDesc)
@@ -329,13 +405,86 @@ func (p *planner) DropTable(n *parser.DropTable) (planNode, error) {
 	if len(td) == 0 {
 		return &emptyNode{}, nil
 	}
-	return &dropTableNode{p: p, n: n, td: td}, nil
+
+	var deps dependents
+	td, interleaved, err := p.addInterleavedDrops(td, n.DropBehavior, &deps)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkFKDrops(td, n.DropBehavior, &deps); err != nil {
+		return nil, err
+	}
+	if err := deps.err(); err != nil {
+		return nil, err
+	}
+	return &dropTableNode{p: p, n: n, td: td, interleaved: interleaved}, nil
 }
 
 func (n *dropTableNode) expandPlan() error {
 	return nil
 }
 
+// dependentError is the error of a drop restricted by an object depending on
+// a dropped one.
+type dependentError struct {
+	msg string
+}
+
+func (e *dependentError) Error() string {
+	return e.msg
+}
+
+// dependents collects the objects restricting a drop, so that they are all
+// reported at once.
+type dependents []string
+
+// add records err if it is a dependentError, and returns it otherwise.
+func (d *dependents) add(err error) error {
+	if e, ok := err.(*dependentError); ok {
+		*d = append(*d, e.msg)
+		return nil
+	}
+	return err
+}
+
+// err returns the error listing the dependents restricting the drop, if any.
+func (d dependents) err() error {
+	switch len(d) {
+	case 0:
+		return nil
+	case 1:
+		return errors.New(d[0])
+	}
+	return errors.Errorf("cannot drop objects other objects depend on: %s", strings.Join(d, "; "))
+}
+
+// checkFKDrops checks that the foreign keys referencing the given tables,
+// which are dropped, can be removed along with them. The foreign keys of the
+// tables which are dropped too don't restrict the drop.
+func (p *planner) checkFKDrops(
+	td []*sqlbase.TableDescriptor, behavior parser.DropBehavior, deps *dependents,
+) error {
+	dropped := make(map[sqlbase.ID]struct{}, len(td))
+	for _, desc := range td {
+		dropped[desc.ID] = struct{}{}
+	}
+	for _, desc := range td {
+		for _, idx := range desc.AllNonDropIndexes() {
+			for _, ref := range idx.ReferencedBy {
+				if _, ok := dropped[ref.Table]; ok {
+					continue
+				}
+				if _, err := p.canRemoveFK(desc.Name, ref, behavior); err != nil {
+					if err := deps.add(err); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (p *planner) canRemoveFK(
 	from string, ref *sqlbase.ForeignKeyReference, behavior parser.DropBehavior,
 ) (*sqlbase.TableDescriptor, error) {
@@ -344,7 +493,9 @@ func (p *planner) canRemoveFK(
 		return nil, err
 	}
 	if behavior != parser.DropCascade {
-		return nil, fmt.Errorf("%q is referenced by foreign key from table %q", from, table.Name)
+		return nil, &dependentError{
+			msg: fmt.Sprintf("%q is referenced by foreign key from table %q", from, table.Name),
+		}
 	}
 	if err := p.checkPrivilege(table, privilege.CREATE); err != nil {
 		return nil, err
@@ -352,6 +503,97 @@ func (p *planner) canRemoveFK(
 	return table, nil
 }
 
+// addInterleavedDrops checks that the data interleaved into the given tables
+// can be dropped along with them, according to the drop behavior of the
+// statement and the one each interleave was created with. It returns the
+// given tables followed by the tables interleaved into them, and the
+// secondary indexes of other tables interleaved into them, all of which are
+// to be dropped. The interleaves restricting the drop are added to deps.
+func (p *planner) addInterleavedDrops(
+	td []*sqlbase.TableDescriptor, behavior parser.DropBehavior, deps *dependents,
+) ([]*sqlbase.TableDescriptor, []sqlbase.ForeignKeyReference, error) {
+	dropped := make(map[sqlbase.ID]struct{}, len(td))
+	for _, desc := range td {
+		dropped[desc.ID] = struct{}{}
+	}
+	var interleaved []sqlbase.ForeignKeyReference
+	seen := make(map[sqlbase.ForeignKeyReference]struct{})
+	// The back references of an ancestor include all of its descendants, not
+	// just its children, so the tables appended to td don't need to be
+	// visited in turn.
+	named := td
+	for _, desc := range named {
+		for _, idx := range desc.AllNonDropIndexes() {
+			for _, ref := range idx.InterleavedBy {
+				if _, ok := seen[ref]; ok {
+					continue
+				}
+				seen[ref] = struct{}{}
+				if _, ok := dropped[ref.Table]; ok {
+					continue
+				}
+				table, err := p.canRemoveInterleave(desc.Name, ref, behavior)
+				if err != nil {
+					if err := deps.add(err); err != nil {
+						return nil, nil, err
+					}
+					continue
+				}
+				if ref.Index == table.PrimaryIndex.ID {
+					dropped[table.ID] = struct{}{}
+					td = append(td, table)
+				} else {
+					interleaved = append(interleaved, ref)
+				}
+			}
+		}
+	}
+
+	// A secondary index is dropped with its table if that is dropped too.
+	indexes := interleaved[:0]
+	for _, ref := range interleaved {
+		if _, ok := dropped[ref.Table]; !ok {
+			indexes = append(indexes, ref)
+		}
+	}
+	return td, indexes, nil
+}
+
+// canRemoveInterleave checks whether the referenced index, which is
+// interleaved into a table being dropped, can be dropped along with it, and
+// returns the index's table.
+func (p *planner) canRemoveInterleave(
+	from string, ref sqlbase.ForeignKeyReference, behavior parser.DropBehavior,
+) (*sqlbase.TableDescriptor, error) {
+	table, err := getTableDescFromID(p.txn, ref.Table)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := table.FindIndexByID(ref.Index)
+	if err != nil {
+		return nil, err
+	}
+	interleaved := fmt.Sprintf("table %q", table.Name)
+	priv := privilege.DROP
+	if ref.Index != table.PrimaryIndex.ID {
+		interleaved = fmt.Sprintf("index %q of table %q", idx.Name, table.Name)
+		priv = privilege.CREATE
+	}
+
+	switch {
+	case idx.Interleave.DropBehavior == sqlbase.InterleaveDescriptor_RESTRICT:
+		return nil, &dependentError{msg: fmt.Sprintf(
+			"%q is interleaved by %s, which was interleaved with RESTRICT", from, interleaved)}
+	case behavior == parser.DropRestrict,
+		behavior == parser.DropDefault && idx.Interleave.DropBehavior != sqlbase.InterleaveDescriptor_CASCADE:
+		return nil, &dependentError{msg: fmt.Sprintf("%q is interleaved by %s", from, interleaved)}
+	}
+	if err := p.checkPrivilege(table, priv); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
 func (p *planner) removeFK(ref *sqlbase.ForeignKeyReference, table *sqlbase.TableDescriptor) error {
 	if table == nil {
 		var err error
@@ -378,20 +620,47 @@ func (n *dropTableNode) Start() error {
 		}
 		// Log a Drop Table event for this table. This is an auditable log event
 		// and is recorded in the same transaction as the table descriptor
-		// update.
+		// update. The database name lets SHOW EVENTS find the events of the
+		// dropped table.
+		dbDesc, err := getDatabaseDescFromID(n.p.txn, droppedDesc.ParentID)
+		if err != nil {
+			return err
+		}
 		if err := MakeEventLogger(n.p.leaseMgr).InsertEventRecord(n.p.txn,
 			EventLogDropTable,
 			int32(droppedDesc.ID),
 			int32(n.p.evalCtx.NodeID),
-			struct {
-				TableName string
-				Statement string
-				User      string
-			}{droppedDesc.Name, n.n.String(), n.p.session.User},
+			droppedTableEventInfo{
+				TableName:    droppedDesc.Name,
+				DatabaseName: dbDesc.Name,
+				Statement:    n.n.String(),
+				User:         n.p.session.User,
+			},
 		); err != nil {
 			return err
 		}
 	}
+	return n.p.dropInterleavedIndexes(n.interleaved, n.n.DropBehavior, n.n.String())
+}
+
+// dropInterleavedIndexes drops the secondary indexes interleaved into
+// dropped tables.
+func (p *planner) dropInterleavedIndexes(
+	refs []sqlbase.ForeignKeyReference, behavior parser.DropBehavior, stmt string,
+) error {
+	for _, ref := range refs {
+		tableDesc, err := getTableDescFromID(p.txn, ref.Table)
+		if err != nil {
+			return err
+		}
+		idx, err := tableDesc.FindIndexByID(ref.Index)
+		if err != nil {
+			return err
+		}
+		if err := p.dropIndexByName(tableDesc, idx.Name, behavior, stmt); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -445,8 +714,13 @@ func (p *planner) dropTableImpl(tableDesc *sqlbase.TableDescriptor) error {
 	}
 	p.notifySchemaChange(tableDesc.ID, sqlbase.InvalidMutationID)
 
-	// Remove FK relationships.
+	// Remove FK and interleave relationships.
 	for _, idx := range tableDesc.AllNonDropIndexes() {
+		if len(idx.Interleave.Ancestors) > 0 {
+			if err := p.removeInterleaveBackReference(tableDesc, idx); err != nil {
+				return err
+			}
+		}
 		if idx.ForeignKey != nil {
 			if err := p.removeFKBackReference(tableDesc, idx); err != nil {
 				return err
@@ -460,6 +734,10 @@ func (p *planner) dropTableImpl(tableDesc *sqlbase.TableDescriptor) error {
 		}
 	}
 
+	if err := p.removeTableComments(tableDesc.ID); err != nil {
+		return err
+	}
+
 	verifyMetadataCallback := func(systemConfig config.SystemConfig, tableID sqlbase.ID) error {
 		desc, err := GetTableDesc(systemConfig, tableID)
 		if err != nil {
@@ -487,34 +765,236 @@ func (p *planner) removeFKBackReference(
 	if err != nil {
 		return errors.Errorf("error resolving referenced table ID %d: %v", idx.ForeignKey.Table, err)
 	}
+	if err := removeFKBackReferenceFromTable(t, tableDesc.ID, idx); err != nil {
+		return err
+	}
+	return p.saveNonmutationAndNotify(t)
+}
+
+// removeFKBackReferenceFromTable removes the reference to the index idx of the
+// table with ID tableID from the referenced table t.
+func removeFKBackReferenceFromTable(
+	t *sqlbase.TableDescriptor, tableID sqlbase.ID, idx sqlbase.IndexDescriptor,
+) error {
 	targetIdx, err := t.FindIndexByID(idx.ForeignKey.Index)
 	if err != nil {
 		return err
 	}
 	for k, ref := range targetIdx.ReferencedBy {
-		if ref.Table == tableDesc.ID {
+		if ref.Table == tableID && ref.Index == idx.ID {
 			targetIdx.ReferencedBy = append(targetIdx.ReferencedBy[:k], targetIdx.ReferencedBy[k+1:]...)
+			break
 		}
 	}
-	return p.saveNonmutationAndNotify(t)
+	return nil
 }
 
-// truncateAndDropTable batches all the commands required for truncating and deleting the
-// table descriptor.
-// It is called from a mutation, async wrt the DROP statement.
-func truncateAndDropTable(tableDesc *sqlbase.TableDescriptor, db *client.DB) error {
-	return db.Txn(func(txn *client.Txn) error {
-		if err := truncateTable(tableDesc, txn); err != nil {
+// removeInterleaveBackReference removes the references to an interleaved
+// index from the ancestors it is interleaved into. Ancestors which are
+// themselves being dropped are left alone.
+func (p *planner) removeInterleaveBackReference(
+	tableDesc *sqlbase.TableDescriptor, idx sqlbase.IndexDescriptor,
+) error {
+	for _, ancestor := range idx.Interleave.Ancestors {
+		t := tableDesc
+		if ancestor.TableID != tableDesc.ID {
+			var err error
+			t, err = getTableDescFromID(p.txn, ancestor.TableID)
+			if err != nil {
+				return errors.Errorf("error resolving interleaved table ID %d: %v", ancestor.TableID, err)
+			}
+			if t.Deleted() {
+				continue
+			}
+		}
+		targetIdx, err := t.FindIndexByID(ancestor.IndexID)
+		if err != nil {
 			return err
 		}
+		for k, ref := range targetIdx.InterleavedBy {
+			if ref.Table == tableDesc.ID && ref.Index == idx.ID {
+				targetIdx.InterleavedBy = append(targetIdx.InterleavedBy[:k], targetIdx.InterleavedBy[k+1:]...)
+				break
+			}
+		}
+		// The caller saves the descriptor of the index's own table.
+		if t != tableDesc {
+			if err := p.saveNonmutationAndNotify(t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// interleavedAncestorDropped returns whether the table into which the given
+// index is ultimately interleaved has been dropped. The index's data is then
+// removed along with that of the ancestor.
+func interleavedAncestorDropped(txn *client.Txn, idx sqlbase.IndexDescriptor) (bool, error) {
+	root, err := getTableDescFromID(txn, idx.Interleave.Ancestors[0].TableID)
+	if err == errDescriptorNotFound {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return root.Deleted(), nil
+}
+
+// interleavedTruncateChunkSize is the maximum number of rows deleted per
+// transaction when removing the data of a dropped interleaved table.
+const interleavedTruncateChunkSize = 600
+
+// truncateInterleavedTable deletes the rows of a dropped table with indexes
+// interleaved into other tables. The data of these indexes is stored in the
+// key span of their root ancestors, and can't be removed by clearing the
+// table's own key span.
+func truncateInterleavedTable(tableDesc *sqlbase.TableDescriptor, db *client.DB) error {
+	prefix := roachpb.Key(sqlbase.MakeIndexKeyPrefix(tableDesc, tableDesc.PrimaryIndex.ID))
+	sp := sqlbase.Span{Start: prefix, End: prefix.PrefixEnd()}
+	for done := false; !done; {
+		if err := db.Txn(func(txn *client.Txn) error {
+			// Nothing needs to be deleted row by row if the data of all the
+			// interleaved indexes is removed along with their ancestors.
+			done = true
+			for _, idx := range tableDesc.AllNonDropIndexes() {
+				if len(idx.Interleave.Ancestors) == 0 {
+					continue
+				}
+				if dropped, err := interleavedAncestorDropped(txn, idx); err != nil {
+					return err
+				} else if !dropped {
+					done = false
+					break
+				}
+			}
+			if done {
+				return nil
+			}
+
+			planner := makePlanner()
+			planner.setTxn(txn)
+			scan := planner.Scan()
+			scan.desc = *tableDesc
+			scan.spans = []sqlbase.Span{sp}
+			scan.initDescDefaults(publicAndNonPublicColumns)
+			rows, err := selectIndex(scan, nil, false)
+			if err != nil {
+				return err
+			}
+			if err := rows.Start(); err != nil {
+				return err
+			}
+			rd, err := makeRowDeleter(txn, tableDesc, nil, scan.cols, false)
+			if err != nil {
+				return err
+			}
+
+			b := &client.Batch{}
+			numRows := 0
+			for ; numRows < interleavedTruncateChunkSize; numRows++ {
+				if next, err := rows.Next(); !next {
+					if err != nil {
+						return err
+					}
+					break
+				}
+				if err := rd.deleteRow(b, rows.Values()); err != nil {
+					return err
+				}
+			}
+			if err := txn.Run(b); err != nil {
+				return err
+			}
+			if numRows < interleavedTruncateChunkSize {
+				done = true
+				return nil
+			}
+			sp.Start = scan.fetcher.Key()
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncateAndDropTable removes the table's data, and then batches all the
+// commands required for deleting the table descriptor.
+// It is called from a mutation, async wrt the DROP statement. If it fails,
+// the schema changer retries the drop later, since the descriptor is only
+// deleted once the data is gone. The stopper, which can be nil in tests,
+// interrupts the retries of the removal of the data.
+func truncateAndDropTable(
+	tableDesc *sqlbase.TableDescriptor, db *client.DB, stopper *stop.Stopper,
+) error {
+	for _, idx := range tableDesc.AllNonDropIndexes() {
+		if len(idx.Interleave.Ancestors) > 0 {
+			if err := truncateInterleavedTable(tableDesc, db); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	// The table is no longer visible to transactions, so its data can be
+	// removed with range tombstones instead of writing a deletion for every
+	// key. This isn't transactional, but it is idempotent: if the node fails
+	// before the descriptor is deleted, the drop is run again.
+	tableStartKey := roachpb.Key(keys.MakeTablePrefix(uint32(tableDesc.ID)))
+	tableEndKey := tableStartKey.PrefixEnd()
+	if log.V(2) {
+		log.Infof("ClearRange %s - %s", tableStartKey, tableEndKey)
+	}
+	opts := base.DefaultRetryOptions()
+	opts.MaxRetries = clearRangeMaxRetries
+	if stopper != nil {
+		opts.Closer = stopper.ShouldQuiesce()
+	}
+	var err error
+	for r := retry.Start(opts); r.Next(); {
+		if err = db.ClearRange(tableStartKey, tableEndKey); err == nil {
+			break
+		}
+		log.Warningf("unable to clear the data of dropped table %d: %s", tableDesc.ID, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return db.Txn(func(txn *client.Txn) error {
 		zoneKey, nameKey, descKey := getKeysForTableDescriptor(tableDesc)
+		// The name of a truncated table refers to the table replacing it.
+		gr, err := txn.Get(nameKey)
+		if err != nil {
+			return err
+		}
 		// Delete table descriptor
 		b := client.Batch{}
 		b.Del(descKey)
-		b.Del(nameKey)
+		if gr.Exists() && sqlbase.ID(gr.ValueInt()) == tableDesc.ID {
+			b.Del(nameKey)
+		}
 		// Delete the zone config entry for this table.
 		b.Del(zoneKey)
+		// Delete the sequences backing the SERIAL columns.
+		for _, col := range tableDesc.Columns {
+			if id, ok := serialSequenceID(col); ok {
+				deleteSequence(&b, id)
+			}
+		}
+		for _, m := range tableDesc.Mutations {
+			if col := m.GetColumn(); col != nil {
+				if id, ok := serialSequenceID(*col); ok {
+					deleteSequence(&b, id)
+				}
+			}
+		}
 		txn.SetSystemConfigTrigger()
 		return txn.Run(&b)
 	})
 }
+
+// clearRangeMaxRetries is the number of attempts to clear the data of a
+// dropped table before the schema changer gives up and retries the drop
+// later.
+const clearRangeMaxRetries = 5
@@ -68,7 +68,7 @@ func convertBatchError(tableDesc *sqlbase.TableDescriptor, b *client.Batch) erro
 				return err
 			}
 
-			return sqlbase.NewUniquenessConstraintViolationError(index, vals)
+			return sqlbase.NewUniquenessConstraintViolationError(tableDesc, index, vals)
 		}
 	}
 	return origPErr.GoError()
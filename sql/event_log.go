@@ -26,6 +26,8 @@ import (
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/stop"
+	"github.com/cockroachdb/cockroach/util/timeutil"
 	"github.com/pkg/errors"
 )
 
@@ -37,6 +39,8 @@ const (
 	EventLogCreateDatabase EventLogType = "create_database"
 	// EventLogDropDatabase is recorded when a database is dropped.
 	EventLogDropDatabase EventLogType = "drop_database"
+	// EventLogRenameDatabase is recorded when a database is renamed.
+	EventLogRenameDatabase EventLogType = "rename_database"
 	// EventLogCreateTable is recorded when a table is created.
 	EventLogCreateTable EventLogType = "create_table"
 	// EventLogDropTable is recorded when a table is dropped.
@@ -62,6 +66,23 @@ const (
 	EventLogNodeRestart EventLogType = "node_restart"
 )
 
+const (
+	// eventLogGCInterval is the interval at which events which have exceeded
+	// the event log retention are deleted.
+	eventLogGCInterval = time.Hour
+	// eventLogGCBatchSize is the maximum number of events deleted by a
+	// transaction of the event log GC.
+	eventLogGCBatchSize = 1000
+)
+
+// droppedTableEventInfo is the info of an EventLogDropTable event.
+type droppedTableEventInfo struct {
+	TableName    string
+	DatabaseName string
+	Statement    string
+	User         string
+}
+
 // eventTableSchema describes the schema of the event log table.
 const eventTableSchema = `
 CREATE TABLE system.eventlog (
@@ -149,3 +170,70 @@ func (ev EventLogger) selectEventTimestamp(input hlc.Timestamp) time.Time {
 	}
 	return input.GoTime()
 }
+
+// DeleteEventsBefore deletes up to limit of the events recorded before
+// cutoff, oldest first, as part of the provided transaction. It returns the
+// number of deleted events.
+func (ev EventLogger) DeleteEventsBefore(txn *client.Txn, cutoff time.Time, limit int) (int, error) {
+	const deleteEventsStmt = `
+DELETE FROM system.eventlog WHERE (timestamp, uniqueID) IN (
+  SELECT timestamp, uniqueID FROM system.eventlog
+  WHERE timestamp < $1 ORDER BY timestamp LIMIT $2
+)`
+	return ev.ExecuteStatementInTransaction(txn, deleteEventsStmt, cutoff, limit)
+}
+
+// deleteAllEventsBefore deletes all events recorded before cutoff, with a
+// transaction per batchSize events so that a large backlog doesn't make a
+// single transaction too large. It stops early if the stopper is stopping,
+// and returns the number of deleted events.
+func (ev EventLogger) deleteAllEventsBefore(
+	db client.DB, cutoff time.Time, batchSize int, stopper *stop.Stopper,
+) (int, error) {
+	var total int
+	for {
+		var deleted int
+		if err := db.Txn(func(txn *client.Txn) error {
+			var err error
+			deleted, err = ev.DeleteEventsBefore(txn, cutoff, batchSize)
+			return err
+		}); err != nil {
+			return total, err
+		}
+		total += deleted
+		if deleted < batchSize {
+			return total, nil
+		}
+		select {
+		case <-stopper.ShouldStop():
+			return total, nil
+		default:
+		}
+	}
+}
+
+// StartGC starts a worker which periodically deletes the events which are
+// older than retention. No worker is started if retention is not positive.
+func (ev EventLogger) StartGC(db client.DB, retention time.Duration, stopper *stop.Stopper) {
+	if retention <= 0 {
+		return
+	}
+	stopper.RunWorker(func() {
+		ticker := time.NewTicker(eventLogGCInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				cutoff := timeutil.Now().Add(-retention)
+				deleted, err := ev.deleteAllEventsBefore(db, cutoff, eventLogGCBatchSize, stopper)
+				if err != nil {
+					log.Warningf("unable to delete events before %s: %s", cutoff, err)
+				} else if log.V(1) {
+					log.Infof("deleted %d events before %s", deleted, cutoff)
+				}
+			case <-stopper.ShouldStop():
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+	"github.com/cockroachdb/cockroach/util/timeutil"
+)
+
+// TestEventLogGC verifies that the events older than the cutoff are deleted
+// in batches, and that the more recent events are kept.
+func TestEventLogGC(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	ev := MakeEventLogger(s.LeaseManager().(*LeaseManager))
+
+	if _, err := db.Exec(`DELETE FROM system.eventlog`); err != nil {
+		t.Fatal(err)
+	}
+	now := timeutil.Now()
+	cutoff := now.Add(-time.Hour)
+	for i := 0; i < 7; i++ {
+		if _, err := db.Exec(
+			`INSERT INTO system.eventlog (timestamp, eventType, targetID, reportingID)
+VALUES ($1, 'test', $2, 1)`,
+			cutoff.Add(-time.Duration(i+1)*time.Minute), i,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(
+		`INSERT INTO system.eventlog (timestamp, eventType, targetID, reportingID)
+VALUES ($1, 'test', 100, 1)`, now,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// The 7 old events are deleted by batches of 3, 3 and 1.
+	deleted, err := ev.deleteAllEventsBefore(*kvDB, cutoff, 3, s.Stopper())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 7 {
+		t.Fatalf("expected 7 deleted events, got %d", deleted)
+	}
+	var targetID int
+	if err := db.QueryRow(`SELECT targetID FROM system.eventlog`).Scan(&targetID); err != nil {
+		t.Fatal(err)
+	}
+	if targetID != 100 {
+		t.Fatalf("expected the recent event to be kept, found target %d", targetID)
+	}
+}
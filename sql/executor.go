@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/context"
@@ -40,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/util/log"
 	"github.com/cockroachdb/cockroach/util/metric"
 	"github.com/cockroachdb/cockroach/util/stop"
+	"github.com/cockroachdb/cockroach/util/timeutil"
 	"github.com/pkg/errors"
 )
 
@@ -96,15 +98,23 @@ type Result struct {
 	// of values in each Row.
 	Columns []ResultColumn
 	// Rows will be populated if the statement type is "Rows". It will contain
-	// the result set of the result.
-	// TODO(nvanbenschoten): Can this be streamed from the planNode?
+	// the result set of the result, or its first rows if Cursor is set.
 	Rows []ResultRow
+	// Cursor is set if the statement was executed by ExecutePortal and has
+	// more rows than the limit. It returns the rows following Rows.
+	Cursor *Cursor
+	// Notices are the notices produced while executing the statement, to be
+	// sent to the client before the result.
+	Notices []Notice
 }
 
 // ResultColumn contains the name and type of a SQL "cell".
 type ResultColumn struct {
 	Name string
 	Typ  parser.Datum
+	// TypeModifier is the PostgreSQL type modifier of the column, e.g. the
+	// maximum length of a STRING(n), or 0 if it has none.
+	TypeModifier int32
 
 	// If set, this is an implicit column; used internally.
 	hidden bool
@@ -140,6 +150,21 @@ type Executor struct {
 	miscCount        *metric.Counter
 	queryCount       *metric.Counter
 
+	// dbMetrics holds the statement counts and latencies broken down by
+	// database.
+	dbMetrics databaseMetricsMap
+
+	// stmtStats collects the statistics of the executed statements, which
+	// are periodically persisted (see StartStatementStatsFlusher).
+	stmtStats *statementStats
+
+	// largeStmts limits the number of large statements executed concurrently.
+	largeStmts admissionQueue
+
+	// tempDatabasePrefix starts the names of the temporary databases of the
+	// sessions (see makeTempDatabasePrefix).
+	tempDatabasePrefix string
+
 	// System Config and mutex.
 	systemConfig   config.SystemConfig
 	databaseCache  *databaseCache
@@ -161,6 +186,17 @@ type ExecutorContext struct {
 	Clock        *hlc.Clock
 	DistSQLSrv   *distsql.ServerImpl
 
+	// PGServerVersion is the PostgreSQL version reported to clients, e.g. in
+	// the server_version parameter. Defaults to DefaultPGServerVersion.
+	PGServerVersion string
+
+	// IndexUsage, if set, counts the reads of the indexes of user tables.
+	IndexUsage *IndexUsageStats
+
+	// SpanStats, if set, returns the statistics of the data of a key span. It
+	// is used to estimate the disk usage of tables and indexes.
+	SpanStats func(span roachpb.RSpan) (SpanStats, error)
+
 	TestingKnobs *ExecutorTestingKnobs
 }
 
@@ -204,6 +240,10 @@ type ExecutorTestingKnobs struct {
 	// each priority level) to avoid the randomness in the normal generation.
 	FixTxnPriority bool
 
+	// UseDistSQL causes the scans of the statements to be run by distSQL
+	// flows where possible, in sync mode, as if testDistSQL was set.
+	UseDistSQL bool
+
 	// SyncSchemaChangersFilter is called before running schema changers
 	// synchronously (at the end of a txn). The function can be used to clear the
 	// schema changers (if the test doesn't want them run using the synchronous
@@ -245,6 +285,8 @@ func NewExecutor(ctx ExecutorContext, stopper *stop.Stopper, registry *metric.Re
 		miscCount:        registry.Counter("misc.count"),
 		queryCount:       registry.Counter("query.count"),
 	}
+	exec.stmtStats = newStatementStats(exec)
+	exec.largeStmts = makeAdmissionQueue(maxConcurrentLargeStmts, registry)
 	exec.systemConfigCond = sync.NewCond(exec.systemConfigMu.RLocker())
 
 	gossipUpdateC := ctx.Gossip.RegisterSystemConfigChannel()
@@ -268,6 +310,7 @@ func NewExecutor(ctx ExecutorContext, stopper *stop.Stopper, registry *metric.Re
 func (e *Executor) SetNodeID(nodeID roachpb.NodeID) {
 	e.nodeID = nodeID
 	e.ctx.LeaseManager.nodeID = uint32(nodeID)
+	e.tempDatabasePrefix = makeTempDatabasePrefix(nodeID)
 }
 
 // updateSystemConfig is called whenever the system config gossip entry is updated.
@@ -303,9 +346,10 @@ func (e *Executor) Prepare(
 	if log.V(2) {
 		log.Infof("preparing statement: %s", query)
 	}
+	session.materializeCursors()
 	stmt, err := parser.ParseOne(query, parser.Syntax(session.Syntax))
 	if err != nil {
-		return nil, err
+		return nil, convertParseError(err)
 	}
 	if err = pinfo.ProcessPlaceholderAnnotations(stmt); err != nil {
 		return nil, err
@@ -335,6 +379,9 @@ func (e *Executor) Prepare(
 		setTxnTimestamps(txn, *protoTS)
 	}
 
+	// Only the columns of the plan are needed, so its nodes can be reused by
+	// the next statement.
+	defer session.planner.alloc.reset()
 	plan, err := session.planner.prepare(stmt)
 	if err != nil {
 		return nil, err
@@ -355,7 +402,7 @@ func (e *Executor) Prepare(
 func (e *Executor) ExecuteStatements(
 	ctx context.Context, session *Session, stmts string, pinfo *parser.PlaceholderInfo,
 ) StatementResults {
-
+	session.materializeCursors()
 	session.planner.resetForBatch(e)
 	session.planner.semaCtx.Placeholders.Assign(pinfo)
 
@@ -364,6 +411,19 @@ func (e *Executor) ExecuteStatements(
 	return e.execRequest(ctx, session, stmts)
 }
 
+// ExecutePortal executes the statement of a portal like ExecuteStatements.
+// If limit is not 0 and the statement runs in a transaction opened by an
+// earlier request, at most limit rows are returned, and the Cursor of the
+// result returns the remaining ones as they are requested. Otherwise, all
+// the rows are returned.
+func (e *Executor) ExecutePortal(
+	ctx context.Context, session *Session, stmt string, pinfo *parser.PlaceholderInfo, limit int,
+) StatementResults {
+	session.portalRowLimit = limit
+	defer func() { session.portalRowLimit = 0 }()
+	return e.ExecuteStatements(ctx, session, stmt, pinfo)
+}
+
 // blockConfigUpdates blocks any gossip updates to the system config
 // until the unlock function returned is called. Useful in tests.
 func (e *Executor) blockConfigUpdates() func() {
@@ -400,8 +460,12 @@ func (e *Executor) execRequest(ctx context.Context, session *Session, sql string
 	var res StatementResults
 	txnState := &session.TxnState
 	planMaker := &session.planner
+	// A cancellation requested before this request only applied to the
+	// previous one.
+	atomic.StoreInt32(&session.queryCancelled, 0)
 	stmts, err := planMaker.parser.Parse(sql, parser.Syntax(session.Syntax))
 	if err != nil {
+		err = convertParseError(err)
 		// A parse error occurred: we can't determine if there were multiple
 		// statements or only one, so just pretend there was one.
 		if txnState.txn != nil {
@@ -569,7 +633,7 @@ func (e *Executor) execRequest(ctx context.Context, session *Session, sql string
 
 // If the plan has a fast path we attempt to query that,
 // otherwise we fall back to counting via plan.Next().
-func countRowsAffected(p planNode) (int, error) {
+func countRowsAffected(planMaker *planner, p planNode) (int, error) {
 	if a, ok := p.(planNodeFastPath); ok {
 		if count, res := a.FastPathResults(); res {
 			return count, nil
@@ -579,6 +643,9 @@ func countRowsAffected(p planNode) (int, error) {
 	next, err := p.Next()
 	for ; next; next, err = p.Next() {
 		count++
+		if err := planMaker.checkCancelled(); err != nil {
+			return count, err
+		}
 	}
 	return count, err
 }
@@ -674,8 +741,13 @@ func (e *Executor) execStmtsInCurrentTxn(
 		var err error
 		switch txnState.State {
 		case Open:
+			// An explicit transaction whose last statement is followed by a COMMIT
+			// in the same batch can be committed together with the statement's
+			// writes, like an implicit one.
+			autoCommit := implicitTxn ||
+				(i+1 < len(stmts) && canCommitWithStmt(stmt, stmts[i+1]))
 			res, err = e.execStmtInOpenTxn(
-				stmt, planMaker, implicitTxn, txnBeginning && (i == 0), /* firstInTxn */
+				stmt, planMaker, implicitTxn, autoCommit, txnBeginning && (i == 0), /* firstInTxn */
 				txnState)
 		case Aborted, RestartWait:
 			res, err = e.execStmtInAbortedTxn(stmt, txnState, planMaker)
@@ -752,6 +824,8 @@ func (e *Executor) execStmtInAbortedTxn(
 			// Reset the state. Txn is Open again.
 			txnState.State = Open
 			txnState.retrying = true
+			// The writes of the txn are discarded, and so are their checks.
+			txnState.deferredFKs = &fkDeferredChecks{}
 			// TODO(andrei/cdo): add a counter for user-directed retries.
 			return Result{}, nil
 		}
@@ -800,8 +874,10 @@ func (e *Executor) execStmtInCommitWaitTxn(
 // implicitTxn: set if the current transaction was implicitly
 //  created by the system (i.e. the client sent the statement outside of
 //  a transaction).
-//  COMMIT/ROLLBACK statements are rejected if set. Also, the transaction
-//  might be auto-committed in this function.
+//  COMMIT/ROLLBACK statements are rejected if set.
+// autoCommit: set if the transaction can be committed along with the
+//  statement. This is the case for implicit transactions, and for statements
+//  followed by a COMMIT.
 // firstInTxn: set for the first statement in a transaction. Used
 //  so that nested BEGIN statements are caught.
 // stmtTimestamp: Used as the statement_timestamp().
@@ -813,6 +889,7 @@ func (e *Executor) execStmtInOpenTxn(
 	stmt parser.Statement,
 	planMaker *planner,
 	implicitTxn bool,
+	autoCommit bool,
 	firstInTxn bool,
 	txnState *txnState,
 ) (Result, error) {
@@ -834,6 +911,7 @@ func (e *Executor) execStmtInOpenTxn(
 			txnState.updateStateAndCleanupOnErr(errTransactionInProgress, e)
 			return Result{Err: errTransactionInProgress}, errTransactionInProgress
 		}
+		txnState.deferredFKs = &fkDeferredChecks{}
 	case *parser.CommitTransaction:
 		if implicitTxn {
 			return e.noTransactionHelper(txnState)
@@ -928,7 +1006,21 @@ func (e *Executor) execStmtInOpenTxn(
 	if txnState.tr != nil {
 		txnState.tr.LazyLog(stmt, true /* sensitive */)
 	}
-	result, err := e.execStmt(stmt, planMaker, implicitTxn /* autoCommit */)
+	// The rows of a portal executed with a row limit are returned by a
+	// cursor, which keeps the plan open until the next request. This needs
+	// the transaction to stay open after the statement, and not to be retried
+	// from the start of the request.
+	rowLimit := 0
+	if !implicitTxn && !txnState.autoRetry {
+		rowLimit = planMaker.session.portalRowLimit
+	}
+	start := timeutil.Now()
+	result, err := e.execStmt(stmt, planMaker, autoCommit, rowLimit)
+	latency := timeutil.Since(start)
+	e.latency.RecordValue(latency.Nanoseconds())
+	database, table := stmtTable(stmt, planMaker.session.Database)
+	e.updateDatabaseStmtCounts(stmt, database, table, latency)
+	e.stmtStats.recordStatement(stmt, result.RowsAffected+len(result.Rows), latency, err)
 	if err != nil {
 		if txnState.tr != nil {
 			txnState.tr.LazyPrintf("ERROR: %v", err)
@@ -999,7 +1091,15 @@ func commitSQLTransaction(
 	if commitType == commit {
 		txnState.commitSeen = true
 	}
-	err := txnState.txn.Commit()
+	var err error
+	// The KV txn might have been committed already, together with the writes
+	// of the previous statement (see canCommitWithStmt), in which case it had
+	// no deferred foreign key checks left to perform.
+	if !txnState.txn.IsFinalized() {
+		if err = txnState.deferredFKs.run(p); err == nil {
+			err = txnState.txn.Commit()
+		}
+	}
 	result := Result{PGTag: (*parser.CommitTransaction)(nil).StatementTag()}
 	if err != nil {
 		// Errors on COMMIT need special handling, as COMMIT needs to finalize the
@@ -1025,22 +1125,69 @@ func commitSQLTransaction(
 	return result, err
 }
 
+// canCommitWithStmt returns whether the KV transaction can be committed in the
+// same batch as the writes of stmt, given the statement that follows it in the
+// same SQL transaction. This is the case for data modification statements
+// directly followed by a COMMIT: the transaction saves a round-trip to commit
+// and, if it has no other writes, can use the one-phase commit fast path when
+// its writes all land on a single range.
+//
+// The writes of the other statements are still waited for before the next
+// statement starts. TODO(tschottdorf): pipeline them, which requires the
+// TxnCoordSender to track the intents in flight and to prove them before
+// committing.
+func canCommitWithStmt(stmt parser.Statement, next parser.Statement) bool {
+	if _, ok := next.(*parser.CommitTransaction); !ok {
+		return false
+	}
+	switch stmt.(type) {
+	case *parser.Insert, *parser.Update, *parser.Delete:
+		return true
+	}
+	return false
+}
+
 // the current transaction might have been committed/rolled back when this returns.
+//
+// If rowLimit is not 0, at most rowLimit rows are returned and the result
+// has a Cursor returning the remaining ones, if any.
 func (e *Executor) execStmt(
-	stmt parser.Statement, planMaker *planner, autoCommit bool,
+	stmt parser.Statement, planMaker *planner, autoCommit bool, rowLimit int,
 ) (Result, error) {
 	var result Result
+	if err := planMaker.checkCancelled(); err != nil {
+		return result, err
+	}
+	if err := planMaker.maybeInjectRetry(); err != nil {
+		return result, err
+	}
+	// Notices of previous statements, or of failed attempts at this one, have
+	// already been returned or must be discarded.
+	planMaker.notices = nil
+	// The plan isn't used once its results have been collected, so its nodes
+	// can be reused by the next statement. The plan of a cursor is instead
+	// read to completion before the next statement is planned.
+	var cursor *Cursor
+	defer func() {
+		if cursor == nil {
+			planMaker.alloc.reset()
+		}
+	}()
 	plan, err := planMaker.makePlan(stmt, autoCommit)
 	if err != nil {
 		return result, err
 	}
 
-	if testDistSQL != 0 {
-		if err := hackPlanToUseDistSQL(plan, testDistSQL == 1); err != nil {
+	if testDistSQL != 0 || e.ctx.TestingKnobs.UseDistSQL {
+		if err := hackPlanToUseDistSQL(plan, testDistSQL != 2); err != nil {
 			return result, err
 		}
 	}
 
+	if err := e.admitStmt(planMaker, plan); err != nil {
+		return result, err
+	}
+
 	if err := plan.Start(); err != nil {
 		return result, err
 	}
@@ -1050,7 +1197,7 @@ func (e *Executor) execStmt(
 
 	switch result.Type {
 	case parser.RowsAffected:
-		count, err := countRowsAffected(plan)
+		count, err := countRowsAffected(planMaker, plan)
 		if err != nil {
 			return result, err
 		}
@@ -1064,39 +1211,33 @@ func (e *Executor) execStmt(
 			}
 		}
 
-		// valuesAlloc is used to allocate the backing storage for the
-		// ResultRow.Values slices in chunks.
-		var valuesAlloc []parser.Datum
-		const maxChunkSize = 64 // Arbitrary, could use tuning.
-		chunkSize := 4          // Arbitrary as well.
-
-		next, err := plan.Next()
-		for ; next; next, err = plan.Next() {
-			// The plan.Values DTuple needs to be copied on each iteration.
-			values := plan.Values()
-
-			n := len(values)
-			if len(valuesAlloc) < n {
-				valuesAlloc = make([]parser.Datum, len(result.Columns)*chunkSize)
-				if chunkSize < maxChunkSize {
-					chunkSize *= 2
-				}
-			}
-			row := ResultRow{Values: valuesAlloc[:0:n]}
-			valuesAlloc = valuesAlloc[n:]
-
-			for _, val := range values {
-				if err := checkResultDatum(val); err != nil {
-					return result, err
-				}
-				row.Values = append(row.Values, val)
-			}
-			result.Rows = append(result.Rows, row)
+		n := 0
+		if rowLimit > 0 {
+			// One more row than the limit is read to tell whether the
+			// statement has more rows.
+			n = rowLimit + 1
 		}
+		var alloc resultRowAlloc
+		rows, _, err := readRows(planMaker, plan, nil, n, &alloc)
 		if err != nil {
 			return result, err
 		}
+		result.Rows = rows
+		if rowLimit > 0 && len(rows) > rowLimit {
+			cursor = &Cursor{
+				e:             e,
+				session:       planMaker.session,
+				plan:          plan,
+				stmtTimestamp: planMaker.evalCtx.GetStmtTimestamp().Time,
+				rows:          rows[rowLimit:],
+				alloc:         alloc,
+			}
+			planMaker.session.cursors = append(planMaker.session.cursors, cursor)
+			result.Rows = rows[:rowLimit:rowLimit]
+			result.Cursor = cursor
+		}
 	}
+	result.Notices = planMaker.notices
 	return result, nil
 }
 
@@ -1209,6 +1350,7 @@ func checkResultDatum(datum parser.Datum) error {
 	case *parser.DDecimal:
 	case *parser.DBytes:
 	case *parser.DString:
+	case *parser.DCollatedString:
 	case *parser.DDate:
 	case *parser.DTimestamp:
 	case *parser.DTimestampTZ:
@@ -1221,6 +1363,16 @@ func checkResultDatum(datum parser.Datum) error {
 	return nil
 }
 
+// convertParseError attaches the syntax error code to errors caused by invalid
+// SQL. Other errors, such as those for unimplemented features, are returned
+// unchanged.
+func convertParseError(err error) error {
+	if syntaxErr, ok := err.(*parser.SyntaxError); ok {
+		return sqlbase.NewSyntaxError(err.Error(), syntaxErr.Position)
+	}
+	return err
+}
+
 // makeResultColumns converts sqlbase.ColumnDescriptors to ResultColumns.
 func makeResultColumns(colDescs []sqlbase.ColumnDescriptor) []ResultColumn {
 	cols := make([]ResultColumn, 0, len(colDescs))
@@ -1232,7 +1384,12 @@ func makeResultColumns(colDescs []sqlbase.ColumnDescriptor) []ResultColumn {
 		}
 
 		hidden := colDesc.Hidden
-		cols = append(cols, ResultColumn{Name: colDesc.Name, Typ: typ, hidden: hidden})
+		cols = append(cols, ResultColumn{
+			Name:         colDesc.Name,
+			Typ:          typ,
+			TypeModifier: colDesc.Type.TypeModifier(),
+			hidden:       hidden,
+		})
 	}
 	return cols
 }
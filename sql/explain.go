@@ -47,6 +47,7 @@ var explainStrings = []string{"", "debug", "plan", "trace", "types"}
 func (p *planner) Explain(n *parser.Explain, autoCommit bool) (planNode, error) {
 	mode := explainNone
 	verbose := false
+	recommend := false
 	expanded := true
 	normalizedExplainTypes := false
 	for _, opt := range n.Options {
@@ -61,6 +62,8 @@ func (p *planner) Explain(n *parser.Explain, autoCommit bool) (planNode, error)
 			newMode = explainTypes
 		} else if strings.EqualFold(opt, "VERBOSE") {
 			verbose = true
+		} else if strings.EqualFold(opt, "RECOMMEND") {
+			recommend = true
 		} else if strings.EqualFold(opt, "NOEXPAND") {
 			expanded = false
 		} else if strings.EqualFold(opt, "NORMALIZE") {
@@ -118,8 +121,9 @@ func (p *planner) Explain(n *parser.Explain, autoCommit bool) (planNode, error)
 
 	case explainPlan:
 		node := &explainPlanNode{
-			verbose: verbose,
-			plan:    plan,
+			verbose:   verbose,
+			recommend: recommend,
+			plan:      plan,
 		}
 		return node, nil
 
@@ -220,8 +224,11 @@ func populateTypes(v *valuesNode, plan planNode, level int) {
 
 type explainPlanNode struct {
 	verbose bool
-	plan    planNode
-	results *valuesNode
+	// If set, the plan is followed by the CREATE INDEX statements which would
+	// improve it (see collectIndexRecommendations).
+	recommend bool
+	plan      planNode
+	results   *valuesNode
 }
 
 func (e *explainPlanNode) ExplainTypes(fn func(string, string)) {}
@@ -260,6 +267,19 @@ func (e *explainPlanNode) ExplainPlan(v bool) (string, string, []planNode) {
 
 func (e *explainPlanNode) Start() error {
 	populateExplain(e.verbose, e.results, e.plan, 0)
+	if e.recommend {
+		for _, rec := range collectIndexRecommendations(e.plan, nil) {
+			row := parser.DTuple{
+				parser.NewDInt(0),
+				parser.NewDString("recommendation"),
+				parser.NewDString(rec),
+			}
+			if e.verbose {
+				row = append(row, parser.NewDString(""), parser.NewDString(""))
+			}
+			e.results.rows = append(e.results.rows, row)
+		}
+	}
 	return nil
 }
 
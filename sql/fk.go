@@ -15,12 +15,15 @@
 package sql
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/security"
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/encoding"
 	"github.com/pkg/errors"
 )
 
@@ -69,7 +72,108 @@ func TablesNeededForFKs(table sqlbase.TableDescriptor, usage FKCheck) TablesByID
 	return ret
 }
 
-type fkInsertHelper map[sqlbase.IndexID][]baseFKHelper
+// fkBatchChecker accumulates the lookups needed by FK checks so that they can
+// be sent together in a single KV batch, instead of one lookup per row. The
+// checks are performed by run, which must be called before the writes they
+// guard are sent.
+type fkBatchChecker struct {
+	txn    *client.Txn
+	checks []fkPendingCheck
+	// Whether some of the checked FKs are deferred, and where their checks are
+	// queued instead of being performed by run, if they are deferred to the
+	// end of the transaction.
+	deferrable bool
+	deferred   *fkDeferredChecks
+}
+
+// fkPendingCheck is a lookup queued in a fkBatchChecker.
+type fkPendingCheck struct {
+	fk *baseFKHelper
+	// The searched key and, unless all the values of the index are searched,
+	// the values it encodes (for error messages).
+	key      roachpb.Key
+	fkValues parser.DTuple
+	// Whether the check is for an inserted value, which must exist, or for a
+	// deleted value, which must not be referenced.
+	insert bool
+}
+
+func (c *fkBatchChecker) add(fk *baseFKHelper, values parser.DTuple, insert bool) error {
+	key, fkValues, err := fk.searchKey(values)
+	if err != nil {
+		return err
+	}
+	if fk.deferred && c.deferred != nil && fkValues != nil {
+		return c.deferred.add(fk.deferredCheck(fkValues, insert))
+	}
+	c.checks = append(c.checks, fkPendingCheck{fk: fk, key: key, fkValues: fkValues, insert: insert})
+	return nil
+}
+
+// run performs the queued checks, returning the violation of the first one
+// that fails, if any.
+func (c *fkBatchChecker) run() error {
+	if c == nil || len(c.checks) == 0 {
+		return nil
+	}
+	checks := c.checks
+	c.checks = c.checks[:0]
+
+	b := c.txn.NewBatch()
+	for _, check := range checks {
+		b.Scan(check.key, check.key.PrefixEnd(), 1)
+	}
+	if err := c.txn.Run(b); err != nil {
+		return err
+	}
+
+	for i, check := range checks {
+		found := false
+		if rows := b.Results[i].Rows; len(rows) > 0 {
+			if _, ok, err := check.fk.rf.ReadIndexKey(rows[0].Key); err == nil && ok {
+				found = true
+			} else {
+				// The key belongs to a table or index interleaved in the
+				// searched span; fall back to a scan that skips such keys.
+				row, err := check.fk.lookup(check.key)
+				if err != nil {
+					return err
+				}
+				found = row != nil
+			}
+		}
+		if err := check.violation(found); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// violation returns the FK violation error, if any, given whether the searched
+// key was found.
+func (check fkPendingCheck) violation(found bool) error {
+	fk := check.fk
+	if check.insert {
+		if !found {
+			return sqlbase.NewForeignKeyViolationError("value %s not found in %s@%s %s", check.fkValues, fk.searchTable.Name, fk.searchIdx.Name, fk.searchIdx.ColumnNames)
+		}
+		return nil
+	}
+	if found {
+		if check.fkValues == nil {
+			return sqlbase.NewForeignKeyViolationError("non-empty columns %s referenced in table %q",
+				fk.writeIdx.ColumnNames, fk.searchTable.Name)
+		}
+		return sqlbase.NewForeignKeyViolationError("value(s) %v in columns %s referenced in table %q",
+			check.fkValues, fk.writeIdx.ColumnNames, fk.searchTable.Name)
+	}
+	return nil
+}
+
+type fkInsertHelper struct {
+	fks     map[sqlbase.IndexID][]baseFKHelper
+	checker *fkBatchChecker
+}
 
 var errSkipUnsedFK = errors.New("no columns involved in FK included in writer")
 
@@ -86,17 +190,22 @@ func makeFKInsertHelper(
 			if err != nil {
 				return fks, err
 			}
-			if fks == nil {
-				fks = make(fkInsertHelper)
+			fk.writeTable = &table
+			fk.deferred = idx.ForeignKey.InitiallyDeferred
+			if fks.fks == nil {
+				fks.fks = make(map[sqlbase.IndexID][]baseFKHelper)
+				fks.checker = &fkBatchChecker{txn: txn}
 			}
-			fks[idx.ID] = append(fks[idx.ID], fk)
+			fks.checker.deferrable = fks.checker.deferrable || fk.deferred
+			fks.fks[idx.ID] = append(fks.fks[idx.ID], fk)
 		}
 	}
 	return fks, nil
 }
 
+// checkAll queues the checks of all the FKs of the table for the given row.
 func (fks fkInsertHelper) checkAll(row parser.DTuple) error {
-	for idx := range fks {
+	for idx := range fks.fks {
 		if err := fks.checkIdx(idx, row); err != nil {
 			return err
 		}
@@ -104,8 +213,10 @@ func (fks fkInsertHelper) checkAll(row parser.DTuple) error {
 	return nil
 }
 
+// checkIdx queues the checks of the FKs of the given index for the given row.
 func (fks fkInsertHelper) checkIdx(idx sqlbase.IndexID, row parser.DTuple) error {
-	for _, fk := range fks[idx] {
+	for i := range fks.fks[idx] {
+		fk := &fks.fks[idx][i]
 		nulls := true
 		for i := range fk.searchIdx.ColumnIDs {
 			found, ok := fk.ids[fk.searchIdx.ColumnIDs[i]]
@@ -118,22 +229,28 @@ func (fks fkInsertHelper) checkIdx(idx sqlbase.IndexID, row parser.DTuple) error
 			continue
 		}
 
-		found, err := fk.check(row)
-		if err != nil {
+		if err := fks.checker.add(fk, row, true); err != nil {
 			return err
 		}
-		if found == nil {
-			fkValues := make(parser.DTuple, len(fk.searchIdx.ColumnIDs))
-			for i := range fk.searchIdx.ColumnIDs {
-				fkValues[i] = row[fk.ids[fk.searchIdx.ColumnIDs[i]]]
-			}
-			return fmt.Errorf("foreign key violation: value %s not found in %s@%s %s", fkValues, fk.searchTable.Name, fk.searchIdx.Name, fk.searchIdx.ColumnNames)
-		}
 	}
 	return nil
 }
 
-type fkDeleteHelper map[sqlbase.IndexID][]baseFKHelper
+// runChecks performs the checks queued so far.
+func (fks fkInsertHelper) runChecks() error {
+	return fks.checker.run()
+}
+
+// fkDeleteHelper checks that deleted values are not referenced, or queues
+// the referential actions of the FKs referencing them.
+type fkDeleteHelper struct {
+	fks     map[sqlbase.IndexID][]baseFKHelper
+	checker *fkBatchChecker
+	// The ON DELETE or ON UPDATE actions of the FKs, set by initActions. They
+	// are indexed like fks, with nil entries for the FKs which restrict the
+	// deletion or update.
+	actions map[sqlbase.IndexID][]*fkAction
+}
 
 func makeFKDeleteHelper(
 	txn *client.Txn, table sqlbase.TableDescriptor, otherTables TablesByID, colMap map[sqlbase.ColumnID]int,
@@ -148,17 +265,24 @@ func makeFKDeleteHelper(
 			if err != nil {
 				return fks, err
 			}
-			if fks == nil {
-				fks = make(fkDeleteHelper)
+			// The FK is described by the referencing index.
+			fk.writeTable = &table
+			fk.deferred = fk.searchIdx.ForeignKey != nil && fk.searchIdx.ForeignKey.InitiallyDeferred
+			if fks.fks == nil {
+				fks.fks = make(map[sqlbase.IndexID][]baseFKHelper)
+				fks.checker = &fkBatchChecker{txn: txn}
 			}
-			fks[idx.ID] = append(fks[idx.ID], fk)
+			fks.checker.deferrable = fks.checker.deferrable || fk.deferred
+			fks.fks[idx.ID] = append(fks.fks[idx.ID], fk)
 		}
 	}
 	return fks, nil
 }
 
+// checkAll queues the checks of all the FKs referencing the table for the
+// given row. A nil row checks that no value at all is referenced.
 func (fks fkDeleteHelper) checkAll(row parser.DTuple) error {
-	for idx := range fks {
+	for idx := range fks.fks {
 		if err := fks.checkIdx(idx, row); err != nil {
 			return err
 		}
@@ -166,29 +290,388 @@ func (fks fkDeleteHelper) checkAll(row parser.DTuple) error {
 	return nil
 }
 
+// checkIdx queues the checks or the actions of the FKs referencing the given
+// index for the given row.
 func (fks fkDeleteHelper) checkIdx(idx sqlbase.IndexID, row parser.DTuple) error {
-	for _, fk := range fks[idx] {
-		found, err := fk.check(row)
-		if err != nil {
+	return fks.checkOrQueueIdx(idx, row, nil)
+}
+
+// checkOrQueueIdx is checkIdx for a row which is either deleted, if newRow is
+// nil, or updated to newRow.
+func (fks fkDeleteHelper) checkOrQueueIdx(idx sqlbase.IndexID, row, newRow parser.DTuple) error {
+	actions := fks.actions[idx]
+	for i := range fks.fks[idx] {
+		if actions != nil && actions[i] != nil && row != nil {
+			actions[i].add(row, newRow)
+			continue
+		}
+		if err := fks.checker.add(&fks.fks[idx][i], row, false); err != nil {
 			return err
 		}
-		if found != nil {
-			if row == nil {
-				return fmt.Errorf("foreign key violation: non-empty columns %s referenced in table %q",
-					fk.writeIdx.ColumnNames, fk.searchTable.Name)
+	}
+	return nil
+}
+
+// runChecks performs the checks queued so far.
+func (fks fkDeleteHelper) runChecks() error {
+	return fks.checker.run()
+}
+
+// initActions sets up the ON DELETE actions of the FKs referencing the table,
+// or their ON UPDATE actions for CheckUpdates, which are run by the given
+// planner on the referencing rows instead of checking that the deleted or
+// updated values are not referenced.
+func (fks *fkDeleteHelper) initActions(p *planner, usage FKCheck) error {
+	for idx, helpers := range fks.fks {
+		for i := range helpers {
+			ref := helpers[i].searchIdx.ForeignKey
+			if ref == nil {
+				continue
+			}
+			action := ref.OnDelete
+			if usage == CheckUpdates {
+				action = ref.OnUpdate
+			}
+			if action == sqlbase.ForeignKeyReference_RESTRICT {
+				continue
+			}
+			a, err := makeFKAction(p, &helpers[i], action, usage == CheckUpdates)
+			if err != nil {
+				return err
+			}
+			if fks.actions == nil {
+				fks.actions = make(map[sqlbase.IndexID][]*fkAction)
 			}
-			fkValues := make(parser.DTuple, len(fk.searchIdx.ColumnIDs))
-			for i := range fk.searchIdx.ColumnIDs {
-				fkValues[i] = row[fk.ids[fk.searchIdx.ColumnIDs[i]]]
+			if fks.actions[idx] == nil {
+				fks.actions[idx] = make([]*fkAction, len(helpers))
 			}
-			return fmt.Errorf("foreign key violation: value(s) %v in columns %s referenced in table %q",
-				fkValues, fk.writeIdx.ColumnNames, fk.searchTable.Name)
+			fks.actions[idx][i] = a
 		}
+	}
+	return nil
+}
+
+// hasActions returns whether there are referential actions to run after the
+// referenced rows are deleted or updated.
+func (fks fkDeleteHelper) hasActions() bool {
+	return len(fks.actions) > 0
+}
 
+// runActions runs the referential actions queued so far. It must be called
+// once the writes of the rows they were queued for have been run, so that
+// the actions which cascade back to the table don't find them again.
+func (fks fkDeleteHelper) runActions() error {
+	for _, actions := range fks.actions {
+		for _, a := range actions {
+			if a == nil {
+				continue
+			}
+			if err := a.run(); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// fkVisitedRow identifies the rows referencing a row through a FK: the
+// referencing table and index, and the encoded referenced values. The
+// statements of an action can run the actions of the FKs referencing the rows
+// they write, and an action coming back to rows already written by the
+// statement is a cycle.
+type fkVisitedRow struct {
+	table sqlbase.ID
+	index sqlbase.IndexID
+	key   string
+}
+
+// fkAction runs the referential action of a FK on the rows referencing the
+// values deleted or updated in the referenced table. Rather than handling the
+// referencing rows one by one, the old values are queued and the rows
+// referencing any of them are deleted or updated by a single statement, run
+// in the transaction of the write. The statement runs the actions of the FKs
+// referencing the rows it writes in turn.
+type fkAction struct {
+	p      *planner
+	fk     *baseFKHelper
+	action sqlbase.ForeignKeyReference_Action
+	// Whether the action is run on updates rather than deletions.
+	update bool
+	// The qualified name of the referencing table, and the referencing
+	// columns.
+	table string
+	cols  []string
+	// The queued values, len(cols) per row, and for updates the new values
+	// of the rows.
+	values    []parser.Datum
+	newValues []parser.Datum
+}
+
+func makeFKAction(
+	p *planner, fk *baseFKHelper, action sqlbase.ForeignKeyReference_Action, update bool,
+) (*fkAction, error) {
+	table, err := fkTableName(p.txn, fk.searchTable)
+	if err != nil {
+		return nil, err
+	}
+	a := &fkAction{
+		p:      p,
+		fk:     fk,
+		action: action,
+		update: update,
+		table:  table,
+	}
+	for i, id := range fk.searchIdx.ColumnIDs {
+		if _, ok := fk.ids[id]; !ok {
+			break
+		}
+		a.cols = append(a.cols, fk.searchIdx.ColumnNames[i])
+	}
+	return a, nil
+}
+
+// add queues the values of a deleted row, or of an updated row along with its
+// new values. NULL values are not referenced, and the updated rows whose
+// referenced values don't change are skipped.
+func (a *fkAction) add(row, newRow parser.DTuple) {
+	changed := newRow == nil
+	for i := range a.cols {
+		j := a.fk.ids[a.fk.searchIdx.ColumnIDs[i]]
+		if row[j] == parser.DNull {
+			return
+		}
+		if newRow != nil && row[j].Compare(newRow[j]) != 0 {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	for i := range a.cols {
+		j := a.fk.ids[a.fk.searchIdx.ColumnIDs[i]]
+		a.values = append(a.values, row[j])
+		if newRow != nil {
+			a.newValues = append(a.newValues, newRow[j])
+		}
+	}
+}
+
+// run deletes or updates the rows referencing the queued values, in chunks of
+// the size of the batches of the table writers.
+func (a *fkAction) run() error {
+	if len(a.values) == 0 {
+		return nil
+	}
+	values, newValues := a.values, a.newValues
+	a.values, a.newValues = nil, nil
+	if a.p.fkVisited == nil {
+		// The outermost action of the statement; the nested ones share its
+		// visited rows.
+		a.p.fkVisited = make(map[fkVisitedRow]struct{})
+		defer func() { a.p.fkVisited = nil }()
+	}
+	for i := 0; i < len(values); i += len(a.cols) {
+		key, err := encodeFKValues(values[i : i+len(a.cols)])
+		if err != nil {
+			return err
+		}
+		row := fkVisitedRow{table: a.fk.searchTable.ID, index: a.fk.searchIdx.ID, key: key}
+		if _, ok := a.p.fkVisited[row]; ok {
+			return errors.Errorf("foreign key actions cycle back to the rows of %s referencing %v",
+				a.table, values[i:i+len(a.cols)])
+		}
+		a.p.fkVisited[row] = struct{}{}
+	}
+	chunkSize := tableWriterBatchSize * len(a.cols)
+	if a.update && len(values) > chunkSize {
+		// The rows set to new values by a chunk would be updated again by a
+		// later chunk whose old values are the same.
+		overlap, err := fkValuesOverlap(values, newValues, len(a.cols))
+		if err != nil {
+			return err
+		}
+		if overlap {
+			chunkSize = len(values)
+		}
+	}
+	for len(values) > 0 {
+		n := len(values)
+		if n > chunkSize {
+			n = chunkSize
+		}
+		var newChunk []parser.Datum
+		if a.update {
+			newChunk = newValues[:n]
+			newValues = newValues[n:]
+		}
+		if err := a.runChunk(values[:n], newChunk); err != nil {
+			return err
+		}
+		values = values[n:]
+	}
+	return nil
+}
+
+// fkValuesOverlap returns whether one of the rows of new values, of numCols
+// columns each, is equal to one of the rows of old values.
+func fkValuesOverlap(values, newValues []parser.Datum, numCols int) (bool, error) {
+	old := make(map[string]struct{}, len(values)/numCols)
+	for i := 0; i < len(values); i += numCols {
+		key, err := encodeFKValues(values[i : i+numCols])
+		if err != nil {
+			return false, err
+		}
+		old[key] = struct{}{}
+	}
+	for i := 0; i < len(newValues); i += numCols {
+		key, err := encodeFKValues(newValues[i : i+numCols])
+		if err != nil {
+			return false, err
+		}
+		if _, ok := old[key]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// encodeFKValues encodes a row of FK values, so that they can be compared.
+func encodeFKValues(row []parser.Datum) (string, error) {
+	var key []byte
+	for _, d := range row {
+		var err error
+		if key, err = sqlbase.EncodeTableKey(key, d, encoding.Ascending); err != nil {
+			return "", err
+		}
+	}
+	return string(key), nil
+}
+
+// runChunk deletes or updates the rows referencing some of the queued values,
+// along with their new values for updates.
+func (a *fkAction) runChunk(values, newValues []parser.Datum) error {
+	// The old values are the first placeholders, followed by the new ones.
+	args := make([]interface{}, 0, len(values)+len(newValues))
+	for _, d := range values {
+		args = append(args, d)
+	}
+	for _, d := range newValues {
+		args = append(args, d)
+	}
+	numRows := len(values) / len(a.cols)
+	numValues := len(values)
+
+	var buf bytes.Buffer
+	switch {
+	case a.action == sqlbase.ForeignKeyReference_CASCADE && !a.update:
+		fmt.Fprintf(&buf, "DELETE FROM %s", a.table)
+		writeFKInList(&buf, a.cols, numRows)
+	case a.action == sqlbase.ForeignKeyReference_CASCADE:
+		// Each referencing column is set to the new value of the row it
+		// references. Every referencing row matches exactly one WHEN, since
+		// the old values are distinct.
+		refCols := fkColumns(a.cols)
+		fmt.Fprintf(&buf, "UPDATE %s SET ", a.table)
+		for j, col := range a.cols {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%s = CASE", quoteNames(col))
+			for row := 0; row < numRows; row++ {
+				fmt.Fprintf(&buf, " WHEN %s = ", refCols)
+				writeFKPlaceholders(&buf, len(a.cols), row)
+				fmt.Fprintf(&buf, " THEN $%d", numValues+row*len(a.cols)+j+1)
+			}
+			fmt.Fprintf(&buf, " ELSE %s END", quoteNames(col))
+		}
+		writeFKInList(&buf, a.cols, numRows)
+	case a.action == sqlbase.ForeignKeyReference_SET_NULL,
+		a.action == sqlbase.ForeignKeyReference_SET_DEFAULT:
+		val := "NULL"
+		if a.action == sqlbase.ForeignKeyReference_SET_DEFAULT {
+			val = "DEFAULT"
+		}
+		fmt.Fprintf(&buf, "UPDATE %s SET ", a.table)
+		for j, col := range a.cols {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%s = %s", quoteNames(col), val)
+		}
+		writeFKInList(&buf, a.cols, numRows)
+	default:
+		return errors.Errorf("unsupported foreign key action %s", a.action)
+	}
+
+	ip := a.p.makeFKPlanner()
+	defer ip.releaseLeases()
+	_, err := ip.exec(buf.String(), args...)
+	return err
+}
+
+// makeFKPlanner returns the planner running the statements of the referential
+// actions and the deferred checks, in the transaction of p and without
+// privilege checks.
+func (p *planner) makeFKPlanner() *planner {
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	ip.systemConfig = p.systemConfig
+	ip.databaseCache = p.databaseCache
+	ip.evalCtx.NodeID = p.evalCtx.NodeID
+	ip.triggerDepth = p.triggerDepth
+	ip.fkVisited = p.fkVisited
+	return ip
+}
+
+// fkTableName returns the qualified name of a table, as used in the
+// statements run for FKs.
+func fkTableName(txn *client.Txn, table *sqlbase.TableDescriptor) (string, error) {
+	dbDesc, err := getDatabaseDescFromID(txn, table.ParentID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s", quoteNames(dbDesc.Name), quoteNames(table.Name)), nil
+}
+
+// fkColumns formats the columns of a FK, as a tuple if there are several.
+func fkColumns(cols []string) string {
+	if len(cols) == 1 {
+		return quoteNames(cols[0])
+	}
+	return fmt.Sprintf("(%s)", quoteNames(cols...))
+}
+
+// writeFKPlaceholders writes the placeholders of the values of the given row
+// for a FK with numCols columns, the values of the rows being the first
+// arguments of the statement.
+func writeFKPlaceholders(buf *bytes.Buffer, numCols, row int) {
+	if numCols > 1 {
+		buf.WriteByte('(')
+	}
+	for j := 0; j < numCols; j++ {
+		if j > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(buf, "$%d", row*numCols+j+1)
+	}
+	if numCols > 1 {
+		buf.WriteByte(')')
+	}
+}
+
+// writeFKInList writes the condition matching the rows whose FK columns have
+// the values of any of the given number of rows.
+func writeFKInList(buf *bytes.Buffer, cols []string, numRows int) {
+	fmt.Fprintf(buf, " WHERE %s IN (", fkColumns(cols))
+	for row := 0; row < numRows; row++ {
+		if row > 0 {
+			buf.WriteString(", ")
+		}
+		writeFKPlaceholders(buf, len(cols), row)
+	}
+	buf.WriteByte(')')
+}
+
 type fkUpdateHelper struct {
 	inbound  fkDeleteHelper // Check old values are not referenced.
 	outbound fkInsertHelper // Check rows referenced by new values still exist.
@@ -202,17 +685,33 @@ func makeFKUpdateHelper(
 	if ret.inbound, err = makeFKDeleteHelper(txn, table, otherTables, colMap); err != nil {
 		return ret, err
 	}
-	ret.outbound, err = makeFKInsertHelper(txn, table, otherTables, colMap)
-	return ret, err
+	if ret.outbound, err = makeFKInsertHelper(txn, table, otherTables, colMap); err != nil {
+		return ret, err
+	}
+	// Share the checker, so that all the checks are sent in the same batch.
+	if ret.inbound.checker == nil {
+		ret.inbound.checker = ret.outbound.checker
+	} else {
+		if ret.outbound.checker != nil && ret.outbound.checker.deferrable {
+			ret.inbound.checker.deferrable = true
+		}
+		ret.outbound.checker = ret.inbound.checker
+	}
+	return ret, nil
 }
 
 func (fks fkUpdateHelper) checkIdx(idx sqlbase.IndexID, oldValues, newValues parser.DTuple) error {
-	if err := fks.inbound.checkIdx(idx, oldValues); err != nil {
+	if err := fks.inbound.checkOrQueueIdx(idx, oldValues, newValues); err != nil {
 		return err
 	}
 	return fks.outbound.checkIdx(idx, newValues)
 }
 
+// runChecks performs the checks queued so far.
+func (fks fkUpdateHelper) runChecks() error {
+	return fks.inbound.checker.run()
+}
+
 type baseFKHelper struct {
 	txn          *client.Txn
 	rf           sqlbase.RowFetcher
@@ -221,6 +720,8 @@ type baseFKHelper struct {
 	writeIdx     sqlbase.IndexDescriptor  // the index we want to modify
 	searchPrefix []byte                   // prefix of keys in searchIdx
 	ids          map[sqlbase.ColumnID]int // col IDs
+	writeTable   *sqlbase.TableDescriptor // the table of writeIdx
+	deferred     bool                     // whether the FK is initially deferred
 }
 
 func makeBaseFKHelper(
@@ -255,7 +756,12 @@ func makeBaseFKHelper(
 
 	b.ids = make(map[sqlbase.ColumnID]int, len(writeIdx.ColumnIDs))
 	nulls := true
+	// The foreign key is on the prefix of the written index which matches the
+	// searched index.
 	for i := range writeIdx.ColumnIDs {
+		if i >= len(searchIdx.ColumnIDs) {
+			break
+		}
 		if found, ok := colMap[writeIdx.ColumnIDs[i]]; ok {
 			b.ids[searchIdx.ColumnIDs[i]] = found
 			nulls = false
@@ -267,22 +773,221 @@ func makeBaseFKHelper(
 	return b, nil
 }
 
-// TODO(dt): Batch checks of many rows.
-func (f baseFKHelper) check(values parser.DTuple) (parser.DTuple, error) {
-	var key roachpb.Key
-	if values != nil {
-		keyBytes, _, err := sqlbase.EncodeIndexKey(
-			f.searchTable, f.searchIdx, f.ids, values, f.searchPrefix)
-		if err != nil {
-			return nil, err
-		}
-		key = roachpb.Key(keyBytes)
-	} else {
-		key = roachpb.Key(f.searchPrefix)
+// searchKey returns the key to search for in searchIdx for the given values
+// and the values of the searched columns. If values is nil, the whole index is
+// searched and no values are returned.
+func (f *baseFKHelper) searchKey(values parser.DTuple) (roachpb.Key, parser.DTuple, error) {
+	if values == nil {
+		return roachpb.Key(f.searchPrefix), nil, nil
+	}
+	keyBytes, _, err := sqlbase.EncodeIndexKey(
+		f.searchTable, f.searchIdx, f.ids, values, f.searchPrefix)
+	if err != nil {
+		return nil, nil, err
 	}
+	// The values are copied since the caller may reuse the row.
+	fkValues := make(parser.DTuple, len(f.searchIdx.ColumnIDs))
+	for i := range f.searchIdx.ColumnIDs {
+		fkValues[i] = values[f.ids[f.searchIdx.ColumnIDs[i]]]
+	}
+	return roachpb.Key(keyBytes), fkValues, nil
+}
+
+// lookup returns the first row of searchIdx under the given key, if any.
+func (f *baseFKHelper) lookup(key roachpb.Key) (parser.DTuple, error) {
 	spans := sqlbase.Spans{sqlbase.Span{Start: key, End: key.PrefixEnd()}}
 	if err := f.rf.StartScan(f.txn, spans, 1); err != nil {
 		return nil, err
 	}
 	return f.rf.NextRow()
 }
+
+// deferredCheck returns the deferred check of the given values of the FK,
+// written to the referencing index if insert is set, and to the referenced
+// one otherwise.
+func (f *baseFKHelper) deferredCheck(values parser.DTuple, insert bool) fkDeferredCheck {
+	// Only the prefix of the searched index matching the written one is
+	// part of the FK.
+	n := 0
+	for _, id := range f.searchIdx.ColumnIDs {
+		if _, ok := f.ids[id]; !ok {
+			break
+		}
+		n++
+	}
+	writeIdx := f.writeIdx
+	check := fkDeferredCheck{
+		table:    f.searchTable,
+		idx:      f.searchIdx,
+		refTable: f.writeTable,
+		refIdx:   &writeIdx,
+		values:   values[:n],
+	}
+	if insert {
+		check.table, check.refTable = check.refTable, check.table
+		check.idx, check.refIdx = check.refIdx, check.idx
+	}
+	return check
+}
+
+// maxDeferredFKChecks is the number of distinct values written to deferred
+// FKs that a transaction can have checked when it commits.
+var maxDeferredFKChecks = 100000
+
+// fkDeferredChecks accumulates the checks of the deferred FKs made by the
+// statements of an explicit transaction, which are performed when it commits
+// rather than at the end of each statement.
+type fkDeferredChecks struct {
+	checks []fkDeferredCheck
+	// The keys of the queued checks, so that a value written several times is
+	// checked once.
+	queued map[string]struct{}
+}
+
+// add queues a check, unless the same value of the FK is already checked.
+func (d *fkDeferredChecks) add(check fkDeferredCheck) error {
+	key := fmt.Sprintf("%d/%d/%s", check.table.ID, check.idx.ID, parser.AsString(&check.values))
+	if _, ok := d.queued[key]; ok {
+		return nil
+	}
+	if len(d.checks) >= maxDeferredFKChecks {
+		return errors.Errorf("more than %d values written to deferred foreign keys in "+
+			"the transaction, split it", maxDeferredFKChecks)
+	}
+	if d.queued == nil {
+		d.queued = make(map[string]struct{})
+	}
+	d.queued[key] = struct{}{}
+	d.checks = append(d.checks, check)
+	return nil
+}
+
+// fkDeferredCheck is the check of a value written to a deferred FK. The value
+// violates the FK if it is still referenced when the transaction commits, but
+// is not found in the referenced index anymore.
+type fkDeferredCheck struct {
+	// The referencing and referenced tables and indexes.
+	table, refTable *sqlbase.TableDescriptor
+	idx, refIdx     *sqlbase.IndexDescriptor
+	values          parser.DTuple
+}
+
+// deferFKChecks makes the checks of the deferred FKs done by the given
+// checker wait for the transaction to commit, if it is explicit; the
+// statements of implicit transactions perform them like the others. It
+// returns whether a statement which could commit the transaction along with
+// its writes still can, which is not the case if deferred checks may have to
+// be performed before.
+func (p *planner) deferFKChecks(c *fkBatchChecker, autoCommit bool) bool {
+	d := p.session.TxnState.deferredFKs
+	if d == nil {
+		return autoCommit
+	}
+	if c != nil && c.deferrable {
+		c.deferred = d
+		return false
+	}
+	return autoCommit && len(d.checks) == 0
+}
+
+// run performs the checks queued so far, with one statement per FK looking
+// up the values still referenced and then the referenced ones.
+func (d *fkDeferredChecks) run(p *planner) error {
+	if d == nil || len(d.checks) == 0 {
+		return nil
+	}
+	type fkID struct {
+		table sqlbase.ID
+		idx   sqlbase.IndexID
+	}
+	var fks []fkID
+	byFK := make(map[fkID][]fkDeferredCheck)
+	for _, check := range d.checks {
+		id := fkID{table: check.table.ID, idx: check.idx.ID}
+		if _, ok := byFK[id]; !ok {
+			fks = append(fks, id)
+		}
+		byFK[id] = append(byFK[id], check)
+	}
+	d.checks = nil
+	d.queued = nil
+
+	ip := p.makeFKPlanner()
+	defer ip.releaseLeases()
+	for _, id := range fks {
+		if err := runDeferredFKChecks(ip, byFK[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runDeferredFKChecks performs the deferred checks of the values of a FK, in
+// chunks of the size of the batches of the table writers.
+func runDeferredFKChecks(p *planner, checks []fkDeferredCheck) error {
+	for len(checks) > 0 {
+		chunk := checks
+		if len(chunk) > tableWriterBatchSize {
+			chunk = chunk[:tableWriterBatchSize]
+		}
+		checks = checks[len(chunk):]
+		if err := runDeferredFKChecksChunk(p, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runDeferredFKChecksChunk performs the deferred checks of some values of a FK.
+func runDeferredFKChecksChunk(p *planner, checks []fkDeferredCheck) error {
+	fk := checks[0]
+	n := len(fk.values)
+	args := make([]interface{}, 0, len(checks)*n)
+	for _, check := range checks {
+		for _, v := range check.values {
+			args = append(args, v)
+		}
+	}
+	table, err := fkTableName(p.txn, fk.table)
+	if err != nil {
+		return err
+	}
+	cols := fk.idx.ColumnNames[:n]
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "SELECT DISTINCT %s FROM %s", quoteNames(cols...), table)
+	writeFKInList(&buf, cols, len(checks))
+	referenced, err := p.queryRows(buf.String(), args...)
+	if err != nil || len(referenced) == 0 {
+		return err
+	}
+
+	args = args[:0]
+	for _, row := range referenced {
+		for _, v := range row {
+			args = append(args, v)
+		}
+	}
+	refTable, err := fkTableName(p.txn, fk.refTable)
+	if err != nil {
+		return err
+	}
+	refCols := fk.refIdx.ColumnNames[:n]
+	buf.Reset()
+	fmt.Fprintf(&buf, "SELECT %s FROM %s", quoteNames(refCols...), refTable)
+	writeFKInList(&buf, refCols, len(referenced))
+	found, err := p.queryRows(buf.String(), args...)
+	if err != nil {
+		return err
+	}
+	foundKeys := make(map[string]struct{}, len(found))
+	for _, row := range found {
+		foundKeys[parser.AsString(&row)] = struct{}{}
+	}
+	for _, row := range referenced {
+		if _, ok := foundKeys[parser.AsString(&row)]; !ok {
+			return sqlbase.NewForeignKeyViolationError("value %s not found in %s@%s %s",
+				row, fk.refTable.Name, fk.refIdx.Name, fk.refIdx.ColumnNames)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/testutils"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestDeferredFKChecks tests that the deferred FK checks are performed in
+// several chunks when a transaction commits, and that the number of values
+// they check is bounded.
+func TestDeferredFKChecks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	defer setTableWriterBatchSize(2)()
+	defer func(max int) { maxDeferredFKChecks = max }(maxDeferredFKChecks)
+	maxDeferredFKChecks = 5
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.parent (id INT PRIMARY KEY);
+CREATE TABLE d.child (id INT PRIMARY KEY, p INT REFERENCES d.parent DEFERRABLE INITIALLY DEFERRED);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// The values written several times are checked once.
+	if _, err := db.Exec(`
+BEGIN;
+INSERT INTO d.child VALUES (1, 1), (2, 2), (3, 3), (4, 4), (5, 5);
+INSERT INTO d.child VALUES (6, 1), (7, 2);
+INSERT INTO d.parent VALUES (1), (2), (3), (4), (5);
+COMMIT;
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// The violation is found in the last chunk.
+	if _, err := db.Exec(`
+BEGIN;
+INSERT INTO d.child VALUES (11, 1), (12, 2), (13, 3), (14, 4), (15, 6);
+COMMIT;
+`); !testutils.IsError(err, `foreign key violation: value \[6\] not found`) {
+		t.Fatalf("expected a foreign key violation, but found %v", err)
+	}
+
+	if _, err := db.Exec(`
+BEGIN;
+INSERT INTO d.child VALUES (21, 1), (22, 2), (23, 3), (24, 4), (25, 5), (26, 6);
+COMMIT;
+`); !testutils.IsError(err, "more than 5 values written to deferred foreign keys") {
+		t.Fatalf("expected too many deferred checks, but found %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM d.child`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 7 {
+		t.Fatalf("expected 7 rows, but found %d", count)
+	}
+}
+
+// TestFKCascadeChunks tests that the referential actions of a statement
+// writing more rows than the batch size of the table writers are run in
+// several chunks.
+func TestFKCascadeChunks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	defer setTableWriterBatchSize(2)()
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.parent (a INT, b INT, PRIMARY KEY (a, b));
+CREATE TABLE d.child (
+  id INT PRIMARY KEY, a INT, b INT,
+  INDEX (a, b),
+  FOREIGN KEY (a, b) REFERENCES d.parent ON DELETE CASCADE ON UPDATE CASCADE
+);
+INSERT INTO d.parent VALUES (1, 1), (2, 2), (3, 3), (4, 4), (5, 5);
+INSERT INTO d.child VALUES (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4), (5, 5, 5), (6, 1, 1);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(`UPDATE d.parent SET b = b + 10`); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM d.child WHERE b = a + 10`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 6 {
+		t.Fatalf("expected 6 updated rows, but found %d", count)
+	}
+
+	if _, err := db.Exec(`DELETE FROM d.parent WHERE a < 5`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM d.child`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 remaining row, but found %d", count)
+	}
+
+	// The new values of the referenced rows are the old values of others.
+	if _, err := db.Exec(`
+CREATE TABLE d.p (k INT PRIMARY KEY);
+CREATE TABLE d.c (id INT PRIMARY KEY, k INT REFERENCES d.p ON UPDATE CASCADE, INDEX (k));
+INSERT INTO d.p VALUES (2), (3), (4), (5), (6);
+INSERT INTO d.c VALUES (2, 2), (3, 3), (4, 4), (5, 5), (6, 6);
+UPDATE d.p SET k = k - 1;
+`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM d.c WHERE k = id - 1`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Fatalf("expected 5 rows referencing their new values, but found %d", count)
+	}
+}
+
+// TestFKValuesOverlap tests the detection of the cascaded updates whose new
+// values are the old values of other rows.
+func TestFKValuesOverlap(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	i := func(v int) parser.Datum { return parser.NewDInt(parser.DInt(v)) }
+	testCases := []struct {
+		values, newValues []parser.Datum
+		numCols           int
+		expected          bool
+	}{
+		{[]parser.Datum{i(1), i(2)}, []parser.Datum{i(3), i(4)}, 1, false},
+		{[]parser.Datum{i(1), i(2)}, []parser.Datum{i(2), i(3)}, 1, true},
+		{[]parser.Datum{i(1), i(2), i(3), i(4)}, []parser.Datum{i(2), i(1), i(4), i(3)}, 2, false},
+		{[]parser.Datum{i(1), i(2), i(3), i(4)}, []parser.Datum{i(3), i(4), i(5), i(6)}, 2, true},
+	}
+	for n, tc := range testCases {
+		overlap, err := fkValuesOverlap(tc.values, tc.newValues, tc.numCols)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if overlap != tc.expected {
+			t.Errorf("%d: expected %t, but found %t", n, tc.expected, overlap)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+)
+
+var _ parser.TxnRetrier = &planner{}
+
+// ForceRetry implements the parser.TxnRetrier interface.
+func (p *planner) ForceRetry(reason string) error {
+	if p.txn == nil {
+		return errors.New("cannot force a retry outside of a transaction")
+	}
+	return p.txn.GenerateForcedRetryableError(reason)
+}
+
+// maybeInjectRetry returns a retryable error, with the probability configured
+// by the FORCE_RETRY_PROBABILITY session variable, before the execution of a
+// statement. At most one error is injected per transaction, so that retried
+// transactions make progress.
+func (p *planner) maybeInjectRetry() error {
+	prob := p.session.ForceRetryProbability
+	txnState := &p.session.TxnState
+	if prob <= 0 || p.txn == nil || txnState.retryInjected {
+		return nil
+	}
+	if rand.Float64() >= prob {
+		return nil
+	}
+	txnState.retryInjected = true
+	return p.ForceRetry("injected by FORCE_RETRY_PROBABILITY")
+}
@@ -308,6 +308,9 @@ func (n *groupNode) Next() (bool, error) {
 			}
 		}
 		scratch = encoded[:0]
+		if err := n.planner.checkCancelled(); err != nil {
+			return false, err
+		}
 
 		n.gotOneRow = true
 
@@ -0,0 +1,193 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/encoding"
+)
+
+// collectIndexRecommendations walks a plan and returns the CREATE INDEX
+// statements which would improve it: for every scan which reads a whole table
+// to evaluate a filter, and for every sort of the rows of a scan, an index on
+// the columns constrained by the filter, followed by the columns of the sort,
+// is recommended (see recommendIndex).
+func collectIndexRecommendations(plan planNode, recs []string) []string {
+	switch n := plan.(type) {
+	case *sortNode:
+		if sel, ok := n.plan.(*selectNode); ok && n.needSort {
+			if scan := selectScan(sel); scan != nil {
+				if rec := recommendIndex(scan, sel, n.ordering); rec != "" {
+					recs = append(recs, rec)
+				}
+				return recs
+			}
+		}
+
+	case *selectNode:
+		if scan := selectScan(n); scan != nil {
+			if scan.isFullScan() {
+				if rec := recommendIndex(scan, n, nil); rec != "" {
+					recs = append(recs, rec)
+				}
+			}
+			return recs
+		}
+	}
+
+	_, _, children := plan.ExplainPlan(true)
+	for _, child := range children {
+		recs = collectIndexRecommendations(child, recs)
+	}
+	return recs
+}
+
+// selectScan returns the scan of the index whose rows are rendered by the
+// select node, if any.
+func selectScan(sel *selectNode) *scanNode {
+	switch n := sel.source.plan.(type) {
+	case *scanNode:
+		return n
+	case *indexJoinNode:
+		return n.index
+	}
+	return nil
+}
+
+// isFullScan returns whether the scan reads all the keys of its index.
+func (n *scanNode) isFullScan() bool {
+	if len(n.spans) == 0 {
+		return true
+	}
+	prefix := roachpb.Key(sqlbase.MakeIndexKeyPrefix(&n.desc, n.index.ID))
+	return len(n.spans) == 1 && n.spans[0].Start.Equal(prefix) &&
+		n.spans[0].End.Equal(prefix.PrefixEnd())
+}
+
+// recommendIndex returns a CREATE INDEX statement for an index which would
+// serve the scan better than the index it uses. The index starts with the
+// columns constrained to a single value, either by the index currently used or
+// by the remaining filter. They are followed by the columns of the desired
+// ordering (which refers to the renders of sel) if there is one, or else by
+// the first column constrained to a range by the filter. The empty string is
+// returned if no column could be determined or if the table already has an
+// index starting with these columns.
+func recommendIndex(scan *scanNode, sel *selectNode, ordering sqlbase.ColumnOrdering) string {
+	var colIdxs []int
+	var dirs []encoding.Direction
+	seen := make(map[int]struct{})
+	addCol := func(colIdx int, dir encoding.Direction) {
+		if _, ok := seen[colIdx]; ok {
+			return
+		}
+		seen[colIdx] = struct{}{}
+		colIdxs = append(colIdxs, colIdx)
+		dirs = append(dirs, dir)
+	}
+
+	exactMatch := make([]int, 0, len(scan.ordering.exactMatchCols))
+	for colIdx := range scan.ordering.exactMatchCols {
+		exactMatch = append(exactMatch, colIdx)
+	}
+	sort.Ints(exactMatch)
+	for _, colIdx := range exactMatch {
+		addCol(colIdx, encoding.Ascending)
+	}
+
+	rangeCol := -1
+	if scan.filter != nil {
+		for _, e := range splitAndExpr(scan.filter, nil) {
+			c, ok := e.(*parser.ComparisonExpr)
+			if !ok {
+				continue
+			}
+			if _, ok := c.TypedRight().(parser.Datum); !ok {
+				continue
+			}
+			ok, colIdx := getQValColIdx(c.Left)
+			if !ok {
+				continue
+			}
+			switch c.Operator {
+			case parser.EQ, parser.In:
+				addCol(colIdx, encoding.Ascending)
+			case parser.LT, parser.LE, parser.GT, parser.GE:
+				if rangeCol == -1 {
+					rangeCol = colIdx
+				}
+			}
+		}
+	}
+
+	if len(ordering) > 0 {
+		for _, o := range ordering {
+			qval, ok := sel.render[o.ColIdx].(*qvalue)
+			if !ok {
+				// The remaining columns of the ordering can't be provided by an
+				// index.
+				break
+			}
+			addCol(qval.colRef.colIdx, o.Direction)
+		}
+	} else if rangeCol != -1 {
+		addCol(rangeCol, encoding.Ascending)
+	}
+	if len(colIdxs) == 0 {
+		return ""
+	}
+
+	// Don't recommend an index the table already has.
+	for _, index := range append([]sqlbase.IndexDescriptor{scan.desc.PrimaryIndex}, scan.desc.Indexes...) {
+		if index.Type != sqlbase.IndexDescriptor_INVERTED && hasIndexPrefix(scan, &index, colIdxs, dirs) {
+			return ""
+		}
+	}
+
+	createIndex := &parser.CreateIndex{
+		Table: &parser.QualifiedName{Base: parser.Name(scan.desc.Name)},
+	}
+	for i, colIdx := range colIdxs {
+		elem := parser.IndexElem{Column: parser.Name(scan.cols[colIdx].Name)}
+		if dirs[i] == encoding.Descending {
+			elem.Direction = parser.Descending
+		}
+		createIndex.Columns = append(createIndex.Columns, elem)
+	}
+	return createIndex.String()
+}
+
+// hasIndexPrefix returns whether the first columns of the index are the given
+// columns of the scan, in the given directions.
+func hasIndexPrefix(
+	scan *scanNode, index *sqlbase.IndexDescriptor, colIdxs []int, dirs []encoding.Direction,
+) bool {
+	if len(colIdxs) > len(index.ColumnIDs) {
+		return false
+	}
+	for i, colIdx := range colIdxs {
+		if scan.cols[colIdx].ID != index.ColumnIDs[i] {
+			return false
+		}
+		indexDir, err := index.ColumnDirections[i].ToEncodingDirection()
+		if err != nil || indexDir != dirs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -84,10 +84,20 @@ func selectIndex(
 ) (planNode, error) {
 	if s.desc.IsEmpty() || (s.filter == nil && analyzeOrdering == nil && s.specifiedIndex == nil) {
 		// No table or no where-clause, no ordering, and no specified index.
+		if !s.desc.IsEmpty() && s.keysOnly() {
+			// No column values are needed (e.g. SELECT COUNT(*)); scan the index
+			// with the smallest keys.
+			s.index = cheapestKeysOnlyIndex(&s.desc)
+			s.isSecondaryIndex = (s.index != &s.desc.PrimaryIndex)
+		}
 		s.initOrdering(0)
 		return s, nil
 	}
 
+	if s.filter != nil {
+		s.filter = s.replaceComputedExprs(s.filter)
+	}
+
 	candidates := make([]*indexInfo, 0, len(s.desc.Indexes)+1)
 	if s.specifiedIndex != nil {
 		// An explicit secondary index was requested. Only add it to the candidate
@@ -102,6 +112,10 @@ func selectIndex(
 			index: &s.desc.PrimaryIndex,
 		})
 		for i := range s.desc.Indexes {
+			if s.desc.Indexes[i].Type == sqlbase.IndexDescriptor_INVERTED {
+				// The keys of an inverted index aren't the values of its column.
+				continue
+			}
 			candidates = append(candidates, &indexInfo{
 				desc:  &s.desc,
 				index: &s.desc.Indexes[i],
@@ -151,8 +165,21 @@ func selectIndex(
 		// number of disjunctive expressions we should limit how many indexes we
 		// use.
 
-		for _, c := range candidates {
-			c.analyzeExprs(exprs)
+		analyzed := candidates
+		if s.specifiedIndex == nil {
+			// A filter which constrains all the columns of the primary key to a
+			// single value selects at most one row. It is fetched from the primary
+			// index without considering the other indexes, which saves the cost
+			// of their analysis for the common point lookups.
+			primary := candidates[0]
+			primary.analyzeExprs(exprs)
+			if primary.isPointLookup() {
+				candidates = candidates[:1]
+			}
+			analyzed = candidates[1:]
+		}
+		for _, c := range analyzed {
+			c.analyzeExprs(s.addShardConstraint(c.index, exprs))
 		}
 	}
 
@@ -198,13 +225,23 @@ func selectIndex(
 	c := candidates[0]
 	s.index = c.index
 	s.isSecondaryIndex = (c.index != &s.desc.PrimaryIndex)
-	s.spans = makeSpans(c.constraints, c.desc, c.index)
+	s.reverse = c.reverse
+	// The rows of a point lookup are fetched with a single batch of Get
+	// requests. The keys of the gets are in increasing order, so a reverse
+	// scan only uses them for a single row.
+	s.pointLookup = c.isPointLookup()
+	if s.pointLookup {
+		s.spans = makePointLookupSpans(c.constraints, c.desc, c.index)
+		s.pointLookup = !s.reverse || len(s.spans) == 1
+	}
+	if !s.pointLookup {
+		s.spans = makeSpans(c.constraints, c.desc, c.index)
+	}
 	if len(s.spans) == 0 {
 		// There are no spans to scan.
 		return &emptyNode{}, nil
 	}
 	s.filter = applyIndexConstraints(s.filter, c.constraints)
-	s.reverse = c.reverse
 
 	var plan planNode
 	if c.covering {
@@ -227,6 +264,37 @@ func selectIndex(
 	return plan, nil
 }
 
+// addShardConstraint returns the analyzed filter expressions with which a
+// hash sharded index whose first sharded column is used by the filter is
+// analyzed: each disjunction additionally constrains the shard column to all
+// its buckets. The constraint always holds, but it lets the lookups of the
+// sharded columns use the index by scanning every bucket. The filter of the
+// scan isn't changed, so the constraint doesn't need to be evaluated when
+// another index is selected.
+func (n *scanNode) addShardConstraint(
+	index *sqlbase.IndexDescriptor, exprs []parser.TypedExprs,
+) []parser.TypedExprs {
+	if index.ShardBuckets == 0 {
+		return exprs
+	}
+	colIdx, ok := n.colIdxMap[index.ColumnIDs[1]]
+	if !ok || !n.filterVars.IndexedVarUsed(colIdx) {
+		return exprs
+	}
+	buckets := make(parser.DTuple, index.ShardBuckets)
+	for b := range buckets {
+		buckets[b] = parser.NewDInt(parser.DInt(b))
+	}
+	shardIdx := n.colIdxMap[index.ColumnIDs[0]]
+	shardExprs, _ := analyzeExpr(parser.NewTypedComparisonExpr(
+		parser.In, n.filterVars.IndexedVar(shardIdx), &buckets))
+	result := make([]parser.TypedExprs, len(exprs))
+	for i, e := range exprs {
+		result[i] = append(append(parser.TypedExprs(nil), e...), shardExprs[0]...)
+	}
+	return result
+}
+
 type indexConstraint struct {
 	start *parser.ComparisonExpr
 	end   *parser.ComparisonExpr
@@ -364,6 +432,43 @@ func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
 	}
 }
 
+// isPointLookup returns true if the constraints pin all the columns of the
+// primary index to one or a list of values (e.g. "k IN (1, 2, 3)"), each of
+// which identifies at most one row.
+func (v *indexInfo) isPointLookup() bool {
+	if v.index != &v.desc.PrimaryIndex || len(v.constraints) == 0 {
+		return false
+	}
+	for _, c := range v.constraints {
+		if c.pinnedPrefix() != len(v.index.ColumnIDs) {
+			return false
+		}
+	}
+	return true
+}
+
+// makePointLookupSpans returns the spans of the rows looked up by a point
+// lookup, one per row, in increasing order. Unlike makeSpans, the spans of
+// rows whose keys are adjacent are not merged.
+func makePointLookupSpans(
+	constraints orIndexConstraints, tableDesc *sqlbase.TableDescriptor, index *sqlbase.IndexDescriptor,
+) sqlbase.Spans {
+	var spans sqlbase.Spans
+	for _, c := range constraints {
+		spans = append(spans, makeSpansForIndexConstraints(c, tableDesc, index)...)
+	}
+	sort.Sort(spans)
+	n := 0
+	for i := range spans {
+		if n > 0 && spans[n-1].Start.Equal(spans[i].Start) {
+			continue
+		}
+		spans[n] = spans[i]
+		n++
+	}
+	return spans[:n]
+}
+
 // analyzeOrdering analyzes the ordering provided by the index and determines
 // if it matches the ordering requested by the query. Non-matching orderings
 // increase the cost of using the index.
@@ -767,6 +872,32 @@ func (v *indexInfo) isCoveringIndex(scan *scanNode) bool {
 	return true
 }
 
+// cheapestKeysOnlyIndex returns the index to scan when no column values are
+// needed. Every index contains a key for each row, so any of them can be used
+// to count the rows. A secondary index has a single key per row, whereas the
+// primary index has one key per column family, so the secondary index with the
+// fewest columns is preferred.
+func cheapestKeysOnlyIndex(desc *sqlbase.TableDescriptor) *sqlbase.IndexDescriptor {
+	index := &desc.PrimaryIndex
+	if len(desc.Columns) == len(desc.PrimaryIndex.ColumnIDs) {
+		// The primary index only holds the sentinel key of each row.
+		return index
+	}
+	numCols := -1
+	for i := range desc.Indexes {
+		idx := &desc.Indexes[i]
+		if idx.Type == sqlbase.IndexDescriptor_INVERTED {
+			// An inverted index has no key for the rows containing no element.
+			continue
+		}
+		n := len(idx.ColumnIDs) + len(idx.ImplicitColumnIDs)
+		if numCols == -1 || n < numCols {
+			index, numCols = idx, n
+		}
+	}
+	return index
+}
+
 type indexInfoByCost []*indexInfo
 
 func (v indexInfoByCost) Len() int {
@@ -1246,6 +1377,32 @@ func (ic indexConstraints) exactPrefix() int {
 
 }
 
+// pinnedPrefix returns the count of the columns of the index which are
+// constrained to one or a list of values in the indexConstraints, so that
+// each combination of the values is a separate span. Unlike exactPrefix, an
+// IN constraint pins its columns whatever the number of values.
+func (ic indexConstraints) pinnedPrefix() int {
+	prefix := 0
+	for _, c := range ic {
+		if c.start == nil || c.end == nil || c.start != c.end {
+			return prefix
+		}
+		switch c.start.Operator {
+		case parser.EQ:
+			prefix++
+		case parser.In:
+			if _, ok := c.start.Left.(*parser.Tuple); ok {
+				prefix += len(c.tupleMap)
+			} else {
+				prefix++
+			}
+		default:
+			return prefix
+		}
+	}
+	return prefix
+}
+
 // exactPrefixDatums returns the first num exact prefix values as Datums; num
 // must be at most ic.exactPrefix()
 func (ic indexConstraints) exactPrefixDatums(num int) []parser.Datum {
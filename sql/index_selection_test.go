@@ -615,6 +615,55 @@ func TestExactPrefix(t *testing.T) {
 	}
 }
 
+func TestIsPointLookup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		expr     string
+		expected bool
+		// The number of rows looked up, i.e. of point lookup spans.
+		rows int
+	}{
+		{`a = 1`, true, 1},
+		{`a = 1 AND b = 2`, true, 1},
+		{`a > 1`, false, 0},
+		// The spans of adjacent keys are not merged.
+		{`a IN (1, 2)`, true, 2},
+		{`a IN (1, 3, 5)`, true, 3},
+		{`a = 1 OR a = 2`, true, 2},
+		{`a = 1 OR a IN (1, 2)`, true, 2},
+		{`a = 1 OR a > 2`, false, 0},
+		{`b = 1`, false, 0},
+	}
+	for _, d := range testData {
+		desc := testTableDesc()
+		if err := desc.AllocateIDs(); err != nil {
+			t.Fatal(err)
+		}
+		expr, _ := parseAndNormalizeExpr(t, d.expr)
+		exprs, _ := analyzeExpr(expr)
+		c := &indexInfo{desc: desc, index: &desc.PrimaryIndex, covering: true}
+		c.analyzeExprs(exprs)
+		if isPointLookup := c.isPointLookup(); isPointLookup != d.expected {
+			t.Errorf("%s: expected %t, but found %t", d.expr, d.expected, isPointLookup)
+		} else if isPointLookup {
+			spans := makePointLookupSpans(c.constraints, desc, &desc.PrimaryIndex)
+			if len(spans) != d.rows {
+				t.Errorf("%s: expected %d spans, but found %s", d.expr, d.rows,
+					sqlbase.PrettySpans(spans, 2))
+			}
+		}
+	}
+
+	// Secondary indexes are not used for point lookups.
+	desc, index := makeTestIndexFromStr(t, "a")
+	constraints, _ := makeConstraints(t, `a = 1`, desc, index)
+	c := &indexInfo{desc: desc, index: index, constraints: constraints}
+	if c.isPointLookup() {
+		t.Errorf("expected no point lookup on secondary index")
+	}
+}
+
 func TestApplyConstraints(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
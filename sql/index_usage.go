@@ -0,0 +1,155 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/stop"
+	"github.com/cockroachdb/cockroach/util/timeutil"
+)
+
+// indexUsageFlushInterval is the interval at which the index reads counted by
+// a node are added to system.index_usage.
+const indexUsageFlushInterval = time.Minute
+
+// indexUsageTableSchema describes the schema of the index usage table, which
+// holds the number of reads of each index of the user tables and the time of
+// the last one.
+const indexUsageTableSchema = `
+CREATE TABLE system.index_usage (
+  tableID   INT,
+  indexID   INT,
+  reads     INT        NOT NULL,
+  lastRead  TIMESTAMP  NOT NULL,
+  PRIMARY KEY (tableID, indexID)
+);`
+
+// AddIndexUsageToMetadataSchema adds the index usage table to the supplied
+// MetadataSchema.
+func AddIndexUsageToMetadataSchema(schema *sqlbase.MetadataSchema) {
+	schema.AddTable(keys.IndexUsageTableID, indexUsageTableSchema)
+}
+
+type indexUsageKey struct {
+	tableID sqlbase.ID
+	indexID sqlbase.IndexID
+}
+
+type indexUsage struct {
+	reads    int64
+	lastRead time.Time
+}
+
+// IndexUsageStats counts the reads of the indexes of user tables on a node.
+// The counts are kept in memory and periodically added to the ones persisted
+// in system.index_usage, which holds the totals of all the nodes.
+type IndexUsageStats struct {
+	mu    sync.Mutex
+	stats map[indexUsageKey]indexUsage
+}
+
+// NewIndexUsageStats creates an IndexUsageStats.
+func NewIndexUsageStats() *IndexUsageStats {
+	return &IndexUsageStats{stats: make(map[indexUsageKey]indexUsage)}
+}
+
+// recordRead counts a read of the given index. Reads of the system tables are
+// not counted. It is a no-op on a nil IndexUsageStats.
+func (s *IndexUsageStats) recordRead(tableID sqlbase.ID, indexID sqlbase.IndexID) {
+	if s == nil || tableID <= keys.MaxReservedDescID {
+		return
+	}
+	now := timeutil.Now()
+	key := indexUsageKey{tableID: tableID, indexID: indexID}
+	s.mu.Lock()
+	u := s.stats[key]
+	u.reads++
+	u.lastRead = now
+	s.stats[key] = u
+	s.mu.Unlock()
+}
+
+// merge adds the given counts to the ones which haven't been flushed yet.
+func (s *IndexUsageStats) merge(stats map[indexUsageKey]indexUsage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, u := range stats {
+		cur := s.stats[key]
+		cur.reads += u.reads
+		if u.lastRead.After(cur.lastRead) {
+			cur.lastRead = u.lastRead
+		}
+		s.stats[key] = cur
+	}
+}
+
+// flush adds the reads counted since the last flush to system.index_usage. If
+// this fails, the reads are kept for the next flush.
+func (s *IndexUsageStats) flush(db client.DB, leaseMgr *LeaseManager) error {
+	s.mu.Lock()
+	stats := s.stats
+	s.stats = make(map[indexUsageKey]indexUsage)
+	s.mu.Unlock()
+	if len(stats) == 0 {
+		return nil
+	}
+
+	const upsertIndexUsageStmt = `
+INSERT INTO system.index_usage (tableID, indexID, reads, lastRead)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (tableID, indexID) DO UPDATE SET
+  reads = index_usage.reads + excluded.reads,
+  lastRead = GREATEST(index_usage.lastRead, excluded.lastRead)
+`
+	ie := InternalExecutor{LeaseManager: leaseMgr}
+	if err := db.Txn(func(txn *client.Txn) error {
+		for key, u := range stats {
+			if _, err := ie.ExecuteStatementInTransaction(txn, upsertIndexUsageStmt,
+				int(key.tableID), int(key.indexID), u.reads, u.lastRead); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		s.merge(stats)
+		return err
+	}
+	return nil
+}
+
+// Start starts a worker which periodically flushes the index reads to
+// system.index_usage.
+func (s *IndexUsageStats) Start(db client.DB, leaseMgr *LeaseManager, stopper *stop.Stopper) {
+	stopper.RunWorker(func() {
+		ticker := time.NewTicker(indexUsageFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.flush(db, leaseMgr); err != nil {
+					log.Warningf("unable to flush index usage statistics: %s", err)
+				}
+			case <-stopper.ShouldStop():
+				return
+			}
+		}
+	})
+}
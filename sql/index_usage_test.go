@@ -0,0 +1,86 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestIndexUsageFlush tests that the index reads counted in memory are added
+// to the ones in system.index_usage.
+func TestIndexUsageFlush(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	leaseManager := s.LeaseManager().(*LeaseManager)
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (k INT PRIMARY KEY, v INT, INDEX t_v (v));
+`); err != nil {
+		t.Fatal(err)
+	}
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "d", "t")
+	primaryID := tableDesc.PrimaryIndex.ID
+	secondaryID := tableDesc.Indexes[0].ID
+
+	checkReads := func(indexID sqlbase.IndexID, expected int) {
+		var reads int
+		if err := db.QueryRow(
+			`SELECT reads FROM system.index_usage WHERE tableID = $1 AND indexID = $2`,
+			int(tableDesc.ID), int(indexID),
+		).Scan(&reads); err != nil {
+			t.Fatal(err)
+		}
+		if reads != expected {
+			t.Errorf("index %d: expected %d reads, but found %d", indexID, expected, reads)
+		}
+	}
+
+	stats := NewIndexUsageStats()
+	stats.recordRead(tableDesc.ID, primaryID)
+	stats.recordRead(tableDesc.ID, primaryID)
+	stats.recordRead(tableDesc.ID, secondaryID)
+	// Reads of the system tables are not counted.
+	stats.recordRead(keys.NamespaceTableID, 1)
+	if err := stats.flush(*kvDB, leaseManager); err != nil {
+		t.Fatal(err)
+	}
+	checkReads(primaryID, 2)
+	checkReads(secondaryID, 1)
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM system.index_usage`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 indexes with reads, but found %d", count)
+	}
+
+	// Flushing again adds the new reads to the persisted ones.
+	stats.recordRead(tableDesc.ID, secondaryID)
+	if err := stats.flush(*kvDB, leaseManager); err != nil {
+		t.Fatal(err)
+	}
+	checkReads(primaryID, 2)
+	checkReads(secondaryID, 2)
+}
@@ -32,6 +32,8 @@ type insertNode struct {
 	n            *parser.Insert
 	insertRows   parser.SelectStatement
 	checkHelper  checkHelper
+	computed     computedHelper
+	triggers     triggerHelper
 
 	insertCols            []sqlbase.ColumnDescriptor
 	insertColIDtoRowIndex map[sqlbase.ColumnID]int
@@ -67,12 +69,21 @@ func (p *planner) Insert(
 		if n.Returning != nil {
 			return nil, fmt.Errorf("RETURNING is not supported with UPSERT")
 		}
+		// The conflicting rows are fetched by the upserter, which doesn't
+		// enforce the policies.
+		if p.policiesApply(en.tableDesc) {
+			return nil, fmt.Errorf("UPSERT is not supported on tables with row-level security policies")
+		}
 	}
 
 	var cols []sqlbase.ColumnDescriptor
 	// Determine which columns we're inserting into.
 	if n.DefaultValues() {
-		cols = en.tableDesc.Columns
+		for _, col := range en.tableDesc.Columns {
+			if col.ComputedExpr == nil {
+				cols = append(cols, col)
+			}
+		}
 	} else {
 		var err error
 		if cols, err = p.processColumns(en.tableDesc, n.Columns); err != nil {
@@ -112,6 +123,14 @@ func (p *planner) Insert(
 			addIfDefault(*col)
 		}
 	}
+	// Add the computed columns, whose values are computed from the others to be
+	// stored or written to the secondary indexes.
+	for _, col := range en.tableDesc.Columns {
+		if col.ComputedExpr != nil {
+			colIDSet[col.ID] = struct{}{}
+			cols = append(cols, col)
+		}
+	}
 
 	defaultExprs, err := makeDefaultExprs(cols, &p.parser, &p.evalCtx)
 	if err != nil {
@@ -151,6 +170,20 @@ func (p *planner) Insert(
 		return nil, err
 	}
 
+	// The FKs checked by upserts are never deferred, as the checks of their
+	// updates can't be.
+	checker := ri.fks.checker
+	if n.OnConflict != nil {
+		checker = nil
+	}
+	autoCommit = p.deferFKChecks(checker, autoCommit)
+
+	var triggers triggerHelper
+	if err := triggers.init(p, en.tableDesc, parser.TriggerInsert); err != nil {
+		return nil, err
+	}
+	autoCommit = autoCommit && !triggers.firesAfter()
+
 	var tw tableWriter
 	if n.OnConflict == nil {
 		tw = &tableInserter{ri: ri, autoCommit: autoCommit}
@@ -196,11 +229,21 @@ func (p *planner) Insert(
 		insertCols:            ri.insertCols,
 		insertColIDtoRowIndex: ri.insertColIDtoRowIndex,
 		tw: tw,
+		triggers:              triggers,
 	}
 
 	if err := in.checkHelper.init(p, en.tableDesc); err != nil {
 		return nil, err
 	}
+	if err := in.computed.init(p, en.tableDesc, false); err != nil {
+		return nil, err
+	}
+	if len(in.computed.exprs) > 0 && n.OnConflict != nil && !n.OnConflict.DoNothing {
+		return nil, fmt.Errorf("UPSERT is not supported on tables with computed columns")
+	}
+	if in.triggers.active() && n.OnConflict != nil {
+		return nil, fmt.Errorf("UPSERT is not supported on tables with triggers")
+	}
 
 	if err := in.run.initEditNode(&in.editNodeBase, rows, n.Returning, desiredTypes); err != nil {
 		return nil, err
@@ -255,6 +298,9 @@ func (n *insertNode) Next() (bool, error) {
 			// We're done. Finish the batch.
 			err = n.tw.finalize()
 		}
+		if err == nil {
+			err = n.triggers.fireAfter()
+		}
 		return false, err
 	}
 
@@ -279,6 +325,21 @@ func (n *insertNode) Next() (bool, error) {
 		rowVals = append(rowVals, d)
 	}
 
+	// The triggers fired before the row is written can change its values,
+	// which are checked below.
+	set, err := n.triggers.fireBefore(nil, n.triggers.rowValues(n.insertColIDtoRowIndex, rowVals, nil))
+	if err != nil {
+		return false, err
+	}
+	if err := n.triggers.setRow(n.insertColIDtoRowIndex, rowVals, set); err != nil {
+		return false, err
+	}
+
+	n.computed.loadRow(n.insertColIDtoRowIndex, rowVals, false)
+	if err := n.computed.fill(&n.p.evalCtx, n.insertColIDtoRowIndex, rowVals); err != nil {
+		return false, err
+	}
+
 	// Check to see if NULL is being inserted into any non-nullable column.
 	for _, col := range n.tableDesc.Columns {
 		if !col.Nullable {
@@ -300,8 +361,10 @@ func (n *insertNode) Next() (bool, error) {
 		return false, err
 	}
 
-	_, err := n.tw.row(rowVals)
-	if err != nil {
+	if _, err := n.tw.row(rowVals); err != nil {
+		return false, err
+	}
+	if err := n.triggers.queueAfter(nil, n.triggers.rowValues(n.insertColIDtoRowIndex, rowVals, nil)); err != nil {
 		return false, err
 	}
 
@@ -325,9 +388,16 @@ func (p *planner) processColumns(tableDesc *sqlbase.TableDescriptor,
 	if node == nil {
 		// VisibleColumns is used here to prevent INSERT INTO <table> VALUES (...)
 		// (as opposed to INSERT INTO <table> (...) VALUES (...)) from writing
-		// hidden columns. At present, the only hidden column is the implicit rowid
-		// primary key column.
-		return tableDesc.VisibleColumns(), nil
+		// hidden columns, which are the implicit rowid primary key column and the
+		// columns of expression indexes. Computed columns are not written
+		// directly either.
+		var cols []sqlbase.ColumnDescriptor
+		for _, col := range tableDesc.VisibleColumns() {
+			if col.ComputedExpr == nil {
+				cols = append(cols, col)
+			}
+		}
+		return cols, nil
 	}
 
 	cols := make([]sqlbase.ColumnDescriptor, len(node))
@@ -345,6 +415,9 @@ func (p *planner) processColumns(tableDesc *sqlbase.TableDescriptor,
 		if _, ok := colIDSet[col.ID]; ok {
 			return nil, fmt.Errorf("multiple assignments to same column \"%s\"", n.Column())
 		}
+		if col.ComputedExpr != nil {
+			return nil, fmt.Errorf("cannot write directly to computed column %q", col.Name)
+		}
 		colIDSet[col.ID] = struct{}{}
 		cols[i] = col
 	}
@@ -357,6 +430,10 @@ func (p *planner) fillDefaults(defaultExprs []parser.TypedExpr,
 	if n.DefaultValues() {
 		row := make(parser.Exprs, 0, len(cols))
 		for i := range cols {
+			if cols[i].ComputedExpr != nil {
+				// Computed columns are filled in when the row is inserted.
+				continue
+			}
 			if defaultExprs == nil {
 				row = append(row, parser.DNull)
 				continue
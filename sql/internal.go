@@ -69,7 +69,7 @@ func (ie InternalExecutor) GetTableSpan(user string, txn *client.Txn, dbName, ta
 
 // getTableID retrieves the table ID for the specified table.
 func getTableID(p *planner, qname *parser.QualifiedName) (sqlbase.ID, error) {
-	if err := qname.NormalizeTableName(p.session.Database); err != nil {
+	if err := p.normalizeTableName(qname); err != nil {
 		return 0, err
 	}
 
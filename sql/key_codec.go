@@ -0,0 +1,145 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+var _ parser.KeyCodec = &planner{}
+
+// EncodeKey implements the parser.KeyCodec interface. A value must be given
+// for each column of the index. The returned key doesn't include the column
+// family of the rows, nor the primary key columns which are implicitly part
+// of the entries of secondary indexes.
+func (p *planner) EncodeKey(table, index, values string) (parser.Datum, error) {
+	tableDesc, err := p.getTableDescFromString(table)
+	if err != nil {
+		return nil, err
+	}
+	indexDesc, err := findPublicIndexByName(tableDesc, index)
+	if err != nil {
+		return nil, err
+	}
+	exprs, err := parser.ParseExprsTraditional([]string{values})
+	if err != nil {
+		return nil, err
+	}
+	if len(exprs) != len(indexDesc.ColumnIDs) {
+		return nil, fmt.Errorf("index %q has %d columns, but %d values were given",
+			indexDesc.Name, len(indexDesc.ColumnIDs), len(exprs))
+	}
+
+	colMap := make(map[sqlbase.ColumnID]int, len(exprs))
+	datums := make([]parser.Datum, len(exprs))
+	for i, expr := range exprs {
+		col, err := tableDesc.FindActiveColumnByID(indexDesc.ColumnIDs[i])
+		if err != nil {
+			return nil, err
+		}
+		typedExpr, err := parser.TypeCheck(expr, nil, col.Type.ToDatumType())
+		if err != nil {
+			return nil, err
+		}
+		if datums[i], err = typedExpr.Eval(&p.evalCtx); err != nil {
+			return nil, err
+		}
+		if err := sqlbase.CheckColumnType(*col, datums[i], nil); err != nil {
+			return nil, err
+		}
+		colMap[col.ID] = i
+	}
+	key, _, err := sqlbase.EncodeIndexKey(tableDesc, indexDesc, colMap, datums,
+		sqlbase.MakeIndexKeyPrefix(tableDesc, indexDesc.ID))
+	if err != nil {
+		return nil, err
+	}
+	return parser.NewDBytes(parser.DBytes(key)), nil
+}
+
+// DecodeKey implements the parser.KeyCodec interface. The result has the form
+// "index: col1=val1, col2=val2", with the values of the columns of the index
+// to which the key belongs.
+func (p *planner) DecodeKey(table string, key []byte) (parser.Datum, error) {
+	tableDesc, err := p.getTableDescFromString(table)
+	if err != nil {
+		return nil, err
+	}
+	var alloc sqlbase.DatumAlloc
+	indexID, _, err := sqlbase.DecodeIndexKeyPrefix(&alloc, tableDesc, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "key %s does not belong to table %s", roachpb.Key(key), table)
+	}
+	indexDesc, err := tableDesc.FindIndexByID(indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The primary key columns which are implicitly part of the entries of
+	// secondary indexes are not decoded, so that the keys returned by
+	// EncodeKey can be decoded.
+	colIDs, dirs := indexDesc.FullColumnIDs()
+	colIDs, dirs = colIDs[:len(indexDesc.ColumnIDs)], dirs[:len(indexDesc.ColumnIDs)]
+	valTypes, err := sqlbase.MakeKeyVals(tableDesc, colIDs)
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]parser.Datum, len(colIDs))
+	_, ok, err := sqlbase.DecodeIndexKey(&alloc, tableDesc, indexID, valTypes, vals, dirs, key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("key %s does not belong to table %s", roachpb.Key(key), table)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(indexDesc.Name)
+	buf.WriteByte(':')
+	for i, id := range colIDs {
+		col, err := tableDesc.FindActiveColumnByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, " %s=%s", col.Name, vals[i])
+	}
+	return parser.NewDString(buf.String()), nil
+}
+
+// findPublicIndexByName returns the primary index or the secondary index of
+// a table with the given name.
+func findPublicIndexByName(
+	tableDesc *sqlbase.TableDescriptor, name string,
+) (*sqlbase.IndexDescriptor, error) {
+	normName := sqlbase.NormalizeName(name)
+	if sqlbase.NormalizeName(tableDesc.PrimaryIndex.Name) == normName {
+		return &tableDesc.PrimaryIndex, nil
+	}
+	for i := range tableDesc.Indexes {
+		if sqlbase.NormalizeName(tableDesc.Indexes[i].Name) == normName {
+			return &tableDesc.Indexes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("index %q does not exist", name)
+}
@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/gossip"
 	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
 	"github.com/cockroachdb/cockroach/security"
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
@@ -112,6 +115,9 @@ type LeaseStore struct {
 	db     client.DB
 	clock  *hlc.Clock
 	nodeID uint32
+	// gossip, if set, is used to ask the nodes holding leases on the previous
+	// version of a table descriptor to release them.
+	gossip *gossip.Gossip
 
 	testingKnobs LeaseStoreTestingKnobs
 }
@@ -240,6 +246,7 @@ func (s LeaseStore) waitForOneVersion(tableID sqlbase.ID, retryOpts retry.Option
 	desc := &sqlbase.Descriptor{}
 	descKey := sqlbase.MakeDescMetadataKey(tableID)
 	var tableDesc *sqlbase.TableDescriptor
+	var invalidated sqlbase.DescriptorVersion
 	for r := retry.Start(retryOpts); r.Next(); {
 		// Get the current version of the table descriptor non-transactionally.
 		//
@@ -263,10 +270,32 @@ func (s LeaseStore) waitForOneVersion(tableID sqlbase.ID, retryOpts retry.Option
 		}
 		log.Infof("publish (count leases): descID=%d name=%s version=%d count=%d",
 			tableDesc.ID, tableDesc.Name, tableDesc.Version-1, count)
+		// Instead of waiting for the system config to be gossiped to the nodes
+		// holding the leases, or for the leases to expire, ask the nodes to
+		// release them as soon as they are not used any more.
+		if invalidated != tableDesc.Version {
+			s.invalidateLeases(tableDesc)
+			invalidated = tableDesc.Version
+		}
 	}
 	return tableDesc.Version, nil
 }
 
+// invalidateLeases gossips that the leases on the versions of a table
+// descriptor older than the given one must be released.
+func (s LeaseStore) invalidateLeases(tableDesc *sqlbase.TableDescriptor) {
+	if s.gossip == nil {
+		return
+	}
+	if err := s.gossip.AddInfo(
+		gossip.MakeTableLeaseInvalidationKey(uint32(tableDesc.ID)),
+		[]byte(strconv.Itoa(int(tableDesc.Version))), LeaseDuration,
+	); err != nil {
+		log.Warningf("error invalidating the leases of table %d(%s): %s",
+			tableDesc.ID, tableDesc.Name, err)
+	}
+}
+
 var errDidntUpdateDescriptor = errors.New("didn't update the table descriptor")
 
 // Publish updates a table descriptor. It also maintains the invariant that
@@ -836,6 +865,15 @@ type tableNameCacheKey struct {
 type tableNameCache struct {
 	mu     sync.Mutex
 	tables map[tableNameCacheKey]*LeaseState
+
+	// ids caches the resolution of table names to IDs, and names is its
+	// inverse. Unlike the leases above, an entry outlives the leases it was
+	// learned from, so that acquiring a new lease for a name doesn't need to
+	// read the namespace table. Entries are only hints: the name in a lease
+	// acquired through them is checked. They are removed when a descriptor with
+	// a different name or a deleted descriptor is seen.
+	ids   map[tableNameCacheKey]sqlbase.ID
+	names map[sqlbase.ID]tableNameCacheKey
 }
 
 // Resolves a (database ID, table name) to the table descriptor's ID. Returns
@@ -882,6 +920,7 @@ func (c *tableNameCache) insert(lease *LeaseState) {
 	defer c.mu.Unlock()
 
 	key := c.makeCacheKey(lease.ParentID, lease.Name)
+	c.setIDLocked(key, lease.ID)
 	existing, ok := c.tables[key]
 	if !ok {
 		c.tables[key] = lease
@@ -917,6 +956,52 @@ func (c *tableNameCache) remove(lease *LeaseState) {
 	}
 }
 
+// getID returns the ID a table name was last resolved to, if any. Unlike get,
+// the result is not backed by a lease and may be stale.
+func (c *tableNameCache) getID(dbID sqlbase.ID, tableName string) (sqlbase.ID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.ids[c.makeCacheKey(dbID, tableName)]
+	return id, ok
+}
+
+// removeID removes the resolution of a name to the given table ID, if any.
+func (c *tableNameCache) removeID(id sqlbase.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeIDLocked(id)
+}
+
+// updateID removes the resolution of a name to the ID of the given table
+// descriptor if the descriptor has a different name or is being deleted.
+func (c *tableNameCache) updateID(table *sqlbase.TableDescriptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, ok := c.names[table.ID]
+	if !ok {
+		return
+	}
+	if table.Deleted() || key != c.makeCacheKey(table.ParentID, table.Name) {
+		c.removeIDLocked(table.ID)
+	}
+}
+
+func (c *tableNameCache) setIDLocked(key tableNameCacheKey, id sqlbase.ID) {
+	if oldID, ok := c.ids[key]; ok {
+		c.removeIDLocked(oldID)
+	}
+	c.removeIDLocked(id)
+	c.ids[key] = id
+	c.names[id] = key
+}
+
+func (c *tableNameCache) removeIDLocked(id sqlbase.ID) {
+	if key, ok := c.names[id]; ok {
+		delete(c.ids, key)
+		delete(c.names, id)
+	}
+}
+
 func (c *tableNameCache) makeCacheKey(dbID sqlbase.ID, tableName string) tableNameCacheKey {
 	return tableNameCacheKey{dbID, sqlbase.NormalizeName(tableName)}
 }
@@ -963,6 +1048,8 @@ func NewLeaseManager(
 		testingKnobs: testingKnobs,
 		tableNames: tableNameCache{
 			tables: make(map[tableNameCacheKey]*LeaseState),
+			ids:    make(map[tableNameCacheKey]sqlbase.ID),
+			names:  make(map[sqlbase.ID]tableNameCacheKey),
 		},
 		stopper: stopper,
 	}
@@ -988,8 +1075,25 @@ func (m *LeaseManager) AcquireByName(
 
 	// We failed to find something in the cache, or what we found is not
 	// guaranteed to be valid by the time we use it because we don't have a
-	// lease with at least a bit of lifetime left in it. So, we do it the hard
-	// way: look in the database to resolve the name, then acquire a new lease.
+	// lease with at least a bit of lifetime left in it. If the name was
+	// resolved before, acquire a new lease for the same table and check that
+	// it still has that name.
+	if tableID, ok := m.tableNames.getID(dbID, tableName); ok {
+		lease, err := m.Acquire(txn, tableID, 0)
+		if err == nil {
+			if nameMatchesLease(lease, dbID, tableName) {
+				return lease, nil
+			}
+			if err := m.Release(lease); err != nil {
+				log.Warningf("error releasing lease: %s", err)
+			}
+		}
+		// The table was renamed or dropped since the name was resolved.
+		m.tableNames.removeID(tableID)
+	}
+
+	// Otherwise, we do it the hard way: look in the database to resolve the
+	// name, then acquire a new lease.
 	var err error
 	tableID, err := m.resolveName(txn, dbID, tableName)
 	if err != nil {
@@ -1132,16 +1236,63 @@ func (m *LeaseManager) findTableState(tableID sqlbase.ID, create bool) *tableSta
 	return t
 }
 
+// tableLeaseInvalidation is a request to release the leases on the versions
+// of a table descriptor older than version.
+type tableLeaseInvalidation struct {
+	tableID sqlbase.ID
+	version sqlbase.DescriptorVersion
+}
+
 // RefreshLeases starts a goroutine that refreshes the lease manager
 // leases for tables received in the latest system configuration via gossip.
-func (m *LeaseManager) RefreshLeases(s *stop.Stopper, db *client.DB, gossip *gossip.Gossip) {
+// The leases on the previous versions of a table descriptor are also
+// released as soon as the node publishing a new version asks for it (see
+// LeaseStore.invalidateLeases).
+func (m *LeaseManager) RefreshLeases(s *stop.Stopper, db *client.DB, g *gossip.Gossip) {
+	m.LeaseStore.gossip = g
+	// The callback must not block gossip. The invalidations which are dropped
+	// are still handled once the system config is gossiped.
+	invalidationC := make(chan tableLeaseInvalidation, 64)
+	keyPrefix := gossip.MakeKey(gossip.KeyTableLeaseInvalidationPrefix, "")
+	g.RegisterCallback(gossip.MakePrefixPattern(gossip.KeyTableLeaseInvalidationPrefix),
+		func(key string, content roachpb.Value) {
+			tableID, err := strconv.ParseUint(strings.TrimPrefix(key, keyPrefix), 10, 32)
+			if err != nil {
+				log.Warningf("%s: invalid table ID: %s", key, err)
+				return
+			}
+			b, err := content.GetBytes()
+			if err != nil {
+				log.Warningf("%s: %s", key, err)
+				return
+			}
+			version, err := strconv.ParseUint(string(b), 10, 32)
+			if err != nil {
+				log.Warningf("%s: invalid version %q: %s", key, b, err)
+				return
+			}
+			select {
+			case invalidationC <- tableLeaseInvalidation{
+				tableID: sqlbase.ID(tableID), version: sqlbase.DescriptorVersion(version),
+			}:
+			default:
+			}
+		})
+
 	s.RunWorker(func() {
 		descKeyPrefix := keys.MakeTablePrefix(uint32(sqlbase.DescriptorTable.ID))
-		gossipUpdateC := gossip.RegisterSystemConfigChannel()
+		gossipUpdateC := g.RegisterSystemConfigChannel()
 		for {
 			select {
+			case inv := <-invalidationC:
+				if t := m.findTableState(inv.tableID, false /* create */); t != nil {
+					if err := t.purgeOldLeases(db, false, inv.version, m.LeaseStore); err != nil {
+						log.Warningf("error purging leases for table %d: %s", inv.tableID, err)
+					}
+				}
+
 			case <-gossipUpdateC:
-				cfg, _ := gossip.GetSystemConfig()
+				cfg, _ := g.GetSystemConfig()
 				if m.testingKnobs.GossipUpdateEvent != nil {
 					m.testingKnobs.GossipUpdateEvent(cfg)
 				}
@@ -1173,6 +1324,9 @@ func (m *LeaseManager) RefreshLeases(s *stop.Stopper, db *client.DB, gossip *gos
 							log.Infof("%s: refreshing lease table: %d (%s), version: %d, deleted: %t",
 								kv.Key, table.ID, table.Name, table.Version, table.Deleted())
 						}
+						// Forget the resolution of the table's previous name, if it
+						// changed.
+						m.tableNames.updateID(table)
 						// Try to refresh the table lease to one >= this version.
 						if t := m.findTableState(table.ID, false /* create */); t != nil {
 							if err := t.purgeOldLeases(
@@ -303,6 +303,65 @@ CREATE TABLE t.test (k CHAR PRIMARY KEY, v CHAR);
 	}
 }
 
+// Test that a name keeps resolving to its table ID after the leases it was
+// resolved with have expired, and stops when the table is renamed.
+func TestNameCacheResolvesIDAfterLeaseExpiration(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	leaseManager := s.LeaseManager().(*LeaseManager)
+
+	if _, err := db.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.test (k CHAR PRIMARY KEY, v CHAR);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Populate the name cache.
+	if _, err := db.Exec("SELECT * FROM t.test;"); err != nil {
+		t.Fatal(err)
+	}
+
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "t", "test")
+
+	// Advance the clock to expire the lease.
+	s.Clock().SetMaxOffset(10 * LeaseDuration)
+	s.Clock().Update(s.Clock().Now().Add(int64(2*LeaseDuration), 0))
+
+	if leaseManager.tableNames.get(tableDesc.ParentID, "test", s.Clock()) != nil {
+		t.Fatalf("name resolves to an expired lease")
+	}
+	if id, ok := leaseManager.tableNames.getID(tableDesc.ParentID, "test"); !ok || id != tableDesc.ID {
+		t.Fatalf("expected name to resolve to %d, but found %d (%t)", tableDesc.ID, id, ok)
+	}
+
+	var lease *LeaseState
+	if err := kvDB.Txn(func(txn *client.Txn) error {
+		var err error
+		lease, err = leaseManager.AcquireByName(txn, tableDesc.ParentID, "test")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if lease.ID != tableDesc.ID {
+		t.Fatalf("name has wrong ID: %d (expected: %d)", lease.ID, tableDesc.ID)
+	}
+	if err := leaseManager.Release(lease); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec("ALTER TABLE t.test RENAME TO t.test2;"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := leaseManager.tableNames.getID(tableDesc.ParentID, "test"); ok {
+		t.Fatalf("old name still resolves")
+	}
+	if id, ok := leaseManager.tableNames.getID(tableDesc.ParentID, "test2"); !ok || id != tableDesc.ID {
+		t.Fatalf("expected new name to resolve to %d, but found %d (%t)", tableDesc.ID, id, ok)
+	}
+}
+
 // Test that table names are not treated as case sensitive by the name cache.
 func TestTableNameNotCaseSensitive(t *testing.T) {
 	defer leaktest.AfterTest(t)()
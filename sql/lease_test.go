@@ -28,6 +28,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/base"
 	"github.com/cockroachdb/cockroach/config"
+	"github.com/cockroachdb/cockroach/gossip"
 	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/keys"
 	"github.com/cockroachdb/cockroach/server"
@@ -35,9 +36,11 @@ import (
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/testutils"
 	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util"
 	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 	"github.com/cockroachdb/cockroach/util/timeutil"
+	"github.com/pkg/errors"
 )
 
 type leaseTest struct {
@@ -579,6 +582,69 @@ CREATE TABLE test.t(a INT PRIMARY KEY);
 	}
 }
 
+// Test that the unused leases on the previous versions of a table are
+// released as soon as the invalidation of the leases is gossiped, without
+// waiting for the new system config.
+func TestLeaseInvalidation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params, _ := createTestServerParams()
+	s, db, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop()
+
+	if _, err := db.Exec(`
+CREATE DATABASE test;
+CREATE TABLE test.t(a INT PRIMARY KEY);
+`); err != nil {
+		t.Fatal(err)
+	}
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "test", "t")
+
+	// The unused lease is kept, since it is on the newest version.
+	lease, err := acquire(s.(*server.TestServer), tableDesc.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LeaseManager().(*csql.LeaseManager).Release(lease); err != nil {
+		t.Fatal(err)
+	}
+	countLeases := func(version sqlbase.DescriptorVersion) int {
+		var count int
+		if err := db.QueryRow(
+			`SELECT COUNT(*) FROM system.lease WHERE descID = $1 AND version = $2`,
+			int(tableDesc.ID), int(version),
+		).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+	if count := countLeases(tableDesc.Version); count != 1 {
+		t.Fatalf("expected 1 lease on version %d, but found %d", tableDesc.Version, count)
+	}
+
+	// Publish a new version without triggering the gossip of the system
+	// config, and invalidate the leases on the previous one.
+	oldVersion := tableDesc.Version
+	tableDesc.Version++
+	if err := kvDB.Put(
+		sqlbase.MakeDescMetadataKey(tableDesc.ID), sqlbase.WrapDescriptor(tableDesc),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Gossip().AddInfo(
+		gossip.MakeTableLeaseInvalidationKey(uint32(tableDesc.ID)),
+		[]byte(fmt.Sprint(tableDesc.Version)), 0,
+	); err != nil {
+		t.Fatal(err)
+	}
+	util.SucceedsSoon(t, func() error {
+		if count := countLeases(oldVersion); count != 0 {
+			return errors.Errorf("%d leases on version %d", count, oldVersion)
+		}
+		return nil
+	})
+}
+
 // TestTxnObeysLeaseExpiration tests that a transaction is aborted when it tries
 // to use a table descriptor with an expired lease.
 func TestTxnObeysLeaseExpiration(t *testing.T) {
@@ -763,6 +763,18 @@ func (t *logicTest) processTestFile(path string) error {
 
 			execKnobs.FixTxnPriority = true
 			defer func() { execKnobs.FixTxnPriority = false }()
+
+		case "distsql":
+			// distsql causes the scans of the following statements to be run
+			// by distSQL flows where possible, to test both execution paths.
+			// The change stays in effect for the duration of that particular
+			// test file.
+			if len(fields) != 1 {
+				return fmt.Errorf("distsql takes no arguments, found: %v", fields[1:])
+			}
+
+			execKnobs.UseDistSQL = true
+			defer func() { execKnobs.UseDistSQL = false }()
 		default:
 			return fmt.Errorf("%s:%d: unknown command: %s", path, s.line, cmd)
 		}
@@ -161,3 +161,60 @@ func TestAbortCountErrorDuringTransaction(t *testing.T) {
 	checkCounterEQ(t, s, "txn.begin.count", 1)
 	checkCounterEQ(t, s, "select.count", 1)
 }
+
+// TestDatabaseQueryCounts tests that statement counts are attributed to the
+// database and the table they were executed against.
+func TestDatabaseQueryCounts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	params, _ := createTestServerParams()
+	s, sqlDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop()
+
+	for _, q := range []string{
+		"CREATE DATABASE a",
+		"CREATE DATABASE b",
+		"CREATE TABLE a.t (k INT PRIMARY KEY)",
+		"CREATE TABLE b.t (k INT PRIMARY KEY)",
+		"INSERT INTO a.t VALUES (1), (2)",
+		"INSERT INTO b.t VALUES (1)",
+		"UPDATE b.t SET k = k + 1",
+		"DELETE FROM a.t WHERE k = 1",
+	} {
+		if _, err := sqlDB.Exec(q); err != nil {
+			t.Fatalf("unexpected error executing '%s': %s'", q, err)
+		}
+	}
+
+	// Statements without a target table are attributed to the session's
+	// database. Use a transaction to pin the session.
+	txn, err := sqlDB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := txn.Exec("SET DATABASE = a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := txn.Exec("SELECT * FROM t"); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	checkCounterEQ(t, s, "db.a.insert.count", 1)
+	checkCounterEQ(t, s, "db.a.update.count", 0)
+	checkCounterEQ(t, s, "db.a.delete.count", 1)
+	checkCounterEQ(t, s, "db.a.select.count", 1)
+	checkCounterEQ(t, s, "db.b.insert.count", 1)
+	checkCounterEQ(t, s, "db.b.update.count", 1)
+	checkCounterEQ(t, s, "db.b.delete.count", 0)
+	checkCounterEQ(t, s, "db.b.select.count", 0)
+
+	// The statements against a single table are also broken down by table.
+	checkCounterEQ(t, s, "db.a.table.t.insert.count", 1)
+	checkCounterEQ(t, s, "db.a.table.t.delete.count", 1)
+	checkCounterEQ(t, s, "db.a.table.t.select.count", 1)
+	checkCounterEQ(t, s, "db.b.table.t.insert.count", 1)
+	checkCounterEQ(t, s, "db.b.table.t.update.count", 1)
+	checkCounterEQ(t, s, "db.b.table.t.select.count", 0)
+}
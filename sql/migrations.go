@@ -0,0 +1,144 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/pkg/errors"
+)
+
+// A migration brings the system schema of a cluster bootstrapped by an
+// earlier version up to date with the bootstrap schema of this version.
+// Migrations are run by every node when it starts, so they must be
+// idempotent and must tolerate running concurrently on several nodes.
+type migration struct {
+	name string
+	fn   func(*LeaseManager) error
+}
+
+// migrations are run in order.
+var migrations = []migration{
+	{
+		name: "create system.settings",
+		fn: func(leaseMgr *LeaseManager) error {
+			return createSystemTable(leaseMgr.db, keys.SettingsTableID, settingsTableSchema)
+		},
+	},
+	{
+		name: "add system.users.createDB",
+		fn:   addUsersCreateDBColumn,
+	},
+	{
+		name: "create system.comments",
+		fn: func(leaseMgr *LeaseManager) error {
+			return createSystemTable(leaseMgr.db, keys.CommentsTableID, sqlbase.CommentsTableSchema)
+		},
+	},
+	{
+		name: "create system.index_usage",
+		fn: func(leaseMgr *LeaseManager) error {
+			return createSystemTable(leaseMgr.db, keys.IndexUsageTableID, indexUsageTableSchema)
+		},
+	},
+	{
+		name: "create system.statement_statistics",
+		fn: func(leaseMgr *LeaseManager) error {
+			return createSystemTable(leaseMgr.db, keys.StatementStatsTableID, statementStatsTableSchema)
+		},
+	},
+	{
+		name: "create system.transaction_statistics",
+		fn: func(leaseMgr *LeaseManager) error {
+			return createSystemTable(leaseMgr.db, keys.TransactionStatsTableID, transactionStatsTableSchema)
+		},
+	},
+	{
+		name: "create system.table_statistics",
+		fn: func(leaseMgr *LeaseManager) error {
+			return createSystemTable(leaseMgr.db, keys.TableStatisticsTableID, tableStatisticsTableSchema)
+		},
+	},
+}
+
+// RunMigrations runs the migrations of the system schema. It must be called
+// once the node can serve KV requests, and before the SQL statements relying
+// on the migrated schema are executed.
+func RunMigrations(leaseMgr *LeaseManager) error {
+	for _, m := range migrations {
+		if log.V(1) {
+			log.Infof("running migration %q", m.name)
+		}
+		if err := m.fn(leaseMgr); err != nil {
+			return errors.Wrapf(err, "migration %q failed", m.name)
+		}
+	}
+	return nil
+}
+
+// createSystemTable creates a system table added with AddTable, unless it
+// already exists.
+func createSystemTable(db client.DB, id sqlbase.ID, schema string) error {
+	desc := sqlbase.CreateSystemTableDescriptor(id, schema)
+	return db.Txn(func(txn *client.Txn) error {
+		nameKey := sqlbase.MakeNameMetadataKey(keys.SystemDatabaseID, desc.Name)
+		gr, err := txn.Get(nameKey)
+		if err != nil {
+			return err
+		}
+		if gr.Exists() {
+			return nil
+		}
+		txn.SetSystemConfigTrigger()
+		b := txn.NewBatch()
+		b.CPut(nameKey, desc.ID, nil)
+		b.CPut(sqlbase.MakeDescMetadataKey(desc.ID), sqlbase.WrapDescriptor(&desc), nil)
+		return txn.CommitInBatch(b)
+	})
+}
+
+// addUsersCreateDBColumn adds the createDB column to system.users. The users
+// created before have a NULL createDB, which doesn't let them create
+// databases.
+func addUsersCreateDBColumn(leaseMgr *LeaseManager) error {
+	hasCreateDB := func(desc *sqlbase.TableDescriptor) bool {
+		_, _, err := desc.FindColumnByName("createDB")
+		return err == nil
+	}
+	desc := &sqlbase.Descriptor{}
+	if err := leaseMgr.db.GetProto(sqlbase.MakeDescMetadataKey(keys.UsersTableID), desc); err != nil {
+		return err
+	}
+	if hasCreateDB(desc.GetTable()) {
+		return nil
+	}
+	_, err := leaseMgr.Publish(keys.UsersTableID, func(desc *sqlbase.TableDescriptor) error {
+		if hasCreateDB(desc) {
+			// Another node added the column since.
+			return errDidntUpdateDescriptor
+		}
+		desc.AddColumn(sqlbase.ColumnDescriptor{
+			Name:     "createDB",
+			Type:     sqlbase.ColumnType{Kind: sqlbase.ColumnType_BOOL},
+			Nullable: true,
+		})
+		// The column is put in a family of its own, like in the bootstrap
+		// schema, so the existing rows are still valid.
+		return desc.AllocateIDs()
+	}, nil)
+	return err
+}
@@ -0,0 +1,125 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestMigrationsCreateSystemTables tests that the migrations create the
+// system tables missing from a cluster bootstrapped without them.
+func TestMigrationsCreateSystemTables(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	leaseManager := s.LeaseManager().(*LeaseManager)
+
+	tables := []struct {
+		name string
+		id   sqlbase.ID
+	}{
+		{"settings", keys.SettingsTableID},
+		{"comments", keys.CommentsTableID},
+		{"index_usage", keys.IndexUsageTableID},
+		{"statement_statistics", keys.StatementStatsTableID},
+		{"transaction_statistics", keys.TransactionStatsTableID},
+		{"table_statistics", keys.TableStatisticsTableID},
+	}
+
+	if err := kvDB.Txn(func(txn *client.Txn) error {
+		txn.SetSystemConfigTrigger()
+		b := txn.NewBatch()
+		for _, table := range tables {
+			b.Del(sqlbase.MakeNameMetadataKey(keys.SystemDatabaseID, table.name))
+			b.Del(sqlbase.MakeDescMetadataKey(table.id))
+		}
+		return txn.CommitInBatch(b)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The migrations are idempotent.
+	for i := 0; i < 2; i++ {
+		if err := RunMigrations(leaseManager); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, table := range tables {
+		gr, err := kvDB.Get(sqlbase.MakeNameMetadataKey(keys.SystemDatabaseID, table.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := sqlbase.ID(gr.ValueInt()); id != table.id {
+			t.Fatalf("expected system.%s to have ID %d, but found %d", table.name, table.id, id)
+		}
+		desc := &sqlbase.Descriptor{}
+		if err := kvDB.GetProto(sqlbase.MakeDescMetadataKey(table.id), desc); err != nil {
+			t.Fatal(err)
+		}
+		if tableDesc := desc.GetTable(); tableDesc == nil || tableDesc.Name != table.name {
+			t.Fatalf("expected the descriptor of system.%s, but found %v", table.name, desc)
+		}
+	}
+}
+
+// TestMigrationsAddUsersCreateDB tests that the migrations add the createDB
+// column to system.users on a cluster bootstrapped without it.
+func TestMigrationsAddUsersCreateDB(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	leaseManager := s.LeaseManager().(*LeaseManager)
+
+	if _, err := db.Exec(`INSERT INTO system.users (username) VALUES ('old')`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Remove the column from the descriptor, leaving the stored values.
+	if _, err := leaseManager.Publish(keys.UsersTableID, func(desc *sqlbase.TableDescriptor) error {
+		_, i, err := desc.FindColumnByName("createDB")
+		if err != nil {
+			return err
+		}
+		desc.RemoveColumnFromFamily(desc.Columns[i].ID)
+		desc.Columns = append(desc.Columns[:i], desc.Columns[i+1:]...)
+		return nil
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunMigrations(leaseManager); err != nil {
+		t.Fatal(err)
+	}
+
+	var createDB *bool
+	if err := db.QueryRow(
+		`SELECT createDB FROM system.users WHERE username = 'old'`,
+	).Scan(&createDB); err != nil {
+		t.Fatal(err)
+	}
+	if createDB != nil {
+		t.Fatalf("expected a NULL createDB, but found %t", *createDB)
+	}
+}
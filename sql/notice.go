@@ -0,0 +1,91 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NoticeSeverity is the severity of a Notice. The values are ordered by
+// increasing severity, as the levels of the postgres client_min_messages
+// setting.
+type NoticeSeverity int
+
+const (
+	// NoticeSeverityDebug is the severity of developer information.
+	NoticeSeverityDebug NoticeSeverity = iota
+	// NoticeSeverityLog is the severity of administrator information.
+	NoticeSeverityLog
+	// NoticeSeverityNotice is the severity of information that may be helpful
+	// to the user, e.g. that an IF EXISTS statement did nothing.
+	NoticeSeverityNotice
+	// NoticeSeverityWarning is the severity of warnings of likely problems,
+	// e.g. the use of deprecated syntax.
+	NoticeSeverityWarning
+	// NoticeSeverityError is higher than the severity of any notice; setting
+	// it as the minimum severity silences all notices.
+	NoticeSeverityError
+)
+
+var noticeSeverityNames = [...]string{
+	NoticeSeverityDebug:   "DEBUG",
+	NoticeSeverityLog:     "LOG",
+	NoticeSeverityNotice:  "NOTICE",
+	NoticeSeverityWarning: "WARNING",
+	NoticeSeverityError:   "ERROR",
+}
+
+func (s NoticeSeverity) String() string {
+	if s < 0 || int(s) >= len(noticeSeverityNames) {
+		return fmt.Sprintf("NoticeSeverity(%d)", s)
+	}
+	return noticeSeverityNames[s]
+}
+
+// parseNoticeSeverity parses a client_min_messages level. As in postgres, the
+// debug1 to debug5 levels are accepted as synonyms of debug.
+func parseNoticeSeverity(s string) (NoticeSeverity, bool) {
+	s = strings.ToUpper(s)
+	switch s {
+	case "DEBUG1", "DEBUG2", "DEBUG3", "DEBUG4", "DEBUG5":
+		return NoticeSeverityDebug, true
+	}
+	for i, name := range noticeSeverityNames {
+		if s == name {
+			return NoticeSeverity(i), true
+		}
+	}
+	return 0, false
+}
+
+// Notice is a message sent to the client along with the result of a
+// statement, without affecting the result itself.
+type Notice struct {
+	Severity NoticeSeverity
+	Message  string
+}
+
+// notice records a notice for the statement being executed, unless its
+// severity is below the session's minimum.
+func (p *planner) notice(severity NoticeSeverity, format string, args ...interface{}) {
+	if severity < p.session.ClientMinMessages {
+		return
+	}
+	p.notices = append(p.notices, Notice{
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
@@ -60,12 +60,16 @@ type AlterTableCmd interface {
 	alterTableCmd()
 }
 
-func (*AlterTableAddColumn) alterTableCmd()      {}
-func (*AlterTableAddConstraint) alterTableCmd()  {}
-func (*AlterTableDropColumn) alterTableCmd()     {}
-func (*AlterTableDropConstraint) alterTableCmd() {}
-func (*AlterTableSetDefault) alterTableCmd()     {}
-func (*AlterTableDropNotNull) alterTableCmd()    {}
+func (*AlterTableAddColumn) alterTableCmd()          {}
+func (*AlterTableAddConstraint) alterTableCmd()      {}
+func (*AlterTableDropColumn) alterTableCmd()         {}
+func (*AlterTableDropConstraint) alterTableCmd()     {}
+func (*AlterTableValidateConstraint) alterTableCmd() {}
+func (*AlterTableSetDefault) alterTableCmd()         {}
+func (*AlterTableDropNotNull) alterTableCmd()        {}
+func (*AlterTableDropInterleave) alterTableCmd()     {}
+func (*AlterTableAlterColumnType) alterTableCmd()    {}
+func (*AlterTableAlterPrimaryKey) alterTableCmd()    {}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -146,6 +150,17 @@ func (node *AlterTableDropConstraint) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// AlterTableValidateConstraint represents a VALIDATE CONSTRAINT command.
+type AlterTableValidateConstraint struct {
+	Constraint Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableValidateConstraint) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("VALIDATE CONSTRAINT ")
+	FormatNode(buf, f, node.Constraint)
+}
+
 // AlterTableSetDefault represents an ALTER COLUMN SET DEFAULT
 // or DROP DEFAULT command.
 type AlterTableSetDefault struct {
@@ -195,3 +210,51 @@ func (node *AlterTableDropNotNull) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(node.Column)
 	buf.WriteString(" DROP NOT NULL")
 }
+
+// AlterTableAlterColumnType represents an ALTER COLUMN TYPE command.
+type AlterTableAlterColumnType struct {
+	columnKeyword bool
+	Column        string
+	ToType        ColumnType
+}
+
+// GetColumn implements the ColumnMutationCmd interface.
+func (node *AlterTableAlterColumnType) GetColumn() string {
+	return node.Column
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableAlterColumnType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("ALTER ")
+	if node.columnKeyword {
+		buf.WriteString("COLUMN ")
+	}
+	buf.WriteString(node.Column)
+	buf.WriteString(" TYPE ")
+	FormatNode(buf, f, node.ToType)
+}
+
+// AlterTableDropInterleave represents an ALTER INDEX DROP INTERLEAVE
+// command.
+type AlterTableDropInterleave struct {
+	Index Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableDropInterleave) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("ALTER INDEX ")
+	FormatNode(buf, f, node.Index)
+	buf.WriteString(" DROP INTERLEAVE")
+}
+
+// AlterTableAlterPrimaryKey represents an ALTER PRIMARY KEY command.
+type AlterTableAlterPrimaryKey struct {
+	Columns IndexElemList
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableAlterPrimaryKey) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("ALTER PRIMARY KEY USING COLUMNS (")
+	FormatNode(buf, f, node.Columns)
+	buf.WriteByte(')')
+}
@@ -24,6 +24,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math"
 	"math/rand"
 	"regexp"
@@ -38,8 +39,10 @@ import (
 	"gopkg.in/inf.v0"
 
 	"github.com/cockroachdb/cockroach/build"
+	"github.com/cockroachdb/cockroach/keys"
 	"github.com/cockroachdb/cockroach/roachpb"
 	"github.com/cockroachdb/cockroach/util/decimal"
+	"github.com/cockroachdb/cockroach/util/duration"
 	"github.com/cockroachdb/cockroach/util/encoding"
 	"github.com/cockroachdb/cockroach/util/timeutil"
 	"github.com/cockroachdb/cockroach/util/uuid"
@@ -52,6 +55,14 @@ var (
 	errSqrtOfNegNumber   = errors.New("cannot take square root of a negative number")
 	errLogOfNegNumber    = errors.New("cannot take logarithm of a negative number")
 	errLogOfZero         = errors.New("cannot take logarithm of zero")
+
+	errCommentsUnavailable = errors.New("comments are not available in this context")
+	errRetryUnavailable    = errors.New("transaction retries are not available in this context")
+	errSizesUnavailable    = errors.New("size estimates are not available in this context")
+	errKeysUnavailable     = errors.New("table keys are not available in this context")
+	errProfileUnavailable  = errors.New("profiles are not available in this context")
+	errSeqUnavailable      = errors.New("sequences are not available in this context")
+	errFilesUnavailable    = errors.New("uploaded files are not available in this context")
 )
 
 const (
@@ -246,6 +257,47 @@ var Builtins = map[string][]Builtin{
 		return NewDString(fmt.Sprintf("%x", sha256.Sum256([]byte(s)))), nil
 	}, TypeString)},
 
+	// fnv32 returns the 32-bit FNV-1a hash of the concatenation of its
+	// arguments. NULL arguments are ignored.
+	"fnv32": {
+		Builtin{
+			Types:      VariadicType{TypeString},
+			ReturnType: TypeInt,
+			fn: func(_ *EvalContext, args DTuple) (Datum, error) {
+				h := fnv.New32a()
+				for _, d := range args {
+					if d == DNull {
+						continue
+					}
+					_, _ = h.Write([]byte(*d.(*DString)))
+				}
+				return NewDInt(DInt(h.Sum32())), nil
+			},
+		},
+	},
+
+	// crdb_internal.fnv32_key returns the 32-bit FNV-1a hash of the key
+	// encoding of its argument, which can be a tuple. The values which
+	// compare equal, like the decimals 1.0 and 1.00, have the same hash.
+	"crdb_internal.fnv32_key": {
+		Builtin{
+			Types:      AnyType{},
+			ReturnType: TypeInt,
+			fn: func(_ *EvalContext, args DTuple) (Datum, error) {
+				if len(args) != 1 {
+					return nil, fmt.Errorf("crdb_internal.fnv32_key takes 1 argument, got %d", len(args))
+				}
+				key, err := encodeKeyAscending(nil, args[0])
+				if err != nil {
+					return nil, err
+				}
+				h := fnv.New32a()
+				_, _ = h.Write(key)
+				return NewDInt(DInt(h.Sum32())), nil
+			},
+		},
+	},
+
 	"to_hex": {
 		Builtin{
 			Types:      ArgTypes{TypeInt},
@@ -508,6 +560,50 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	// unordered_unique_rowid and node_prefixed_unique_rowid are alternatives
+	// to unique_rowid for the tables with a high rate of inserts, whose
+	// sequential values are all written to the last range of the index.
+	"unordered_unique_rowid": {
+		Builtin{
+			Types:      ArgTypes{},
+			ReturnType: TypeInt,
+			category:   categoryIDGeneration,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				return NewDInt(generateUnorderedUniqueInt(ctx.NodeID)), nil
+			},
+		},
+	},
+
+	"node_prefixed_unique_rowid": {
+		Builtin{
+			Types:      ArgTypes{},
+			ReturnType: TypeInt,
+			category:   categoryIDGeneration,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				return NewDInt(generateNodePrefixedUniqueInt(ctx.NodeID)), nil
+			},
+		},
+	},
+
+	// crdb_internal.serial_nextval increments the sequence backing a SERIAL
+	// column created with serial_normalization = sequence.
+	"crdb_internal.serial_nextval": {
+		Builtin{
+			Types:      ArgTypes{TypeInt},
+			ReturnType: TypeInt,
+			category:   categoryIDGeneration,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Sequences == nil {
+					return nil, errSeqUnavailable
+				}
+				return ctx.Sequences.NextVal(int64(*args[0].(*DInt)))
+			},
+		},
+	},
+
 	"experimental_uuid_v4": {uuidV4Impl},
 	"uuid_v4":              {uuidV4Impl},
 
@@ -601,6 +697,40 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	"timezone": {
+		Builtin{
+			Types:      ArgTypes{TypeString, TypeTimestamp},
+			ReturnType: TypeTimestampTZ,
+			category:   categoryDateAndTime,
+			fn: func(_ *EvalContext, args DTuple) (Datum, error) {
+				// The wall clock time of the timestamp is interpreted in the
+				// time zone.
+				loc, err := loadTimeZone(string(*args[0].(*DString)))
+				if err != nil {
+					return nil, err
+				}
+				t := args[1].(*DTimestamp).Time.UTC()
+				return MakeDTimestampTZ(time.Date(t.Year(), t.Month(), t.Day(), t.Hour(),
+					t.Minute(), t.Second(), t.Nanosecond(), loc), time.Nanosecond), nil
+			},
+		},
+		Builtin{
+			Types:      ArgTypes{TypeString, TypeTimestampTZ},
+			ReturnType: TypeTimestamp,
+			category:   categoryDateAndTime,
+			fn: func(_ *EvalContext, args DTuple) (Datum, error) {
+				// The result is the wall clock time in the time zone.
+				loc, err := loadTimeZone(string(*args[0].(*DString)))
+				if err != nil {
+					return nil, err
+				}
+				t := args[1].(*DTimestampTZ).Time.In(loc)
+				return MakeDTimestamp(time.Date(t.Year(), t.Month(), t.Day(), t.Hour(),
+					t.Minute(), t.Second(), t.Nanosecond(), time.UTC), time.Nanosecond), nil
+			},
+		},
+	},
+
 	"extract": {
 		Builtin{
 			Types:      ArgTypes{TypeString, TypeTimestamp},
@@ -979,6 +1109,39 @@ var Builtins = map[string][]Builtin{
 			return dd, nil
 		}),
 	},
+
+	// System info functions.
+
+	"col_description": {
+		Builtin{
+			Types:      ArgTypes{TypeInt, TypeInt},
+			ReturnType: TypeString,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Comments == nil {
+					return nil, errCommentsUnavailable
+				}
+				return ctx.Comments.ColumnComment(int64(*args[0].(*DInt)), int64(*args[1].(*DInt)))
+			},
+		},
+	},
+
+	"obj_description": {
+		Builtin{
+			Types:      ArgTypes{TypeInt},
+			ReturnType: TypeString,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Comments == nil {
+					return nil, errCommentsUnavailable
+				}
+				return ctx.Comments.ObjectComment(int64(*args[0].(*DInt)))
+			},
+		},
+	},
+
 	"version": {
 		Builtin{
 			Types:      ArgTypes{},
@@ -989,6 +1152,174 @@ var Builtins = map[string][]Builtin{
 			},
 		},
 	},
+
+	// crdb_internal.force_retry returns a retryable error until the current
+	// transaction has been running for at least the given interval. It lets
+	// applications test their transaction retry loops.
+	"crdb_internal.force_retry": {
+		Builtin{
+			Types:      ArgTypes{TypeInterval},
+			ReturnType: TypeInt,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				minDuration := args[0].(*DInterval).Duration
+				if ctx.stmtTimestamp.Before(duration.Add(ctx.txnTimestamp, minDuration)) {
+					if ctx.Retrier == nil {
+						return nil, errRetryUnavailable
+					}
+					return nil, ctx.Retrier.ForceRetry("forced by crdb_internal.force_retry()")
+				}
+				return NewDInt(0), nil
+			},
+		},
+	},
+
+	// crdb_internal.table_size and crdb_internal.index_size estimate the
+	// number of bytes stored on disk for a table or an index, summed over all
+	// the replicas of their ranges.
+	"crdb_internal.table_size": {
+		Builtin{
+			Types:      ArgTypes{TypeString},
+			ReturnType: TypeInt,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Sizes == nil {
+					return nil, errSizesUnavailable
+				}
+				return ctx.Sizes.TableSize(string(*args[0].(*DString)))
+			},
+		},
+	},
+
+	"crdb_internal.index_size": {
+		Builtin{
+			Types:      ArgTypes{TypeString, TypeString},
+			ReturnType: TypeInt,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Sizes == nil {
+					return nil, errSizesUnavailable
+				}
+				return ctx.Sizes.IndexSize(string(*args[0].(*DString)), string(*args[1].(*DString)))
+			},
+		},
+	},
+
+	// crdb_internal.pretty_key, crdb_internal.encode_key and
+	// crdb_internal.decode_key help relating the rows of tables to their keys,
+	// which appear in the range boundaries and the logs.
+	"crdb_internal.pretty_key": {
+		Builtin{
+			Types:      ArgTypes{TypeBytes, TypeInt},
+			ReturnType: TypeString,
+			category:   categorySystemInfo,
+			fn: func(_ *EvalContext, args DTuple) (Datum, error) {
+				return NewDString(prettyKey([]byte(*args[0].(*DBytes)), int(*args[1].(*DInt)))), nil
+			},
+		},
+	},
+
+	"crdb_internal.encode_key": {
+		Builtin{
+			Types:      ArgTypes{TypeString, TypeString, TypeString},
+			ReturnType: TypeBytes,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Keys == nil {
+					return nil, errKeysUnavailable
+				}
+				return ctx.Keys.EncodeKey(string(*args[0].(*DString)), string(*args[1].(*DString)),
+					string(*args[2].(*DString)))
+			},
+		},
+	},
+
+	"crdb_internal.decode_key": {
+		Builtin{
+			Types:      ArgTypes{TypeString, TypeBytes},
+			ReturnType: TypeString,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Keys == nil {
+					return nil, errKeysUnavailable
+				}
+				return ctx.Keys.DecodeKey(string(*args[0].(*DString)), []byte(*args[1].(*DBytes)))
+			},
+		},
+	},
+
+	// crdb_internal.cpu_profile and crdb_internal.heap_profile return the
+	// profiles of the node executing the statement, in the format read by
+	// `go tool pprof`.
+	"crdb_internal.cpu_profile": {
+		Builtin{
+			Types:      ArgTypes{TypeInterval},
+			ReturnType: TypeBytes,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Profiler == nil {
+					return nil, errProfileUnavailable
+				}
+				d := args[0].(*DInterval).Duration
+				if d.Months != 0 || d.Days != 0 || d.Nanos <= 0 {
+					return nil, fmt.Errorf("invalid profile duration: %s", d)
+				}
+				return ctx.Profiler.CPUProfile(time.Duration(d.Nanos))
+			},
+		},
+	},
+
+	"crdb_internal.heap_profile": {
+		Builtin{
+			Types:      ArgTypes{},
+			ReturnType: TypeBytes,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Profiler == nil {
+					return nil, errProfileUnavailable
+				}
+				return ctx.Profiler.HeapProfile()
+			},
+		},
+	},
+
+	// crdb_internal.userfile returns the contents of a file uploaded with
+	// `cockroach userfile upload`, named by its userfile:// URI, so that the
+	// bulk operations can read files without access to external storage.
+	"crdb_internal.userfile": {
+		Builtin{
+			Types:      ArgTypes{TypeString},
+			ReturnType: TypeBytes,
+			category:   categorySystemInfo,
+			impure:     true,
+			fn: func(ctx *EvalContext, args DTuple) (Datum, error) {
+				if ctx.Userfiles == nil {
+					return nil, errFilesUnavailable
+				}
+				return ctx.Userfiles.ReadUserfile(string(*args[0].(*DString)))
+			},
+		},
+	},
+
+	// crdb_version returns the actual CockroachDB version, which clients can
+	// rely on regardless of the PostgreSQL server_version reported to them.
+	"crdb_version": {
+		Builtin{
+			Types:      ArgTypes{},
+			ReturnType: TypeString,
+			category:   categorySystemInfo,
+			fn: func(_ *EvalContext, args DTuple) (Datum, error) {
+				return NewDString(build.GetInfo().Short()), nil
+			},
+		},
+	},
 }
 
 func init() {
@@ -1393,6 +1724,31 @@ func overlay(s, to string, pos, size int) (Datum, error) {
 	return NewDString(string(runes[:pos]) + to + string(runes[after:])), nil
 }
 
+// loadTimeZone returns the location of a time zone of the tz database, such
+// as "UTC" or "America/New_York".
+func loadTimeZone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find time zone %q: %v", name, err)
+	}
+	return loc, nil
+}
+
+// prettyKey returns the human readable form of a key, without its first
+// skipFields fields, so that "/Table/51/1/2/0" becomes "/1/2/0" when two fields
+// are skipped.
+func prettyKey(key roachpb.Key, skipFields int) string {
+	p := keys.PrettyPrint(key)
+	for i := 0; i < skipFields && len(p) > 0; i++ {
+		n := strings.IndexByte(p[1:], '/')
+		if n == -1 {
+			return ""
+		}
+		p = p[n+1:]
+	}
+	return p
+}
+
 func round(x float64, n int64) (Datum, error) {
 	switch {
 	case n < 0:
@@ -1498,8 +1854,23 @@ func generateUniqueInt(nodeID roachpb.NodeID) DInt {
 	// TODO(pmattis): Do we have to worry about persisting the milliseconds value
 	// periodically to avoid the clock ever going backwards (e.g. due to NTP
 	// adjustment)?
+	id := generateUniqueTimestamp()
+
+	// We xor in the nodeID so that nodeIDs larger than 32K will flip bits in the
+	// timestamp portion of the final value instead of always setting them.
+	id = (id << uniqueIntNodeIDBits) ^ uint64(nodeID)
+	return DInt(id)
+}
+
+// uniqueIntNodeIDBits is the number of bits of the node ID in the values of
+// generateUniqueInt.
+const uniqueIntNodeIDBits = 15
+
+// generateUniqueTimestamp returns the current time at a 10-microsecond
+// granularity since uniqueIntEpoch. The returned values are strictly
+// increasing.
+func generateUniqueTimestamp() uint64 {
 	const precision = uint64(10 * time.Microsecond)
-	const nodeIDBits = 15
 
 	nowNanos := timeutil.Now().UnixNano()
 	// Paranoia: nowNanos should never be less than uniqueIntEpoch.
@@ -1514,9 +1885,77 @@ func generateUniqueInt(nodeID roachpb.NodeID) DInt {
 	}
 	uniqueIntState.timestamp = id
 	uniqueIntState.Unlock()
+	return id
+}
 
-	// We xor in the nodeID so that nodeIDs larger than 32K will flip bits in the
-	// timestamp portion of the final value instead of always setting them.
-	id = (id << nodeIDBits) ^ uint64(nodeID)
-	return DInt(id)
+// generateUnorderedUniqueInt returns the value of generateUniqueInt with its
+// bits reversed, so that consecutive values are spread over the whole range
+// of positive integers instead of being inserted at the end of an index.
+// Reversing the 63 lower bits keeps the values unique and positive.
+func generateUnorderedUniqueInt(nodeID roachpb.NodeID) DInt {
+	id := uint64(generateUniqueInt(nodeID))
+	var reversed uint64
+	for i := 0; i < 63; i++ {
+		reversed = (reversed << 1) | (id & 1)
+		id >>= 1
+	}
+	return DInt(reversed)
+}
+
+// generateNodePrefixedUniqueInt returns a unique int whose upper bits are the
+// node ID, followed by the timestamp of generateUniqueInt. The values
+// generated by a node are increasing and grouped together, so that the
+// inserts of each node are at the end of a different part of an index. The
+// top-bit is left empty so that negative values are not returned, and only
+// the lower 15 bits of the node ID are used.
+func generateNodePrefixedUniqueInt(nodeID roachpb.NodeID) DInt {
+	const timestampBits = 63 - uniqueIntNodeIDBits
+	const nodeIDMask = 1<<uniqueIntNodeIDBits - 1
+	id := generateUniqueTimestamp() & (1<<timestampBits - 1)
+	return DInt((uint64(nodeID)&nodeIDMask)<<timestampBits | id)
+}
+
+// encodeKeyAscending appends the ascending key encoding of a datum to b. It
+// matches the encoding of the datums in the keys of indexes.
+func encodeKeyAscending(b []byte, d Datum) ([]byte, error) {
+	if d == DNull {
+		return encoding.EncodeNullAscending(b), nil
+	}
+	switch t := d.(type) {
+	case *DBool:
+		if *t {
+			return encoding.EncodeVarintAscending(b, 1), nil
+		}
+		return encoding.EncodeVarintAscending(b, 0), nil
+	case *DInt:
+		return encoding.EncodeVarintAscending(b, int64(*t)), nil
+	case *DFloat:
+		return encoding.EncodeFloatAscending(b, float64(*t)), nil
+	case *DDecimal:
+		return encoding.EncodeDecimalAscending(b, &t.Dec), nil
+	case *DString:
+		return encoding.EncodeStringAscending(b, string(*t)), nil
+	case *DCollatedString:
+		b = encoding.EncodeBytesAscending(b, t.Key)
+		return encoding.EncodeStringAscending(b, t.Contents), nil
+	case *DBytes:
+		return encoding.EncodeStringAscending(b, string(*t)), nil
+	case *DDate:
+		return encoding.EncodeVarintAscending(b, int64(*t)), nil
+	case *DTimestamp:
+		return encoding.EncodeTimeAscending(b, t.Time), nil
+	case *DTimestampTZ:
+		return encoding.EncodeTimeAscending(b, t.Time), nil
+	case *DInterval:
+		return encoding.EncodeDurationAscending(b, t.Duration)
+	case *DTuple:
+		for _, datum := range *t {
+			var err error
+			if b, err = encodeKeyAscending(b, datum); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("unable to encode key: %T", d)
 }
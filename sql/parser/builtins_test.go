@@ -30,3 +30,32 @@ func TestCategory(t *testing.T) {
 		t.Fatalf("bad category: expected %q got %q", expected, actual)
 	}
 }
+
+func TestUniqueIntVariants(t *testing.T) {
+	const nodeID = 3
+	prevOrdered := generateUniqueInt(nodeID)
+	prevPrefixed := generateNodePrefixedUniqueInt(nodeID)
+	unordered := map[DInt]struct{}{}
+	for i := 0; i < 1000; i++ {
+		// The ordered values increase, the unordered ones are positive and unique.
+		ordered := generateUniqueInt(nodeID)
+		u := generateUnorderedUniqueInt(nodeID)
+		if ordered <= prevOrdered || u < 0 {
+			t.Fatalf("unexpected values %d, %d after %d", ordered, u, prevOrdered)
+		}
+		prevOrdered = ordered
+		if _, ok := unordered[u]; ok {
+			t.Fatalf("duplicate value %d", u)
+		}
+		unordered[u] = struct{}{}
+
+		prefixed := generateNodePrefixedUniqueInt(nodeID)
+		if prefixed <= prevPrefixed {
+			t.Fatalf("expected %d to be greater than %d", prefixed, prevPrefixed)
+		}
+		if n := prefixed >> (63 - uniqueIntNodeIDBits); n != nodeID {
+			t.Fatalf("expected node ID %d in %d, but found %d", nodeID, prefixed, n)
+		}
+		prevPrefixed = prefixed
+	}
+}
@@ -30,16 +30,17 @@ type ColumnType interface {
 	columnType()
 }
 
-func (*BoolColType) columnType()        {}
-func (*IntColType) columnType()         {}
-func (*FloatColType) columnType()       {}
-func (*DecimalColType) columnType()     {}
-func (*DateColType) columnType()        {}
-func (*TimestampColType) columnType()   {}
-func (*TimestampTZColType) columnType() {}
-func (*IntervalColType) columnType()    {}
-func (*StringColType) columnType()      {}
-func (*BytesColType) columnType()       {}
+func (*BoolColType) columnType()           {}
+func (*IntColType) columnType()            {}
+func (*FloatColType) columnType()          {}
+func (*DecimalColType) columnType()        {}
+func (*DateColType) columnType()           {}
+func (*TimestampColType) columnType()      {}
+func (*TimestampTZColType) columnType()    {}
+func (*IntervalColType) columnType()       {}
+func (*StringColType) columnType()         {}
+func (*CollatedStringColType) columnType() {}
+func (*BytesColType) columnType()          {}
 
 // Pre-allocated immutable boolean column types.
 var (
@@ -222,6 +223,24 @@ func (node *StringColType) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// CollatedStringColType represents a STRING, CHAR or VARCHAR type with a
+// locale.
+type CollatedStringColType struct {
+	Name   string
+	N      int
+	Locale string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CollatedStringColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(node.Name)
+	if node.N > 0 {
+		fmt.Fprintf(buf, "(%d)", node.N)
+	}
+	buf.WriteString(" COLLATE ")
+	FormatNode(buf, f, Name(node.Locale))
+}
+
 // Pre-allocated immutable bytes column types.
 var (
 	bytesColTypeBlob  = &BytesColType{Name: "BLOB"}
@@ -239,22 +258,25 @@ func (node *BytesColType) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(node.Name)
 }
 
-func (node *BoolColType) String() string        { return AsString(node) }
-func (node *IntColType) String() string         { return AsString(node) }
-func (node *FloatColType) String() string       { return AsString(node) }
-func (node *DecimalColType) String() string     { return AsString(node) }
-func (node *DateColType) String() string        { return AsString(node) }
-func (node *TimestampColType) String() string   { return AsString(node) }
-func (node *TimestampTZColType) String() string { return AsString(node) }
-func (node *IntervalColType) String() string    { return AsString(node) }
-func (node *StringColType) String() string      { return AsString(node) }
-func (node *BytesColType) String() string       { return AsString(node) }
+func (node *BoolColType) String() string           { return AsString(node) }
+func (node *IntColType) String() string            { return AsString(node) }
+func (node *FloatColType) String() string          { return AsString(node) }
+func (node *DecimalColType) String() string        { return AsString(node) }
+func (node *DateColType) String() string           { return AsString(node) }
+func (node *TimestampColType) String() string      { return AsString(node) }
+func (node *TimestampTZColType) String() string    { return AsString(node) }
+func (node *IntervalColType) String() string       { return AsString(node) }
+func (node *StringColType) String() string         { return AsString(node) }
+func (node *CollatedStringColType) String() string { return AsString(node) }
+func (node *BytesColType) String() string          { return AsString(node) }
 
 // DatumTypeToColumnType produces a SQL column type equivalent to the
 // given Datum type. Used to generate CastExpr nodes during
 // normalization.
 func DatumTypeToColumnType(d Datum) (ColumnType, error) {
-	switch d.(type) {
+	switch t := d.(type) {
+	case *DBool:
+		return boolColTypeBool, nil
 	case *DInt:
 		return intColTypeInt, nil
 	case *DFloat:
@@ -271,6 +293,8 @@ func DatumTypeToColumnType(d Datum) (ColumnType, error) {
 		return dateColTypeDate, nil
 	case *DString:
 		return stringColTypeString, nil
+	case *DCollatedString:
+		return &CollatedStringColType{Name: "STRING", Locale: t.Locale}, nil
 	case *DBytes:
 		return bytesColTypeBytes, nil
 	}
@@ -0,0 +1,81 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package parser
+
+import "bytes"
+
+// CommentOnDatabase represents a COMMENT ON DATABASE statement.
+type CommentOnDatabase struct {
+	Name    Name
+	Comment *string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CommentOnDatabase) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("COMMENT ON DATABASE ")
+	FormatNode(buf, f, node.Name)
+	formatComment(buf, node.Comment)
+}
+
+// CommentOnTable represents a COMMENT ON TABLE statement.
+type CommentOnTable struct {
+	Table   *QualifiedName
+	Comment *string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CommentOnTable) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("COMMENT ON TABLE ")
+	FormatNode(buf, f, node.Table)
+	formatComment(buf, node.Comment)
+}
+
+// CommentOnColumn represents a COMMENT ON COLUMN statement. The column is
+// qualified by its table, e.g. t.col or db.t.col.
+type CommentOnColumn struct {
+	ColumnName *QualifiedName
+	Comment    *string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CommentOnColumn) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("COMMENT ON COLUMN ")
+	FormatNode(buf, f, node.ColumnName)
+	formatComment(buf, node.Comment)
+}
+
+// CommentOnIndex represents a COMMENT ON INDEX statement.
+type CommentOnIndex struct {
+	Index   *TableNameWithIndex
+	Comment *string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CommentOnIndex) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("COMMENT ON INDEX ")
+	FormatNode(buf, f, node.Index)
+	formatComment(buf, node.Comment)
+}
+
+// formatComment formats the IS clause of a COMMENT ON statement. A nil comment
+// removes the comment and is formatted as NULL.
+func formatComment(buf *bytes.Buffer, comment *string) {
+	buf.WriteString(" IS ")
+	if comment == nil {
+		buf.WriteString("NULL")
+		return
+	}
+	encodeSQLString(buf, *comment)
+}
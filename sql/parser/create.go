@@ -31,7 +31,11 @@ import (
 type CreateDatabase struct {
 	IfNotExists bool
 	Name        Name
+	Template    string
+	Owner       string
 	Encoding    *StrVal
+	Collate     string
+	CType       string
 }
 
 // Format implements the NodeFormatter interface.
@@ -41,21 +45,48 @@ func (node *CreateDatabase) Format(buf *bytes.Buffer, f FmtFlags) {
 		buf.WriteString("IF NOT EXISTS ")
 	}
 	FormatNode(buf, f, node.Name)
+	if node.Template != "" {
+		buf.WriteString(" TEMPLATE=")
+		FormatNode(buf, f, Name(node.Template))
+	}
+	if node.Owner != "" {
+		buf.WriteString(" OWNER=")
+		FormatNode(buf, f, Name(node.Owner))
+	}
 	if node.Encoding != nil {
 		buf.WriteString(" ENCODING=")
 		node.Encoding.Format(buf, f)
 	}
+	if node.Collate != "" {
+		buf.WriteString(" LC_COLLATE=")
+		encodeSQLString(buf, node.Collate)
+	}
+	if node.CType != "" {
+		buf.WriteString(" LC_CTYPE=")
+		encodeSQLString(buf, node.CType)
+	}
 }
 
 // IndexElem represents a column with a direction in a CREATE INDEX statement.
+// The elements of an expression index are expressions instead of columns.
 type IndexElem struct {
 	Column    Name
+	Expr      Expr
 	Direction Direction
 }
 
 // Format implements the NodeFormatter interface.
 func (node IndexElem) Format(buf *bytes.Buffer, f FmtFlags) {
-	FormatNode(buf, f, node.Column)
+	switch node.Expr.(type) {
+	case nil:
+		FormatNode(buf, f, node.Column)
+	case *FuncExpr:
+		FormatNode(buf, f, node.Expr)
+	default:
+		buf.WriteByte('(')
+		FormatNode(buf, f, node.Expr)
+		buf.WriteByte(')')
+	}
 	if node.Direction != DefaultDirection {
 		buf.WriteByte(' ')
 		buf.WriteString(node.Direction.String())
@@ -81,12 +112,15 @@ type CreateIndex struct {
 	Name        Name
 	Table       *QualifiedName
 	Unique      bool
+	Inverted    bool
 	IfNotExists bool
 	Columns     IndexElemList
+	Sharded     *ShardedIndexDef
 	// Extra columns to be stored together with the indexed ones as an optimization
 	// for improved reading performance.
-	Storing    NameList
-	Interleave *InterleaveDef
+	Storing     NameList
+	Interleave  *InterleaveDef
+	PartitionBy *PartitionBy
 }
 
 // Format implements the NodeFormatter interface.
@@ -95,6 +129,9 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Unique {
 		buf.WriteString("UNIQUE ")
 	}
+	if node.Inverted {
+		buf.WriteString("INVERTED ")
+	}
 	buf.WriteString("INDEX ")
 	if node.IfNotExists {
 		buf.WriteString("IF NOT EXISTS ")
@@ -105,12 +142,151 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	fmt.Fprintf(buf, "ON %s (", node.Table)
 	FormatNode(buf, f, node.Columns)
 	buf.WriteByte(')')
+	if node.Sharded != nil {
+		FormatNode(buf, f, node.Sharded)
+	}
 	if node.Storing != nil {
 		fmt.Fprintf(buf, " STORING (%s)", node.Storing)
 	}
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.PartitionBy != nil {
+		FormatNode(buf, f, node.PartitionBy)
+	}
+}
+
+// ShardedIndexDef represents a hash sharded index definition within a CREATE
+// TABLE or CREATE INDEX statement.
+type ShardedIndexDef struct {
+	ShardBuckets Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShardedIndexDef) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(" USING HASH WITH BUCKET_COUNT = ")
+	FormatNode(buf, f, node.ShardBuckets)
+}
+
+// CreateStatistics represents a CREATE STATISTICS statement.
+type CreateStatistics struct {
+	Name        Name
+	ColumnNames NameList
+	Table       *QualifiedName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateStatistics) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE STATISTICS ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.ColumnNames)
+	buf.WriteString(" FROM ")
+	FormatNode(buf, f, node.Table)
+}
+
+// TriggerEvent is a bitmask of the statements a trigger fires on.
+type TriggerEvent int
+
+// TriggerEvent values.
+const (
+	TriggerInsert TriggerEvent = 1 << iota
+	TriggerUpdate
+	TriggerDelete
+)
+
+// Format implements the NodeFormatter interface.
+func (e TriggerEvent) Format(buf *bytes.Buffer, f FmtFlags) {
+	sep := ""
+	for _, ev := range []struct {
+		event TriggerEvent
+		name  string
+	}{
+		{TriggerInsert, "INSERT"},
+		{TriggerUpdate, "UPDATE"},
+		{TriggerDelete, "DELETE"},
+	} {
+		if e&ev.event != 0 {
+			buf.WriteString(sep)
+			buf.WriteString(ev.name)
+			sep = " OR "
+		}
+	}
+}
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name   Name
+	Before bool
+	Events TriggerEvent
+	Table  *QualifiedName
+	// Body holds the statements executed for each row.
+	Body string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE TRIGGER ")
+	FormatNode(buf, f, node.Name)
+	if node.Before {
+		buf.WriteString(" BEFORE ")
+	} else {
+		buf.WriteString(" AFTER ")
+	}
+	FormatNode(buf, f, node.Events)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.Table)
+	buf.WriteString(" FOR EACH ROW EXECUTE ")
+	encodeSQLString(buf, node.Body)
+}
+
+// CreatePolicy represents a CREATE POLICY statement.
+type CreatePolicy struct {
+	Name  Name
+	Table *QualifiedName
+	Expr  Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePolicy) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE POLICY ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.Table)
+	buf.WriteString(" USING (")
+	FormatNode(buf, f, node.Expr)
+	buf.WriteByte(')')
+}
+
+// CreateMaterializedView represents a CREATE MATERIALIZED VIEW statement.
+type CreateMaterializedView struct {
+	Name        *QualifiedName
+	ColumnNames NameList
+	AsSource    *Select
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateMaterializedView) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE MATERIALIZED VIEW ")
+	FormatNode(buf, f, node.Name)
+	if len(node.ColumnNames) > 0 {
+		buf.WriteString(" (")
+		FormatNode(buf, f, node.ColumnNames)
+		buf.WriteByte(')')
+	}
+	buf.WriteString(" AS ")
+	FormatNode(buf, f, node.AsSource)
+}
+
+// RefreshMaterializedView represents a REFRESH MATERIALIZED VIEW statement.
+type RefreshMaterializedView struct {
+	Name *QualifiedName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RefreshMaterializedView) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("REFRESH MATERIALIZED VIEW ")
+	FormatNode(buf, f, node.Name)
 }
 
 // TableDef represents a column, index or constraint definition within a CREATE
@@ -126,6 +302,7 @@ type TableDef interface {
 func (*ColumnTableDef) tableDef() {}
 func (*IndexTableDef) tableDef()  {}
 func (*FamilyTableDef) tableDef() {}
+func (*LikeTableDef) tableDef()   {}
 
 // TableDefs represents a list of table definitions.
 type TableDefs []TableDef
@@ -175,23 +352,50 @@ type ColumnTableDef struct {
 		Table          *QualifiedName
 		Col            Name
 		ConstraintName Name
+		Actions        ReferenceActions
+		Timing         ConstraintTiming
 	}
 	Family struct {
 		Name        Name
 		Create      bool
 		IfNotExists bool
 	}
+	Computed struct {
+		Computed bool
+		Expr     Expr
+		Stored   bool
+	}
 }
 
 func newColumnTableDef(
 	name Name, typ ColumnType, qualifications []NamedColumnQualification,
-) *ColumnTableDef {
+) (*ColumnTableDef, error) {
 	d := &ColumnTableDef{
 		Name: name,
 		Type: typ,
 	}
 	d.Nullable.Nullability = SilentNull
+	// The timing clauses apply to the constraint preceding them.
+	var prev ColumnQualification
+	var fkTiming []ConstraintTimingAttr
 	for _, c := range qualifications {
+		if attr, ok := c.Qualification.(ConstraintTimingAttr); ok {
+			switch prev.(type) {
+			case *ColumnFKConstraint:
+				fkTiming = append(fkTiming, attr)
+			case UniqueConstraint, PrimaryKeyConstraint:
+				if attr == Deferrable || attr == InitiallyDeferred {
+					return nil, fmt.Errorf("unique constraints cannot be deferred")
+				}
+				if attr == NotValid {
+					return nil, fmt.Errorf("unique constraints cannot be marked NOT VALID")
+				}
+			default:
+				return nil, fmt.Errorf("misplaced %s clause", attr)
+			}
+			continue
+		}
+		prev = c.Qualification
 		switch t := c.Qualification.(type) {
 		case *ColumnDefault:
 			d.DefaultExpr.Expr = t.Expr
@@ -226,6 +430,7 @@ func newColumnTableDef(
 		case *ColumnFKConstraint:
 			d.References.Table = t.Table
 			d.References.Col = t.Col
+			d.References.Actions = t.Actions
 			if c.Name != "" {
 				d.References.ConstraintName = c.Name
 			}
@@ -233,11 +438,32 @@ func newColumnTableDef(
 			d.Family.Name = t.Family
 			d.Family.Create = t.Create
 			d.Family.IfNotExists = t.IfNotExists
+		case *ColumnComputedDef:
+			d.Computed.Computed = true
+			d.Computed.Expr = t.Expr
+			d.Computed.Stored = t.Stored
+		case ColumnCollation:
+			locale := string(t)
+			if err := CheckCollationLocale(locale); err != nil {
+				return nil, err
+			}
+			switch s := d.Type.(type) {
+			case *StringColType:
+				d.Type = &CollatedStringColType{Name: s.Name, N: s.N, Locale: locale}
+			case *CollatedStringColType:
+				return nil, fmt.Errorf("multiple COLLATE declarations for column %q", name)
+			default:
+				return nil, fmt.Errorf("COLLATE declaration for non-string-typed column %q", name)
+			}
 		default:
 			panic(fmt.Sprintf("unexpected column qualification: %T", c))
 		}
 	}
-	return d
+	var err error
+	if d.References.Timing, err = makeConstraintTiming(fkTiming); err != nil {
+		return nil, err
+	}
+	return d, nil
 }
 
 func (node *ColumnTableDef) setName(name Name) {
@@ -269,6 +495,15 @@ func (node *ColumnTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 		buf.WriteString(" DEFAULT ")
 		FormatNode(buf, f, node.DefaultExpr.Expr)
 	}
+	if node.Computed.Computed {
+		buf.WriteString(" AS (")
+		FormatNode(buf, f, node.Computed.Expr)
+		if node.Computed.Stored {
+			buf.WriteString(") STORED")
+		} else {
+			buf.WriteString(") VIRTUAL")
+		}
+	}
 	if node.CheckExpr.Expr != nil {
 		if node.CheckExpr.ConstraintName != "" {
 			fmt.Fprintf(buf, " CONSTRAINT %s", node.CheckExpr.ConstraintName)
@@ -288,6 +523,8 @@ func (node *ColumnTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 			FormatNode(buf, f, node.References.Col)
 			buf.WriteByte(')')
 		}
+		FormatNode(buf, f, node.References.Actions)
+		FormatNode(buf, f, node.References.Timing)
 	}
 	if node.Family.Name != "" || node.Family.Create {
 		if node.Family.Create {
@@ -323,6 +560,12 @@ func (UniqueConstraint) columnQualification()        {}
 func (*ColumnCheckConstraint) columnQualification()  {}
 func (*ColumnFKConstraint) columnQualification()     {}
 func (*ColumnFamilyConstraint) columnQualification() {}
+func (*ColumnComputedDef) columnQualification()      {}
+func (ColumnCollation) columnQualification()         {}
+func (ConstraintTimingAttr) columnQualification()    {}
+
+// ColumnCollation represents a COLLATE clause for a column.
+type ColumnCollation string
 
 // ColumnDefault represents a DEFAULT clause for a column.
 type ColumnDefault struct {
@@ -346,10 +589,140 @@ type ColumnCheckConstraint struct {
 	Expr Expr
 }
 
+// ColumnComputedDef represents the description of a computed column, whose
+// value is either stored or computed when the row is read.
+type ColumnComputedDef struct {
+	Expr   Expr
+	Stored bool
+}
+
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table *QualifiedName
-	Col   Name // empty-string means use PK
+	Table   *QualifiedName
+	Col     Name // empty-string means use PK
+	Actions ReferenceActions
+}
+
+// ReferenceAction is the action taken on the rows referencing a row of
+// another table when the row is deleted or updated.
+type ReferenceAction int
+
+// ReferenceAction values.
+const (
+	NoAction ReferenceAction = iota
+	Restrict
+	Cascade
+	SetNull
+	SetDefault
+)
+
+var referenceActionName = [...]string{
+	NoAction:   "NO ACTION",
+	Restrict:   "RESTRICT",
+	Cascade:    "CASCADE",
+	SetNull:    "SET NULL",
+	SetDefault: "SET DEFAULT",
+}
+
+func (a ReferenceAction) String() string {
+	return referenceActionName[a]
+}
+
+// ReferenceActions are the actions of a foreign key. The default NO ACTION
+// is not formatted.
+type ReferenceActions struct {
+	Delete ReferenceAction
+	Update ReferenceAction
+}
+
+// Format implements the NodeFormatter interface.
+func (node ReferenceActions) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Delete != NoAction {
+		fmt.Fprintf(buf, " ON DELETE %s", node.Delete)
+	}
+	if node.Update != NoAction {
+		fmt.Fprintf(buf, " ON UPDATE %s", node.Update)
+	}
+}
+
+// ConstraintTimingAttr is a DEFERRABLE, NOT DEFERRABLE, INITIALLY DEFERRED,
+// INITIALLY IMMEDIATE or NOT VALID clause of a constraint.
+type ConstraintTimingAttr int
+
+// ConstraintTimingAttr values.
+const (
+	Deferrable ConstraintTimingAttr = iota
+	NotDeferrable
+	InitiallyDeferred
+	InitiallyImmediate
+	NotValid
+)
+
+var constraintTimingAttrName = [...]string{
+	Deferrable:         "DEFERRABLE",
+	NotDeferrable:      "NOT DEFERRABLE",
+	InitiallyDeferred:  "INITIALLY DEFERRED",
+	InitiallyImmediate: "INITIALLY IMMEDIATE",
+	NotValid:           "NOT VALID",
+}
+
+func (a ConstraintTimingAttr) String() string {
+	return constraintTimingAttrName[a]
+}
+
+// ConstraintTiming determines whether the checks of a constraint can be
+// deferred until the end of the transaction, and whether they are by default.
+// The default NOT DEFERRABLE INITIALLY IMMEDIATE is not formatted. NotValid
+// skips the checks of the existing rows of the table a constraint is added
+// to.
+type ConstraintTiming struct {
+	Deferrable        bool
+	InitiallyDeferred bool
+	NotValid          bool
+}
+
+// makeConstraintTiming combines the timing clauses of a constraint. As in
+// postgres, INITIALLY DEFERRED implies DEFERRABLE.
+func makeConstraintTiming(attrs []ConstraintTimingAttr) (ConstraintTiming, error) {
+	var t ConstraintTiming
+	var notDeferrable, initiallyImmediate bool
+	for _, a := range attrs {
+		switch a {
+		case Deferrable:
+			t.Deferrable = true
+		case NotDeferrable:
+			notDeferrable = true
+		case InitiallyDeferred:
+			t.InitiallyDeferred = true
+		case InitiallyImmediate:
+			initiallyImmediate = true
+		case NotValid:
+			t.NotValid = true
+		}
+	}
+	if t.InitiallyDeferred && notDeferrable {
+		return t, fmt.Errorf("constraint declared INITIALLY DEFERRED must be DEFERRABLE")
+	}
+	if t.Deferrable && notDeferrable || t.InitiallyDeferred && initiallyImmediate {
+		return t, fmt.Errorf("conflicting constraint properties")
+	}
+	if t.InitiallyDeferred {
+		t.Deferrable = true
+	}
+	return t, nil
+}
+
+// Format implements the NodeFormatter interface.
+func (node ConstraintTiming) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Deferrable {
+		buf.WriteString(" DEFERRABLE")
+	}
+	if node.InitiallyDeferred {
+		buf.WriteString(" INITIALLY DEFERRED")
+	}
+	if node.NotValid {
+		buf.WriteString(" NOT VALID")
+	}
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -372,10 +745,13 @@ func NameListToIndexElems(lst NameList) IndexElemList {
 // IndexTableDef represents an index definition within a CREATE TABLE
 // statement.
 type IndexTableDef struct {
-	Name       Name
-	Columns    IndexElemList
-	Storing    NameList
-	Interleave *InterleaveDef
+	Name        Name
+	Columns     IndexElemList
+	Sharded     *ShardedIndexDef
+	Storing     NameList
+	Interleave  *InterleaveDef
+	PartitionBy *PartitionBy
+	Inverted    bool
 }
 
 func (node *IndexTableDef) setName(name Name) {
@@ -384,6 +760,9 @@ func (node *IndexTableDef) setName(name Name) {
 
 // Format implements the NodeFormatter interface.
 func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Inverted {
+		buf.WriteString("INVERTED ")
+	}
 	buf.WriteString("INDEX ")
 	if node.Name != "" {
 		FormatNode(buf, f, node.Name)
@@ -392,6 +771,9 @@ func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteByte('(')
 	FormatNode(buf, f, node.Columns)
 	buf.WriteByte(')')
+	if node.Sharded != nil {
+		FormatNode(buf, f, node.Sharded)
+	}
 	if node.Storing != nil {
 		buf.WriteString(" STORING (")
 		FormatNode(buf, f, node.Storing)
@@ -400,6 +782,9 @@ func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.PartitionBy != nil {
+		FormatNode(buf, f, node.PartitionBy)
+	}
 }
 
 // ConstraintTableDef represents a constraint definition within a CREATE TABLE
@@ -433,6 +818,9 @@ func (node *UniqueConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteByte('(')
 	FormatNode(buf, f, node.Columns)
 	buf.WriteByte(')')
+	if node.Sharded != nil {
+		FormatNode(buf, f, node.Sharded)
+	}
 	if node.Storing != nil {
 		buf.WriteString(" STORING (")
 		FormatNode(buf, f, node.Storing)
@@ -441,6 +829,9 @@ func (node *UniqueConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.PartitionBy != nil {
+		FormatNode(buf, f, node.PartitionBy)
+	}
 }
 
 func (*CheckConstraintTableDef) tableDef()           {}
@@ -449,8 +840,9 @@ func (*CheckConstraintTableDef) constraintTableDef() {}
 // CheckConstraintTableDef represents a check constraint within a CREATE
 // TABLE statement.
 type CheckConstraintTableDef struct {
-	Name Name
-	Expr Expr
+	Name     Name
+	Expr     Expr
+	NotValid bool
 }
 
 func (node *CheckConstraintTableDef) setName(name Name) {
@@ -465,6 +857,46 @@ func (node *CheckConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	fmt.Fprintf(buf, "CHECK (")
 	FormatNode(buf, f, node.Expr)
 	buf.WriteByte(')')
+	if node.NotValid {
+		buf.WriteString(" NOT VALID")
+	}
+}
+
+func (*ForeignKeyConstraintTableDef) tableDef()           {}
+func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
+
+// ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint within a
+// CREATE TABLE statement. An empty ToCols references the primary key of the
+// table.
+type ForeignKeyConstraintTableDef struct {
+	Name     Name
+	Table    *QualifiedName
+	FromCols NameList
+	ToCols   NameList
+	Actions  ReferenceActions
+	Timing   ConstraintTiming
+}
+
+func (node *ForeignKeyConstraintTableDef) setName(name Name) {
+	node.Name = name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ForeignKeyConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Name != "" {
+		fmt.Fprintf(buf, "CONSTRAINT %s ", node.Name)
+	}
+	buf.WriteString("FOREIGN KEY (")
+	FormatNode(buf, f, node.FromCols)
+	buf.WriteString(") REFERENCES ")
+	FormatNode(buf, f, node.Table)
+	if len(node.ToCols) > 0 {
+		buf.WriteString(" (")
+		FormatNode(buf, f, node.ToCols)
+		buf.WriteByte(')')
+	}
+	FormatNode(buf, f, node.Actions)
+	FormatNode(buf, f, node.Timing)
 }
 
 // FamilyElem represents a column in a FAMILY constraint.
@@ -514,6 +946,73 @@ func (node *FamilyTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteByte(')')
 }
 
+// LikeTableOpt is a set of the properties of a table copied by LIKE in
+// addition to its columns.
+type LikeTableOpt int
+
+// The values for LikeTableOpt.
+const (
+	LikeTableOptConstraints LikeTableOpt = 1 << iota
+	LikeTableOptDefaults
+	LikeTableOptIndexes
+
+	LikeTableOptAll = LikeTableOptConstraints | LikeTableOptDefaults | LikeTableOptIndexes
+)
+
+var likeTableOptNames = map[LikeTableOpt]string{
+	LikeTableOptConstraints: "CONSTRAINTS",
+	LikeTableOptDefaults:    "DEFAULTS",
+	LikeTableOptIndexes:     "INDEXES",
+	LikeTableOptAll:         "ALL",
+}
+
+func (o LikeTableOpt) String() string {
+	return likeTableOptNames[o]
+}
+
+// LikeTableOption represents an INCLUDING or EXCLUDING option of LIKE.
+type LikeTableOption struct {
+	Excluded bool
+	Opt      LikeTableOpt
+}
+
+// LikeTableDef represents a LIKE definition within a CREATE TABLE statement,
+// which copies the columns of another table.
+type LikeTableDef struct {
+	Name    *QualifiedName
+	Options []LikeTableOption
+}
+
+func (node *LikeTableDef) setName(name Name) {}
+
+// Opts returns the properties of the table which are copied, with the
+// options applied in order.
+func (node *LikeTableDef) Opts() LikeTableOpt {
+	var opts LikeTableOpt
+	for _, o := range node.Options {
+		if o.Excluded {
+			opts &^= o.Opt
+		} else {
+			opts |= o.Opt
+		}
+	}
+	return opts
+}
+
+// Format implements the NodeFormatter interface.
+func (node *LikeTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("LIKE ")
+	FormatNode(buf, f, node.Name)
+	for _, o := range node.Options {
+		if o.Excluded {
+			buf.WriteString(" EXCLUDING ")
+		} else {
+			buf.WriteString(" INCLUDING ")
+		}
+		buf.WriteString(o.Opt.String())
+	}
+}
+
 // InterleaveDef represents an interleave definition within a CREATE TABLE
 // or CREATE INDEX statement.
 type InterleaveDef struct {
@@ -540,25 +1039,118 @@ func (node *InterleaveDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// PartitionBy represents a PARTITION BY definition within a CREATE TABLE or
+// CREATE INDEX statement. Exactly one of List and Range is set.
+type PartitionBy struct {
+	Fields NameList
+	List   []ListPartition
+	Range  []RangePartition
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PartitionBy) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(" PARTITION BY ")
+	if node.List != nil {
+		buf.WriteString("LIST")
+	} else {
+		buf.WriteString("RANGE")
+	}
+	buf.WriteString(" (")
+	FormatNode(buf, f, node.Fields)
+	buf.WriteString(") (")
+	for i := range node.List {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		FormatNode(buf, f, &node.List[i])
+	}
+	for i := range node.Range {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		FormatNode(buf, f, &node.Range[i])
+	}
+	buf.WriteByte(')')
+}
+
+// ListPartition represents a PARTITION definition within a PARTITION BY LIST.
+type ListPartition struct {
+	Name  Name
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ListPartition) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("PARTITION ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" VALUES IN (")
+	FormatNode(buf, f, node.Exprs)
+	buf.WriteByte(')')
+}
+
+// RangePartition represents a PARTITION definition within a PARTITION BY
+// RANGE. The partition holds the rows whose partitioned columns are at least
+// From and less than To.
+type RangePartition struct {
+	Name Name
+	From Exprs
+	To   Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RangePartition) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("PARTITION ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" VALUES FROM (")
+	FormatNode(buf, f, node.From)
+	buf.WriteString(") TO (")
+	FormatNode(buf, f, node.To)
+	buf.WriteByte(')')
+}
+
 // CreateTable represents a CREATE TABLE statement.
 type CreateTable struct {
 	IfNotExists bool
+	Temporary   bool
 	Table       *QualifiedName
 	Interleave  *InterleaveDef
+	PartitionBy *PartitionBy
 	Defs        TableDefs
+	// AsSource is the query of a CREATE TABLE ... AS statement, whose results
+	// are the initial rows of the table, and AsColumnNames the names given to
+	// its columns, if any.
+	AsSource      *Select
+	AsColumnNames NameList
 }
 
 // Format implements the NodeFormatter interface.
 func (node *CreateTable) Format(buf *bytes.Buffer, f FmtFlags) {
-	buf.WriteString("CREATE TABLE ")
+	buf.WriteString("CREATE ")
+	if node.Temporary {
+		buf.WriteString("TEMPORARY ")
+	}
+	buf.WriteString("TABLE ")
 	if node.IfNotExists {
 		buf.WriteString("IF NOT EXISTS ")
 	}
 	FormatNode(buf, f, node.Table)
+	if node.AsSource != nil {
+		if len(node.AsColumnNames) > 0 {
+			buf.WriteString(" (")
+			FormatNode(buf, f, node.AsColumnNames)
+			buf.WriteByte(')')
+		}
+		buf.WriteString(" AS ")
+		FormatNode(buf, f, node.AsSource)
+		return
+	}
 	buf.WriteString(" (")
 	FormatNode(buf, f, node.Defs)
 	buf.WriteByte(')')
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.PartitionBy != nil {
+		FormatNode(buf, f, node.PartitionBy)
+	}
 }
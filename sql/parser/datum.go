@@ -23,8 +23,11 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 	"gopkg.in/inf.v0"
 
 	"github.com/cockroachdb/cockroach/roachpb"
@@ -555,6 +558,154 @@ func (d *DString) Format(buf *bytes.Buffer, f FmtFlags) {
 	encodeSQLString(buf, string(*d))
 }
 
+// DCollatedString is the Datum for strings with a locale. The strings are
+// ordered by the collation key of their contents in the locale, and by their
+// contents if the keys are equal, so that only identical strings are equal.
+type DCollatedString struct {
+	Contents string
+	Locale   string
+	// Key is the collation key of the contents.
+	Key []byte
+}
+
+// collatorPools holds a pool of collators for each locale of collated
+// strings. As collators can't be used concurrently, each computation of a key
+// takes a collator, along with its buffer, from the pool of the locale.
+var collatorPools struct {
+	sync.RWMutex
+	byLocale map[string]*sync.Pool
+}
+
+// collatorWithBuf is a collator along with the buffer its keys are computed
+// in.
+type collatorWithBuf struct {
+	c   *collate.Collator
+	buf collate.Buffer
+}
+
+// getCollatorPool returns the pool of collators of a locale.
+func getCollatorPool(locale string) *sync.Pool {
+	collatorPools.RLock()
+	pool, ok := collatorPools.byLocale[locale]
+	collatorPools.RUnlock()
+	if ok {
+		return pool
+	}
+	tag := language.Und
+	if locale != "" {
+		var err error
+		if tag, err = language.Parse(locale); err != nil {
+			panic(fmt.Sprintf("invalid locale %s: %v", locale, err))
+		}
+	}
+	collatorPools.Lock()
+	defer collatorPools.Unlock()
+	if pool, ok := collatorPools.byLocale[locale]; ok {
+		return pool
+	}
+	pool = &sync.Pool{
+		New: func() interface{} {
+			return &collatorWithBuf{c: collate.New(tag)}
+		},
+	}
+	if collatorPools.byLocale == nil {
+		collatorPools.byLocale = make(map[string]*sync.Pool)
+	}
+	collatorPools.byLocale[locale] = pool
+	return pool
+}
+
+// NewDCollatedString is a helper routine to create a *DCollatedString. The
+// locale must have been checked with CheckCollationLocale; the empty locale,
+// used when only the kind of a column is known, uses the root collation.
+func NewDCollatedString(contents, locale string) *DCollatedString {
+	pool := getCollatorPool(locale)
+	cb := pool.Get().(*collatorWithBuf)
+	key := append([]byte(nil), cb.c.KeyFromString(&cb.buf, contents)...)
+	cb.buf.Reset()
+	pool.Put(cb)
+	return &DCollatedString{Contents: contents, Locale: locale, Key: key}
+}
+
+// CheckCollationLocale returns an error if the locale of a COLLATE clause is
+// not a valid language tag.
+func CheckCollationLocale(locale string) error {
+	if _, err := language.Parse(locale); err != nil {
+		return fmt.Errorf("invalid locale %s: %v", locale, err)
+	}
+	return nil
+}
+
+// ReturnType implements the TypedExpr interface.
+func (d *DCollatedString) ReturnType() Datum {
+	return &DCollatedString{Locale: d.Locale}
+}
+
+// Type implements the Datum interface.
+func (d *DCollatedString) Type() string {
+	return fmt.Sprintf("collatedstring{%s}", d.Locale)
+}
+
+// TypeEqual implements the Datum interface. TypeCollatedString, whose locale
+// is empty, is equal to the collated string types of all the locales.
+func (d *DCollatedString) TypeEqual(other Datum) bool {
+	v, ok := other.(*DCollatedString)
+	return ok && (d.Locale == v.Locale || d.Locale == "" || v.Locale == "")
+}
+
+// Compare implements the Datum interface.
+func (d *DCollatedString) Compare(other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DCollatedString)
+	if !ok || !d.TypeEqual(v) {
+		panic(fmt.Sprintf("unsupported comparison: %s to %s", d.Type(), other.Type()))
+	}
+	if c := bytes.Compare(d.Key, v.Key); c != 0 {
+		return c
+	}
+	return strings.Compare(d.Contents, v.Contents)
+}
+
+// HasPrev implements the Datum interface.
+func (*DCollatedString) HasPrev() bool {
+	return false
+}
+
+// Prev implements the Datum interface.
+func (d *DCollatedString) Prev() Datum {
+	panic(d.Type() + ".Prev() not supported")
+}
+
+// HasNext implements the Datum interface.
+func (*DCollatedString) HasNext() bool {
+	return false
+}
+
+// Next implements the Datum interface.
+func (d *DCollatedString) Next() Datum {
+	panic(d.Type() + ".Next() not supported")
+}
+
+// IsMax implements the Datum interface.
+func (*DCollatedString) IsMax() bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DCollatedString) IsMin() bool {
+	return len(d.Contents) == 0
+}
+
+// Format implements the NodeFormatter interface.
+func (d *DCollatedString) Format(buf *bytes.Buffer, f FmtFlags) {
+	encodeSQLString(buf, d.Contents)
+	buf.WriteString(" COLLATE ")
+	FormatNode(buf, f, Name(d.Locale))
+}
+
 // DBytes is the bytes Datum. The underlying type is a string because we want
 // the immutability, but this may contain arbitrary bytes.
 type DBytes string
@@ -980,7 +1131,7 @@ type DInterval struct {
 // string, or an error if parsing is unsuccessful.
 func ParseDInterval(s string) (*DInterval, error) {
 	// At this time the only supported interval formats are:
-	// - Postgres compatible.
+	// - Postgres compatible, including the output of Postgres.
 	// - iso8601 format (with designators only), see interval.go for
 	//   sources of documentation.
 	// - Golang time.parseDuration compatible.
@@ -998,9 +1149,9 @@ func ParseDInterval(s string) (*DInterval, error) {
 			return nil, makeParseError(s, TypeInterval.Type(), err)
 		}
 		return &DInterval{Duration: dur}, nil
-	} else if strings.ContainsRune(s, ' ') {
-		// If it has a space, then we're most likely a postgres string,
-		// as neither iso8601 nor golang permit spaces.
+	} else if strings.ContainsAny(s, " :") {
+		// If it has a space or a colon, then we're most likely a postgres
+		// string, as neither iso8601 nor golang permit them.
 		dur, err := postgresToDuration(s)
 		if err != nil {
 			return nil, makeParseError(s, TypeInterval.Type(), err)
@@ -46,8 +46,9 @@ func (d DropBehavior) String() string {
 
 // DropDatabase represents a DROP DATABASE statement.
 type DropDatabase struct {
-	Name     Name
-	IfExists bool
+	Name         Name
+	IfExists     bool
+	DropBehavior DropBehavior
 }
 
 // Format implements the NodeFormatter interface.
@@ -57,6 +58,10 @@ func (node *DropDatabase) Format(buf *bytes.Buffer, f FmtFlags) {
 		buf.WriteString("IF EXISTS ")
 	}
 	FormatNode(buf, f, node.Name)
+	if node.DropBehavior != DropDefault {
+		buf.WriteByte(' ')
+		buf.WriteString(node.DropBehavior.String())
+	}
 }
 
 // DropIndex represents a DROP INDEX statement.
@@ -79,6 +84,42 @@ func (node *DropIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	Name     Name
+	Table    *QualifiedName
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.Table)
+}
+
+// DropPolicy represents a DROP POLICY statement.
+type DropPolicy struct {
+	Name     Name
+	Table    *QualifiedName
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPolicy) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP POLICY ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.Table)
+}
+
 // DropTable represents a DROP TABLE statement.
 type DropTable struct {
 	Names        QualifiedNames
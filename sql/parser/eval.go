@@ -703,6 +703,13 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 				return DBool(*left.(*DString) == *right.(*DString)), nil
 			},
 		},
+		CmpOp{
+			LeftType:  TypeCollatedString,
+			RightType: TypeCollatedString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (DBool, error) {
+				return DBool(left.Compare(right) == 0), nil
+			},
+		},
 		CmpOp{
 			LeftType:  TypeBytes,
 			RightType: TypeBytes,
@@ -837,6 +844,13 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 				return DBool(*left.(*DString) < *right.(*DString)), nil
 			},
 		},
+		CmpOp{
+			LeftType:  TypeCollatedString,
+			RightType: TypeCollatedString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (DBool, error) {
+				return DBool(left.Compare(right) < 0), nil
+			},
+		},
 		CmpOp{
 			LeftType:  TypeBytes,
 			RightType: TypeBytes,
@@ -970,6 +984,13 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 				return DBool(*left.(*DString) <= *right.(*DString)), nil
 			},
 		},
+		CmpOp{
+			LeftType:  TypeCollatedString,
+			RightType: TypeCollatedString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (DBool, error) {
+				return DBool(left.Compare(right) <= 0), nil
+			},
+		},
 		CmpOp{
 			LeftType:  TypeBytes,
 			RightType: TypeBytes,
@@ -1101,6 +1122,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(TypeFloat),
 		makeEvalTupleIn(TypeDecimal),
 		makeEvalTupleIn(TypeString),
+		makeEvalTupleIn(TypeCollatedString),
 		makeEvalTupleIn(TypeBytes),
 		makeEvalTupleIn(TypeDate),
 		makeEvalTupleIn(TypeTimestamp),
@@ -1240,6 +1262,38 @@ type EvalContext struct {
 	ReCache *RegexpCache
 	tmpDec  inf.Dec
 
+	// Comments looks up the comments set by COMMENT ON for obj_description()
+	// and col_description(). It is nil outside of SQL statements.
+	Comments CommentLookup
+
+	// Retrier forces the retry of the current transaction for
+	// crdb_internal.force_retry(). It is nil outside of SQL statements.
+	Retrier TxnRetrier
+
+	// Sizes estimates the disk usage of tables and indexes for
+	// crdb_internal.table_size() and crdb_internal.index_size(). It is nil
+	// outside of SQL statements.
+	Sizes SizeEstimator
+
+	// Keys encodes and decodes the keys of tables and indexes for
+	// crdb_internal.encode_key() and crdb_internal.decode_key(). It is nil
+	// outside of SQL statements.
+	Keys KeyCodec
+
+	// Profiler collects the profiles of the node for
+	// crdb_internal.cpu_profile() and crdb_internal.heap_profile(). It is nil
+	// outside of SQL statements.
+	Profiler Profiler
+
+	// Sequences increments the sequences backing SERIAL columns for
+	// crdb_internal.serial_nextval(). It is nil outside of SQL statements and
+	// schema changes.
+	Sequences SequenceGenerator
+
+	// Userfiles reads the files uploaded with `cockroach userfile upload` for
+	// crdb_internal.userfile(). It is nil outside of SQL statements.
+	Userfiles UserfileReader
+
 	// TODO(mjibson): remove prepareOnly in favor of a 2-step prepare-exec solution
 	// that is also able to save the plan to skip work during the exec step.
 	PrepareOnly bool
@@ -1250,6 +1304,67 @@ type EvalContext struct {
 	SkipNormalize bool
 }
 
+// CommentLookup retrieves the comments on schema objects.
+type CommentLookup interface {
+	// ObjectComment returns the comment on the database or table with the
+	// given descriptor ID, or DNull if there is none.
+	ObjectComment(id int64) (Datum, error)
+	// ColumnComment returns the comment on the column with the given ID of
+	// the table with the given descriptor ID, or DNull if there is none.
+	ColumnComment(tableID, columnID int64) (Datum, error)
+}
+
+// TxnRetrier forces the retry of the current transaction.
+type TxnRetrier interface {
+	// ForceRetry returns a retryable error for the current transaction, which
+	// is prepared for its next attempt.
+	ForceRetry(reason string) error
+}
+
+// SizeEstimator estimates the disk usage of tables and indexes.
+type SizeEstimator interface {
+	// TableSize returns the approximate number of bytes stored by all the
+	// replicas of the data of the named table, including its indexes.
+	TableSize(table string) (Datum, error)
+	// IndexSize returns the approximate number of bytes stored by all the
+	// replicas of the named index of the named table.
+	IndexSize(table, index string) (Datum, error)
+}
+
+// KeyCodec encodes and decodes the keys of the data of tables.
+type KeyCodec interface {
+	// EncodeKey returns the key prefix of the entries of the named index of
+	// the named table whose columns have the given values, which are a
+	// comma-separated list of expressions.
+	EncodeKey(table, index, values string) (Datum, error)
+	// DecodeKey returns the name of the index of the named table to which a
+	// key belongs and the values of the columns encoded in it.
+	DecodeKey(table string, key []byte) (Datum, error)
+}
+
+// Profiler collects the profiles of the node executing a statement.
+type Profiler interface {
+	// CPUProfile returns the CPU profile of the node collected during the
+	// given duration.
+	CPUProfile(d time.Duration) (Datum, error)
+	// HeapProfile returns the profile of the memory allocations of the node.
+	HeapProfile() (Datum, error)
+}
+
+// SequenceGenerator increments the sequences backing SERIAL columns.
+type SequenceGenerator interface {
+	// NextVal increments the sequence with the given ID and returns its new
+	// value.
+	NextVal(id int64) (Datum, error)
+}
+
+// UserfileReader reads the files uploaded by the users into the cluster.
+type UserfileReader interface {
+	// ReadUserfile returns the contents of the file named by a userfile://
+	// URI, among the files uploaded by the user executing the statement.
+	ReadUserfile(uri string) (Datum, error)
+}
+
 // GetStmtTimestamp retrieves the current statement timestamp as per
 // the evaluation context. The timestamp is guaranteed to be nonzero.
 func (ctx *EvalContext) GetStmtTimestamp() *DTimestamp {
@@ -1431,7 +1546,7 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		return d, nil
 	}
 
-	switch expr.Type.(type) {
+	switch typ := expr.Type.(type) {
 	case *BoolColType:
 		switch v := d.(type) {
 		case *DBool:
@@ -1523,6 +1638,8 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			s = DString(d.String())
 		case *DString:
 			s = *t
+		case *DCollatedString:
+			s = DString(t.Contents)
 		case *DBytes:
 			if !utf8.ValidString(string(*t)) {
 				return nil, fmt.Errorf("invalid utf8: %q", string(*t))
@@ -1538,6 +1655,14 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		}
 		return &s, nil
 
+	case *CollatedStringColType:
+		switch t := d.(type) {
+		case *DString:
+			return NewDCollatedString(string(*t), typ.Locale), nil
+		case *DCollatedString:
+			return NewDCollatedString(t.Contents, typ.Locale), nil
+		}
+
 	case *BytesColType:
 		switch t := d.(type) {
 		case *DString:
@@ -1616,6 +1741,21 @@ func (expr *CoalesceExpr) Eval(ctx *EvalContext) (Datum, error) {
 	return DNull, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (expr *CollateExpr) Eval(ctx *EvalContext) (Datum, error) {
+	d, err := expr.Expr.(TypedExpr).Eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch t := d.(type) {
+	case *DString:
+		return NewDCollatedString(string(*t), expr.Locale), nil
+	case *DCollatedString:
+		return NewDCollatedString(t.Contents, expr.Locale), nil
+	}
+	return DNull, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (expr *ComparisonExpr) Eval(ctx *EvalContext) (Datum, error) {
 	left, err := expr.Left.(TypedExpr).Eval(ctx)
@@ -1923,6 +2063,11 @@ func (t *DBytes) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DCollatedString) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DDate) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -446,6 +446,13 @@ func TestEval(t *testing.T) {
 		{`'12h2m1s23ms'::interval`, `12h2m1.023s`},
 		{`1::interval`, `1ns`},
 		{`(1::interval)::interval`, `1ns`},
+		{`'1 year 2 mons 3 days 04:00:00'::interval`, `14m3d4h0m0s`},
+		{`'-1 days +02:30:00.5'::interval`, `0m-1d2h30m0.5s`},
+		{`'04:05:06'::interval`, `4h5m6s`},
+		{`'2 hrs 30 MINS'::interval`, `2h30m0s`},
+		{`'P1Y2M3DT4H'::interval`, `14m3d4h0m0s`},
+		{`'PT-1H-30M'::interval`, `-1h30m0s`},
+		{`'PT1.5S'::interval`, `1.5s`},
 		{`'2010-09-28'::date + 3`, `2010-10-01`},
 		{`3 + '2010-09-28'::date`, `2010-10-01`},
 		{`'2010-09-28'::date - 3`, `2010-09-25`},
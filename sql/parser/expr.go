@@ -1202,7 +1202,8 @@ var (
 	intCastTypes       = []Datum{DNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString}
 	floatCastTypes     = []Datum{DNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString}
 	decimalCastTypes   = []Datum{DNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString}
-	stringCastTypes    = []Datum{DNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ}
+	stringCastTypes    = []Datum{DNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString, TypeBytes, TypeTimestamp, TypeTimestampTZ}
+	collatedCastTypes  = []Datum{DNull, TypeString, TypeCollatedString}
 	bytesCastTypes     = []Datum{DNull, TypeString, TypeBytes}
 	dateCastTypes      = []Datum{DNull, TypeString, TypeDate, TypeTimestamp}
 	timestampCastTypes = []Datum{DNull, TypeString, TypeDate, TypeTimestamp, TypeTimestampTZ}
@@ -1210,7 +1211,7 @@ var (
 )
 
 func colTypeToTypeAndValidArgTypes(t ColumnType) (Datum, []Datum) {
-	switch t.(type) {
+	switch t := t.(type) {
 	case *BoolColType:
 		return TypeBool, boolCastTypes
 	case *IntColType:
@@ -1221,6 +1222,8 @@ func colTypeToTypeAndValidArgTypes(t ColumnType) (Datum, []Datum) {
 		return TypeDecimal, decimalCastTypes
 	case *StringColType:
 		return TypeString, stringCastTypes
+	case *CollatedStringColType:
+		return &DCollatedString{Locale: t.Locale}, collatedCastTypes
 	case *BytesColType:
 		return TypeBytes, bytesCastTypes
 	case *DateColType:
@@ -1239,6 +1242,21 @@ func (node *CastExpr) castTypeAndValidArgTypes() (Datum, []Datum) {
 	return colTypeToTypeAndValidArgTypes(node.Type)
 }
 
+// CollateExpr represents an (expr COLLATE locale) expression.
+type CollateExpr struct {
+	Expr   Expr
+	Locale string
+
+	typeAnnotation
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CollateExpr) Format(buf *bytes.Buffer, f FmtFlags) {
+	exprFmtWithParen(buf, f, node.Expr)
+	buf.WriteString(" COLLATE ")
+	FormatNode(buf, f, Name(node.Locale))
+}
+
 // AnnotateTypeExpr represents a ANNOTATE_TYPE(expr, type) expression.
 type AnnotateTypeExpr struct {
 	Expr Expr
@@ -1275,9 +1293,11 @@ func (node *BinaryExpr) String() string       { return AsString(node) }
 func (node *CaseExpr) String() string         { return AsString(node) }
 func (node *CastExpr) String() string         { return AsString(node) }
 func (node *CoalesceExpr) String() string     { return AsString(node) }
+func (node *CollateExpr) String() string      { return AsString(node) }
 func (node *ComparisonExpr) String() string   { return AsString(node) }
 func (node *DBool) String() string            { return AsString(node) }
 func (node *DBytes) String() string           { return AsString(node) }
+func (node *DCollatedString) String() string  { return AsString(node) }
 func (node *DDate) String() string            { return AsString(node) }
 func (node *DDecimal) String() string         { return AsString(node) }
 func (node *DFloat) String() string           { return AsString(node) }
@@ -24,6 +24,7 @@ import (
 type fmtFlags struct {
 	showTypes        bool
 	showTableAliases bool
+	hideConstants    bool
 }
 
 // FmtFlags enables conditional formatting in the pretty-printer.
@@ -42,6 +43,11 @@ var FmtQualify FmtFlags = &fmtFlags{showTableAliases: true}
 // annotate expressions with their resolved types.
 var FmtShowTypes FmtFlags = &fmtFlags{showTypes: true}
 
+// FmtHideConstants instructs the pretty-printer to print '_' in place of the
+// constants, so that statements which only differ by their constants are
+// formatted identically.
+var FmtHideConstants FmtFlags = &fmtFlags{hideConstants: true}
+
 // NodeFormatter is implemented by nodes that can be pretty-printed.
 type NodeFormatter interface {
 	// Format performs pretty-printing towards a bytes buffer. The
@@ -52,6 +58,13 @@ type NodeFormatter interface {
 // FormatNode recurses into a node for pretty-printing.
 // Flag-driven special cases can hook into this.
 func FormatNode(buf *bytes.Buffer, f FmtFlags, n NodeFormatter) {
+	if f.hideConstants {
+		switch n.(type) {
+		case Constant, Datum:
+			buf.WriteByte('_')
+			return
+		}
+	}
 	if f.showTypes {
 		if te, ok := n.(TypedExpr); ok {
 			buf.WriteByte('(')
@@ -17,12 +17,13 @@
 package parser
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/util/duration"
-
-	"fmt"
 )
 
 type intervalLexer struct {
@@ -56,7 +57,7 @@ func (l *intervalLexer) consumeInt() int64 {
 		}
 	}
 	if offset == l.offset {
-		l.err = fmt.Errorf("interval: missing int at offset %d, %v", offset, l.str[offset])
+		l.err = fmt.Errorf("interval: missing int at offset %d: %q", offset, l.str[offset:])
 		return 0
 	}
 	return x
@@ -70,13 +71,14 @@ func (l *intervalLexer) consumeUnit(skipCharacter byte) string {
 
 	offset := l.offset
 	for ; l.offset < len(l.str); l.offset++ {
-		if (l.str[l.offset] >= '0' && l.str[l.offset] <= '9') || l.str[l.offset] == skipCharacter {
+		if (l.str[l.offset] >= '0' && l.str[l.offset] <= '9') || l.str[l.offset] == skipCharacter ||
+			l.str[l.offset] == '-' || l.str[l.offset] == '+' {
 			break
 		}
 	}
 
 	if offset == l.offset {
-		l.err = fmt.Errorf("interval: missing unit at offset %d, %v", offset, l.str[offset])
+		l.err = fmt.Errorf("interval: missing unit at offset %d: %q", offset, l.str[offset:])
 		return ""
 	}
 	return l.str[offset:l.offset]
@@ -91,6 +93,39 @@ func (l *intervalLexer) consumeSpaces() {
 	}
 }
 
+// Consumes an optional sign, and returns whether it is negative.
+func (l *intervalLexer) consumeSign() bool {
+	if l.err != nil || l.offset == len(l.str) {
+		return false
+	}
+	switch l.str[l.offset] {
+	case '-':
+		l.offset++
+		return true
+	case '+':
+		l.offset++
+	}
+	return false
+}
+
+// Consumes an optional fractional part of a second (including the leading
+// '.'), and returns it as a number of nanoseconds.
+func (l *intervalLexer) consumeFraction() int64 {
+	if l.err != nil || l.offset == len(l.str) || l.str[l.offset] != '.' {
+		return 0
+	}
+	l.offset++
+	var nanos int64
+	scale := time.Second.Nanoseconds()
+	for ; l.offset < len(l.str) && l.str[l.offset] >= '0' && l.str[l.offset] <= '9'; l.offset++ {
+		// Digits beyond the precision of nanoseconds are ignored.
+		if scale /= 10; scale > 0 {
+			nanos += int64(l.str[l.offset]-'0') * scale
+		}
+	}
+	return nanos
+}
+
 // ISO Units.
 var isoDateUnitMap = map[string]duration.Duration{
 	"D": {Days: 1},
@@ -127,14 +162,27 @@ func iso8601ToDuration(s string) (duration.Duration, error) {
 			l.offset++
 		}
 
+		neg := l.consumeSign()
 		v := l.consumeInt()
+		var fraction int64
+		if unitMap == isoTimeUnitMap {
+			// Only the seconds can have a fractional part.
+			fraction = l.consumeFraction()
+		}
 		u := l.consumeUnit('T')
 		if l.err != nil {
 			return d, l.err
 		}
+		if fraction != 0 && u != "S" {
+			return d, fmt.Errorf("interval: unexpected fraction for unit %s in iso8601 duration %s", u, s)
+		}
 
 		if unit, ok := unitMap[u]; ok {
-			d = d.Add(unit.Mul(v))
+			part := unit.Mul(v).Add(duration.Duration{Nanos: fraction})
+			if neg {
+				part = part.Mul(-1)
+			}
+			d = d.Add(part)
 		} else {
 			return d, fmt.Errorf("interval: unknown unit %s in iso8601 duration %s", u, s)
 		}
@@ -159,18 +207,65 @@ var postgresUnitMap = map[string]duration.Duration{
 	"months":  {Months: 1},
 	"year":    {Months: 12},
 	"years":   {Months: 12},
+
+	// Abbreviations.
+	"us":           {Nanos: time.Microsecond.Nanoseconds()},
+	"microsecond":  {Nanos: time.Microsecond.Nanoseconds()},
+	"microseconds": {Nanos: time.Microsecond.Nanoseconds()},
+	"ms":           {Nanos: time.Millisecond.Nanoseconds()},
+	"millisecond":  {Nanos: time.Millisecond.Nanoseconds()},
+	"milliseconds": {Nanos: time.Millisecond.Nanoseconds()},
+	"sec":          {Nanos: time.Second.Nanoseconds()},
+	"secs":         {Nanos: time.Second.Nanoseconds()},
+	"min":          {Nanos: time.Minute.Nanoseconds()},
+	"mins":         {Nanos: time.Minute.Nanoseconds()},
+	"hr":           {Nanos: time.Hour.Nanoseconds()},
+	"hrs":          {Nanos: time.Hour.Nanoseconds()},
+	"mon":          {Months: 1},
+	"mons":         {Months: 1},
+	"yr":           {Months: 12},
+	"yrs":          {Months: 12},
 }
 
-// Parses a duration in the "traditional" Postgres format.
+// Parses a duration in the "traditional" Postgres format, such as "1 year 2
+// mons -3 days 04:05:06.7", which is also the format of the intervals output
+// by Postgres.
 func postgresToDuration(s string) (duration.Duration, error) {
 	var d duration.Duration
 	l := intervalLexer{str: s, offset: 0, err: nil}
+	l.consumeSpaces()
 	for l.offset != len(l.str) {
+		neg := l.consumeSign()
 		v := l.consumeInt()
+		if l.err == nil && l.offset < len(l.str) && l.str[l.offset] == ':' {
+			// A time of the form [-]hh:mm[:ss[.f]].
+			l.offset++
+			nanos := v*time.Hour.Nanoseconds() + l.consumeInt()*time.Minute.Nanoseconds()
+			if l.err == nil && l.offset < len(l.str) && l.str[l.offset] == ':' {
+				l.offset++
+				nanos += l.consumeInt() * time.Second.Nanoseconds()
+				nanos += l.consumeFraction()
+			}
+			if l.err != nil {
+				return d, l.err
+			}
+			if neg {
+				nanos = -nanos
+			}
+			d = d.Add(duration.Duration{Nanos: nanos})
+			l.consumeSpaces()
+			continue
+		}
+		if neg {
+			v = -v
+		}
 		l.consumeSpaces()
 		u := l.consumeUnit(' ')
 		l.consumeSpaces()
-		if unit, ok := postgresUnitMap[u]; ok {
+		if l.err != nil {
+			return d, l.err
+		}
+		if unit, ok := postgresUnitMap[strings.ToLower(u)]; ok {
 			d = d.Add(unit.Mul(v))
 		} else {
 			return d, fmt.Errorf("interval: unknown unit %s in postgres duration %s", u, s)
@@ -179,3 +274,158 @@ func postgresToDuration(s string) (duration.Duration, error) {
 
 	return d, nil
 }
+
+// IntervalStyle is an output format of intervals, which can be chosen with
+// the IntervalStyle session variable.
+type IntervalStyle int
+
+const (
+	// IntervalStyleDefault formats intervals like Go durations, such as
+	// "14m3d4h0m0s".
+	IntervalStyleDefault IntervalStyle = iota
+	// IntervalStylePostgres formats intervals like Postgres does by default,
+	// such as "1 year 2 mons 3 days 04:00:00".
+	IntervalStylePostgres
+	// IntervalStyleISO8601 formats intervals as ISO 8601 durations with
+	// designators, such as "P1Y2M3DT4H".
+	IntervalStyleISO8601
+)
+
+var intervalStyleNames = [...]string{
+	IntervalStyleDefault:  "default",
+	IntervalStylePostgres: "postgres",
+	IntervalStyleISO8601:  "iso_8601",
+}
+
+func (s IntervalStyle) String() string {
+	if s < 0 || int(s) >= len(intervalStyleNames) {
+		return fmt.Sprintf("IntervalStyle(%d)", s)
+	}
+	return intervalStyleNames[s]
+}
+
+// ParseIntervalStyle parses the name of an interval style.
+func ParseIntervalStyle(s string) (IntervalStyle, bool) {
+	s = strings.ToLower(s)
+	for i, name := range intervalStyleNames {
+		if name == s {
+			return IntervalStyle(i), true
+		}
+	}
+	return 0, false
+}
+
+// FormatInterval returns the representation of an interval in the given
+// style. All of them can be parsed by ParseDInterval.
+func FormatInterval(d duration.Duration, style IntervalStyle) string {
+	switch style {
+	case IntervalStylePostgres:
+		return durationToPostgres(d)
+	case IntervalStyleISO8601:
+		return durationToISO8601(d)
+	default:
+		return (&DInterval{Duration: d}).String()
+	}
+}
+
+// splitNanos splits the absolute value of a number of nanoseconds into hours,
+// minutes, seconds and nanoseconds.
+func splitNanos(nanos int64) (hours, minutes, seconds, fraction int64) {
+	if nanos < 0 {
+		nanos = -nanos
+	}
+	hours = nanos / time.Hour.Nanoseconds()
+	nanos %= time.Hour.Nanoseconds()
+	minutes = nanos / time.Minute.Nanoseconds()
+	nanos %= time.Minute.Nanoseconds()
+	return hours, minutes, nanos / time.Second.Nanoseconds(), nanos % time.Second.Nanoseconds()
+}
+
+// writeFraction writes a fractional part of a second, without its trailing
+// zeros.
+func writeFraction(buf *bytes.Buffer, fraction int64) {
+	if fraction != 0 {
+		fmt.Fprintf(buf, ".%s", strings.TrimRight(fmt.Sprintf("%09d", fraction), "0"))
+	}
+}
+
+// durationToPostgres formats a duration like Postgres does with its default
+// "postgres" IntervalStyle. A '+' is written before the positive fields
+// following a negative one.
+func durationToPostgres(d duration.Duration) string {
+	var buf bytes.Buffer
+	prevNeg := false
+	writeField := func(v int64, unit string) {
+		if v == 0 {
+			return
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		if prevNeg && v > 0 {
+			buf.WriteByte('+')
+		}
+		fmt.Fprintf(&buf, "%d %s", v, unit)
+		if v != 1 {
+			buf.WriteByte('s')
+		}
+		prevNeg = v < 0
+	}
+	writeField(d.Months/12, "year")
+	writeField(d.Months%12, "mon")
+	writeField(d.Days, "day")
+
+	if d.Nanos != 0 || buf.Len() == 0 {
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		if d.Nanos < 0 {
+			buf.WriteByte('-')
+		} else if prevNeg {
+			buf.WriteByte('+')
+		}
+		hours, minutes, seconds, fraction := splitNanos(d.Nanos)
+		fmt.Fprintf(&buf, "%02d:%02d:%02d", hours, minutes, seconds)
+		writeFraction(&buf, fraction)
+	}
+	return buf.String()
+}
+
+// durationToISO8601 formats a duration as an ISO 8601 duration with
+// designators, with a sign for each negative field.
+func durationToISO8601(d duration.Duration) string {
+	if d == (duration.Duration{}) {
+		return "PT0S"
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('P')
+	writeField := func(v int64, designator byte) {
+		if v != 0 {
+			fmt.Fprintf(&buf, "%d%c", v, designator)
+		}
+	}
+	writeField(d.Months/12, 'Y')
+	writeField(d.Months%12, 'M')
+	writeField(d.Days, 'D')
+	if d.Nanos == 0 {
+		return buf.String()
+	}
+
+	buf.WriteByte('T')
+	sign := int64(1)
+	if d.Nanos < 0 {
+		sign = -1
+	}
+	hours, minutes, seconds, fraction := splitNanos(d.Nanos)
+	writeField(sign*hours, 'H')
+	writeField(sign*minutes, 'M')
+	if seconds != 0 || fraction != 0 {
+		if sign < 0 {
+			buf.WriteByte('-')
+		}
+		fmt.Fprintf(&buf, "%d", seconds)
+		writeFraction(&buf, fraction)
+		buf.WriteByte('S')
+	}
+	return buf.String()
+}
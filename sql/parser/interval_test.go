@@ -0,0 +1,74 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/util/duration"
+)
+
+func TestFormatInterval(t *testing.T) {
+	testData := []struct {
+		d        duration.Duration
+		postgres string
+		iso8601  string
+	}{
+		{duration.Duration{}, `00:00:00`, `PT0S`},
+		{duration.Duration{Months: 14, Days: 3, Nanos: (4 * time.Hour).Nanoseconds()},
+			`1 year 2 mons 3 days 04:00:00`, `P1Y2M3DT4H`},
+		{duration.Duration{Months: 1, Days: 1}, `1 mon 1 day`, `P1M1D`},
+		{duration.Duration{Months: -25}, `-2 years -1 mons`, `P-2Y-1M`},
+		{duration.Duration{Days: -1, Nanos: (2*time.Hour + 30*time.Minute + 500*time.Millisecond).Nanoseconds()},
+			`-1 days +02:30:00.5`, `P-1DT2H30M0.5S`},
+		{duration.Duration{Nanos: -(time.Hour + 1250*time.Millisecond).Nanoseconds()},
+			`-01:00:01.25`, `PT-1H-1.25S`},
+		{duration.Duration{Nanos: 1}, `00:00:00.000000001`, `PT0.000000001S`},
+	}
+	for _, d := range testData {
+		for _, f := range []struct {
+			style    IntervalStyle
+			expected string
+		}{
+			{IntervalStylePostgres, d.postgres},
+			{IntervalStyleISO8601, d.iso8601},
+		} {
+			s := FormatInterval(d.d, f.style)
+			if s != f.expected {
+				t.Errorf("%s: expected %s in style %s, but found %s", d.d, f.expected, f.style, s)
+				continue
+			}
+			// The intervals can be parsed back.
+			parsed, err := ParseDInterval(s)
+			if err != nil {
+				t.Errorf("%s: %v", s, err)
+			} else if parsed.Duration != d.d {
+				t.Errorf("%s: expected %s, but found %s", s, d.d, parsed.Duration)
+			}
+		}
+	}
+}
+
+func TestParseIntervalStyle(t *testing.T) {
+	for _, s := range []IntervalStyle{IntervalStyleDefault, IntervalStylePostgres, IntervalStyleISO8601} {
+		if parsed, ok := ParseIntervalStyle(s.String()); !ok || parsed != s {
+			t.Errorf("%s: expected %s, but found %s (%t)", s, s, parsed, ok)
+		}
+	}
+	if _, ok := ParseIntervalStyle("sql_standard"); ok {
+		t.Errorf("expected sql_standard to be unsupported")
+	}
+}
@@ -5,6 +5,7 @@ package parser
 var keywords = map[string]int{
 	"ACTION":            ACTION,
 	"ADD":               ADD,
+	"AFTER":             AFTER,
 	"ALL":               ALL,
 	"ALTER":             ALTER,
 	"ANALYSE":           ANALYSE,
@@ -17,6 +18,7 @@ var keywords = map[string]int{
 	"ASC":               ASC,
 	"ASYMMETRIC":        ASYMMETRIC,
 	"AT":                AT,
+	"BEFORE":            BEFORE,
 	"BEGIN":             BEGIN,
 	"BETWEEN":           BETWEEN,
 	"BIGINT":            BIGINT,
@@ -26,6 +28,7 @@ var keywords = map[string]int{
 	"BOOL":              BOOL,
 	"BOOLEAN":           BOOLEAN,
 	"BOTH":              BOTH,
+	"BUCKET_COUNT":      BUCKET_COUNT,
 	"BY":                BY,
 	"BYTEA":             BYTEA,
 	"BYTES":             BYTES,
@@ -36,18 +39,22 @@ var keywords = map[string]int{
 	"CHARACTER":         CHARACTER,
 	"CHARACTERISTICS":   CHARACTERISTICS,
 	"CHECK":             CHECK,
+	"CLUSTER":           CLUSTER,
 	"COALESCE":          COALESCE,
 	"COLLATE":           COLLATE,
 	"COLLATION":         COLLATION,
 	"COLUMN":            COLUMN,
 	"COLUMNS":           COLUMNS,
+	"COMMENT":           COMMENT,
 	"COMMIT":            COMMIT,
 	"COMMITTED":         COMMITTED,
 	"CONFLICT":          CONFLICT,
+	"CONSISTENCY":       CONSISTENCY,
 	"CONSTRAINT":        CONSTRAINT,
 	"CONSTRAINTS":       CONSTRAINTS,
 	"COVERING":          COVERING,
 	"CREATE":            CREATE,
+	"CREATEDB":          CREATEDB,
 	"CROSS":             CROSS,
 	"CUBE":              CUBE,
 	"CURRENT":           CURRENT,
@@ -67,19 +74,26 @@ var keywords = map[string]int{
 	"DEC":               DEC,
 	"DECIMAL":           DECIMAL,
 	"DEFAULT":           DEFAULT,
+	"DEFAULTS":          DEFAULTS,
 	"DEFERRABLE":        DEFERRABLE,
+	"DEFERRED":          DEFERRED,
 	"DELETE":            DELETE,
 	"DESC":              DESC,
+	"DETAILS":           DETAILS,
 	"DISTINCT":          DISTINCT,
 	"DO":                DO,
 	"DOUBLE":            DOUBLE,
 	"DROP":              DROP,
+	"EACH":              EACH,
 	"ELSE":              ELSE,
 	"ENCODING":          ENCODING,
 	"END":               END,
+	"EVENTS":            EVENTS,
 	"EXCEPT":            EXCEPT,
+	"EXCLUDING":         EXCLUDING,
 	"EXECUTE":           EXECUTE,
 	"EXISTS":            EXISTS,
+	"EXPERIMENTAL":      EXPERIMENTAL,
 	"EXPLAIN":           EXPLAIN,
 	"EXTRACT":           EXTRACT,
 	"FALSE":             FALSE,
@@ -99,13 +113,16 @@ var keywords = map[string]int{
 	"GREATEST":          GREATEST,
 	"GROUP":             GROUP,
 	"GROUPING":          GROUPING,
+	"HASH":              HASH,
 	"HAVING":            HAVING,
 	"HIGH":              HIGH,
 	"HOUR":              HOUR,
 	"IF":                IF,
 	"IFNULL":            IFNULL,
 	"ILIKE":             ILIKE,
+	"IMMEDIATE":         IMMEDIATE,
 	"IN":                IN,
+	"INCLUDING":         INCLUDING,
 	"INDEX":             INDEX,
 	"INDEXES":           INDEXES,
 	"INITIALLY":         INITIALLY,
@@ -118,23 +135,28 @@ var keywords = map[string]int{
 	"INTERSECT":         INTERSECT,
 	"INTERVAL":          INTERVAL,
 	"INTO":              INTO,
+	"INVERTED":          INVERTED,
 	"IS":                IS,
 	"ISOLATION":         ISOLATION,
 	"JOIN":              JOIN,
 	"KEY":               KEY,
 	"KEYS":              KEYS,
 	"LATERAL":           LATERAL,
+	"LC_COLLATE":        LC_COLLATE,
+	"LC_CTYPE":          LC_CTYPE,
 	"LEADING":           LEADING,
 	"LEAST":             LEAST,
 	"LEFT":              LEFT,
 	"LEVEL":             LEVEL,
 	"LIKE":              LIKE,
 	"LIMIT":             LIMIT,
+	"LIST":              LIST,
 	"LOCAL":             LOCAL,
 	"LOCALTIME":         LOCALTIME,
 	"LOCALTIMESTAMP":    LOCALTIMESTAMP,
 	"LOW":               LOW,
 	"MATCH":             MATCH,
+	"MATERIALIZED":      MATERIALIZED,
 	"MINUTE":            MINUTE,
 	"MONTH":             MONTH,
 	"NAME":              NAME,
@@ -142,6 +164,7 @@ var keywords = map[string]int{
 	"NATURAL":           NATURAL,
 	"NEXT":              NEXT,
 	"NO":                NO,
+	"NOCREATEDB":        NOCREATEDB,
 	"NORMAL":            NORMAL,
 	"NOT":               NOT,
 	"NOTHING":           NOTHING,
@@ -155,6 +178,7 @@ var keywords = map[string]int{
 	"OFFSET":            OFFSET,
 	"ON":                ON,
 	"ONLY":              ONLY,
+	"OPTIONS":           OPTIONS,
 	"OR":                OR,
 	"ORDER":             ORDER,
 	"ORDINALITY":        ORDINALITY,
@@ -163,10 +187,13 @@ var keywords = map[string]int{
 	"OVER":              OVER,
 	"OVERLAPS":          OVERLAPS,
 	"OVERLAY":           OVERLAY,
+	"OWNER":             OWNER,
 	"PARENT":            PARENT,
 	"PARTIAL":           PARTIAL,
 	"PARTITION":         PARTITION,
+	"PASSWORD":          PASSWORD,
 	"PLACING":           PLACING,
+	"POLICY":            POLICY,
 	"POSITION":          POSITION,
 	"PRECEDING":         PRECEDING,
 	"PRECISION":         PRECISION,
@@ -179,6 +206,7 @@ var keywords = map[string]int{
 	"RECURSIVE":         RECURSIVE,
 	"REF":               REF,
 	"REFERENCES":        REFERENCES,
+	"REFRESH":           REFRESH,
 	"RELEASE":           RELEASE,
 	"RENAME":            RENAME,
 	"REPEATABLE":        REPEATABLE,
@@ -191,6 +219,8 @@ var keywords = map[string]int{
 	"ROW":               ROW,
 	"ROWS":              ROWS,
 	"SAVEPOINT":         SAVEPOINT,
+	"SCHEMA":            SCHEMA,
+	"SCRUB":             SCRUB,
 	"SEARCH":            SEARCH,
 	"SECOND":            SECOND,
 	"SELECT":            SELECT,
@@ -199,6 +229,7 @@ var keywords = map[string]int{
 	"SESSION":           SESSION,
 	"SESSION_USER":      SESSION_USER,
 	"SET":               SET,
+	"SETTING":           SETTING,
 	"SHOW":              SHOW,
 	"SIMILAR":           SIMILAR,
 	"SIMPLE":            SIMPLE,
@@ -208,6 +239,8 @@ var keywords = map[string]int{
 	"SOME":              SOME,
 	"SQL":               SQL,
 	"START":             START,
+	"STATISTICS":        STATISTICS,
+	"STORED":            STORED,
 	"STORING":           STORING,
 	"STRICT":            STRICT,
 	"STRING":            STRING,
@@ -216,6 +249,9 @@ var keywords = map[string]int{
 	"SYSTEM":            SYSTEM,
 	"TABLE":             TABLE,
 	"TABLES":            TABLES,
+	"TEMP":              TEMP,
+	"TEMPLATE":          TEMPLATE,
+	"TEMPORARY":         TEMPORARY,
 	"TEXT":              TEXT,
 	"THEN":              THEN,
 	"TIME":              TIME,
@@ -225,6 +261,8 @@ var keywords = map[string]int{
 	"TRAILING":          TRAILING,
 	"TRANSACTION":       TRANSACTION,
 	"TREAT":             TREAT,
+	"TRIGGER":           TRIGGER,
+	"TRIGGERS":          TRIGGERS,
 	"TRIM":              TRIM,
 	"TRUE":              TRUE,
 	"TRUNCATE":          TRUNCATE,
@@ -236,6 +274,7 @@ var keywords = map[string]int{
 	"UNKNOWN":           UNKNOWN,
 	"UPDATE":            UPDATE,
 	"UPSERT":            UPSERT,
+	"USAGE":             USAGE,
 	"USER":              USER,
 	"USING":             USING,
 	"VALID":             VALID,
@@ -245,6 +284,8 @@ var keywords = map[string]int{
 	"VARCHAR":           VARCHAR,
 	"VARIADIC":          VARIADIC,
 	"VARYING":           VARYING,
+	"VIEW":              VIEW,
+	"VIRTUAL":           VIRTUAL,
 	"WHEN":              WHEN,
 	"WHERE":             WHERE,
 	"WINDOW":            WINDOW,
@@ -647,6 +647,14 @@ func isVar(expr Expr) bool {
 	return ok
 }
 
+// IsConst returns true if the expression contains no variables and no calls
+// to impure functions, i.e. if it always evaluates to the same value. The
+// expression must have been type checked.
+func IsConst(expr TypedExpr) bool {
+	var v isConstVisitor
+	return v.run(expr)
+}
+
 type containsVarsVisitor struct {
 	containsVars bool
 }
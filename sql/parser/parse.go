@@ -65,6 +65,18 @@ type Parser struct {
 	isAggregateVisitor IsAggregateVisitor
 }
 
+// SyntaxError is returned by Parse when the input is not valid SQL.
+type SyntaxError struct {
+	msg string
+	// Position is the 1-based character position in the input of the token
+	// at which parsing failed.
+	Position int
+}
+
+func (e *SyntaxError) Error() string {
+	return e.msg
+}
+
 // Parse parses the sql and returns a list of statements.
 func (p *Parser) Parse(sql string, syntax Syntax) (stmts StatementList, err error) {
 	defer func() {
@@ -78,7 +90,7 @@ func (p *Parser) Parse(sql string, syntax Syntax) (stmts StatementList, err erro
 	}()
 	p.scanner.init(sql, syntax)
 	if p.parserImpl.Parse(&p.scanner) != 0 {
-		return nil, errors.New(p.scanner.lastError)
+		return nil, &SyntaxError{msg: p.scanner.lastError, Position: p.scanner.lastErrorPos}
 	}
 	return p.scanner.stmts, nil
 }
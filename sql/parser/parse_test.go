@@ -52,17 +52,54 @@ func TestParse(t *testing.T) {
 		{`CREATE DATABASE IF NOT EXISTS a`},
 		{`CREATE DATABASE IF NOT EXISTS a ENCODING='UTF8'`},
 		{`CREATE DATABASE IF NOT EXISTS a ENCODING='INVALID'`},
+		{`CREATE DATABASE a TEMPLATE=template0`},
+		{`CREATE DATABASE a TEMPLATE=template0 OWNER=bob ENCODING='UTF8' LC_COLLATE='C' LC_CTYPE='C'`},
+		{`CREATE DATABASE IF NOT EXISTS a LC_COLLATE='en_US.UTF-8'`},
+
+		{`CREATE USER a`},
+		{`CREATE USER a WITH CREATEDB`},
+		{`ALTER USER a WITH NOCREATEDB`},
+		{`DROP USER a`},
+		{`DROP USER IF EXISTS a, b`},
+
+		{`CREATE POLICY a ON b USING (c = 'd')`},
+		{`CREATE POLICY a ON b.c USING (d > 1 AND e IS NOT NULL)`},
+		{`DROP POLICY a ON b`},
+		{`DROP POLICY IF EXISTS a ON b.c`},
 
 		{`CREATE INDEX a ON b (c)`},
+		{`CREATE TEMPORARY TABLE a (b INT)`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a AS SELECT 1`},
+		{`CREATE TABLE a (LIKE b)`},
+		{`CREATE TABLE a (LIKE b.c INCLUDING ALL EXCLUDING INDEXES, d INT)`},
+		{`CREATE TABLE a (LIKE b INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`},
+		{`CREATE TABLE a AS SELECT * FROM b`},
+		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b WHERE c = 1`},
+		{`CREATE TABLE a.b (c, d) AS VALUES (1, 'x')`},
+		{`CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW a.b (c, d) AS SELECT e, COUNT(*) FROM f GROUP BY e`},
+		{`REFRESH MATERIALIZED VIEW a`},
+		{`REFRESH MATERIALIZED VIEW a.b`},
+		{`CREATE STATISTICS a ON b FROM c`},
+		{`CREATE STATISTICS a ON b, c FROM d.e`},
 		{`CREATE INDEX a ON b.c (d)`},
 		{`CREATE INDEX ON a (b)`},
 		{`CREATE INDEX ON a (b) STORING (c)`},
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c (d)`},
 		{`CREATE INDEX ON a (b ASC, c DESC)`},
+		{`CREATE INDEX ON a (lower(b))`},
+		{`CREATE INDEX ON a ((b + c) DESC, lower(d) ASC)`},
+		{`CREATE UNIQUE INDEX a ON b (lower(c)) STORING (d)`},
 		{`CREATE UNIQUE INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b (c) STORING (d)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
+		{`CREATE INDEX a ON b (c) USING HASH WITH BUCKET_COUNT = 8`},
+		{`CREATE UNIQUE INDEX IF NOT EXISTS a ON b (c, d) USING HASH WITH BUCKET_COUNT = 4 STORING (e)`},
+		{`CREATE UNIQUE INDEX a ON b (c, d) PARTITION BY LIST (c) (PARTITION p1 VALUES IN (1, 2), PARTITION p2 VALUES IN (3))`},
+		{`CREATE INDEX IF NOT EXISTS a ON b (c) PARTITION BY RANGE (c) (PARTITION p1 VALUES FROM (1) TO (10))`},
 		{`CREATE UNIQUE INDEX a ON b.c (d)`},
+		{`CREATE INVERTED INDEX a ON b (c)`},
+		{`CREATE INVERTED INDEX IF NOT EXISTS a ON b.c (d)`},
 
 		{`CREATE TABLE a ()`},
 		{`CREATE TABLE a (b INT)`},
@@ -73,6 +110,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b VARCHAR(3))`},
 		{`CREATE TABLE a (b STRING)`},
 		{`CREATE TABLE a (b STRING(3))`},
+		{`CREATE TABLE a (b STRING COLLATE de)`},
+		{`CREATE TABLE a (b VARCHAR(3) COLLATE "en-US" NOT NULL)`},
 		{`CREATE TABLE a (b FLOAT)`},
 		{`CREATE TABLE a (b SERIAL)`},
 		{`CREATE TABLE a (b SMALLSERIAL)`},
@@ -93,6 +132,10 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (a INT CONSTRAINT one DEFAULT 1 CHECK (a > 0))`},
 		{`CREATE TABLE a (a INT DEFAULT 1 CONSTRAINT positive CHECK (a > 0))`},
 		{`CREATE TABLE a (a INT CONSTRAINT one DEFAULT 1 CONSTRAINT positive CHECK (a > 0))`},
+		{`CREATE TABLE a (a INT, b INT AS (a + 1) VIRTUAL)`},
+		{`CREATE TABLE a (a STRING, b STRING NOT NULL AS (lower(a)) VIRTUAL, INDEX (b))`},
+		{`CREATE TABLE a (a INT, b INT, c INT AS (a + b) STORED)`},
+		{`CREATE TABLE a (a STRING, b STRING AS (lower(a)) STORED FAMILY f, INDEX (b))`},
 		// "0" lost quotes previously.
 		{`CREATE TABLE a (b INT, c TEXT, PRIMARY KEY (b, c, "0"))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b, c))`},
@@ -105,13 +148,32 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo)`},
 		{`CREATE TABLE a (b INT, c INT CONSTRAINT ref REFERENCES foo)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo (bar))`},
+		{`CREATE TABLE a (b INT, FOREIGN KEY (b) REFERENCES foo)`},
+		{`CREATE TABLE a (b INT, CONSTRAINT ref FOREIGN KEY (b) REFERENCES foo (bar))`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON DELETE CASCADE)`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo (bar) ON DELETE SET NULL)`},
+		{`CREATE TABLE a (b INT, FOREIGN KEY (b) REFERENCES foo ON DELETE SET DEFAULT)`},
+		{`CREATE TABLE a (b INT, FOREIGN KEY (b) REFERENCES foo ON DELETE RESTRICT)`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON UPDATE CASCADE)`},
+		{`CREATE TABLE a (b INT, FOREIGN KEY (b) REFERENCES foo ON DELETE SET NULL ON UPDATE SET DEFAULT)`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo DEFERRABLE)`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT, FOREIGN KEY (b) REFERENCES foo DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT, INDEX (b) STORING (c))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b ASC, c DESC) STORING (c))`},
 		{`CREATE TABLE a (b INT, INDEX (b) INTERLEAVE IN PARENT c (d, e))`},
+		{`CREATE TABLE a (b INT, c INT, INDEX d (b) USING HASH WITH BUCKET_COUNT = 16, CONSTRAINT e UNIQUE (c) USING HASH WITH BUCKET_COUNT = 2)`},
+		{`CREATE TABLE a (b INT, INDEX (b) PARTITION BY RANGE (b) (PARTITION c VALUES FROM (1) TO (2)))`},
+		{`CREATE TABLE a (b INT, CONSTRAINT c UNIQUE (b) PARTITION BY LIST (b) (PARTITION d VALUES IN (1)))`},
+		{`CREATE TABLE a (b STRING, INDEX (lower(b)), UNIQUE INDEX c ((b || 'x')))`},
+		{`CREATE TABLE a (b INT, c STRING, INVERTED INDEX (b), INVERTED INDEX d (c))`},
 		{`CREATE TABLE a (b INT, FAMILY (b))`},
 		{`CREATE TABLE a (b INT, c STRING, FAMILY foo (b), FAMILY (c))`},
 		{`CREATE TABLE a (b INT) INTERLEAVE IN PARENT foo (c, d)`},
 		{`CREATE TABLE a (b INT) INTERLEAVE IN PARENT foo (c) CASCADE`},
+		{`CREATE TABLE a (b INT, c STRING, PRIMARY KEY (b, c)) PARTITION BY LIST (b, c) (PARTITION p1 VALUES IN ((1, 'x'), (2, 'y')), PARTITION p2 VALUES IN ((3, 'z')))`},
+		{`CREATE TABLE IF NOT EXISTS a (b INT PRIMARY KEY) PARTITION BY RANGE (b) (PARTITION p1 VALUES FROM (1) TO (10), PARTITION p2 VALUES FROM (10) TO (20))`},
+		{`CREATE TABLE a (b INT, c INT, PRIMARY KEY (b, c)) INTERLEAVE IN PARENT d (b) PARTITION BY RANGE (b, c) (PARTITION p1 VALUES FROM (1, 2) TO (3, 4))`},
 		{`CREATE TABLE a.b (b INT)`},
 		{`CREATE TABLE IF NOT EXISTS a (b INT)`},
 
@@ -124,6 +186,8 @@ func TestParse(t *testing.T) {
 
 		{`DROP DATABASE a`},
 		{`DROP DATABASE IF EXISTS a`},
+		{`DROP DATABASE a CASCADE`},
+		{`DROP DATABASE IF EXISTS a RESTRICT`},
 		{`DROP TABLE a`},
 		{`DROP TABLE a.b`},
 		{`DROP TABLE a, b`},
@@ -153,16 +217,36 @@ func TestParse(t *testing.T) {
 		{`SHOW SYNTAX`},
 
 		{`SHOW DATABASES`},
+		{`SHOW DATABASES WITH COMMENT`},
+		{`SHOW DATABASES WITH DETAILS`},
 		{`SHOW TABLES`},
 		{`SHOW TABLES FROM a`},
 		{`SHOW TABLES FROM a.b.c`},
+		{`SHOW TABLES WITH COMMENT`},
+		{`SHOW TABLES FROM a WITH COMMENT`},
+		{`SHOW TABLES WITH DETAILS`},
+		{`SHOW TABLES FROM a WITH DETAILS`},
 		{`SHOW COLUMNS FROM a`},
 		{`SHOW COLUMNS FROM a.b.c`},
+		{`SHOW COLUMNS FROM a WITH COMMENT`},
 		{`SHOW INDEXES FROM a`},
 		{`SHOW INDEXES FROM a.b.c`},
+		{`SHOW INDEXES FROM a WITH COMMENT`},
+		{`SHOW INDEX USAGE FROM a`},
+		{`SHOW INDEX USAGE FROM a.b.c`},
+		{`SHOW STATISTICS FOR TABLE a`},
+		{`SHOW STATISTICS FOR TABLE a.b`},
 		{`SHOW CONSTRAINTS FROM a`},
+		{`SHOW CONSISTENCY FROM a`},
+		{`SHOW CONSISTENCY FROM a.b`},
 		{`SHOW CONSTRAINTS FROM a.b.c`},
 		{`SHOW TABLES FROM a; SHOW COLUMNS FROM b`},
+		{`SHOW EVENTS`},
+		{`SHOW EVENTS WHERE eventtype = 'create_table'`},
+		{`SHOW EVENTS FOR TABLE a.b`},
+		{`SHOW EVENTS FOR TABLE a WHERE reportingid = 1`},
+		{`SHOW EVENTS FOR DATABASE a`},
+		{`SHOW EVENTS FOR DATABASE a WHERE eventtype = 'drop_table'`},
 
 		// Tables are the default, but can also be specified with
 		// GRANT x ON TABLE y. However, the stringer does not output TABLE.
@@ -308,6 +392,8 @@ func TestParse(t *testing.T) {
 
 		{`SELECT "FROM" FROM t`},
 		{`SELECT CAST(1 AS TEXT)`},
+		{`SELECT a COLLATE de`},
+		{`SELECT 'a' COLLATE "en-US" < (b || c) COLLATE "en-US"`},
 		{`SELECT ANNOTATE_TYPE(1, TEXT)`},
 		{`SELECT a FROM t AS bar`},
 		{`SELECT a FROM t AS bar (bar1)`},
@@ -427,6 +513,9 @@ func TestParse(t *testing.T) {
 		{`SET TIME ZONE -7.3`},
 		{`SET TIME ZONE DEFAULT`},
 		{`SET TIME ZONE LOCAL`},
+		{`SET CLUSTER SETTING sql.cross_db_fks.enabled = true`},
+		{`SET CLUSTER SETTING sql.cross_db_fks.enabled = DEFAULT`},
+		{`SHOW CLUSTER SETTING sql.cross_db_fks.enabled`},
 
 		{`SELECT OVERLAY('w333333rce' PLACING 'resou' FROM 3)`},
 		{`SELECT OVERLAY('w333333rce' PLACING 'resou' FROM 3 FOR 5)`},
@@ -441,6 +530,10 @@ func TestParse(t *testing.T) {
 		{`TRUNCATE TABLE a, b.c`},
 		{`TRUNCATE TABLE a CASCADE`},
 
+		{`EXPERIMENTAL SCRUB TABLE a`},
+		{`EXPERIMENTAL SCRUB TABLE a.b WITH OPTIONS physical`},
+		{`EXPERIMENTAL SCRUB TABLE a WITH OPTIONS logical, physical`},
+
 		{`UPDATE a SET b = 3`},
 		{`UPDATE a.b SET b = 3`},
 		{`UPDATE a SET b.c = 3`},
@@ -461,10 +554,21 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE IF EXISTS a RENAME TO b`},
 		{`ALTER INDEX a@b RENAME TO b`},
 		{`ALTER INDEX IF EXISTS a@b RENAME TO b`},
+
+		{`COMMENT ON DATABASE a IS 'b'`},
+		{`COMMENT ON DATABASE a IS NULL`},
+		{`COMMENT ON TABLE a IS 'b'`},
+		{`COMMENT ON TABLE a.b IS 'c'`},
+		{`COMMENT ON COLUMN a.b IS 'c'`},
+		{`COMMENT ON COLUMN a.b.c IS NULL`},
+		{`COMMENT ON INDEX a@b IS 'c'`},
 		{`ALTER TABLE a RENAME COLUMN c1 TO c2`},
 		{`ALTER TABLE IF EXISTS a RENAME COLUMN c1 TO c2`},
 
 		{`ALTER TABLE a ADD b INT, ADD CONSTRAINT a_idx UNIQUE (a)`},
+		{`ALTER TABLE a ADD CONSTRAINT ref FOREIGN KEY (b) REFERENCES foo (bar)`},
+		{`ALTER TABLE a ADD FOREIGN KEY (b, c) REFERENCES foo (d, e)`},
+		{`ALTER TABLE a ADD FOREIGN KEY (b) REFERENCES foo (d) ON DELETE CASCADE`},
 		{`ALTER TABLE a ADD IF NOT EXISTS b INT, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE IF EXISTS a ADD b INT, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE IF EXISTS a ADD IF NOT EXISTS b INT, ADD CONSTRAINT a_idx UNIQUE (a)`},
@@ -489,12 +593,22 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a DROP COLUMN b RESTRICT`},
 		{`ALTER TABLE a DROP CONSTRAINT b CASCADE`},
 		{`ALTER TABLE a DROP CONSTRAINT IF EXISTS b RESTRICT`},
+		{`ALTER TABLE a VALIDATE CONSTRAINT b`},
+		{`ALTER TABLE a ADD CONSTRAINT c CHECK (b > 0), VALIDATE CONSTRAINT c`},
+		{`ALTER TABLE a ADD CONSTRAINT c CHECK (b > 0) NOT VALID`},
+		{`ALTER TABLE a ADD FOREIGN KEY (b) REFERENCES foo (d) NOT VALID`},
+		{`ALTER TABLE a ADD FOREIGN KEY (b) REFERENCES foo (d) DEFERRABLE NOT VALID`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT 42`},
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT NULL`},
 		{`ALTER TABLE a ALTER COLUMN b DROP DEFAULT`},
 		{`ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER COLUMN b TYPE INT`},
+		{`ALTER TABLE a ALTER b TYPE DECIMAL(10,2)`},
 		{`ALTER TABLE a ALTER b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER INDEX b DROP INTERLEAVE`},
+		{`ALTER TABLE a ALTER PRIMARY KEY USING COLUMNS (b)`},
+		{`ALTER TABLE a ALTER PRIMARY KEY USING COLUMNS (b, c DESC)`},
 	}
 	for _, d := range testData {
 		stmts, err := parseTraditional(d.sql)
@@ -520,6 +634,10 @@ func TestParse2(t *testing.T) {
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b))`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) INTERLEAVE IN PARENT c (d))`,
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b) INTERLEAVE IN PARENT c (d))`},
+		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) USING HASH WITH BUCKET_COUNT = 4)`,
+			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b) USING HASH WITH BUCKET_COUNT = 4)`},
+		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) PARTITION BY RANGE (b) (PARTITION c VALUES FROM (1) TO (2)))`,
+			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b) PARTITION BY RANGE (b) (PARTITION c VALUES FROM (1) TO (2)))`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
 
 		{`SELECT BOOL 'foo'`, `SELECT CAST('foo' AS BOOL)`},
@@ -573,9 +691,18 @@ func TestParse2(t *testing.T) {
 			`SELECT e'\n'`},
 		{"SELECT '\n\\'",
 			`SELECT e'\n\\'`},
+		// Dollar-quoted strings are formatted as regular string literals.
+		{`SELECT $$a'b$$`,
+			`SELECT e'a\'b'`},
+		{`SELECT $body$SELECT 'a'$body$ || $$\n$$`,
+			`SELECT e'SELECT \'a\'' || e'\\n'`},
 		{`SELECT "a'a" FROM t`,
 			`SELECT "a'a" FROM t`},
 		// Hexadecimal literal strings are turned into regular strings.
+		{`CREATE DATABASE a WITH ENCODING 'UTF8'`,
+			`CREATE DATABASE a ENCODING='UTF8'`},
+		{`CREATE DATABASE a WITH TEMPLATE = template0 OWNER = bob ENCODING = 'UTF8' LC_COLLATE = 'C' LC_CTYPE = 'C'`,
+			`CREATE DATABASE a TEMPLATE=template0 OWNER=bob ENCODING='UTF8' LC_COLLATE='C' LC_CTYPE='C'`},
 		{`SELECT x'61'`, `SELECT 'a'`},
 		{`SELECT X'61'`, `SELECT 'a'`},
 		// Comments are stripped.
@@ -650,6 +777,8 @@ func TestParse2(t *testing.T) {
 			`SELECT a FROM t EXCEPT SELECT 1 FROM t`},
 		{`SELECT a FROM t INTERSECT DISTINCT SELECT 1 FROM t`,
 			`SELECT a FROM t INTERSECT SELECT 1 FROM t`},
+		{`SET CLUSTER SETTING a TO 1`,
+			`SET CLUSTER SETTING a = 1`},
 		{`SET TIME ZONE pst8pdt`,
 			`SET TIME ZONE 'pst8pdt'`},
 		{`SET TIME ZONE "Europe/Rome"`,
@@ -672,6 +801,31 @@ func TestParse2(t *testing.T) {
 		// Special position syntax
 		{`SELECT POSITION('ig' in 'high')`,
 			`SELECT STRPOS('high', 'ig')`},
+		{`CREATE TEMP TABLE a (b INT)`, `CREATE TEMPORARY TABLE a (b INT)`},
+		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE STRING`, `ALTER TABLE a ALTER COLUMN b TYPE STRING`},
+		{`CREATE TABLE a (b INT REFERENCES foo ON DELETE NO ACTION)`, `CREATE TABLE a (b INT REFERENCES foo)`},
+		{`CREATE TABLE a (b INT REFERENCES foo ON UPDATE CASCADE ON DELETE RESTRICT)`,
+			`CREATE TABLE a (b INT REFERENCES foo ON DELETE RESTRICT ON UPDATE CASCADE)`},
+		{`CREATE TABLE a (b INT REFERENCES foo INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT REFERENCES foo DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT REFERENCES foo NOT DEFERRABLE INITIALLY IMMEDIATE NOT NULL)`,
+			`CREATE TABLE a (b INT NOT NULL REFERENCES foo)`},
+		{`CREATE TABLE a (b INT, UNIQUE (b) NOT DEFERRABLE)`, `CREATE TABLE a (b INT, UNIQUE (b))`},
+		// The trigger events are formatted in a fixed order.
+		{`CREATE TRIGGER a AFTER DELETE OR INSERT ON b FOR EACH ROW EXECUTE 'SELECT 1'`,
+			`CREATE TRIGGER a AFTER INSERT OR DELETE ON b FOR EACH ROW EXECUTE 'SELECT 1'`},
+		// The passwords are redacted.
+		{`CREATE USER IF NOT EXISTS a WITH PASSWORD 'b'`,
+			`CREATE USER IF NOT EXISTS a WITH PASSWORD '*****'`},
+		{`CREATE USER a PASSWORD 'b'`, `CREATE USER a WITH PASSWORD '*****'`},
+		{`ALTER USER a WITH PASSWORD 'b' NOCREATEDB`, `ALTER USER a WITH PASSWORD '*****' NOCREATEDB`},
+		{`ALTER USER a PASSWORD 'b'`, `ALTER USER a WITH PASSWORD '*****'`},
+		{`ALTER USER a CREATEDB PASSWORD 'b'`, `ALTER USER a WITH PASSWORD '*****' CREATEDB`},
+		// Special AT TIME ZONE syntax
+		{`SELECT a AT TIME ZONE 'UTC'`,
+			`SELECT TIMEZONE('UTC', a)`},
+		{`SELECT a + b AT TIME ZONE c`,
+			`SELECT a + TIMEZONE(c, b)`},
 		// Special trim syntax
 		{`SELECT TRIM('xy' from 'xyxtrimyyx')`,
 			`SELECT BTRIM('xyxtrimyyx', 'xy')`},
@@ -963,6 +1117,30 @@ SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
 	}
 }
 
+func TestParseErrorPosition(t *testing.T) {
+	testData := []struct {
+		sql      string
+		expected int
+	}{
+		{`SELECT * FROM`, 14},
+		{`SELECT * FROM t WHERE k=`, 25},
+		{`SELECT 1; SELECT 2 3`, 20},
+		{"SELECT 1,\nFROM t", 11},
+		{`SELECT 'héllo' 1`, 16},
+		{`SELECT '1`, 8},
+	}
+	for _, d := range testData {
+		_, err := parseTraditional(d.sql)
+		syntaxErr, ok := err.(*SyntaxError)
+		if !ok {
+			t.Fatalf("%s: expected syntax error, but found %v", d.sql, err)
+		}
+		if syntaxErr.Position != d.expected {
+			t.Errorf("%s: expected position %d, but found %d", d.sql, d.expected, syntaxErr.Position)
+		}
+	}
+}
+
 func TestParsePanic(t *testing.T) {
 	// Replicates #1801.
 	defer func() {
@@ -34,16 +34,19 @@ const singleQuote = '\''
 
 // Scanner lexes SQL statements.
 type Scanner struct {
-	in          string
-	pos         int
-	tokBuf      sqlSymType
-	lastTok     sqlSymType
-	nextTok     *sqlSymType
-	lastError   string
-	stmts       []Statement
-	identQuote  int
-	stringQuote int
-	syntax      Syntax
+	in        string
+	pos       int
+	tokBuf    sqlSymType
+	lastTok   sqlSymType
+	nextTok   *sqlSymType
+	lastError string
+	// lastErrorPos is the 1-based character position in the input of the
+	// token that caused lastError.
+	lastErrorPos int
+	stmts        []Statement
+	identQuote   int
+	stringQuote  int
+	syntax       Syntax
 
 	initialized bool
 }
@@ -154,6 +157,7 @@ func (s *Scanner) Error(e string) {
 	fmt.Fprintf(&buf, "%s^\n", strings.Repeat(" ", s.lastTok.pos-j))
 
 	s.lastError = buf.String()
+	s.lastErrorPos = utf8.RuneCountInString(s.in[:s.lastTok.pos]) + 1
 }
 
 func (s *Scanner) scan(lval *sqlSymType) {
@@ -181,6 +185,9 @@ func (s *Scanner) scan(lval *sqlSymType) {
 		if isDigit(s.peek()) {
 			s.scanPlaceholder(lval)
 			return
+		} else if s.scanDollarQuotedString(lval) {
+			// $tag$[^$tag$]*$tag$
+			return
 		} else if s.syntax == Modern {
 			// TODO(pmattis): This should really be prefixed with '@', but that
 			// conflicts with using '@' for index indirection in qualified names.
@@ -776,6 +783,39 @@ outer:
 	return true
 }
 
+// scanDollarQuotedString scans a string delimited by $tag$, where the tag is
+// empty or an identifier which doesn't contain '$'. The contents of the string
+// are taken literally, so they can contain quotes and backslashes without
+// escaping. The leading '$' has already been consumed. If it doesn't start a
+// delimiter, nothing is consumed and false is returned.
+func (s *Scanner) scanDollarQuotedString(lval *sqlSymType) bool {
+	start := s.pos
+	if isIdentStart(s.peek()) {
+		s.pos++
+		for t := s.peek(); isIdentStart(t) || isDigit(t); t = s.peek() {
+			s.pos++
+		}
+	}
+	if s.peek() != '$' {
+		s.pos = start
+		return false
+	}
+	s.pos++
+
+	delim := s.in[start-1 : s.pos]
+	end := strings.Index(s.in[s.pos:], delim)
+	if end == -1 {
+		s.pos = len(s.in)
+		lval.id = ERROR
+		lval.str = errUnterminated
+		return true
+	}
+	lval.id = SCONST
+	lval.str = s.in[s.pos : s.pos+end]
+	s.pos += end + len(delim)
+	return true
+}
+
 func isDigit(ch int) bool {
 	return ch >= '0' && ch <= '9'
 }
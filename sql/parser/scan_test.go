@@ -343,6 +343,14 @@ world'`, `hello
 world`},
 		{`x'666f6f'`, `foo`},
 		{`X'626172'`, `bar`},
+		{`$$a'b\n$$`, `a'b\n`},
+		{`$$$$`, ``},
+		{`$foo$a$$b$ $foo$`, `a$$b$ `},
+		{`$Foo_1$a$foo$b$Foo_1$`, `a$foo$b`},
+		{`$foo$hello
+world$foo$`, `hello
+world`},
+		{`$$a$$ $$b$$`, `a`},
 	}
 	for _, d := range testData {
 		s := MakeScanner(d.sql, Traditional)
@@ -411,6 +419,8 @@ func TestScanError(t *testing.T) {
 		{`X'beef\x41\x41'`, "invalid hexadecimal string literal"},
 		{`x'''1'''`, "invalid hexadecimal string literal"},
 		{`$9223372036854775809`, "integer value out of range"},
+		{`$$a`, "unterminated string"},
+		{`$foo$a$bar$`, "unterminated string"},
 	}
 	for _, d := range testData {
 		s := MakeScanner(d.sql, Traditional)
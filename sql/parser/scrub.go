@@ -0,0 +1,33 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package parser
+
+import "bytes"
+
+// Scrub represents an EXPERIMENTAL SCRUB TABLE statement.
+type Scrub struct {
+	Table   *QualifiedName
+	Options NameList
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Scrub) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("EXPERIMENTAL SCRUB TABLE ")
+	FormatNode(buf, f, node.Table)
+	if len(node.Options) > 0 {
+		buf.WriteString(" WITH OPTIONS ")
+		FormatNode(buf, f, node.Options)
+	}
+}
@@ -42,6 +42,24 @@ func (node *Set) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// SetClusterSetting represents a SET CLUSTER SETTING statement.
+type SetClusterSetting struct {
+	Name  *QualifiedName
+	Value Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *SetClusterSetting) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SET CLUSTER SETTING ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" = ")
+	if node.Value == nil {
+		buf.WriteString("DEFAULT")
+	} else {
+		FormatNode(buf, f, node.Value)
+	}
+}
+
 // SetTransaction represents a SET TRANSACTION statement.
 type SetTransaction struct {
 	Isolation    IsolationLevel
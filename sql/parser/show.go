@@ -35,40 +35,113 @@ func (node *Show) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(node.Name)
 }
 
+// ShowClusterSetting represents a SHOW CLUSTER SETTING statement.
+type ShowClusterSetting struct {
+	Name *QualifiedName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowClusterSetting) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW CLUSTER SETTING ")
+	FormatNode(buf, f, node.Name)
+}
+
 // ShowColumns represents a SHOW COLUMNS statement.
 type ShowColumns struct {
-	Table *QualifiedName
+	Table       *QualifiedName
+	WithComment bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *ShowColumns) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("SHOW COLUMNS FROM ")
 	FormatNode(buf, f, node.Table)
+	if node.WithComment {
+		buf.WriteString(" WITH COMMENT")
+	}
 }
 
 // ShowDatabases represents a SHOW DATABASES statement.
 type ShowDatabases struct {
+	WithComment bool
+	WithDetails bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *ShowDatabases) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("SHOW DATABASES")
+	if node.WithComment {
+		buf.WriteString(" WITH COMMENT")
+	}
+	if node.WithDetails {
+		buf.WriteString(" WITH DETAILS")
+	}
 }
 
 // ShowIndex represents a SHOW INDEX statement.
 type ShowIndex struct {
-	Table *QualifiedName
+	Table       *QualifiedName
+	WithComment bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *ShowIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("SHOW INDEXES FROM ")
 	FormatNode(buf, f, node.Table)
+	if node.WithComment {
+		buf.WriteString(" WITH COMMENT")
+	}
+}
+
+// ShowIndexUsage represents a SHOW INDEX USAGE statement.
+type ShowIndexUsage struct {
+	Table *QualifiedName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowIndexUsage) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW INDEX USAGE FROM ")
+	FormatNode(buf, f, node.Table)
+}
+
+// ShowTriggers represents a SHOW TRIGGERS statement.
+type ShowTriggers struct {
+	Table *QualifiedName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowTriggers) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW TRIGGERS FROM ")
+	FormatNode(buf, f, node.Table)
+}
+
+// ShowStatistics represents a SHOW STATISTICS statement.
+type ShowStatistics struct {
+	Table *QualifiedName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowStatistics) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW STATISTICS FOR TABLE ")
+	FormatNode(buf, f, node.Table)
 }
 
 // ShowTables represents a SHOW TABLES statement.
 type ShowTables struct {
-	Name *QualifiedName
+	Name        *QualifiedName
+	WithComment bool
+	WithDetails bool
+}
+
+// ShowConsistency represents a SHOW CONSISTENCY statement.
+type ShowConsistency struct {
+	Table *QualifiedName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowConsistency) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW CONSISTENCY FROM ")
+	FormatNode(buf, f, node.Table)
 }
 
 // ShowConstraints represents a SHOW CONSTRAINTS statement.
@@ -92,6 +165,12 @@ func (node *ShowTables) Format(buf *bytes.Buffer, f FmtFlags) {
 		buf.WriteString(" FROM ")
 		FormatNode(buf, f, node.Name)
 	}
+	if node.WithComment {
+		buf.WriteString(" WITH COMMENT")
+	}
+	if node.WithDetails {
+		buf.WriteString(" WITH DETAILS")
+	}
 }
 
 // ShowGrants represents a SHOW GRANTS statement.
@@ -124,3 +203,25 @@ func (node *ShowCreateTable) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("SHOW CREATE TABLE ")
 	FormatNode(buf, f, node.Table)
 }
+
+// ShowEvents represents a SHOW EVENTS statement.
+type ShowEvents struct {
+	// At most one of Table and Database is set, restricting the events to the
+	// ones that target the given table or database.
+	Table    *QualifiedName
+	Database Name
+	Where    *Where
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowEvents) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW EVENTS")
+	if node.Table != nil {
+		buf.WriteString(" FOR TABLE ")
+		FormatNode(buf, f, node.Table)
+	} else if node.Database != "" {
+		buf.WriteString(" FOR DATABASE ")
+		FormatNode(buf, f, node.Database)
+	}
+	FormatNode(buf, f, node.Where)
+}
@@ -288,6 +288,54 @@ func (u *sqlSymUnion) dropBehavior() DropBehavior {
 func (u *sqlSymUnion) interleave() *InterleaveDef {
     return u.val.(*InterleaveDef)
 }
+func (u *sqlSymUnion) shardedIndexDef() *ShardedIndexDef {
+    return u.val.(*ShardedIndexDef)
+}
+func (u *sqlSymUnion) partitionBy() *PartitionBy {
+    return u.val.(*PartitionBy)
+}
+func (u *sqlSymUnion) listPartitions() []ListPartition {
+    return u.val.([]ListPartition)
+}
+func (u *sqlSymUnion) rangePartitions() []RangePartition {
+    return u.val.([]RangePartition)
+}
+func (u *sqlSymUnion) listPartition() ListPartition {
+    return u.val.(ListPartition)
+}
+func (u *sqlSymUnion) rangePartition() RangePartition {
+    return u.val.(RangePartition)
+}
+func (u *sqlSymUnion) referenceAction() ReferenceAction {
+    return u.val.(ReferenceAction)
+}
+func (u *sqlSymUnion) referenceActions() ReferenceActions {
+    return u.val.(ReferenceActions)
+}
+func (u *sqlSymUnion) constraintTimingAttr() ConstraintTimingAttr {
+    return u.val.(ConstraintTimingAttr)
+}
+func (u *sqlSymUnion) constraintTimingAttrs() []ConstraintTimingAttr {
+    return u.val.([]ConstraintTimingAttr)
+}
+func (u *sqlSymUnion) constraintTiming() ConstraintTiming {
+    return u.val.(ConstraintTiming)
+}
+func (u *sqlSymUnion) strPtr() *string {
+    return u.val.(*string)
+}
+func (u *sqlSymUnion) triggerEvent() TriggerEvent {
+    return u.val.(TriggerEvent)
+}
+func (u *sqlSymUnion) likeTableOpt() LikeTableOpt {
+    return u.val.(LikeTableOpt)
+}
+func (u *sqlSymUnion) likeTableOptions() []LikeTableOption {
+    return u.val.([]LikeTableOption)
+}
+func (u *sqlSymUnion) userOptions() UserOptions {
+    return u.val.(UserOptions)
+}
 
 %}
 
@@ -304,15 +352,25 @@ func (u *sqlSymUnion) interleave() *InterleaveDef {
 %type <Statement> stmt
 
 %type <Statement> alter_table_stmt
+%type <Statement> alter_user_stmt
+%type <Statement> comment_stmt
 %type <Statement> create_stmt
 %type <Statement> create_database_stmt
 %type <Statement> create_index_stmt
+%type <Statement> create_materialized_view_stmt
+%type <Statement> create_statistics_stmt
 %type <Statement> create_table_stmt
+%type <Statement> create_policy_stmt
+%type <Statement> create_schema_stmt
+%type <Statement> create_trigger_stmt
+%type <Statement> create_user_stmt
+%type <UserOptions> opt_user_options user_option_list user_option
 %type <Statement> delete_stmt
 %type <Statement> drop_stmt
 %type <Statement> explain_stmt
 %type <Statement> explainable_stmt
 %type <Statement> prepare_stmt
+%type <Statement> refresh_stmt
 %type <Statement> preparable_stmt
 %type <Statement> execute_stmt
 %type <Statement> deallocate_stmt
@@ -323,6 +381,7 @@ func (u *sqlSymUnion) interleave() *InterleaveDef {
 %type <Statement> revoke_stmt
 %type <*Select> select_stmt
 %type <Statement> savepoint_stmt
+%type <Statement> scrub_stmt
 %type <Statement> set_stmt
 %type <Statement> show_stmt
 %type <Statement> transaction_stmt
@@ -339,11 +398,20 @@ func (u *sqlSymUnion) interleave() *InterleaveDef {
 %type <AlterTableCmd> alter_table_cmd
 %type <AlterTableCmds> alter_table_cmds
 
-%type <empty> opt_collate_clause
+%type <empty> opt_collate_clause opt_equal opt_with
 
 %type <DropBehavior> opt_drop_behavior
 
+%type <bool> trigger_timing
+%type <bool> opt_temp
+%type <TriggerEvent> trigger_events trigger_event
+%type <LikeTableOpt> like_table_option
+%type <[]LikeTableOption> like_table_option_list
+
 %type <*StrVal> opt_encoding_clause
+%type <str>   opt_template_clause opt_owner_clause
+%type <*string> comment_text
+%type <str>   opt_lc_collate_clause opt_lc_ctype_clause
 
 %type <IsolationLevel> transaction_iso_level
 %type <UserPriority>  transaction_user_priority
@@ -370,6 +438,12 @@ func (u *sqlSymUnion) interleave() *InterleaveDef {
 
 %type <TableDefs> opt_table_elem_list table_elem_list
 %type <*InterleaveDef> opt_interleave
+%type <*PartitionBy> opt_partition_by
+%type <*ShardedIndexDef> opt_hash_sharded
+%type <[]ListPartition> list_partitions
+%type <ListPartition> list_partition
+%type <[]RangePartition> range_partitions
+%type <RangePartition> range_partition
 %type <empty> opt_all_clause
 %type <bool> distinct_clause
 %type <[]string> opt_column_list
@@ -495,7 +569,12 @@ func (u *sqlSymUnion) interleave() *InterleaveDef {
 %type <[]NamedColumnQualification> col_qual_list
 %type <NamedColumnQualification> col_qualification
 %type <ColumnQualification> col_qualification_elem
-%type <empty> key_actions key_delete key_match key_update key_action
+%type <ReferenceActions> key_actions
+%type <ConstraintTimingAttr> constraint_timing_attr
+%type <[]ConstraintTimingAttr> constraint_timing_attrs
+%type <ConstraintTiming> opt_constraint_timing
+%type <ReferenceAction> key_delete key_update key_action
+%type <empty> key_match
 
 %type <Expr>  func_application func_expr_common_subexpr
 %type <Expr>  func_expr func_expr_windowless
@@ -537,80 +616,80 @@ func (u *sqlSymUnion) interleave() *InterleaveDef {
 // "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str>   ACTION ADD
+%token <str>   ACTION ADD AFTER
 %token <str>   ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str>   ASYMMETRIC AT
 
-%token <str>   BEGIN BETWEEN BIGINT BIGSERIAL BIT
-%token <str>   BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
+%token <str>   BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BIT
+%token <str>   BLOB BOOL BOOLEAN BOTH BUCKET_COUNT BY BYTEA BYTES
 
 %token <str>   CASCADE CASE CAST CHAR
-%token <str>   CHARACTER CHARACTERISTICS CHECK
-%token <str>   COALESCE COLLATE COLLATION COLUMN COLUMNS COMMIT
-%token <str>   COMMITTED CONCAT CONFLICT CONSTRAINT CONSTRAINTS
-%token <str>   COVERING CREATE
+%token <str>   CHARACTER CHARACTERISTICS CHECK CLUSTER
+%token <str>   COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMIT
+%token <str>   COMMITTED CONCAT CONFLICT CONSISTENCY CONSTRAINT CONSTRAINTS
+%token <str>   COVERING CREATE CREATEDB
 %token <str>   CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE
 %token <str>   CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str>   CURRENT_USER CYCLE
 
-%token <str>   DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT
-%token <str>   DEALLOCATE DEFERRABLE DELETE DESC
+%token <str>   DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT DEFAULTS
+%token <str>   DEALLOCATE DEFERRABLE DEFERRED DELETE DESC DETAILS
 %token <str>   DISTINCT DO DOUBLE DROP
 
-%token <str>   ELSE ENCODING END ESCAPE EXCEPT
-%token <str>   EXISTS EXECUTE EXPLAIN EXTRACT
+%token <str>   EACH ELSE ENCODING END ESCAPE EVENTS EXCEPT EXCLUDING
+%token <str>   EXISTS EXECUTE EXPERIMENTAL EXPLAIN EXTRACT
 
 %token <str>   FALSE FAMILY FETCH FILTER FIRST FLOAT FLOORDIV FOLLOWING FOR
 %token <str>   FORCE_INDEX FOREIGN FROM FULL
 
 %token <str>   GRANT GRANTS GREATEST GROUP GROUPING
 
-%token <str>   HAVING HIGH HOUR
+%token <str>   HASH HAVING HIGH HOUR
 
-%token <str>   IF IFNULL ILIKE IN INTERLEAVE
+%token <str>   IF IFNULL ILIKE IMMEDIATE IN INCLUDING INTERLEAVE
 %token <str>   INDEX INDEXES INITIALLY
 %token <str>   INNER INSERT INT INT64 INTEGER
-%token <str>   INTERSECT INTERVAL INTO IS ISOLATION
+%token <str>   INTERSECT INTERVAL INTO INVERTED IS ISOLATION
 
 %token <str>   JOIN
 
 %token <str>   KEY KEYS
 
-%token <str>   LATERAL
-%token <str>   LEADING LEAST LEFT LEVEL LIKE LIMIT LOCAL
+%token <str>   LATERAL LC_COLLATE LC_CTYPE
+%token <str>   LEADING LEAST LEFT LEVEL LIKE LIMIT LIST LOCAL
 %token <str>   LOCALTIME LOCALTIMESTAMP LOW LSHIFT
 
-%token <str>   MATCH MINUTE MONTH
+%token <str>   MATCH MATERIALIZED MINUTE MONTH
 
-%token <str>   NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
+%token <str>   NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NOCREATEDB NORMAL
 %token <str>   NOT NOTHING NULL NULLIF
 %token <str>   NULLS NUMERIC
 
-%token <str>   OF OFF OFFSET ON ONLY OR
-%token <str>   ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY
+%token <str>   OF OFF OFFSET ON ONLY OPTIONS OR
+%token <str>   ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY OWNER
 
-%token <str>   PARENT PARTIAL PARTITION PLACING POSITION
+%token <str>   PARENT PARTIAL PARTITION PASSWORD PLACING POLICY POSITION
 %token <str>   PRECEDING PRECISION PREPARE PRIMARY PRIORITY
 
-%token <str>   RANGE READ REAL RECURSIVE REF REFERENCES
+%token <str>   RANGE READ REAL RECURSIVE REF REFERENCES REFRESH
 %token <str>   RENAME REPEATABLE
 %token <str>   RELEASE RESTRICT RETURNING REVOKE RIGHT ROLLBACK ROLLUP
 %token <str>   ROW ROWS RSHIFT
 
-%token <str>   SAVEPOINT SEARCH SECOND SELECT
-%token <str>   SERIAL SERIALIZABLE SESSION SESSION_USER SET SHOW
+%token <str>   SAVEPOINT SCHEMA SCRUB SEARCH SECOND SELECT
+%token <str>   SERIAL SERIALIZABLE SESSION SESSION_USER SET SETTING SHOW
 %token <str>   SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SQL
-%token <str>   START STRICT STRING STORING SUBSTRING
+%token <str>   START STATISTICS STRICT STRING STORED STORING SUBSTRING
 %token <str>   SYMMETRIC SYSTEM
 
-%token <str>   TABLE TABLES TEXT THEN
-%token <str>   TIME TIMESTAMP TIMESTAMPTZ TO TRAILING TRANSACTION TREAT TRIM TRUE
-%token <str>   TRUNCATE TYPE
+%token <str>   TABLE TABLES TEMP TEMPLATE TEMPORARY TEXT THEN
+%token <str>   TIME TIMESTAMP TIMESTAMPTZ TO TRAILING TRANSACTION TREAT TRIGGER
+%token <str>   TRIGGERS TRIM TRUE TRUNCATE TYPE
 
 %token <str>   UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN
-%token <str>   UPDATE UPSERT USER USING
+%token <str>   UPDATE UPSERT USAGE USER USING
 
-%token <str>   VALID VALIDATE VALUE VALUES VARCHAR VARIADIC VARYING
+%token <str>   VALID VALIDATE VALUE VALUES VARCHAR VARIADIC VARYING VIEW VIRTUAL
 
 %token <str>   WHEN WHERE WINDOW WITH WITHIN WITHOUT
 
@@ -717,6 +796,8 @@ stmt_list:
 
 stmt:
   alter_table_stmt
+| alter_user_stmt
+| comment_stmt
 | create_stmt
 | delete_stmt
 | drop_stmt
@@ -726,9 +807,11 @@ stmt:
 | deallocate_stmt
 | grant_stmt
 | insert_stmt
+| refresh_stmt
 | rename_stmt
 | revoke_stmt
 | savepoint_stmt
+| scrub_stmt
 | select_stmt
   {
     $$.val = $1.slct()
@@ -795,6 +878,16 @@ alter_table_cmd:
   {
     $$.val = &AlterTableDropNotNull{columnKeyword: $2.bool(), Column: $3}
   }
+  // ALTER TABLE <name> ALTER INDEX <idxname> DROP INTERLEAVE
+| ALTER INDEX name DROP INTERLEAVE
+  {
+    $$.val = &AlterTableDropInterleave{Index: Name($3)}
+  }
+  // ALTER TABLE <name> ALTER PRIMARY KEY USING COLUMNS (<colnames...>)
+| ALTER PRIMARY KEY USING COLUMNS '(' index_params ')'
+  {
+    $$.val = &AlterTableAlterPrimaryKey{Columns: $7.idxElems()}
+  }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> SET NOT NULL
 | ALTER opt_column name SET NOT NULL { unimplemented() }
   // ALTER TABLE <name> DROP [COLUMN] IF EXISTS <colname> [RESTRICT|CASCADE]
@@ -814,7 +907,10 @@ alter_table_cmd:
   }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> [SET DATA] TYPE <typename>
   //     [ USING <expression> ]
-| ALTER opt_column name opt_set_data TYPE typename opt_collate_clause alter_using {}
+| ALTER opt_column name opt_set_data TYPE typename opt_collate_clause alter_using
+  {
+    $$.val = &AlterTableAlterColumnType{columnKeyword: $2.bool(), Column: $3, ToType: $6.colType()}
+  }
   // ALTER TABLE <name> ADD CONSTRAINT ...
 | ADD table_constraint
   {
@@ -823,7 +919,10 @@ alter_table_cmd:
   // ALTER TABLE <name> ALTER CONSTRAINT ...
 | ALTER CONSTRAINT name { unimplemented() }
   // ALTER TABLE <name> VALIDATE CONSTRAINT ...
-| VALIDATE CONSTRAINT name { unimplemented() }
+| VALIDATE CONSTRAINT name
+  {
+    $$.val = &AlterTableValidateConstraint{Constraint: Name($3)}
+  }
   // ALTER TABLE <name> DROP CONSTRAINT IF EXISTS <name> [RESTRICT|CASCADE]
 | DROP CONSTRAINT IF EXISTS name opt_drop_behavior
   {
@@ -876,10 +975,46 @@ alter_using:
 | /* EMPTY */ {}
 
 // CREATE [DATABASE|INDEX|TABLE|TABLE AS]
+// COMMENT ON THING name IS 'text'
+comment_stmt:
+  COMMENT ON DATABASE name IS comment_text
+  {
+    $$.val = &CommentOnDatabase{Name: Name($4), Comment: $6.strPtr()}
+  }
+| COMMENT ON TABLE qualified_name IS comment_text
+  {
+    $$.val = &CommentOnTable{Table: $4.qname(), Comment: $6.strPtr()}
+  }
+| COMMENT ON COLUMN qualified_name IS comment_text
+  {
+    $$.val = &CommentOnColumn{ColumnName: $4.qname(), Comment: $6.strPtr()}
+  }
+| COMMENT ON INDEX table_name_with_index IS comment_text
+  {
+    $$.val = &CommentOnIndex{Index: $4.tableWithIdx(), Comment: $6.strPtr()}
+  }
+
+comment_text:
+  SCONST
+  {
+    t := $1
+    $$.val = &t
+  }
+| NULL
+  {
+    $$.val = (*string)(nil)
+  }
+
 create_stmt:
   create_database_stmt
 | create_index_stmt
+| create_materialized_view_stmt
+| create_policy_stmt
+| create_schema_stmt
+| create_statistics_stmt
 | create_table_stmt
+| create_trigger_stmt
+| create_user_stmt
 
 // DELETE FROM query
 delete_stmt:
@@ -890,13 +1025,13 @@ delete_stmt:
 
 // DROP itemtype [ IF EXISTS ] itemname [, itemname ...] [ RESTRICT | CASCADE ]
 drop_stmt:
-  DROP DATABASE name
+  DROP DATABASE name opt_drop_behavior
   {
-    $$.val = &DropDatabase{Name: Name($3), IfExists: false}
+    $$.val = &DropDatabase{Name: Name($3), IfExists: false, DropBehavior: $4.dropBehavior()}
   }
-| DROP DATABASE IF EXISTS name
+| DROP DATABASE IF EXISTS name opt_drop_behavior
   {
-    $$.val = &DropDatabase{Name: Name($5), IfExists: true}
+    $$.val = &DropDatabase{Name: Name($5), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
 | DROP INDEX table_name_with_index_list opt_drop_behavior
   {
@@ -922,6 +1057,32 @@ drop_stmt:
   {
     $$.val = &DropTable{Names: $5.qnames(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP SCHEMA name_list opt_drop_behavior { unimplemented() }
+| DROP SCHEMA IF EXISTS name_list opt_drop_behavior { unimplemented() }
+| DROP POLICY name ON qualified_name
+  {
+    $$.val = &DropPolicy{Name: Name($3), Table: $5.qname(), IfExists: false}
+  }
+| DROP POLICY IF EXISTS name ON qualified_name
+  {
+    $$.val = &DropPolicy{Name: Name($5), Table: $7.qname(), IfExists: true}
+  }
+| DROP TRIGGER name ON qualified_name
+  {
+    $$.val = &DropTrigger{Name: Name($3), Table: $5.qname(), IfExists: false}
+  }
+| DROP TRIGGER IF EXISTS name ON qualified_name
+  {
+    $$.val = &DropTrigger{Name: Name($5), Table: $7.qname(), IfExists: true}
+  }
+| DROP USER name_list
+  {
+    $$.val = &DropUser{Names: NameList($3.strs()), IfExists: false}
+  }
+| DROP USER IF EXISTS name_list
+  {
+    $$.val = &DropUser{Names: NameList($5.strs()), IfExists: true}
+  }
 
 any_name_list:
   any_name
@@ -1157,6 +1318,7 @@ grantee_list:
 
 // SET name TO 'var_value'
 // SET TIME ZONE 'var_value'
+// SET CLUSTER SETTING name = 'var_value'
 set_stmt:
   SET set_rest
   {
@@ -1174,6 +1336,14 @@ set_stmt:
   {
     $$.val = $3.stmt()
   }
+| SET CLUSTER SETTING var_name to_or_eq var_value
+  {
+    $$.val = &SetClusterSetting{Name: $4.qname(), Value: $6.expr()}
+  }
+| SET CLUSTER SETTING var_name to_or_eq DEFAULT
+  {
+    $$.val = &SetClusterSetting{Name: $4.qname()}
+  }
 | set_exprs_internal { /* SKIP DOC */ }
 
 set_exprs_internal:
@@ -1234,6 +1404,10 @@ generic_set:
     $$.val = &Set{Name: $1.qname()}
   }
 
+to_or_eq:
+  '='
+| TO
+
 set_rest_more:
   // Generic SET syntaxes:
   generic_set
@@ -1377,14 +1551,30 @@ show_stmt:
   {
     $$.val = &Show{Name: $2}
   }
+| SHOW CLUSTER SETTING var_name
+  {
+    $$.val = &ShowClusterSetting{Name: $4.qname()}
+  }
 | SHOW COLUMNS FROM var_name
   {
     $$.val = &ShowColumns{Table: $4.qname()}
   }
+| SHOW COLUMNS FROM var_name WITH COMMENT
+  {
+    $$.val = &ShowColumns{Table: $4.qname(), WithComment: true}
+  }
 | SHOW DATABASES
   {
     $$.val = &ShowDatabases{}
   }
+| SHOW DATABASES WITH COMMENT
+  {
+    $$.val = &ShowDatabases{WithComment: true}
+  }
+| SHOW DATABASES WITH DETAILS
+  {
+    $$.val = &ShowDatabases{WithDetails: true}
+  }
 | SHOW GRANTS on_privilege_target_clause for_grantee_clause
   {
     $$.val = &ShowGrants{Targets: $3.targetListPtr(), Grantees: $4.strs()}
@@ -1393,10 +1583,34 @@ show_stmt:
   {
     $$.val = &ShowIndex{Table: $4.qname()}
   }
+| SHOW INDEX FROM var_name WITH COMMENT
+  {
+    $$.val = &ShowIndex{Table: $4.qname(), WithComment: true}
+  }
 | SHOW INDEXES FROM var_name
   {
     $$.val = &ShowIndex{Table: $4.qname()}
   }
+| SHOW INDEXES FROM var_name WITH COMMENT
+  {
+    $$.val = &ShowIndex{Table: $4.qname(), WithComment: true}
+  }
+| SHOW INDEX USAGE FROM var_name
+  {
+    $$.val = &ShowIndexUsage{Table: $5.qname()}
+  }
+| SHOW TRIGGERS FROM var_name
+  {
+    $$.val = &ShowTriggers{Table: $4.qname()}
+  }
+| SHOW STATISTICS FOR TABLE var_name
+  {
+    $$.val = &ShowStatistics{Table: $5.qname()}
+  }
+| SHOW CONSISTENCY FROM var_name
+  {
+    $$.val = &ShowConsistency{Table: $4.qname()}
+  }
 | SHOW CONSTRAINT FROM var_name
   {
     $$.val = &ShowConstraints{Table: $4.qname()}
@@ -1413,6 +1627,14 @@ show_stmt:
   {
     $$.val = &ShowTables{Name: $3.qname()}
   }
+| SHOW TABLES opt_from_var_name_clause WITH COMMENT
+  {
+    $$.val = &ShowTables{Name: $3.qname(), WithComment: true}
+  }
+| SHOW TABLES opt_from_var_name_clause WITH DETAILS
+  {
+    $$.val = &ShowTables{Name: $3.qname(), WithDetails: true}
+  }
 | SHOW TIME ZONE
   {
     $$.val = &Show{Name: "TIME ZONE"}
@@ -1433,6 +1655,18 @@ show_stmt:
   {
     $$.val = &ShowCreateTable{Table: $4.qname()}
   }
+| SHOW EVENTS where_clause
+  {
+    $$.val = &ShowEvents{Where: newWhere(astWhere, $3.expr())}
+  }
+| SHOW EVENTS FOR TABLE var_name where_clause
+  {
+    $$.val = &ShowEvents{Table: $5.qname(), Where: newWhere(astWhere, $6.expr())}
+  }
+| SHOW EVENTS FOR DATABASE name where_clause
+  {
+    $$.val = &ShowEvents{Database: Name($5), Where: newWhere(astWhere, $6.expr())}
+  }
 
 opt_from_var_name_clause:
   FROM var_name
@@ -1467,13 +1701,35 @@ for_grantee_clause:
 
 // CREATE TABLE relname
 create_table_stmt:
-  CREATE TABLE any_name '(' opt_table_elem_list ')' opt_interleave
+  CREATE opt_temp TABLE any_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by
+  {
+    $$.val = &CreateTable{Table: $4.qname(), IfNotExists: false, Temporary: $2.bool(), Interleave: $8.interleave(), PartitionBy: $9.partitionBy(), Defs: $6.tblDefs()}
+  }
+| CREATE opt_temp TABLE IF NOT EXISTS any_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by
+  {
+    $$.val = &CreateTable{Table: $7.qname(), IfNotExists: true, Temporary: $2.bool(), Interleave: $11.interleave(), PartitionBy: $12.partitionBy(), Defs: $9.tblDefs()}
+  }
+| CREATE opt_temp TABLE any_name opt_column_list AS select_stmt
   {
-    $$.val = &CreateTable{Table: $3.qname(), IfNotExists: false, Interleave: $7.interleave(), Defs: $5.tblDefs()}
+    $$.val = &CreateTable{Table: $4.qname(), IfNotExists: false, Temporary: $2.bool(), AsColumnNames: NameList($5.strs()), AsSource: $7.slct()}
   }
-| CREATE TABLE IF NOT EXISTS any_name '(' opt_table_elem_list ')' opt_interleave
+| CREATE opt_temp TABLE IF NOT EXISTS any_name opt_column_list AS select_stmt
   {
-    $$.val = &CreateTable{Table: $6.qname(), IfNotExists: true, Interleave: $10.interleave(), Defs: $8.tblDefs()}
+    $$.val = &CreateTable{Table: $7.qname(), IfNotExists: true, Temporary: $2.bool(), AsColumnNames: NameList($8.strs()), AsSource: $10.slct()}
+  }
+
+opt_temp:
+  TEMPORARY
+  {
+    $$.val = true
+  }
+| TEMP
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
   }
 
 opt_table_elem_list:
@@ -1504,6 +1760,42 @@ table_elem:
   {
     $$.val = $1.constraintDef()
   }
+| LIKE qualified_name like_table_option_list
+  {
+    $$.val = &LikeTableDef{Name: $2.qname(), Options: $3.likeTableOptions()}
+  }
+
+like_table_option_list:
+  like_table_option_list INCLUDING like_table_option
+  {
+    $$.val = append($1.likeTableOptions(), LikeTableOption{Opt: $3.likeTableOpt()})
+  }
+| like_table_option_list EXCLUDING like_table_option
+  {
+    $$.val = append($1.likeTableOptions(), LikeTableOption{Excluded: true, Opt: $3.likeTableOpt()})
+  }
+| /* EMPTY */
+  {
+    $$.val = []LikeTableOption(nil)
+  }
+
+like_table_option:
+  CONSTRAINTS
+  {
+    $$.val = LikeTableOptConstraints
+  }
+| DEFAULTS
+  {
+    $$.val = LikeTableOptDefaults
+  }
+| INDEXES
+  {
+    $$.val = LikeTableOptIndexes
+  }
+| ALL
+  {
+    $$.val = LikeTableOptAll
+  }
 
 opt_interleave:
   INTERLEAVE IN PARENT name '(' name_list ')' opt_drop_behavior
@@ -1520,10 +1812,71 @@ opt_interleave:
     $$.val = (*InterleaveDef)(nil)
   }
 
+opt_hash_sharded:
+  USING HASH WITH BUCKET_COUNT '=' ICONST
+  {
+    $$.val = &ShardedIndexDef{ShardBuckets: $6.numVal()}
+  }
+| /* EMPTY */
+  {
+    $$.val = (*ShardedIndexDef)(nil)
+  }
+
+opt_partition_by:
+  PARTITION BY LIST '(' name_list ')' '(' list_partitions ')'
+  {
+    $$.val = &PartitionBy{Fields: NameList($5.strs()), List: $8.listPartitions()}
+  }
+| PARTITION BY RANGE '(' name_list ')' '(' range_partitions ')'
+  {
+    $$.val = &PartitionBy{Fields: NameList($5.strs()), Range: $8.rangePartitions()}
+  }
+| /* EMPTY */
+  {
+    $$.val = (*PartitionBy)(nil)
+  }
+
+list_partitions:
+  list_partition
+  {
+    $$.val = []ListPartition{$1.listPartition()}
+  }
+| list_partitions ',' list_partition
+  {
+    $$.val = append($1.listPartitions(), $3.listPartition())
+  }
+
+list_partition:
+  PARTITION name VALUES IN '(' expr_list ')'
+  {
+    $$.val = ListPartition{Name: Name($2), Exprs: $6.exprs()}
+  }
+
+range_partitions:
+  range_partition
+  {
+    $$.val = []RangePartition{$1.rangePartition()}
+  }
+| range_partitions ',' range_partition
+  {
+    $$.val = append($1.rangePartitions(), $3.rangePartition())
+  }
+
+range_partition:
+  PARTITION name VALUES FROM '(' expr_list ')' TO '(' expr_list ')'
+  {
+    $$.val = RangePartition{Name: Name($2), From: $6.exprs(), To: $10.exprs()}
+  }
+
 column_def:
   name typename col_qual_list
   {
-    $$.val = newColumnTableDef(Name($1), $2.colType(), $3.colQuals())
+    tableDef, err := newColumnTableDef(Name($1), $2.colType(), $3.colQuals())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = tableDef
   }
 
 col_qual_list:
@@ -1545,7 +1898,10 @@ col_qualification:
   {
     $$.val = NamedColumnQualification{Qualification: $1.colQualElem()}
   }
-| COLLATE any_name { unimplemented() }
+| COLLATE name
+  {
+    $$.val = NamedColumnQualification{Qualification: ColumnCollation($2)}
+  }
 | FAMILY name
   {
     $$.val = NamedColumnQualification{Qualification: &ColumnFamilyConstraint{Family: Name($2)}}
@@ -1558,6 +1914,10 @@ col_qualification:
   {
     $$.val = NamedColumnQualification{Qualification: &ColumnFamilyConstraint{Family: Name($6), Create: true, IfNotExists: true}}
   }
+| constraint_timing_attr
+  {
+    $$.val = NamedColumnQualification{Qualification: $1.constraintTimingAttr()}
+  }
 
 // DEFAULT NULL is already the default for Postgres. But define it here and
 // carry it forward into the system to make it explicit.
@@ -1596,35 +1956,56 @@ col_qualification_elem:
   {
     $$.val = &ColumnDefault{Expr: $2.expr()}
   }
+| AS '(' a_expr ')' VIRTUAL
+  {
+    $$.val = &ColumnComputedDef{Expr: $3.expr()}
+  }
+| AS '(' a_expr ')' STORED
+  {
+    $$.val = &ColumnComputedDef{Expr: $3.expr(), Stored: true}
+  }
 | REFERENCES qualified_name opt_name_parens key_match key_actions
  {
     $$.val = &ColumnFKConstraint{
       Table: $2.qname(),
       Col: Name($3),
+      Actions: $5.referenceActions(),
     }
  }
 
 index_def:
-  INDEX opt_name '(' index_params ')' opt_storing opt_interleave
+  INDEX opt_name '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by
   {
     $$.val = &IndexTableDef{
       Name:    Name($2),
       Columns: $4.idxElems(),
-      Storing: $6.strs(),
-      Interleave: $7.interleave(),
+      Sharded: $6.shardedIndexDef(),
+      Storing: $7.strs(),
+      Interleave: $8.interleave(),
+      PartitionBy: $9.partitionBy(),
     }
   }
-| UNIQUE INDEX opt_name '(' index_params ')' opt_storing opt_interleave
+| UNIQUE INDEX opt_name '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by
   {
     $$.val = &UniqueConstraintTableDef{
       IndexTableDef: IndexTableDef {
         Name:    Name($3),
         Columns: $5.idxElems(),
-        Storing: $7.strs(),
-        Interleave: $8.interleave(),
+        Sharded: $7.shardedIndexDef(),
+        Storing: $8.strs(),
+        Interleave: $9.interleave(),
+        PartitionBy: $10.partitionBy(),
       },
     }
   }
+| INVERTED INDEX opt_name '(' index_params ')'
+  {
+    $$.val = &IndexTableDef{
+      Name:     Name($3),
+      Columns:  $5.idxElems(),
+      Inverted: true,
+    }
+  }
 
 family_def:
   FAMILY opt_name '(' family_params ')'
@@ -1650,19 +2031,34 @@ table_constraint:
   }
 
 constraint_elem:
-  CHECK '(' a_expr ')'
+  CHECK '(' a_expr ')' opt_constraint_timing
   {
+    if $5.constraintTiming().Deferrable {
+      sqllex.Error("check constraints cannot be deferred")
+      return 1
+    }
     $$.val = &CheckConstraintTableDef{
       Expr: $3.expr(),
+      NotValid: $5.constraintTiming().NotValid,
     }
   }
-| UNIQUE '(' name_list ')' opt_storing opt_interleave
+| UNIQUE '(' name_list ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by opt_constraint_timing
   {
+    if $9.constraintTiming().Deferrable {
+      sqllex.Error("unique constraints cannot be deferred")
+      return 1
+    }
+    if $9.constraintTiming().NotValid {
+      sqllex.Error("unique constraints cannot be marked NOT VALID")
+      return 1
+    }
     $$.val = &UniqueConstraintTableDef{
       IndexTableDef: IndexTableDef{
         Columns: NameListToIndexElems($3.strs()),
-        Storing: $5.strs(),
-        Interleave: $6.interleave(),
+        Sharded: $5.shardedIndexDef(),
+        Storing: $6.strs(),
+        Interleave: $7.interleave(),
+        PartitionBy: $8.partitionBy(),
       },
     }
   }
@@ -1676,7 +2072,16 @@ constraint_elem:
     }
   }
 | FOREIGN KEY '(' name_list ')' REFERENCES qualified_name
-    opt_column_list key_match key_actions { unimplemented() }
+    opt_column_list key_match key_actions opt_constraint_timing
+  {
+    $$.val = &ForeignKeyConstraintTableDef{
+      Table: $7.qname(),
+      FromCols: $4.strs(),
+      ToCols: $8.strs(),
+      Actions: $10.referenceActions(),
+      Timing: $11.constraintTiming(),
+    }
+  }
 
 storing:
   COVERING
@@ -1717,29 +2122,112 @@ key_match:
 | MATCH SIMPLE { unimplemented() }
 | /* EMPTY */ {}
 
-// We combine the update and delete actions into one value temporarily for
-// simplicity of parsing, and then break them down again in the calling
-// production. update is in the left 8 bits, delete in the right. Note that
-// NOACTION is the default.
+// NO ACTION is the default.
 key_actions:
-  key_update { unimplemented() }
-| key_delete { unimplemented() }
-| key_update key_delete { unimplemented() }
-| key_delete key_update { unimplemented() }
-| /* EMPTY */ {}
+  key_update
+  {
+    $$.val = ReferenceActions{Update: $1.referenceAction()}
+  }
+| key_delete
+  {
+    $$.val = ReferenceActions{Delete: $1.referenceAction()}
+  }
+| key_update key_delete
+  {
+    $$.val = ReferenceActions{Delete: $2.referenceAction(), Update: $1.referenceAction()}
+  }
+| key_delete key_update
+  {
+    $$.val = ReferenceActions{Delete: $1.referenceAction(), Update: $2.referenceAction()}
+  }
+| /* EMPTY */
+  {
+    $$.val = ReferenceActions{}
+  }
 
 key_update:
-  ON UPDATE key_action { unimplemented() }
+  ON UPDATE key_action
+  {
+    $$.val = $3.referenceAction()
+  }
 
 key_delete:
-  ON DELETE key_action { unimplemented() }
+  ON DELETE key_action
+  {
+    $$.val = $3.referenceAction()
+  }
+
+// The DEFERRABLE and INITIALLY clauses of a constraint. Those of a column
+// constraint are column qualifications of their own, so that NOT DEFERRABLE
+// and NOT NULL don't conflict.
+opt_constraint_timing:
+  constraint_timing_attrs
+  {
+    timing, err := makeConstraintTiming($1.constraintTimingAttrs())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = timing
+  }
+| /* EMPTY */
+  {
+    $$.val = ConstraintTiming{}
+  }
+
+constraint_timing_attrs:
+  constraint_timing_attr
+  {
+    $$.val = []ConstraintTimingAttr{$1.constraintTimingAttr()}
+  }
+| constraint_timing_attrs constraint_timing_attr
+  {
+    $$.val = append($1.constraintTimingAttrs(), $2.constraintTimingAttr())
+  }
+
+constraint_timing_attr:
+  DEFERRABLE
+  {
+    $$.val = Deferrable
+  }
+| NOT DEFERRABLE
+  {
+    $$.val = NotDeferrable
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = InitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = InitiallyImmediate
+  }
+| NOT VALID
+  {
+    $$.val = NotValid
+  }
 
 key_action:
-  NO ACTION { unimplemented() }
-| RESTRICT { unimplemented() }
-| CASCADE { unimplemented() }
-| SET NULL { unimplemented() }
-| SET DEFAULT { unimplemented() }
+  NO ACTION
+  {
+    $$.val = NoAction
+  }
+| RESTRICT
+  {
+    $$.val = Restrict
+  }
+| CASCADE
+  {
+    $$.val = Cascade
+  }
+| SET NULL
+  {
+    $$.val = SetNull
+  }
+| SET DEFAULT
+  {
+    $$.val = SetDefault
+  }
 
 numeric_only:
   FCONST
@@ -1762,20 +2250,33 @@ truncate_stmt:
     $$.val = &Truncate{Tables: $3.qnames(), DropBehavior: $4.dropBehavior()}
   }
 
+// EXPERIMENTAL SCRUB TABLE
+scrub_stmt:
+  EXPERIMENTAL SCRUB TABLE qualified_name
+  {
+    $$.val = &Scrub{Table: $4.qname()}
+  }
+| EXPERIMENTAL SCRUB TABLE qualified_name WITH OPTIONS name_list
+  {
+    $$.val = &Scrub{Table: $4.qname(), Options: NameList($7.strs())}
+  }
+
 // CREATE INDEX
 create_index_stmt:
-  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by
   {
     $$.val = &CreateIndex{
       Name:    Name($4),
       Table:   $6.qname(),
       Unique:  $2.bool(),
       Columns: $8.idxElems(),
-      Storing: $10.strs(),
-      Interleave: $11.interleave(),
+      Sharded: $10.shardedIndexDef(),
+      Storing: $11.strs(),
+      Interleave: $12.interleave(),
+      PartitionBy: $13.partitionBy(),
     }
   }
-| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by
   {
     $$.val = &CreateIndex{
       Name:        Name($7),
@@ -1783,9 +2284,161 @@ create_index_stmt:
       Unique:      $2.bool(),
       IfNotExists: true,
       Columns:     $11.idxElems(),
-      Storing:     $13.strs(),
-      Interleave: $14.interleave(),
+      Sharded:     $13.shardedIndexDef(),
+      Storing:     $14.strs(),
+      Interleave: $15.interleave(),
+      PartitionBy: $16.partitionBy(),
+    }
+  }
+| CREATE INVERTED INDEX opt_name ON qualified_name '(' index_params ')'
+  {
+    $$.val = &CreateIndex{
+      Name:     Name($4),
+      Table:    $6.qname(),
+      Inverted: true,
+      Columns:  $8.idxElems(),
+    }
+  }
+| CREATE INVERTED INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')'
+  {
+    $$.val = &CreateIndex{
+      Name:        Name($7),
+      Table:       $9.qname(),
+      Inverted:    true,
+      IfNotExists: true,
+      Columns:     $11.idxElems(),
+    }
+  }
+
+// CREATE MATERIALIZED VIEW name [ ( column [, ...] ) ] AS query
+create_materialized_view_stmt:
+  CREATE MATERIALIZED VIEW qualified_name opt_column_list AS select_stmt
+  {
+    $$.val = &CreateMaterializedView{Name: $4.qname(), ColumnNames: NameList($5.strs()), AsSource: $7.slct()}
+  }
+
+// REFRESH MATERIALIZED VIEW name
+refresh_stmt:
+  REFRESH MATERIALIZED VIEW qualified_name
+  {
+    $$.val = &RefreshMaterializedView{Name: $4.qname()}
+  }
+
+// CREATE USER [ IF NOT EXISTS ] name [ [ WITH ] option [ ... ] ]
+//
+// where option is PASSWORD 'password', CREATEDB or NOCREATEDB.
+create_user_stmt:
+  CREATE USER name opt_user_options
+  {
+    $$.val = &CreateUser{Name: Name($3), Options: $4.userOptions()}
+  }
+| CREATE USER IF NOT EXISTS name opt_user_options
+  {
+    $$.val = &CreateUser{Name: Name($6), Options: $7.userOptions(), IfNotExists: true}
+  }
+
+// ALTER USER name [ WITH ] option [ ... ]
+alter_user_stmt:
+  ALTER USER name opt_with user_option_list
+  {
+    $$.val = &AlterUser{Name: Name($3), Options: $5.userOptions()}
+  }
+
+opt_user_options:
+  opt_with user_option_list
+  {
+    $$.val = $2.userOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = UserOptions{}
+  }
+
+user_option_list:
+  user_option
+| user_option_list user_option
+  {
+    opts, err := $1.userOptions().combine($2.userOptions())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
     }
+    $$.val = opts
+  }
+
+user_option:
+  PASSWORD SCONST
+  {
+    t := $2
+    $$.val = UserOptions{Password: &t}
+  }
+| CREATEDB
+  {
+    t := true
+    $$.val = UserOptions{CreateDB: &t}
+  }
+| NOCREATEDB
+  {
+    t := false
+    $$.val = UserOptions{CreateDB: &t}
+  }
+
+// Tables are only namespaced by their database, so schemas are not supported.
+create_schema_stmt:
+  CREATE SCHEMA name { unimplemented() }
+| CREATE SCHEMA IF NOT EXISTS name { unimplemented() }
+
+// CREATE POLICY name ON table USING (expr)
+create_policy_stmt:
+  CREATE POLICY name ON qualified_name USING '(' a_expr ')'
+  {
+    $$.val = &CreatePolicy{Name: Name($3), Table: $5.qname(), Expr: $8.expr()}
+  }
+
+// CREATE TRIGGER name { BEFORE | AFTER } event [ OR ... ] ON table
+//   FOR EACH ROW EXECUTE 'statements'
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_timing trigger_events ON qualified_name FOR EACH ROW EXECUTE SCONST
+  {
+    $$.val = &CreateTrigger{Name: Name($3), Before: $4.bool(), Events: $5.triggerEvent(), Table: $7.qname(), Body: $12}
+  }
+
+trigger_timing:
+  BEFORE
+  {
+    $$.val = true
+  }
+| AFTER
+  {
+    $$.val = false
+  }
+
+trigger_events:
+  trigger_event
+| trigger_events OR trigger_event
+  {
+    $$.val = $1.triggerEvent() | $3.triggerEvent()
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = TriggerInsert
+  }
+| UPDATE
+  {
+    $$.val = TriggerUpdate
+  }
+| DELETE
+  {
+    $$.val = TriggerDelete
+  }
+
+// CREATE STATISTICS name ON col [, col ...] FROM table
+create_statistics_stmt:
+  CREATE STATISTICS name ON name_list FROM qualified_name
+  {
+    $$.val = &CreateStatistics{Name: Name($3), ColumnNames: NameList($5.strs()), Table: $7.qname()}
   }
 
 opt_unique:
@@ -1816,8 +2469,14 @@ index_elem:
   {
     $$.val = IndexElem{Column: Name($1), Direction: $3.dir()}
   }
-| func_expr_windowless opt_collate opt_asc_desc { unimplemented() }
-| '(' a_expr ')' opt_collate opt_asc_desc { unimplemented() }
+| func_expr_windowless opt_collate opt_asc_desc
+  {
+    $$.val = IndexElem{Expr: $1.expr(), Direction: $3.dir()}
+  }
+| '(' a_expr ')' opt_collate opt_asc_desc
+  {
+    $$.val = IndexElem{Expr: $2.expr(), Direction: $5.dir()}
+  }
 
 family_params:
   family_elem
@@ -2006,17 +2665,52 @@ transaction_iso_level:
   }
 
 create_database_stmt:
-  CREATE DATABASE name opt_encoding_clause
+  CREATE DATABASE name opt_with opt_template_clause opt_owner_clause opt_encoding_clause opt_lc_collate_clause opt_lc_ctype_clause
+  {
+    $$.val = &CreateDatabase{
+      Name: Name($3),
+      Template: $5,
+      Owner: $6,
+      Encoding: $7.strVal(),
+      Collate: $8,
+      CType: $9,
+    }
+  }
+| CREATE DATABASE IF NOT EXISTS name opt_with opt_template_clause opt_owner_clause opt_encoding_clause opt_lc_collate_clause opt_lc_ctype_clause
+  {
+    $$.val = &CreateDatabase{
+      IfNotExists: true,
+      Name: Name($6),
+      Template: $8,
+      Owner: $9,
+      Encoding: $10.strVal(),
+      Collate: $11,
+      CType: $12,
+    }
+  }
+
+opt_template_clause:
+  TEMPLATE opt_equal name
   {
-    $$.val = &CreateDatabase{Name: Name($3), Encoding: $4.strVal()}
+    $$ = $3
   }
-| CREATE DATABASE IF NOT EXISTS name opt_encoding_clause
+| /* EMPTY */
   {
-    $$.val = &CreateDatabase{IfNotExists: true, Name: Name($6), Encoding: $7.strVal()}
+    $$ = ""
+  }
+
+opt_owner_clause:
+  OWNER opt_equal name
+  {
+    $$ = $3
+  }
+| /* EMPTY */
+  {
+    $$ = ""
   }
 
 opt_encoding_clause:
-  ENCODING '=' SCONST
+  ENCODING opt_equal SCONST
   {
     $$.val = &StrVal{s: $3}
   }
@@ -2024,6 +2718,34 @@ opt_encoding_clause:
     $$.val = (*StrVal)(nil)
   }
 
+opt_lc_collate_clause:
+  LC_COLLATE opt_equal SCONST
+  {
+    $$ = $3
+  }
+| /* EMPTY */
+  {
+    $$ = ""
+  }
+
+opt_lc_ctype_clause:
+  LC_CTYPE opt_equal SCONST
+  {
+    $$ = $3
+  }
+| /* EMPTY */
+  {
+    $$ = ""
+  }
+
+opt_equal:
+  '=' {}
+| /* EMPTY */ {}
+
+opt_with:
+  WITH {}
+| /* EMPTY */ {}
+
 // TODO(dan): While RETURNING is not supported with UPSERT and ON CONFLICT
 // (#6637), we do some gymnastics with the grammar to make the diagrams in the
 // docs only show the supported combinations. This simplifies once #6637 is
@@ -3155,8 +3877,14 @@ a_expr:
 //  {
 //    $$.val = &AnnotateTypeExpr{Expr: $1.expr(), Type: $3.colType()}
 //  }
-| a_expr COLLATE any_name { unimplemented() }
-| a_expr AT TIME ZONE a_expr %prec AT { unimplemented() }
+| a_expr COLLATE name
+  {
+    $$.val = &CollateExpr{Expr: $1.expr(), Locale: $3}
+  }
+| a_expr AT TIME ZONE a_expr %prec AT
+  {
+    $$.val = &FuncExpr{Name: &QualifiedName{Base: "TIMEZONE"}, Exprs: Exprs{$5.expr(), $1.expr()}}
+  }
   // These operators must be called out explicitly in order to make use of
   // bison's automatic operator-precedence handling. All other operator names
   // are handled by the generic productions using "OP", below; and all those
@@ -3605,8 +4333,14 @@ func_expr:
 // expressions are not allowed, where needed to disambiguate the grammar
 // (e.g. in CREATE INDEX).
 func_expr_windowless:
-  func_application { unimplemented() }
-| func_expr_common_subexpr { unimplemented() }
+  func_application
+  {
+    $$.val = $1.expr()
+  }
+| func_expr_common_subexpr
+  {
+    $$.val = $1.expr()
+  }
 
 // Special expressions that are considered to be functions.
 func_expr_common_subexpr:
@@ -4392,18 +5126,25 @@ col_label:
 unreserved_keyword:
   ACTION
 | ADD
+| AFTER
 | ALTER
 | AT
+| BEFORE
 | BEGIN
 | BLOB
+| BUCKET_COUNT
 | BY
 | CASCADE
+| CLUSTER
 | COLUMNS
+| COMMENT
 | COMMIT
 | COMMITTED
 | CONFLICT
+| CONSISTENCY
 | CONSTRAINTS
 | COVERING
+| CREATEDB
 | CUBE
 | CURRENT
 | CYCLE
@@ -4412,46 +5153,66 @@ unreserved_keyword:
 | DATABASES
 | DAY
 | DEALLOCATE
+| DEFAULTS
+| DEFERRED
 | DELETE
+| DETAILS
 | DOUBLE
 | DROP
+| EACH
 | ENCODING
+| EVENTS
+| EXCLUDING
 | EXECUTE
+| EXPERIMENTAL
 | EXPLAIN
 | FILTER
 | FIRST
 | FOLLOWING
 | FORCE_INDEX
 | GRANTS
+| HASH
 | HIGH
 | HOUR
+| IMMEDIATE
+| INCLUDING
 | INDEXES
 | INSERT
 | INTERLEAVE
+| INVERTED
 | ISOLATION
 | KEY
 | KEYS
+| LC_COLLATE
+| LC_CTYPE
 | LEVEL
+| LIST
 | LOCAL
 | LOW
 | MATCH
+| MATERIALIZED
 | MINUTE
 | MONTH
 | NAME
 | NAMES
 | NEXT
 | NO
+| NOCREATEDB
 | NORMAL
 | NOTHING
 | NO_INDEX_JOIN
 | NULLS
 | OF
 | OFF
+| OPTIONS
 | ORDINALITY
 | OVER
+| OWNER
 | PARENT
 | PARTIAL
 | PARTITION
+| PASSWORD
+| POLICY
 | PRECEDING
 | PREPARE
 | PRIORITY
@@ -4459,6 +5220,7 @@ unreserved_keyword:
 | READ
 | RECURSIVE
 | REF
+| REFRESH
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -4468,22 +5230,32 @@ unreserved_keyword:
 | ROLLUP
 | ROWS
 | SAVEPOINT
+| SCHEMA
+| SCRUB
 | SEARCH
 | SECOND
 | SERIALIZABLE
 | SESSION
 | SET
+| SETTING
 | SHOW
 | SIMPLE
 | SNAPSHOT
 | SQL
 | START
+| STATISTICS
+| STORED
 | STORING
 | STRICT
 | SYSTEM
 | TABLES
+| TEMP
+| TEMPLATE
+| TEMPORARY
 | TEXT
 | TRANSACTION
+| TRIGGER
+| TRIGGERS
 | TRUNCATE
 | TYPE
 | UNBOUNDED
@@ -4491,10 +5263,13 @@ unreserved_keyword:
 | UNKNOWN
 | UPDATE
 | UPSERT
+| USAGE
 | VALID
 | VALIDATE
 | VALUE
 | VARYING
+| VIEW
+| VIRTUAL
 | WITHIN
 | WITHOUT
 | YEAR
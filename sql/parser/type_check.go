@@ -35,6 +35,8 @@ var (
 	TypeDecimal Datum = &DDecimal{}
 	// TypeString is the type of a DString.
 	TypeString Datum = NewDString("")
+	// TypeCollatedString is the type of a DCollatedString of any locale.
+	TypeCollatedString Datum = &DCollatedString{}
 	// TypeBytes is the type of a DBytes.
 	TypeBytes Datum = NewDBytes("")
 	// TypeDate is the type of a DDate.
@@ -260,6 +262,25 @@ func (expr *AnnotateTypeExpr) TypeCheck(ctx *SemaContext, desired Datum) (TypedE
 	return expr, nil
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *CollateExpr) TypeCheck(ctx *SemaContext, desired Datum) (TypedExpr, error) {
+	if err := CheckCollationLocale(expr.Locale); err != nil {
+		return nil, err
+	}
+	subExpr, err := expr.Expr.TypeCheck(ctx, TypeString)
+	if err != nil {
+		return nil, err
+	}
+	switch typ := subExpr.ReturnType(); {
+	case typ == DNull, typ.TypeEqual(TypeString), typ.TypeEqual(TypeCollatedString):
+	default:
+		return nil, fmt.Errorf("incompatible COLLATE argument type: %s", typ.Type())
+	}
+	expr.Expr = subExpr
+	expr.typ = &DCollatedString{Locale: expr.Locale}
+	return expr, nil
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *CoalesceExpr) TypeCheck(ctx *SemaContext, desired Datum) (TypedExpr, error) {
 	typedSubExprs, retType, err := typeCheckSameTypedExprs(ctx, desired, expr.Exprs...)
@@ -299,12 +320,17 @@ func (expr *ExistsExpr) TypeCheck(ctx *SemaContext, desired Datum) (TypedExpr, e
 
 // TypeCheck implements the Expr interface.
 func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired Datum) (TypedExpr, error) {
+	name := string(expr.Name.Base)
 	if len(expr.Name.Indirect) > 0 {
-		// We don't support qualified function names (yet).
-		return nil, fmt.Errorf("unknown function: %s", expr.Name)
+		// Qualified function names are only supported for builtins such as
+		// crdb_internal.force_retry(), which are registered under their
+		// qualified name.
+		ind, ok := expr.Name.Indirect[0].(NameIndirection)
+		if !ok || len(expr.Name.Indirect) > 1 {
+			return nil, fmt.Errorf("unknown function: %s", expr.Name)
+		}
+		name += "." + string(ind)
 	}
-
-	name := string(expr.Name.Base)
 	// Optimize for the case where name is already normalized to upper/lower
 	// case. Note that the Builtins map contains duplicate entries for
 	// upper/lower case names.
@@ -348,7 +374,7 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired Datum) (TypedExpr, err
 	}
 	expr.fn = fn.(Builtin)
 	returnType := fn.returnType()
-	if _, ok = expr.fn.params().(AnyType); ok {
+	if _, ok = expr.fn.params().(AnyType); ok && returnType == nil {
 		if len(typedSubExprs) > 0 {
 			returnType = typedSubExprs[0].ReturnType()
 		} else {
@@ -585,6 +611,12 @@ func (d *DDecimal) TypeCheck(_ *SemaContext, desired Datum) (TypedExpr, error) {
 // identity function for Datum.
 func (d *DString) TypeCheck(_ *SemaContext, desired Datum) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DCollatedString) TypeCheck(_ *SemaContext, desired Datum) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DBytes) TypeCheck(_ *SemaContext, desired Datum) (TypedExpr, error) { return d, nil }
@@ -721,6 +753,14 @@ func typeCheckComparisonOp(
 		return nil, nil, CmpOp{}, fmt.Errorf(unsupportedCompErrFmtWithTypes, leftReturn.Type(),
 			op, rightReturn.Type())
 	}
+	// The comparison operators of collated strings accept all the locales, but
+	// only strings of the same locale can be compared.
+	if l, ok := leftReturn.(*DCollatedString); ok {
+		if r, ok := rightReturn.(*DCollatedString); ok && l.Locale != r.Locale {
+			return nil, nil, CmpOp{}, fmt.Errorf(unsupportedCompErrFmtWithTypes, leftReturn.Type(),
+				op, rightReturn.Type())
+		}
+	}
 	return leftExpr, rightExpr, fn.(CmpOp), nil
 }
 
@@ -0,0 +1,109 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package parser
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+)
+
+// UserOptions are the options of a CREATE USER or ALTER USER statement. The
+// options which are nil are not set by the statement.
+type UserOptions struct {
+	Password *string
+	// CreateDB determines whether the user can create databases.
+	CreateDB *bool
+}
+
+// combine returns the options set by either o or other, or an error if an
+// option is set by both.
+func (o UserOptions) combine(other UserOptions) (UserOptions, error) {
+	if o.Password != nil && other.Password != nil || o.CreateDB != nil && other.CreateDB != nil {
+		return o, errors.New("conflicting or redundant options")
+	}
+	if other.Password != nil {
+		o.Password = other.Password
+	}
+	if other.CreateDB != nil {
+		o.CreateDB = other.CreateDB
+	}
+	return o, nil
+}
+
+// Format implements the NodeFormatter interface. The password is redacted,
+// as the statements are logged and recorded in the statement statistics.
+func (o UserOptions) Format(buf *bytes.Buffer, f FmtFlags) {
+	if o.Password == nil && o.CreateDB == nil {
+		return
+	}
+	buf.WriteString(" WITH")
+	if o.Password != nil {
+		buf.WriteString(" PASSWORD '*****'")
+	}
+	if o.CreateDB != nil {
+		if *o.CreateDB {
+			buf.WriteString(" CREATEDB")
+		} else {
+			buf.WriteString(" NOCREATEDB")
+		}
+	}
+}
+
+// CreateUser represents a CREATE USER statement. A user created without a
+// password has no password.
+type CreateUser struct {
+	Name        Name
+	Options     UserOptions
+	IfNotExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateUser) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE USER ")
+	if node.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	FormatNode(buf, f, node.Name)
+	FormatNode(buf, f, node.Options)
+}
+
+// AlterUser represents an ALTER USER statement.
+type AlterUser struct {
+	Name    Name
+	Options UserOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterUser) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("ALTER USER ")
+	FormatNode(buf, f, node.Name)
+	FormatNode(buf, f, node.Options)
+}
+
+// DropUser represents a DROP USER statement.
+type DropUser struct {
+	Names    NameList
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropUser) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP USER ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Names)
+}
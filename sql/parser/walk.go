@@ -133,6 +133,17 @@ func (expr *CastExpr) Walk(v Visitor) Expr {
 	return expr
 }
 
+// Walk implements the Expr interface.
+func (expr *CollateExpr) Walk(v Visitor) Expr {
+	e, changed := WalkExpr(v, expr.Expr)
+	if changed {
+		exprCopy := *expr
+		exprCopy.Expr = e
+		return &exprCopy
+	}
+	return expr
+}
+
 // Walk implements the Expr interface.
 func (expr *AnnotateTypeExpr) Walk(v Visitor) Expr {
 	e, changed := WalkExpr(v, expr.Expr)
@@ -398,6 +409,9 @@ func (expr dNull) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DString) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DCollatedString) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DTimestamp) Walk(_ Visitor) Expr { return expr }
 
@@ -17,7 +17,9 @@
 package pgwire
 
 import (
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"net"
@@ -43,8 +45,9 @@ const (
 )
 
 const (
-	version30  = 196608
-	versionSSL = 80877103
+	version30     = 196608
+	versionSSL    = 80877103
+	versionCancel = 80877102
 )
 
 const drainMaxWait = 10 * time.Second
@@ -65,9 +68,21 @@ type Server struct {
 	mu struct {
 		sync.Mutex
 		draining bool
+		// conns maps the cancel keys of the open connections to the
+		// connections, to find the target of cancel requests.
+		conns   map[cancelKey]*v3Conn
+		nextPID int32
 	}
 }
 
+// cancelKey identifies a connection in cancel requests. It is sent to the
+// client in the BackendKeyData message when the connection is established.
+// The process ID is unique among the connections of a Server, and the secret
+// is random so that other clients can't cancel the connection's queries.
+type cancelKey struct {
+	pid, secret int32
+}
+
 type serverMetrics struct {
 	bytesInCount  *metric.Counter
 	bytesOutCount *metric.Counter
@@ -84,12 +99,14 @@ func newServerMetrics(reg *metric.Registry) *serverMetrics {
 
 // MakeServer creates a Server, adding network stats to the given Registry.
 func MakeServer(context *base.Context, executor *sql.Executor, reg *metric.Registry) *Server {
-	return &Server{
+	s := &Server{
 		context:  context,
 		executor: executor,
 		registry: reg,
 		metrics:  newServerMetrics(reg),
 	}
+	s.mu.conns = make(map[cancelKey]*v3Conn)
+	return s
 }
 
 // Match returns true if rd appears to be a Postgres connection.
@@ -103,7 +120,7 @@ func Match(rd io.Reader) bool {
 	if err != nil {
 		return false
 	}
-	return version == version30 || version == versionSSL
+	return version == version30 || version == versionSSL || version == versionCancel
 }
 
 // IsDraining returns true if the server is not currently accepting
@@ -201,6 +218,21 @@ func (s *Server) ServeConn(conn net.Conn) error {
 		errSSLRequired = true
 	}
 
+	if version == versionCancel {
+		// As in postgres, there is no reply to a cancel request, whether the
+		// request is valid or not.
+		pid, err := buf.getUint32()
+		if err != nil {
+			return err
+		}
+		secret, err := buf.getUint32()
+		if err != nil {
+			return err
+		}
+		s.cancelQuery(cancelKey{pid: int32(pid), secret: int32(secret)})
+		return nil
+	}
+
 	if version == version30 {
 		sessionArgs, argsErr := parseOptions(buf.msg)
 		// We make a connection regardless of argsErr. If there was an error parsing
@@ -220,6 +252,12 @@ func (s *Server) ServeConn(conn net.Conn) error {
 			return v3conn.sendInternalError(ErrDraining)
 		}
 
+		key, err := s.registerConn(&v3conn)
+		if err != nil {
+			return v3conn.sendInternalError(err.Error())
+		}
+		defer s.unregisterConn(key)
+
 		if tlsConn, ok := conn.(*tls.Conn); ok {
 			tlsState := tlsConn.ConnectionState()
 			authenticationHook, err := security.UserAuthHook(s.context.Insecure, &tlsState)
@@ -234,6 +272,41 @@ func (s *Server) ServeConn(conn net.Conn) error {
 	return errors.Errorf("unknown protocol version %d", version)
 }
 
+// registerConn assigns a cancel key to the connection and makes it the target
+// of the cancel requests using that key.
+func (s *Server) registerConn(c *v3Conn) (cancelKey, error) {
+	var secret [4]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return cancelKey{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.nextPID++
+	key := cancelKey{pid: s.mu.nextPID, secret: int32(binary.BigEndian.Uint32(secret[:]))}
+	s.mu.conns[key] = c
+	c.cancelKey = key
+	return key, nil
+}
+
+// unregisterConn stops the cancel requests using key from reaching their
+// connection.
+func (s *Server) unregisterConn(key cancelKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.mu.conns, key)
+}
+
+// cancelQuery cancels the query being executed by the connection with the
+// given cancel key. It does nothing if there is no such connection.
+func (s *Server) cancelQuery(key cancelKey) {
+	s.mu.Lock()
+	c, ok := s.mu.conns[key]
+	s.mu.Unlock()
+	if ok {
+		c.session.CancelQuery()
+	}
+}
+
 // Registry returns a registry with the metrics tracked by this server, which can be used to
 // access its stats or be added to another registry.
 func (s *Server) Registry() *metric.Registry {
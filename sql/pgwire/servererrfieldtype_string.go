@@ -8,14 +8,16 @@ const (
 	_serverErrFieldType_name_0 = "serverErrFieldSQLState"
 	_serverErrFieldType_name_1 = "serverErrFieldSrcFile"
 	_serverErrFieldType_name_2 = "serverErrFieldSrcLineserverErrFieldMsgPrimary"
-	_serverErrFieldType_name_3 = "serverErrFieldSrcFunctionserverErrFieldSeverity"
+	_serverErrFieldType_name_3 = "serverErrFieldPosition"
+	_serverErrFieldType_name_4 = "serverErrFieldSrcFunctionserverErrFieldSeverity"
 )
 
 var (
 	_serverErrFieldType_index_0 = [...]uint8{0, 22}
 	_serverErrFieldType_index_1 = [...]uint8{0, 21}
 	_serverErrFieldType_index_2 = [...]uint8{0, 21, 45}
-	_serverErrFieldType_index_3 = [...]uint8{0, 25, 47}
+	_serverErrFieldType_index_3 = [...]uint8{0, 22}
+	_serverErrFieldType_index_4 = [...]uint8{0, 25, 47}
 )
 
 func (i serverErrFieldType) String() string {
@@ -27,9 +29,11 @@ func (i serverErrFieldType) String() string {
 	case 76 <= i && i <= 77:
 		i -= 76
 		return _serverErrFieldType_name_2[_serverErrFieldType_index_2[i]:_serverErrFieldType_index_2[i+1]]
+	case i == 80:
+		return _serverErrFieldType_name_3
 	case 82 <= i && i <= 83:
 		i -= 82
-		return _serverErrFieldType_name_3[_serverErrFieldType_index_3[i]:_serverErrFieldType_index_3[i+1]]
+		return _serverErrFieldType_name_4[_serverErrFieldType_index_4[i]:_serverErrFieldType_index_4[i+1]]
 	default:
 		return fmt.Sprintf("serverErrFieldType(%d)", i)
 	}
@@ -30,6 +30,7 @@ import (
 	"gopkg.in/inf.v0"
 
 	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/util/duration"
 	"github.com/cockroachdb/cockroach/util/log"
 	"github.com/cockroachdb/pq"
 	"github.com/cockroachdb/pq/oid"
@@ -96,7 +97,7 @@ func typeForDatum(d parser.Datum) pgType {
 	case *parser.DDecimal:
 		return pgType{oid.T_numeric, -1}
 
-	case *parser.DString:
+	case *parser.DString, *parser.DCollatedString:
 		return pgType{oid.T_text, -1}
 
 	case *parser.DDate:
@@ -118,7 +119,27 @@ func typeForDatum(d parser.Datum) pgType {
 
 const secondsInDay = 24 * 60 * 60
 
-func (b *writeBuffer) writeTextDatum(d parser.Datum, sessionLoc *time.Location) {
+// pgEpochUnix is the Unix time of the Postgres epoch, 2000-01-01 00:00:00 UTC.
+// Binary timestamps and dates are encoded relative to it.
+const pgEpochUnix = 946684800
+
+// pgEpochUnixDays is the number of days between the Unix and Postgres epochs.
+const pgEpochUnixDays = pgEpochUnix / secondsInDay
+
+// timeToPGBinary returns the number of microseconds between the Postgres epoch
+// and t, which is the binary encoding of timestamps.
+func timeToPGBinary(t time.Time) int64 {
+	return (t.Unix()-pgEpochUnix)*1000000 + int64(t.Nanosecond()/1000)
+}
+
+// pgBinaryToTime is the inverse of timeToPGBinary.
+func pgBinaryToTime(i int64) time.Time {
+	return time.Unix(pgEpochUnix+i/1000000, (i%1000000)*1000).UTC()
+}
+
+func (b *writeBuffer) writeTextDatum(
+	d parser.Datum, sessionLoc *time.Location, intervalStyle parser.IntervalStyle,
+) {
 	if log.V(2) {
 		log.Infof("pgwire writing TEXT datum of type: %T, %#v", d, d)
 	}
@@ -166,6 +187,9 @@ func (b *writeBuffer) writeTextDatum(d parser.Datum, sessionLoc *time.Location)
 	case *parser.DString:
 		b.writeLengthPrefixedString(string(*v))
 
+	case *parser.DCollatedString:
+		b.writeLengthPrefixedString(v.Contents)
+
 	case *parser.DDate:
 		t := time.Unix(int64(*v)*secondsInDay, 0)
 		s := formatTs(t, nil)
@@ -183,7 +207,7 @@ func (b *writeBuffer) writeTextDatum(d parser.Datum, sessionLoc *time.Location)
 		b.write(s)
 
 	case *parser.DInterval:
-		b.writeLengthPrefixedString(v.String())
+		b.writeLengthPrefixedString(parser.FormatInterval(v.Duration, intervalStyle))
 
 	default:
 		b.setError(errors.Errorf("unsupported type %T", d))
@@ -302,6 +326,27 @@ func (b *writeBuffer) writeBinaryDatum(d parser.Datum) {
 	case *parser.DString:
 		b.writeLengthPrefixedString(string(*v))
 
+	case *parser.DCollatedString:
+		b.writeLengthPrefixedString(v.Contents)
+
+	case *parser.DDate:
+		b.putInt32(4)
+		b.putInt32(int32(int64(*v) - pgEpochUnixDays))
+
+	case *parser.DTimestamp:
+		b.putInt32(8)
+		b.putInt64(timeToPGBinary(v.Time))
+
+	case *parser.DTimestampTZ:
+		b.putInt32(8)
+		b.putInt64(timeToPGBinary(v.Time))
+
+	case *parser.DInterval:
+		b.putInt32(16)
+		b.putInt64(v.Nanos / int64(time.Microsecond))
+		b.putInt32(int32(v.Days))
+		b.putInt32(int32(v.Months))
+
 	default:
 		b.setError(errors.Errorf("unsupported type %T", d))
 	}
@@ -608,6 +653,13 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return d, errors.Errorf("could not parse string %q as timestamp", b)
 			}
 			d = parser.MakeDTimestamp(ts, time.Microsecond)
+		case formatBinary:
+			var i int64
+			err := binary.Read(bytes.NewReader(b), binary.BigEndian, &i)
+			if err != nil {
+				return d, err
+			}
+			d = parser.MakeDTimestamp(pgBinaryToTime(i), time.Microsecond)
 		default:
 			return d, errors.Errorf("unsupported timestamp format code: %s", code)
 		}
@@ -619,6 +671,13 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return d, errors.Errorf("could not parse string %q as timestamp", b)
 			}
 			d = parser.MakeDTimestampTZ(ts, time.Microsecond)
+		case formatBinary:
+			var i int64
+			err := binary.Read(bytes.NewReader(b), binary.BigEndian, &i)
+			if err != nil {
+				return d, err
+			}
+			d = parser.MakeDTimestampTZ(pgBinaryToTime(i), time.Microsecond)
 		default:
 			return d, errors.Errorf("unsupported timestamptz format code: %s", code)
 		}
@@ -635,6 +694,13 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 			}
 			daysSinceEpoch := ts.Unix() / secondsInDay
 			d = parser.NewDDate(parser.DDate(daysSinceEpoch))
+		case formatBinary:
+			var i int32
+			err := binary.Read(bytes.NewReader(b), binary.BigEndian, &i)
+			if err != nil {
+				return d, err
+			}
+			d = parser.NewDDate(parser.DDate(int64(i) + pgEpochUnixDays))
 		default:
 			return d, errors.Errorf("unsupported date format code: %s", code)
 		}
@@ -646,6 +712,20 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return d, errors.Errorf("could not parse string %q as interval", b)
 			}
 			return d, nil
+		case formatBinary:
+			r := bytes.NewReader(b)
+			var micros int64
+			var days, months int32
+			for _, ptr := range []interface{}{&micros, &days, &months} {
+				if err := binary.Read(r, binary.BigEndian, ptr); err != nil {
+					return d, err
+				}
+			}
+			d = &parser.DInterval{Duration: duration.Duration{
+				Months: int64(months),
+				Days:   int64(days),
+				Nanos:  micros * int64(time.Microsecond),
+			}}
 		default:
 			return d, errors.Errorf("unsupported interval format code: %s", code)
 		}
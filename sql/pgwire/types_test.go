@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/pq/oid"
 
 	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/util/duration"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 	"github.com/cockroachdb/cockroach/util/metric"
 )
@@ -82,6 +83,68 @@ func TestTimestampRoundtrip(t *testing.T) {
 	}
 }
 
+func TestBinaryRoundtrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dec := new(parser.DDecimal)
+	dec.SetString("-1728718718271827121233.1212121212")
+	ts := time.Date(2016, 9, 12, 18, 3, 4, 123456000, time.UTC)
+	preEpoch := time.Date(1969, 7, 20, 20, 17, 40, 1000, time.UTC)
+
+	testCases := []struct {
+		id    oid.Oid
+		datum parser.Datum
+	}{
+		{oid.T_numeric, dec},
+		{oid.T_date, parser.NewDDate(parser.DDate(17056))},
+		{oid.T_date, parser.NewDDate(parser.DDate(-200))},
+		{oid.T_timestamp, parser.MakeDTimestamp(ts, time.Microsecond)},
+		{oid.T_timestamp, parser.MakeDTimestamp(preEpoch, time.Microsecond)},
+		{oid.T_timestamptz, parser.MakeDTimestampTZ(ts, time.Microsecond)},
+		{oid.T_interval, &parser.DInterval{Duration: duration.Duration{Months: 14, Days: -3, Nanos: 3723000001000}}},
+	}
+	for _, tc := range testCases {
+		wbuf := writeBuffer{bytecount: metric.NewCounter()}
+		wbuf.writeBinaryDatum(tc.datum)
+		if wbuf.err != nil {
+			t.Fatalf("%s: %s", tc.datum, wbuf.err)
+		}
+
+		rbuf := readBuffer{msg: wbuf.wrapped.Bytes()}
+		plen, err := rbuf.getUint32()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := rbuf.getBytes(int(plen))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodeOidDatum(tc.id, formatBinary, b)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.datum, err)
+		}
+		if got.Compare(tc.datum) != 0 {
+			t.Errorf("expected %s, got %s", tc.datum, got)
+		}
+	}
+}
+
+func TestBinaryTimestampEpoch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Binary timestamps count microseconds from 2000-01-01.
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if i := timeToPGBinary(epoch); i != 0 {
+		t.Fatalf("expected 0, got %d", i)
+	}
+	if i := timeToPGBinary(epoch.Add(-time.Microsecond)); i != -1 {
+		t.Fatalf("expected -1, got %d", i)
+	}
+	if actual := pgBinaryToTime(-1); !actual.Equal(epoch.Add(-time.Microsecond)) {
+		t.Fatalf("expected %s, got %s", epoch.Add(-time.Microsecond), actual)
+	}
+}
+
 func BenchmarkWriteBinaryDecimal(b *testing.B) {
 	buf := writeBuffer{bytecount: metric.NewCounter()}
 
@@ -66,6 +66,9 @@ const (
 	serverMsgBindComplete         serverMessageType = '2'
 	serverMsgParameterStatus      serverMessageType = 'S'
 	serverMsgNoData               serverMessageType = 'n'
+	serverMsgPortalSuspended      serverMessageType = 's'
+	serverMsgNoticeResponse       serverMessageType = 'N'
+	serverMsgBackendKeyData       serverMessageType = 'K'
 )
 
 //go:generate stringer -type=serverErrFieldType
@@ -79,6 +82,7 @@ const (
 	serverErrFieldSrcFile     serverErrFieldType = 'F'
 	serverErrFieldSrcLine     serverErrFieldType = 'L'
 	serverErrFieldSrcFunction serverErrFieldType = 'R'
+	serverErrFieldPosition    serverErrFieldType = 'P'
 )
 
 //go:generate stringer -type=prepareType
@@ -103,6 +107,23 @@ type preparedStatementMeta struct {
 // sql.PreparedPortal on a v3Conn's sql.Session.
 type preparedPortalMeta struct {
 	outFormats []formatCode
+
+	// suspended is set once an Execute message with a row limit did not
+	// return all the rows of the portal. Later Execute messages resume from
+	// it instead of running the statement again.
+	suspended *suspendedPortal
+}
+
+// suspendedPortal holds the result of a portal whose execution was suspended
+// because it reached the row limit of an Execute message.
+type suspendedPortal struct {
+	result sql.Result
+	// sent is the number of rows of result already sent to the client.
+	sent int
+	// cursor returns the rows following those of result, if the statement
+	// was executed in a transaction which keeps its plan open. It is nil
+	// once all its rows were sent.
+	cursor *sql.Cursor
 }
 
 type v3Conn struct {
@@ -120,6 +141,9 @@ type v3Conn struct {
 	// https://github.com/postgres/postgres/blob/master/src/backend/tcop/postgres.c
 	doingExtendedQueryMessage, ignoreTillSync bool
 
+	// cancelKey identifies the connection in cancel requests.
+	cancelKey cancelKey
+
 	metrics *serverMetrics
 }
 
@@ -181,11 +205,13 @@ func parseOptions(data []byte) (sql.SessionArgs, error) {
 var statusReportParams = map[string]string{
 	"client_encoding": "UTF8",
 	"DateStyle":       "ISO",
-	// The latest version of the docs that was consulted during the development
-	// of this package. We specify this version to avoid having to support old
-	// code paths which various client tools fall back to if they can't
-	// determine that the server is new enough.
-	"server_version": "9.5.0",
+}
+
+func (c *v3Conn) sendParameterStatus(key, value string) error {
+	c.writeBuf.initMsg(serverMsgParameterStatus)
+	c.writeBuf.writeTerminatedString(key)
+	c.writeBuf.writeTerminatedString(value)
+	return c.writeBuf.finishMsg(c.wr)
 }
 
 func (c *v3Conn) serve(authenticationHook func(string, bool) error) error {
@@ -203,13 +229,19 @@ func (c *v3Conn) serve(authenticationHook func(string, bool) error) error {
 		return err
 	}
 	for key, value := range statusReportParams {
-		c.writeBuf.initMsg(serverMsgParameterStatus)
-		c.writeBuf.writeTerminatedString(key)
-		c.writeBuf.writeTerminatedString(value)
-		if err := c.writeBuf.finishMsg(c.wr); err != nil {
+		if err := c.sendParameterStatus(key, value); err != nil {
 			return err
 		}
 	}
+	if err := c.sendParameterStatus("server_version", c.executor.PGServerVersion()); err != nil {
+		return err
+	}
+	c.writeBuf.initMsg(serverMsgBackendKeyData)
+	c.writeBuf.putInt32(c.cancelKey.pid)
+	c.writeBuf.putInt32(c.cancelKey.secret)
+	if err := c.writeBuf.finishMsg(c.wr); err != nil {
+		return err
+	}
 	if err := c.wr.Flush(); err != nil {
 		return err
 	}
@@ -302,7 +334,7 @@ func (c *v3Conn) serve(authenticationHook func(string, bool) error) error {
 			err = c.wr.Flush()
 
 		default:
-			err = c.sendErrorWithCode(pgerror.CodeProtocolViolationError, sqlbase.MakeSrcCtx(0),
+			err = c.sendErrorWithCode(pgerror.CodeProtocolViolationError, sqlbase.MakeSrcCtx(0), 0,
 				fmt.Sprintf("unrecognized client message type %s", typ))
 		}
 		if err != nil {
@@ -317,7 +349,7 @@ func (c *v3Conn) handleSimpleQuery(ctx context.Context, buf *readBuffer) error {
 		return err
 	}
 
-	return c.executeStatements(ctx, query, nil, nil, true, 0)
+	return c.executeStatements(ctx, query, nil, nil, true)
 }
 
 func (c *v3Conn) handleParse(ctx context.Context, buf *readBuffer) error {
@@ -443,7 +475,7 @@ func (c *v3Conn) handleDescribe(buf *readBuffer) error {
 			return c.sendInternalError(fmt.Sprintf("unknown portal %q", name))
 		}
 
-		portalMeta := portal.ProtocolMeta.(preparedPortalMeta)
+		portalMeta := portal.ProtocolMeta.(*preparedPortalMeta)
 		return c.sendRowDescription(portal.Stmt.Columns, portalMeta.outFormats)
 	default:
 		return errors.Errorf("unknown describe type: %s", typ)
@@ -463,6 +495,9 @@ func (c *v3Conn) handleClose(buf *readBuffer) error {
 	case prepareStatement:
 		c.session.PreparedStatements.Delete(name)
 	case preparePortal:
+		if portal, ok := c.session.PreparedPortals.Get(name); ok {
+			closePortal(portal)
+		}
 		c.session.PreparedPortals.Delete(name)
 	default:
 		return errors.Errorf("unknown close type: %s", typ)
@@ -476,10 +511,11 @@ func (c *v3Conn) handleBind(buf *readBuffer) error {
 		return err
 	}
 	// The unnamed portal can be freely overwritten.
-	if portalName != "" {
-		if c.session.PreparedPortals.Exists(portalName) {
+	if portal, ok := c.session.PreparedPortals.Get(portalName); ok {
+		if portalName != "" {
 			return c.sendInternalError(fmt.Sprintf("portal %q already exists", portalName))
 		}
+		closePortal(portal)
 	}
 	statementName, err := buf.getString()
 	if err != nil {
@@ -599,7 +635,7 @@ func (c *v3Conn) handleBind(buf *readBuffer) error {
 	// Create the new PreparedPortal in the connection's Session.
 	portal := c.session.PreparedPortals.New(portalName, stmt, qargs)
 	// Attach pgwire-specific metadata to the PreparedPortal.
-	portal.ProtocolMeta = preparedPortalMeta{outFormats: columnFormatCodes}
+	portal.ProtocolMeta = &preparedPortalMeta{outFormats: columnFormatCodes}
 	c.writeBuf.initMsg(serverMsgBindComplete)
 	return c.writeBuf.finishMsg(c.wr)
 }
@@ -619,13 +655,97 @@ func (c *v3Conn) handleExecute(ctx context.Context, buf *readBuffer) error {
 	}
 
 	stmt := portal.Stmt
-	portalMeta := portal.ProtocolMeta.(preparedPortalMeta)
+	portalMeta := portal.ProtocolMeta.(*preparedPortalMeta)
+	if portalMeta.suspended != nil {
+		return c.sendSuspendedRows(portalMeta, int(limit))
+	}
 	pinfo := parser.PlaceholderInfo{
 		Types:  stmt.SQLTypes,
 		Values: portal.Qargs,
 	}
 
-	return c.executeStatements(ctx, stmt.Query, &pinfo, portalMeta.outFormats, false, int(limit))
+	if limit == 0 {
+		return c.executeStatements(ctx, stmt.Query, &pinfo, portalMeta.outFormats, false)
+	}
+
+	tracing.AnnotateTrace()
+	results := c.executor.ExecutePortal(ctx, c.session, stmt.Query, &pinfo, int(limit))
+
+	tracing.AnnotateTrace()
+	if results.Empty {
+		c.writeBuf.initMsg(serverMsgEmptyQuery)
+		return c.writeBuf.finishMsg(c.wr)
+	}
+	// A prepared statement is a single statement, so there is a single result.
+	// If it has more rows than the limit, the portal is suspended after sending
+	// limit rows and the rest are sent by subsequent Execute messages. In a
+	// transaction opened by an earlier message, the rest are only read when
+	// they are requested.
+	if len(results.ResultList) == 1 {
+		result := results.ResultList[0]
+		if result.Err == nil && result.Type == parser.Rows &&
+			(result.Cursor != nil || len(result.Rows) > int(limit)) {
+			portalMeta.suspended = &suspendedPortal{result: result, cursor: result.Cursor}
+			if err := c.sendNotices(result.Notices); err != nil {
+				return err
+			}
+			return c.sendSuspendedRows(portalMeta, int(limit))
+		}
+	}
+	return c.sendResponse(results.ResultList, portalMeta.outFormats, false)
+}
+
+// sendSuspendedRows sends up to limit of the remaining rows of a suspended
+// portal, or all of them if limit is 0. It sends PortalSuspended if rows
+// remain afterwards and CommandComplete otherwise. As in postgres, the row
+// count of the CommandComplete tag is the number of rows sent by this call,
+// and executing a completed portal again returns no rows.
+func (c *v3Conn) sendSuspendedRows(portalMeta *preparedPortalMeta, limit int) error {
+	s := portalMeta.suspended
+	var rows []sql.ResultRow
+	var more bool
+	if s.sent < len(s.result.Rows) {
+		rows = s.result.Rows[s.sent:]
+		if limit != 0 && len(rows) > limit {
+			rows = rows[:limit]
+		}
+		s.sent += len(rows)
+		more = s.sent < len(s.result.Rows) || s.cursor != nil
+	} else if s.cursor != nil {
+		var err error
+		rows, more, err = s.cursor.Fetch(limit)
+		if err != nil {
+			s.cursor = nil
+			return c.sendError(err)
+		}
+		if !more {
+			s.cursor = nil
+		}
+	}
+	for _, row := range rows {
+		if err := c.sendDataRow(row, portalMeta.outFormats); err != nil {
+			return err
+		}
+	}
+
+	if more {
+		c.writeBuf.initMsg(serverMsgPortalSuspended)
+		return c.writeBuf.finishMsg(c.wr)
+	}
+	tag := append(c.tagBuf[:0], s.result.PGTag...)
+	tag = append(tag, ' ')
+	tag = appendUint(tag, uint(len(rows)))
+	return c.sendCommandComplete(tag)
+}
+
+// closePortal releases the rows of a portal which are no longer needed
+// because the portal is closed or replaced.
+func closePortal(portal *sql.PreparedPortal) {
+	portalMeta := portal.ProtocolMeta.(*preparedPortalMeta)
+	if s := portalMeta.suspended; s != nil && s.cursor != nil {
+		s.cursor.Close()
+		s.cursor = nil
+	}
 }
 
 func (c *v3Conn) executeStatements(
@@ -634,7 +754,6 @@ func (c *v3Conn) executeStatements(
 	pinfo *parser.PlaceholderInfo,
 	formatCodes []formatCode,
 	sendDescription bool,
-	limit int,
 ) error {
 	tracing.AnnotateTrace()
 	results := c.executor.ExecuteStatements(ctx, c.session, stmts, pinfo)
@@ -645,7 +764,7 @@ func (c *v3Conn) executeStatements(
 		c.writeBuf.initMsg(serverMsgEmptyQuery)
 		return c.writeBuf.finishMsg(c.wr)
 	}
-	return c.sendResponse(results.ResultList, formatCodes, sendDescription, limit)
+	return c.sendResponse(results.ResultList, formatCodes, sendDescription)
 }
 
 func (c *v3Conn) sendCommandComplete(tag []byte) error {
@@ -657,7 +776,11 @@ func (c *v3Conn) sendCommandComplete(tag []byte) error {
 
 func (c *v3Conn) sendError(err error) error {
 	if sqlErr, ok := err.(sqlbase.ErrorWithPGCode); ok {
-		return c.sendErrorWithCode(sqlErr.Code(), sqlErr.SrcContext(), err.Error())
+		var position int
+		if posErr, ok := err.(sqlbase.ErrorWithPosition); ok {
+			position = posErr.Position()
+		}
+		return c.sendErrorWithCode(sqlErr.Code(), sqlErr.SrcContext(), position, err.Error())
 	}
 	return c.sendInternalError(err.Error())
 }
@@ -665,12 +788,16 @@ func (c *v3Conn) sendError(err error) error {
 // TODO(andrei): Figure out the correct codes to send for all the errors
 // in this file and remove this function.
 func (c *v3Conn) sendInternalError(errToSend string) error {
-	return c.sendErrorWithCode(pgerror.CodeInternalError, sqlbase.MakeSrcCtx(1), errToSend)
+	return c.sendErrorWithCode(pgerror.CodeInternalError, sqlbase.MakeSrcCtx(1), 0, errToSend)
 }
 
 // errCode is a postgres error code, plus our extensions.
 // See http://www.postgresql.org/docs/9.5/static/errcodes-appendix.html
-func (c *v3Conn) sendErrorWithCode(errCode string, errCtx sqlbase.SrcCtx, errToSend string) error {
+// position is the 1-based character position in the statement the error
+// refers to, or 0 if there is none.
+func (c *v3Conn) sendErrorWithCode(
+	errCode string, errCtx sqlbase.SrcCtx, position int, errToSend string,
+) error {
 	if c.doingExtendedQueryMessage {
 		c.ignoreTillSync = true
 	}
@@ -686,6 +813,11 @@ func (c *v3Conn) sendErrorWithCode(errCode string, errCtx sqlbase.SrcCtx, errToS
 	c.writeBuf.putErrFieldMsg(serverErrFieldMsgPrimary)
 	c.writeBuf.writeTerminatedString(errToSend)
 
+	if position > 0 {
+		c.writeBuf.putErrFieldMsg(serverErrFieldPosition)
+		c.writeBuf.writeTerminatedString(strconv.Itoa(position))
+	}
+
 	if errCtx.File != "" {
 		c.writeBuf.putErrFieldMsg(serverErrFieldSrcFile)
 		c.writeBuf.writeTerminatedString(errCtx.File)
@@ -708,7 +840,7 @@ func (c *v3Conn) sendErrorWithCode(errCode string, errCtx sqlbase.SrcCtx, errToS
 	return c.wr.Flush()
 }
 
-func (c *v3Conn) sendResponse(results sql.ResultList, formatCodes []formatCode, sendDescription bool, limit int) error {
+func (c *v3Conn) sendResponse(results sql.ResultList, formatCodes []formatCode, sendDescription bool) error {
 	if len(results) == 0 {
 		return c.sendCommandComplete(nil)
 	}
@@ -719,11 +851,8 @@ func (c *v3Conn) sendResponse(results sql.ResultList, formatCodes []formatCode,
 			}
 			break
 		}
-		if limit != 0 && len(result.Rows) > limit {
-			if err := c.sendInternalError(fmt.Sprintf("execute row count limits not supported: %d of %d", limit, len(result.Rows))); err != nil {
-				return err
-			}
-			break
+		if err := c.sendNotices(result.Notices); err != nil {
+			return err
 		}
 
 		if result.PGTag == "INSERT" {
@@ -752,23 +881,7 @@ func (c *v3Conn) sendResponse(results sql.ResultList, formatCodes []formatCode,
 
 			// Send DataRows.
 			for _, row := range result.Rows {
-				c.writeBuf.initMsg(serverMsgDataRow)
-				c.writeBuf.putInt16(int16(len(row.Values)))
-				for i, col := range row.Values {
-					fmtCode := formatText
-					if formatCodes != nil {
-						fmtCode = formatCodes[i]
-					}
-					switch fmtCode {
-					case formatText:
-						c.writeBuf.writeTextDatum(col, c.session.Location)
-					case formatBinary:
-						c.writeBuf.writeBinaryDatum(col)
-					default:
-						c.writeBuf.setError(errors.Errorf("unsupported format code %s", fmtCode))
-					}
-				}
-				if err := c.writeBuf.finishMsg(c.wr); err != nil {
+				if err := c.sendDataRow(row, formatCodes); err != nil {
 					return err
 				}
 			}
@@ -793,6 +906,54 @@ func (c *v3Conn) sendResponse(results sql.ResultList, formatCodes []formatCode,
 	return nil
 }
 
+// sendNotices sends a NoticeResponse message for each notice. Notices are
+// informational and don't affect the flow of the other messages.
+func (c *v3Conn) sendNotices(notices []sql.Notice) error {
+	for _, notice := range notices {
+		code := pgerror.CodeSuccessfulCompletionError
+		if notice.Severity == sql.NoticeSeverityWarning {
+			code = pgerror.CodeWarningError
+		}
+
+		c.writeBuf.initMsg(serverMsgNoticeResponse)
+
+		c.writeBuf.putErrFieldMsg(serverErrFieldSeverity)
+		c.writeBuf.writeTerminatedString(notice.Severity.String())
+
+		c.writeBuf.putErrFieldMsg(serverErrFieldSQLState)
+		c.writeBuf.writeTerminatedString(code)
+
+		c.writeBuf.putErrFieldMsg(serverErrFieldMsgPrimary)
+		c.writeBuf.writeTerminatedString(notice.Message)
+
+		c.writeBuf.nullTerminate()
+		if err := c.writeBuf.finishMsg(c.wr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *v3Conn) sendDataRow(row sql.ResultRow, formatCodes []formatCode) error {
+	c.writeBuf.initMsg(serverMsgDataRow)
+	c.writeBuf.putInt16(int16(len(row.Values)))
+	for i, col := range row.Values {
+		fmtCode := formatText
+		if formatCodes != nil {
+			fmtCode = formatCodes[i]
+		}
+		switch fmtCode {
+		case formatText:
+			c.writeBuf.writeTextDatum(col, c.session.Location, c.session.IntervalStyle)
+		case formatBinary:
+			c.writeBuf.writeBinaryDatum(col)
+		default:
+			c.writeBuf.setError(errors.Errorf("unsupported format code %s", fmtCode))
+		}
+	}
+	return c.writeBuf.finishMsg(c.wr)
+}
+
 func (c *v3Conn) sendRowDescription(columns []sql.ResultColumn, formatCodes []formatCode) error {
 	if len(columns) == 0 {
 		c.writeBuf.initMsg(serverMsgNoData)
@@ -812,7 +973,12 @@ func (c *v3Conn) sendRowDescription(columns []sql.ResultColumn, formatCodes []fo
 		c.writeBuf.putInt16(0) // Column attribute ID (optional).
 		c.writeBuf.putInt32(int32(typ.oid))
 		c.writeBuf.putInt16(int16(typ.size))
-		c.writeBuf.putInt32(0) // Type modifier (none of our supported types have modifiers).
+		typeModifier := column.TypeModifier
+		if typeModifier == 0 {
+			// The type has no modifier.
+			typeModifier = -1
+		}
+		c.writeBuf.putInt32(typeModifier)
 		if formatCodes == nil {
 			c.writeBuf.putInt16(int16(formatText))
 		} else {
@@ -17,12 +17,17 @@
 package pgwire
 
 import (
+	"bufio"
+	"bytes"
 	"io"
 	"io/ioutil"
 	"net"
+	"reflect"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/base"
 	"github.com/cockroachdb/cockroach/sql"
+	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 	"github.com/cockroachdb/cockroach/util/metric"
 )
@@ -35,6 +40,19 @@ func makeTestV3Conn(c net.Conn) v3Conn {
 	)
 }
 
+// makeBufferedTestV3Conn returns a v3Conn whose messages are written to out
+// rather than to its connection, along with a function closing the
+// connection.
+func makeBufferedTestV3Conn(out *bytes.Buffer) (v3Conn, func()) {
+	w, r := net.Pipe()
+	c := makeTestV3Conn(r)
+	c.wr = bufio.NewWriter(out)
+	return c, func() {
+		_ = w.Close()
+		_ = r.Close()
+	}
+}
+
 // TestMaliciousInputs verifies that known malicious inputs sent to
 // a v3Conn don't crash the server.
 func TestMaliciousInputs(t *testing.T) {
@@ -89,3 +107,321 @@ func testMaliciousInput(t *testing.T, data []byte) {
 	v3Conn := makeTestV3Conn(r)
 	_ = v3Conn.serve(nil)
 }
+
+// TestSendSuspendedRows verifies that the rows of a suspended portal are sent
+// in batches limited by the row counts of the Execute messages.
+func TestSendSuspendedRows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var out bytes.Buffer
+	c, closeConn := makeBufferedTestV3Conn(&out)
+	defer closeConn()
+
+	var rows []sql.ResultRow
+	for i := 0; i < 3; i++ {
+		rows = append(rows, sql.ResultRow{Values: []parser.Datum{parser.NewDInt(parser.DInt(i))}})
+	}
+	portalMeta := &preparedPortalMeta{
+		suspended: &suspendedPortal{
+			result: sql.Result{Type: parser.Rows, PGTag: "SELECT", Rows: rows},
+		},
+	}
+
+	testCases := []struct {
+		limit    int
+		expected []serverMessageType
+		tag      string
+	}{
+		{2, []serverMessageType{serverMsgDataRow, serverMsgDataRow, serverMsgPortalSuspended}, ""},
+		{2, []serverMessageType{serverMsgDataRow, serverMsgCommandComplete}, "SELECT 1"},
+		{2, []serverMessageType{serverMsgCommandComplete}, "SELECT 0"},
+	}
+	for i, tc := range testCases {
+		out.Reset()
+		if err := c.sendSuspendedRows(portalMeta, tc.limit); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		rd := bufio.NewReader(&out)
+		var buf readBuffer
+		for _, expected := range tc.expected {
+			typ, _, err := buf.readTypedMsg(rd)
+			if err != nil {
+				t.Fatalf("%d: %s", i, err)
+			}
+			if serverMessageType(typ) != expected {
+				t.Fatalf("%d: expected %s, got %s", i, expected, serverMessageType(typ))
+			}
+		}
+		if tc.tag != "" {
+			if tag, err := buf.getString(); err != nil {
+				t.Fatal(err)
+			} else if tag != tc.tag {
+				t.Errorf("%d: expected tag %q, got %q", i, tc.tag, tag)
+			}
+		}
+		if n := rd.Buffered() + out.Len(); n != 0 {
+			t.Errorf("%d: %d unexpected bytes left", i, n)
+		}
+	}
+}
+
+// TestInterleavedSuspendedPortals verifies that several portals of a session
+// can be suspended at the same time and resumed in any order.
+func TestInterleavedSuspendedPortals(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var out bytes.Buffer
+	c, closeConn := makeBufferedTestV3Conn(&out)
+	defer closeConn()
+
+	makePortal := func(vals ...string) *preparedPortalMeta {
+		var rows []sql.ResultRow
+		for _, v := range vals {
+			rows = append(rows, sql.ResultRow{Values: []parser.Datum{parser.NewDString(v)}})
+		}
+		return &preparedPortalMeta{
+			suspended: &suspendedPortal{
+				result: sql.Result{Type: parser.Rows, PGTag: "SELECT", Rows: rows},
+			},
+		}
+	}
+	a := makePortal("a1", "a2", "a3")
+	b := makePortal("b1", "b2", "b3")
+
+	// readValues returns the values of the DataRow messages sent by the last
+	// call to sendSuspendedRows.
+	readValues := func() []string {
+		if err := c.wr.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		rd := bufio.NewReader(&out)
+		var vals []string
+		for {
+			var buf readBuffer
+			typ, _, err := buf.readTypedMsg(rd)
+			if err == io.EOF {
+				return vals
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if serverMessageType(typ) != serverMsgDataRow {
+				continue
+			}
+			if _, err := buf.getUint16(); err != nil {
+				t.Fatal(err)
+			}
+			n, err := buf.getUint32()
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := buf.getBytes(int(n))
+			if err != nil {
+				t.Fatal(err)
+			}
+			vals = append(vals, string(v))
+		}
+	}
+
+	testCases := []struct {
+		portal   *preparedPortalMeta
+		limit    int
+		expected []string
+	}{
+		{a, 1, []string{"a1"}},
+		{b, 2, []string{"b1", "b2"}},
+		{a, 1, []string{"a2"}},
+		{b, 2, []string{"b3"}},
+		{a, 0, []string{"a3"}},
+	}
+	for i, tc := range testCases {
+		if err := c.sendSuspendedRows(tc.portal, tc.limit); err != nil {
+			t.Fatal(err)
+		}
+		if vals := readValues(); !reflect.DeepEqual(vals, tc.expected) {
+			t.Errorf("%d: expected %v, got %v", i, tc.expected, vals)
+		}
+	}
+}
+
+// TestSendNotices verifies that the notices of a result are sent before the
+// result itself.
+func TestSendNotices(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var out bytes.Buffer
+	c, closeConn := makeBufferedTestV3Conn(&out)
+	defer closeConn()
+
+	results := sql.ResultList{{
+		Type:  parser.DDL,
+		PGTag: "DROP TABLE",
+		Notices: []sql.Notice{
+			{Severity: sql.NoticeSeverityNotice, Message: `table "a" does not exist, skipping`},
+			{Severity: sql.NoticeSeverityWarning, Message: "careful"},
+		},
+	}}
+	if err := c.sendResponse(results, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := bufio.NewReader(&out)
+	expected := []struct {
+		typ    serverMessageType
+		fields []string
+	}{
+		{serverMsgNoticeResponse, []string{"SNOTICE", "C00000", `Mtable "a" does not exist, skipping`}},
+		{serverMsgNoticeResponse, []string{"SWARNING", "C01000", "Mcareful"}},
+		{serverMsgCommandComplete, []string{"DROP TABLE"}},
+	}
+	for _, e := range expected {
+		var buf readBuffer
+		typ, _, err := buf.readTypedMsg(rd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if serverMessageType(typ) != e.typ {
+			t.Fatalf("expected %s, got %s", e.typ, serverMessageType(typ))
+		}
+		for _, field := range e.fields {
+			if s, err := buf.getString(); err != nil {
+				t.Fatal(err)
+			} else if s != field {
+				t.Errorf("expected %q, got %q", field, s)
+			}
+		}
+	}
+}
+
+// TestCancelRequest verifies that cancel requests are routed by their cancel
+// key and don't get a reply, and that only the requests with the secret of
+// the connection cancel its query.
+func TestCancelRequest(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s := MakeServer(&base.Context{Insecure: true}, &sql.Executor{}, metric.NewRegistry())
+
+	var out bytes.Buffer
+	c1, closeConn1 := makeBufferedTestV3Conn(&out)
+	defer closeConn1()
+	c2, closeConn2 := makeBufferedTestV3Conn(&out)
+	defer closeConn2()
+	key1, err := s.registerConn(&c1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := s.registerConn(&c2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key1.pid == key2.pid {
+		t.Fatalf("expected distinct process IDs, got %d twice", key1.pid)
+	}
+	if c1.cancelKey != key1 || c2.cancelKey != key2 {
+		t.Fatalf("expected connections to know their keys")
+	}
+
+	sendCancel := func(key cancelKey) {
+		cw, cr := net.Pipe()
+		defer cw.Close()
+		go func() {
+			var buf writeBuffer
+			buf.bytecount = metric.NewCounter()
+			buf.putInt32(16)
+			buf.putInt32(versionCancel)
+			buf.putInt32(key.pid)
+			buf.putInt32(key.secret)
+			_, _ = cw.Write(buf.wrapped.Bytes())
+		}()
+		if err := s.ServeConn(cr); err != nil {
+			t.Fatal(err)
+		}
+		// No reply is sent: the server side is done with the connection.
+		if err := cr.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkCancelled := func(expected1, expected2 bool) {
+		if c1.session.QueryCancelled() != expected1 || c2.session.QueryCancelled() != expected2 {
+			t.Fatalf("expected the queries to be cancelled: %t, %t; got %t, %t",
+				expected1, expected2, c1.session.QueryCancelled(), c2.session.QueryCancelled())
+		}
+	}
+	// A wrong secret or an unknown process ID doesn't cancel anything.
+	sendCancel(cancelKey{pid: key1.pid, secret: key1.secret + 1})
+	sendCancel(cancelKey{pid: key2.pid + 1, secret: key2.secret})
+	checkCancelled(false, false)
+	// The valid key only cancels the query of its connection.
+	sendCancel(key1)
+	checkCancelled(true, false)
+
+	s.unregisterConn(key1)
+	s.mu.Lock()
+	_, ok1 := s.mu.conns[key1]
+	_, ok2 := s.mu.conns[key2]
+	s.mu.Unlock()
+	if ok1 || !ok2 {
+		t.Fatalf("expected only %v to be registered", key2)
+	}
+}
+
+// TestSendRowDescriptionTypeModifier verifies that the type modifiers of
+// columns are described, and that -1 is sent for types without one.
+func TestSendRowDescriptionTypeModifier(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var out bytes.Buffer
+	c, closeConn := makeBufferedTestV3Conn(&out)
+	defer closeConn()
+
+	columns := []sql.ResultColumn{
+		{Name: "s", Typ: parser.TypeString, TypeModifier: 14},
+		{Name: "i", Typ: parser.TypeInt},
+	}
+	if err := c.sendRowDescription(columns, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf readBuffer
+	typ, _, err := buf.readTypedMsg(bufio.NewReader(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serverMessageType(typ) != serverMsgRowDescription {
+		t.Fatalf("expected %s, got %s", serverMsgRowDescription, serverMessageType(typ))
+	}
+	if n, err := buf.getUint16(); err != nil {
+		t.Fatal(err)
+	} else if int(n) != len(columns) {
+		t.Fatalf("expected %d columns, got %d", len(columns), n)
+	}
+	for i, expected := range []int32{14, -1} {
+		if name, err := buf.getString(); err != nil {
+			t.Fatal(err)
+		} else if name != columns[i].Name {
+			t.Fatalf("expected column %q, got %q", columns[i].Name, name)
+		}
+		// Skip the table OID, column attribute ID, type OID and type size.
+		if _, err := buf.getBytes(12); err != nil {
+			t.Fatal(err)
+		}
+		typeModifier, err := buf.getUint32()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int32(typeModifier) != expected {
+			t.Errorf("%s: expected type modifier %d, got %d", columns[i].Name, expected, int32(typeModifier))
+		}
+		if _, err := buf.getUint16(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
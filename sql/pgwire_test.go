@@ -1174,6 +1174,103 @@ func TestPrepareSyntax(t *testing.T) {
 	}
 }
 
+func TestPGWireSyntaxErrorPosition(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	pgURL, cleanupFn := sqlutils.PGUrl(t, s.ServingAddr(), security.RootUser, "TestPGWireSyntaxErrorPosition")
+	defer cleanupFn()
+
+	db, err := gosql.Open("postgres", pgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	checkErr := func(err error, expected string) {
+		pqErr, ok := err.(*pq.Error)
+		if !ok {
+			t.Fatalf("expected pq error, got %v", err)
+		}
+		if pqErr.Code != "42601" {
+			t.Errorf("expected code 42601, got %s", pqErr.Code)
+		}
+		if pqErr.Position != expected {
+			t.Errorf("expected position %q, got %q", expected, pqErr.Position)
+		}
+	}
+
+	_, err = db.Exec(`SELECT 1; SELECT 2 3`)
+	checkErr(err, "20")
+
+	_, err = db.Prepare(`SELECT * FROM`)
+	checkErr(err, "14")
+}
+
+// TestPGPreparedDescribe verifies that the columns of all statements which
+// return rows are described before their execution.
+func TestPGPreparedDescribe(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	pgURL, cleanupFn := sqlutils.PGUrl(t, s.ServingAddr(), security.RootUser, "TestPGPreparedDescribe")
+	defer cleanupFn()
+
+	db, err := gosql.Open("postgres", pgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE DATABASE d; CREATE TABLE d.t (s STRING(10))`); err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		query    string
+		expected []string
+	}{
+		{`VALUES (1, 2)`, []string{"column1", "column2"}},
+		{`(SELECT 1 AS a)`, []string{"a"}},
+		{`SELECT 1 AS a UNION SELECT 2`, []string{"a"}},
+		{`EXPLAIN SELECT 1`, []string{"Level", "Type", "Description"}},
+		{`SHOW SYNTAX`, []string{"SYNTAX"}},
+		{`INSERT INTO d.t VALUES ('a') RETURNING s`, []string{"s"}},
+	}
+	for _, d := range testData {
+		stmt, err := db.Prepare(d.query)
+		if err != nil {
+			t.Fatalf("%s: %v", d.query, err)
+		}
+		rows, err := stmt.Query()
+		if err != nil {
+			t.Fatalf("%s: %v", d.query, err)
+		}
+		cols, err := rows.Columns()
+		if err != nil {
+			t.Fatalf("%s: %v", d.query, err)
+		}
+		if !reflect.DeepEqual(cols, d.expected) {
+			t.Errorf("%s: expected columns %v, got %v", d.query, d.expected, cols)
+		}
+		for rows.Next() {
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatalf("%s: %v", d.query, err)
+		}
+		if err := rows.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 func TestPGWireOverUnixSocket(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
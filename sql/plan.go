@@ -236,22 +236,48 @@ func (p *planner) newPlan(stmt parser.Statement, desiredTypes []parser.Datum, au
 	switch n := stmt.(type) {
 	case *parser.AlterTable:
 		return p.AlterTable(n)
+	case *parser.AlterUser:
+		return p.AlterUser(n)
 	case *parser.BeginTransaction:
 		return p.BeginTransaction(n)
+	case *parser.CommentOnColumn:
+		return p.CommentOnColumn(n)
+	case *parser.CommentOnDatabase:
+		return p.CommentOnDatabase(n)
+	case *parser.CommentOnIndex:
+		return p.CommentOnIndex(n)
+	case *parser.CommentOnTable:
+		return p.CommentOnTable(n)
 	case *parser.CreateDatabase:
 		return p.CreateDatabase(n)
 	case *parser.CreateIndex:
 		return p.CreateIndex(n)
+	case *parser.CreatePolicy:
+		return p.CreatePolicy(n)
+	case *parser.CreateStatistics:
+		return p.CreateStatistics(n)
 	case *parser.CreateTable:
 		return p.CreateTable(n)
+	case *parser.CreateMaterializedView:
+		return p.CreateMaterializedView(n)
+	case *parser.CreateTrigger:
+		return p.CreateTrigger(n)
+	case *parser.CreateUser:
+		return p.CreateUser(n)
 	case *parser.Delete:
 		return p.Delete(n, desiredTypes, autoCommit)
 	case *parser.DropDatabase:
 		return p.DropDatabase(n)
 	case *parser.DropIndex:
 		return p.DropIndex(n)
+	case *parser.DropPolicy:
+		return p.DropPolicy(n)
 	case *parser.DropTable:
 		return p.DropTable(n)
+	case *parser.DropTrigger:
+		return p.DropTrigger(n)
+	case *parser.DropUser:
+		return p.DropUser(n)
 	case *parser.Explain:
 		return p.Explain(n, autoCommit)
 	case *parser.Grant:
@@ -260,22 +286,28 @@ func (p *planner) newPlan(stmt parser.Statement, desiredTypes []parser.Datum, au
 		return p.Insert(n, desiredTypes, autoCommit)
 	case *parser.ParenSelect:
 		return p.newPlan(n.Select, desiredTypes, autoCommit)
+	case *parser.RefreshMaterializedView:
+		return p.RefreshMaterializedView(n)
 	case *parser.RenameColumn:
 		return p.RenameColumn(n)
 	case *parser.RenameDatabase:
-		return p.RenameDatabase(n)
+		return p.RenameDatabase(n, autoCommit)
 	case *parser.RenameIndex:
 		return p.RenameIndex(n)
 	case *parser.RenameTable:
 		return p.RenameTable(n)
 	case *parser.Revoke:
 		return p.Revoke(n)
+	case *parser.Scrub:
+		return p.Scrub(n)
 	case *parser.Select:
 		return p.Select(n, desiredTypes, autoCommit)
 	case *parser.SelectClause:
 		return p.SelectClause(n, nil, nil, desiredTypes, publicColumns)
 	case *parser.Set:
 		return p.Set(n)
+	case *parser.SetClusterSetting:
+		return p.SetClusterSetting(n)
 	case *parser.SetTimeZone:
 		return p.SetTimeZone(n)
 	case *parser.SetTransaction:
@@ -284,8 +316,12 @@ func (p *planner) newPlan(stmt parser.Statement, desiredTypes []parser.Datum, au
 		return p.SetDefaultIsolation(n)
 	case *parser.Show:
 		return p.Show(n)
+	case *parser.ShowClusterSetting:
+		return p.ShowClusterSetting(n)
 	case *parser.ShowCreateTable:
 		return p.ShowCreateTable(n)
+	case *parser.ShowEvents:
+		return p.ShowEvents(n)
 	case *parser.ShowColumns:
 		return p.ShowColumns(n)
 	case *parser.ShowDatabases:
@@ -294,10 +330,18 @@ func (p *planner) newPlan(stmt parser.Statement, desiredTypes []parser.Datum, au
 		return p.ShowGrants(n)
 	case *parser.ShowIndex:
 		return p.ShowIndex(n)
+	case *parser.ShowIndexUsage:
+		return p.ShowIndexUsage(n)
+	case *parser.ShowStatistics:
+		return p.ShowStatistics(n)
+	case *parser.ShowConsistency:
+		return p.ShowConsistency(n)
 	case *parser.ShowConstraints:
 		return p.ShowConstraints(n)
 	case *parser.ShowTables:
 		return p.ShowTables(n)
+	case *parser.ShowTriggers:
+		return p.ShowTriggers(n)
 	case *parser.Truncate:
 		return p.Truncate(n)
 	case *parser.UnionClause:
@@ -315,16 +359,24 @@ func (p *planner) prepare(stmt parser.Statement) (planNode, error) {
 	switch n := stmt.(type) {
 	case *parser.Delete:
 		return p.Delete(n, nil, false)
+	case *parser.Explain:
+		return p.Explain(n, false)
 	case *parser.Insert:
 		return p.Insert(n, nil, false)
+	case *parser.ParenSelect:
+		return p.prepare(n.Select)
 	case *parser.Select:
 		return p.Select(n, nil, false)
 	case *parser.SelectClause:
 		return p.SelectClause(n, nil, nil, nil, publicColumns)
 	case *parser.Show:
 		return p.Show(n)
+	case *parser.ShowClusterSetting:
+		return p.ShowClusterSetting(n)
 	case *parser.ShowCreateTable:
 		return p.ShowCreateTable(n)
+	case *parser.ShowEvents:
+		return p.ShowEvents(n)
 	case *parser.ShowColumns:
 		return p.ShowColumns(n)
 	case *parser.ShowDatabases:
@@ -333,15 +385,27 @@ func (p *planner) prepare(stmt parser.Statement) (planNode, error) {
 		return p.ShowGrants(n)
 	case *parser.ShowIndex:
 		return p.ShowIndex(n)
+	case *parser.ShowIndexUsage:
+		return p.ShowIndexUsage(n)
+	case *parser.ShowStatistics:
+		return p.ShowStatistics(n)
+	case *parser.ShowConsistency:
+		return p.ShowConsistency(n)
 	case *parser.ShowConstraints:
 		return p.ShowConstraints(n)
 	case *parser.ShowTables:
 		return p.ShowTables(n)
+	case *parser.ShowTriggers:
+		return p.ShowTriggers(n)
+	case *parser.UnionClause:
+		return p.UnionClause(n, nil, false)
 	case *parser.Update:
 		return p.Update(n, nil, false)
+	case *parser.ValuesClause:
+		return p.ValuesClause(n, nil)
 	default:
-		// Other statement types do not support placeholders so there is no need
-		// for any special handling here.
+		// Other statement types do not return rows, nor do they support
+		// placeholders, so there is no need for any special handling here.
 		return nil, nil
 	}
 }
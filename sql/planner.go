@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/config"
 	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/pkg/errors"
 )
@@ -63,7 +64,22 @@ type planner struct {
 	collectSubqueryPlansVisitor collectSubqueryPlansVisitor
 	qnameVisitor                qnameVisitor
 
+	// Avoid allocations by reusing the plan nodes of previous statements.
+	alloc planNodeAlloc
+
 	execCtx *ExecutorContext
+
+	// notices accumulates the notices of the statement being executed.
+	notices []Notice
+
+	// triggerDepth is the number of triggers being executed whose statements
+	// are executed by this planner.
+	triggerDepth int
+
+	// fkVisited holds the referenced rows whose referencing rows were written
+	// by the foreign key actions of the statement being executed. It is shared
+	// by the planners executing the nested actions.
+	fkVisited map[fkVisitedRow]struct{}
 }
 
 // makePlanner creates a new planner instances, referencing a dummy Session.
@@ -171,7 +187,14 @@ func (p *planner) resetContexts() {
 	p.semaCtx.Location = &p.session.Location
 
 	p.evalCtx = parser.EvalContext{
-		Location: &p.session.Location,
+		Location:  &p.session.Location,
+		Comments:  p,
+		Retrier:   p,
+		Sizes:     p,
+		Keys:      p,
+		Profiler:  p,
+		Sequences: p,
+		Userfiles: p,
 	}
 }
 
@@ -234,6 +257,31 @@ func (p *planner) queryRow(sql string, args ...interface{}) (parser.DTuple, erro
 	return values, nil
 }
 
+// queryRows executes a SQL query string and returns all the rows it produces.
+func (p *planner) queryRows(sql string, args ...interface{}) ([]parser.DTuple, error) {
+	plan, err := p.query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.Start(); err != nil {
+		return nil, err
+	}
+	var rows []parser.DTuple
+	for {
+		next, err := plan.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !next {
+			return rows, nil
+		}
+		values := plan.Values()
+		row := make(parser.DTuple, len(values))
+		copy(row, values)
+		rows = append(rows, row)
+	}
+}
+
 // exec implements the queryRunner interface.
 func (p *planner) exec(sql string, args ...interface{}) (int, error) {
 	plan, err := p.query(sql, args...)
@@ -243,7 +291,16 @@ func (p *planner) exec(sql string, args ...interface{}) (int, error) {
 	if err := plan.Start(); err != nil {
 		return 0, err
 	}
-	return countRowsAffected(plan)
+	return countRowsAffected(p, plan)
+}
+
+// checkCancelled returns an error if the client requested the cancellation of
+// the request being executed.
+func (p *planner) checkCancelled() error {
+	if p.session.QueryCancelled() {
+		return sqlbase.NewQueryCanceledError()
+	}
+	return nil
 }
 
 // setTestingVerifyMetadata implements the queryRunner interface.
@@ -305,3 +362,68 @@ func (p *planner) fillFKTableMap(m TablesByID) error {
 	}
 	return nil
 }
+
+const planNodeAllocSize = 4 // Arbitrary, could be tuned.
+
+// planNodeAlloc provides storage for the plan nodes built by most statements,
+// so that a planner can reuse it across statements instead of allocating them
+// every time. The nodes are only valid until reset, which is called once a
+// statement's results have been collected; any further node is allocated
+// normally.
+type planNodeAlloc struct {
+	scanNodes         [planNodeAllocSize]scanNode
+	numScanNodes      int
+	selectNodes       [planNodeAllocSize]selectNode
+	numSelectNodes    int
+	selectTopNodes    [planNodeAllocSize]selectTopNode
+	numSelectTopNodes int
+}
+
+// newScanNode allocates a scanNode.
+func (a *planNodeAlloc) newScanNode(v scanNode) *scanNode {
+	if a.numScanNodes == len(a.scanNodes) {
+		return &v
+	}
+	r := &a.scanNodes[a.numScanNodes]
+	a.numScanNodes++
+	*r = v
+	return r
+}
+
+// newSelectNode allocates a selectNode.
+func (a *planNodeAlloc) newSelectNode(v selectNode) *selectNode {
+	if a.numSelectNodes == len(a.selectNodes) {
+		return &v
+	}
+	r := &a.selectNodes[a.numSelectNodes]
+	a.numSelectNodes++
+	*r = v
+	return r
+}
+
+// newSelectTopNode allocates a selectTopNode.
+func (a *planNodeAlloc) newSelectTopNode(v selectTopNode) *selectTopNode {
+	if a.numSelectTopNodes == len(a.selectTopNodes) {
+		return &v
+	}
+	r := &a.selectTopNodes[a.numSelectTopNodes]
+	a.numSelectTopNodes++
+	*r = v
+	return r
+}
+
+// reset makes the storage of all the nodes allocated so far available again.
+// The nodes are cleared so that they don't keep the data they reference (e.g.
+// fetched rows) alive.
+func (a *planNodeAlloc) reset() {
+	for i := 0; i < a.numScanNodes; i++ {
+		a.scanNodes[i] = scanNode{}
+	}
+	for i := 0; i < a.numSelectNodes; i++ {
+		a.selectNodes[i] = selectNode{}
+	}
+	for i := 0; i < a.numSelectTopNodes; i++ {
+		a.selectTopNodes[i] = selectTopNode{}
+	}
+	a.numScanNodes, a.numSelectNodes, a.numSelectTopNodes = 0, 0, 0
+}
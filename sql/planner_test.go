@@ -0,0 +1,89 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/tracing"
+)
+
+// runBenchmarkPlanNodeAlloc measures the planning and execution of a point
+// lookup by a planner which reuses its plan nodes across statements, as the
+// executor does, or by one which allocates them every time.
+func runBenchmarkPlanNodeAlloc(b *testing.B, reuse bool) {
+	defer tracing.Disable()()
+	s, db, kvDB := serverutils.StartServer(b, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	leaseManager := s.LeaseManager().(*LeaseManager)
+
+	if _, err := db.Exec(`
+CREATE DATABASE bench;
+CREATE TABLE bench.kv (k INT PRIMARY KEY, v INT);
+INSERT INTO bench.kv VALUES (1, 1);
+`); err != nil {
+		b.Fatal(err)
+	}
+	stmt, err := parser.ParseOneTraditional(`SELECT v FROM bench.kv WHERE k = 1`)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err := kvDB.Txn(func(txn *client.Txn) error {
+		p := makeInternalPlanner(txn, security.RootUser)
+		p.leaseMgr = leaseManager
+		defer p.releaseLeases()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			plan, err := p.makePlan(stmt, false)
+			if err != nil {
+				return err
+			}
+			if err := plan.Start(); err != nil {
+				return err
+			}
+			for {
+				next, err := plan.Next()
+				if err != nil {
+					return err
+				}
+				if !next {
+					break
+				}
+			}
+			if reuse {
+				p.alloc.reset()
+			}
+		}
+		b.StopTimer()
+		return nil
+	}); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkPlanNodeAlloc_Alloc(b *testing.B) {
+	runBenchmarkPlanNodeAlloc(b, false)
+}
+
+func BenchmarkPlanNodeAlloc_Reuse(b *testing.B) {
+	runBenchmarkPlanNodeAlloc(b, true)
+}
@@ -0,0 +1,147 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// CreatePolicy adds a row-level security policy to a table. The rows of the
+// table read by SELECT, UPDATE and DELETE statements executed by users other
+// than root are restricted to those for which the expression of every policy
+// of the table is true. These users can't UPSERT or INSERT ... ON CONFLICT
+// into the table, as the conflicting rows aren't read through a scan.
+// Privileges: CREATE on table.
+//   Notes: postgres requires ownership of the table, and only enforces the
+//          policies once row level security is enabled on the table.
+func (p *planner) CreatePolicy(n *parser.CreatePolicy) (planNode, error) {
+	tableDesc, err := p.getTableDescToAlter(n.Table)
+	if err != nil {
+		return nil, err
+	}
+	if i := findPolicy(tableDesc, string(n.Name)); i >= 0 {
+		return nil, fmt.Errorf("policy %q for table %q already exists", n.Name, tableDesc.Name)
+	}
+
+	if _, err := parser.SimpleVisit(n.Expr, func(expr parser.Expr) (error, bool, parser.Expr) {
+		if _, ok := expr.(*parser.Subquery); ok {
+			return errors.New("subqueries are not allowed in POLICY expressions"), false, expr
+		}
+		return nil, true, expr
+	}); err != nil {
+		return nil, err
+	}
+	if p.parser.AggregateInExpr(n.Expr) {
+		return nil, errors.New("aggregate functions are not allowed in POLICY expressions")
+	}
+	// The expression is analyzed the same way when the policy is enforced.
+	sourceInfo := newSourceInfoForSingleTable(tableDesc.Name, makeResultColumns(tableDesc.Columns))
+	if _, err := p.analyzeExpr(n.Expr, multiSourceInfo{sourceInfo}, make(qvalMap),
+		parser.TypeBool, true, "POLICY"); err != nil {
+		return nil, err
+	}
+
+	tableDesc.Policies = append(tableDesc.Policies, sqlbase.TableDescriptor_Policy{
+		Name: string(n.Name),
+		Expr: n.Expr.String(),
+	})
+	if err := p.writeAlteredTableDesc(tableDesc); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// DropPolicy removes a row-level security policy from a table.
+// Privileges: CREATE on table.
+//   Notes: postgres requires ownership of the table.
+func (p *planner) DropPolicy(n *parser.DropPolicy) (planNode, error) {
+	tableDesc, err := p.getTableDescToAlter(n.Table)
+	if err != nil {
+		return nil, err
+	}
+	i := findPolicy(tableDesc, string(n.Name))
+	if i < 0 {
+		if n.IfExists {
+			return &emptyNode{}, nil
+		}
+		return nil, fmt.Errorf("policy %q for table %q does not exist", n.Name, tableDesc.Name)
+	}
+
+	tableDesc.Policies = append(tableDesc.Policies[:i], tableDesc.Policies[i+1:]...)
+	if err := p.writeAlteredTableDesc(tableDesc); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// findPolicy returns the index of the policy of a table with the given name,
+// or -1 if there is none.
+func findPolicy(tableDesc *sqlbase.TableDescriptor, name string) int {
+	normName := sqlbase.NormalizeName(name)
+	for i, policy := range tableDesc.Policies {
+		if sqlbase.NormalizeName(policy.Name) == normName {
+			return i
+		}
+	}
+	return -1
+}
+
+// policiesApply returns whether the policies of a table restrict the rows
+// the session user can access.
+func (p *planner) policiesApply(tableDesc *sqlbase.TableDescriptor) bool {
+	return len(tableDesc.Policies) > 0 && p.session.User != security.RootUser
+}
+
+// initPolicies sets the filter of the scan to the conjunction of the
+// expressions of the policies of the table, unless the session user is
+// exempt from them. The filter is kept when the filter of a query is pushed
+// down into the scan (see selectNode.expandPlan).
+func (n *scanNode) initPolicies(p *planner) error {
+	if !p.policiesApply(n.desc) {
+		return nil
+	}
+
+	exprStrings := make([]string, len(n.desc.Policies))
+	for i, policy := range n.desc.Policies {
+		exprStrings[i] = policy.Expr
+	}
+	exprs, err := parser.ParseExprsTraditional(exprStrings)
+	if err != nil {
+		return err
+	}
+
+	sourceInfo := newSourceInfoForSingleTable(n.desc.Name, n.resultColumns)
+	convFunc := func(expr parser.VariableExpr) (bool, parser.VariableExpr) {
+		qval := expr.(*qvalue)
+		return true, n.filterVars.IndexedVar(qval.colRef.colIdx)
+	}
+	filters := make(parser.TypedExprs, len(exprs))
+	for i, raw := range exprs {
+		typedExpr, err := p.analyzeExpr(raw, multiSourceInfo{sourceInfo}, make(qvalMap),
+			parser.TypeBool, true, "POLICY")
+		if err != nil {
+			return err
+		}
+		filters[i] = exprConvertVars(typedExpr, convFunc)
+	}
+	n.policyFilter = joinAndExprs(filters)
+	n.filter = n.policyFilter
+	return nil
+}
@@ -141,6 +141,11 @@ func (pp PreparedPortals) Exists(name string) bool {
 // PreparedStatement, binding the statement using the given QueryArguments.
 func (pp PreparedPortals) New(name string, stmt *PreparedStatement, qargs parser.QueryArguments,
 ) *PreparedPortal {
+	// A portal being replaced (only the unnamed portal can be) no longer belongs
+	// to its statement, and must not be removed when that statement is.
+	if old, ok := pp.Get(name); ok {
+		delete(old.Stmt.portalNames, name)
+	}
 	stmt.portalNames[name] = struct{}{}
 	portal := &PreparedPortal{
 		Stmt:  stmt,
@@ -0,0 +1,75 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestPreparedPortals verifies that several portals can be bound at the same
+// time, and that rebinding the unnamed portal detaches it from its previous
+// statement.
+func TestPreparedPortals(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s := &Session{}
+	s.PreparedStatements = makePreparedStatements(s)
+	s.PreparedPortals = makePreparedPortals(s)
+	newStmt := func(name string) *PreparedStatement {
+		stmt := &PreparedStatement{Query: name, portalNames: make(map[string]struct{})}
+		s.PreparedStatements.stmts[name] = stmt
+		return stmt
+	}
+	a := newStmt("a")
+	b := newStmt("b")
+
+	checkPortal := func(name string, expected *PreparedStatement) {
+		portal, ok := s.PreparedPortals.Get(name)
+		if expected == nil {
+			if ok {
+				t.Fatalf("expected no portal %q, found one bound to %q", name, portal.Stmt.Query)
+			}
+			return
+		}
+		if !ok {
+			t.Fatalf("expected portal %q to exist", name)
+		}
+		if portal.Stmt != expected {
+			t.Fatalf("expected portal %q to be bound to %q, found %q",
+				name, expected.Query, portal.Stmt.Query)
+		}
+	}
+
+	s.PreparedPortals.New("pa", a, nil)
+	s.PreparedPortals.New("pb", b, nil)
+	s.PreparedPortals.New("", a, nil)
+	checkPortal("pa", a)
+	checkPortal("pb", b)
+	checkPortal("", a)
+
+	// Rebinding the unnamed portal to b detaches it from a: deleting a only
+	// removes the portals still bound to it.
+	s.PreparedPortals.New("", b, nil)
+	s.PreparedStatements.Delete("a")
+	checkPortal("pa", nil)
+	checkPortal("pb", b)
+	checkPortal("", b)
+
+	s.PreparedStatements.Delete("b")
+	checkPortal("pb", nil)
+	checkPortal("", nil)
+}
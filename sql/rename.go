@@ -39,7 +39,7 @@ var (
 // Privileges: security.RootUser user.
 //   Notes: postgres requires superuser, db owner, or "CREATEDB".
 //          mysql >= 5.1.23 does not allow database renames.
-func (p *planner) RenameDatabase(n *parser.RenameDatabase) (planNode, error) {
+func (p *planner) RenameDatabase(n *parser.RenameDatabase, autoCommit bool) (planNode, error) {
 	if n.Name == "" || n.NewName == "" {
 		return nil, errEmptyDatabaseName
 	}
@@ -58,6 +58,12 @@ func (p *planner) RenameDatabase(n *parser.RenameDatabase) (planNode, error) {
 		return &emptyNode{}, nil
 	}
 
+	// The remaining statements of an explicit transaction would resolve names
+	// against a database which no longer exists.
+	if !autoCommit && string(n.Name) == p.session.Database {
+		return nil, fmt.Errorf("cannot rename the current database %q inside a transaction", string(n.Name))
+	}
+
 	// Now update the nameMetadataKey and the descriptor.
 	descKey := sqlbase.MakeDescMetadataKey(dbDesc.GetID())
 	dbDesc.SetName(string(n.NewName))
@@ -82,6 +88,25 @@ func (p *planner) RenameDatabase(n *parser.RenameDatabase) (planNode, error) {
 		}
 		return nil, err
 	}
+	// The old name may be reused by a new database before the cache is
+	// refreshed from the gossiped system config.
+	p.databaseCache.deleteID(string(n.Name))
+
+	// Log Rename Database event. This is an auditable log event and is
+	// recorded in the same transaction as the database descriptor update.
+	if err := MakeEventLogger(p.leaseMgr).InsertEventRecord(p.txn,
+		EventLogRenameDatabase,
+		int32(descID),
+		int32(p.evalCtx.NodeID),
+		struct {
+			DatabaseName    string
+			NewDatabaseName string
+			Statement       string
+			User            string
+		}{n.Name.String(), n.NewName.String(), n.String(), p.session.User},
+	); err != nil {
+		return nil, err
+	}
 
 	p.setTestingVerifyMetadata(func(systemConfig config.SystemConfig) error {
 		if err := expectDescriptorID(systemConfig, newKey, descID); err != nil {
@@ -102,16 +127,22 @@ func (p *planner) RenameDatabase(n *parser.RenameDatabase) (planNode, error) {
 //          mysql requires ALTER, DROP on the original table, and CREATE, INSERT
 //          on the new table (and does not copy privileges over).
 func (p *planner) RenameTable(n *parser.RenameTable) (planNode, error) {
-	if err := n.NewName.NormalizeTableName(p.session.Database); err != nil {
+	if err := p.normalizeTableName(n.Name); err != nil {
 		return nil, err
 	}
 
-	if n.NewName.Table() == "" {
-		return nil, errEmptyTableName
+	// Temporary tables are renamed within the session's temporary database.
+	if n.Name.Database() == p.session.tempDatabase {
+		if err := n.NewName.NormalizeTableName(p.session.tempDatabase); err != nil {
+			return nil, err
+		}
+		p.session.tempTables[sqlbase.NormalizeName(n.NewName.Table())] = struct{}{}
+	} else if err := n.NewName.NormalizeTableName(p.session.Database); err != nil {
+		return nil, err
 	}
 
-	if err := n.Name.NormalizeTableName(p.session.Database); err != nil {
-		return nil, err
+	if n.NewName.Table() == "" {
+		return nil, errEmptyTableName
 	}
 
 	dbDesc, err := p.mustGetDatabaseDesc(n.Name.Database())
@@ -132,7 +163,7 @@ func (p *planner) RenameTable(n *parser.RenameTable) (planNode, error) {
 			return &emptyNode{}, nil
 		}
 		// Key does not exist, but we want it to: error out.
-		return nil, fmt.Errorf("table %q does not exist", n.Name.Table())
+		return nil, sqlbase.NewUndefinedTableError(n.Name.Table())
 	}
 
 	targetDbDesc, err := p.mustGetDatabaseDesc(n.NewName.Database())
@@ -223,7 +254,7 @@ func (p *planner) RenameIndex(n *parser.RenameIndex) (planNode, error) {
 		return nil, errEmptyIndexName
 	}
 
-	if err := n.Index.Table.NormalizeTableName(p.session.Database); err != nil {
+	if err := p.normalizeTableName(n.Index.Table); err != nil {
 		return nil, err
 	}
 
@@ -286,7 +317,7 @@ func (p *planner) RenameColumn(n *parser.RenameColumn) (planNode, error) {
 		return nil, errEmptyColumnName
 	}
 
-	if err := n.Table.NormalizeTableName(p.session.Database); err != nil {
+	if err := p.normalizeTableName(n.Table); err != nil {
 		return nil, err
 	}
 
@@ -307,7 +338,7 @@ func (p *planner) RenameColumn(n *parser.RenameColumn) (planNode, error) {
 			return &emptyNode{}, nil
 		}
 		// Key does not exist, but we want it to: error out.
-		return nil, fmt.Errorf("table %q does not exist", n.Table.Table())
+		return nil, sqlbase.NewUndefinedTableError(n.Table.Table())
 	}
 
 	tableDesc, err := p.mustGetTableDesc(n.Table)
@@ -373,6 +404,23 @@ func (p *planner) RenameColumn(n *parser.RenameColumn) (planNode, error) {
 			tableDesc.Checks[i].Expr = after
 		}
 	}
+	for i := range tableDesc.Columns {
+		col := &tableDesc.Columns[i]
+		if col.ComputedExpr == nil {
+			continue
+		}
+		raw, err := parser.ParseExprTraditional(*col.ComputedExpr)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := parser.SimpleVisit(raw, preFn)
+		if err != nil {
+			return nil, err
+		}
+		if after := expr.String(); after != *col.ComputedExpr {
+			col.ComputedExpr = &after
+		}
+	}
 	// Rename the column in the indexes.
 	tableDesc.RenameColumn(column.ID, newColName)
 	column.Name = newColName
@@ -83,7 +83,9 @@ func (p *planner) makeReturningHelper(
 			return returningHelper{}, err
 		}
 		rh.exprs = append(rh.exprs, typedExpr)
-		rh.columns = append(rh.columns, ResultColumn{Name: outputName, Typ: typedExpr.ReturnType()})
+		rh.columns = append(rh.columns, ResultColumn{
+			Name: outputName, Typ: typedExpr.ReturnType(), TypeModifier: renderTypeModifier(typedExpr),
+		})
 	}
 	return rh, nil
 }
@@ -42,7 +42,7 @@ const (
 type rowHelper struct {
 	tableDesc    *sqlbase.TableDescriptor
 	indexes      []sqlbase.IndexDescriptor
-	indexEntries []sqlbase.IndexEntry
+	indexEntries [][]sqlbase.IndexEntry
 
 	// Computed and cached.
 	primaryIndexKeyPrefix []byte
@@ -57,7 +57,7 @@ func (rh *rowHelper) encodeIndexes(
 	colIDtoRowIndex map[sqlbase.ColumnID]int, values []parser.Datum,
 ) (
 	primaryIndexKey []byte,
-	secondaryIndexEntries []sqlbase.IndexEntry,
+	secondaryIndexEntries [][]sqlbase.IndexEntry,
 	err error,
 ) {
 	if rh.primaryIndexKeyPrefix == nil {
@@ -82,11 +82,11 @@ func (rh *rowHelper) encodeIndexes(
 func (rh *rowHelper) encodeSecondaryIndexes(
 	colIDtoRowIndex map[sqlbase.ColumnID]int, values []parser.Datum,
 ) (
-	secondaryIndexEntries []sqlbase.IndexEntry,
+	secondaryIndexEntries [][]sqlbase.IndexEntry,
 	err error,
 ) {
 	if len(rh.indexEntries) != len(rh.indexes) {
-		rh.indexEntries = make([]sqlbase.IndexEntry, len(rh.indexes))
+		rh.indexEntries = make([][]sqlbase.IndexEntry, len(rh.indexes))
 	}
 	err = sqlbase.EncodeSecondaryIndexes(
 		rh.tableDesc, rh.indexes, colIDtoRowIndex, values, rh.indexEntries)
@@ -205,7 +205,8 @@ func insertPutFn(b *client.Batch, key *roachpb.Key, value *roachpb.Value) {
 }
 
 // insertRow adds to the batch the kv operations necessary to insert a table row
-// with the given values.
+// with the given values. The FK checks for the row are queued in ri.fks and
+// must be run before the batch.
 func (ri *rowInserter) insertRow(b *client.Batch, values []parser.Datum, ignoreConflicts bool) error {
 	if len(values) != len(ri.insertCols) {
 		return errors.Errorf("got %d values but expected %d", len(values), len(ri.insertCols))
@@ -318,9 +319,15 @@ func (ri *rowInserter) insertRow(b *client.Batch, values []parser.Datum, ignoreC
 		ri.key = nil
 	}
 
-	for i := range secondaryIndexEntries {
-		e := &secondaryIndexEntries[i]
-		putFn(b, &e.Key, &e.Value)
+	for i, entries := range secondaryIndexEntries {
+		fn := putFn
+		if isReplacedPrimaryIndex(&ri.helper.indexes[i]) {
+			fn = insertPutFn
+		}
+		for j := range entries {
+			e := &entries[j]
+			fn(b, &e.Key, &e.Value)
+		}
 	}
 
 	return nil
@@ -345,11 +352,27 @@ type rowUpdater struct {
 	marshalled      []roachpb.Value
 	newValues       []parser.Datum
 	key             roachpb.Key
-	indexEntriesBuf []sqlbase.IndexEntry
+	indexEntriesBuf [][]sqlbase.IndexEntry
 	valueBuf        []byte
 	value           roachpb.Value
 }
 
+// updatesIndexKeys returns whether the update changes the keys of the rows in
+// the primary index or in a secondary index.
+func (ru *rowUpdater) updatesIndexKeys() bool {
+	if ru.primaryKeyColChange {
+		return true
+	}
+	for _, index := range ru.helper.indexes {
+		for _, id := range index.ColumnIDs {
+			if _, ok := ru.updateColIDtoRowIndex[id]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type rowUpdaterType int
 
 const (
@@ -394,6 +417,11 @@ func makeRowUpdater(
 			// Only update columns.
 			return false
 		}
+		// An index written with the encoding of a primary index stores all
+		// the columns of the rows.
+		if index.PrimaryEncoding {
+			return true
+		}
 		// If the primary key changed, we need to update all of them.
 		if primaryKeyColChange {
 			return true
@@ -499,6 +527,15 @@ func makeRowUpdater(
 				}
 			}
 		}
+		if hasPrimaryEncodedIndex(indexes) {
+			for _, fam := range tableDesc.Families {
+				for _, colID := range fam.ColumnIDs {
+					if err := maybeAddCol(colID); err != nil {
+						return rowUpdater{}, err
+					}
+				}
+			}
+		}
 	}
 
 	var err error
@@ -514,6 +551,9 @@ func makeRowUpdater(
 // The row corresponding to oldValues is updated with the ones in updateValues.
 // Note that updateValues only contains the ones that are changing.
 //
+// The FK checks for the row are queued in ru.fks and must be run before the
+// batch.
+//
 // The return value is only good until the next call to UpdateRow.
 func (ru *rowUpdater) updateRow(
 	b *client.Batch,
@@ -535,8 +575,13 @@ func (ru *rowUpdater) updateRow(
 	// The secondary index entries returned by rowHelper.encodeIndexes are only
 	// valid until the next call to encodeIndexes. We need to copy them so that
 	// we can compare against the new secondary index entries.
-	secondaryIndexEntries = append(ru.indexEntriesBuf[:0], secondaryIndexEntries...)
-	ru.indexEntriesBuf = secondaryIndexEntries
+	if len(ru.indexEntriesBuf) != len(secondaryIndexEntries) {
+		ru.indexEntriesBuf = make([][]sqlbase.IndexEntry, len(secondaryIndexEntries))
+	}
+	for i, entries := range secondaryIndexEntries {
+		ru.indexEntriesBuf[i] = append(ru.indexEntriesBuf[i][:0], entries...)
+	}
+	secondaryIndexEntries = ru.indexEntriesBuf
 
 	// Check that the new value types match the column types. This needs to
 	// happen before index encoding because certain datum types (i.e. tuple)
@@ -554,7 +599,7 @@ func (ru *rowUpdater) updateRow(
 	}
 
 	rowPrimaryKeyChanged := false
-	var newSecondaryIndexEntries []sqlbase.IndexEntry
+	var newSecondaryIndexEntries [][]sqlbase.IndexEntry
 	if ru.primaryKeyColChange {
 		var newPrimaryIndexKey []byte
 		newPrimaryIndexKey, newSecondaryIndexEntries, err =
@@ -576,7 +621,7 @@ func (ru *rowUpdater) updateRow(
 			return nil, err
 		}
 		for i := range newSecondaryIndexEntries {
-			if !bytes.Equal(newSecondaryIndexEntries[i].Key, secondaryIndexEntries[i].Key) {
+			if !indexEntryKeysEqual(newSecondaryIndexEntries[i], secondaryIndexEntries[i]) {
 				if err := ru.fks.checkIdx(ru.helper.indexes[i].ID, oldValues, ru.newValues); err != nil {
 					return nil, err
 				}
@@ -692,32 +737,107 @@ func (ru *rowUpdater) updateRow(
 		ru.key = nil
 	}
 
-	// Update secondary indexes.
-	for i, newSecondaryIndexEntry := range newSecondaryIndexEntries {
-		secondaryIndexEntry := secondaryIndexEntries[i]
-		secondaryKeyChanged := !bytes.Equal(newSecondaryIndexEntry.Key, secondaryIndexEntry.Key)
-		if secondaryKeyChanged {
-			if err := ru.fks.checkIdx(ru.helper.indexes[i].ID, oldValues, ru.newValues); err != nil {
-				return nil, err
-			}
+	// Update secondary indexes. Only the keys which changed are written, as an
+	// inverted index can keep some of the keys of a row when its value changes.
+	for i, newEntries := range newSecondaryIndexEntries {
+		oldEntries := secondaryIndexEntries[i]
+		// The values of an index written with the encoding of a primary index
+		// change along with the ones of the row.
+		primaryEncoded := ru.helper.indexes[i].PrimaryEncoding
+		if !primaryEncoded && indexEntryKeysEqual(newEntries, oldEntries) {
+			continue
+		}
+		if err := ru.fks.checkIdx(ru.helper.indexes[i].ID, oldValues, ru.newValues); err != nil {
+			return nil, err
+		}
 
+		_, deleteOnly := ru.deleteOnlyIndex[i]
+		for _, oldEntry := range oldEntries {
+			// The entries of an index written with the encoding of a primary
+			// index which is not updated would keep stale values.
+			if containsIndexEntryKey(newEntries, oldEntry.Key) && !(primaryEncoded && deleteOnly) {
+				continue
+			}
 			if log.V(2) {
-				log.Infof("Del %s", secondaryIndexEntry.Key)
+				log.Infof("Del %s", oldEntry.Key)
 			}
-			b.Del(secondaryIndexEntry.Key)
-			// Do not update Indexes in the DELETE_ONLY state.
-			if _, ok := ru.deleteOnlyIndex[i]; !ok {
+			b.Del(oldEntry.Key)
+		}
+		// Do not update Indexes in the DELETE_ONLY state.
+		if deleteOnly {
+			continue
+		}
+		for j := range newEntries {
+			newEntry := &newEntries[j]
+			if containsIndexEntryKey(oldEntries, newEntry.Key) {
+				if primaryEncoded {
+					if log.V(2) {
+						log.Infof("Put %s -> %v", newEntry.Key, newEntry.Value.PrettyPrint())
+					}
+					b.Put(newEntry.Key, &newEntry.Value)
+				}
+				continue
+			}
+			if isReplacedPrimaryIndex(&ru.helper.indexes[i]) {
 				if log.V(2) {
-					log.Infof("CPut %s -> %v", newSecondaryIndexEntry.Key, newSecondaryIndexEntry.Value.PrettyPrint())
+					log.Infof("Put %s -> %v", newEntry.Key, newEntry.Value.PrettyPrint())
 				}
-				b.CPut(newSecondaryIndexEntry.Key, &newSecondaryIndexEntry.Value, nil)
+				b.Put(newEntry.Key, &newEntry.Value)
+				continue
+			}
+			if log.V(2) {
+				log.Infof("CPut %s -> %v", newEntry.Key, newEntry.Value.PrettyPrint())
 			}
+			b.CPut(newEntry.Key, &newEntry.Value, nil)
 		}
 	}
 
 	return ru.newValues, nil
 }
 
+// indexEntryKeysEqual returns whether two lists of index entries have the same
+// keys in the same order.
+func indexEntryKeysEqual(a, b []sqlbase.IndexEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i].Key, b[i].Key) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsIndexEntryKey returns whether one of the index entries has the key.
+func containsIndexEntryKey(entries []sqlbase.IndexEntry, key roachpb.Key) bool {
+	for i := range entries {
+		if bytes.Equal(entries[i].Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPrimaryEncodedIndex returns whether one of the indexes is written with the
+// encoding of a primary index, whose key/values hold the values of all the
+// columns of the rows.
+func hasPrimaryEncodedIndex(indexes []sqlbase.IndexDescriptor) bool {
+	for _, index := range indexes {
+		if index.PrimaryEncoding {
+			return true
+		}
+	}
+	return false
+}
+
+// isReplacedPrimaryIndex returns whether the index is the old primary index of a
+// table whose primary key has been changed, which is written until it is
+// dropped but no longer enforces the uniqueness of the old primary key.
+func isReplacedPrimaryIndex(index *sqlbase.IndexDescriptor) bool {
+	return index.PrimaryEncoding && index.ReplacesID == 0
+}
+
 // isColumnOnlyUpdate returns true if this rowUpdater is only updating column
 // data (in contrast to updating the primary key or other indexes).
 func (ru *rowUpdater) isColumnOnlyUpdate() bool {
@@ -786,6 +906,16 @@ func makeRowDeleter(
 			}
 		}
 	}
+	if hasPrimaryEncodedIndex(indexes) {
+		// All the key/values of the rows of the index need to be deleted.
+		for _, fam := range tableDesc.Families {
+			for _, colID := range fam.ColumnIDs {
+				if err := maybeAddCol(colID); err != nil {
+					return rowDeleter{}, err
+				}
+			}
+		}
+	}
 
 	rd := rowDeleter{
 		helper:               rowHelper{tableDesc: tableDesc, indexes: indexes},
@@ -803,7 +933,8 @@ func makeRowDeleter(
 }
 
 // deleteRow adds to the batch the kv operations necessary to delete a table row
-// with the given values.
+// with the given values. The FK checks for the row are queued in rd.fks and
+// must be run before the batch.
 func (rd *rowDeleter) deleteRow(b *client.Batch, values []parser.Datum) error {
 	if err := rd.fks.checkAll(values); err != nil {
 		return err
@@ -814,11 +945,13 @@ func (rd *rowDeleter) deleteRow(b *client.Batch, values []parser.Datum) error {
 		return err
 	}
 
-	for _, secondaryIndexEntry := range secondaryIndexEntries {
-		if log.V(2) {
-			log.Infof("Del %s", secondaryIndexEntry.Key)
+	for _, entries := range secondaryIndexEntries {
+		for _, secondaryIndexEntry := range entries {
+			if log.V(2) {
+				log.Infof("Del %s", secondaryIndexEntry.Key)
+			}
+			b.Del(secondaryIndexEntry.Key)
 		}
-		b.Del(secondaryIndexEntry.Key)
 	}
 
 	// Delete the row.
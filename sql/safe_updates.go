@@ -0,0 +1,42 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/pkg/errors"
+)
+
+// errUnsafeUpdate is returned for the statements rejected because the
+// sql_safe_updates session variable is set.
+func errUnsafeUpdate(stmt string) error {
+	return errors.Errorf("rejected: %s (sql_safe_updates = true)", stmt)
+}
+
+// tableIsEmpty returns whether a table, whose name was normalized, has no
+// rows. The rows are counted as root, so that the privileges needed to drop
+// the table are enough.
+func (p *planner) tableIsEmpty(name *parser.QualifiedName) (bool, error) {
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	row, err := ip.queryRow(fmt.Sprintf(`SELECT 1 FROM %s LIMIT 1`, name))
+	if err != nil {
+		return false, err
+	}
+	return row == nil, nil
+}
@@ -20,7 +20,9 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"sort"
 
+	"github.com/cockroachdb/cockroach/keys"
 	"github.com/cockroachdb/cockroach/roachpb"
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/sql/privilege"
@@ -58,6 +60,14 @@ type scanNode struct {
 	isSecondaryIndex bool
 	reverse          bool
 	ordering         orderingInfo
+	// Set if each span is the prefix of the keys of a single row of the
+	// primary index, whose column families are then fetched directly with Get
+	// requests instead of a scan.
+	pointLookup bool
+	// If non-zero, only the first row for each value of the first
+	// skipPrefixLen columns of the index is returned; the following rows with
+	// the same prefix are skipped (see setSkipPrefix).
+	skipPrefixLen int
 
 	explain   explainMode
 	rowIndex  int // the index of the current row
@@ -67,6 +77,13 @@ type scanNode struct {
 	// parser.IndexedVar leaves generated using filterVars.
 	filter     parser.TypedExpr
 	filterVars parser.IndexedVarHelper
+	// policyFilter restricts the rows to those allowed by the row-level
+	// security policies of the table (see initPolicies); it is part of filter.
+	policyFilter parser.TypedExpr
+
+	// computed evaluates the virtual computed columns of the table when the
+	// primary index is scanned, since their values are not stored in it.
+	computed computedHelper
 
 	scanInitialized bool
 	fetcher         sqlbase.RowFetcher
@@ -76,7 +93,7 @@ type scanNode struct {
 }
 
 func (p *planner) Scan() *scanNode {
-	return &scanNode{p: p}
+	return p.alloc.newScanNode(scanNode{p: p})
 }
 
 func (n *scanNode) Columns() []ResultColumn {
@@ -121,15 +138,66 @@ func (n *scanNode) expandPlan() error {
 }
 
 func (n *scanNode) Start() error {
+	if !n.isSecondaryIndex {
+		if err := n.initComputed(); err != nil {
+			return err
+		}
+	}
+
 	err := n.fetcher.Init(&n.desc, n.colIdxMap, n.index, n.reverse, n.isSecondaryIndex, n.cols,
 		n.valNeededForCol)
 	if err != nil {
 		return err
 	}
+	n.fetcher.SetCheckCancelled(n.p.checkCancelled)
 
 	return n.p.startSubqueryPlans(n.filter)
 }
 
+// initComputed sets up the evaluation of the virtual computed columns and
+// marks the columns they are computed from as needed.
+func (n *scanNode) initComputed() error {
+	if err := n.computed.init(n.p, &n.desc, true); err != nil {
+		return err
+	}
+	for i, col := range n.computed.computedCols {
+		if idx, ok := n.colIdxMap[col.ID]; !ok || !n.valNeededForCol[idx] {
+			continue
+		}
+		for _, id := range n.computed.dependencies(i) {
+			if idx, ok := n.colIdxMap[id]; ok {
+				n.valNeededForCol[idx] = true
+			}
+		}
+	}
+	return nil
+}
+
+// needsComputed returns whether the scan evaluates virtual computed columns,
+// which are not stored in the primary index.
+func (n *scanNode) needsComputed() bool {
+	if n.isSecondaryIndex {
+		return false
+	}
+	for i, col := range n.cols {
+		if col.IsVirtual() && n.valNeededForCol[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// needsCollation returns whether the scan reads collated strings, whose
+// locale is not part of the column types of the table readers.
+func (n *scanNode) needsCollation() bool {
+	for i, col := range n.cols {
+		if col.Type.Kind == sqlbase.ColumnType_COLLATEDSTRING && n.valNeededForCol[i] {
+			return true
+		}
+	}
+	return false
+}
+
 // initScan sets up the rowFetcher and starts a scan.
 func (n *scanNode) initScan() error {
 	if len(n.spans) == 0 {
@@ -140,19 +208,56 @@ func (n *scanNode) initScan() error {
 		n.spans = append(n.spans, sqlbase.Span{Start: start, End: start.PrefixEnd()})
 	}
 
+	if n.p.execCtx != nil {
+		n.p.execCtx.IndexUsage.recordRead(n.desc.ID, n.index.ID)
+	}
+
 	limitHint := n.limitHint
 	if limitHint != 0 && n.limitSoft {
 		// Read a multiple of the limit if the limit is "soft".
 		limitHint *= 2
 	}
+	if n.skipPrefixLen > 0 && limitHint == 0 {
+		// A skip-scan only needs the first row of each prefix: the batches
+		// which seek to the next prefix are limited to a row (see
+		// RowFetcher.SkipPrefix), and grow when the prefixes have few rows.
+		limitHint = 1
+	}
 
-	if err := n.fetcher.StartScan(n.p.txn, n.spans, limitHint); err != nil {
+	if n.pointLookup {
+		if err := n.fetcher.StartGets(n.p.txn, n.pointLookupKeys()); err != nil {
+			return err
+		}
+	} else if err := n.fetcher.StartScan(n.p.txn, n.spans, limitHint); err != nil {
 		return err
 	}
 	n.scanInitialized = true
 	return nil
 }
 
+// pointLookupKeys returns the keys of the column families of the rows looked
+// up by a point lookup, in increasing order.
+func (n *scanNode) pointLookupKeys() []roachpb.Key {
+	// The keys of the families are ordered by family ID.
+	familyIDs := make([]int, len(n.desc.Families))
+	for i, family := range n.desc.Families {
+		familyIDs[i] = int(family.ID)
+	}
+	sort.Ints(familyIDs)
+
+	kvKeys := make([]roachpb.Key, 0, len(n.spans)*len(familyIDs))
+	for _, span := range n.spans {
+		rowPrefix := span.Start
+		for _, id := range familyIDs {
+			// MakeFamilyKey appends to its argument, so copy the prefix every time.
+			key := make([]byte, len(rowPrefix), len(rowPrefix)+4)
+			copy(key, rowPrefix)
+			kvKeys = append(kvKeys, keys.MakeFamilyKey(key, uint32(id)))
+		}
+	}
+	return kvKeys
+}
+
 // debugNext is a helper function used by Next() when in explainDebug mode.
 func (n *scanNode) debugNext() (bool, error) {
 	// In debug mode, we output a set of debug values for each key.
@@ -199,16 +304,64 @@ func (n *scanNode) Next() (bool, error) {
 		if err != nil || n.row == nil {
 			return false, err
 		}
+		if len(n.computed.exprs) > 0 {
+			n.computed.loadRow(n.colIdxMap, n.row, false)
+			if err := n.computed.fill(&n.p.evalCtx, n.colIdxMap, n.row); err != nil {
+				return false, err
+			}
+		}
 		passesFilter, err := sqlbase.RunFilter(n.filter, &n.p.evalCtx)
 		if err != nil {
 			return false, err
 		}
 		if passesFilter {
+			if n.skipPrefixLen > 0 {
+				if err := n.skipPrefix(); err != nil {
+					return false, err
+				}
+			}
 			return true, nil
 		}
 	}
 }
 
+// setSkipPrefix turns the scan into a skip-scan which returns a single row for
+// each value of the first prefixLen columns of the index. It is used when the
+// upper layer only needs distinct values of these columns: the other rows
+// sharing them are skipped without being decoded, and, when they span more
+// than a batch, without being retrieved. It is a no-op if the index doesn't
+// allow skipping rows.
+func (n *scanNode) setSkipPrefix(prefixLen int) {
+	if prefixLen == 0 || prefixLen > len(n.index.ColumnIDs) ||
+		len(n.index.Interleave.Ancestors) > 0 {
+		return
+	}
+	if prefixLen == len(n.index.ColumnIDs) && (!n.isSecondaryIndex || n.index.Unique) {
+		// Each prefix is the key of a single row; there is nothing to skip.
+		return
+	}
+	for _, id := range n.index.ColumnIDs[:prefixLen] {
+		idx, ok := n.colIdxMap[id]
+		if !ok {
+			return
+		}
+		// The values of the prefix columns are needed to encode the prefix.
+		n.valNeededForCol[idx] = true
+	}
+	n.skipPrefixLen = prefixLen
+}
+
+// skipPrefix skips the rows which share the prefix of the current row.
+func (n *scanNode) skipPrefix() error {
+	prefix, _, err := sqlbase.EncodeColumns(
+		n.index.ColumnIDs[:n.skipPrefixLen], n.index.ColumnDirections[:n.skipPrefixLen],
+		n.colIdxMap, n.row, sqlbase.MakeIndexKeyPrefix(&n.desc, n.index.ID))
+	if err != nil {
+		return err
+	}
+	return n.fetcher.SkipPrefix(prefix)
+}
+
 func (n *scanNode) ExplainPlan(_ bool) (name, description string, children []planNode) {
 	if n.reverse {
 		name = "revscan"
@@ -221,6 +374,9 @@ func (n *scanNode) ExplainPlan(_ bool) (name, description string, children []pla
 	if spans != "" {
 		fmt.Fprintf(&desc, " %s", spans)
 	}
+	if n.skipPrefixLen > 0 {
+		fmt.Fprintf(&desc, " (skip-scan prefix %d)", n.skipPrefixLen)
+	}
 	if n.limitHint > 0 && !n.limitSoft {
 		if n.limitHint == 1 {
 			desc.WriteString(" (max 1 row)")
@@ -282,6 +438,9 @@ func (n *scanNode) initTable(
 			if n.specifiedIndex == nil {
 				return "", fmt.Errorf("index \"%s\" not found", indexName)
 			}
+			if n.specifiedIndex.Type == sqlbase.IndexDescriptor_INVERTED {
+				return "", fmt.Errorf("inverted index \"%s\" cannot be scanned", indexName)
+			}
 		}
 	}
 	n.noIndexJoin = (indexHints != nil && indexHints.NoIndexJoin)
@@ -298,6 +457,17 @@ func (n *scanNode) setNeededColumns(needed []bool) {
 	copy(n.valNeededForCol, needed)
 }
 
+// keysOnly returns true if the upper layer doesn't need the value of any
+// column, in which case only the keys of the index have to be scanned.
+func (n *scanNode) keysOnly() bool {
+	for _, needed := range n.valNeededForCol {
+		if needed {
+			return false
+		}
+	}
+	return true
+}
+
 // Initializes the column structures.
 func (n *scanNode) initDescDefaults(scanVisibility scanVisibility) {
 	n.index = &n.desc.PrimaryIndex
@@ -186,3 +186,82 @@ func TestScanBatches(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestScanSkipPrefix tests the skip-scan used by SELECT DISTINCT over a prefix
+// of the index, with batch sizes which make the skipped rows span batches.
+func TestScanSkipPrefix(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(
+		t, base.TestServerArgs{UseDatabase: "test"})
+	defer s.Stopper().Stop()
+
+	// The test will screw around with KVBatchSize; make sure to restore it at the end.
+	restore := sqlbase.SetKVBatchSize(10)
+	defer restore()
+
+	numAs := 5
+	numBs := 20
+
+	if _, err := db.Exec(`
+CREATE DATABASE test;
+CREATE TABLE test.skip (a INT, b INT, v STRING, PRIMARY KEY (a, b));
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`INSERT INTO test.skip VALUES `)
+	for a := 0; a < numAs; a++ {
+		for b := 0; b < numBs; b++ {
+			if a+b > 0 {
+				buf.WriteString(", ")
+			}
+			if (a+b)%2 == 0 {
+				fmt.Fprintf(&buf, "(%d, %d, 'str%d%d')", a, b, a, b)
+			} else {
+				fmt.Fprintf(&buf, "(%d, %d, NULL)", a, b)
+			}
+		}
+	}
+	if _, err := db.Exec(buf.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		query    string
+		expected []int
+	}{
+		{`SELECT DISTINCT a FROM test.skip`, []int{0, 1, 2, 3, 4}},
+		{`SELECT DISTINCT a FROM test.skip ORDER BY a DESC`, []int{4, 3, 2, 1, 0}},
+		{`SELECT DISTINCT a FROM test.skip WHERE a IN (1, 3, 4)`, []int{1, 3, 4}},
+		// The filter is evaluated on the rows preceding the skipped ones.
+		{`SELECT DISTINCT a FROM test.skip WHERE b >= a * 5`, []int{0, 1, 2, 3}},
+	}
+
+	numKeys := 3 * numAs * numBs / 2
+	for _, batch := range []int{1, 2, 3, 5, 10, 13, 100, numKeys} {
+		sqlbase.SetKVBatchSize(int64(batch))
+		for _, tc := range testCases {
+			rows, err := db.Query(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var results []int
+			for rows.Next() {
+				var a int
+				if err := rows.Scan(&a); err != nil {
+					t.Fatal(err)
+				}
+				results = append(results, a)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(results) != fmt.Sprint(tc.expected) {
+				t.Errorf("batch size %d: %s: expected %v, but found %v",
+					batch, tc.query, tc.expected, results)
+			}
+		}
+	}
+}
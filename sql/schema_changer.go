@@ -66,7 +66,7 @@ func (sc *SchemaChanger) truncateAndDropTable(
 		return err
 	}
 	*lease = l
-	return truncateAndDropTable(tableDesc, &sc.db)
+	return truncateAndDropTable(tableDesc, &sc.db, sc.leaseMgr.stopper)
 }
 
 // NewSchemaChangerForTesting only for tests.
@@ -283,6 +283,25 @@ func (sc SchemaChanger) exec(
 		}
 	}
 
+	if hasValidatingConstraints(desc.GetTable()) {
+		lease, err = sc.ExtendLease(lease)
+		if err != nil {
+			return err
+		}
+		// Wait for everyone to see the version with the constraints, so that
+		// they are checked on all the rows written from now on, before checking
+		// the existing rows.
+		if err := sc.waitToUpdateLeases(); err != nil {
+			return err
+		}
+		if err := sc.validateForeignKeys(&lease, validatingForeignKeys(desc.GetTable())); err != nil {
+			return err
+		}
+		if err := sc.validateChecks(&lease, validatingChecks(desc.GetTable())); err != nil {
+			return err
+		}
+	}
+
 	// Wait for the schema change to propagate to all nodes after this function
 	// returns, so that the new schema is live everywhere. This is not needed for
 	// correctness but is done to make the UI experience/tests predictable.
@@ -424,9 +443,16 @@ func (sc *SchemaChanger) waitToUpdateLeases() error {
 // done finalizes the mutations (adds new cols/indexes to the table).
 // It ensures that all nodes are on the current (pre-update) version of the
 // schema.
-// Returns the updated of the descriptor.
-func (sc *SchemaChanger) done() (*sqlbase.Descriptor, error) {
-	return sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+// Returns the ID of the mutations dropping the indexes replaced by the
+// finalized ones when the primary key of the table is changed, or
+// InvalidMutationID.
+func (sc *SchemaChanger) done() (sqlbase.MutationID, error) {
+	// The sequences backing the dropped SERIAL columns.
+	var droppedSequences []sqlbase.ID
+	var followUpID sqlbase.MutationID
+	_, err := sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+		droppedSequences = nil
+		followUpID = sqlbase.InvalidMutationID
 		i := 0
 		for _, mutation := range desc.Mutations {
 			if mutation.MutationID != sc.mutationID {
@@ -434,6 +460,12 @@ func (sc *SchemaChanger) done() (*sqlbase.Descriptor, error) {
 				// mutations if they have the mutation ID we're looking for.
 				break
 			}
+			if col := mutation.GetColumn(); col != nil &&
+				mutation.Direction == sqlbase.DescriptorMutation_DROP {
+				if id, ok := serialSequenceID(*col); ok {
+					droppedSequences = append(droppedSequences, id)
+				}
+			}
 			desc.MakeMutationComplete(mutation)
 			i++
 		}
@@ -444,8 +476,31 @@ func (sc *SchemaChanger) done() (*sqlbase.Descriptor, error) {
 		}
 		// Trim the executed mutations from the descriptor.
 		desc.Mutations = desc.Mutations[i:]
+		// The columns of the expressions of the dropped indexes are dropped
+		// along with them.
+		desc.RemoveUnusedIndexExprColumns()
+		// The indexes replaced by the ones made public are dropped by
+		// mutations added with the next mutation ID, which is finalized.
+		for _, m := range desc.Mutations {
+			if m.MutationID == desc.NextMutationID {
+				var err error
+				if followUpID, err = desc.FinalizeMutation(); err != nil {
+					return err
+				}
+				break
+			}
+		}
 		return nil
 	}, func(txn *client.Txn) error {
+		if len(droppedSequences) > 0 {
+			b := txn.NewBatch()
+			for _, id := range droppedSequences {
+				deleteSequence(b, id)
+			}
+			if err := txn.Run(b); err != nil {
+				return err
+			}
+		}
 		// Log "Finish Schema Change" event. Only the table ID and mutation ID
 		// are logged; this can be correlated with the DDL statement that
 		// initiated the change using the mutation id.
@@ -458,6 +513,7 @@ func (sc *SchemaChanger) done() (*sqlbase.Descriptor, error) {
 			}{uint32(sc.mutationID)},
 		)
 	})
+	return followUpID, err
 }
 
 // runStateMachineAndBackfill runs the schema change state machine followed by
@@ -482,8 +538,15 @@ func (sc *SchemaChanger) runStateMachineAndBackfill(
 	}
 
 	// Mark the mutations as completed.
-	_, err := sc.done()
-	return err
+	followUpID, err := sc.done()
+	if err != nil || followUpID == sqlbase.InvalidMutationID {
+		return err
+	}
+	// Drop the indexes replaced by the new ones right away: the other schema
+	// changes of the table wait for them to be dropped, and the writes to the
+	// table keep maintaining them until then.
+	sc.mutationID = followUpID
+	return sc.runStateMachineAndBackfill(lease, nil)
 }
 
 // reverseMutations reverses the direction of all the mutations with the
@@ -491,11 +554,15 @@ func (sc *SchemaChanger) runStateMachineAndBackfill(
 // applying a schema change. If a column being added is reversed and dropped,
 // all new indexes referencing the column will also be dropped.
 func (sc *SchemaChanger) reverseMutations(causingError error) error {
+	var fkIndexes []sqlbase.IndexDescriptor
 	// Reverse the flow of the state machine.
 	_, err := sc.leaseMgr.Publish(sc.tableID, func(desc *sqlbase.TableDescriptor) error {
 		// Keep track of the column mutations being reversed so that indexes
 		// referencing them can be dropped.
 		columns := make(map[string]struct{})
+		// Reversed index mutations with foreign keys whose back references on
+		// other tables have to be removed.
+		fkIndexes = nil
 
 		for i, mutation := range desc.Mutations {
 			if mutation.MutationID != sc.mutationID {
@@ -511,6 +578,15 @@ func (sc *SchemaChanger) reverseMutations(causingError error) error {
 				if col := mutation.GetColumn(); col != nil {
 					columns[col.Name] = struct{}{}
 				}
+				if idx := mutation.GetIndex(); idx != nil && idx.ForeignKey != nil {
+					if idx.ForeignKey.Table == desc.ID {
+						if err := removeFKBackReferenceFromTable(desc, desc.ID, *idx); err != nil {
+							return err
+						}
+					} else {
+						fkIndexes = append(fkIndexes, *idx)
+					}
+				}
 
 			case sqlbase.DescriptorMutation_DROP:
 				desc.Mutations[i].Direction = sqlbase.DescriptorMutation_ADD
@@ -525,6 +601,25 @@ func (sc *SchemaChanger) reverseMutations(causingError error) error {
 		// Publish() will increment the version.
 		return nil
 	}, func(txn *client.Txn) error {
+		for _, idx := range fkIndexes {
+			t, err := getTableDescFromID(txn, idx.ForeignKey.Table)
+			if err != nil {
+				return err
+			}
+			if t.Deleted() {
+				continue
+			}
+			if err := removeFKBackReferenceFromTable(t, sc.tableID, idx); err != nil {
+				return err
+			}
+			if err := t.SetUpVersion(); err != nil {
+				return err
+			}
+			if err := txn.Put(sqlbase.MakeDescMetadataKey(t.ID), sqlbase.WrapDescriptor(t)); err != nil {
+				return err
+			}
+		}
+
 		// Log "Reverse Schema Change" event. Only the causing error and the
 		// mutation ID are logged; this can be correlated with the DDL statement
 		// that initiated the change using the mutation id.
@@ -590,7 +685,7 @@ func (sc *SchemaChanger) IsDone() (bool, error) {
 			return err
 		}
 		if sc.mutationID == sqlbase.InvalidMutationID {
-			if tableDesc.UpVersion {
+			if tableDesc.UpVersion || hasValidatingConstraints(tableDesc) {
 				done = false
 			}
 		} else {
@@ -724,9 +819,10 @@ func (s *SchemaChangeManager) Start(stopper *stop.Stopper) {
 						// check for the presence of mutations?
 						// A schema change execution might fail soon after
 						// unsetting UpVersion, and we still want to process
-						// outstanding mutations. Similar with a table marked for deletion.
-						if table.UpVersion || table.Deleted() ||
-							table.Renamed() || len(table.Mutations) > 0 {
+						// outstanding mutations. Similar with a table marked for deletion,
+						// and with constraints which haven't been validated.
+						if table.UpVersion || table.Deleted() || table.Renamed() ||
+							len(table.Mutations) > 0 || hasValidatingConstraints(table) {
 							if log.V(2) {
 								log.Infof("%s: queue up pending schema change; table: %d, version: %d",
 									kv.Key, table.ID, table.Version)
@@ -20,6 +20,7 @@ import (
 	gosql "database/sql"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -34,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/testutils"
 	"github.com/cockroachdb/cockroach/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/util"
+	"github.com/cockroachdb/cockroach/util/encoding"
 	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 	"github.com/cockroachdb/cockroach/util/protoutil"
@@ -1089,3 +1091,135 @@ CREATE TABLE t.test (k INT PRIMARY KEY, v INT);
 		t.Fatalf("expected %d key value pairs, but got %d", e, len(kvs))
 	}
 }
+
+// TestAlterColumnTypeConversionFailure tests that a value which can't be
+// converted by the backfill of ALTER COLUMN TYPE, because it was written
+// since the statement checked the values, reverses the schema change and
+// keeps the values of the column.
+func TestAlterColumnTypeConversionFailure(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var putInvalidValue func() error
+	params, _ := createTestServerParams()
+	params.Knobs = base.TestingKnobs{
+		SQLExecutor: &csql.ExecutorTestingKnobs{
+			SchemaChangersStartBackfillNotification: func() error {
+				if putInvalidValue == nil {
+					return nil
+				}
+				f := putInvalidValue
+				putInvalidValue = nil
+				return f()
+			},
+		},
+		SQLSchemaChangeManager: &csql.SchemaChangeManagerTestingKnobs{
+			AsyncSchemaChangerExecNotification: schemaChangeManagerDisabled,
+		},
+	}
+	server, sqlDB, kvDB := serverutils.StartServer(t, params)
+	defer server.Stopper().Stop()
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.test (k INT PRIMARY KEY, v STRING, FAMILY f1 (k), FAMILY f2 (v));
+INSERT INTO t.test VALUES (1, '1'), (2, '2');
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "t", "test")
+	rowKey := encoding.EncodeVarintAscending(
+		sqlbase.MakeIndexKeyPrefix(tableDesc, tableDesc.PrimaryIndex.ID), 2)
+	f2 := uint32(tableDesc.Families[1].ID)
+	putInvalidValue = func() error {
+		var v roachpb.Value
+		v.SetString("x")
+		return kvDB.Put(keys.MakeFamilyKey(rowKey, f2), &v)
+	}
+
+	if _, err := sqlDB.Exec(
+		`ALTER TABLE t.test ALTER COLUMN v TYPE INT`,
+	); !testutils.IsError(err, `cannot convert a value of column "v"`) {
+		t.Fatalf("expected a conversion error, but found %v", err)
+	}
+
+	// The column is still a STRING column.
+	if _, err := sqlDB.Exec(`INSERT INTO t.test VALUES (3, 'y')`); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := sqlDB.Query(`SELECT k, v FROM t.test ORDER BY k`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var k int
+		var v string
+		if err := rows.Scan(&k, &v); err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"1", "x", "y"}; !reflect.DeepEqual(values, expected) {
+		t.Fatalf("expected %v, but found %v", expected, values)
+	}
+}
+
+// TestAlterPrimaryKeyDropsReplacedIndexes tests that changing the primary key
+// of a table drops the replaced indexes before the statement returns, without
+// the asynchronous schema changers, so that the table can be altered again.
+func TestAlterPrimaryKeyDropsReplacedIndexes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params, _ := createTestServerParams()
+	params.Knobs = base.TestingKnobs{
+		SQLSchemaChangeManager: &csql.SchemaChangeManagerTestingKnobs{
+			AsyncSchemaChangerExecNotification: schemaChangeManagerDisabled,
+		},
+	}
+	server, sqlDB, kvDB := serverutils.StartServer(t, params)
+	defer server.Stopper().Stop()
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.test (k INT PRIMARY KEY, v INT NOT NULL, INDEX foo (v));
+INSERT INTO t.test VALUES (1, 10), (2, 20);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	oldDesc := sqlbase.GetTableDescriptor(kvDB, "t", "test")
+	if _, err := sqlDB.Exec(`ALTER TABLE t.test ALTER PRIMARY KEY USING COLUMNS (v)`); err != nil {
+		t.Fatal(err)
+	}
+
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "t", "test")
+	if len(tableDesc.Mutations) != 0 {
+		t.Fatalf("expected no mutations, but found %v", tableDesc.Mutations)
+	}
+	for _, id := range []sqlbase.IndexID{oldDesc.PrimaryIndex.ID, oldDesc.Indexes[0].ID} {
+		if _, err := tableDesc.FindIndexByID(id); err == nil {
+			t.Fatalf("expected index %d to be dropped", id)
+		}
+		prefix := roachpb.Key(sqlbase.MakeIndexKeyPrefix(oldDesc, id))
+		if kvs, err := kvDB.Scan(prefix, prefix.PrefixEnd(), 0); err != nil {
+			t.Fatal(err)
+		} else if len(kvs) != 0 {
+			t.Fatalf("expected the data of index %d to be deleted, but found %d keys", id, len(kvs))
+		}
+	}
+
+	if _, err := sqlDB.Exec(`CREATE INDEX bar ON t.test (k)`); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := sqlDB.QueryRow(`SELECT COUNT(*) FROM t.test@bar`).Scan(&count); err != nil {
+		t.Fatal(err)
+	} else if count != 2 {
+		t.Fatalf("expected 2 rows, but found %d", count)
+	}
+}
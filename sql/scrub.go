@@ -0,0 +1,244 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// The types of the violations reported by SCRUB.
+const (
+	scrubMissingIndexEntry  = "missing_index_entry"
+	scrubDanglingIndexEntry = "dangling_index_entry"
+	scrubCheckViolation     = "check_constraint_violation"
+	scrubForeignKeyMissing  = "foreign_key_violation"
+)
+
+var scrubColumns = []ResultColumn{
+	{Name: "ErrorType", Typ: parser.TypeString},
+	{Name: "Constraint", Typ: parser.TypeString},
+	{Name: "PrimaryKey", Typ: parser.TypeString},
+	{Name: "Details", Typ: parser.TypeString},
+}
+
+// Scrub checks the logical consistency of the data of a table: every row of
+// the primary index must have exactly one entry in each secondary index and
+// vice versa, every row must satisfy the CHECK constraints, and every foreign
+// key must reference an existing row. With the "physical" option, the
+// key/value pairs of the table are checked instead (see checkPhysical), and
+// with both the "logical" and "physical" options, both checks are run. All
+// the checks run inline, within the statement. Each violation is returned as
+// a row.
+// Privileges: root.
+//   Notes: postgres and mysql do not have a SCRUB statement.
+func (p *planner) Scrub(n *parser.Scrub) (planNode, error) {
+	if p.session.User != security.RootUser {
+		return nil, errors.Errorf("only %s is allowed to scrub tables", security.RootUser)
+	}
+	if err := p.normalizeTableName(n.Table); err != nil {
+		return nil, err
+	}
+	logical, physical := len(n.Options) == 0, false
+	for _, opt := range n.Options {
+		switch sqlbase.NormalizeName(opt) {
+		case "logical":
+			logical = true
+		case "physical":
+			physical = true
+		default:
+			return nil, fmt.Errorf("unknown SCRUB option: %s", opt)
+		}
+	}
+	desc, err := p.mustGetTableDesc(n.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &valuesNode{columns: scrubColumns}
+	s := scrubber{p: p, v: v, desc: desc, table: n.Table.String()}
+	if physical {
+		if err := s.checkPhysical(); err != nil {
+			return nil, err
+		}
+	}
+	if !logical {
+		return v, nil
+	}
+	for _, pkName := range desc.PrimaryIndex.ColumnNames {
+		s.pkCols = append(s.pkCols, parser.Name(pkName).String())
+	}
+	for i := range desc.Indexes {
+		if desc.Indexes[i].Type == sqlbase.IndexDescriptor_INVERTED {
+			// Inverted indexes can't be scanned to compare them to the table.
+			continue
+		}
+		if err := s.checkIndex(&desc.Indexes[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, check := range desc.Checks {
+		if err := s.checkConstraint(check); err != nil {
+			return nil, err
+		}
+	}
+	for _, index := range append([]sqlbase.IndexDescriptor{desc.PrimaryIndex}, desc.Indexes...) {
+		if index.ForeignKey != nil {
+			if err := s.checkForeignKey(&index); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// scrubber runs the queries which look for the violations of the constraints
+// of a table, and accumulates them in a valuesNode.
+type scrubber struct {
+	p    *planner
+	v    *valuesNode
+	desc *sqlbase.TableDescriptor
+	// table is the fully qualified name of the table, and pkCols the names of
+	// the columns of its primary key, ready to be used in queries.
+	table  string
+	pkCols []string
+
+	// The state of the physical checks.
+	alloc sqlbase.DatumAlloc
+	// sentinelRow is the key of the last row of the primary index whose
+	// sentinel key was scanned.
+	sentinelRow roachpb.Key
+	// rowLookups are the secondary index entries whose row still has to be
+	// looked up.
+	rowLookups []rowLookup
+}
+
+// checkIndex reports the rows of the primary index which have no entry in
+// the secondary index, and the entries of the secondary index which have no
+// row. Only the columns of the secondary index and of the primary key are
+// read, so that the secondary index is scanned without looking up the rows.
+func (s *scrubber) checkIndex(index *sqlbase.IndexDescriptor) error {
+	cols := append([]string(nil), s.pkCols...)
+	for _, name := range index.ColumnNames {
+		col := parser.Name(name).String()
+		if !containsString(cols, col) {
+			cols = append(cols, col)
+		}
+	}
+	selectCols := strings.Join(cols, ", ")
+	indexName := parser.Name(index.Name).String()
+	primaryName := parser.Name(s.desc.PrimaryIndex.Name).String()
+
+	if err := s.report(scrubMissingIndexEntry, index.Name, cols, fmt.Sprintf(
+		`SELECT %[1]s FROM %[2]s@%[3]s EXCEPT ALL SELECT %[1]s FROM %[2]s@%[4]s`,
+		selectCols, s.table, primaryName, indexName)); err != nil {
+		return err
+	}
+	return s.report(scrubDanglingIndexEntry, index.Name, cols, fmt.Sprintf(
+		`SELECT %[1]s FROM %[2]s@%[3]s EXCEPT ALL SELECT %[1]s FROM %[2]s@%[4]s`,
+		selectCols, s.table, indexName, primaryName))
+}
+
+// checkConstraint reports the rows which don't satisfy a CHECK constraint.
+func (s *scrubber) checkConstraint(check *sqlbase.TableDescriptor_CheckConstraint) error {
+	return s.report(scrubCheckViolation, check.Name, s.pkCols, fmt.Sprintf(
+		`SELECT %s FROM %s WHERE NOT (%s)`, strings.Join(s.pkCols, ", "), s.table, check.Expr))
+}
+
+// checkForeignKey reports the rows whose foreign key columns are all non-NULL
+// but don't match any row of the referenced table.
+func (s *scrubber) checkForeignKey(index *sqlbase.IndexDescriptor) error {
+	other, err := getTableDescFromID(s.p.txn, index.ForeignKey.Table)
+	if err != nil {
+		return err
+	}
+	otherIdx, err := other.FindIndexByID(index.ForeignKey.Index)
+	if err != nil {
+		return err
+	}
+	otherName, err := s.p.showTableName(other, 0 /* dbID */)
+	if err != nil {
+		return err
+	}
+
+	var cols, on, notNull []string
+	for _, pkCol := range s.pkCols {
+		cols = append(cols, "c."+pkCol)
+	}
+	for i, otherCol := range otherIdx.ColumnNames {
+		col := "c." + parser.Name(index.ColumnNames[i]).String()
+		cols = append(cols, col)
+		on = append(on, fmt.Sprintf("%s = p.%s", col, parser.Name(otherCol).String()))
+		notNull = append(notNull, col+" IS NOT NULL")
+	}
+	// The referenced columns of a matching row are all non-NULL.
+	firstRef := "p." + parser.Name(otherIdx.ColumnNames[0]).String()
+	return s.report(scrubForeignKeyMissing, index.ForeignKey.Name, cols, fmt.Sprintf(
+		`SELECT %s FROM %s AS c LEFT OUTER JOIN %s AS p ON %s WHERE %s IS NULL AND %s`,
+		strings.Join(cols, ", "), s.table, otherName, strings.Join(on, " AND "),
+		firstRef, strings.Join(notNull, " AND ")))
+}
+
+// report runs a query returning the violations of a constraint. The first
+// columns of its results are the primary key of the offending row, and all of
+// them (whose names are given) are reported as the details of the violation.
+func (s *scrubber) report(errType, constraint string, cols []string, query string) error {
+	plan, err := s.p.query(query)
+	if err != nil {
+		return err
+	}
+	if err := plan.Start(); err != nil {
+		return err
+	}
+	for {
+		next, err := plan.Next()
+		if err != nil {
+			return err
+		}
+		if !next {
+			return nil
+		}
+		values := plan.Values()
+		var details bytes.Buffer
+		for i, val := range values {
+			if i > 0 {
+				details.WriteString(", ")
+			}
+			fmt.Fprintf(&details, "%s=%s", strings.TrimPrefix(cols[i], "c."), val)
+		}
+		s.v.rows = append(s.v.rows, []parser.Datum{
+			parser.NewDString(errType),
+			parser.NewDString(constraint),
+			parser.NewDString(values[:len(s.pkCols)].String()),
+			parser.NewDString(details.String()),
+		})
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,332 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/encoding"
+	"github.com/cockroachdb/cockroach/util/envutil"
+	"github.com/pkg/errors"
+)
+
+// The types of the violations reported by the physical checks of SCRUB, in
+// addition to dangling index entries.
+const (
+	scrubInvalidEncoding    = "invalid_encoding"
+	scrubOrphanedIndexData  = "orphaned_index_data"
+	scrubOrphanedFamilyData = "orphaned_column_family"
+)
+
+// scrubPhysicalBatchSize is the number of key/value pairs read at a time by
+// the physical checks of SCRUB.
+const scrubPhysicalBatchSize = 1000
+
+// scrubPhysicalBatchDelay is the time the physical checks of SCRUB wait
+// after each batch, to limit their impact on the rest of the traffic.
+var scrubPhysicalBatchDelay = envutil.EnvOrDefaultDuration("scrub_batch_delay", 0)
+
+// rowLookup is an entry of a secondary index and the primary key of the row
+// it references.
+type rowLookup struct {
+	index    *sqlbase.IndexDescriptor
+	entryKey roachpb.Key
+	rowKey   roachpb.Key
+}
+
+// checkPhysical scans all the key/value pairs of the table, including the
+// ones interleaved in other tables. It reports the keys and values which
+// can't be decoded or whose column family or columns don't exist, the data of
+// indexes which don't exist, the column families of the rows whose sentinel
+// key is missing, and the entries of the secondary indexes whose row doesn't
+// exist.
+//
+// The scan runs inline, within the SCRUB statement and on the gateway node:
+// it is not a background job and it is not distributed to the nodes holding
+// the data, as there is no infrastructure for background jobs yet. A client
+// disconnecting or cancelling the statement stops it.
+//
+// TODO(dt): run the checks as a distributed background job once jobs exist.
+func (s *scrubber) checkPhysical() error {
+	tablePrefix := roachpb.Key(keys.MakeTablePrefix(uint32(s.desc.ID)))
+	if err := s.scanPhysical(
+		roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()}, nil,
+	); err != nil {
+		return err
+	}
+
+	// The indexes interleaved in the same ancestor are checked with a single
+	// scan of its index.
+	var prefixes []roachpb.Key
+	interleaved := make(map[string][]*sqlbase.IndexDescriptor)
+	for _, index := range s.publicIndexes() {
+		if len(index.Interleave.Ancestors) == 0 {
+			continue
+		}
+		prefix := roachpb.Key(sqlbase.MakeIndexKeyPrefix(s.desc, index.ID))
+		if _, ok := interleaved[string(prefix)]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+		interleaved[string(prefix)] = append(interleaved[string(prefix)], index)
+	}
+	for _, prefix := range prefixes {
+		if err := s.scanPhysical(
+			roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}, interleaved[string(prefix)],
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *scrubber) publicIndexes() []*sqlbase.IndexDescriptor {
+	indexes := []*sqlbase.IndexDescriptor{&s.desc.PrimaryIndex}
+	for i := range s.desc.Indexes {
+		if s.desc.Indexes[i].Type == sqlbase.IndexDescriptor_INVERTED {
+			continue
+		}
+		indexes = append(indexes, &s.desc.Indexes[i])
+	}
+	return indexes
+}
+
+// scanPhysical checks the key/value pairs of a span in batches. If indexes is
+// nil, the span holds the data of the table, otherwise it is the span of an
+// index of another table in which the given indexes are interleaved.
+func (s *scrubber) scanPhysical(span roachpb.Span, indexes []*sqlbase.IndexDescriptor) error {
+	s.sentinelRow = nil
+	start := span.Key
+	for {
+		kvs, err := s.p.txn.Scan(start, span.EndKey, scrubPhysicalBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, kv := range kvs {
+			s.checkKV(kv, indexes)
+		}
+		if err := s.lookupRows(); err != nil {
+			return err
+		}
+		if len(kvs) < scrubPhysicalBatchSize {
+			return nil
+		}
+		start = kvs[len(kvs)-1].Key.Next()
+		if scrubPhysicalBatchDelay > 0 {
+			time.Sleep(scrubPhysicalBatchDelay)
+		}
+	}
+}
+
+// checkKV checks a key/value pair. Keys which belong to other tables
+// interleaved in the table (or in the same ancestor) are skipped.
+func (s *scrubber) checkKV(kv client.KeyValue, indexes []*sqlbase.IndexDescriptor) {
+	if indexes == nil {
+		_, _, indexID, err := sqlbase.DecodeTableIDIndexID(kv.Key)
+		if err != nil {
+			s.reportKV(scrubInvalidEncoding, parser.DNull, parser.DNull, kv.Key, err.Error())
+			return
+		}
+		for _, index := range s.publicIndexes() {
+			if index.ID == indexID {
+				indexes = []*sqlbase.IndexDescriptor{index}
+			}
+		}
+		if indexes == nil {
+			// The inverted indexes and the indexes which are being added or
+			// dropped are not checked.
+			if _, err := s.desc.FindIndexByID(indexID); err != nil {
+				s.reportKV(scrubOrphanedIndexData, parser.DNull, parser.DNull, kv.Key,
+					fmt.Sprintf("index %d does not exist", indexID))
+			}
+			return
+		}
+	}
+
+	for _, index := range indexes {
+		colIDs, dirs := index.FullColumnIDs()
+		valTypes, err := sqlbase.MakeKeyVals(s.desc, colIDs)
+		if err != nil {
+			s.reportKV(scrubInvalidEncoding, parser.NewDString(index.Name), parser.DNull, kv.Key,
+				err.Error())
+			return
+		}
+		vals := make([]parser.Datum, len(colIDs))
+		rest, ok, err := sqlbase.DecodeIndexKey(&s.alloc, s.desc, index.ID, valTypes, vals, dirs, kv.Key)
+		if err != nil {
+			s.reportKV(scrubInvalidEncoding, parser.NewDString(index.Name), parser.DNull, kv.Key,
+				err.Error())
+			return
+		}
+		if !ok {
+			continue
+		}
+		if index.ID == s.desc.PrimaryIndex.ID {
+			s.checkRowKV(kv, parser.DTuple(vals), kv.Key[:len(kv.Key)-len(rest)], rest)
+		} else {
+			s.checkIndexEntry(kv, index)
+		}
+		return
+	}
+}
+
+// checkRowKV checks a key/value pair of the primary index, whose key is made
+// of the key of the row and the suffix holding its column family.
+func (s *scrubber) checkRowKV(kv client.KeyValue, pk parser.DTuple, rowKey, suffix []byte) {
+	constraint := parser.NewDString(s.desc.PrimaryIndex.Name)
+	reportInvalid := func(err error) {
+		s.reportKV(scrubInvalidEncoding, constraint, parser.NewDString(pk.String()), kv.Key,
+			err.Error())
+	}
+
+	rest, famID, err := encoding.DecodeUvarintAscending(suffix)
+	if err == nil && famID != keys.SentinelFamilyID {
+		// The keys of the other families end with the length of the family ID.
+		rest, _, err = encoding.DecodeUvarintAscending(rest)
+	}
+	if err != nil {
+		reportInvalid(err)
+		return
+	}
+	if len(rest) > 0 {
+		reportInvalid(errors.Errorf("unexpected bytes after the column family: %q", rest))
+		return
+	}
+	family, err := s.desc.FindFamilyByID(sqlbase.FamilyID(famID))
+	if err != nil {
+		s.reportKV(scrubOrphanedFamilyData, constraint, parser.NewDString(pk.String()), kv.Key,
+			err.Error())
+		return
+	}
+
+	// The sentinel family is always written, and sorts before the other
+	// families of the row.
+	if famID == keys.SentinelFamilyID {
+		s.sentinelRow = append(s.sentinelRow[:0], rowKey...)
+	} else if !bytes.Equal(s.sentinelRow, rowKey) {
+		s.reportKV(scrubOrphanedFamilyData, constraint, parser.NewDString(pk.String()), kv.Key,
+			fmt.Sprintf("column family %s of a row without sentinel", family.Name))
+	}
+
+	if err := s.checkFamilyValue(family, kv.Value); err != nil {
+		reportInvalid(err)
+	}
+}
+
+// checkFamilyValue checks that the value of a column family can be decoded
+// using the types of its columns.
+func (s *scrubber) checkFamilyValue(
+	family *sqlbase.ColumnFamilyDescriptor, value *roachpb.Value,
+) error {
+	if value.GetTag() != roachpb.ValueType_TUPLE {
+		if family.DefaultColumnID == 0 {
+			if family.ID == keys.SentinelFamilyID {
+				return nil
+			}
+			return errors.Errorf("single entry value with no default column id")
+		}
+		col, err := s.desc.FindColumnByID(family.DefaultColumnID)
+		if err != nil {
+			return err
+		}
+		_, err = sqlbase.UnmarshalColumnValue(&s.alloc, col.Type, value)
+		return err
+	}
+
+	b, err := value.GetTuple()
+	if err != nil {
+		return err
+	}
+	var colID sqlbase.ColumnID
+	for len(b) > 0 {
+		_, _, colIDDiff, _, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return err
+		}
+		colID += sqlbase.ColumnID(colIDDiff)
+		if !familyContainsColumn(family, colID) {
+			return errors.Errorf("column %d is not in column family %s", colID, family.Name)
+		}
+		col, err := s.desc.FindColumnByID(colID)
+		if err != nil {
+			return err
+		}
+		if _, b, err = sqlbase.DecodeTableValue(&s.alloc, col.Type.ToDatumType(), b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func familyContainsColumn(family *sqlbase.ColumnFamilyDescriptor, colID sqlbase.ColumnID) bool {
+	for _, id := range family.ColumnIDs {
+		if id == colID {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIndexEntry decodes the primary key of the row referenced by an entry
+// of a secondary index. The row is looked up later, with the other entries of
+// the batch.
+func (s *scrubber) checkIndexEntry(kv client.KeyValue, index *sqlbase.IndexDescriptor) {
+	rowKey, err := sqlbase.ExtractIndexKey(&s.alloc, s.desc, kv)
+	if err != nil {
+		s.reportKV(scrubInvalidEncoding, parser.NewDString(index.Name), parser.DNull, kv.Key,
+			err.Error())
+		return
+	}
+	s.rowLookups = append(s.rowLookups, rowLookup{index: index, entryKey: kv.Key, rowKey: rowKey})
+}
+
+// lookupRows reports the secondary index entries collected by
+// checkIndexEntry whose row doesn't have a sentinel key.
+func (s *scrubber) lookupRows() error {
+	if len(s.rowLookups) == 0 {
+		return nil
+	}
+	b := s.p.txn.NewBatch()
+	for _, l := range s.rowLookups {
+		b.Get(keys.MakeRowSentinelKey(l.rowKey))
+	}
+	if err := s.p.txn.Run(b); err != nil {
+		return err
+	}
+	for i, l := range s.rowLookups {
+		if b.Results[i].Rows[0].Value == nil {
+			s.reportKV(scrubDanglingIndexEntry, parser.NewDString(l.index.Name), parser.DNull,
+				l.entryKey, fmt.Sprintf("row %s does not exist", l.rowKey))
+		}
+	}
+	s.rowLookups = s.rowLookups[:0]
+	return nil
+}
+
+// reportKV reports a violation found in a key/value pair.
+func (s *scrubber) reportKV(errType string, constraint, pk parser.Datum, key roachpb.Key, details string) {
+	s.v.rows = append(s.v.rows, []parser.Datum{
+		parser.NewDString(errType),
+		constraint,
+		pk,
+		parser.NewDString(fmt.Sprintf("%s: %s", key, details)),
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql_test
+
+import (
+	gosql "database/sql"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/encoding"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestScrubIndex tests that SCRUB reports the rows missing from a secondary
+// index and the index entries which don't match any row.
+func TestScrubIndex(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (k INT PRIMARY KEY, v INT, INDEX t_v (v));
+INSERT INTO d.t VALUES (1, 10), (2, 20);
+`); err != nil {
+		t.Fatal(err)
+	}
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "d", "t")
+	colMap := map[sqlbase.ColumnID]int{
+		tableDesc.Columns[0].ID: 0,
+		tableDesc.Columns[1].ID: 1,
+	}
+	indexEntry := func(k, v int) sqlbase.IndexEntry {
+		entry, err := sqlbase.EncodeSecondaryIndex(tableDesc, &tableDesc.Indexes[0], colMap,
+			[]parser.Datum{parser.NewDInt(parser.DInt(k)), parser.NewDInt(parser.DInt(v))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entry
+	}
+
+	// Remove the index entry of the first row, and add one for a row which
+	// doesn't exist.
+	if err := kvDB.Del(indexEntry(1, 10).Key); err != nil {
+		t.Fatal(err)
+	}
+	dangling := indexEntry(3, 30)
+	if err := kvDB.Put(dangling.Key, &dangling.Value); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`EXPERIMENTAL SCRUB TABLE d.t`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var results [][4]string
+	for rows.Next() {
+		var r [4]string
+		if err := rows.Scan(&r[0], &r[1], &r[2], &r[3]); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][4]string{
+		{"missing_index_entry", "t_v", "(1)", "k=1, v=10"},
+		{"dangling_index_entry", "t_v", "(3)", "k=3, v=30"},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %v, but found %v", expected, results)
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Errorf("%d: expected %v, but found %v", i, expected[i], results[i])
+		}
+	}
+}
+
+// TestScrubPhysical tests that the physical checks of SCRUB report the
+// invalid values, the data of missing indexes and column families, and the
+// index entries whose row doesn't exist.
+func TestScrubPhysical(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (
+  k INT PRIMARY KEY, v INT, w INT, INDEX t_v (v), FAMILY f1 (k, v), FAMILY f2 (w)
+);
+INSERT INTO d.t VALUES (1, 10, 100), (2, 20, 200);
+`); err != nil {
+		t.Fatal(err)
+	}
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "d", "t")
+	indexKey := func(indexID sqlbase.IndexID, k int) []byte {
+		return encoding.EncodeVarintAscending(sqlbase.MakeIndexKeyPrefix(tableDesc, indexID), int64(k))
+	}
+	f2 := uint32(tableDesc.Families[1].ID)
+
+	// Remove the sentinel of the first row, which leaves its second column
+	// family and its index entry orphaned, corrupt the second column family of
+	// the second row, and add the data of an index which doesn't exist.
+	if err := kvDB.Del(keys.MakeRowSentinelKey(indexKey(tableDesc.PrimaryIndex.ID, 1))); err != nil {
+		t.Fatal(err)
+	}
+	var invalid roachpb.Value
+	invalid.SetString("foo")
+	if err := kvDB.Put(keys.MakeFamilyKey(indexKey(tableDesc.PrimaryIndex.ID, 2), f2), &invalid); err != nil {
+		t.Fatal(err)
+	}
+	if err := kvDB.Put(keys.MakeRowSentinelKey(indexKey(9, 1)), "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`EXPERIMENTAL SCRUB TABLE d.t WITH OPTIONS physical`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var results [][3]gosql.NullString
+	for rows.Next() {
+		var r [3]gosql.NullString
+		var details string
+		if err := rows.Scan(&r[0], &r[1], &r[2], &details); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	str := func(s string) gosql.NullString {
+		return gosql.NullString{String: s, Valid: true}
+	}
+	null := gosql.NullString{}
+	// The index entries are looked up after each batch of keys, so the
+	// dangling entry is reported last.
+	expected := [][3]gosql.NullString{
+		{str("orphaned_column_family"), str("primary"), str("(1)")},
+		{str("invalid_encoding"), str("primary"), str("(2)")},
+		{str("orphaned_index_data"), null, null},
+		{str("dangling_index_entry"), str("t_v"), null},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %v, but found %v", expected, results)
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Errorf("%d: expected %v, but found %v", i, expected[i], results[i])
+		}
+	}
+}
@@ -260,7 +260,7 @@ func (p *planner) Select(n *parser.Select, desiredTypes []parser.Datum, autoComm
 		if err != nil {
 			return nil, err
 		}
-		result := &selectTopNode{source: plan, sort: sort, limit: limit}
+		result := p.alloc.newSelectTopNode(selectTopNode{source: plan, sort: sort, limit: limit})
 		limit.setTop(result)
 		return result, nil
 	}
@@ -287,7 +287,7 @@ func (p *planner) SelectClause(
 	desiredTypes []parser.Datum,
 	scanVisibility scanVisibility,
 ) (planNode, error) {
-	s := &selectNode{planner: p}
+	s := p.alloc.newSelectNode(selectNode{planner: p})
 
 	s.qvals = make(qvalMap)
 
@@ -324,13 +324,13 @@ func (p *planner) SelectClause(
 	}
 	distinctPlan := p.Distinct(parsed)
 
-	result := &selectTopNode{
+	result := p.alloc.newSelectTopNode(selectTopNode{
 		source:   s,
 		group:    group,
 		sort:     sort,
 		distinct: distinctPlan,
 		limit:    limitPlan,
-	}
+	})
 	s.top = result
 	limitPlan.setTop(result)
 	distinctPlan.setTop(result)
@@ -362,7 +362,8 @@ func (s *selectNode) expandPlan() error {
 		neededCols := make([]bool, len(s.source.info.sourceColumns))
 		for i := range neededCols {
 			_, ok := s.qvals[columnRef{s.source.info, i}]
-			neededCols[i] = ok
+			// The columns of the policy filter are needed to evaluate it.
+			neededCols[i] = ok || scan.filterVars.IndexedVarUsed(i)
 		}
 		scan.setNeededColumns(neededCols)
 
@@ -378,6 +379,13 @@ func (s *selectNode) expandPlan() error {
 		}
 
 		scan.filter, s.filter = splitFilter(s.filter, convFunc)
+		if scan.policyFilter != nil {
+			if scan.filter == nil {
+				scan.filter = scan.policyFilter
+			} else {
+				scan.filter = makeAnd(scan.policyFilter, scan.filter)
+			}
+		}
 		if s.filter != nil {
 			// Right now we support only one table, so the entire expression
 			// should be converted.
@@ -390,6 +398,18 @@ func (s *selectNode) expandPlan() error {
 				selOrder := s.computeOrdering(indexOrdering)
 				return computeOrderingMatch(ordering, selOrder, false), len(ordering)
 			}
+		} else if s.top.distinct != nil && len(scan.desc.Indexes) > 0 {
+			// No ordering is required, but an index ordered on the rendered columns
+			// (in any order) lets the distinctNode work on sorted rows and the scan
+			// skip duplicates (see distinctNode.initSkipScan).
+			analyzeOrdering = func(indexOrdering orderingInfo) (matchingCols, totalCols int) {
+				selOrder := s.computeOrdering(indexOrdering)
+				matchingCols = len(selOrder.exactMatchCols) + len(selOrder.ordering)
+				if matchingCols > len(s.render) {
+					matchingCols = len(s.render)
+				}
+				return matchingCols, len(s.render)
+			}
 		}
 
 		// If we have a reasonable limit, prefer an order matching index even if
@@ -552,7 +572,9 @@ func (s *selectNode) addRender(target parser.SelectExpr, desiredType parser.Datu
 			outputName = t.Column()
 		}
 	}
-	s.columns = append(s.columns, ResultColumn{Name: outputName, Typ: normalized.ReturnType()})
+	s.columns = append(s.columns, ResultColumn{
+		Name: outputName, Typ: normalized.ReturnType(), TypeModifier: renderTypeModifier(normalized),
+	})
 	return nil
 }
 
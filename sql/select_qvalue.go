@@ -43,6 +43,15 @@ func (cr columnRef) get() ResultColumn {
 	return cr.source.sourceColumns[cr.colIdx]
 }
 
+// renderTypeModifier returns the type modifier of the column referenced by a
+// render expression, or 0 if the expression is not a column reference.
+func renderTypeModifier(expr parser.TypedExpr) int32 {
+	if qval, ok := expr.(*qvalue); ok {
+		return qval.colRef.get().TypeModifier
+	}
+	return 0
+}
+
 type qvalResolver struct {
 	sources multiSourceInfo
 	qvals   qvalMap
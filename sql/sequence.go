@@ -0,0 +1,248 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/privilege"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/encoding"
+)
+
+// SerialNormalization controls the default value given to SERIAL columns.
+type SerialNormalization int
+
+const (
+	// SerialUsesRowID makes SERIAL columns default to unique_rowid(), whose
+	// values are unique across the cluster but large and not consecutive.
+	SerialUsesRowID SerialNormalization = iota
+	// SerialUsesSequence backs every SERIAL column by its own sequence, as
+	// postgres does, so that its values are small increasing integers. The
+	// sequence is a counter incremented outside of the transactions inserting
+	// the rows: the values of aborted transactions are not reused.
+	SerialUsesSequence
+)
+
+func (s SerialNormalization) String() string {
+	switch s {
+	case SerialUsesRowID:
+		return "rowid"
+	case SerialUsesSequence:
+		return "sequence"
+	}
+	return fmt.Sprintf("SerialNormalization(%d)", int(s))
+}
+
+// serialNextValFmt is the default expression of the SERIAL columns backed by
+// a sequence, formatted with the ID of the sequence.
+const serialNextValFmt = "crdb_internal.serial_nextval(%d)"
+
+// sequenceKey returns the key of the counter of a sequence. Sequences are
+// allocated IDs from the descriptors' ones, and don't have descriptors.
+func sequenceKey(id sqlbase.ID) roachpb.Key {
+	return roachpb.Key(keys.MakeTablePrefix(uint32(id)))
+}
+
+// sequenceOwnerKey returns the key holding the ID of the table whose SERIAL
+// column is backed by a sequence. It's written along with the table, and
+// tells the IDs of sequences from the other IDs.
+func sequenceOwnerKey(id sqlbase.ID) roachpb.Key {
+	return encoding.EncodeUvarintAscending(sequenceKey(id), 0)
+}
+
+// writeSequenceOwners records the table as the owner of the sequences backing
+// its SERIAL columns, including the ones being added.
+func (p *planner) writeSequenceOwners(desc *sqlbase.TableDescriptor) error {
+	b := &client.Batch{}
+	put := func(col sqlbase.ColumnDescriptor) {
+		if id, ok := serialSequenceID(col); ok {
+			b.Put(sequenceOwnerKey(id), int64(desc.ID))
+		}
+	}
+	for _, col := range desc.Columns {
+		put(col)
+	}
+	for _, m := range desc.Mutations {
+		if col := m.GetColumn(); col != nil {
+			put(*col)
+		}
+	}
+	return p.txn.Run(b)
+}
+
+// deleteSequence deletes the counter of a sequence, and its owner.
+func deleteSequence(b *client.Batch, id sqlbase.ID) {
+	b.Del(sequenceKey(id))
+	b.Del(sequenceOwnerKey(id))
+}
+
+// serialSequenceID returns the ID of the sequence backing a SERIAL column,
+// if any.
+func serialSequenceID(col sqlbase.ColumnDescriptor) (sqlbase.ID, bool) {
+	if col.DefaultExpr == nil {
+		return 0, false
+	}
+	var id sqlbase.ID
+	if n, err := fmt.Sscanf(*col.DefaultExpr, serialNextValFmt, &id); err != nil || n != 1 {
+		return 0, false
+	}
+	return id, true
+}
+
+// addSerialSequences makes the SERIAL columns among the given column
+// definitions default to the next value of a new sequence, if the session
+// asks for it.
+func (p *planner) addSerialSequences(
+	desc *sqlbase.TableDescriptor, defs ...*parser.ColumnTableDef,
+) error {
+	if p.session.SerialNormalization != SerialUsesSequence {
+		return nil
+	}
+	for _, d := range defs {
+		if t, ok := d.Type.(*parser.IntColType); !ok || !t.IsSerial() {
+			continue
+		}
+		ir, err := p.txn.Inc(keys.DescIDGenerator, 1)
+		if err != nil {
+			return err
+		}
+		s := fmt.Sprintf(serialNextValFmt, ir.ValueInt()-1)
+		if err := setColumnDefault(desc, string(d.Name), s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setColumnDefault sets the default expression of a column of a table, which
+// can be one being added.
+func setColumnDefault(desc *sqlbase.TableDescriptor, name, expr string) error {
+	status, i, err := desc.FindColumnByName(name)
+	if err != nil {
+		return err
+	}
+	if status == sqlbase.DescriptorActive {
+		desc.Columns[i].DefaultExpr = &expr
+	} else {
+		desc.Mutations[i].GetColumn().DefaultExpr = &expr
+	}
+	return nil
+}
+
+var _ parser.SequenceGenerator = &planner{}
+
+// NextVal implements the parser.SequenceGenerator interface. The session user
+// needs the UPDATE privilege on the table owning the sequence.
+func (p *planner) NextVal(id int64) (parser.Datum, error) {
+	if err := p.checkSequence(sqlbase.ID(id)); err != nil {
+		return nil, err
+	}
+	if p.execCtx == nil || p.execCtx.DB == nil {
+		// Planners which aren't executing statements of a session don't have
+		// access to the database outside of their transaction.
+		return txnSequences{txn: p.txn}.NextVal(id)
+	}
+	return dbSequences{db: p.execCtx.DB}.NextVal(id)
+}
+
+// dbSequences increments sequences outside of any transaction, so that the
+// transactions using a sequence don't conflict with each other.
+type dbSequences struct {
+	db *client.DB
+}
+
+var _ parser.SequenceGenerator = dbSequences{}
+
+// NextVal implements the parser.SequenceGenerator interface.
+func (s dbSequences) NextVal(id int64) (parser.Datum, error) {
+	if err := checkSequenceID(id); err != nil {
+		return nil, err
+	}
+	r, err := s.db.Inc(sequenceKey(sqlbase.ID(id)), 1)
+	if err != nil {
+		return nil, err
+	}
+	return parser.NewDInt(parser.DInt(r.ValueInt())), nil
+}
+
+// txnSequences increments sequences in a transaction.
+type txnSequences struct {
+	txn *client.Txn
+}
+
+var _ parser.SequenceGenerator = txnSequences{}
+
+// NextVal implements the parser.SequenceGenerator interface.
+func (s txnSequences) NextVal(id int64) (parser.Datum, error) {
+	if err := checkSequenceID(id); err != nil {
+		return nil, err
+	}
+	r, err := s.txn.Inc(sequenceKey(sqlbase.ID(id)), 1)
+	if err != nil {
+		return nil, err
+	}
+	return parser.NewDInt(parser.DInt(r.ValueInt())), nil
+}
+
+// checkSequenceID rejects the IDs which can't have been allocated to a
+// sequence, so that the keys of the system tables can't be written.
+func checkSequenceID(id int64) error {
+	if id <= keys.MaxReservedDescID {
+		return fmt.Errorf("invalid sequence ID %d", id)
+	}
+	return nil
+}
+
+// checkSequence returns an error unless the ID is the one of a sequence backing
+// a SERIAL column of a table on which the session user has the UPDATE
+// privilege.
+func (p *planner) checkSequence(id sqlbase.ID) error {
+	if err := checkSequenceID(int64(id)); err != nil {
+		return err
+	}
+	gr, err := p.txn.Get(sequenceOwnerKey(id))
+	if err != nil {
+		return err
+	}
+	if !gr.Exists() {
+		return fmt.Errorf("invalid sequence ID %d", id)
+	}
+	desc, err := getTableDescFromID(p.txn, sqlbase.ID(gr.ValueInt()))
+	if err != nil {
+		return err
+	}
+	owned := false
+	for _, col := range desc.Columns {
+		if seqID, ok := serialSequenceID(col); ok && seqID == id {
+			owned = true
+		}
+	}
+	for _, m := range desc.Mutations {
+		if col := m.GetColumn(); col != nil && m.Direction == sqlbase.DescriptorMutation_ADD {
+			if seqID, ok := serialSequenceID(*col); ok && seqID == id {
+				owned = true
+			}
+		}
+	}
+	if !owned || desc.Deleted() {
+		return fmt.Errorf("invalid sequence ID %d", id)
+	}
+	return p.checkPrivilege(desc, privilege.UPDATE)
+}
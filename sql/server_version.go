@@ -0,0 +1,67 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultPGServerVersion is the PostgreSQL version reported to clients unless
+// configured otherwise. It is the latest version of the docs that was
+// consulted during the development of pgwire. We specify this version to avoid
+// having to support old code paths which various client tools fall back to if
+// they can't determine that the server is new enough.
+const DefaultPGServerVersion = "9.5.0"
+
+// PGServerVersionNum returns the server_version_num corresponding to a
+// PostgreSQL server_version: 90500 for "9.5.0" and, since the numbering
+// changed with version 10, 100003 for "10.3".
+func PGServerVersionNum(version string) (int, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return 0, errors.Errorf("invalid PostgreSQL version %q", version)
+	}
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || n > 99 {
+			return 0, errors.Errorf("invalid PostgreSQL version %q", version)
+		}
+		nums[i] = n
+	}
+	if nums[0] >= 10 {
+		if len(parts) > 2 {
+			return 0, errors.Errorf("invalid PostgreSQL version %q", version)
+		}
+		return nums[0]*10000 + nums[1], nil
+	}
+	return nums[0]*10000 + nums[1]*100 + nums[2], nil
+}
+
+// pgServerVersion returns the PostgreSQL version reported to clients.
+func (ctx *ExecutorContext) pgServerVersion() string {
+	if ctx == nil || ctx.PGServerVersion == "" {
+		return DefaultPGServerVersion
+	}
+	return ctx.PGServerVersion
+}
+
+// PGServerVersion returns the PostgreSQL version reported to clients.
+func (e *Executor) PGServerVersion() string {
+	return e.ctx.pgServerVersion()
+}
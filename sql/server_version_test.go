@@ -0,0 +1,51 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+func TestPGServerVersionNum(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		version  string
+		expected int
+	}{
+		{"9.5.0", 90500},
+		{"9.6.3", 90603},
+		{"9.6", 90600},
+		{"10.3", 100003},
+		{"10", 100000},
+	}
+	for _, d := range testData {
+		num, err := PGServerVersionNum(d.version)
+		if err != nil {
+			t.Fatalf("%s: %v", d.version, err)
+		}
+		if num != d.expected {
+			t.Errorf("%s: expected %d, but found %d", d.version, d.expected, num)
+		}
+	}
+
+	for _, version := range []string{"", "9.x", "9.5.0.1", "10.3.1", "-1.0"} {
+		if _, err := PGServerVersionNum(version); err == nil {
+			t.Errorf("%q: expected error", version)
+		}
+	}
+}
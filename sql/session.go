@@ -20,6 +20,7 @@ package sql
 import (
 	"fmt"
 	"net"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/context"
@@ -28,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/base"
 	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/util/log"
 	"github.com/cockroachdb/cockroach/util/retry"
@@ -49,7 +51,38 @@ type Session struct {
 
 	Location              *time.Location
 	DefaultIsolationLevel enginepb.IsolationType
-	Trace                 trace.Trace
+	// ClientMinMessages is the minimum severity of the notices sent to the
+	// client.
+	ClientMinMessages NoticeSeverity
+	// ForceRetryProbability is the probability with which a retryable error
+	// is injected before executing a statement in the first attempt of a
+	// transaction. It lets applications test their retry loops.
+	ForceRetryProbability float64
+	// IntervalStyle is the format of the intervals sent to the client.
+	IntervalStyle parser.IntervalStyle
+	// SafeUpdates causes the statements which are likely to be typos, such as
+	// a DELETE without WHERE clause, to be rejected.
+	SafeUpdates bool
+	// SerialNormalization controls the default value given to SERIAL columns.
+	SerialNormalization SerialNormalization
+	Trace               trace.Trace
+
+	// tempDatabase is the name of the database holding the temporary tables
+	// of the session, and tempTables the names of the tables it created there.
+	// tempDatabasePrefix starts the name of the database (see
+	// makeTempDatabasePrefix).
+	tempDatabase       string
+	tempTables         map[string]struct{}
+	tempDatabasePrefix string
+
+	// queryCancelled is set (atomically) by CancelQuery to interrupt the
+	// request being executed.
+	queryCancelled int32
+
+	// portalRowLimit is the row limit of the portal executed by
+	// ExecutePortal, and cursors are the cursors whose plan is still open.
+	portalRowLimit int
+	cursors        []*Cursor
 }
 
 // SessionArgs contains arguments for creating a new Session with NewSession().
@@ -62,9 +95,12 @@ type SessionArgs struct {
 // remote can be nil.
 func NewSession(args SessionArgs, e *Executor, remote net.Addr) *Session {
 	s := &Session{
-		Database: args.Database,
-		User:     args.User,
-		Location: time.UTC,
+		Database:          args.Database,
+		User:              args.User,
+		Location:          time.UTC,
+		ClientMinMessages: NoticeSeverityNotice,
+
+		tempDatabasePrefix: e.tempDatabasePrefix,
 	}
 	cfg, cache := e.getSystemConfig()
 	s.planner = planner{
@@ -85,12 +121,29 @@ func NewSession(args SessionArgs, e *Executor, remote net.Addr) *Session {
 	return s
 }
 
+// CancelQuery interrupts the request being executed by the Session, if any.
+// The interrupted statement returns a query canceled error. It is safe to call
+// concurrently with the execution of the request.
+func (s *Session) CancelQuery() {
+	atomic.StoreInt32(&s.queryCancelled, 1)
+}
+
+// QueryCancelled returns whether the request being executed by the Session
+// was cancelled by CancelQuery.
+func (s *Session) QueryCancelled() bool {
+	return atomic.LoadInt32(&s.queryCancelled) != 0
+}
+
 // Finish releases resources held by the Session.
 func (s *Session) Finish() {
 	// Cleanup leases. We might have unreleased leases if we're finishing the
 	// session abruptly in the middle of a transaction, or, until #7648 is
 	// addressed, there might be leases accumulated by preparing statements.
 	s.planner.releaseLeases()
+	s.TxnState.finishAdmission()
+	if err := s.dropTempDatabase(); err != nil {
+		log.Warningf("unable to drop the temporary tables of the session: %s", err)
+	}
 	if s.Trace != nil {
 		s.Trace.Finish()
 		s.Trace = nil
@@ -145,8 +198,16 @@ type txnState struct {
 	// the same batch), but not if the error needs to be reported to the user.
 	commitSeen bool
 
+	// A retryable error was injected because of the session's
+	// ForceRetryProbability. At most one is injected per transaction.
+	retryInjected bool
+
 	// The schema change closures to run when this txn is done.
 	schemaChangers schemaChangerCollection
+
+	// The checks of the deferred foreign keys to perform before this txn
+	// commits. Only set for explicit transactions.
+	deferredFKs *fkDeferredChecks
 	// TODO(andrei): this is the same as Session.Trace. Consider removing this and
 	// passing the Session along everywhere the trace is needed.
 	tr trace.Trace
@@ -154,10 +215,15 @@ type txnState struct {
 	// The timestamp to report for current_timestamp(), now() etc.
 	// This must be constant for the lifetime of a SQL transaction.
 	sqlTimestamp time.Time
+
+	// releaseAdmission, if set, releases the place of the txn in the queue
+	// of large statements (see Executor.admitStmt).
+	releaseAdmission func()
 }
 
 // reset creates a new Txn and initializes it using the session defaults.
 func (ts *txnState) reset(ctx context.Context, e *Executor, s *Session) {
+	ts.finishAdmission()
 	*ts = txnState{}
 	ts.txn = client.NewTxn(ctx, *e.ctx.DB)
 	ts.txn.Proto.Isolation = s.DefaultIsolationLevel
@@ -182,6 +248,16 @@ func (ts *txnState) resetStateAndTxn(state TxnStateEnum) {
 
 	ts.State = state
 	ts.txn = nil
+	ts.finishAdmission()
+}
+
+// finishAdmission releases the place of the txn in the queue of large
+// statements, if it has one.
+func (ts *txnState) finishAdmission() {
+	if ts.releaseAdmission != nil {
+		ts.releaseAdmission()
+		ts.releaseAdmission = nil
+	}
 }
 
 // updateStateAndCleanupOnErr updates txnState based on the type of error that we
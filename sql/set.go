@@ -19,6 +19,7 @@ package sql
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,6 +43,13 @@ func (p *planner) Set(n *parser.Set) (planNode, error) {
 	// By using QualifiedName.String() here any variables that are keywords will
 	// be double quoted.
 	name := strings.ToUpper(n.Name.String())
+	if len(n.Values) == 0 {
+		// SET ... TO DEFAULT resets the variable to its default value.
+		if err := p.resetSessionVar(name); err != nil {
+			return nil, err
+		}
+		return &emptyNode{}, nil
+	}
 	typedValues := make([]parser.TypedExpr, len(n.Values))
 	for i, expr := range n.Values {
 		typedValue, err := parser.TypeCheck(expr, nil, parser.TypeString)
@@ -78,6 +86,61 @@ func (p *planner) Set(n *parser.Set) (planNode, error) {
 			return nil, fmt.Errorf("%s: \"%s\" is not in (%q, %q)", name, s, parser.Modern, parser.Traditional)
 		}
 
+	case `CLIENT_MIN_MESSAGES`:
+		s, err := p.getStringVal(name, typedValues)
+		if err != nil {
+			return nil, err
+		}
+		severity, ok := parseNoticeSeverity(s)
+		if !ok {
+			return nil, fmt.Errorf("%s: \"%s\" is not a valid message level", name, s)
+		}
+		p.session.ClientMinMessages = severity
+
+	case `FORCE_RETRY_PROBABILITY`:
+		prob, err := p.getFloatVal(name, typedValues)
+		if err != nil {
+			return nil, err
+		}
+		if prob < 0 || prob > 1 {
+			return nil, fmt.Errorf("%s: %v is not between 0 and 1", name, prob)
+		}
+		p.session.ForceRetryProbability = prob
+
+	case `INTERVALSTYLE`:
+		s, err := p.getStringVal(name, typedValues)
+		if err != nil {
+			return nil, err
+		}
+		style, ok := parser.ParseIntervalStyle(s)
+		if !ok {
+			return nil, fmt.Errorf("%s: \"%s\" is not in (%q, %q, %q)", name, s,
+				parser.IntervalStyleDefault, parser.IntervalStylePostgres, parser.IntervalStyleISO8601)
+		}
+		p.session.IntervalStyle = style
+
+	case `SQL_SAFE_UPDATES`:
+		safe, err := p.getBoolVal(name, typedValues)
+		if err != nil {
+			return nil, err
+		}
+		p.session.SafeUpdates = safe
+
+	case `SERIAL_NORMALIZATION`:
+		s, err := p.getStringVal(name, typedValues)
+		if err != nil {
+			return nil, err
+		}
+		switch sqlbase.NormalizeName(s) {
+		case SerialUsesRowID.String():
+			p.session.SerialNormalization = SerialUsesRowID
+		case SerialUsesSequence.String():
+			p.session.SerialNormalization = SerialUsesSequence
+		default:
+			return nil, fmt.Errorf("%s: \"%s\" is not in (%q, %q)", name, s,
+				SerialUsesRowID, SerialUsesSequence)
+		}
+
 	case `EXTRA_FLOAT_DIGITS`:
 		// These settings are sent by the JDBC driver but we silently ignore them.
 
@@ -87,6 +150,32 @@ func (p *planner) Set(n *parser.Set) (planNode, error) {
 	return &emptyNode{}, nil
 }
 
+// resetSessionVar resets a session variable to the value it has in a new
+// session.
+func (p *planner) resetSessionVar(name string) error {
+	switch name {
+	case `SYNTAX`:
+		p.session.Syntax = int32(parser.Traditional)
+	case `CLIENT_MIN_MESSAGES`:
+		p.session.ClientMinMessages = NoticeSeverityNotice
+	case `FORCE_RETRY_PROBABILITY`:
+		p.session.ForceRetryProbability = 0
+	case `INTERVALSTYLE`:
+		p.session.IntervalStyle = parser.IntervalStyleDefault
+	case `SQL_SAFE_UPDATES`:
+		p.session.SafeUpdates = false
+	case `SERIAL_NORMALIZATION`:
+		p.session.SerialNormalization = SerialUsesRowID
+	case `EXTRA_FLOAT_DIGITS`:
+		// Silently ignored, as when it is set.
+	case `DATABASE`:
+		return fmt.Errorf("%s: has no default value", name)
+	default:
+		return fmt.Errorf("unknown variable: %q", name)
+	}
+	return nil
+}
+
 func (p *planner) getStringVal(name string, values []parser.TypedExpr) (string, error) {
 	if len(values) != 1 {
 		return "", fmt.Errorf("%s: requires a single string value", name)
@@ -103,6 +192,56 @@ func (p *planner) getStringVal(name string, values []parser.TypedExpr) (string,
 	return string(*s), nil
 }
 
+func (p *planner) getBoolVal(name string, values []parser.TypedExpr) (bool, error) {
+	if len(values) != 1 {
+		return false, fmt.Errorf("%s: requires a single boolean value", name)
+	}
+	val, err := values[0].Eval(&p.evalCtx)
+	if err != nil {
+		return false, err
+	}
+	switch v := val.(type) {
+	case *parser.DBool:
+		return bool(*v), nil
+	case *parser.DString:
+		switch sqlbase.NormalizeName(string(*v)) {
+		case "true", "on":
+			return true, nil
+		case "false", "off":
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %q is not a boolean", name, string(*v))
+	}
+	return false, fmt.Errorf("%s: requires a single boolean value: %s is a %s",
+		name, values[0], val.Type())
+}
+
+func (p *planner) getFloatVal(name string, values []parser.TypedExpr) (float64, error) {
+	if len(values) != 1 {
+		return 0, fmt.Errorf("%s: requires a single numeric value", name)
+	}
+	val, err := values[0].Eval(&p.evalCtx)
+	if err != nil {
+		return 0, err
+	}
+	switch v := val.(type) {
+	case *parser.DFloat:
+		return float64(*v), nil
+	case *parser.DDecimal:
+		return strconv.ParseFloat(v.Dec.String(), 64)
+	case *parser.DInt:
+		return float64(*v), nil
+	case *parser.DString:
+		f, err := strconv.ParseFloat(string(*v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %q is not a number", name, string(*v))
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("%s: requires a single numeric value: %s is a %s",
+		name, values[0], val.Type())
+}
+
 func (p *planner) SetDefaultIsolation(n *parser.SetDefaultIsolation) (planNode, error) {
 	switch n.Isolation {
 	case parser.SerializableIsolation:
@@ -0,0 +1,139 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// settingsTableSchema describes the schema of the cluster settings table.
+// Only the settings which were changed from their default have a row.
+const settingsTableSchema = `
+CREATE TABLE system.settings (
+  name         STRING     PRIMARY KEY,
+  value        STRING     NOT NULL,
+  lastUpdated  TIMESTAMP  NOT NULL DEFAULT now()
+);`
+
+// AddSettingsToMetadataSchema adds the cluster settings table to the
+// supplied MetadataSchema.
+func AddSettingsToMetadataSchema(schema *sqlbase.MetadataSchema) {
+	schema.AddTable(keys.SettingsTableID, settingsTableSchema)
+}
+
+// crossDatabaseFKsSetting is the cluster setting which permits foreign keys
+// referencing tables in another database.
+const crossDatabaseFKsSetting = "sql.cross_db_fks.enabled"
+
+// clusterSettings are the boolean settings which apply to the whole
+// cluster, with their default values.
+var clusterSettings = map[string]bool{
+	crossDatabaseFKsSetting: false,
+}
+
+// SetClusterSetting changes the value of a cluster setting, or resets it to
+// its default.
+// Privileges: root.
+//   Notes: postgres and mysql do not have cluster settings.
+func (p *planner) SetClusterSetting(n *parser.SetClusterSetting) (planNode, error) {
+	if p.session.User != security.RootUser {
+		return nil, errors.Errorf("only %s is allowed to change cluster settings", security.RootUser)
+	}
+	name, err := checkClusterSettingName(n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	defer ip.releaseLeases()
+	if n.Value == nil {
+		if _, err := ip.exec(`DELETE FROM system.settings WHERE name = $1`, name); err != nil {
+			return nil, err
+		}
+		return &emptyNode{}, nil
+	}
+
+	typedValue, err := parser.TypeCheck(n.Value, nil, parser.TypeBool)
+	if err != nil {
+		return nil, err
+	}
+	value, err := p.getBoolVal(name, []parser.TypedExpr{typedValue})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ip.exec(
+		`UPSERT INTO system.settings (name, value, lastUpdated) VALUES ($1, $2, now())`,
+		name, fmt.Sprint(value),
+	); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// ShowClusterSetting returns the value of a cluster setting.
+// Privileges: None.
+//   Notes: postgres and mysql do not have cluster settings.
+func (p *planner) ShowClusterSetting(n *parser.ShowClusterSetting) (planNode, error) {
+	name, err := checkClusterSettingName(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	value, err := p.getClusterSetting(name)
+	if err != nil {
+		return nil, err
+	}
+	return &valuesNode{
+		columns: []ResultColumn{{Name: name, Typ: parser.TypeBool}},
+		rows:    []parser.DTuple{{parser.MakeDBool(parser.DBool(value))}},
+	}, nil
+}
+
+// getClusterSetting returns the value of a cluster setting, as seen by the
+// planner's transaction.
+func (p *planner) getClusterSetting(name string) (bool, error) {
+	// The session user may not be able to read system.settings.
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	defer ip.releaseLeases()
+	row, err := ip.queryRow(`SELECT value FROM system.settings WHERE name = $1`, name)
+	if err != nil {
+		return false, err
+	}
+	if row == nil {
+		return clusterSettings[name], nil
+	}
+	value, err := parser.ParseDBool(string(*row[0].(*parser.DString)))
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid value of cluster setting %s", name)
+	}
+	return bool(*value), nil
+}
+
+// checkClusterSettingName returns the name of a known cluster setting.
+func checkClusterSettingName(qname *parser.QualifiedName) (string, error) {
+	name := strings.ToLower(qname.String())
+	if _, ok := clusterSettings[name]; !ok {
+		return "", fmt.Errorf("unknown cluster setting %q", name)
+	}
+	return name, nil
+}
@@ -18,10 +18,16 @@ package sql
 
 import (
 	"bytes"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/security"
 	"github.com/cockroachdb/cockroach/sql/parser"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/util/encoding"
@@ -41,6 +47,29 @@ func (p *planner) Show(n *parser.Show) (planNode, error) {
 		v.rows = append(v.rows, []parser.Datum{parser.NewDString(p.session.Location.String())})
 	case `SYNTAX`:
 		v.rows = append(v.rows, []parser.Datum{parser.NewDString(parser.Syntax(p.session.Syntax).String())})
+	case `SERVER_VERSION`:
+		v.rows = append(v.rows, []parser.Datum{parser.NewDString(p.execCtx.pgServerVersion())})
+	case `SERVER_VERSION_NUM`:
+		num, err := PGServerVersionNum(p.execCtx.pgServerVersion())
+		if err != nil {
+			return nil, err
+		}
+		v.rows = append(v.rows, []parser.Datum{parser.NewDString(strconv.Itoa(num))})
+	case `FORCE_RETRY_PROBABILITY`:
+		v.rows = append(v.rows, []parser.Datum{parser.NewDString(
+			strconv.FormatFloat(p.session.ForceRetryProbability, 'g', -1, 64))})
+	case `CLIENT_MIN_MESSAGES`:
+		v.rows = append(v.rows, []parser.Datum{parser.NewDString(p.session.ClientMinMessages.String())})
+	case `INTERVALSTYLE`:
+		v.rows = append(v.rows, []parser.Datum{parser.NewDString(p.session.IntervalStyle.String())})
+	case `SQL_SAFE_UPDATES`:
+		safeUpdates := "off"
+		if p.session.SafeUpdates {
+			safeUpdates = "on"
+		}
+		v.rows = append(v.rows, []parser.Datum{parser.NewDString(safeUpdates)})
+	case `SERIAL_NORMALIZATION`:
+		v.rows = append(v.rows, []parser.Datum{parser.NewDString(p.session.SerialNormalization.String())})
 	case `DEFAULT_TRANSACTION_ISOLATION`:
 		level := p.session.DefaultIsolationLevel.String()
 		v.rows = append(v.rows, []parser.Datum{parser.NewDString(level)})
@@ -73,25 +102,51 @@ func (p *planner) ShowColumns(n *parser.ShowColumns) (planNode, error) {
 			{Name: "Default", Typ: parser.TypeString},
 		},
 	}
+	if n.WithComment {
+		v.columns = append(v.columns, ResultColumn{Name: "Comment", Typ: parser.TypeString})
+	}
 
 	for i, col := range desc.Columns {
 		defaultExpr := parser.Datum(parser.DNull)
 		if e := desc.Columns[i].DefaultExpr; e != nil {
 			defaultExpr = parser.NewDString(*e)
 		}
-		v.rows = append(v.rows, []parser.Datum{
+		row := []parser.Datum{
 			parser.NewDString(desc.Columns[i].Name),
 			parser.NewDString(col.Type.SQLString()),
 			parser.MakeDBool(parser.DBool(desc.Columns[i].Nullable)),
 			defaultExpr,
-		})
+		}
+		if n.WithComment {
+			comment, err := p.getComment(columnCommentType, desc.ID, int(col.ID))
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, comment)
+		}
+		v.rows = append(v.rows, row)
 	}
 	return v, nil
 }
 
+// showTableName returns the quoted name of the given table, qualified with the
+// name of its database if that is not the database with the given ID.
+func (p *planner) showTableName(desc *sqlbase.TableDescriptor, dbID sqlbase.ID) (string, error) {
+	if desc.ParentID == dbID {
+		return quoteNames(desc.Name), nil
+	}
+	dbDesc, err := getDatabaseDescFromID(p.txn, desc.ParentID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s", quoteNames(dbDesc.Name), quoteNames(desc.Name)), nil
+}
+
 // showCreateInterleave returns an INTERLEAVE IN PARENT clause for the specified
 // index, if applicable.
-func (p *planner) showCreateInterleave(idx *sqlbase.IndexDescriptor) (string, error) {
+func (p *planner) showCreateInterleave(
+	desc *sqlbase.TableDescriptor, idx *sqlbase.IndexDescriptor,
+) (string, error) {
 	if len(idx.Interleave.Ancestors) == 0 {
 		return "", nil
 	}
@@ -104,8 +159,85 @@ func (p *planner) showCreateInterleave(idx *sqlbase.IndexDescriptor) (string, er
 	for _, ancestor := range intl.Ancestors {
 		sharedPrefixLen += int(ancestor.SharedPrefixLen)
 	}
+	parentName, err := p.showTableName(parentTable, desc.ParentID)
+	if err != nil {
+		return "", err
+	}
 	interleavedColumnNames := quoteNames(idx.ColumnNames[:sharedPrefixLen]...)
-	s := fmt.Sprintf(" INTERLEAVE IN PARENT %s (%s)", parentTable.Name, interleavedColumnNames)
+	s := fmt.Sprintf(" INTERLEAVE IN PARENT %s (%s)", parentName, interleavedColumnNames)
+	if intl.DropBehavior != sqlbase.InterleaveDescriptor_DEFAULT {
+		s += " " + intl.DropBehavior.String()
+	}
+	return s, nil
+}
+
+// showCreatePartitioning returns the PARTITION BY clause of an index, or the
+// empty string if the index is not partitioned.
+func showCreatePartitioning(idx *sqlbase.IndexDescriptor) string {
+	part := &idx.Partitioning
+	if part.NumColumns == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	kind := "RANGE"
+	if len(part.List) > 0 {
+		kind = "LIST"
+	}
+	fmt.Fprintf(&buf, " PARTITION BY %s (%s) (", kind,
+		quoteNames(idx.ColumnNames[:part.NumColumns]...))
+	for i, l := range part.List {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "PARTITION %s VALUES IN (%s)",
+			quoteNames(l.Name), strings.Join(l.Values, ", "))
+	}
+	for i, r := range part.Range {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "PARTITION %s VALUES FROM %s TO %s", quoteNames(r.Name), r.From, r.To)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// showCreateFK returns a REFERENCES clause for the foreign key of the
+// specified index, to be attached to the definition of the index's first
+// column.
+func (p *planner) showCreateFK(
+	desc *sqlbase.TableDescriptor, idx *sqlbase.IndexDescriptor,
+) (string, error) {
+	fk := idx.ForeignKey
+	other, err := getTableDescFromID(p.txn, fk.Table)
+	if err != nil {
+		return "", err
+	}
+	otherIdx, err := other.FindIndexByID(fk.Index)
+	if err != nil {
+		return "", err
+	}
+	otherName, err := p.showTableName(other, desc.ParentID)
+	if err != nil {
+		return "", err
+	}
+	s := fmt.Sprintf(" CONSTRAINT %s REFERENCES %s (%s)",
+		quoteNames(fk.Name), otherName, quoteNames(otherIdx.ColumnNames[0]))
+	if fk.OnDelete != sqlbase.ForeignKeyReference_RESTRICT {
+		s += " ON DELETE " + strings.Replace(fk.OnDelete.String(), "_", " ", -1)
+	}
+	if fk.OnUpdate != sqlbase.ForeignKeyReference_RESTRICT {
+		s += " ON UPDATE " + strings.Replace(fk.OnUpdate.String(), "_", " ", -1)
+	}
+	if fk.Deferrable {
+		s += " DEFERRABLE"
+	}
+	if fk.InitiallyDeferred {
+		s += " INITIALLY DEFERRED"
+	}
+	if isUnvalidated(fk.Validity) {
+		s += " NOT VALID"
+	}
 	return s, nil
 }
 
@@ -125,6 +257,18 @@ func (p *planner) ShowCreateTable(n *parser.ShowCreateTable) (planNode, error) {
 		},
 	}
 
+	// Foreign keys are declared on the first column of the index which
+	// holds them.
+	fkIndexes := make(map[sqlbase.ColumnID]*sqlbase.IndexDescriptor)
+	for _, idx := range append([]sqlbase.IndexDescriptor{desc.PrimaryIndex}, desc.Indexes...) {
+		if idx.ForeignKey != nil {
+			if _, ok := fkIndexes[idx.ColumnIDs[0]]; !ok {
+				idx := idx
+				fkIndexes[idx.ColumnIDs[0]] = &idx
+			}
+		}
+	}
+
 	var buf bytes.Buffer
 	fmt.Fprintf(&buf, "CREATE TABLE %s (", quoteNames(n.Table.String()))
 	var primary string
@@ -148,6 +292,16 @@ func (p *planner) ShowCreateTable(n *parser.ShowCreateTable) (planNode, error) {
 			}
 			fmt.Fprintf(&buf, " DEFAULT %s", *col.DefaultExpr)
 		}
+		if col.ComputedExpr != nil {
+			fmt.Fprintf(&buf, " AS (%s) %s", *col.ComputedExpr, computedColumnStorage[col.Stored])
+		}
+		if idx, ok := fkIndexes[col.ID]; ok {
+			fk, err := p.showCreateFK(desc, idx)
+			if err != nil {
+				return nil, err
+			}
+			buf.WriteString(fk)
+		}
 		if desc.PrimaryIndex.ColumnIDs[0] == col.ID {
 			// Only set primary if the primary key is on a visible column (not rowid).
 			primary = fmt.Sprintf(",\n\tCONSTRAINT %s PRIMARY KEY (%s)",
@@ -162,16 +316,33 @@ func (p *planner) ShowCreateTable(n *parser.ShowCreateTable) (planNode, error) {
 		if len(idx.StoreColumnNames) > 0 {
 			storing = fmt.Sprintf(" STORING (%s)", quoteNames(idx.StoreColumnNames...))
 		}
-		interleave, err := p.showCreateInterleave(&idx)
+		interleave, err := p.showCreateInterleave(desc, &idx)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Fprintf(&buf, ",\n\t%sINDEX %s (%s)%s%s",
+		var cols []string
+		var sharded string
+		for i := range idx.ColumnNames {
+			if i == 0 && idx.ShardBuckets > 0 {
+				// The shard column is implied by USING HASH.
+				sharded = fmt.Sprintf(" USING HASH WITH BUCKET_COUNT = %d", idx.ShardBuckets)
+				continue
+			}
+			elem, err := indexElem(desc, &idx, i)
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, parser.AsString(elem))
+		}
+		fmt.Fprintf(&buf, ",\n\t%s%sINDEX %s (%s)%s%s%s%s",
 			isUnique[idx.Unique],
+			indexTypeName[idx.Type],
 			quoteNames(idx.Name),
-			quoteNames(idx.ColumnNames...),
+			strings.Join(cols, ", "),
+			sharded,
 			storing,
 			interleave,
+			showCreatePartitioning(&idx),
 		)
 	}
 	for _, fam := range desc.Families {
@@ -193,14 +364,18 @@ func (p *planner) ShowCreateTable(n *parser.ShowCreateTable) (planNode, error) {
 			fmt.Fprintf(&buf, "CONSTRAINT %s ", quoteNames(e.Name))
 		}
 		fmt.Fprintf(&buf, "CHECK (%s)", e.Expr)
+		if isUnvalidated(e.Validity) {
+			buf.WriteString(" NOT VALID")
+		}
 	}
 
 	buf.WriteString("\n)")
-	interleave, err := p.showCreateInterleave(&desc.PrimaryIndex)
+	interleave, err := p.showCreateInterleave(desc, &desc.PrimaryIndex)
 	if err != nil {
 		return nil, err
 	}
 	buf.WriteString(interleave)
+	buf.WriteString(showCreatePartitioning(&desc.PrimaryIndex))
 
 	v.rows = append(v.rows, []parser.Datum{
 		parser.NewDString(n.Table.String()),
@@ -211,6 +386,27 @@ func (p *planner) ShowCreateTable(n *parser.ShowCreateTable) (planNode, error) {
 
 var isUnique = map[bool]string{true: "UNIQUE "}
 
+var computedColumnStorage = map[bool]string{false: "VIRTUAL", true: "STORED"}
+
+var indexTypeName = map[sqlbase.IndexDescriptor_Type]string{sqlbase.IndexDescriptor_INVERTED: "INVERTED "}
+
+// indexElem returns the i-th column of an index as it is written in CREATE
+// INDEX, which is the expression of the column for expression indexes. The
+// direction of the column is not set.
+func indexElem(
+	desc *sqlbase.TableDescriptor, idx *sqlbase.IndexDescriptor, i int,
+) (parser.IndexElem, error) {
+	elem := parser.IndexElem{Column: parser.Name(idx.ColumnNames[i])}
+	col, err := desc.FindColumnByID(idx.ColumnIDs[i])
+	if err != nil {
+		return elem, err
+	}
+	if col.IsIndexExpr() {
+		elem.Expr, err = parser.ParseExprTraditional(*col.ComputedExpr)
+	}
+	return elem, err
+}
+
 // quoteName quotes based on Traditional syntax and adds commas between names.
 func quoteNames(names ...string) string {
 	return parser.NameList(names).String()
@@ -231,17 +427,111 @@ func (p *planner) ShowDatabases(n *parser.ShowDatabases) (planNode, error) {
 		return nil, err
 	}
 	v := &valuesNode{columns: []ResultColumn{{Name: "Database", Typ: parser.TypeString}}}
+	if n.WithComment {
+		v.columns = append(v.columns, ResultColumn{Name: "Comment", Typ: parser.TypeString})
+	}
+	if n.WithDetails {
+		v.columns = append(v.columns, detailsColumns...)
+	}
 	for _, row := range sr {
 		_, name, err := encoding.DecodeUnsafeStringAscending(
 			bytes.TrimPrefix(row.Key, prefix), nil)
 		if err != nil {
 			return nil, err
 		}
-		v.rows = append(v.rows, []parser.Datum{parser.NewDString(name)})
+		// The databases holding the temporary tables of the sessions are
+		// not listed.
+		if isTempDatabase(name) {
+			continue
+		}
+		values := []parser.Datum{parser.NewDString(name)}
+		if n.WithComment {
+			dbDesc, err := p.mustGetDatabaseDesc(name)
+			if err != nil {
+				return nil, err
+			}
+			comment, err := p.getComment(databaseCommentType, dbDesc.ID, 0)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, comment)
+		}
+		if n.WithDetails {
+			details, err := p.databaseDetails(name)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, details...)
+		}
+		v.rows = append(v.rows, values)
 	}
 	return v, nil
 }
 
+// detailsColumns are the columns added by WITH DETAILS to SHOW DATABASES and
+// SHOW TABLES: the number of rows counted by the most recent statistic of
+// each table, if any, and the number of bytes of the live data of a single
+// replica and of all the data of all the replicas.
+var detailsColumns = []ResultColumn{
+	{Name: "EstimatedRows", Typ: parser.TypeInt},
+	{Name: "LogicalBytes", Typ: parser.TypeInt},
+	{Name: "PhysicalBytes", Typ: parser.TypeInt},
+}
+
+// tableDetails returns the values of the detailsColumns for a table.
+func (p *planner) tableDetails(desc *sqlbase.TableDescriptor) (parser.DTuple, error) {
+	rows, err := p.estimatedRowCount(desc)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := p.tableSpanStats(desc)
+	if err != nil {
+		return nil, err
+	}
+	return parser.DTuple{
+		rows,
+		parser.NewDInt(parser.DInt(stats.logicalBytes())),
+		parser.NewDInt(parser.DInt(stats.physicalBytes())),
+	}, nil
+}
+
+// databaseDetails returns the values of the detailsColumns for a database,
+// which are the sums of the ones of its tables. The estimated number of rows
+// is NULL if none of the tables has statistics.
+func (p *planner) databaseDetails(name string) (parser.DTuple, error) {
+	dbDesc, err := p.mustGetDatabaseDesc(name)
+	if err != nil {
+		return nil, err
+	}
+	tableNames, err := p.getTableNames(dbDesc)
+	if err != nil {
+		return nil, err
+	}
+	var rows, logical, physical parser.DInt
+	hasRows := false
+	for _, tableName := range tableNames {
+		desc, err := p.mustGetTableDesc(tableName)
+		if err != nil {
+			return nil, err
+		}
+		details, err := p.tableDetails(desc)
+		if err != nil {
+			return nil, err
+		}
+		if r, ok := details[0].(*parser.DInt); ok {
+			rows += *r
+			hasRows = true
+		}
+		logical += *details[1].(*parser.DInt)
+		physical += *details[2].(*parser.DInt)
+	}
+	var rowsDatum parser.Datum = parser.DNull
+	if hasRows {
+		rowsDatum = parser.NewDInt(rows)
+	}
+	return parser.DTuple{rowsDatum, parser.NewDInt(logical), parser.NewDInt(physical)}, nil
+}
+
 // ShowGrants returns grant details for the specified objects and users.
 // TODO(marc): implement no targets (meaning full scan).
 // Privileges: None.
@@ -315,10 +605,13 @@ func (p *planner) ShowIndex(n *parser.ShowIndex) (planNode, error) {
 			{Name: "Storing", Typ: parser.TypeBool},
 		},
 	}
+	if n.WithComment {
+		v.columns = append(v.columns, ResultColumn{Name: "Comment", Typ: parser.TypeString})
+	}
 
 	appendRow := func(index sqlbase.IndexDescriptor, colName string, sequence int,
-		direction string, isStored bool) {
-		v.rows = append(v.rows, []parser.Datum{
+		direction string, isStored bool, comment parser.Datum) {
+		row := []parser.Datum{
 			parser.NewDString(n.Table.Table()),
 			parser.NewDString(index.Name),
 			parser.MakeDBool(parser.DBool(index.Unique)),
@@ -326,23 +619,126 @@ func (p *planner) ShowIndex(n *parser.ShowIndex) (planNode, error) {
 			parser.NewDString(colName),
 			parser.NewDString(direction),
 			parser.MakeDBool(parser.DBool(isStored)),
-		})
+		}
+		if n.WithComment {
+			row = append(row, comment)
+		}
+		v.rows = append(v.rows, row)
 	}
 
 	for _, index := range append([]sqlbase.IndexDescriptor{desc.PrimaryIndex}, desc.Indexes...) {
+		// The comment on an index is repeated on the row of each of its
+		// columns.
+		var comment parser.Datum
+		if n.WithComment {
+			if comment, err = p.getComment(indexCommentType, desc.ID, int(index.ID)); err != nil {
+				return nil, err
+			}
+		}
 		sequence := 1
-		for i, col := range index.ColumnNames {
-			appendRow(index, col, sequence, index.ColumnDirections[i].String(), false)
+		for i, col := range desc.IndexColumnDisplayNames(&index) {
+			appendRow(index, col, sequence, index.ColumnDirections[i].String(), false, comment)
 			sequence++
 		}
 		for _, col := range index.StoreColumnNames {
-			appendRow(index, col, sequence, "N/A", true)
+			appendRow(index, col, sequence, "N/A", true, comment)
 			sequence++
 		}
 	}
 	return v, nil
 }
 
+// ShowIndexUsage returns the number of reads of each index of a table, as
+// recorded in system.index_usage, along with the indexes which look like they
+// could be dropped: the secondary indexes that have never been read and the
+// non-unique indexes whose columns are a prefix of the columns of another
+// index of the table.
+// Privileges: SELECT on system.index_usage.
+//   Notes: postgres and mysql do not have a SHOW INDEX USAGE statement.
+func (p *planner) ShowIndexUsage(n *parser.ShowIndexUsage) (planNode, error) {
+	desc, err := p.mustGetTableDesc(n.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[sqlbase.IndexID]parser.DTuple)
+	plan, err := p.query(`SELECT indexID, reads, lastRead FROM system.index_usage
+WHERE tableID = $1`, int(desc.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.Start(); err != nil {
+		return nil, err
+	}
+	for {
+		next, err := plan.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !next {
+			break
+		}
+		values := plan.Values()
+		usage[sqlbase.IndexID(*values[0].(*parser.DInt))] = parser.DTuple{values[1], values[2]}
+	}
+
+	v := &valuesNode{
+		columns: []ResultColumn{
+			{Name: "Table", Typ: parser.TypeString},
+			{Name: "Name", Typ: parser.TypeString},
+			{Name: "Reads", Typ: parser.TypeInt},
+			{Name: "LastRead", Typ: parser.TypeTimestamp},
+			{Name: "Unused", Typ: parser.TypeBool},
+			{Name: "RedundantWith", Typ: parser.TypeString},
+		},
+	}
+
+	indexes := append([]sqlbase.IndexDescriptor{desc.PrimaryIndex}, desc.Indexes...)
+	for _, index := range indexes {
+		reads, lastRead := parser.Datum(parser.NewDInt(0)), parser.Datum(parser.DNull)
+		if u, ok := usage[index.ID]; ok {
+			reads, lastRead = u[0], u[1]
+		}
+		// The primary index holds the data of the table and can't be dropped.
+		unused := index.ID != desc.PrimaryIndex.ID && *reads.(*parser.DInt) == 0
+
+		redundantWith := parser.DNull
+		if index.ID != desc.PrimaryIndex.ID && !index.Unique {
+			for _, other := range indexes {
+				if other.ID != index.ID && other.Type == index.Type && isIndexPrefix(index, other) {
+					redundantWith = parser.NewDString(other.Name)
+					break
+				}
+			}
+		}
+
+		v.rows = append(v.rows, []parser.Datum{
+			parser.NewDString(n.Table.Table()),
+			parser.NewDString(index.Name),
+			reads,
+			lastRead,
+			parser.MakeDBool(parser.DBool(unused)),
+			redundantWith,
+		})
+	}
+	return v, nil
+}
+
+// isIndexPrefix returns whether the columns of the index, along with their
+// directions, are a prefix of the columns of the other index, in which case
+// the other index can serve all the scans of the first one.
+func isIndexPrefix(index, other sqlbase.IndexDescriptor) bool {
+	if len(index.ColumnIDs) > len(other.ColumnIDs) {
+		return false
+	}
+	for i, id := range index.ColumnIDs {
+		if id != other.ColumnIDs[i] || index.ColumnDirections[i] != other.ColumnDirections[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // ShowConstraints returns all the constraints for a table.
 // Privileges: None.
 //   Notes: postgres does not have a SHOW CONSTRAINTS statement.
@@ -398,8 +794,12 @@ func (p *planner) ShowConstraints(n *parser.ShowConstraints) (planNode, error) {
 				return nil, errors.Errorf("error resolving index %d in table %s referenced in foreign key",
 					index.ForeignKey.Index, other.Name)
 			}
+			otherName, err := p.showTableName(other, desc.ParentID)
+			if err != nil {
+				return nil, err
+			}
 			appendRow(index.ForeignKey.Name, "FOREIGN KEY", fmt.Sprintf("%v", index.ColumnNames),
-				fmt.Sprintf("%s.%v", other.Name, otherIdx.ColumnNames))
+				fmt.Sprintf("%s.%v", otherName, otherIdx.ColumnNames))
 		}
 	}
 	for _, c := range desc.Checks {
@@ -458,9 +858,163 @@ func (p *planner) ShowTables(n *parser.ShowTables) (planNode, error) {
 		return nil, err
 	}
 	v := &valuesNode{columns: []ResultColumn{{Name: "Table", Typ: parser.TypeString}}}
+	if n.WithComment {
+		v.columns = append(v.columns, ResultColumn{Name: "Comment", Typ: parser.TypeString})
+	}
+	if n.WithDetails {
+		v.columns = append(v.columns, detailsColumns...)
+	}
 	for _, name := range tableNames {
-		v.rows = append(v.rows, []parser.Datum{parser.NewDString(name.Table())})
+		row := []parser.Datum{parser.NewDString(name.Table())}
+		if n.WithComment || n.WithDetails {
+			desc, err := p.mustGetTableDesc(name)
+			if err != nil {
+				return nil, err
+			}
+			if n.WithComment {
+				comment, err := p.getComment(tableCommentType, desc.ID, 0)
+				if err != nil {
+					return nil, err
+				}
+				row = append(row, comment)
+			}
+			if n.WithDetails {
+				details, err := p.tableDetails(desc)
+				if err != nil {
+					return nil, err
+				}
+				row = append(row, details...)
+			}
+		}
+		v.rows = append(v.rows, row)
+	}
+
+	return v, nil
+}
+
+// ShowEvents returns the contents of the event log, most recent first,
+// optionally restricted to the events that target a specific table or
+// database.
+// Privileges: SELECT on system.eventlog.
+//   Notes: postgres and mysql do not have a SHOW EVENTS statement.
+func (p *planner) ShowEvents(n *parser.ShowEvents) (planNode, error) {
+	filter := "true"
+	switch {
+	case n.Table != nil:
+		desc, err := p.getTableDesc(n.Table)
+		if err != nil {
+			return nil, err
+		}
+		if desc != nil {
+			filter = fmt.Sprintf("targetID = %d", desc.ID)
+			break
+		}
+		// The table may have been dropped, in which case its ID is found in
+		// the events of its drop.
+		ids, err := p.droppedTableIDs(n.Table)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, sqlbase.NewUndefinedTableError(n.Table.String())
+		}
+		filter = fmt.Sprintf("targetID IN (%s)", strings.Join(ids, ", "))
+	case n.Database != "":
+		desc, err := p.mustGetDatabaseDesc(string(n.Database))
+		if err != nil {
+			return nil, err
+		}
+		filter = fmt.Sprintf("targetID = %d", desc.ID)
+	}
+	if n.Where != nil {
+		filter = fmt.Sprintf("%s AND (%s)", filter, n.Where.Expr)
+	}
+	return p.query(fmt.Sprintf(`SELECT timestamp, eventType, targetID, reportingID, info
+FROM system.eventlog WHERE %s ORDER BY timestamp DESC, uniqueID DESC`, filter))
+}
+
+// ShowConsistency runs a consistency check on the ranges containing the data
+// of a table and returns the checksum computed by each replica of each of
+// them, and whether it differs from the checksum of the lease holder. The
+// checksum of a replica is NULL if it couldn't be collected. A replica whose
+// data diverges also reports the divergence in its log (or terminates, if so
+// configured).
+// Privileges: security.RootUser user.
+//   Notes: postgres and mysql do not have a SHOW CONSISTENCY statement.
+func (p *planner) ShowConsistency(n *parser.ShowConsistency) (planNode, error) {
+	if p.session.User != security.RootUser {
+		return nil, errors.Errorf("only %s is allowed to check consistency", security.RootUser)
+	}
+	desc, err := p.mustGetTableDesc(n.Table)
+	if err != nil {
+		return nil, err
 	}
 
+	tableStartKey := roachpb.Key(keys.MakeTablePrefix(uint32(desc.ID)))
+	b := &client.Batch{}
+	b.CheckConsistency(tableStartKey, tableStartKey.PrefixEnd(), false /* withDiff */)
+	if err := p.execCtx.DB.Run(b); err != nil {
+		return nil, err
+	}
+	resp := b.RawResponse().Responses[0].GetInner().(*roachpb.CheckConsistencyResponse)
+
+	v := &valuesNode{
+		columns: []ResultColumn{
+			{Name: "RangeID", Typ: parser.TypeInt},
+			{Name: "StartKey", Typ: parser.TypeString},
+			{Name: "NodeID", Typ: parser.TypeInt},
+			{Name: "StoreID", Typ: parser.TypeInt},
+			{Name: "Checksum", Typ: parser.TypeString},
+			{Name: "Mismatch", Typ: parser.TypeBool},
+		},
+	}
+	for _, result := range resp.Results {
+		for _, replica := range result.Replicas {
+			checksum, mismatch := parser.Datum(parser.DNull), parser.Datum(parser.DNull)
+			if replica.Checksum != nil {
+				checksum = parser.NewDString(hex.EncodeToString(replica.Checksum))
+				mismatch = parser.MakeDBool(parser.DBool(!bytes.Equal(replica.Checksum, result.Checksum)))
+			}
+			v.rows = append(v.rows, []parser.Datum{
+				parser.NewDInt(parser.DInt(result.RangeID)),
+				parser.NewDString(result.StartKey.String()),
+				parser.NewDInt(parser.DInt(replica.Replica.NodeID)),
+				parser.NewDInt(parser.DInt(replica.Replica.StoreID)),
+				checksum,
+				mismatch,
+			})
+		}
+	}
 	return v, nil
 }
+
+// droppedTableIDs returns the IDs of the dropped tables which had the given
+// name, as recorded by the events of their drop.
+func (p *planner) droppedTableIDs(qname *parser.QualifiedName) ([]string, error) {
+	// The session user may not be able to read system.eventlog.
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	defer ip.releaseLeases()
+	rows, err := ip.queryRows(
+		`SELECT targetID, info FROM system.eventlog WHERE eventType = $1`, string(EventLogDropTable))
+	if err != nil {
+		return nil, err
+	}
+	dbName, tableName := sqlbase.NormalizeName(qname.Database()), sqlbase.NormalizeName(qname.Table())
+	var ids []string
+	for _, row := range rows {
+		info, ok := row[1].(*parser.DString)
+		if !ok {
+			continue
+		}
+		var event droppedTableEventInfo
+		if err := json.Unmarshal([]byte(*info), &event); err != nil {
+			return nil, errors.Wrapf(err, "invalid info of event %s", EventLogDropTable)
+		}
+		if sqlbase.NormalizeName(event.DatabaseName) == dbName &&
+			sqlbase.NormalizeName(event.TableName) == tableName {
+			ids = append(ids, row[0].String())
+		}
+	}
+	return ids, nil
+}
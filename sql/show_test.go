@@ -17,10 +17,22 @@
 package sql_test
 
 import (
+	gosql "database/sql"
 	"fmt"
 	"testing"
 
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/storage"
+	"github.com/cockroachdb/cockroach/storage/engine"
 	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/util"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 )
 
@@ -171,3 +183,109 @@ func TestShowCreateTable(t *testing.T) {
 		}
 	}
 }
+
+// TestShowConsistency writes data to a single replica of the range of a table
+// and checks that SHOW CONSISTENCY reports that replica, and only it, as
+// diverging from the lease holder.
+func TestShowConsistency(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationAuto,
+		ServerArgs: base.TestServerArgs{
+			Knobs: base.TestingKnobs{
+				Store: &storage.StoreTestingKnobs{
+					// Don't terminate the diverging replica.
+					BadChecksumPanic: func([]storage.ReplicaSnapshotDiff) {},
+				},
+			},
+		},
+	})
+	defer tc.Stopper().Stop()
+	sqlDB := tc.ServerConn(0)
+
+	if _, err := sqlDB.Exec(`
+		CREATE DATABASE d;
+		CREATE TABLE d.t (k INT PRIMARY KEY, v INT);
+		INSERT INTO d.t VALUES (1, 1), (2, 2);
+	`); err != nil {
+		t.Fatal(err)
+	}
+	tableDesc := sqlbase.GetTableDescriptor(tc.Servers[0].DB(), "d", "t")
+	tablePrefix := roachpb.Key(keys.MakeTablePrefix(uint32(tableDesc.ID)))
+
+	// Wait for the table to get a range of its own, replicated on all nodes.
+	var desc roachpb.RangeDescriptor
+	util.SucceedsSoon(t, func() error {
+		var err error
+		if desc, err = tc.LookupRange(tablePrefix); err != nil {
+			return err
+		}
+		if !desc.StartKey.Equal(tablePrefix) {
+			return errors.Errorf("table range starts at %s", desc.StartKey)
+		}
+		if len(desc.Replicas) != 3 {
+			return errors.Errorf("table range has %d replicas", len(desc.Replicas))
+		}
+		return nil
+	})
+	leaseHolder, err := tc.FindRangeLeaseHolder(&desc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write a key to one of the other replicas only.
+	var diverging roachpb.ReplicaDescriptor
+	for _, replica := range desc.Replicas {
+		if replica.StoreID != leaseHolder.StoreID {
+			diverging = replica
+			break
+		}
+	}
+	var store *storage.Store
+	for _, s := range tc.Servers {
+		if s.Stores().HasStore(diverging.StoreID) {
+			if store, err = s.Stores().GetStore(diverging.StoreID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	var val roachpb.Value
+	val.SetInt(42)
+	if err := engine.MVCCPut(context.Background(), store.Engine(), nil,
+		append(tablePrefix, "diverging"...), store.Clock().Now(), val, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := sqlDB.Query(`SHOW CONSISTENCY FROM d.t`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	replicas := 0
+	for rows.Next() {
+		var rangeID, nodeID, storeID int64
+		var startKey string
+		var checksum gosql.NullString
+		var mismatch gosql.NullBool
+		if err := rows.Scan(&rangeID, &startKey, &nodeID, &storeID, &checksum, &mismatch); err != nil {
+			t.Fatal(err)
+		}
+		if roachpb.RangeID(rangeID) != desc.RangeID {
+			continue
+		}
+		replicas++
+		if !checksum.Valid || !mismatch.Valid {
+			t.Fatalf("no checksum for store %d", storeID)
+		}
+		if expected := roachpb.StoreID(storeID) == diverging.StoreID; mismatch.Bool != expected {
+			t.Errorf("store %d: expected mismatch %t, got %t", storeID, expected, mismatch.Bool)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if replicas != 3 {
+		t.Fatalf("expected 3 replicas of range %d, got %d", desc.RangeID, replicas)
+	}
+}
@@ -33,6 +33,9 @@ import (
 // sortNode represents a node that sorts the rows returned by its
 // sub-node.
 type sortNode struct {
+	// p is used to check for the cancellation of the statement while the
+	// values are accumulated. It is nil for the sorts of internal results.
+	p        *planner
 	plan     planNode
 	columns  []ResultColumn
 	ordering sqlbase.ColumnOrdering
@@ -147,7 +150,7 @@ func (p *planner) orderBy(orderBy parser.OrderBy, n planNode) (*sortNode, error)
 		ordering = append(ordering, sqlbase.ColumnOrderInfo{ColIdx: index, Direction: direction})
 	}
 
-	return &sortNode{columns: columns, ordering: ordering}, nil
+	return &sortNode{p: p, columns: columns, ordering: ordering}, nil
 }
 
 // colIndex takes an expression that refers to a column using an integer, verifies it refers to a
@@ -334,6 +337,11 @@ func (n *sortNode) Next() (bool, error) {
 
 		values := n.plan.Values()
 		n.sortStrategy.Add(values)
+		if n.p != nil {
+			if err := n.p.checkCancelled(); err != nil {
+				return false, err
+			}
+		}
 
 		if n.explain == explainDebug {
 			// Emit a "buffered" row.
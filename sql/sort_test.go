@@ -0,0 +1,70 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/encoding"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// cancellingNode returns the rows of a valuesNode, and cancels the statement
+// of its planner once it has returned cancelAfter of them.
+type cancellingNode struct {
+	*valuesNode
+	cancelAfter int
+	returned    int
+}
+
+func (n *cancellingNode) Next() (bool, error) {
+	if n.returned == n.cancelAfter {
+		n.p.session.CancelQuery()
+	}
+	n.returned++
+	return n.valuesNode.Next()
+}
+
+// TestSortCancel checks that a sort stops accumulating its values when the
+// statement is cancelled, before it has produced any row.
+func TestSortCancel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	p := makePlanner()
+	const numRows = 1000
+	v := &valuesNode{p: p, columns: []ResultColumn{{Name: "a", Typ: parser.TypeInt}}}
+	for i := numRows; i > 0; i-- {
+		v.rows = append(v.rows, parser.DTuple{parser.NewDInt(parser.DInt(i))})
+	}
+	source := &cancellingNode{valuesNode: v, cancelAfter: 10}
+	n := &sortNode{
+		p:        p,
+		plan:     source,
+		columns:  v.columns,
+		ordering: sqlbase.ColumnOrdering{{ColIdx: 0, Direction: encoding.Ascending}},
+		needSort: true,
+	}
+
+	if _, err := n.Next(); err == nil {
+		t.Fatal("expected the sort to be cancelled")
+	} else if _, ok := err.(*sqlbase.ErrQueryCanceled); !ok {
+		t.Fatalf("expected a query canceled error, but found %v", err)
+	}
+	if source.returned >= numRows {
+		t.Fatalf("the sort read all the %d rows of its source before stopping", source.returned)
+	}
+}
@@ -49,6 +49,22 @@ type ErrorWithPGCode interface {
 	SrcContext() SrcCtx
 }
 
+// ErrorWithPosition represents errors that refer to a position in the
+// statement text. pgwire recognizes this interface and sends the position to
+// the user so that the offending token can be highlighted.
+//
+// Only syntax errors implement it.
+// TODO(knz): give type-check and other semantic errors a position too. This
+// needs the parser to record the source positions of expressions.
+type ErrorWithPosition interface {
+	error
+	// Position returns the 1-based character position in the statement, or 0
+	// if it is unknown.
+	Position() int
+}
+
+var _ ErrorWithPosition = &ErrSyntax{}
+
 var _ ErrorWithPGCode = &ErrNonNullViolation{}
 var _ ErrorWithPGCode = &ErrUniquenessConstraintViolation{}
 var _ ErrorWithPGCode = &ErrTransactionAborted{}
@@ -56,6 +72,12 @@ var _ ErrorWithPGCode = &ErrTransactionCommitted{}
 var _ ErrorWithPGCode = &ErrUndefinedDatabase{}
 var _ ErrorWithPGCode = &ErrUndefinedTable{}
 var _ ErrorWithPGCode = &ErrRetry{}
+var _ ErrorWithPGCode = &ErrForeignKeyViolation{}
+var _ ErrorWithPGCode = &ErrCheckViolation{}
+var _ ErrorWithPGCode = &ErrColumnConversion{}
+var _ ErrorWithPGCode = &ErrUndefinedColumn{}
+var _ ErrorWithPGCode = &ErrSyntax{}
+var _ ErrorWithPGCode = &ErrQueryCanceled{}
 
 const (
 	txnAbortedMsg = "current transaction is aborted, commands ignored " +
@@ -175,12 +197,13 @@ func (e *ErrNonNullViolation) SrcContext() SrcCtx {
 // NewUniquenessConstraintViolationError creates a new
 // ErrUniquenessConstrainViolation.
 func NewUniquenessConstraintViolationError(
-	index *IndexDescriptor, vals []parser.Datum,
+	tableDesc *TableDescriptor, index *IndexDescriptor, vals []parser.Datum,
 ) error {
 	return &ErrUniquenessConstraintViolation{
-		ctx:   MakeSrcCtx(1),
-		index: index,
-		vals:  vals,
+		ctx:         MakeSrcCtx(1),
+		index:       index,
+		columnNames: tableDesc.IndexColumnDisplayNames(index),
+		vals:        vals,
 	}
 }
 
@@ -188,7 +211,10 @@ func NewUniquenessConstraintViolationError(
 type ErrUniquenessConstraintViolation struct {
 	ctx   SrcCtx
 	index *IndexDescriptor
-	vals  []parser.Datum
+	// columnNames are the names of the columns of the index, with the
+	// expressions of expression indexes in place of their columns.
+	columnNames []string
+	vals        []parser.Datum
 }
 
 // Code implements the ErrorWithPGCode interface.
@@ -203,7 +229,7 @@ func (e *ErrUniquenessConstraintViolation) Error() string {
 	}
 
 	return fmt.Sprintf("duplicate key value (%s)=(%s) violates unique constraint %q",
-		strings.Join(e.index.ColumnNames, ","),
+		strings.Join(e.columnNames, ","),
 		strings.Join(valStrs, ","),
 		e.index.Name)
 }
@@ -268,11 +294,177 @@ func (e *ErrUndefinedDatabase) SrcContext() SrcCtx {
 	return e.ctx
 }
 
+// NewForeignKeyViolationError creates a new ErrForeignKeyViolation.
+func NewForeignKeyViolationError(format string, args ...interface{}) error {
+	return &ErrForeignKeyViolation{ctx: MakeSrcCtx(1), msg: fmt.Sprintf(format, args...)}
+}
+
+// ErrForeignKeyViolation represents a violation of a FOREIGN KEY constraint.
+type ErrForeignKeyViolation struct {
+	ctx SrcCtx
+	msg string
+}
+
+func (e *ErrForeignKeyViolation) Error() string {
+	return "foreign key violation: " + e.msg
+}
+
+// Code implements the ErrorWithPGCode interface.
+func (*ErrForeignKeyViolation) Code() string {
+	return pgerror.CodeForeignKeyViolationError
+}
+
+// SrcContext implements the ErrorWithPGCode interface.
+func (e *ErrForeignKeyViolation) SrcContext() SrcCtx {
+	return e.ctx
+}
+
+// NewCheckViolationError creates a new ErrCheckViolation.
+func NewCheckViolationError(expr string) error {
+	return &ErrCheckViolation{ctx: MakeSrcCtx(1), expr: expr}
+}
+
+// ErrCheckViolation represents a violation of a CHECK constraint.
+type ErrCheckViolation struct {
+	ctx  SrcCtx
+	expr string
+}
+
+func (e *ErrCheckViolation) Error() string {
+	return fmt.Sprintf("failed to satisfy CHECK constraint (%s)", e.expr)
+}
+
+// Code implements the ErrorWithPGCode interface.
+func (*ErrCheckViolation) Code() string {
+	return pgerror.CodeCheckViolationError
+}
+
+// SrcContext implements the ErrorWithPGCode interface.
+func (e *ErrCheckViolation) SrcContext() SrcCtx {
+	return e.ctx
+}
+
+// NewColumnConversionError creates a new ErrColumnConversion.
+func NewColumnConversionError(columnName string, err error) error {
+	return &ErrColumnConversion{ctx: MakeSrcCtx(1), columnName: columnName, err: err}
+}
+
+// ErrColumnConversion represents a value of a column whose type is changed
+// that can't be converted to the new type of the column.
+type ErrColumnConversion struct {
+	ctx        SrcCtx
+	columnName string
+	err        error
+}
+
+func (e *ErrColumnConversion) Error() string {
+	return fmt.Sprintf("cannot convert a value of column %q: %v", e.columnName, e.err)
+}
+
+// Code implements the ErrorWithPGCode interface.
+func (*ErrColumnConversion) Code() string {
+	return pgerror.CodeInvalidCharacterValueForCastError
+}
+
+// SrcContext implements the ErrorWithPGCode interface.
+func (e *ErrColumnConversion) SrcContext() SrcCtx {
+	return e.ctx
+}
+
+// NewUndefinedColumnError creates a new ErrUndefinedColumn.
+func NewUndefinedColumnError(name string) error {
+	return &ErrUndefinedColumn{ctx: MakeSrcCtx(1), msg: fmt.Sprintf("column %q does not exist", name)}
+}
+
+// NewUnresolvedNameError creates a new ErrUndefinedColumn for a qualified name
+// in an expression that does not match any column of the data sources.
+func NewUnresolvedNameError(name string) error {
+	return &ErrUndefinedColumn{ctx: MakeSrcCtx(1), msg: fmt.Sprintf("qualified name \"%s\" not found", name)}
+}
+
+// ErrUndefinedColumn represents a missing table column.
+type ErrUndefinedColumn struct {
+	ctx SrcCtx
+	msg string
+}
+
+func (e *ErrUndefinedColumn) Error() string {
+	return e.msg
+}
+
+// Code implements the ErrorWithPGCode interface.
+func (*ErrUndefinedColumn) Code() string {
+	return pgerror.CodeUndefinedColumnError
+}
+
+// SrcContext implements the ErrorWithPGCode interface.
+func (e *ErrUndefinedColumn) SrcContext() SrcCtx {
+	return e.ctx
+}
+
+// NewSyntaxError creates a new ErrSyntax. position is the 1-based character
+// position in the statement of the offending token, or 0 if unknown.
+func NewSyntaxError(msg string, position int) error {
+	return &ErrSyntax{ctx: MakeSrcCtx(1), msg: msg, position: position}
+}
+
+// ErrSyntax represents a statement that could not be parsed.
+type ErrSyntax struct {
+	ctx      SrcCtx
+	msg      string
+	position int
+}
+
+func (e *ErrSyntax) Error() string {
+	return e.msg
+}
+
+// Code implements the ErrorWithPGCode interface.
+func (*ErrSyntax) Code() string {
+	return pgerror.CodeSyntaxError
+}
+
+// SrcContext implements the ErrorWithPGCode interface.
+func (e *ErrSyntax) SrcContext() SrcCtx {
+	return e.ctx
+}
+
+// Position implements the ErrorWithPosition interface.
+func (e *ErrSyntax) Position() int {
+	return e.position
+}
+
+// NewQueryCanceledError creates a new ErrQueryCanceled.
+func NewQueryCanceledError() error {
+	return &ErrQueryCanceled{ctx: MakeSrcCtx(1)}
+}
+
+// ErrQueryCanceled signals that a statement was interrupted because the
+// client requested its cancellation.
+type ErrQueryCanceled struct {
+	ctx SrcCtx
+}
+
+func (*ErrQueryCanceled) Error() string {
+	return "canceling statement due to user request"
+}
+
+// Code implements the ErrorWithPGCode interface.
+func (*ErrQueryCanceled) Code() string {
+	return pgerror.CodeQueryCanceledError
+}
+
+// SrcContext implements the ErrorWithPGCode interface.
+func (e *ErrQueryCanceled) SrcContext() SrcCtx {
+	return e.ctx
+}
+
 // IsIntegrityConstraintError returns true if the error is some kind of SQL
 // constraint violation.
 func IsIntegrityConstraintError(err error) bool {
 	switch err.(type) {
-	case *ErrNonNullViolation, *ErrUniquenessConstraintViolation:
+	case *ErrNonNullViolation, *ErrUniquenessConstraintViolation,
+		*ErrForeignKeyViolation, *ErrCheckViolation, *ErrColumnConversion:
 		return true
 	default:
 		return false
@@ -104,12 +104,22 @@ type kvFetcher struct {
 	spans           Spans
 	reverse         bool
 	firstBatchLimit int64
+	// If set, these keys are retrieved with Get requests instead of scanning
+	// the spans.
+	getKeys []roachpb.Key
+	// If set, checkCancelled is called before each batch is retrieved, and
+	// the scan stops with its error.
+	checkCancelled func() error
 
 	batchIdx     int
 	fetchEnd     bool
 	kvs          []client.KeyValue
 	kvIndex      int
 	totalFetched int64
+
+	// If set, the next batch resumes after the keys with this prefix instead
+	// of after the last retrieved key (see skipPrefix).
+	skippedPrefix roachpb.Key
 }
 
 // getBatchSize returns the max size of the next batch.
@@ -160,8 +170,47 @@ func makeKVFetcher(txn *client.Txn, spans Spans, reverse bool, firstBatchLimit i
 	return kvFetcher{txn: txn, spans: spans, reverse: reverse, firstBatchLimit: firstBatchLimit}
 }
 
+// makeKVGetFetcher initializes a kvFetcher which retrieves the given keys with
+// Get requests, in a single batch. Keys which do not exist are skipped.
+func makeKVGetFetcher(txn *client.Txn, keys []roachpb.Key) kvFetcher {
+	return kvFetcher{txn: txn, getKeys: keys}
+}
+
+// fetchGets retrieves the keys of a kvFetcher made by makeKVGetFetcher.
+func (f *kvFetcher) fetchGets() error {
+	b := &client.Batch{}
+	for _, key := range f.getKeys {
+		b.Get(key)
+	}
+	if err := f.txn.Run(b); err != nil {
+		return err
+	}
+
+	f.kvs = make([]client.KeyValue, 0, len(f.getKeys))
+	for _, result := range b.Results {
+		if kv := result.Rows[0]; kv.Exists() {
+			f.kvs = append(f.kvs, kv)
+		}
+	}
+
+	f.batchIdx++
+	f.totalFetched += int64(len(f.kvs))
+	f.kvIndex = 0
+	f.fetchEnd = true
+	return nil
+}
+
 // fetch retrieves spans from the kv
 func (f *kvFetcher) fetch() error {
+	if f.checkCancelled != nil {
+		if err := f.checkCancelled(); err != nil {
+			return err
+		}
+	}
+	if f.getKeys != nil {
+		return f.fetchGets()
+	}
+
 	batchSize := f.getBatchSize()
 
 	b := &client.Batch{}
@@ -177,6 +226,16 @@ func (f *kvFetcher) fetch() error {
 			resumeKey = resumeKey.Next()
 		}
 	}
+	if f.skippedPrefix != nil {
+		// The remaining keys with the skipped prefix are not needed; resume
+		// forward scans at the end of the prefix and reverse scans at its start.
+		if !f.reverse {
+			resumeKey = f.skippedPrefix.PrefixEnd()
+		} else {
+			resumeKey = f.skippedPrefix
+		}
+		f.skippedPrefix = nil
+	}
 
 	atEnd := true
 	if !f.reverse {
@@ -258,6 +317,22 @@ func (f *kvFetcher) fetch() error {
 	return nil
 }
 
+// skipPrefix skips the following key/values which have the given prefix.
+// Those that were already retrieved are dropped; if they extend past the
+// current batch, the next batch seeks to the end of the prefix so that the
+// rest of them are never read. The batch after a seek is limited again like
+// the first batch: when the fetcher was started with a limit of a row, each
+// prefix then costs a single seek, whatever its number of rows.
+func (f *kvFetcher) skipPrefix(prefix roachpb.Key) {
+	for f.kvIndex < len(f.kvs) && bytes.HasPrefix(f.kvs[f.kvIndex].Key, prefix) {
+		f.kvIndex++
+	}
+	if f.kvIndex == len(f.kvs) && !f.fetchEnd {
+		f.skippedPrefix = prefix
+		f.batchIdx = 0
+	}
+}
+
 // nextKV returns the next key/value (initiating fetches as necessary). When there are no more keys,
 // returns false and an empty key/value.
 func (f *kvFetcher) nextKV() (bool, client.KeyValue, error) {
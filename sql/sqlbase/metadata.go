@@ -112,6 +112,13 @@ func (ms *MetadataSchema) AddTable(id ID, definition string) {
 	})
 }
 
+// CreateSystemTableDescriptor returns the descriptor of a table added to the
+// system database with AddTable. It's used to create the system tables which
+// were added after a cluster was bootstrapped.
+func CreateSystemTableDescriptor(id ID, definition string) TableDescriptor {
+	return createTableDescriptor(id, keys.SystemDatabaseID, definition, NewDefaultPrivilegeDescriptor())
+}
+
 // DescriptorCount returns the number of descriptors that will be created by
 // this schema. This value is needed to automate certain tests.
 func (ms MetadataSchema) DescriptorCount() int {
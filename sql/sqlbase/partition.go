@@ -0,0 +1,179 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+)
+
+// MakePartitioning makes the descriptor of the partitioning of an index by the
+// values of a prefix of its columns. The values of the partitions must be
+// constants of the types of the partitioned columns, the values of a list
+// partitioning must be in at most one partition and the ranges of a range
+// partitioning must not overlap.
+func MakePartitioning(
+	desc *TableDescriptor, index *IndexDescriptor, partBy *parser.PartitionBy,
+) (PartitioningDescriptor, error) {
+	var part PartitioningDescriptor
+	if len(partBy.Fields) > len(index.ColumnNames) {
+		return part, partitionColumnsMismatchError(partBy, index)
+	}
+	cols := make([]ColumnDescriptor, len(partBy.Fields))
+	for i, field := range partBy.Fields {
+		if NormalizeName(string(field)) != NormalizeName(index.ColumnNames[i]) {
+			return part, partitionColumnsMismatchError(partBy, index)
+		}
+		// The column IDs of the index are not allocated yet when the table is
+		// being created.
+		col, err := desc.FindActiveColumnByName(index.ColumnNames[i])
+		if err != nil {
+			return part, err
+		}
+		cols[i] = col
+	}
+	part.NumColumns = uint32(len(cols))
+
+	names := make(map[string]struct{})
+	checkName := func(name parser.Name) error {
+		normName := NormalizeName(string(name))
+		if _, ok := names[normName]; ok {
+			return fmt.Errorf("partition %q specified more than once", name)
+		}
+		names[normName] = struct{}{}
+		return nil
+	}
+
+	var evalCtx parser.EvalContext
+	type listValue struct {
+		name  parser.Name
+		tuple *parser.DTuple
+	}
+	var values []listValue
+	for _, l := range partBy.List {
+		if err := checkName(l.Name); err != nil {
+			return part, err
+		}
+		list := PartitioningDescriptor_List{Name: string(l.Name)}
+		for _, expr := range l.Exprs {
+			exprs := parser.Exprs{expr}
+			if t, ok := expr.(*parser.Tuple); ok && len(cols) > 1 {
+				exprs = t.Exprs
+			}
+			tuple, err := makePartitionTuple(&evalCtx, cols, exprs, l.Name)
+			if err != nil {
+				return part, err
+			}
+			// The values are compared as datums, since equal values may be
+			// written differently, e.g. 1.0 and 1.00.
+			for _, other := range values {
+				if other.tuple.Compare(tuple) == 0 {
+					return part, fmt.Errorf("%s cannot be present in more than one partition (%q and %q)",
+						tuple, other.name, l.Name)
+				}
+			}
+			values = append(values, listValue{name: l.Name, tuple: tuple})
+			// The value of a single column is not written as a tuple.
+			value := tuple.String()
+			if len(cols) == 1 {
+				value = (*tuple)[0].String()
+			}
+			list.Values = append(list.Values, value)
+		}
+		part.List = append(part.List, list)
+	}
+
+	type bounds struct {
+		name     parser.Name
+		from, to *parser.DTuple
+	}
+	var ranges []bounds
+	for _, r := range partBy.Range {
+		if err := checkName(r.Name); err != nil {
+			return part, err
+		}
+		from, err := makePartitionTuple(&evalCtx, cols, r.From, r.Name)
+		if err != nil {
+			return part, err
+		}
+		to, err := makePartitionTuple(&evalCtx, cols, r.To, r.Name)
+		if err != nil {
+			return part, err
+		}
+		if from.Compare(to) >= 0 {
+			return part, fmt.Errorf("lower bound %s of partition %q is not less than its upper bound %s",
+				from, r.Name, to)
+		}
+		ranges = append(ranges, bounds{name: r.Name, from: from, to: to})
+		part.Range = append(part.Range, PartitioningDescriptor_Range{
+			Name: string(r.Name),
+			From: from.String(),
+			To:   to.String(),
+		})
+	}
+	for i := range ranges {
+		for j := i + 1; j < len(ranges); j++ {
+			a, b := ranges[i], ranges[j]
+			if a.from.Compare(b.to) < 0 && b.from.Compare(a.to) < 0 {
+				return part, fmt.Errorf("partitions %q and %q overlap", a.name, b.name)
+			}
+		}
+	}
+	return part, nil
+}
+
+// makePartitionTuple evaluates the values of the partitioned columns given in
+// the definition of a partition.
+func makePartitionTuple(
+	evalCtx *parser.EvalContext, cols []ColumnDescriptor, exprs parser.Exprs, name parser.Name,
+) (*parser.DTuple, error) {
+	context := fmt.Sprintf("partition %q", name)
+	if len(exprs) != len(cols) {
+		return nil, fmt.Errorf("%s: expected %d values, found %d", context, len(cols), len(exprs))
+	}
+	tuple := make(parser.DTuple, len(exprs))
+	for i, expr := range exprs {
+		if parser.ContainsVars(expr) {
+			return nil, exprContainsVarsError(context, expr)
+		}
+		colType := cols[i].Type.ToDatumType()
+		typedExpr, err := parser.TypeCheck(expr, nil, colType)
+		if err != nil {
+			return nil, err
+		}
+		if typ := typedExpr.ReturnType(); !colType.TypeEqual(typ) {
+			return nil, incompatibleExprTypeError(context, colType, typ)
+		}
+		if !parser.IsConst(typedExpr) {
+			return nil, fmt.Errorf("%s: values must be constant", context)
+		}
+		d, err := typedExpr.Eval(evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		tuple[i] = d
+	}
+	return &tuple, nil
+}
+
+func partitionColumnsMismatchError(partBy *parser.PartitionBy, index *IndexDescriptor) error {
+	n := len(partBy.Fields)
+	if n > len(index.ColumnNames) {
+		n = len(index.ColumnNames)
+	}
+	return fmt.Errorf("declared partition columns (%s) do not match first %d columns in index being partitioned (%s)",
+		partBy.Fields, len(partBy.Fields), parser.NameList(index.ColumnNames[:n]))
+}
@@ -77,6 +77,7 @@ type RowFetcher struct {
 	keyVals          []parser.Datum // the index key values for the current row
 	implicitValTypes []parser.Datum // the implicit value types for unique indexes
 	implicitVals     []parser.Datum // the implicit values for unique indexes
+	implicitNeeded   bool           // whether any implicit value is needed
 	indexKey         []byte         // the index key of the current row
 	row              parser.DTuple
 	prettyValueBuf   bytes.Buffer
@@ -86,8 +87,17 @@ type RowFetcher struct {
 	keyRemainingBytes []byte
 	kvEnd             bool
 
+	// The number of keys and rows retrieved over all the scans, used to
+	// estimate the number of keys per row when sizing batches.
+	keysFetched int64
+	rowsFetched int64
+
 	// Buffered allocation of decoded datums.
 	alloc DatumAlloc
+
+	// If set, a scan is interrupted between batches of key/values if this
+	// returns an error (see SetCheckCancelled).
+	checkCancelled func() error
 }
 
 // Init sets up a RowFetcher for a given table and index. If we are using a
@@ -108,6 +118,7 @@ func (rf *RowFetcher) Init(
 	rf.isSecondaryIndex = isSecondaryIndex
 	rf.cols = cols
 	rf.valNeededForCol = valNeededForCol
+	rf.implicitNeeded = false
 	rf.row = make([]parser.Datum, len(rf.cols))
 
 	var indexColumnIDs []ColumnID
@@ -144,10 +155,22 @@ func (rf *RowFetcher) Init(
 			return err
 		}
 		rf.implicitVals = make([]parser.Datum, len(rf.implicitValTypes))
+		for _, id := range index.ImplicitColumnIDs {
+			if idx, ok := rf.colIdxMap[id]; ok && rf.valNeededForCol[idx] {
+				rf.implicitNeeded = true
+			}
+		}
 	}
 	return nil
 }
 
+// SetCheckCancelled sets a function called before each batch of key/values
+// is retrieved. If it returns an error, e.g. because the statement was
+// cancelled, the scan stops and NextRow returns the error.
+func (rf *RowFetcher) SetCheckCancelled(fn func() error) {
+	rf.checkCancelled = fn
+}
+
 // StartScan initializes and starts the key-value scan. Can be used multiple
 // times.
 func (rf *RowFetcher) StartScan(txn *client.Txn, spans Spans, limitHint int64) error {
@@ -165,17 +188,48 @@ func (rf *RowFetcher) StartScan(txn *client.Txn, spans Spans, limitHint int64) e
 	// a very restrictive filter and actually have to retrieve a lot of rows).
 	firstBatchLimit := limitHint
 	if firstBatchLimit != 0 {
-		// For a secondary index, we have one key per row.
-		if !rf.isSecondaryIndex {
-			// We have a sentinel key per row plus at most one key per non-PK column. Of course, we
-			// may have other keys due to a schema change, but this is only a hint.
-			firstBatchLimit *= int64(1 + len(rf.cols) - len(rf.index.ColumnIDs))
-		}
+		firstBatchLimit *= rf.keysPerRow()
 		// We need an extra key to make sure we form the last row.
 		firstBatchLimit++
 	}
 
 	rf.kvFetcher = makeKVFetcher(txn, spans, rf.reverse, firstBatchLimit)
+	rf.kvFetcher.checkCancelled = rf.checkCancelled
+
+	// Retrieve the first key.
+	_, err := rf.NextKey()
+	return err
+}
+
+// keysPerRow estimates the number of keys that form a row. If rows were already
+// retrieved by this RowFetcher (e.g. by previous scans), the average observed
+// width is used; otherwise the estimate is based on the index.
+func (rf *RowFetcher) keysPerRow() int64 {
+	if rf.rowsFetched > 0 {
+		// Round up, to avoid needing a second batch for the last row.
+		return (rf.keysFetched + rf.rowsFetched - 1) / rf.rowsFetched
+	}
+	// For a secondary index, we have one key per row.
+	if rf.isSecondaryIndex {
+		return 1
+	}
+	// We have at most one key per column family. Of course, we may have other
+	// keys due to a schema change or interleaved tables, but this is only a
+	// hint.
+	if n := len(rf.desc.Families); n > 0 {
+		return int64(n)
+	}
+	return 1
+}
+
+// StartGets initializes and starts the retrieval of the given keys with Get
+// requests. This is cheaper than a scan when all the keys that can hold the
+// rows to fetch are known, e.g. the keys of the column families of a single
+// row of the primary index. The keys must be sorted.
+func (rf *RowFetcher) StartGets(txn *client.Txn, keys []roachpb.Key) error {
+	rf.indexKey = nil
+	rf.kvFetcher = makeKVGetFetcher(txn, keys)
+	rf.kvFetcher.checkCancelled = rf.checkCancelled
 
 	// Retrieve the first key.
 	_, err := rf.NextKey()
@@ -197,6 +251,7 @@ func (rf *RowFetcher) NextKey() (rowDone bool, err error) {
 		if rf.kvEnd {
 			return true, nil
 		}
+		rf.keysFetched++
 
 		rf.keyRemainingBytes, ok, err = rf.ReadIndexKey(rf.kv.Key)
 		if err != nil {
@@ -291,7 +346,10 @@ func (rf *RowFetcher) ProcessKV(kv client.KeyValue, debugStrings bool) (
 			return "", "", err
 		}
 	} else {
-		if rf.implicitVals != nil {
+		// The value of a unique index is only decoded if one of the implicit
+		// columns is needed; scans which only count rows don't need any.
+		decodeImplicit := rf.implicitVals != nil && (debugStrings || rf.implicitNeeded)
+		if decodeImplicit {
 			// This is a unique index; decode the implicit column values from
 			// the value.
 			_, err := DecodeKeyVals(&rf.alloc, rf.implicitValTypes, rf.implicitVals, nil,
@@ -310,7 +368,7 @@ func (rf *RowFetcher) ProcessKV(kv client.KeyValue, debugStrings bool) (
 		}
 
 		if log.V(2) {
-			if rf.implicitVals != nil {
+			if decodeImplicit {
 				log.Infof("Scan %s -> %s", kv.Key, prettyDatums(rf.implicitVals))
 			} else {
 				log.Infof("Scan %s", kv.Key)
@@ -349,10 +407,9 @@ func (rf *RowFetcher) processValueSingle(
 		if debugStrings {
 			prettyKey = fmt.Sprintf("%s/%s", prettyKey, rf.desc.Columns[idx].Name)
 		}
-		kind := rf.cols[idx].Type.Kind
 		// TODO(dan): Once we decide if we're changing the tuple encoding, see if we
 		// can get rid of UnmarshalColumnValue in favor of DecodeTableValue.
-		value, err := UnmarshalColumnValue(&rf.alloc, kind, kv.Value)
+		value, err := UnmarshalColumnValue(&rf.alloc, rf.cols[idx].Type, kv.Value)
 		if err != nil {
 			return "", "", err
 		}
@@ -424,8 +481,8 @@ func (rf *RowFetcher) processValueTuple(
 			prettyKey = fmt.Sprintf("%s/%s", prettyKey, rf.desc.Columns[idx].Name)
 		}
 
-		kind := rf.cols[idx].Type.Kind.ToDatumType()
-		value, tupleBytes, err = DecodeTableValue(&rf.alloc, kind, tupleBytes)
+		typ := rf.cols[idx].Type.ToDatumType()
+		value, tupleBytes, err = DecodeTableValue(&rf.alloc, typ, tupleBytes)
 		if err != nil {
 			return "", "", err
 		}
@@ -480,6 +537,21 @@ func (rf *RowFetcher) NextRow() (parser.DTuple, error) {
 	}
 }
 
+// SkipPrefix skips the rows whose keys have the given prefix. It is used after
+// a row is returned by NextRow, when the following rows sharing some of its
+// index columns aren't needed (e.g. for SELECT DISTINCT over a prefix of the
+// index). The skipped rows are not decoded and, if they extend past the
+// current batch, are not even retrieved: the next batch seeks to the end of
+// the prefix, and is limited like the first batch of the scan.
+func (rf *RowFetcher) SkipPrefix(prefix roachpb.Key) error {
+	if rf.kvEnd || !bytes.HasPrefix(rf.kv.Key, prefix) {
+		return nil
+	}
+	rf.kvFetcher.skipPrefix(prefix)
+	_, err := rf.NextKey()
+	return err
+}
+
 // NextKeyDebug processes one key at a time and returns a pretty printed key and
 // value. If we completed a row, the row is returned as well (see nextRow). If
 // there are no more keys, prettyKey is "".
@@ -505,6 +577,7 @@ func (rf *RowFetcher) NextKeyDebug() (
 }
 
 func (rf *RowFetcher) finalizeRow() {
+	rf.rowsFetched++
 	// Fill in any missing values with NULLs
 	for i, col := range rf.cols {
 		if rf.valNeededForCol[i] && rf.row[i] == nil {
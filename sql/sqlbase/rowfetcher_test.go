@@ -0,0 +1,61 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestRowFetcherKeysPerRow verifies the estimate of the number of keys per
+// row used to size the first batch of scans with a limit hint.
+func TestRowFetcherKeysPerRow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	threeFamilies := &TableDescriptor{
+		Families: []ColumnFamilyDescriptor{{ID: 0}, {ID: 1}, {ID: 2}},
+	}
+	testCases := []struct {
+		desc             *TableDescriptor
+		isSecondaryIndex bool
+		keysFetched      int64
+		rowsFetched      int64
+		expected         int64
+	}{
+		// Before any row is formed, the estimate comes from the index.
+		{threeFamilies, false, 0, 0, 3},
+		{threeFamilies, true, 0, 0, 1},
+		{&TableDescriptor{}, false, 0, 0, 1},
+		// Keys without a formed row don't change the estimate.
+		{threeFamilies, false, 2, 0, 3},
+		// Afterwards, the observed width is used, rounded up.
+		{threeFamilies, false, 4, 4, 1},
+		{threeFamilies, false, 9, 4, 3},
+		{threeFamilies, true, 10, 5, 2},
+		{&TableDescriptor{}, false, 12, 3, 4},
+	}
+	for i, tc := range testCases {
+		rf := RowFetcher{
+			desc:             tc.desc,
+			isSecondaryIndex: tc.isSecondaryIndex,
+			keysFetched:      tc.keysFetched,
+			rowsFetched:      tc.rowsFetched,
+		}
+		if n := rf.keysPerRow(); n != tc.expected {
+			t.Errorf("%d: expected %d keys per row, got %d", i, tc.expected, n)
+		}
+	}
+}
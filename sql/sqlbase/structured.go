@@ -183,6 +183,24 @@ func (desc *IndexDescriptor) ContainsColumnID(colID ColumnID) bool {
 	return false
 }
 
+// validateInverted checks that an inverted index only has the features its key
+// encoding supports.
+func (desc *IndexDescriptor) validateInverted() error {
+	if len(desc.ColumnIDs) != 1 {
+		return fmt.Errorf("inverted index %q must contain exactly 1 column", desc.Name)
+	}
+	if desc.Unique {
+		return fmt.Errorf("inverted index %q cannot be unique", desc.Name)
+	}
+	if len(desc.StoreColumnNames) > 0 {
+		return fmt.Errorf("inverted index %q cannot store columns", desc.Name)
+	}
+	if len(desc.Interleave.Ancestors) > 0 {
+		return fmt.Errorf("inverted index %q cannot be interleaved", desc.Name)
+	}
+	return nil
+}
+
 // FullColumnIDs returns the index column IDs including any implicit column IDs
 // for non-unique indexes. It also returns the direction with which each column
 // was encoded.
@@ -255,6 +273,29 @@ func (desc *TableDescriptor) AllNonDropIndexes() []IndexDescriptor {
 	return indexes
 }
 
+// PrimaryKeyChangeInProgress returns whether the primary key of the table is
+// being changed, which lasts until the replaced indexes have been dropped.
+func (desc *TableDescriptor) PrimaryKeyChangeInProgress() bool {
+	for _, m := range desc.Mutations {
+		if idx := m.GetIndex(); idx != nil && (idx.PrimaryEncoding || idx.ReplacesID != 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// newPrimaryIndex returns the index being added to replace the primary index
+// of a table whose primary key is being changed, or nil.
+func (desc *TableDescriptor) newPrimaryIndex() *IndexDescriptor {
+	for _, m := range desc.Mutations {
+		if idx := m.GetIndex(); idx != nil && idx.PrimaryEncoding &&
+			m.Direction == DescriptorMutation_ADD {
+			return idx
+		}
+	}
+	return nil
+}
+
 func generatedFamilyName(familyID FamilyID, columnNames []string) string {
 	var buf bytes.Buffer
 	fmt.Fprintf(&buf, "fam_%d", familyID)
@@ -374,11 +415,22 @@ func (desc *TableDescriptor) AllocateIDs() error {
 	for i := range desc.Indexes {
 		collectIndexes(&desc.Indexes[i])
 	}
+	// The indexes being dropped keep the implicit columns they were written
+	// with, which may not be the ones of the current primary key once it has
+	// been changed.
+	droppingIndexes := make(map[*IndexDescriptor]struct{})
+	addingIndexes := make(map[*IndexDescriptor]struct{})
 	for _, m := range desc.Mutations {
 		if index := m.GetIndex(); index != nil {
 			collectIndexes(index)
+			if m.Direction == DescriptorMutation_DROP {
+				droppingIndexes[index] = struct{}{}
+			} else {
+				addingIndexes[index] = struct{}{}
+			}
 		}
 	}
+	newPrimaryIndex := desc.newPrimaryIndex()
 
 	for _, index := range anonymousIndexes {
 		index.allocateName(desc)
@@ -398,12 +450,29 @@ func (desc *TableDescriptor) AllocateIDs() error {
 				index.ColumnIDs[j] = columnNames[NormalizeName(colName)]
 			}
 		}
+		if _, ok := droppingIndexes[index]; ok {
+			continue
+		}
+		if index.PrimaryEncoding {
+			// An index written with the encoding of a primary index stores
+			// all the columns of the rows.
+			index.ImplicitColumnIDs = nil
+			continue
+		}
 		if index != &desc.PrimaryIndex {
+			// The indexes added to a table whose primary key is being
+			// changed, including the ones replacing its secondary indexes,
+			// become public along with the new primary index and refer to the
+			// rows by the new primary key.
+			primaryIndex := &desc.PrimaryIndex
+			if _, ok := addingIndexes[index]; ok && newPrimaryIndex != nil {
+				primaryIndex = newPrimaryIndex
+			}
 			// Need to clear ImplicitColumnIDs because it is used by
 			// ContainsColumnID.
 			index.ImplicitColumnIDs = nil
 			var implicitColumnIDs []ColumnID
-			for _, primaryColID := range desc.PrimaryIndex.ColumnIDs {
+			for _, primaryColID := range primaryIndex.ColumnIDs {
 				if !index.ContainsColumnID(primaryColID) {
 					implicitColumnIDs = append(implicitColumnIDs, primaryColID)
 				}
@@ -421,7 +490,7 @@ func (desc *TableDescriptor) AllocateIDs() error {
 				} else {
 					col = desc.Mutations[i].GetColumn()
 				}
-				if desc.PrimaryIndex.ContainsColumnID(col.ID) {
+				if primaryIndex.ContainsColumnID(col.ID) {
 					continue
 				}
 				if index.ContainsColumnID(col.ID) {
@@ -461,6 +530,11 @@ func (desc *TableDescriptor) AllocateIDs() error {
 		if _, ok := columnsInFamilies[col.ID]; ok {
 			return
 		}
+		if col.IsVirtual() {
+			// The values of virtual computed columns are not stored in the
+			// primary index.
+			return
+		}
 		if _, ok := primaryIndexColIDs[col.ID]; ok {
 			// Primary index columns are required to be assigned to family 0.
 			desc.Families[0].ColumnNames = append(desc.Families[0].ColumnNames, col.Name)
@@ -599,6 +673,8 @@ func (desc *TableDescriptor) Validate() error {
 
 	columnNames := make(map[string]ColumnID, len(desc.Columns))
 	columnIDs := make(map[ColumnID]string, len(desc.Columns))
+	computedColumnIDs := make(map[ColumnID]struct{})
+	virtualColumnIDs := make(map[ColumnID]struct{})
 	for _, column := range desc.allNonDropColumns() {
 		if err := validateName(column.Name, "column"); err != nil {
 			return err
@@ -629,6 +705,12 @@ func (desc *TableDescriptor) Validate() error {
 		if err := uniqConstraint(column.NullableConstraintName); err != nil {
 			return err
 		}
+		if column.ComputedExpr != nil {
+			computedColumnIDs[column.ID] = struct{}{}
+		}
+		if column.IsVirtual() {
+			virtualColumnIDs[column.ID] = struct{}{}
+		}
 	}
 
 	for _, m := range desc.Mutations {
@@ -699,6 +781,9 @@ func (desc *TableDescriptor) Validate() error {
 		}
 
 		for _, colID := range family.ColumnIDs {
+			if _, ok := virtualColumnIDs[colID]; ok {
+				return fmt.Errorf("virtual computed column %q cannot be assigned to a family", columnIDs[colID])
+			}
 			if famID, ok := colIDToFamilyID[colID]; ok {
 				return fmt.Errorf("column %d is in both family %d and %d", colID, famID, family.ID)
 			}
@@ -706,6 +791,9 @@ func (desc *TableDescriptor) Validate() error {
 		}
 	}
 	for colID := range columnIDs {
+		if _, ok := virtualColumnIDs[colID]; ok {
+			continue
+		}
 		if _, ok := colIDToFamilyID[colID]; !ok {
 			return fmt.Errorf("column %d is not in any column family", colID)
 		}
@@ -727,16 +815,20 @@ func (desc *TableDescriptor) Validate() error {
 			return fmt.Errorf("invalid index ID %d", index.ID)
 		}
 
-		normName := NormalizeName(index.Name)
-		if _, ok := indexNames[normName]; ok {
-			return fmt.Errorf("duplicate index name: \"%s\"", index.Name)
-		}
-		indexNames[normName] = struct{}{}
+		// An index replacing another one takes over its name when it becomes
+		// public.
+		if index.ReplacesID == 0 {
+			normName := NormalizeName(index.Name)
+			if _, ok := indexNames[normName]; ok {
+				return fmt.Errorf("duplicate index name: \"%s\"", index.Name)
+			}
+			indexNames[normName] = struct{}{}
 
-		if index.Unique {
-			// TODO(dt): Should probably add a separate constraintName to the idx.
-			if err := uniqConstraint(index.Name); err != nil {
-				return err
+			if index.Unique {
+				// TODO(dt): Should probably add a separate constraintName to the idx.
+				if err := uniqConstraint(index.Name); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -780,9 +872,18 @@ func (desc *TableDescriptor) Validate() error {
 					index.Name, name, colID, index.ColumnIDs[i])
 			}
 		}
+
+		if index.Type == IndexDescriptor_INVERTED {
+			if err := index.validateInverted(); err != nil {
+				return err
+			}
+		}
 	}
 
 	for _, colID := range desc.PrimaryIndex.ColumnIDs {
+		if _, ok := computedColumnIDs[colID]; ok {
+			return fmt.Errorf("computed column %q cannot be part of the primary key", columnIDs[colID])
+		}
 		famID, ok := colIDToFamilyID[colID]
 		if !ok || famID != FamilyID(0) {
 			return fmt.Errorf("primary key column %d is not in column family 0", colID)
@@ -811,7 +912,7 @@ func upperBoundColumnValueEncodedSize(col ColumnDescriptor) (int, bool) {
 		typ = encoding.Float
 	case ColumnType_INTERVAL:
 		typ = encoding.Duration
-	case ColumnType_STRING, ColumnType_BYTES:
+	case ColumnType_STRING, ColumnType_COLLATEDSTRING, ColumnType_BYTES:
 		// STRINGs are counted as runes, so this isn't totally correct, but this
 		// seems better than always assuming the maximum rune width.
 		typ, size = encoding.Bytes, int(col.Type.Width)
@@ -970,6 +1071,29 @@ func (desc *TableDescriptor) RemoveColumnFromFamily(colID ColumnID) {
 	}
 }
 
+// RemoveUnusedIndexExprColumns removes the columns of the expressions of
+// expression indexes which are no longer part of any index, once the indexes
+// are dropped.
+func (desc *TableDescriptor) RemoveUnusedIndexExprColumns() {
+	indexes := append([]IndexDescriptor{desc.PrimaryIndex}, desc.Indexes...)
+	for _, m := range desc.Mutations {
+		if idx := m.GetIndex(); idx != nil {
+			indexes = append(indexes, *idx)
+		}
+	}
+	cols := desc.Columns[:0]
+	for _, col := range desc.Columns {
+		used := !col.IsIndexExpr()
+		for i := 0; !used && i < len(indexes); i++ {
+			used = indexes[i].ContainsColumnID(col.ID)
+		}
+		if used {
+			cols = append(cols, col)
+		}
+	}
+	desc.Columns = cols
+}
+
 // RenameColumn updates all references to a column name in indexes and families.
 func (desc *TableDescriptor) RenameColumn(colID ColumnID, newColName string) {
 	for i := range desc.Families {
@@ -1014,7 +1138,7 @@ func (desc *TableDescriptor) FindColumnByName(name string) (DescriptorStatus, in
 			}
 		}
 	}
-	return DescriptorAbsent, -1, fmt.Errorf("column %q does not exist", name)
+	return DescriptorAbsent, -1, NewUndefinedColumnError(name)
 }
 
 // FindActiveColumnByName finds an active column with the specified name.
@@ -1025,7 +1149,7 @@ func (desc *TableDescriptor) FindActiveColumnByName(name string) (ColumnDescript
 			return c, nil
 		}
 	}
-	return ColumnDescriptor{}, fmt.Errorf("column %q does not exist", name)
+	return ColumnDescriptor{}, NewUndefinedColumnError(name)
 }
 
 // FindColumnByID finds the column with specified ID.
@@ -1119,6 +1243,12 @@ func (desc *TableDescriptor) IsInterleaved() bool {
 	return false
 }
 
+// IsMaterializedView returns true if the table stores the results of the
+// query of a materialized view.
+func (desc *TableDescriptor) IsMaterializedView() bool {
+	return desc.ViewQuery != ""
+}
+
 // MakeMutationComplete updates the descriptor upon completion of a mutation.
 func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) {
 	switch m.Direction {
@@ -1128,7 +1258,9 @@ func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) {
 			desc.AddColumn(*t.Column)
 
 		case *DescriptorMutation_Index:
-			if err := desc.AddIndex(*t.Index, false); err != nil {
+			if t.Index.ReplacesID != 0 {
+				desc.replaceIndex(*t.Index)
+			} else if err := desc.AddIndex(*t.Index, false); err != nil {
 				panic(err)
 			}
 		}
@@ -1143,6 +1275,34 @@ func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) {
 	}
 }
 
+// replaceIndex makes public an index replacing another one of a table whose
+// primary key is being changed, and adds a mutation dropping the replaced
+// index. The mutation isn't part of the schema change which added the new
+// index: its ID is finalized by the schema changer, which then runs it.
+func (desc *TableDescriptor) replaceIndex(idx IndexDescriptor) {
+	replacedID := idx.ReplacesID
+	idx.ReplacesID = 0
+	if desc.PrimaryIndex.ID == replacedID {
+		// The old primary index is written with the encoding of a primary
+		// index until it is deleted.
+		old := desc.PrimaryIndex
+		old.PrimaryEncoding = true
+		idx.PrimaryEncoding = false
+		desc.PrimaryIndex = idx
+		desc.AddIndexMutation(old, DescriptorMutation_DROP)
+		return
+	}
+	for i := range desc.Indexes {
+		if desc.Indexes[i].ID == replacedID {
+			old := desc.Indexes[i]
+			desc.Indexes[i] = idx
+			desc.AddIndexMutation(old, DescriptorMutation_DROP)
+			return
+		}
+	}
+	panic(fmt.Sprintf("index %d replaced by index %q not found", replacedID, idx.Name))
+}
+
 // AddColumnMutation adds a column mutation to desc.Mutations.
 func (desc *TableDescriptor) AddColumnMutation(c ColumnDescriptor, direction DescriptorMutation_Direction) {
 	m := DescriptorMutation{Descriptor_: &DescriptorMutation_Column{Column: &c}, Direction: direction}
@@ -1202,6 +1362,18 @@ func (desc *TableDescriptor) SetUpVersion() error {
 	return nil
 }
 
+// IsVirtual returns whether the column is a computed column whose value is
+// not stored in the primary index.
+func (desc *ColumnDescriptor) IsVirtual() bool {
+	return desc.ComputedExpr != nil && !desc.Stored
+}
+
+// IsIndexExpr returns whether the column is the hidden computed column
+// holding the values of an expression of an expression index.
+func (desc *ColumnDescriptor) IsIndexExpr() bool {
+	return desc.Hidden && desc.ComputedExpr != nil
+}
+
 // VisibleColumns returns all non hidden columns.
 func (desc *TableDescriptor) VisibleColumns() []ColumnDescriptor {
 	var cols []ColumnDescriptor
@@ -1250,10 +1422,37 @@ func (c *ColumnType) SQLString() string {
 		}
 	case ColumnType_TIMESTAMPTZ:
 		return "TIMESTAMP WITH TIME ZONE"
+	case ColumnType_COLLATEDSTRING:
+		var buf bytes.Buffer
+		buf.WriteString(ColumnType_STRING.String())
+		if c.Width > 0 {
+			fmt.Fprintf(&buf, "(%d)", c.Width)
+		}
+		buf.WriteString(" COLLATE ")
+		parser.Name(*c.Locale).Format(&buf, parser.FmtSimple)
+		return buf.String()
 	}
 	return c.Kind.String()
 }
 
+// TypeModifier returns the type modifier reported to PostgreSQL clients for
+// the column type, or 0 if it has none. As in PostgreSQL, the modifiers are
+// offset by 4, the size of a varlena header.
+func (c *ColumnType) TypeModifier() int32 {
+	const varHdrSz = 4
+	switch c.Kind {
+	case ColumnType_STRING, ColumnType_COLLATEDSTRING:
+		if c.Width > 0 {
+			return c.Width + varHdrSz
+		}
+	case ColumnType_DECIMAL:
+		if c.Precision > 0 {
+			return (c.Precision<<16 | c.Width) + varHdrSz
+		}
+	}
+	return 0
+}
+
 // ToDatumType converts the ColumnType_Kind to the correct type Datum, or
 // nil if there is no correspondence.
 func (k ColumnType_Kind) ToDatumType() parser.Datum {
@@ -1268,6 +1467,8 @@ func (k ColumnType_Kind) ToDatumType() parser.Datum {
 		return parser.TypeDecimal
 	case ColumnType_STRING:
 		return parser.TypeString
+	case ColumnType_COLLATEDSTRING:
+		return parser.TypeCollatedString
 	case ColumnType_BYTES:
 		return parser.TypeBytes
 	case ColumnType_DATE:
@@ -1285,6 +1486,9 @@ func (k ColumnType_Kind) ToDatumType() parser.Datum {
 // ToDatumType converts the ColumnType to the correct type Datum, or
 // nil if there is no correspondence.
 func (c *ColumnType) ToDatumType() parser.Datum {
+	if c.Kind == ColumnType_COLLATEDSTRING && c.Locale != nil {
+		return &parser.DCollatedString{Locale: *c.Locale}
+	}
 	return c.Kind.ToDatumType()
 }
 
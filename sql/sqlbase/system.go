@@ -54,7 +54,8 @@ CREATE TABLE system.lease (
 	usersTableSchema = `
 CREATE TABLE system.users (
   username       STRING PRIMARY KEY,
-  hashedPassword BYTES
+  hashedPassword BYTES,
+  createDB       BOOL
 );`
 
 	// Zone settings per DB/Table.
@@ -71,6 +72,19 @@ CREATE TABLE system.ui (
 	value       BYTES,
 	lastUpdated TIMESTAMP NOT NULL
 );`
+
+	// CommentsTableSchema describes the comments set by COMMENT ON, keyed by
+	// the type of the commented object, the ID of its descriptor and, for
+	// columns and indexes, the ID of the column or index within the table (0
+	// otherwise). It is exported for the migration creating the table.
+	CommentsTableSchema = `
+CREATE TABLE system.comments (
+	type     INT,
+	objectID INT,
+	subID    INT,
+	comment  STRING NOT NULL,
+	PRIMARY KEY (type, objectID, subID)
+);`
 )
 
 var (
@@ -177,6 +191,7 @@ func addSystemDatabaseToSchema(target *MetadataSchema) {
 	// Add other system tables.
 	target.AddTable(keys.LeaseTableID, leaseTableSchema)
 	target.AddTable(keys.UITableID, uiTableSchema)
+	target.AddTable(keys.CommentsTableID, CommentsTableSchema)
 
 	target.otherKV = append(target.otherKV, createDefaultZoneConfig()...)
 }
@@ -27,7 +27,7 @@ import (
 func TestInitialKeys(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
-	const nonSystemDesc = 2
+	const nonSystemDesc = 3
 	const keysPerDesc = 2
 	const nonDescKeys = 2
 
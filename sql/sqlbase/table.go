@@ -18,6 +18,8 @@ package sqlbase
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -47,6 +49,7 @@ func MakeTableDesc(p *parser.CreateTable, parentID ID) (TableDescriptor, error)
 	desc.Version = 1
 
 	var primaryIndexColumnSet map[parser.Name]struct{}
+	var computedColumns []*parser.ColumnTableDef
 	for _, def := range p.Defs {
 		switch d := def.(type) {
 		case *parser.ColumnTableDef:
@@ -55,6 +58,9 @@ func MakeTableDesc(p *parser.CreateTable, parentID ID) (TableDescriptor, error)
 				return desc, err
 			}
 			desc.AddColumn(*col)
+			if d.Computed.Computed {
+				computedColumns = append(computedColumns, d)
+			}
 			if idx != nil {
 				if err := desc.AddIndex(*idx, d.PrimaryKey); err != nil {
 					return desc, err
@@ -75,9 +81,28 @@ func MakeTableDesc(p *parser.CreateTable, parentID ID) (TableDescriptor, error)
 				Name:             string(d.Name),
 				StoreColumnNames: d.Storing,
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			if d.Inverted {
+				idx.Type = IndexDescriptor_INVERTED
+			}
+			cols, err := desc.AddIndexExprColumns(d.Columns)
+			if err != nil {
+				return desc, err
+			}
+			if d.Sharded != nil {
+				if cols, idx.ShardBuckets, err = desc.AddShardColumn(cols, d.Sharded); err != nil {
+					return desc, err
+				}
+			}
+			if err := idx.FillColumns(cols); err != nil {
 				return desc, err
 			}
+			if d.PartitionBy != nil {
+				part, err := MakePartitioning(&desc, &idx, d.PartitionBy)
+				if err != nil {
+					return desc, err
+				}
+				idx.Partitioning = part
+			}
 			if err := desc.AddIndex(idx, false); err != nil {
 				return desc, err
 			}
@@ -90,9 +115,25 @@ func MakeTableDesc(p *parser.CreateTable, parentID ID) (TableDescriptor, error)
 				Unique:           true,
 				StoreColumnNames: d.Storing,
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			cols, err := desc.AddIndexExprColumns(d.Columns)
+			if err != nil {
 				return desc, err
 			}
+			if d.Sharded != nil {
+				if cols, idx.ShardBuckets, err = desc.AddShardColumn(cols, d.Sharded); err != nil {
+					return desc, err
+				}
+			}
+			if err := idx.FillColumns(cols); err != nil {
+				return desc, err
+			}
+			if d.PartitionBy != nil {
+				part, err := MakePartitioning(&desc, &idx, d.PartitionBy)
+				if err != nil {
+					return desc, err
+				}
+				idx.Partitioning = part
+			}
 			if err := desc.AddIndex(idx, d.PrimaryKey); err != nil {
 				return desc, err
 			}
@@ -114,48 +155,15 @@ func MakeTableDesc(p *parser.CreateTable, parentID ID) (TableDescriptor, error)
 			// the table level (i.e., columns never have a check constraint themselves). We
 			// will adhere to the stricter definition.
 
-			preFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
-				qname, ok := expr.(*parser.QualifiedName)
-				if !ok {
-					// Not a qname, don't do anything to this node.
-					return nil, true, expr
-				}
-
-				if err := qname.NormalizeColumnName(); err != nil {
-					return err, false, nil
-				}
-
-				if qname.IsStar() {
-					return fmt.Errorf("* not allowed in constraint %q", d.Expr.String()), false, nil
-				}
-				col, err := desc.FindActiveColumnByName(qname.Column())
-				if err != nil {
-					return fmt.Errorf("column %q not found for constraint %q", qname.String(), d.Expr.String()), false, nil
-				}
-				// Convert to a dummy datum of the correct type.
-				return nil, false, col.Type.ToDatumType()
-			}
-
-			expr, err := parser.SimpleVisit(d.Expr, preFn)
+			check, err := MakeCheckConstraint(&desc, d)
 			if err != nil {
 				return desc, err
 			}
-
-			if err := SanitizeVarFreeExpr(expr, parser.TypeBool, "CHECK"); err != nil {
-				return desc, err
-			}
-
-			var p parser.Parser
-			if p.AggregateInExpr(expr) {
-				return desc, fmt.Errorf("Aggregate functions are not allowed in CHECK expressions")
-			}
-
-			check := &TableDescriptor_CheckConstraint{Expr: d.Expr.String()}
-			if len(d.Name) > 0 {
-				check.Name = string(d.Name)
-			}
 			desc.Checks = append(desc.Checks, check)
 
+		case *parser.ForeignKeyConstraintTableDef:
+			// Foreign keys are resolved once the descriptor has IDs.
+
 		case *parser.FamilyTableDef:
 			names := make([]string, len(d.Columns))
 			for i, col := range d.Columns {
@@ -172,6 +180,22 @@ func MakeTableDesc(p *parser.CreateTable, parentID ID) (TableDescriptor, error)
 		}
 	}
 
+	// Computed column expressions can refer to columns defined after them, so
+	// they are checked once all the columns are known.
+	for _, d := range computedColumns {
+		if err := validateComputedExpr(&desc, d); err != nil {
+			return desc, err
+		}
+	}
+
+	if p.PartitionBy != nil {
+		part, err := MakePartitioning(&desc, &desc.PrimaryIndex, p.PartitionBy)
+		if err != nil {
+			return desc, err
+		}
+		desc.PrimaryIndex.Partitioning = part
+	}
+
 	if primaryIndexColumnSet != nil {
 		// Primary index columns are not nullable.
 		for i := range desc.Columns {
@@ -184,6 +208,304 @@ func MakeTableDesc(p *parser.CreateTable, parentID ID) (TableDescriptor, error)
 	return desc, nil
 }
 
+// MakeCheckConstraint makes the descriptor of a CHECK constraint of a table,
+// whose expression can only refer to the active columns of the table.
+func MakeCheckConstraint(
+	desc *TableDescriptor, d *parser.CheckConstraintTableDef,
+) (*TableDescriptor_CheckConstraint, error) {
+	preFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		qname, ok := expr.(*parser.QualifiedName)
+		if !ok {
+			// Not a qname, don't do anything to this node.
+			return nil, true, expr
+		}
+
+		if err := qname.NormalizeColumnName(); err != nil {
+			return err, false, nil
+		}
+
+		if qname.IsStar() {
+			return fmt.Errorf("* not allowed in constraint %q", d.Expr.String()), false, nil
+		}
+		col, err := desc.FindActiveColumnByName(qname.Column())
+		if err != nil {
+			return fmt.Errorf("column %q not found for constraint %q", qname.String(), d.Expr.String()), false, nil
+		}
+		// Convert to a dummy datum of the correct type.
+		return nil, false, col.Type.ToDatumType()
+	}
+
+	expr, err := parser.SimpleVisit(d.Expr, preFn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SanitizeVarFreeExpr(expr, parser.TypeBool, "CHECK"); err != nil {
+		return nil, err
+	}
+
+	var p parser.Parser
+	if p.AggregateInExpr(expr) {
+		return nil, fmt.Errorf("Aggregate functions are not allowed in CHECK expressions")
+	}
+
+	check := &TableDescriptor_CheckConstraint{Expr: d.Expr.String()}
+	if len(d.Name) > 0 {
+		check.Name = string(d.Name)
+	}
+	return check, nil
+}
+
+// validateComputedExpr verifies that the expression of a computed column has
+// the type of the column, only refers to non-computed columns of the table and
+// is deterministic.
+func validateComputedExpr(desc *TableDescriptor, d *parser.ColumnTableDef) error {
+	col, err := desc.FindActiveColumnByName(string(d.Name))
+	if err != nil {
+		return err
+	}
+	colDatumType := col.Type.ToDatumType()
+	context := fmt.Sprintf("computed column %q", d.Name)
+	typedExpr, err := typeCheckComputedExpr(desc, d.Computed.Expr, context, colDatumType)
+	if err != nil {
+		return err
+	}
+	if typ := typedExpr.ReturnType(); !colDatumType.TypeEqual(typ) {
+		return incompatibleExprTypeError("computed column", colDatumType, typ)
+	}
+	return nil
+}
+
+// typeCheckComputedExpr type checks an expression computed from the columns of
+// a table, which may only refer to non-computed columns of the table and must
+// be deterministic. The context describes the expression in the errors.
+func typeCheckComputedExpr(
+	desc *TableDescriptor, expr parser.Expr, context string, desired parser.Datum,
+) (parser.TypedExpr, error) {
+	preFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		qname, ok := expr.(*parser.QualifiedName)
+		if !ok {
+			// Not a qname, don't do anything to this node.
+			return nil, true, expr
+		}
+
+		if err := qname.NormalizeColumnName(); err != nil {
+			return err, false, nil
+		}
+
+		if qname.IsStar() {
+			return fmt.Errorf("* not allowed in %s", context), false, nil
+		}
+		col, err := desc.FindActiveColumnByName(qname.Column())
+		if err != nil {
+			return fmt.Errorf("column %q not found for %s", qname.String(), context), false, nil
+		}
+		if col.ComputedExpr != nil {
+			return fmt.Errorf("%s cannot refer to computed column %q", context, col.Name), false, nil
+		}
+		// Convert to a dummy datum of the correct type.
+		return nil, false, col.Type.ToDatumType()
+	}
+
+	expr, err := parser.SimpleVisit(expr, preFn)
+	if err != nil {
+		return nil, err
+	}
+	typedExpr, err := parser.TypeCheck(expr, nil, desired)
+	if err != nil {
+		return nil, err
+	}
+	if !parser.IsConst(typedExpr) {
+		return nil, fmt.Errorf("%s cannot use impure functions", context)
+	}
+	return typedExpr, nil
+}
+
+// indexExprColumnName is the name of the hidden columns holding the values of
+// the expressions of expression indexes.
+const indexExprColumnName = "crdb_internal_idx_expr"
+
+// IndexColumnDisplayNames returns the names of the columns of an index as
+// they are reported to users: the columns holding the expressions of
+// expression indexes are replaced by their expressions.
+func (desc *TableDescriptor) IndexColumnDisplayNames(index *IndexDescriptor) []string {
+	names := make([]string, len(index.ColumnNames))
+	for i, name := range index.ColumnNames {
+		names[i] = name
+		if !strings.HasPrefix(name, indexExprColumnName) {
+			continue
+		}
+		if col, err := desc.FindColumnByID(index.ColumnIDs[i]); err == nil && col.IsIndexExpr() {
+			names[i] = *col.ComputedExpr
+		}
+	}
+	return names
+}
+
+// AddIndexExprColumns returns the elements of an index with its expressions
+// replaced by hidden virtual computed columns of the table, so that the index
+// is written, backfilled and selected as an index on these columns. The
+// indexes on the same expression share its column.
+func (desc *TableDescriptor) AddIndexExprColumns(
+	elems parser.IndexElemList,
+) (parser.IndexElemList, error) {
+	var res parser.IndexElemList
+	for i, elem := range elems {
+		if elem.Expr == nil {
+			continue
+		}
+		if res == nil {
+			res = append(parser.IndexElemList(nil), elems...)
+		}
+		// The columns are referred to by their names in the descriptor, so that
+		// the expression is written the same way as the filters it's matched
+		// against.
+		expr, err := parser.SimpleVisit(elem.Expr, func(expr parser.Expr) (error, bool, parser.Expr) {
+			qname, ok := expr.(*parser.QualifiedName)
+			if !ok {
+				return nil, true, expr
+			}
+			if err := qname.NormalizeColumnName(); err != nil {
+				return err, false, nil
+			}
+			if col, err := desc.FindActiveColumnByName(qname.Column()); err == nil {
+				return nil, false, &parser.QualifiedName{Base: parser.Name(col.Name)}
+			}
+			return nil, false, expr
+		})
+		if err != nil {
+			return nil, err
+		}
+		exprStr := expr.String()
+		typedExpr, err := typeCheckComputedExpr(desc, expr, "index expression", parser.NoTypePreference)
+		if err != nil {
+			return nil, err
+		}
+
+		name := ""
+		for _, col := range desc.Columns {
+			if col.IsIndexExpr() && *col.ComputedExpr == exprStr {
+				name = col.Name
+				break
+			}
+		}
+		if name == "" {
+			typ, err := parser.DatumTypeToColumnType(typedExpr.ReturnType())
+			if err != nil {
+				return nil, fmt.Errorf("cannot index expression %s of type %s",
+					exprStr, typedExpr.ReturnType().Type())
+			}
+			name = indexExprColumnName
+			for j := 1; ; j++ {
+				if _, _, err := desc.FindColumnByName(name); err != nil {
+					break
+				}
+				name = fmt.Sprintf("%s_%d", indexExprColumnName, j)
+			}
+			col, _, err := MakeColumnDefDescs(&parser.ColumnTableDef{Name: parser.Name(name), Type: typ})
+			if err != nil {
+				return nil, err
+			}
+			col.Hidden = true
+			col.ComputedExpr = &exprStr
+			desc.AddColumn(*col)
+		}
+		res[i] = parser.IndexElem{Column: parser.Name(name), Direction: elem.Direction}
+	}
+	if res == nil {
+		return elems, nil
+	}
+	return res, nil
+}
+
+// maxShardBuckets is the maximum BUCKET_COUNT of a hash sharded index.
+const maxShardBuckets = 2048
+
+// evalShardBuckets returns the number of buckets of a hash sharded index.
+func evalShardBuckets(d *parser.ShardedIndexDef) (uint32, error) {
+	typedExpr, err := parser.TypeCheck(d.ShardBuckets, nil, parser.TypeInt)
+	if err != nil {
+		return 0, err
+	}
+	var evalCtx parser.EvalContext
+	datum, err := typedExpr.Eval(&evalCtx)
+	if err != nil {
+		return 0, err
+	}
+	buckets, ok := datum.(*parser.DInt)
+	if !ok || *buckets <= 0 || *buckets > maxShardBuckets {
+		return 0, fmt.Errorf("BUCKET_COUNT must be an integer between 1 and %d, got %s",
+			maxShardBuckets, datum)
+	}
+	return uint32(*buckets), nil
+}
+
+// AddShardColumn returns the elements of a hash sharded index prefixed by its
+// hidden virtual computed shard column, holding the bucket of the hash of the
+// values of the elements, and the number of buckets. The indexes sharded on
+// the same columns into the same number of buckets share the column.
+func (desc *TableDescriptor) AddShardColumn(
+	elems parser.IndexElemList, d *parser.ShardedIndexDef,
+) (parser.IndexElemList, uint32, error) {
+	buckets, err := evalShardBuckets(d)
+	if err != nil {
+		return nil, 0, err
+	}
+	names := make([]string, len(elems))
+	args := make([]string, len(elems))
+	for i, elem := range elems {
+		names[i] = string(elem.Column)
+		args[i] = parser.AsString(elem.Column)
+	}
+	// The values are hashed by their key encoding, so that the values which
+	// compare equal are in the same bucket.
+	arg := strings.Join(args, ", ")
+	if len(args) > 1 {
+		arg = "(" + arg + ")"
+	}
+	expr, err := parser.ParseExprTraditional(
+		fmt.Sprintf("mod(crdb_internal.fnv32_key(%s), %d)", arg, buckets))
+	if err != nil {
+		return nil, 0, err
+	}
+	exprStr := expr.String()
+	typedExpr, err := typeCheckComputedExpr(desc, expr, "shard column", parser.TypeInt)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	prefix := fmt.Sprintf("crdb_internal_%s_shard_%d", strings.Join(names, "_"), buckets)
+	name := ""
+	for _, col := range desc.Columns {
+		if col.IsIndexExpr() && *col.ComputedExpr == exprStr {
+			name = col.Name
+			break
+		}
+	}
+	if name == "" {
+		name = prefix
+		for j := 1; ; j++ {
+			if _, _, err := desc.FindColumnByName(name); err != nil {
+				break
+			}
+			name = fmt.Sprintf("%s_%d", prefix, j)
+		}
+		typ, err := parser.DatumTypeToColumnType(typedExpr.ReturnType())
+		if err != nil {
+			return nil, 0, err
+		}
+		col, _, err := MakeColumnDefDescs(&parser.ColumnTableDef{Name: parser.Name(name), Type: typ})
+		if err != nil {
+			return nil, 0, err
+		}
+		col.Hidden = true
+		col.ComputedExpr = &exprStr
+		desc.AddColumn(*col)
+	}
+	shard := parser.IndexElem{Column: parser.Name(name), Direction: parser.DefaultDirection}
+	return append(parser.IndexElemList{shard}, elems...), buckets, nil
+}
+
 func exprContainsVarsError(context string, Expr parser.Expr) error {
 	return fmt.Errorf("%s expression '%s' may not contain variable sub-expressions", context, Expr)
 }
@@ -262,6 +584,11 @@ func MakeColumnDefDescs(d *parser.ColumnTableDef) (*ColumnDescriptor, *IndexDesc
 		col.Type.Kind = ColumnType_STRING
 		col.Type.Width = int32(t.N)
 		colDatumType = parser.TypeString
+	case *parser.CollatedStringColType:
+		col.Type.Kind = ColumnType_COLLATEDSTRING
+		col.Type.Width = int32(t.N)
+		col.Type.Locale = &t.Locale
+		colDatumType = &parser.DCollatedString{Locale: t.Locale}
 	case *parser.BytesColType:
 		col.Type.Kind = ColumnType_BYTES
 		colDatumType = parser.TypeBytes
@@ -296,6 +623,26 @@ func MakeColumnDefDescs(d *parser.ColumnTableDef) (*ColumnDescriptor, *IndexDesc
 		col.DefaultExpr = &s
 	}
 
+	if d.Computed.Computed {
+		switch {
+		case d.DefaultExpr.Expr != nil:
+			return nil, nil, fmt.Errorf("computed column %q cannot have a default value", col.Name)
+		case d.PrimaryKey:
+			return nil, nil, fmt.Errorf("computed column %q cannot be part of the primary key", col.Name)
+		case d.References.Table != nil:
+			return nil, nil, fmt.Errorf("computed column %q cannot reference another table", col.Name)
+		case !d.Computed.Stored && (d.Family.Create || len(d.Family.Name) > 0):
+			return nil, nil, fmt.Errorf("virtual computed column %q cannot be assigned to a family", col.Name)
+		}
+		var p parser.Parser
+		if p.AggregateInExpr(d.Computed.Expr) {
+			return nil, nil, fmt.Errorf("Aggregate functions are not allowed in computed column expressions")
+		}
+		s := d.Computed.Expr.String()
+		col.ComputedExpr = &s
+		col.Stored = d.Computed.Stored
+	}
+
 	var idx *IndexDescriptor
 	if d.PrimaryKey || d.Unique {
 		idx = &IndexDescriptor{
@@ -522,6 +869,15 @@ func EncodeTableKey(b []byte, val parser.Datum, dir encoding.Direction) ([]byte,
 			return encoding.EncodeStringAscending(b, string(*t)), nil
 		}
 		return encoding.EncodeStringDescending(b, string(*t)), nil
+	case *parser.DCollatedString:
+		// The collation key orders the strings, and the contents follow it
+		// so that they can be decoded.
+		if dir == encoding.Ascending {
+			b = encoding.EncodeBytesAscending(b, t.Key)
+			return encoding.EncodeStringAscending(b, t.Contents), nil
+		}
+		b = encoding.EncodeBytesDescending(b, t.Key)
+		return encoding.EncodeStringDescending(b, t.Contents), nil
 	case *parser.DBytes:
 		if dir == encoding.Ascending {
 			return encoding.EncodeStringAscending(b, string(*t)), nil
@@ -577,6 +933,8 @@ func EncodeTableValue(appendTo []byte, colID ColumnID, val parser.Datum) ([]byte
 		return encoding.EncodeDecimalValue(appendTo, uint32(colID), &t.Dec), nil
 	case *parser.DString:
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(*t)), nil
+	case *parser.DCollatedString:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Contents)), nil
 	case *parser.DBytes:
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(*t)), nil
 	case *parser.DDate:
@@ -1011,7 +1369,7 @@ func DecodeTableKey(
 	}
 	var rkey []byte
 	var err error
-	switch valType.(type) {
+	switch t := valType.(type) {
 	case *parser.DBool:
 		var i int64
 		if dir == encoding.Ascending {
@@ -1056,6 +1414,21 @@ func DecodeTableKey(
 			rkey, r, err = encoding.DecodeUnsafeStringDescending(key, nil)
 		}
 		return a.NewDString(parser.DString(r)), rkey, err
+	case *parser.DCollatedString:
+		var k []byte
+		var r string
+		if dir == encoding.Ascending {
+			if rkey, k, err = encoding.DecodeBytesAscending(key, nil); err != nil {
+				return nil, nil, err
+			}
+			rkey, r, err = encoding.DecodeUnsafeStringAscending(rkey, nil)
+		} else {
+			if rkey, k, err = encoding.DecodeBytesDescending(key, nil); err != nil {
+				return nil, nil, err
+			}
+			rkey, r, err = encoding.DecodeUnsafeStringDescending(rkey, nil)
+		}
+		return &parser.DCollatedString{Contents: r, Locale: t.Locale, Key: k}, rkey, err
 	case *parser.DBytes:
 		var r []byte
 		if dir == encoding.Ascending {
@@ -1110,7 +1483,7 @@ func DecodeTableValue(a *DatumAlloc, valType parser.Datum, b []byte) (parser.Dat
 	if typ == encoding.Null {
 		return parser.DNull, b[dataOffset:], nil
 	}
-	switch valType.(type) {
+	switch t := valType.(type) {
 	case *parser.DBool:
 		var x bool
 		b, x, err = encoding.DecodeBoolValue(b)
@@ -1135,6 +1508,10 @@ func DecodeTableValue(a *DatumAlloc, valType parser.Datum, b []byte) (parser.Dat
 		var data []byte
 		b, data, err = encoding.DecodeBytesValue(b)
 		return a.NewDString(parser.DString(data)), b, err
+	case *parser.DCollatedString:
+		var data []byte
+		b, data, err = encoding.DecodeBytesValue(b)
+		return parser.NewDCollatedString(string(data), t.Locale), b, err
 	case *parser.DBytes:
 		var data []byte
 		b, data, err = encoding.DecodeBytesValue(b)
@@ -1217,23 +1594,176 @@ func EncodeSecondaryIndex(
 	return entry, nil
 }
 
+// EncodeInvertedIndexKeys encodes key/values for an inverted index, one for
+// each element contained in the value of its column. colMap maps ColumnIDs to
+// indices in `values`. The key of an element is made of its value followed by
+// the primary key of the row, so that the keys of the rows containing the same
+// element are unique.
+func EncodeInvertedIndexKeys(
+	tableDesc *TableDescriptor,
+	index *IndexDescriptor,
+	colMap map[ColumnID]int,
+	values []parser.Datum,
+) ([]IndexEntry, error) {
+	if len(index.ColumnIDs) != 1 {
+		return nil, errors.Errorf("inverted index %q must contain exactly 1 column", index.Name)
+	}
+	val := parser.Datum(parser.DNull)
+	if i, ok := colMap[index.ColumnIDs[0]]; ok {
+		val = values[i]
+	}
+	elems := invertedIndexElements(val)
+	if len(elems) == 0 {
+		return nil, nil
+	}
+
+	dir, err := index.ColumnDirections[0].ToEncodingDirection()
+	if err != nil {
+		return nil, err
+	}
+	extraKey, _, err := EncodeColumns(index.ImplicitColumnIDs, nil, colMap, values, nil)
+	if err != nil {
+		return nil, err
+	}
+	keyPrefix := MakeIndexKeyPrefix(tableDesc, index.ID)
+
+	entries := make([]IndexEntry, len(elems))
+	for i, elem := range elems {
+		key := append([]byte(nil), keyPrefix...)
+		if key, err = EncodeTableKey(key, elem, dir); err != nil {
+			return nil, err
+		}
+		key = append(key, extraKey...)
+		entries[i].Key = keys.MakeRowSentinelKey(key)
+		entries[i].Value.SetBytes([]byte{})
+	}
+	return entries, nil
+}
+
+// invertedIndexElements returns the elements under which a value is found in
+// an inverted index. A NULL contains no element, and the values of the column
+// types which aren't containers are their own single element.
+func invertedIndexElements(val parser.Datum) []parser.Datum {
+	if val == parser.DNull {
+		return nil
+	}
+	return []parser.Datum{val}
+}
+
+// EncodePrimaryIndex encodes key/values for an index written with the encoding
+// of a primary index, which is the encoding of the new primary index of a table
+// whose primary key is being changed. colMap maps ColumnIDs to indices in
+// `values`. A row has a key/value per column family holding the values of its
+// columns which are not in the key: the one of family 0 always exists and acts
+// as a sentinel, and the ones of the other families are omitted when all their
+// values are NULL.
+func EncodePrimaryIndex(
+	tableDesc *TableDescriptor,
+	index *IndexDescriptor,
+	colMap map[ColumnID]int,
+	values []parser.Datum,
+) ([]IndexEntry, error) {
+	keyPrefix := MakeIndexKeyPrefix(tableDesc, index.ID)
+	indexKey, _, err := EncodeIndexKey(tableDesc, index, colMap, values, keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []IndexEntry
+	for _, family := range tableDesc.Families {
+		// MakeFamilyKey appends to its argument, so each family gets its own
+		// copy of the index key.
+		familyKey := keys.MakeFamilyKey(append([]byte(nil), indexKey...), uint32(family.ID))
+
+		if len(family.ColumnIDs) == 1 && family.ColumnIDs[0] == family.DefaultColumnID {
+			// Storage optimization to store DefaultColumnID directly as a value.
+			i, ok := colMap[family.DefaultColumnID]
+			if !ok || values[i] == parser.DNull {
+				continue
+			}
+			col, err := tableDesc.FindColumnByID(family.DefaultColumnID)
+			if err != nil {
+				return nil, err
+			}
+			value, err := MarshalColumnValue(*col, values[i])
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, IndexEntry{Key: familyKey, Value: value})
+			continue
+		}
+
+		colIDs := append(columnIDs(nil), family.ColumnIDs...)
+		sort.Sort(colIDs)
+		var valueBuf []byte
+		var lastColID ColumnID
+		for _, colID := range colIDs {
+			if index.ContainsColumnID(colID) {
+				// The values of the columns of the key are not repeated in
+				// the values of the families.
+				continue
+			}
+			i, ok := colMap[colID]
+			if !ok || values[i] == parser.DNull {
+				continue
+			}
+			valueBuf, err = EncodeTableValue(valueBuf, colID-lastColID, values[i])
+			if err != nil {
+				return nil, err
+			}
+			lastColID = colID
+		}
+		if family.ID == 0 || len(valueBuf) > 0 {
+			entry := IndexEntry{Key: familyKey}
+			entry.Value.SetTuple(valueBuf)
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+type columnIDs []ColumnID
+
+func (c columnIDs) Len() int           { return len(c) }
+func (c columnIDs) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c columnIDs) Less(i, j int) bool { return c[i] < c[j] }
+
 // EncodeSecondaryIndexes encodes key/values for the secondary indexes. colMap
 // maps ColumnIDs to indices in `values`. secondaryIndexEntries is the return
 // value (passed as a parameter so the caller can reuse between rows) and is
-// expected to be the same length as indexes.
+// expected to be the same length as indexes. It holds the entries of each
+// index: a forward index has a single entry per row, and an inverted index one
+// per element of the indexed value.
 func EncodeSecondaryIndexes(
 	tableDesc *TableDescriptor,
 	indexes []IndexDescriptor,
 	colMap map[ColumnID]int,
 	values []parser.Datum,
-	secondaryIndexEntries []IndexEntry,
+	secondaryIndexEntries [][]IndexEntry,
 ) error {
 	for i := range indexes {
-		var err error
-		secondaryIndexEntries[i], err = EncodeSecondaryIndex(tableDesc, &indexes[i], colMap, values)
+		index := &indexes[i]
+		if index.PrimaryEncoding {
+			entries, err := EncodePrimaryIndex(tableDesc, index, colMap, values)
+			if err != nil {
+				return err
+			}
+			secondaryIndexEntries[i] = entries
+			continue
+		}
+		if index.Type == IndexDescriptor_INVERTED {
+			entries, err := EncodeInvertedIndexKeys(tableDesc, index, colMap, values)
+			if err != nil {
+				return err
+			}
+			secondaryIndexEntries[i] = entries
+			continue
+		}
+		entry, err := EncodeSecondaryIndex(tableDesc, index, colMap, values)
 		if err != nil {
 			return err
 		}
+		secondaryIndexEntries[i] = append(secondaryIndexEntries[i][:0], entry)
 	}
 	return nil
 }
@@ -1264,6 +1794,11 @@ func CheckColumnType(col ColumnDescriptor, val parser.Datum, pmap *parser.Placeh
 	case ColumnType_STRING:
 		_, ok = val.(*parser.DString)
 		set = parser.TypeString
+	case ColumnType_COLLATEDSTRING:
+		if v, vok := val.(*parser.DCollatedString); vok {
+			ok = v.Locale == *col.Type.Locale
+		}
+		set = col.Type.ToDatumType()
 	case ColumnType_BYTES:
 		_, ok = val.(*parser.DBytes)
 		if !ok {
@@ -1339,6 +1874,11 @@ func MarshalColumnValue(col ColumnDescriptor, val parser.Datum) (roachpb.Value,
 			r.SetString(string(*v))
 			return r, nil
 		}
+	case ColumnType_COLLATEDSTRING:
+		if v, ok := val.(*parser.DCollatedString); ok && v.Locale == *col.Type.Locale {
+			r.SetString(v.Contents)
+			return r, nil
+		}
 	case ColumnType_BYTES:
 		if v, ok := val.(*parser.DBytes); ok {
 			r.SetString(string(*v))
@@ -1379,13 +1919,13 @@ func MarshalColumnValue(col ColumnDescriptor, val parser.Datum) (roachpb.Value,
 // expected by the column. An error is returned if the value's type does not
 // match the column's type.
 func UnmarshalColumnValue(
-	a *DatumAlloc, kind ColumnType_Kind, value *roachpb.Value,
+	a *DatumAlloc, typ ColumnType, value *roachpb.Value,
 ) (parser.Datum, error) {
 	if value == nil {
 		return parser.DNull, nil
 	}
 
-	switch kind {
+	switch typ.Kind {
 	case ColumnType_BOOL:
 		v, err := value.GetBool()
 		if err != nil {
@@ -1418,6 +1958,12 @@ func UnmarshalColumnValue(
 			return nil, err
 		}
 		return a.NewDString(parser.DString(v)), nil
+	case ColumnType_COLLATEDSTRING:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return parser.NewDCollatedString(string(v), *typ.Locale), nil
 	case ColumnType_BYTES:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -1449,7 +1995,7 @@ func UnmarshalColumnValue(
 		}
 		return a.NewDInterval(parser.DInterval{Duration: d}), nil
 	default:
-		return nil, errors.Errorf("unsupported column type: %s", kind)
+		return nil, errors.Errorf("unsupported column type: %s", typ.Kind)
 	}
 }
 
@@ -1465,6 +2011,13 @@ func CheckValueWidth(col ColumnDescriptor, val parser.Datum) error {
 					col.Type.SQLString(), col.Name)
 			}
 		}
+	case ColumnType_COLLATEDSTRING:
+		if v, ok := val.(*parser.DCollatedString); ok {
+			if col.Type.Width > 0 && utf8.RuneCountInString(v.Contents) > int(col.Type.Width) {
+				return fmt.Errorf("value too long for type %s (column %q)",
+					col.Type.SQLString(), col.Name)
+			}
+		}
 	case ColumnType_DECIMAL:
 		if v, ok := val.(*parser.DDecimal); ok {
 			if col.Type.Precision > 0 {
@@ -94,6 +94,12 @@ func RandDatum(rng *rand.Rand, typ ColumnType_Kind, null bool) parser.Datum {
 			p[i] = byte(1 + rng.Intn(127))
 		}
 		return parser.NewDString(string(p))
+	case ColumnType_COLLATEDSTRING:
+		p := make([]byte, rng.Intn(10))
+		for i := range p {
+			p[i] = byte(1 + rng.Intn(127))
+		}
+		return parser.NewDCollatedString(string(p), "en")
 	case ColumnType_BYTES:
 		p := make([]byte, rng.Intn(10))
 		_, _ = rng.Read(p)
@@ -0,0 +1,314 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/metric"
+	"github.com/cockroachdb/cockroach/util/stop"
+	"github.com/cockroachdb/cockroach/util/timeutil"
+)
+
+// stmtStatsFlushInterval is the interval at which the statement and
+// transaction statistics collected by a node are added to the system tables.
+const stmtStatsFlushInterval = 10 * time.Minute
+
+// stmtStatsAggregationInterval is the granularity of the persisted
+// statistics: the statistics of all the statements executed in the same
+// interval are added to the same rows.
+const stmtStatsAggregationInterval = time.Hour
+
+// statementStatsTableSchema describes the schema of the statement statistics
+// table. Statements are identified by their fingerprint: their text with the
+// constants replaced by '_'.
+const statementStatsTableSchema = `
+CREATE TABLE system.statement_statistics (
+  aggregatedTS     TIMESTAMP,
+  fingerprint      STRING,
+  count            INT        NOT NULL,
+  errors           INT        NOT NULL,
+  rows             INT        NOT NULL,
+  latencyNanos     INT        NOT NULL,
+  maxLatencyNanos  INT        NOT NULL,
+  PRIMARY KEY (aggregatedTS, fingerprint)
+);`
+
+// transactionStatsTableSchema describes the schema of the transaction
+// statistics table.
+const transactionStatsTableSchema = `
+CREATE TABLE system.transaction_statistics (
+  aggregatedTS  TIMESTAMP  PRIMARY KEY,
+  begins        INT        NOT NULL,
+  commits       INT        NOT NULL,
+  rollbacks     INT        NOT NULL,
+  aborts        INT        NOT NULL
+);`
+
+// AddStatementStatsToMetadataSchema adds the statement and transaction
+// statistics tables to the supplied MetadataSchema.
+func AddStatementStatsToMetadataSchema(schema *sqlbase.MetadataSchema) {
+	schema.AddTable(keys.StatementStatsTableID, statementStatsTableSchema)
+	schema.AddTable(keys.TransactionStatsTableID, transactionStatsTableSchema)
+}
+
+// maxStmtStatsFingerprints is the maximum number of fingerprints whose
+// statistics are kept in memory between two flushes. The statistics of the
+// statements with other fingerprints are aggregated under
+// stmtStatsOverflowFingerprint, so that a workload with many distinct
+// statements does not make the node run out of memory.
+const maxStmtStatsFingerprints = 5000
+
+// stmtStatsOverflowFingerprint is the fingerprint under which the statistics
+// of the statements beyond maxStmtStatsFingerprints are aggregated.
+const stmtStatsOverflowFingerprint = "(other)"
+
+type stmtStatsKey struct {
+	aggregatedTS time.Time
+	fingerprint  string
+}
+
+type stmtStats struct {
+	count      int64
+	errors     int64
+	rows       int64
+	latency    time.Duration
+	maxLatency time.Duration
+}
+
+// txnCounts holds the values of the transaction counters of an Executor.
+type txnCounts struct {
+	begins, commits, rollbacks, aborts int64
+}
+
+// add adds o to the statistics.
+func (st *stmtStats) add(o stmtStats) {
+	st.count += o.count
+	st.errors += o.errors
+	st.rows += o.rows
+	st.latency += o.latency
+	if o.maxLatency > st.maxLatency {
+		st.maxLatency = o.maxLatency
+	}
+}
+
+func (c txnCounts) sub(o txnCounts) txnCounts {
+	return txnCounts{
+		begins:    c.begins - o.begins,
+		commits:   c.commits - o.commits,
+		rollbacks: c.rollbacks - o.rollbacks,
+		aborts:    c.aborts - o.aborts,
+	}
+}
+
+// statementStats collects the statistics of the statements executed by an
+// Executor. They are kept in memory, aggregated by hour, and periodically
+// added to the ones persisted in system.statement_statistics, which holds the
+// totals of all the nodes. Along with them, the number of transactions begun,
+// committed, rolled back and aborted since the last flush, as counted by the
+// txn.* metrics of the Executor, are added to system.transaction_statistics.
+type statementStats struct {
+	txnBeginCount, txnCommitCount, txnRollbackCount, txnAbortCount *metric.Counter
+
+	mu    sync.Mutex
+	stats map[stmtStatsKey]stmtStats
+	// flushedTxnCounts holds the values of the transaction counters at the
+	// last successful flush.
+	flushedTxnCounts txnCounts
+}
+
+func newStatementStats(e *Executor) *statementStats {
+	return &statementStats{
+		txnBeginCount:    e.txnBeginCount,
+		txnCommitCount:   e.txnCommitCount,
+		txnRollbackCount: e.txnRollbackCount,
+		txnAbortCount:    e.txnAbortCount,
+		stats:            make(map[stmtStatsKey]stmtStats),
+	}
+}
+
+// stmtFingerprint returns the fingerprint under which the statistics of a
+// statement are aggregated.
+func stmtFingerprint(stmt parser.Statement) string {
+	return parser.AsStringWithFlags(stmt, parser.FmtHideConstants)
+}
+
+// recordStatement records an execution of a statement, which returned or
+// affected the given number of rows and took the given duration.
+func (s *statementStats) recordStatement(
+	stmt parser.Statement, rows int, latency time.Duration, err error,
+) {
+	key := stmtStatsKey{
+		aggregatedTS: timeutil.Now().Truncate(stmtStatsAggregationInterval),
+		fingerprint:  stmtFingerprint(stmt),
+	}
+	st := stmtStats{count: 1, rows: int64(rows), latency: latency, maxLatency: latency}
+	if err != nil {
+		st.errors = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addLocked(key, st)
+}
+
+// merge adds the given statistics to the ones which haven't been flushed
+// yet.
+func (s *statementStats) merge(stats map[stmtStatsKey]stmtStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, st := range stats {
+		s.addLocked(key, st)
+	}
+}
+
+// addLocked adds st to the statistics of key, or to the ones of the overflow
+// fingerprint if there are already maxStmtStatsFingerprints keys. s.mu must be
+// held.
+func (s *statementStats) addLocked(key stmtStatsKey, st stmtStats) {
+	cur, ok := s.stats[key]
+	if !ok && len(s.stats) >= maxStmtStatsFingerprints {
+		key.fingerprint = stmtStatsOverflowFingerprint
+		cur = s.stats[key]
+	}
+	cur.add(st)
+	s.stats[key] = cur
+}
+
+func (s *statementStats) txnCounts() txnCounts {
+	return txnCounts{
+		begins:    s.txnBeginCount.Count(),
+		commits:   s.txnCommitCount.Count(),
+		rollbacks: s.txnRollbackCount.Count(),
+		aborts:    s.txnAbortCount.Count(),
+	}
+}
+
+// flush adds the statistics collected since the last flush to the system
+// tables. If this fails, the statistics are kept for the next flush.
+func (s *statementStats) flush(db client.DB, leaseMgr *LeaseManager) error {
+	s.mu.Lock()
+	stats := s.stats
+	s.stats = make(map[stmtStatsKey]stmtStats)
+	flushed := s.flushedTxnCounts
+	s.mu.Unlock()
+	cur := s.txnCounts()
+	txns := cur.sub(flushed)
+
+	const upsertStmtStatsStmt = `
+INSERT INTO system.statement_statistics
+  (aggregatedTS, fingerprint, count, errors, rows, latencyNanos, maxLatencyNanos)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (aggregatedTS, fingerprint) DO UPDATE SET
+  count = statement_statistics.count + excluded.count,
+  errors = statement_statistics.errors + excluded.errors,
+  rows = statement_statistics.rows + excluded.rows,
+  latencyNanos = statement_statistics.latencyNanos + excluded.latencyNanos,
+  maxLatencyNanos = GREATEST(statement_statistics.maxLatencyNanos, excluded.maxLatencyNanos)
+`
+	const upsertTxnStatsStmt = `
+INSERT INTO system.transaction_statistics (aggregatedTS, begins, commits, rollbacks, aborts)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (aggregatedTS) DO UPDATE SET
+  begins = transaction_statistics.begins + excluded.begins,
+  commits = transaction_statistics.commits + excluded.commits,
+  rollbacks = transaction_statistics.rollbacks + excluded.rollbacks,
+  aborts = transaction_statistics.aborts + excluded.aborts
+`
+	ie := InternalExecutor{LeaseManager: leaseMgr}
+	if err := db.Txn(func(txn *client.Txn) error {
+		for key, st := range stats {
+			if _, err := ie.ExecuteStatementInTransaction(txn, upsertStmtStatsStmt,
+				key.aggregatedTS, key.fingerprint, st.count, st.errors, st.rows,
+				st.latency.Nanoseconds(), st.maxLatency.Nanoseconds()); err != nil {
+				return err
+			}
+		}
+		if txns == (txnCounts{}) {
+			return nil
+		}
+		_, err := ie.ExecuteStatementInTransaction(txn, upsertTxnStatsStmt,
+			timeutil.Now().Truncate(stmtStatsAggregationInterval),
+			txns.begins, txns.commits, txns.rollbacks, txns.aborts)
+		return err
+	}); err != nil {
+		s.merge(stats)
+		return err
+	}
+	s.mu.Lock()
+	s.flushedTxnCounts = cur
+	s.mu.Unlock()
+	return nil
+}
+
+// deleteBefore deletes the statistics aggregated before cutoff from the
+// system tables. It returns the number of deleted rows.
+func (s *statementStats) deleteBefore(
+	db client.DB, leaseMgr *LeaseManager, cutoff time.Time,
+) (int, error) {
+	ie := InternalExecutor{LeaseManager: leaseMgr}
+	var deleted int
+	err := db.Txn(func(txn *client.Txn) error {
+		deleted = 0
+		for _, stmt := range []string{
+			`DELETE FROM system.statement_statistics WHERE aggregatedTS < $1`,
+			`DELETE FROM system.transaction_statistics WHERE aggregatedTS < $1`,
+		} {
+			n, err := ie.ExecuteStatementInTransaction(txn, stmt, cutoff)
+			if err != nil {
+				return err
+			}
+			deleted += n
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// StartStatementStatsFlusher starts a worker which periodically flushes the
+// statement and transaction statistics of the Executor to the system tables
+// and deletes the statistics older than retention. Statistics are kept
+// forever if retention is not positive.
+func (e *Executor) StartStatementStatsFlusher(retention time.Duration, stopper *stop.Stopper) {
+	db, leaseMgr := *e.ctx.DB, e.ctx.LeaseManager
+	stopper.RunWorker(func() {
+		ticker := time.NewTicker(stmtStatsFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := e.stmtStats.flush(db, leaseMgr); err != nil {
+					log.Warningf("unable to flush statement statistics: %s", err)
+				}
+				if retention <= 0 {
+					continue
+				}
+				cutoff := timeutil.Now().Add(-retention)
+				if deleted, err := e.stmtStats.deleteBefore(db, leaseMgr, cutoff); err != nil {
+					log.Warningf("unable to delete statement statistics before %s: %s", cutoff, err)
+				} else if log.V(1) {
+					log.Infof("deleted %d statement statistics rows before %s", deleted, cutoff)
+				}
+			case <-stopper.ShouldStop():
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+	"github.com/cockroachdb/cockroach/util/metric"
+	"github.com/cockroachdb/cockroach/util/timeutil"
+	"github.com/pkg/errors"
+)
+
+func TestStmtFingerprint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		sql      string
+		expected string
+	}{
+		{`SELECT * FROM t WHERE k = 1`, `SELECT * FROM t WHERE k = _`},
+		{`SELECT * FROM t WHERE k = 2`, `SELECT * FROM t WHERE k = _`},
+		{`SELECT * FROM t WHERE k = $1`, `SELECT * FROM t WHERE k = $1`},
+		{`SELECT a, 'foo' FROM t LIMIT 10`, `SELECT a, _ FROM t LIMIT _`},
+		{`INSERT INTO t VALUES (1, 'a', NULL)`, `INSERT INTO t VALUES (_, _, _)`},
+		{`UPDATE t SET v = v + 1.5 WHERE k IN (1, 2, 3)`,
+			`UPDATE t SET v = v + _ WHERE k IN (_, _, _)`},
+	}
+	for _, d := range testData {
+		stmt, err := parser.ParseOneTraditional(d.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", d.sql, err)
+		}
+		if f := stmtFingerprint(stmt); f != d.expected {
+			t.Errorf("%s: expected %s, but found %s", d.sql, d.expected, f)
+		}
+	}
+}
+
+// TestStatementStatsFlush tests that the statement and transaction
+// statistics collected in memory are added to the ones in the system tables,
+// and that the old statistics are deleted.
+func TestStatementStatsFlush(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+	leaseManager := s.LeaseManager().(*LeaseManager)
+
+	stats := newStatementStats(&Executor{
+		txnBeginCount:    metric.NewCounter(),
+		txnCommitCount:   metric.NewCounter(),
+		txnRollbackCount: metric.NewCounter(),
+		txnAbortCount:    metric.NewCounter(),
+	})
+	record := func(sql string, rows int, err error) {
+		stmt, parseErr := parser.ParseOneTraditional(sql)
+		if parseErr != nil {
+			t.Fatal(parseErr)
+		}
+		stats.recordStatement(stmt, rows, time.Millisecond, err)
+	}
+
+	checkStmtStats := func(fingerprint string, expCount, expErrors, expRows int) {
+		var count, errs, rows int
+		if err := db.QueryRow(
+			`SELECT SUM(count), SUM(errors), SUM(rows) FROM system.statement_statistics
+WHERE fingerprint = $1`, fingerprint,
+		).Scan(&count, &errs, &rows); err != nil {
+			t.Fatal(err)
+		}
+		if count != expCount || errs != expErrors || rows != expRows {
+			t.Errorf("%s: expected count=%d errors=%d rows=%d, but found %d, %d, %d",
+				fingerprint, expCount, expErrors, expRows, count, errs, rows)
+		}
+	}
+	checkTxnStats := func(expBegins, expCommits int) {
+		var begins, commits int
+		if err := db.QueryRow(
+			`SELECT SUM(begins), SUM(commits) FROM system.transaction_statistics`,
+		).Scan(&begins, &commits); err != nil {
+			t.Fatal(err)
+		}
+		if begins != expBegins || commits != expCommits {
+			t.Errorf("expected %d begins and %d commits, but found %d and %d",
+				expBegins, expCommits, begins, commits)
+		}
+	}
+
+	record(`SELECT * FROM t WHERE k = 1`, 1, nil)
+	record(`SELECT * FROM t WHERE k = 2`, 0, nil)
+	record(`INSERT INTO t VALUES (1)`, 0, errors.New("duplicate key"))
+	stats.txnBeginCount.Inc(2)
+	stats.txnCommitCount.Inc(1)
+	if err := stats.flush(*kvDB, leaseManager); err != nil {
+		t.Fatal(err)
+	}
+	checkStmtStats(`SELECT * FROM t WHERE k = _`, 2, 0, 1)
+	checkStmtStats(`INSERT INTO t VALUES (_)`, 1, 1, 0)
+	checkTxnStats(2, 1)
+
+	// Flushing again adds the new statistics to the persisted ones.
+	record(`SELECT * FROM t WHERE k = 3`, 1, nil)
+	stats.txnBeginCount.Inc(1)
+	stats.txnCommitCount.Inc(1)
+	if err := stats.flush(*kvDB, leaseManager); err != nil {
+		t.Fatal(err)
+	}
+	checkStmtStats(`SELECT * FROM t WHERE k = _`, 3, 0, 2)
+	checkTxnStats(3, 2)
+
+	// All the statistics were aggregated in the current hour, so none of them
+	// are older than an hour ago.
+	if deleted, err := stats.deleteBefore(
+		*kvDB, leaseManager, timeutil.Now().Add(-time.Hour),
+	); err != nil {
+		t.Fatal(err)
+	} else if deleted != 0 {
+		t.Errorf("expected no rows to be deleted, but %d were", deleted)
+	}
+	if deleted, err := stats.deleteBefore(
+		*kvDB, leaseManager, timeutil.Now().Add(time.Hour),
+	); err != nil {
+		t.Fatal(err)
+	} else if deleted < 3 {
+		t.Errorf("expected at least 3 rows to be deleted, but %d were", deleted)
+	}
+	var count int
+	if err := db.QueryRow(
+		`SELECT COUNT(*) FROM system.statement_statistics`,
+	).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("expected no statement statistics, but found %d", count)
+	}
+}
+
+// TestStatementStatsLimit tests that the statistics of the statements beyond
+// maxStmtStatsFingerprints are aggregated under the overflow fingerprint.
+func TestStatementStatsLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stats := newStatementStats(&Executor{})
+	ts := timeutil.Now().Truncate(stmtStatsAggregationInterval)
+	for i := 0; i < maxStmtStatsFingerprints+10; i++ {
+		stats.merge(map[stmtStatsKey]stmtStats{
+			{aggregatedTS: ts, fingerprint: fmt.Sprintf("SELECT %d", i)}: {count: 1, rows: 2},
+		})
+	}
+	if n := len(stats.stats); n != maxStmtStatsFingerprints+1 {
+		t.Fatalf("expected %d fingerprints, but found %d", maxStmtStatsFingerprints+1, n)
+	}
+	overflow := stats.stats[stmtStatsKey{aggregatedTS: ts, fingerprint: stmtStatsOverflowFingerprint}]
+	if overflow.count != 10 || overflow.rows != 20 {
+		t.Errorf("expected count=10 rows=20 for the overflow fingerprint, but found %d, %d",
+			overflow.count, overflow.rows)
+	}
+
+	// The statistics of a known fingerprint are still added to it.
+	stats.merge(map[stmtStatsKey]stmtStats{
+		{aggregatedTS: ts, fingerprint: "SELECT 0"}: {count: 1},
+	})
+	if st := stats.stats[stmtStatsKey{aggregatedTS: ts, fingerprint: "SELECT 0"}]; st.count != 2 {
+		t.Errorf("expected count=2, but found %d", st.count)
+	}
+}
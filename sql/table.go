@@ -138,7 +138,7 @@ var _ SchemaAccessor = &planner{}
 
 // getTableDesc implements the SchemaAccessor interface.
 func (p *planner) getTableDesc(qname *parser.QualifiedName) (*sqlbase.TableDescriptor, error) {
-	if err := qname.NormalizeTableName(p.session.Database); err != nil {
+	if err := p.normalizeTableName(qname); err != nil {
 		return nil, err
 	}
 	dbDesc, err := p.mustGetDatabaseDesc(qname.Database())
@@ -174,7 +174,7 @@ func (p *planner) getTableLease(qname *parser.QualifiedName) (*sqlbase.TableDesc
 	if log.V(2) {
 		log.Infof("planner acquiring lease on table %q", qname)
 	}
-	if err := qname.NormalizeTableName(p.session.Database); err != nil {
+	if err := p.normalizeTableName(qname); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,144 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/storage/engine/enginepb"
+	"github.com/pkg/errors"
+)
+
+// SpanStats holds the statistics of the data of a key span.
+type SpanStats struct {
+	// Stats are the total MVCC statistics of all the replicas of the ranges
+	// overlapping the span, restricted to the keys of the span.
+	Stats enginepb.MVCCStats
+	// Replicas and Ranges are the numbers of replicas and of ranges
+	// overlapping the span.
+	Replicas, Ranges int
+}
+
+// physicalBytes returns the number of bytes of the keys and values of all the
+// replicas, including the previous versions of the values which haven't been
+// garbage collected yet.
+func (s SpanStats) physicalBytes() int64 {
+	return s.Stats.Total()
+}
+
+// logicalBytes returns the number of bytes of the live keys and values of a
+// single replica of the span, assuming all its ranges are equally replicated.
+func (s SpanStats) logicalBytes() int64 {
+	if s.Replicas == 0 {
+		return 0
+	}
+	return s.Stats.LiveBytes * int64(s.Ranges) / int64(s.Replicas)
+}
+
+var _ parser.SizeEstimator = &planner{}
+
+// TableSize implements the parser.SizeEstimator interface.
+func (p *planner) TableSize(table string) (parser.Datum, error) {
+	tableDesc, err := p.getTableDescFromString(table)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := p.tableSpanStats(tableDesc)
+	if err != nil {
+		return nil, err
+	}
+	return parser.NewDInt(parser.DInt(stats.physicalBytes())), nil
+}
+
+// IndexSize implements the parser.SizeEstimator interface.
+func (p *planner) IndexSize(table, index string) (parser.Datum, error) {
+	tableDesc, err := p.getTableDescFromString(table)
+	if err != nil {
+		return nil, err
+	}
+	indexDesc, err := findPublicIndexByName(tableDesc, index)
+	if err != nil {
+		return nil, err
+	}
+	prefix := roachpb.Key(sqlbase.MakeIndexKeyPrefix(tableDesc, indexDesc.ID))
+	stats, err := p.spanStats(roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	if err != nil {
+		return nil, err
+	}
+	return parser.NewDInt(parser.DInt(stats.physicalBytes())), nil
+}
+
+// getTableDescFromString returns the descriptor of a table named by a string, such as
+// "t" or "db.t".
+func (p *planner) getTableDescFromString(table string) (*sqlbase.TableDescriptor, error) {
+	expr, err := parser.ParseExprTraditional(table)
+	if err != nil {
+		return nil, err
+	}
+	qname, ok := expr.(*parser.QualifiedName)
+	if !ok {
+		return nil, fmt.Errorf("invalid table name: %q", table)
+	}
+	if err := p.normalizeTableName(qname); err != nil {
+		return nil, err
+	}
+	return p.mustGetTableDesc(qname)
+}
+
+// tableSpanStats returns the statistics of the data of a table, including
+// all its indexes.
+func (p *planner) tableSpanStats(tableDesc *sqlbase.TableDescriptor) (SpanStats, error) {
+	prefix := roachpb.Key(keys.MakeTablePrefix(uint32(tableDesc.ID)))
+	return p.spanStats(roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+}
+
+func (p *planner) spanStats(span roachpb.Span) (SpanStats, error) {
+	if p.execCtx == nil || p.execCtx.SpanStats == nil {
+		return SpanStats{}, errors.New("span statistics are not available")
+	}
+	startKey, err := keys.Addr(span.Key)
+	if err != nil {
+		return SpanStats{}, err
+	}
+	endKey, err := keys.Addr(span.EndKey)
+	if err != nil {
+		return SpanStats{}, err
+	}
+	return p.execCtx.SpanStats(roachpb.RSpan{Key: startKey, EndKey: endKey})
+}
+
+// estimatedRowCount returns the number of rows of a table counted by its most
+// recent statistic, or DNull if CREATE STATISTICS was never run on it.
+func (p *planner) estimatedRowCount(tableDesc *sqlbase.TableDescriptor) (parser.Datum, error) {
+	if p.txn == nil {
+		return nil, errors.New("statistics can only be read in a transaction")
+	}
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	row, err := ip.queryRow(`SELECT rowCount FROM system.table_statistics WHERE tableID = $1
+ORDER BY createdAt DESC LIMIT 1`, int(tableDesc.ID))
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return parser.DNull, nil
+	}
+	return row[0], nil
+}
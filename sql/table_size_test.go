@@ -0,0 +1,76 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestShowWithDetails tests that SHOW DATABASES and SHOW TABLES WITH DETAILS
+// report the sizes of the data of tables, and the row counts of their
+// statistics.
+func TestShowWithDetails(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (k INT PRIMARY KEY, v STRING);
+INSERT INTO d.t VALUES (1, 'one'), (2, 'two'), (3, 'three');
+CREATE STATISTICS s ON k FROM d.t;
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	checkDetails := func(query, name string) {
+		rows, err := db.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var rowName string
+			var estimatedRows sql.NullInt64
+			var logical, physical int64
+			if err := rows.Scan(&rowName, &estimatedRows, &logical, &physical); err != nil {
+				t.Fatal(err)
+			}
+			if rowName != name {
+				continue
+			}
+			if !estimatedRows.Valid || estimatedRows.Int64 != 3 {
+				t.Errorf("%s: expected 3 estimated rows, but found %v", query, estimatedRows)
+			}
+			if logical <= 0 || physical < logical {
+				t.Errorf("%s: expected 0 < logical bytes <= physical bytes, but found %d and %d",
+					query, logical, physical)
+			}
+			return
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		t.Errorf("%s: %s not found", query, name)
+	}
+	checkDetails(`SHOW TABLES FROM d WITH DETAILS`, "t")
+	checkDetails(`SHOW DATABASES WITH DETAILS`, "d")
+}
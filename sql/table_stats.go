@@ -0,0 +1,251 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/privilege"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// histogramBuckets is the maximum number of buckets of the histograms
+// collected for single column statistics.
+const histogramBuckets = 10
+
+// tableStatisticsTableSchema describes the schema of the table statistics
+// table. Each row holds a statistic collected by CREATE STATISTICS on a set
+// of columns of a table, whose IDs are stored as a comma-separated list.
+const tableStatisticsTableSchema = `
+CREATE TABLE system.table_statistics (
+  tableID        INT,
+  name           STRING,
+  columnIDs      STRING     NOT NULL,
+  createdAt      TIMESTAMP  NOT NULL,
+  rowCount       INT        NOT NULL,
+  distinctCount  INT        NOT NULL,
+  nullCount      INT        NOT NULL,
+  histogram      STRING,
+  PRIMARY KEY (tableID, name)
+);`
+
+// AddTableStatisticsToMetadataSchema adds the table statistics table to the
+// supplied MetadataSchema.
+func AddTableStatisticsToMetadataSchema(schema *sqlbase.MetadataSchema) {
+	schema.AddTable(keys.TableStatisticsTableID, tableStatisticsTableSchema)
+}
+
+// CreateStatistics computes the number of rows of a table, and the number of
+// distinct values and of NULL values of a set of its columns, and stores them
+// in system.table_statistics, replacing the statistic of the same name if
+// any. A histogram of the values is also computed for single columns.
+// Privileges: SELECT on table.
+//   Notes: postgres requires ownership of the table.
+func (p *planner) CreateStatistics(n *parser.CreateStatistics) (planNode, error) {
+	if err := p.normalizeTableName(n.Table); err != nil {
+		return nil, err
+	}
+	tableDesc, err := p.mustGetTableDesc(n.Table)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPrivilege(tableDesc, privilege.SELECT); err != nil {
+		return nil, err
+	}
+
+	colIDs := make([]string, len(n.ColumnNames))
+	colNames := make([]string, len(n.ColumnNames))
+	isNull := make([]string, len(n.ColumnNames))
+	for i, name := range n.ColumnNames {
+		col, err := tableDesc.FindActiveColumnByName(name)
+		if err != nil {
+			return nil, err
+		}
+		colIDs[i] = strconv.Itoa(int(col.ID))
+		colNames[i] = parser.Name(col.Name).String()
+		isNull[i] = colNames[i] + " IS NULL"
+	}
+	table := n.Table.String()
+	cols := strings.Join(colNames, ", ")
+
+	countRows := func(query string) (int64, error) {
+		row, err := p.queryRow(query)
+		if err != nil {
+			return 0, err
+		}
+		return int64(*row[0].(*parser.DInt)), nil
+	}
+	rowCount, err := countRows(fmt.Sprintf(`SELECT COUNT(*) FROM %s`, table))
+	if err != nil {
+		return nil, err
+	}
+	distinctCount, err := countRows(
+		fmt.Sprintf(`SELECT COUNT(*) FROM (SELECT DISTINCT %s FROM %s) AS d`, cols, table))
+	if err != nil {
+		return nil, err
+	}
+	nullCount, err := countRows(fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s`,
+		table, strings.Join(isNull, " OR ")))
+	if err != nil {
+		return nil, err
+	}
+	histogram := parser.DNull
+	if len(colNames) == 1 {
+		h, err := p.computeHistogram(table, colNames[0], rowCount-nullCount)
+		if err != nil {
+			return nil, err
+		}
+		histogram = parser.NewDString(h)
+	}
+
+	ie := InternalExecutor{LeaseManager: p.leaseMgr}
+	if _, err := ie.ExecuteStatementInTransaction(p.txn, `
+UPSERT INTO system.table_statistics
+  (tableID, name, columnIDs, createdAt, rowCount, distinctCount, nullCount, histogram)
+VALUES ($1, $2, $3, now(), $4, $5, $6, $7)`,
+		int(tableDesc.ID), string(n.Name), strings.Join(colIDs, ","),
+		rowCount, distinctCount, nullCount, histogram); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// computeHistogram returns an equi-depth histogram of the non-NULL values of
+// a column of a table, which has the given number of them. It is formatted as
+// a list of buckets "<upper bound>:<rows>", where each bucket holds the values
+// greater than the upper bound of the previous bucket.
+func (p *planner) computeHistogram(table, col string, numValues int64) (string, error) {
+	plan, err := p.query(fmt.Sprintf(`SELECT %[1]s FROM %[2]s WHERE %[1]s IS NOT NULL ORDER BY %[1]s`,
+		col, table))
+	if err != nil {
+		return "", err
+	}
+	if err := plan.Start(); err != nil {
+		return "", err
+	}
+	bucketSize := (numValues + histogramBuckets - 1) / histogramBuckets
+	var buf bytes.Buffer
+	var prev parser.Datum
+	var rows int64
+	flush := func() {
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%s:%d", prev, rows)
+		rows = 0
+	}
+	for {
+		next, err := plan.Next()
+		if err != nil {
+			return "", err
+		}
+		if !next {
+			break
+		}
+		val := plan.Values()[0]
+		// All the rows with the same value go to the same bucket.
+		if prev != nil && rows >= bucketSize && val.Compare(prev) != 0 {
+			flush()
+		}
+		prev = val
+		rows++
+	}
+	if rows > 0 {
+		flush()
+	}
+	return buf.String(), nil
+}
+
+// ShowStatistics returns the statistics collected on a table by CREATE
+// STATISTICS.
+// Privileges: SELECT on table.
+//   Notes: postgres and mysql do not have a SHOW STATISTICS statement.
+func (p *planner) ShowStatistics(n *parser.ShowStatistics) (planNode, error) {
+	tableDesc, err := p.mustGetTableDesc(n.Table)
+	if err != nil {
+		return nil, err
+	}
+	// The statistics are read as root, but reveal the contents of the table.
+	if err := p.checkPrivilege(tableDesc, privilege.SELECT); err != nil {
+		return nil, err
+	}
+	if p.txn == nil {
+		return nil, errors.New("statistics can only be read in a transaction")
+	}
+
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	plan, err := ip.query(`SELECT name, columnIDs, createdAt, rowCount, distinctCount, nullCount,
+histogram FROM system.table_statistics WHERE tableID = $1 ORDER BY name`, int(tableDesc.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.Start(); err != nil {
+		return nil, err
+	}
+
+	v := &valuesNode{
+		columns: []ResultColumn{
+			{Name: "Name", Typ: parser.TypeString},
+			{Name: "Columns", Typ: parser.TypeString},
+			{Name: "Created", Typ: parser.TypeTimestamp},
+			{Name: "RowCount", Typ: parser.TypeInt},
+			{Name: "DistinctCount", Typ: parser.TypeInt},
+			{Name: "NullCount", Typ: parser.TypeInt},
+			{Name: "Histogram", Typ: parser.TypeString},
+		},
+	}
+	for {
+		next, err := plan.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !next {
+			break
+		}
+		values := plan.Values()
+		// Columns which were dropped since the statistic was collected are
+		// shown by ID.
+		var colNames []string
+		for _, s := range strings.Split(string(*values[1].(*parser.DString)), ",") {
+			id, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, err
+			}
+			if col, err := tableDesc.FindColumnByID(sqlbase.ColumnID(id)); err == nil {
+				colNames = append(colNames, col.Name)
+			} else {
+				colNames = append(colNames, fmt.Sprintf("[%d]", id))
+			}
+		}
+		v.rows = append(v.rows, []parser.Datum{
+			values[0],
+			parser.NewDString(fmt.Sprintf("%v", colNames)),
+			values[2],
+			values[3],
+			values[4],
+			values[5],
+			values[6],
+		})
+	}
+	return v, nil
+}
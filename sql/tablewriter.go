@@ -69,6 +69,21 @@ type tableWriter interface {
 	finalize() error
 }
 
+// tableWriterBatchSize is the number of rows whose KV operations a
+// tableUpdater or tableDeleter accumulates in a single batch before sending
+// it. Coalescing the rows amortizes the round trips needed to maintain the
+// primary and secondary indexes, while the bound keeps an individual batch
+// from growing without limit for statements touching many rows.
+var tableWriterBatchSize = 10000
+
+// setTableWriterBatchSize changes the tableWriter batch size, and returns a
+// function that restores it.
+func setTableWriterBatchSize(val int) func() {
+	oldVal := tableWriterBatchSize
+	tableWriterBatchSize = val
+	return func() { tableWriterBatchSize = oldVal }
+}
+
 var _ tableWriter = (*tableInserter)(nil)
 var _ tableWriter = (*tableUpdater)(nil)
 var _ tableWriter = (*tableUpserter)(nil)
@@ -103,6 +118,10 @@ func (ti *tableInserter) row(values parser.DTuple) (parser.DTuple, error) {
 }
 
 func (ti *tableInserter) finalize() error {
+	if err := ti.ri.fks.runChecks(); err != nil {
+		return err
+	}
+
 	var err error
 	if ti.autoCommit {
 		// An auto-txn can commit the transaction with the batch. This is an
@@ -127,6 +146,12 @@ type tableUpdater struct {
 	// Set by init.
 	txn *client.Txn
 	b   *client.Batch
+
+	// batchRows is the number of rows whose writes are in b.
+	batchRows int
+	// flushEarly is set when the writes can be sent before all the rows to
+	// update are read, see init.
+	flushEarly bool
 }
 
 func (tu *tableUpdater) expand() error {
@@ -140,16 +165,62 @@ func (tu *tableUpdater) start() error {
 func (tu *tableUpdater) init(txn *client.Txn) error {
 	tu.txn = txn
 	tu.b = txn.NewBatch()
+	// The rows to update are read while they are updated, and the scans of
+	// the transaction see its own writes. If the update changes the key of
+	// the rows in an index, sending its writes before the source is
+	// exhausted would let a later scan batch read the rows again and update
+	// them twice. The writes are then kept in a single batch.
+	tu.flushEarly = !tu.ru.updatesIndexKeys()
 	return nil
 }
 
 func (tu *tableUpdater) row(values parser.DTuple) (parser.DTuple, error) {
 	oldValues := values[:len(tu.ru.fetchCols)]
 	updateValues := values[len(tu.ru.fetchCols):]
-	return tu.ru.updateRow(tu.b, oldValues, updateValues)
+	newValues, err := tu.ru.updateRow(tu.b, oldValues, updateValues)
+	if err != nil {
+		return nil, err
+	}
+	tu.batchRows++
+	if tu.flushEarly && tu.batchRows >= tableWriterBatchSize {
+		if err := tu.flush(); err != nil {
+			return nil, err
+		}
+	}
+	return newValues, nil
+}
+
+// flush sends the writes accumulated so far and starts a new batch. The last
+// batch is left to finalize so that an auto-txn can still commit with it.
+func (tu *tableUpdater) flush() error {
+	if err := tu.ru.fks.runChecks(); err != nil {
+		return err
+	}
+	if err := tu.txn.Run(tu.b); err != nil {
+		return convertBatchError(tu.ru.helper.tableDesc, tu.b)
+	}
+	if err := tu.ru.fks.inbound.runActions(); err != nil {
+		return err
+	}
+	tu.b = tu.txn.NewBatch()
+	tu.batchRows = 0
+	return nil
 }
 
 func (tu *tableUpdater) finalize() error {
+	if err := tu.ru.fks.runChecks(); err != nil {
+		return err
+	}
+
+	if tu.ru.fks.inbound.hasActions() {
+		// The referential actions are run once the rows are updated, so the
+		// transaction can't be committed with the batch.
+		if err := tu.txn.Run(tu.b); err != nil {
+			return convertBatchError(tu.ru.helper.tableDesc, tu.b)
+		}
+		return tu.ru.fks.inbound.runActions()
+	}
+
 	var err error
 	if tu.autoCommit {
 		// An auto-txn can commit the transaction with the batch. This is an
@@ -348,6 +419,12 @@ func (tu *tableUpserter) flush() error {
 		}
 	}
 
+	if err := tu.ri.fks.runChecks(); err != nil {
+		return err
+	}
+	if err := tu.ru.fks.runChecks(); err != nil {
+		return err
+	}
 	if err := tu.txn.Run(b); err != nil {
 		return convertBatchError(tu.tableDesc, b)
 	}
@@ -472,6 +549,9 @@ func (tu *tableUpserter) fetchExisting() ([]parser.DTuple, error) {
 
 func (tu *tableUpserter) finalize() error {
 	if tu.fastPathBatch != nil {
+		if err := tu.ri.fks.runChecks(); err != nil {
+			return err
+		}
 		return tu.txn.Run(tu.fastPathBatch)
 	}
 	return tu.flush()
@@ -485,6 +565,9 @@ type tableDeleter struct {
 	// Set by init.
 	txn *client.Txn
 	b   *client.Batch
+
+	// batchRows is the number of rows whose deletions are in b.
+	batchRows int
 }
 
 func (td *tableDeleter) expand() error {
@@ -502,10 +585,48 @@ func (td *tableDeleter) init(txn *client.Txn) error {
 }
 
 func (td *tableDeleter) row(values parser.DTuple) (parser.DTuple, error) {
-	return nil, td.rd.deleteRow(td.b, values)
+	if err := td.rd.deleteRow(td.b, values); err != nil {
+		return nil, err
+	}
+	td.batchRows++
+	if td.batchRows >= tableWriterBatchSize {
+		if err := td.rd.fks.runChecks(); err != nil {
+			return nil, err
+		}
+		if err := td.flush(); err != nil {
+			return nil, err
+		}
+		if err := td.rd.fks.runActions(); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// flush sends the deletions accumulated so far and starts a new batch. The
+// last batch is left to finalize so that an auto-txn can still commit with
+// it.
+func (td *tableDeleter) flush() error {
+	if err := td.txn.Run(td.b); err != nil {
+		return convertBatchError(td.rd.helper.tableDesc, td.b)
+	}
+	td.b = td.txn.NewBatch()
+	td.batchRows = 0
+	return nil
 }
 
 func (td *tableDeleter) finalize() error {
+	if err := td.rd.fks.runChecks(); err != nil {
+		return err
+	}
+	if td.rd.fks.hasActions() {
+		// The referential actions are run once the rows are deleted, so the
+		// transaction can't be committed with the batch.
+		if err := td.txn.Run(td.b); err != nil {
+			return err
+		}
+		return td.rd.fks.runActions()
+	}
 	if td.autoCommit {
 		// An auto-txn can commit the transaction with the batch. This is an
 		// optimization to avoid an extra round-trip to the transaction
@@ -529,6 +650,12 @@ func (td *tableDeleter) fastPathAvailable() bool {
 		}
 		return false
 	}
+	if len(td.rd.fks.fks) > 0 {
+		if log.V(2) {
+			log.Info("delete forced to scan: table is referenced by foreign keys")
+		}
+		return false
+	}
 	return true
 }
 
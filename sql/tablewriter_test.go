@@ -0,0 +1,180 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	gosql "database/sql"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/testutils"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestTableWriterBatches tests that UPDATE and DELETE keep the primary and
+// secondary indexes consistent when the writes of a statement are split over
+// several batches.
+func TestTableWriterBatches(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	// The test will screw around with the batch size; make sure to restore it
+	// at the end.
+	defer setTableWriterBatchSize(3)()
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (
+  k INT PRIMARY KEY,
+  u INT,
+  v INT,
+  w INT,
+  UNIQUE INDEX t_u (u),
+  INDEX t_v (v)
+);
+INSERT INTO d.t (k, u, v) VALUES (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4),
+  (5, 5, 5), (6, 6, 6), (7, 7, 7), (8, 8, 8), (9, 9, 9), (10, 10, 10);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	checkCount := func(query string, expected int) {
+		var count int
+		if err := db.QueryRow(query).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != expected {
+			t.Errorf("%s: expected %d, but found %d", query, expected, count)
+		}
+	}
+	checkIndexes := func(expected int) {
+		checkCount(`SELECT COUNT(*) FROM d.t@primary`, expected)
+		checkCount(`SELECT COUNT(*) FROM d.t@t_u WHERE u > 0`, expected)
+		checkCount(`SELECT COUNT(*) FROM d.t@t_v WHERE v > 0`, expected)
+	}
+
+	expectRows := func(res gosql.Result, expected int64) {
+		n, err := res.RowsAffected()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != expected {
+			t.Errorf("expected %d rows affected, but found %d", expected, n)
+		}
+	}
+
+	// An update which doesn't change the keys of the indexes is sent in
+	// several batches.
+	res, err := db.Exec(`UPDATE d.t SET w = k`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRows(res, 10)
+	checkCount(`SELECT COUNT(*) FROM d.t WHERE w = k`, 10)
+
+	res, err = db.Exec(`UPDATE d.t SET u = u + 100, v = v + 100`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRows(res, 10)
+	checkIndexes(10)
+	checkCount(`SELECT COUNT(*) FROM d.t@t_u WHERE u > 100`, 10)
+	checkCount(`SELECT COUNT(*) FROM d.t@t_v WHERE v > 100`, 10)
+
+	// A conflict on a row other than the first ones is reported and none of
+	// the statement's writes are visible.
+	if _, err := db.Exec(
+		`UPDATE d.t SET u = CASE WHEN k = 8 THEN 101 ELSE u END, v = v + 100`,
+	); !testutils.IsError(err,
+		`duplicate key value \(u\)=\(101\) violates unique constraint "t_u"`) {
+		t.Fatalf("expected unique violation, but found %v", err)
+	}
+	checkCount(`SELECT COUNT(*) FROM d.t@t_u WHERE u = 101`, 1)
+	checkCount(`SELECT COUNT(*) FROM d.t@t_v WHERE v > 200`, 0)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = tx.Exec(`DELETE FROM d.t WHERE k > 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRows(res, 8)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	checkIndexes(2)
+}
+
+// TestTableWriterUpdateKeys tests that an UPDATE changing the keys of the
+// rows it reads, with more rows than fit in a batch of the scan, updates each
+// row exactly once.
+func TestTableWriterUpdateKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	defer setTableWriterBatchSize(3)()
+	defer sqlbase.SetKVBatchSize(2)()
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (k INT PRIMARY KEY, v INT, INDEX t_v (v));
+INSERT INTO d.t VALUES (1, 1), (2, 2), (3, 3), (4, 4), (5, 5),
+  (6, 6), (7, 7), (8, 8), (9, 9), (10, 10);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	checkSums := func(expK, expV int) {
+		var count, k, v int
+		if err := db.QueryRow(`SELECT COUNT(*), SUM(k), SUM(v) FROM d.t`).Scan(&count, &k, &v); err != nil {
+			t.Fatal(err)
+		}
+		if count != 10 || k != expK || v != expV {
+			t.Errorf("expected 10 rows with sums %d and %d, but found %d rows with sums %d and %d",
+				expK, expV, count, k, v)
+		}
+		if err := db.QueryRow(`SELECT COUNT(*) FROM d.t@t_v WHERE v > 0`).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 10 {
+			t.Errorf("expected 10 entries in t_v, but found %d", count)
+		}
+	}
+
+	// The moved rows sort after the ones still to be read.
+	for _, stmt := range []string{
+		`UPDATE d.t SET k = k + 100`,
+		`UPDATE d.t SET v = v + 100 WHERE v > 0`,
+	} {
+		res, err := db.Exec(stmt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			t.Fatal(err)
+		} else if n != 10 {
+			t.Errorf("%s: expected 10 rows affected, but found %d", stmt, n)
+		}
+	}
+	checkSums(1055, 1055)
+}
@@ -0,0 +1,207 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/config"
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/privilege"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/util/encoding"
+	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/uuid"
+	"github.com/pkg/errors"
+)
+
+// The temporary tables of a session are created in a database of their own,
+// named pg_temp_<node ID>_<process ID>_<session ID>, the IDs of the process
+// and of the session being random. The database is created along with the
+// first temporary table of the session, and dropped when the session ends.
+// The databases left behind by the sessions of an earlier process of a node
+// are dropped when the node starts (see DropOrphanedTempDatabases).
+const tempDatabasePrefix = "pg_temp_"
+
+// makeTempDatabasePrefix returns the prefix of the names of the temporary
+// databases created by the sessions of this process of a node.
+func makeTempDatabasePrefix(nodeID roachpb.NodeID) string {
+	return fmt.Sprintf("%s%d_%x_", tempDatabasePrefix, nodeID, uuid.MakeV4().GetBytes()[:4])
+}
+
+// isTempDatabase returns whether a database holds the temporary tables of a
+// session.
+func isTempDatabase(name string) bool {
+	return strings.HasPrefix(name, tempDatabasePrefix)
+}
+
+// getOrCreateTempDatabase returns the descriptor of the database holding the
+// temporary tables of the session, creating it if needed. The session's user
+// is granted all the privileges on it.
+func (p *planner) getOrCreateTempDatabase() (*sqlbase.DatabaseDescriptor, error) {
+	if p.session.tempDatabase == "" {
+		prefix := p.session.tempDatabasePrefix
+		if prefix == "" {
+			prefix = tempDatabasePrefix
+		}
+		p.session.tempDatabase = fmt.Sprintf("%s%x", prefix, uuid.MakeV4().GetBytes()[:8])
+	}
+	dbDesc, err := p.getDatabaseDesc(p.session.tempDatabase)
+	if err != nil || dbDesc != nil {
+		return dbDesc, err
+	}
+	desc := makeDatabaseDesc(&parser.CreateDatabase{Name: parser.Name(p.session.tempDatabase)})
+	desc.Privileges.Grant(p.session.User, privilege.List{privilege.ALL})
+	if _, err := p.createDescriptor(databaseKey{desc.Name}, &desc, false); err != nil {
+		return nil, err
+	}
+	return &desc, nil
+}
+
+// createTempTable qualifies the name of a table created by CREATE TEMPORARY
+// TABLE with the session's temporary database, and returns its descriptor.
+func (p *planner) createTempTable(n *parser.CreateTable) (*sqlbase.DatabaseDescriptor, error) {
+	if len(n.Table.Indirect) > 0 {
+		return nil, errors.Errorf("cannot create temporary table %q in a database", n.Table)
+	}
+	dbDesc, err := p.getOrCreateTempDatabase()
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Table.NormalizeTableName(dbDesc.Name); err != nil {
+		return nil, err
+	}
+	if p.session.tempTables == nil {
+		p.session.tempTables = make(map[string]struct{})
+	}
+	p.session.tempTables[sqlbase.NormalizeName(n.Table.Table())] = struct{}{}
+	return dbDesc, nil
+}
+
+// normalizeTableName qualifies an unqualified table name with the session's
+// temporary database if it names one of the session's temporary tables, which
+// hide the tables of the session's database, and with the session's database
+// otherwise.
+func (p *planner) normalizeTableName(qname *parser.QualifiedName) error {
+	if qname != nil && len(qname.Indirect) == 0 && qname.Base != "" {
+		isTemp, err := p.isTempTable(string(qname.Base))
+		if err != nil {
+			return err
+		}
+		if isTemp {
+			return qname.NormalizeTableName(p.session.tempDatabase)
+		}
+	}
+	return qname.NormalizeTableName(p.session.Database)
+}
+
+// isTempTable returns whether a temporary table of the session has the given
+// name. The tables the session created are looked up again, as they may have
+// been dropped since, or created in a transaction which was rolled back.
+func (p *planner) isTempTable(name string) (bool, error) {
+	if _, ok := p.session.tempTables[sqlbase.NormalizeName(name)]; !ok {
+		return false, nil
+	}
+	dbDesc, err := p.getDatabaseDesc(p.session.tempDatabase)
+	if err != nil || dbDesc == nil {
+		return false, err
+	}
+	gr, err := p.txn.Get(tableKey{parentID: dbDesc.ID, name: name}.Key())
+	if err != nil {
+		return false, err
+	}
+	return gr.Exists(), nil
+}
+
+// dropTempDatabase drops the database holding the temporary tables of the
+// session, if it created one. As for DROP DATABASE, the data of the tables is
+// deleted asynchronously by the schema changers.
+func (s *Session) dropTempDatabase() error {
+	if s.tempDatabase == "" || s.planner.execCtx == nil {
+		return nil
+	}
+	return dropDatabase(s.planner.execCtx.DB, s.planner.leaseMgr, s.User, s.tempDatabase,
+		s.planner.systemConfig, s.planner.databaseCache)
+}
+
+// DropOrphanedTempDatabases drops the temporary databases of the sessions of
+// the earlier processes of the node, which the sessions could not drop if the
+// node crashed. It must be called once the node ID is set.
+func (e *Executor) DropOrphanedTempDatabases() error {
+	nodePrefix := fmt.Sprintf("%s%d_", tempDatabasePrefix, e.nodeID)
+	var names []string
+	if err := e.ctx.DB.Txn(func(txn *client.Txn) error {
+		prefix := sqlbase.MakeNameMetadataKey(keys.RootNamespaceID, "")
+		sr, err := txn.Scan(prefix, prefix.PrefixEnd(), 0)
+		if err != nil {
+			return err
+		}
+		names = names[:0]
+		for _, row := range sr {
+			_, name, err := encoding.DecodeUnsafeStringAscending(
+				bytes.TrimPrefix(row.Key, prefix), nil)
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(name, nodePrefix) && !strings.HasPrefix(name, e.tempDatabasePrefix) {
+				names = append(names, name)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	cfg, cache := e.getSystemConfig()
+	for _, name := range names {
+		if err := dropDatabase(e.ctx.DB, e.ctx.LeaseManager, security.RootUser, name, cfg, cache); err != nil {
+			return errors.Wrapf(err, "unable to drop temporary database %s", name)
+		}
+		log.Infof("dropped orphaned temporary database %s", name)
+	}
+	return nil
+}
+
+// dropDatabase drops a database, if it exists, in a transaction of its own.
+func dropDatabase(
+	db *client.DB,
+	leaseMgr *LeaseManager,
+	user, name string,
+	cfg config.SystemConfig,
+	cache *databaseCache,
+) error {
+	return db.Txn(func(txn *client.Txn) error {
+		p := makeInternalPlanner(txn, user)
+		p.leaseMgr = leaseMgr
+		p.systemConfig = cfg
+		p.databaseCache = cache
+		defer p.releaseLeases()
+
+		plan, err := p.makePlan(&parser.DropDatabase{Name: parser.Name(name), IfExists: true}, false)
+		if err != nil {
+			return err
+		}
+		if err := plan.Start(); err != nil {
+			return err
+		}
+		_, err = countRowsAffected(p, plan)
+		return err
+	})
+}
@@ -0,0 +1,476 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/privilege"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// maxTriggerDepth is the number of levels triggers can be nested, as the
+// statements of a trigger can fire other triggers.
+const maxTriggerDepth = 16
+
+// maxQueuedTriggerRows is the number of rows a statement can write to a table
+// with AFTER triggers, as the values of the rows are held in memory until the
+// statement has written all of them.
+var maxQueuedTriggerRows = 10000
+
+// CreateTrigger adds a row-level trigger to a table. The statements of the
+// trigger are executed in the transaction of the statements it fires on, as
+// the user executing them. The last statement of a trigger fired before the
+// rows inserted or updated are written can be a SELECT, whose columns give
+// new values to the columns of the row with the same names.
+// Privileges: CREATE on table.
+//   Notes: postgres requires the TRIGGER privilege, and the trigger executes
+//          a function instead of statements.
+func (p *planner) CreateTrigger(n *parser.CreateTrigger) (planNode, error) {
+	tableDesc, err := p.getTableDescToAlter(n.Table)
+	if err != nil {
+		return nil, err
+	}
+	if i := findTrigger(tableDesc, string(n.Name)); i >= 0 {
+		return nil, fmt.Errorf("trigger %q for table %q already exists", n.Name, tableDesc.Name)
+	}
+
+	stmts, err := p.parser.Parse(n.Body, parser.Traditional)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) == 0 {
+		return nil, errors.New("trigger has no statements")
+	}
+	for i, stmt := range stmts {
+		switch stmt.(type) {
+		case *parser.Insert, *parser.Update, *parser.Delete:
+		case *parser.Select:
+			if !n.Before || n.Events&parser.TriggerDelete != 0 || i != len(stmts)-1 {
+				return nil, fmt.Errorf(
+					"only the last statement of a BEFORE INSERT or UPDATE trigger can be a SELECT: %s", stmt)
+			}
+		default:
+			return nil, fmt.Errorf("statement not supported in triggers: %s", stmt)
+		}
+	}
+
+	tableDesc.Triggers = append(tableDesc.Triggers, sqlbase.TableDescriptor_Trigger{
+		Name:     string(n.Name),
+		Before:   n.Before,
+		OnInsert: n.Events&parser.TriggerInsert != 0,
+		OnUpdate: n.Events&parser.TriggerUpdate != 0,
+		OnDelete: n.Events&parser.TriggerDelete != 0,
+		Body:     n.Body,
+	})
+	if err := p.writeAlteredTableDesc(tableDesc); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// DropTrigger removes a trigger from a table.
+// Privileges: CREATE on table.
+//   Notes: postgres requires ownership of the table.
+func (p *planner) DropTrigger(n *parser.DropTrigger) (planNode, error) {
+	tableDesc, err := p.getTableDescToAlter(n.Table)
+	if err != nil {
+		return nil, err
+	}
+	i := findTrigger(tableDesc, string(n.Name))
+	if i < 0 {
+		if n.IfExists {
+			return &emptyNode{}, nil
+		}
+		return nil, fmt.Errorf("trigger %q for table %q does not exist", n.Name, tableDesc.Name)
+	}
+
+	tableDesc.Triggers = append(tableDesc.Triggers[:i], tableDesc.Triggers[i+1:]...)
+	if err := p.writeAlteredTableDesc(tableDesc); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// ShowTriggers returns the triggers of a table.
+// Privileges: None.
+//   Notes: postgres does not have a SHOW TRIGGERS statement.
+//          mysql requires the TRIGGER privilege.
+func (p *planner) ShowTriggers(n *parser.ShowTriggers) (planNode, error) {
+	if err := p.normalizeTableName(n.Table); err != nil {
+		return nil, err
+	}
+	desc, err := p.mustGetTableDesc(n.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &valuesNode{
+		columns: []ResultColumn{
+			{Name: "Name", Typ: parser.TypeString},
+			{Name: "Timing", Typ: parser.TypeString},
+			{Name: "Events", Typ: parser.TypeString},
+			{Name: "Body", Typ: parser.TypeString},
+		},
+	}
+	for _, trigger := range desc.Triggers {
+		timing := "AFTER"
+		if trigger.Before {
+			timing = "BEFORE"
+		}
+		var events bytes.Buffer
+		triggerEvents(trigger).Format(&events, parser.FmtSimple)
+		v.rows = append(v.rows, []parser.Datum{
+			parser.NewDString(trigger.Name),
+			parser.NewDString(timing),
+			parser.NewDString(events.String()),
+			parser.NewDString(trigger.Body),
+		})
+	}
+	return v, nil
+}
+
+// getTableDescToAlter returns the descriptor of a table whose triggers or
+// policies are created or dropped, which requires the CREATE privilege.
+func (p *planner) getTableDescToAlter(
+	table *parser.QualifiedName,
+) (*sqlbase.TableDescriptor, error) {
+	if err := p.normalizeTableName(table); err != nil {
+		return nil, err
+	}
+	tableDesc, err := p.mustGetTableDesc(table)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPrivilege(tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return tableDesc, nil
+}
+
+// writeAlteredTableDesc writes the descriptor of a table whose triggers or
+// policies changed.
+func (p *planner) writeAlteredTableDesc(tableDesc *sqlbase.TableDescriptor) error {
+	if err := tableDesc.SetUpVersion(); err != nil {
+		return err
+	}
+	if err := p.writeTableDesc(tableDesc); err != nil {
+		return err
+	}
+	p.notifySchemaChange(tableDesc.ID, sqlbase.InvalidMutationID)
+	return nil
+}
+
+// findTrigger returns the index of the trigger of a table with the given
+// name, or -1 if there is none.
+func findTrigger(tableDesc *sqlbase.TableDescriptor, name string) int {
+	normName := sqlbase.NormalizeName(name)
+	for i, trigger := range tableDesc.Triggers {
+		if sqlbase.NormalizeName(trigger.Name) == normName {
+			return i
+		}
+	}
+	return -1
+}
+
+// triggerEvents returns the statements a trigger fires on.
+func triggerEvents(trigger sqlbase.TableDescriptor_Trigger) parser.TriggerEvent {
+	var events parser.TriggerEvent
+	if trigger.OnInsert {
+		events |= parser.TriggerInsert
+	}
+	if trigger.OnUpdate {
+		events |= parser.TriggerUpdate
+	}
+	if trigger.OnDelete {
+		events |= parser.TriggerDelete
+	}
+	return events
+}
+
+// triggerHelper executes the triggers of a table fired by the rows written by
+// a statement. The triggers fired after the rows are written are queued, and
+// executed once the statement has written all its rows so that their
+// statements see them.
+type triggerHelper struct {
+	p        *planner
+	database string
+	cols     []sqlbase.ColumnDescriptor
+	before   []sqlbase.TableDescriptor_Trigger
+	after    []sqlbase.TableDescriptor_Trigger
+
+	// queued holds the old and new values of the rows for which the after
+	// triggers are to be fired.
+	queued [][2]map[string]parser.Datum
+}
+
+func (t *triggerHelper) init(
+	p *planner, tableDesc *sqlbase.TableDescriptor, event parser.TriggerEvent,
+) error {
+	for _, trigger := range tableDesc.Triggers {
+		if triggerEvents(trigger)&event == 0 {
+			continue
+		}
+		if trigger.Before {
+			t.before = append(t.before, trigger)
+		} else {
+			t.after = append(t.after, trigger)
+		}
+	}
+	t.p = p
+	t.cols = tableDesc.Columns
+	if !t.active() {
+		return nil
+	}
+	// The unqualified table names in the statements of the triggers refer to
+	// the tables of the database of the table, whichever the session's
+	// database is.
+	dbDesc, err := getDatabaseDescFromID(p.txn, tableDesc.ParentID)
+	if err != nil {
+		return err
+	}
+	t.database = dbDesc.Name
+	return nil
+}
+
+// active returns whether there are triggers to fire.
+func (t *triggerHelper) active() bool {
+	return len(t.before) > 0 || len(t.after) > 0
+}
+
+// firesAfter returns whether there are triggers to fire after the rows are
+// written. The statement can't commit its transaction with its last batch of
+// writes then.
+func (t *triggerHelper) firesAfter() bool {
+	return len(t.after) > 0
+}
+
+// rowValues returns the values of the columns of a row by name, or nil if
+// there are no triggers to fire. The columns which are not part of the row
+// have the values in prev, or are NULL if prev is nil.
+func (t *triggerHelper) rowValues(
+	colIdx map[sqlbase.ColumnID]int, row parser.DTuple, prev map[string]parser.Datum,
+) map[string]parser.Datum {
+	if !t.active() {
+		return nil
+	}
+	vals := make(map[string]parser.Datum, len(t.cols))
+	for _, col := range t.cols {
+		name := sqlbase.NormalizeName(col.Name)
+		if i, ok := colIdx[col.ID]; ok {
+			vals[name] = row[i]
+		} else if prev != nil {
+			vals[name] = prev[name]
+		} else {
+			vals[name] = parser.DNull
+		}
+	}
+	return vals
+}
+
+// fireBefore executes the triggers fired before a row is written. The old
+// values are nil for inserted rows, and the new ones for deleted rows. The
+// new values set by the triggers replace the ones in newVals, so that the
+// triggers fired next see them, and are returned by column name.
+func (t *triggerHelper) fireBefore(
+	oldVals, newVals map[string]parser.Datum,
+) (map[string]parser.Datum, error) {
+	var set map[string]parser.Datum
+	for _, trigger := range t.before {
+		triggerSet, err := t.p.execTrigger(t.database, trigger, oldVals, newVals)
+		if err != nil {
+			return nil, errors.Wrapf(err, "trigger %q", trigger.Name)
+		}
+		for name, d := range triggerSet {
+			if _, ok := newVals[name]; !ok {
+				return nil, fmt.Errorf("trigger %q: column %q does not exist", trigger.Name, name)
+			}
+			newVals[name] = d
+			if set == nil {
+				set = make(map[string]parser.Datum)
+			}
+			set[name] = d
+		}
+	}
+	return set, nil
+}
+
+// setRow replaces the values of a row with the ones set by the triggers fired
+// before it is written. The columns set must be written by the statement.
+func (t *triggerHelper) setRow(
+	colIdx map[sqlbase.ColumnID]int, row parser.DTuple, set map[string]parser.Datum,
+) error {
+	for _, col := range t.cols {
+		d, ok := set[sqlbase.NormalizeName(col.Name)]
+		if !ok {
+			continue
+		}
+		i, ok := colIdx[col.ID]
+		if !ok {
+			return fmt.Errorf("column %q set by a trigger is not written by the statement", col.Name)
+		}
+		if err := sqlbase.CheckColumnType(col, d, nil); err != nil {
+			return err
+		}
+		row[i] = d
+	}
+	return nil
+}
+
+// queueAfter queues the execution of the triggers fired after a row is
+// written. It fails once more than maxQueuedTriggerRows rows are queued.
+func (t *triggerHelper) queueAfter(oldVals, newVals map[string]parser.Datum) error {
+	if len(t.after) == 0 {
+		return nil
+	}
+	if len(t.queued) >= maxQueuedTriggerRows {
+		return fmt.Errorf("statements cannot write more than %d rows to a table with AFTER triggers",
+			maxQueuedTriggerRows)
+	}
+	t.queued = append(t.queued, [2]map[string]parser.Datum{oldVals, newVals})
+	return nil
+}
+
+// fireAfter executes the triggers fired after the rows are written, once the
+// statement has flushed its writes.
+func (t *triggerHelper) fireAfter() error {
+	queued := t.queued
+	t.queued = nil
+	for _, vals := range queued {
+		for _, trigger := range t.after {
+			if _, err := t.p.execTrigger(t.database, trigger, vals[0], vals[1]); err != nil {
+				return errors.Wrapf(err, "trigger %q", trigger.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// execTrigger executes the statements of a trigger for a row, with the
+// given database as the current database. If the last statement is a SELECT,
+// the values of the row it returns are returned by column name.
+func (p *planner) execTrigger(
+	database string,
+	trigger sqlbase.TableDescriptor_Trigger,
+	oldVals, newVals map[string]parser.Datum,
+) (map[string]parser.Datum, error) {
+	if p.triggerDepth >= maxTriggerDepth {
+		return nil, fmt.Errorf("triggers cannot be nested more than %d levels deep", maxTriggerDepth)
+	}
+	ip := makeInternalPlanner(p.txn, p.session.User)
+	ip.leaseMgr = p.leaseMgr
+	ip.session.Database = database
+	ip.systemConfig = p.systemConfig
+	ip.databaseCache = p.databaseCache
+	ip.evalCtx.NodeID = p.evalCtx.NodeID
+	ip.triggerDepth = p.triggerDepth + 1
+
+	// The statements are parsed for each row, as planning them modifies them.
+	stmts, err := ip.parser.Parse(trigger.Body, parser.Traditional)
+	if err != nil {
+		return nil, err
+	}
+	v := triggerRowVisitor{oldVals: oldVals, newVals: newVals}
+	for _, stmt := range stmts {
+		stmt, _ = parser.WalkStmt(&v, stmt)
+		if v.err != nil {
+			return nil, v.err
+		}
+		plan, err := ip.makePlan(stmt, false)
+		if err != nil {
+			return nil, err
+		}
+		if err := plan.Start(); err != nil {
+			return nil, err
+		}
+		if _, ok := stmt.(*parser.Select); ok {
+			return triggerRow(plan)
+		}
+		if _, err := countRowsAffected(ip, plan); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// triggerRow returns the values of the single row returned by the SELECT of
+// a trigger by column name.
+func triggerRow(plan planNode) (map[string]parser.Datum, error) {
+	next, err := plan.Next()
+	if err != nil {
+		return nil, err
+	}
+	if !next {
+		return nil, errors.New("the SELECT of the trigger returned no row")
+	}
+	vals := make(map[string]parser.Datum)
+	for i, col := range plan.Columns() {
+		vals[sqlbase.NormalizeName(col.Name)] = plan.Values()[i]
+	}
+	if next, err := plan.Next(); err != nil {
+		return nil, err
+	} else if next {
+		return nil, errors.New("the SELECT of the trigger returned more than one row")
+	}
+	return vals, nil
+}
+
+// triggerRowVisitor replaces the references to NEW.<column> and OLD.<column>
+// in the statements of a trigger with the values of the row it is fired for.
+type triggerRowVisitor struct {
+	oldVals, newVals map[string]parser.Datum
+	err              error
+}
+
+var _ parser.Visitor = &triggerRowVisitor{}
+
+func (v *triggerRowVisitor) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	qname, ok := expr.(*parser.QualifiedName)
+	if !ok || len(qname.Indirect) != 1 {
+		return true, expr
+	}
+	col, ok := qname.Indirect[0].(parser.NameIndirection)
+	if !ok {
+		return true, expr
+	}
+	var vals map[string]parser.Datum
+	var row string
+	switch sqlbase.NormalizeName(string(qname.Base)) {
+	case "new":
+		vals, row = v.newVals, "NEW"
+	case "old":
+		vals, row = v.oldVals, "OLD"
+	default:
+		return true, expr
+	}
+	if vals == nil {
+		v.err = fmt.Errorf("%s is not available for this statement", row)
+		return false, expr
+	}
+	d, ok := vals[sqlbase.NormalizeName(string(col))]
+	if !ok {
+		v.err = fmt.Errorf("column %q does not exist", col)
+		return false, expr
+	}
+	return false, d
+}
+
+func (*triggerRowVisitor) VisitPost(expr parser.Expr) parser.Expr { return expr }
@@ -0,0 +1,61 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/base"
+	"github.com/cockroachdb/cockroach/testutils"
+	"github.com/cockroachdb/cockroach/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestTriggerQueueLimit tests that a statement can't write more than
+// maxQueuedTriggerRows rows to a table with AFTER triggers.
+func TestTriggerQueueLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(n int) { maxQueuedTriggerRows = n }(maxQueuedTriggerRows)
+	maxQueuedTriggerRows = 2
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop()
+
+	if _, err := db.Exec(`
+CREATE DATABASE d;
+CREATE TABLE d.t (k INT PRIMARY KEY);
+CREATE TABLE d.log (k INT);
+CREATE TRIGGER log_rows AFTER INSERT ON d.t FOR EACH ROW EXECUTE 'INSERT INTO log VALUES (NEW.k)';
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO d.t VALUES (1), (2)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO d.t VALUES (3), (4), (5)`); !testutils.IsError(err,
+		"statements cannot write more than 2 rows to a table with AFTER triggers") {
+		t.Fatalf("expected the AFTER triggers queue to be full, got %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM d.log`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected the triggers to fire for 2 rows, but they fired for %d", count)
+	}
+}
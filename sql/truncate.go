@@ -17,6 +17,8 @@
 package sql
 
 import (
+	"fmt"
+
 	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/keys"
 	"github.com/cockroachdb/cockroach/roachpb"
@@ -24,42 +26,269 @@ import (
 	"github.com/cockroachdb/cockroach/sql/privilege"
 	"github.com/cockroachdb/cockroach/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/protoutil"
 )
 
-// Truncate deletes all rows from a table.
+// Truncate deletes all rows from tables. Each table is replaced by an empty
+// copy with a new ID, and the old table is dropped: its data is deleted
+// asynchronously by the schema changers, as for DROP TABLE, instead of in the
+// transaction. The tables interleaved into the truncated ones are truncated
+// along with them, as are, with CASCADE, the tables referencing them through
+// foreign keys. Otherwise, no row of these tables may reference a truncated
+// row.
 // Privileges: DROP on table.
 //   Notes: postgres requires TRUNCATE.
 //          mysql requires DROP (for mysql >= 5.1.16, DELETE before that).
 func (p *planner) Truncate(n *parser.Truncate) (planNode, error) {
-	for _, tableQualifiedName := range n.Tables {
-		tableDesc, err := p.getTableLease(tableQualifiedName)
+	td := make([]*sqlbase.TableDescriptor, 0, len(n.Tables))
+	truncated := make(map[sqlbase.ID]struct{}, len(n.Tables))
+	for _, name := range n.Tables {
+		tableDesc, err := p.mustGetTableDesc(name)
 		if err != nil {
 			return nil, err
 		}
+		if _, ok := truncated[tableDesc.ID]; ok {
+			continue
+		}
+		truncated[tableDesc.ID] = struct{}{}
+		td = append(td, tableDesc)
+	}
 
-		if err := p.checkPrivilege(tableDesc, privilege.DROP); err != nil {
+	// Add the tables interleaved into the truncated ones, and those
+	// referencing them, until no table is added.
+	for {
+		var deps dependents
+		expanded, interleaved, err := p.addInterleavedDrops(td, n.DropBehavior, &deps)
+		if err != nil {
 			return nil, err
 		}
-
-		if err := truncateTable(tableDesc, p.txn); err != nil {
+		if err := deps.err(); err != nil {
 			return nil, err
 		}
+		for _, desc := range expanded[len(td):] {
+			truncated[desc.ID] = struct{}{}
+		}
+		// The data of the secondary indexes interleaved into the truncated
+		// tables is removed with theirs, so their tables are truncated too.
+		for _, ref := range interleaved {
+			if _, ok := truncated[ref.Table]; ok {
+				continue
+			}
+			table, err := getTableDescFromID(p.txn, ref.Table)
+			if err != nil {
+				return nil, err
+			}
+			truncated[table.ID] = struct{}{}
+			expanded = append(expanded, table)
+		}
+		if n.DropBehavior == parser.DropCascade {
+			for _, desc := range expanded {
+				for _, idx := range desc.AllNonDropIndexes() {
+					for _, ref := range idx.ReferencedBy {
+						if _, ok := truncated[ref.Table]; ok {
+							continue
+						}
+						table, err := p.canRemoveFK(desc.Name, ref, n.DropBehavior)
+						if err != nil {
+							return nil, err
+						}
+						truncated[table.ID] = struct{}{}
+						expanded = append(expanded, table)
+					}
+				}
+			}
+		}
+		if len(expanded) == len(td) {
+			break
+		}
+		td = expanded
+	}
 
-		fkTables := TablesNeededForFKs(*tableDesc, CheckDeletes)
-		if err := p.fillFKTableMap(fkTables); err != nil {
+	// The tables being altered can't be replaced, as their mutations are
+	// processed by schema changers referring to their IDs. Their rows are
+	// deleted in the transaction instead.
+	var replaced []*sqlbase.TableDescriptor
+	for _, tableDesc := range td {
+		if err := p.checkPrivilege(tableDesc, privilege.DROP); err != nil {
 			return nil, err
 		}
-		colMap := colIDtoRowIndexFromCols(tableDesc.Columns)
-		if helper, err := makeFKDeleteHelper(p.txn, *tableDesc, fkTables, colMap); err != nil {
+		if tableDesc.IsMaterializedView() {
+			return nil, fmt.Errorf("cannot truncate materialized view %q", tableDesc.Name)
+		}
+		if err := p.checkTruncateFKs(*tableDesc, truncated); err != nil {
 			return nil, err
-		} else if err = helper.checkAll(nil); err != nil {
+		}
+		if len(tableDesc.Mutations) == 0 {
+			replaced = append(replaced, tableDesc)
+			continue
+		}
+		if tableDesc.IsInterleaved() {
+			return nil, fmt.Errorf("cannot truncate interleaved table %q while it is being altered",
+				tableDesc.Name)
+		}
+		if err := truncateTable(tableDesc, p.txn); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := p.replaceTables(replaced); err != nil {
+		return nil, err
+	}
 	return &emptyNode{}, nil
 }
 
+// checkTruncateFKs checks that no row of the tables which aren't truncated
+// references a row of a truncated table.
+func (p *planner) checkTruncateFKs(
+	tableDesc sqlbase.TableDescriptor, truncated map[sqlbase.ID]struct{},
+) error {
+	// Only the references from the tables which aren't truncated are
+	// checked. The descriptor is a copy, whose indexes can be modified.
+	outside := func(refs []*sqlbase.ForeignKeyReference) []*sqlbase.ForeignKeyReference {
+		var ret []*sqlbase.ForeignKeyReference
+		for _, ref := range refs {
+			if _, ok := truncated[ref.Table]; !ok {
+				ret = append(ret, ref)
+			}
+		}
+		return ret
+	}
+	tableDesc.PrimaryIndex.ReferencedBy = outside(tableDesc.PrimaryIndex.ReferencedBy)
+	tableDesc.Indexes = append([]sqlbase.IndexDescriptor(nil), tableDesc.Indexes...)
+	for i := range tableDesc.Indexes {
+		tableDesc.Indexes[i].ReferencedBy = outside(tableDesc.Indexes[i].ReferencedBy)
+	}
+
+	fkTables := TablesNeededForFKs(tableDesc, CheckDeletes)
+	if err := p.fillFKTableMap(fkTables); err != nil {
+		return err
+	}
+	colMap := colIDtoRowIndexFromCols(tableDesc.Columns)
+	helper, err := makeFKDeleteHelper(p.txn, tableDesc, fkTables, colMap)
+	if err != nil {
+		return err
+	}
+	if err := helper.checkAll(nil); err != nil {
+		return err
+	}
+	return helper.runChecks()
+}
+
+// replaceTables replaces each of the given tables by an empty copy with a new
+// ID, which takes over its name, comments, zone config, and the foreign keys
+// and interleaves between it and other tables. The old tables are dropped.
+func (p *planner) replaceTables(td []*sqlbase.TableDescriptor) error {
+	newIDs := make(map[sqlbase.ID]sqlbase.ID, len(td))
+	for _, tableDesc := range td {
+		ir, err := p.txn.Inc(keys.DescIDGenerator, 1)
+		if err != nil {
+			return err
+		}
+		newIDs[tableDesc.ID] = sqlbase.ID(ir.ValueInt() - 1)
+	}
+
+	// The tables which aren't replaced but reference replaced ones.
+	others := make(map[sqlbase.ID]struct{})
+	for _, tableDesc := range td {
+		newDesc := protoutil.Clone(tableDesc).(*sqlbase.TableDescriptor)
+		newDesc.ID = newIDs[tableDesc.ID]
+		newDesc.Version = 1
+		newDesc.UpVersion = false
+		for _, id := range remapTableIDs(newDesc, newIDs) {
+			if _, ok := newIDs[id]; !ok {
+				others[id] = struct{}{}
+			}
+		}
+		if err := newDesc.Validate(); err != nil {
+			return err
+		}
+		if err := p.writeTableDesc(newDesc); err != nil {
+			return err
+		}
+		// The sequences backing the SERIAL columns are moved to the new table.
+		if err := p.writeSequenceOwners(newDesc); err != nil {
+			return err
+		}
+
+		zoneKey, nameKey, _ := getKeysForTableDescriptor(tableDesc)
+		b := &client.Batch{}
+		b.Put(nameKey, newDesc.ID)
+		gr, err := p.txn.Get(zoneKey)
+		if err != nil {
+			return err
+		}
+		if gr.Exists() {
+			b.Put(sqlbase.MakeZoneKey(newDesc.ID), gr.ValueBytes())
+		}
+		if err := p.txn.Run(b); err != nil {
+			return err
+		}
+		if err := p.moveTableComments(tableDesc.ID, newDesc.ID); err != nil {
+			return err
+		}
+
+		// The sequences backing the SERIAL columns are used by the new table,
+		// and aren't deleted with the old one.
+		for i := range tableDesc.Columns {
+			if _, ok := serialSequenceID(tableDesc.Columns[i]); ok {
+				tableDesc.Columns[i].DefaultExpr = nil
+			}
+		}
+		if err := tableDesc.SetUpVersion(); err != nil {
+			return err
+		}
+		tableDesc.State = sqlbase.TableDescriptor_DROP
+		if err := p.writeTableDesc(tableDesc); err != nil {
+			return err
+		}
+		p.notifySchemaChange(tableDesc.ID, sqlbase.InvalidMutationID)
+	}
+
+	for id := range others {
+		tableDesc, err := getTableDescFromID(p.txn, id)
+		if err != nil {
+			return err
+		}
+		remapTableIDs(tableDesc, newIDs)
+		if err := p.saveNonmutationAndNotify(tableDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remapTableIDs replaces the IDs of the tables referenced by the foreign keys
+// and interleaves of a table's indexes according to newIDs, and returns the
+// IDs of all these tables before replacement.
+func remapTableIDs(tableDesc *sqlbase.TableDescriptor, newIDs map[sqlbase.ID]sqlbase.ID) []sqlbase.ID {
+	var referenced []sqlbase.ID
+	remap := func(id *sqlbase.ID) {
+		referenced = append(referenced, *id)
+		if newID, ok := newIDs[*id]; ok {
+			*id = newID
+		}
+	}
+	indexes := []*sqlbase.IndexDescriptor{&tableDesc.PrimaryIndex}
+	for i := range tableDesc.Indexes {
+		indexes = append(indexes, &tableDesc.Indexes[i])
+	}
+	for _, idx := range indexes {
+		if idx.ForeignKey != nil {
+			remap(&idx.ForeignKey.Table)
+		}
+		for _, ref := range idx.ReferencedBy {
+			remap(&ref.Table)
+		}
+		for i := range idx.Interleave.Ancestors {
+			remap(&idx.Interleave.Ancestors[i].TableID)
+		}
+		for i := range idx.InterleavedBy {
+			remap(&idx.InterleavedBy[i].Table)
+		}
+	}
+	return referenced
+}
+
 // truncateTable truncates the data of a table.
 // It deletes a range of data for the table, which includes the PK and all
 // indexes.
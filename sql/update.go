@@ -49,6 +49,10 @@ func (p *planner) makeEditNode(t parser.TableExpr, autoCommit bool, priv privile
 		return editNodeBase{}, err
 	}
 
+	if tableDesc.IsMaterializedView() {
+		return editNodeBase{}, errors.Errorf("cannot modify materialized view %q", tableDesc.Name)
+	}
+
 	return editNodeBase{
 		p:          p,
 		tableDesc:  tableDesc,
@@ -112,6 +116,8 @@ type updateNode struct {
 	updateColsIdx map[sqlbase.ColumnID]int // index in updateCols slice
 	tw            tableUpdater
 	checkHelper   checkHelper
+	computed      computedHelper
+	triggers      triggerHelper
 
 	run struct {
 		// The following fields are populated during Start().
@@ -127,6 +133,10 @@ type updateNode struct {
 func (p *planner) Update(n *parser.Update, desiredTypes []parser.Datum, autoCommit bool) (planNode, error) {
 	tracing.AnnotateTrace()
 
+	if n.Where == nil && p.session.SafeUpdates {
+		return nil, errUnsafeUpdate("UPDATE without WHERE clause")
+	}
+
 	en, err := p.makeEditNode(n.Table, autoCommit, privilege.UPDATE)
 	if err != nil {
 		return nil, err
@@ -158,13 +168,34 @@ func (p *planner) Update(n *parser.Update, desiredTypes []parser.Datum, autoComm
 		return nil, err
 	}
 
+	// The triggers are given the values of all the columns.
+	var triggers triggerHelper
+	if err := triggers.init(p, en.tableDesc, parser.TriggerUpdate); err != nil {
+		return nil, err
+	}
+
 	var requestedCols []sqlbase.ColumnDescriptor
-	if len(n.Returning) > 0 || len(en.tableDesc.Checks) > 0 {
+	if len(n.Returning) > 0 || len(en.tableDesc.Checks) > 0 || triggers.active() {
 		// TODO(dan): This could be made tighter, just the rows needed for RETURNING
 		// exprs.
 		requestedCols = en.tableDesc.Columns
 	}
 
+	// If the columns a computed column is computed from are updated, the
+	// computed columns are updated as well, which requires all the columns they
+	// are computed from.
+	var computed computedHelper
+	if err := computed.init(p, en.tableDesc, false); err != nil {
+		return nil, err
+	}
+	numExprCols := len(updateCols)
+	if computed.dependsOn(updateCols) {
+		updateCols = append(updateCols, computed.computedCols...)
+		if requestedCols == nil {
+			requestedCols = en.tableDesc.Columns
+		}
+	}
+
 	fkTables := TablesNeededForFKs(*en.tableDesc, CheckUpdates)
 	if err := p.fillFKTableMap(fkTables); err != nil {
 		return nil, err
@@ -173,6 +204,11 @@ func (p *planner) Update(n *parser.Update, desiredTypes []parser.Datum, autoComm
 	if err != nil {
 		return nil, err
 	}
+	if err := ru.fks.inbound.initActions(p, CheckUpdates); err != nil {
+		return nil, err
+	}
+	autoCommit = p.deferFKChecks(ru.fks.inbound.checker, autoCommit)
+	autoCommit = autoCommit && !triggers.firesAfter()
 	tw := tableUpdater{ru: ru, autoCommit: autoCommit}
 
 	tracing.AnnotateTrace()
@@ -210,6 +246,11 @@ func (p *planner) Update(n *parser.Update, desiredTypes []parser.Datum, autoComm
 			i++
 		}
 	}
+	// The values of the computed columns are filled in for each row.
+	for _, col := range updateCols[numExprCols:] {
+		targets = append(targets, parser.SelectExpr{Expr: parser.DNull})
+		desiredTypesFromSelect = append(desiredTypesFromSelect, col.Type.ToDatumType())
+	}
 
 	rows, err := p.SelectClause(&parser.SelectClause{
 		Exprs: targets,
@@ -253,6 +294,8 @@ func (p *planner) Update(n *parser.Update, desiredTypes []parser.Datum, autoComm
 		updateCols:    ru.updateCols,
 		updateColsIdx: updateColsIdx,
 		tw:            tw,
+		computed:      computed,
+		triggers:      triggers,
 	}
 	if err := un.checkHelper.init(p, en.tableDesc); err != nil {
 		return nil, err
@@ -286,6 +329,9 @@ func (u *updateNode) Next() (bool, error) {
 			// We're done. Finish the batch.
 			err = u.tw.finalize()
 		}
+		if err == nil {
+			err = u.triggers.fireAfter()
+		}
 		return false, err
 	}
 
@@ -302,6 +348,23 @@ func (u *updateNode) Next() (bool, error) {
 	updateValues := oldValues[len(u.tw.ru.fetchCols):]
 	oldValues = oldValues[:len(u.tw.ru.fetchCols)]
 
+	// The triggers fired before the row is written can change its updated
+	// values, which are checked below.
+	oldVals := u.triggers.rowValues(u.tw.ru.fetchColIDtoRowIndex, oldValues, nil)
+	set, err := u.triggers.fireBefore(oldVals, u.triggers.rowValues(u.updateColsIdx, updateValues, oldVals))
+	if err != nil {
+		return false, err
+	}
+	if err := u.triggers.setRow(u.updateColsIdx, updateValues, set); err != nil {
+		return false, err
+	}
+
+	u.computed.loadRow(u.tw.ru.fetchColIDtoRowIndex, oldValues, false)
+	u.computed.loadRow(u.updateColsIdx, updateValues, true)
+	if err := u.computed.fill(&u.p.evalCtx, u.updateColsIdx, updateValues); err != nil {
+		return false, err
+	}
+
 	u.checkHelper.loadRow(u.tw.ru.fetchColIDtoRowIndex, oldValues, false)
 	u.checkHelper.loadRow(u.updateColsIdx, updateValues, true)
 	if err := u.checkHelper.check(&u.p.evalCtx); err != nil {
@@ -327,6 +390,10 @@ func (u *updateNode) Next() (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	newVals := u.triggers.rowValues(u.updateColsIdx, updateValues, oldVals)
+	if err := u.triggers.queueAfter(oldVals, newVals); err != nil {
+		return false, err
+	}
 
 	resultRow, err := u.rh.cookResultRow(newValues)
 	if err != nil {
@@ -0,0 +1,228 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/security"
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// CreateUser adds a user to system.users. Unless the CREATEDB option is
+// given, the user can't create databases.
+// Privileges: INSERT on system.users.
+//   Notes: postgres requires the CREATEROLE privilege.
+//          mysql requires the CREATE USER privilege.
+func (p *planner) CreateUser(n *parser.CreateUser) (planNode, error) {
+	name, err := checkUserName(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	// A user created without a password has a NULL hashed password.
+	var hashed parser.Datum = parser.DNull
+	if n.Options.Password != nil {
+		h, err := hashUserPassword(*n.Options.Password)
+		if err != nil {
+			return nil, err
+		}
+		hashed = parser.NewDBytes(parser.DBytes(h))
+	}
+	createDB := n.Options.CreateDB != nil && *n.Options.CreateDB
+
+	ip := p.makeUserPlanner()
+	defer ip.releaseLeases()
+	row, err := ip.queryRow(`SELECT 1 FROM system.users WHERE username = $1`, name)
+	if err != nil {
+		return nil, err
+	}
+	if row != nil {
+		if n.IfNotExists {
+			return &emptyNode{}, nil
+		}
+		return nil, fmt.Errorf("user %q already exists", name)
+	}
+	if _, err := ip.exec(
+		`INSERT INTO system.users (username, hashedPassword, createDB) VALUES ($1, $2, $3)`,
+		name, hashed, createDB,
+	); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// AlterUser changes the options of a user.
+// Privileges: UPDATE on system.users.
+//   Notes: postgres requires the CREATEROLE privilege.
+//          mysql requires the CREATE USER privilege.
+func (p *planner) AlterUser(n *parser.AlterUser) (planNode, error) {
+	name, err := checkUserName(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	var sets []string
+	args := []interface{}{name}
+	if n.Options.Password != nil {
+		hashed, err := hashUserPassword(*n.Options.Password)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, hashed)
+		sets = append(sets, fmt.Sprintf("hashedPassword = $%d", len(args)))
+	}
+	if n.Options.CreateDB != nil {
+		args = append(args, *n.Options.CreateDB)
+		sets = append(sets, fmt.Sprintf("createDB = $%d", len(args)))
+	}
+
+	ip := p.makeUserPlanner()
+	defer ip.releaseLeases()
+	count, err := ip.exec(fmt.Sprintf(`UPDATE system.users SET %s WHERE username = $1`,
+		strings.Join(sets, ", ")), args...)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("user %q does not exist", name)
+	}
+	return &emptyNode{}, nil
+}
+
+// DropUser removes users from system.users. The users which have privileges
+// on a database or a table can't be dropped: their privileges must be revoked
+// first.
+// Privileges: DELETE on system.users.
+//   Notes: postgres requires the CREATEROLE privilege.
+//          mysql requires the CREATE USER privilege.
+func (p *planner) DropUser(n *parser.DropUser) (planNode, error) {
+	names := make([]string, len(n.Names))
+	for i, userName := range n.Names {
+		name, err := checkUserName(parser.Name(userName))
+		if err != nil {
+			return nil, err
+		}
+		names[i] = name
+	}
+
+	ip := p.makeUserPlanner()
+	defer ip.releaseLeases()
+	for _, name := range names {
+		count, err := ip.exec(`DELETE FROM system.users WHERE username = $1`, name)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			if !n.IfExists {
+				return nil, fmt.Errorf("user %q does not exist", name)
+			}
+			continue
+		}
+		if err := p.checkUserHasNoPrivileges(name); err != nil {
+			return nil, err
+		}
+	}
+	return &emptyNode{}, nil
+}
+
+// checkUserHasNoPrivileges returns an error if a user has privileges on a
+// database or a table. The privileges are not revoked by DROP USER, so that a
+// user created later with the same name doesn't get them.
+func (p *planner) checkUserHasNoPrivileges(user string) error {
+	prefix := roachpb.Key(keys.MakeTablePrefix(uint32(sqlbase.DescriptorTable.ID)))
+	kvs, err := p.txn.Scan(prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return err
+	}
+	for _, kv := range kvs {
+		desc := &sqlbase.Descriptor{}
+		if err := kv.ValueProto(desc); err != nil {
+			return err
+		}
+		var d sqlbase.DescriptorProto
+		if table := desc.GetTable(); table != nil {
+			if table.Deleted() {
+				continue
+			}
+			d = table
+		} else if db := desc.GetDatabase(); db != nil {
+			d = db
+		} else {
+			continue
+		}
+		for _, u := range d.GetPrivileges().Users {
+			if u.User == user && u.Privileges != 0 {
+				return fmt.Errorf("cannot drop user %q: it has privileges on %s %q",
+					user, d.TypeName(), d.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+// checkCreateDBPrivilege returns an error if the session user can't create
+// databases. Besides root, the users created with the CREATEDB option can.
+func (p *planner) checkCreateDBPrivilege() error {
+	if p.session.User == security.RootUser {
+		return nil
+	}
+	// The session user may not be able to read system.users.
+	ip := makeInternalPlanner(p.txn, security.RootUser)
+	ip.leaseMgr = p.leaseMgr
+	defer ip.releaseLeases()
+	row, err := ip.queryRow(
+		`SELECT createDB FROM system.users WHERE username = $1`, p.session.User)
+	if err != nil {
+		return err
+	}
+	if row == nil || row[0] != parser.DBoolTrue {
+		return errors.Errorf("user %s does not have the CREATEDB option", p.session.User)
+	}
+	return nil
+}
+
+// makeUserPlanner returns the planner used to access system.users. It
+// executes the statements as the session user, so that the privileges on
+// system.users decide who can manage the users.
+func (p *planner) makeUserPlanner() *planner {
+	ip := makeInternalPlanner(p.txn, p.session.User)
+	ip.leaseMgr = p.leaseMgr
+	return ip
+}
+
+// checkUserName returns the name of a user managed by the user statements.
+// The root user is not stored in system.users, and can't be managed.
+func checkUserName(n parser.Name) (string, error) {
+	name := string(n)
+	if name == "" {
+		return "", errors.New("user name cannot be empty")
+	}
+	if name == security.RootUser {
+		return "", fmt.Errorf("user %q cannot be modified", name)
+	}
+	return name, nil
+}
+
+// hashUserPassword hashes the password of a user, which can't be empty.
+func hashUserPassword(password string) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("empty passwords are not permitted")
+	}
+	return security.HashPassword([]byte(password))
+}
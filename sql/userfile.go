@@ -0,0 +1,109 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/pkg/errors"
+)
+
+// The files uploaded with `cockroach userfile upload` are stored in a pair
+// of tables of each user in the UserfileDatabase database: the files table
+// maps the names of the files to their IDs and sizes, and the payload table
+// holds the contents of the files, split in chunks keyed by the file ID and
+// their offset.
+const (
+	// UserfileDatabase is the database holding the tables of the uploaded
+	// files.
+	UserfileDatabase = "userfiles"
+	// UserfileURIScheme is the scheme of the URIs naming uploaded files.
+	UserfileURIScheme = "userfile://"
+)
+
+// maxUserfileReadSize is the size of the largest file crdb_internal.userfile()
+// returns, as the contents are held in memory.
+const maxUserfileReadSize = 64 << 20
+
+// UserfileTables returns the qualified names of the tables storing the
+// metadata and the contents of the files uploaded by the given user.
+func UserfileTables(user string) (files, payload string) {
+	db := parser.Name(UserfileDatabase).String()
+	files = fmt.Sprintf("%s.%s", db, parser.Name(user+"_upload_files"))
+	payload = fmt.Sprintf("%s.%s", db, parser.Name(user+"_upload_payload"))
+	return files, payload
+}
+
+// UserfileName returns the name under which a file is stored, given either
+// a plain name or a userfile:// URI.
+func UserfileName(name string) (string, error) {
+	name = strings.TrimPrefix(name, UserfileURIScheme)
+	name = path.Clean("/" + name)[1:]
+	if name == "" {
+		return "", errors.New("empty file name")
+	}
+	return name, nil
+}
+
+var _ parser.UserfileReader = &planner{}
+
+// ReadUserfile implements the parser.UserfileReader interface. The file is
+// read with the privileges of the session's user.
+func (p *planner) ReadUserfile(uri string) (parser.Datum, error) {
+	if !strings.HasPrefix(uri, UserfileURIScheme) {
+		return nil, errors.Errorf("%q is not a %s URI", uri, UserfileURIScheme)
+	}
+	name, err := UserfileName(uri)
+	if err != nil {
+		return nil, err
+	}
+	files, payload := UserfileTables(p.session.User)
+
+	ip := makeInternalPlanner(p.txn, p.session.User)
+	ip.leaseMgr = p.leaseMgr
+	defer ip.releaseLeases()
+	row, err := ip.queryRow(fmt.Sprintf(`SELECT file_id, file_size FROM %s WHERE filename = $1`, files), name)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, errors.Errorf("file %s does not exist", name)
+	}
+	fileID, size := row[0], int64(*row[1].(*parser.DInt))
+	if size > maxUserfileReadSize {
+		return nil, errors.Errorf("file %s is too large to be read: %d bytes, the maximum is %d",
+			name, size, maxUserfileReadSize)
+	}
+	rows, err := ip.queryRows(fmt.Sprintf(
+		`SELECT byte_offset, payload FROM %s WHERE file_id = $1 ORDER BY byte_offset`, payload), fileID)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for _, row := range rows {
+		if offset := int64(*row[0].(*parser.DInt)); offset != int64(buf.Len()) {
+			return nil, errors.Errorf("file %s is corrupt at offset %d", name, buf.Len())
+		}
+		buf.WriteString(string(*row[1].(*parser.DBytes)))
+	}
+	if int64(buf.Len()) != size {
+		return nil, errors.Errorf("file %s is truncated: expected %d bytes, found %d", name, size, buf.Len())
+	}
+	return parser.NewDBytes(parser.DBytes(buf.String())), nil
+}
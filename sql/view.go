@@ -0,0 +1,110 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/sql/parser"
+	"github.com/cockroachdb/cockroach/sql/privilege"
+	"github.com/cockroachdb/cockroach/sql/sqlbase"
+)
+
+// CreateMaterializedView creates a table holding the results of a query,
+// which are written when the view is created and when it is refreshed.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on the schema.
+func (p *planner) CreateMaterializedView(n *parser.CreateMaterializedView) (planNode, error) {
+	if err := n.Name.NormalizeTableName(p.session.Database); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.mustGetDatabaseDesc(n.Name.Database())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.checkPrivilege(dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	// The query is saved before it is planned, as planning modifies it.
+	query := n.AsSource.String()
+	plan, err := p.planViewQuery(n.AsSource, n.Name.Database())
+	if err != nil {
+		return nil, err
+	}
+	defs, err := makeTableDefsFromQuery(plan, n.ColumnNames, "materialized view", n.Name.Table())
+	if err != nil {
+		return nil, err
+	}
+
+	return &createTableNode{
+		p:          p,
+		n:          &parser.CreateTable{Table: n.Name, Defs: defs},
+		dbDesc:     dbDesc,
+		viewQuery:  query,
+		sourcePlan: plan,
+	}, nil
+}
+
+// RefreshMaterializedView replaces the rows of a materialized view with the
+// current results of its query.
+// Privileges: INSERT on view.
+//   Notes: postgres requires ownership of the view.
+func (p *planner) RefreshMaterializedView(n *parser.RefreshMaterializedView) (planNode, error) {
+	tableDesc, err := p.getTableLease(n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.checkPrivilege(tableDesc, privilege.INSERT); err != nil {
+		return nil, err
+	}
+
+	if !tableDesc.IsMaterializedView() {
+		return nil, fmt.Errorf("%q is not a materialized view", tableDesc.Name)
+	}
+
+	if err := truncateTable(tableDesc, p.txn); err != nil {
+		return nil, err
+	}
+	if err := p.populateView(tableDesc, n.Name.Database()); err != nil {
+		return nil, err
+	}
+	return &emptyNode{}, nil
+}
+
+// planViewQuery plans the query of a materialized view. The names of the
+// tables it reads are resolved in the database of the view.
+func (p *planner) planViewQuery(stmt parser.Statement, database string) (planNode, error) {
+	defer func(database string) { p.session.Database = database }(p.session.Database)
+	p.session.Database = database
+	return p.makePlan(stmt, false)
+}
+
+// populateView writes the results of the query of a materialized view in the
+// given database to its table, which is expected to be empty.
+func (p *planner) populateView(tableDesc *sqlbase.TableDescriptor, database string) error {
+	stmt, err := parser.ParseOneTraditional(tableDesc.ViewQuery)
+	if err != nil {
+		return err
+	}
+	plan, err := p.planViewQuery(stmt, database)
+	if err != nil {
+		return err
+	}
+	return p.insertQueryRows(tableDesc, plan)
+}
@@ -320,6 +320,50 @@ func (a *Allocator) ShouldRebalance(storeID roachpb.StoreID) bool {
 	return a.improve(storeDesc, sl, makeNodeIDSet(storeDesc.Node.NodeID)) != nil
 }
 
+// TransferLeaseTarget returns a replica of the range to which the range lease
+// should be transferred from the store with the given ID, the current lease
+// holder. Only live stores holding the range's other replicas are considered.
+// The returned bool is false if no transfer would improve the balance of
+// leases or load across the stores matching the required attributes.
+func (a Allocator) TransferLeaseTarget(
+	required roachpb.Attributes, existing []roachpb.ReplicaDescriptor, leaseStoreID roachpb.StoreID,
+) (roachpb.ReplicaDescriptor, bool) {
+	if !a.options.AllowRebalance {
+		return roachpb.ReplicaDescriptor{}, false
+	}
+	leaseStoreDesc := a.storePool.getStoreDescriptor(leaseStoreID)
+	if leaseStoreDesc == nil {
+		return roachpb.ReplicaDescriptor{}, false
+	}
+	dead := make(map[roachpb.StoreID]struct{})
+	for _, repl := range a.storePool.deadReplicas(existing) {
+		dead[repl.StoreID] = struct{}{}
+	}
+	var candidates []*roachpb.StoreDescriptor
+	for _, repl := range existing {
+		if repl.StoreID == leaseStoreID {
+			continue
+		}
+		if _, ok := dead[repl.StoreID]; ok {
+			continue
+		}
+		if desc := a.storePool.getStoreDescriptor(repl.StoreID); desc != nil {
+			candidates = append(candidates, desc)
+		}
+	}
+
+	sl, _, _ := a.storePool.getStoreList(required, a.options.Deterministic)
+	rcb := rangeCountBalancer{a.randGen}
+	if target := rcb.improveLease(leaseStoreDesc, candidates, sl); target != nil {
+		for _, repl := range existing {
+			if repl.StoreID == target.StoreID {
+				return repl, true
+			}
+		}
+	}
+	return roachpb.ReplicaDescriptor{}, false
+}
+
 // selectGood attempts to select a store from the supplied store list that it
 // considers to be 'Good' relative to the other stores in the list. Any nodes
 // in the supplied 'exclude' list will be disqualified from selection. Returns
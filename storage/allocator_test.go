@@ -487,6 +487,124 @@ func TestAllocatorRebalanceByCount(t *testing.T) {
 	}
 }
 
+// TestAllocatorRebalanceByQPS verifies that a store serving considerably more
+// requests than the mean rebalances to less loaded stores even though range
+// counts are balanced.
+func TestAllocatorRebalanceByQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper, g, _, a, _ := createTestAllocator()
+	defer stopper.Stop()
+
+	stores := []*roachpb.StoreDescriptor{
+		{
+			StoreID:  1,
+			Node:     roachpb.NodeDescriptor{NodeID: 1},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 100, RangeCount: 10, QueriesPerSecond: 1000},
+		},
+		{
+			StoreID:  2,
+			Node:     roachpb.NodeDescriptor{NodeID: 2},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 100, RangeCount: 10, QueriesPerSecond: 200},
+		},
+		{
+			StoreID:  3,
+			Node:     roachpb.NodeDescriptor{NodeID: 3},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 100, RangeCount: 10, QueriesPerSecond: 200},
+		},
+		{
+			StoreID:  4,
+			Node:     roachpb.NodeDescriptor{NodeID: 4},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 100, RangeCount: 10, QueriesPerSecond: 100},
+		},
+	}
+	gossiputil.NewStoreGossiper(g).GossipStores(stores, t)
+
+	// Every rebalance target must be a store other than the overloaded one.
+	for i := 0; i < 10; i++ {
+		result := a.RebalanceTarget(1, roachpb.Attributes{}, []roachpb.ReplicaDescriptor{})
+		if result == nil {
+			t.Fatal("nil result")
+		}
+		if result.StoreID == 1 {
+			t.Errorf("%d: expected rebalance away from store 1", i)
+		}
+	}
+
+	// Verify ShouldRebalance results.
+	a.options.Deterministic = true
+	for i, store := range stores {
+		result := a.ShouldRebalance(store.StoreID)
+		if expResult := (i == 0); expResult != result {
+			t.Errorf("%d: expected rebalance %t; got %t", i, expResult, result)
+		}
+	}
+}
+
+// TestAllocatorTransferLeaseTarget verifies that leases are transferred to
+// the replica with the fewest leases, or to the least loaded replica if the
+// lease holder is overloaded.
+func TestAllocatorTransferLeaseTarget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper, g, _, a, _ := createTestAllocator()
+	defer stopper.Stop()
+
+	stores := []*roachpb.StoreDescriptor{
+		{
+			StoreID:  1,
+			Node:     roachpb.NodeDescriptor{NodeID: 1},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 100, LeaseCount: 10},
+		},
+		{
+			StoreID:  2,
+			Node:     roachpb.NodeDescriptor{NodeID: 2},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 100, LeaseCount: 2},
+		},
+		{
+			StoreID:  3,
+			Node:     roachpb.NodeDescriptor{NodeID: 3},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 100, LeaseCount: 6, QueriesPerSecond: 1000},
+		},
+		{
+			// This store holds no replica of the range.
+			StoreID:  4,
+			Node:     roachpb.NodeDescriptor{NodeID: 4},
+			Capacity: roachpb.StoreCapacity{Capacity: 100, Available: 100, LeaseCount: 6, QueriesPerSecond: 200},
+		},
+	}
+	gossiputil.NewStoreGossiper(g).GossipStores(stores, t)
+
+	existing := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1, ReplicaID: 1},
+		{NodeID: 2, StoreID: 2, ReplicaID: 2},
+		{NodeID: 3, StoreID: 3, ReplicaID: 3},
+	}
+
+	testCases := []struct {
+		leaseStoreID roachpb.StoreID
+		// expected is the store ID of the transfer target, or zero if no transfer
+		// is expected.
+		expected roachpb.StoreID
+	}{
+		// Store 1 holds more leases than the mean.
+		{leaseStoreID: 1, expected: 2},
+		// Store 2 holds fewer leases than the mean.
+		{leaseStoreID: 2, expected: 0},
+		// Store 3 holds the mean lease count but is overloaded.
+		{leaseStoreID: 3, expected: 1},
+	}
+	for i, test := range testCases {
+		target, ok := a.TransferLeaseTarget(roachpb.Attributes{}, existing, test.leaseStoreID)
+		if ok != (test.expected != 0) || target.StoreID != test.expected {
+			t.Errorf("%d: expected transfer to store %d; got %+v (ok %t)", i, test.expected, target, ok)
+		}
+	}
+
+	a.options.AllowRebalance = false
+	if target, ok := a.TransferLeaseTarget(roachpb.Attributes{}, existing, 1); ok {
+		t.Errorf("expected no transfer with rebalancing disabled; got %+v", target)
+	}
+}
+
 // TestAllocatorRemoveTarget verifies that the replica chosen by RemoveTarget is
 // the one with the lowest capacity.
 func TestAllocatorRemoveTarget(t *testing.T) {
@@ -23,6 +23,17 @@ import (
 	"github.com/cockroachdb/cockroach/util/log"
 )
 
+const (
+	// qpsRebalanceThreshold is the fraction above the mean request rate at
+	// which a store is considered overloaded, in which case it sheds replicas
+	// and leases regardless of its range and lease counts.
+	qpsRebalanceThreshold = 0.25
+	// minQPSRebalanceDifference is the minimum difference between the request
+	// rate of an overloaded store and the mean. It avoids rebalancing churn in
+	// lightly loaded clusters.
+	minQPSRebalanceDifference = 100
+)
+
 type nodeIDSet map[roachpb.NodeID]struct{}
 
 func makeNodeIDSet(nodeIDs ...roachpb.NodeID) nodeIDSet {
@@ -34,7 +45,8 @@ func makeNodeIDSet(nodeIDs ...roachpb.NodeID) nodeIDSet {
 }
 
 // rangeCountBalancer attempts to balance ranges across the cluster while
-// considering only the number of ranges being serviced each store.
+// considering the number of ranges being serviced by each store, unless a
+// store serves considerably more requests than the others.
 type rangeCountBalancer struct {
 	rand allocatorRand
 }
@@ -57,6 +69,21 @@ func (rcb rangeCountBalancer) selectGood(
 	return best
 }
 
+// selectLeastLoaded returns the store serving the fewest requests from a
+// random sample of the stores in the list.
+func (rcb rangeCountBalancer) selectLeastLoaded(
+	sl StoreList, excluded nodeIDSet,
+) *roachpb.StoreDescriptor {
+	candidates := selectRandom(rcb.rand, 3, sl, excluded)
+	var best *roachpb.StoreDescriptor
+	for _, candidate := range candidates {
+		if best == nil || candidate.Capacity.QueriesPerSecond < best.Capacity.QueriesPerSecond {
+			best = candidate
+		}
+	}
+	return best
+}
+
 func (rcb rangeCountBalancer) selectBad(sl StoreList) *roachpb.StoreDescriptor {
 	var worst *roachpb.StoreDescriptor
 	for _, candidate := range sl.stores {
@@ -72,11 +99,29 @@ func (rcb rangeCountBalancer) selectBad(sl StoreList) *roachpb.StoreDescriptor {
 }
 
 // improve returns a candidate StoreDescriptor to rebalance a replica to. The
-// strategy is to always converge on the mean range count. If that isn't
-// possible, we don't return any candidate.
+// strategy is to always converge on the mean range count, unless the given
+// store is overloaded, in which case the replica is moved to a store serving
+// fewer requests than the mean. If that isn't possible, we don't return any
+// candidate.
 func (rcb rangeCountBalancer) improve(
 	store *roachpb.StoreDescriptor, sl StoreList, excluded nodeIDSet,
 ) *roachpb.StoreDescriptor {
+	if overloaded(store, sl) {
+		candidate := rcb.selectLeastLoaded(sl, excluded)
+		if candidate == nil || candidate.Capacity.QueriesPerSecond >= sl.candidateQPS.mean {
+			if log.V(2) {
+				log.Infof("not rebalancing: no candidate target for overloaded store %d",
+					store.StoreID)
+			}
+			return nil
+		}
+		if log.V(2) {
+			log.Infof("found candidate store %d for overloaded store %d",
+				candidate.StoreID, store.StoreID)
+		}
+		return candidate
+	}
+
 	// Moving a replica from the given store makes its range count converge on
 	// the mean range count.
 	if store.Capacity.FractionUsed() <= maxFractionUsedThreshold &&
@@ -116,6 +161,52 @@ func (rcb rangeCountBalancer) improve(
 	return candidate
 }
 
+// improveLease returns the store to transfer a range lease to from the given
+// lease holder store, chosen among the stores holding the range's other
+// replicas. If the lease holder is overloaded, the lease is moved to the
+// candidate serving the fewest requests, provided it serves fewer than the
+// mean. Otherwise, the lease is moved to the candidate holding the fewest
+// leases if that makes the lease counts converge on the mean. Returns nil if
+// no transfer would improve the balance.
+func (rcb rangeCountBalancer) improveLease(
+	leaseStore *roachpb.StoreDescriptor, candidates []*roachpb.StoreDescriptor, sl StoreList,
+) *roachpb.StoreDescriptor {
+	var best *roachpb.StoreDescriptor
+	if overloaded(leaseStore, sl) {
+		for _, candidate := range candidates {
+			if best == nil || candidate.Capacity.QueriesPerSecond < best.Capacity.QueriesPerSecond {
+				best = candidate
+			}
+		}
+		if best == nil || best.Capacity.QueriesPerSecond >= sl.candidateQPS.mean {
+			return nil
+		}
+		return best
+	}
+
+	if float64(leaseStore.Capacity.LeaseCount) <= sl.candidateLeases.mean {
+		return nil
+	}
+	for _, candidate := range candidates {
+		if best == nil || candidate.Capacity.LeaseCount < best.Capacity.LeaseCount {
+			best = candidate
+		}
+	}
+	// The transfer must not merely reverse the imbalance between the two
+	// stores.
+	if best == nil || best.Capacity.LeaseCount+1 >= leaseStore.Capacity.LeaseCount {
+		return nil
+	}
+	return best
+}
+
+// overloaded returns whether the given store serves considerably more
+// requests than the mean of the stores in the list.
+func overloaded(store *roachpb.StoreDescriptor, sl StoreList) bool {
+	qps, mean := store.Capacity.QueriesPerSecond, sl.candidateQPS.mean
+	return qps > mean*(1+qpsRebalanceThreshold) && qps-mean > minQPSRebalanceDifference
+}
+
 // selectRandom chooses up to count random store descriptors from the given
 // store list, excluding any stores that are too full to accept more replicas.
 func selectRandom(
@@ -32,10 +32,12 @@ import (
 	"github.com/cockroachdb/cockroach/storage/engine"
 	"github.com/cockroachdb/cockroach/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/testutils"
+	"github.com/cockroachdb/cockroach/util"
 	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 	"github.com/cockroachdb/cockroach/util/log"
 	"github.com/cockroachdb/cockroach/util/tracing"
+	"github.com/pkg/errors"
 )
 
 func adminMergeArgs(key roachpb.Key) roachpb.AdminMergeRequest {
@@ -426,6 +428,58 @@ func TestStoreRangeMergeStats(t *testing.T) {
 	}
 }
 
+// TestStoreRangeMergeQueue verifies that the merge queue merges an empty
+// range into its left hand neighbor once it serves few requests, but leaves
+// ranges separated by zone config split keys alone.
+func TestStoreRangeMergeQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sCtx := storage.TestStoreContext()
+	sCtx.TestingKnobs.DisableSplitQueue = true
+	sCtx.MergeQueueEnabled = true
+	store, stopper, _ := createTestStoreWithContext(t, sCtx)
+	defer stopper.Stop()
+
+	// Split off two empty ranges beyond the largest descriptor ID, where no
+	// zone config split keys separate them.
+	leftKey := keys.MakeTablePrefix(1000)
+	rightKey := append(append([]byte(nil), leftKey...), 'b')
+	// The left range serves many requests until the merge is expected. It is
+	// the last range before the second split, so it can't be merged yet.
+	if err := store.DB().AdminSplit(leftKey); err != nil {
+		t.Fatal(err)
+	}
+	store.LookupReplica(leftKey, nil).SetQPSForTesting(1000)
+	if err := store.DB().AdminSplit(rightKey); err != nil {
+		t.Fatal(err)
+	}
+	if left, right := store.LookupReplica(leftKey, nil), store.LookupReplica(rightKey, nil); left == right {
+		t.Fatalf("expected ranges to be split; got %s", left)
+	}
+
+	// Ranges serving many requests aren't merged.
+	store.ForceMergeScanAndProcess()
+	if left, right := store.LookupReplica(leftKey, nil), store.LookupReplica(rightKey, nil); left == right {
+		t.Fatalf("expected busy range not to be merged; got %s", left)
+	}
+	store.LookupReplica(leftKey, nil).SetQPSForTesting(0)
+
+	util.SucceedsSoon(t, func() error {
+		store.ForceMergeScanAndProcess()
+		left := store.LookupReplica(leftKey, nil)
+		right := store.LookupReplica(rightKey, nil)
+		if left != right {
+			return errors.Errorf("ranges were not merged: %s, %s", left.Desc(), right.Desc())
+		}
+		return nil
+	})
+
+	// The first range is separated from the merged range by the split keys of
+	// the system tables; it must not be merged.
+	if first, merged := store.LookupReplica(roachpb.RKeyMin, nil), store.LookupReplica(leftKey, nil); first == merged {
+		t.Fatalf("expected first range to remain split from %s", merged)
+	}
+}
+
 func BenchmarkStoreRangeMerge(b *testing.B) {
 	defer tracing.Disable()()
 	sCtx := storage.TestStoreContext()
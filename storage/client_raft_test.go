@@ -19,6 +19,9 @@ package storage_test
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"sync/atomic"
@@ -294,6 +297,65 @@ func TestReplicateRange(t *testing.T) {
 	})
 }
 
+// TestAddSSTableReplicated verifies that AddSSTable is applied through Raft,
+// ingesting the data on every replica of the range.
+func TestAddSSTableReplicated(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	mtc := startMultiTestContext(t, 3)
+	defer mtc.Stop()
+	mtc.replicateRange(1, 1, 2)
+
+	dir := util.CreateTempDir(t, "sstable")
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	path := filepath.Join(dir, "data.sst")
+	sst := engine.MakeRocksDBSstFileWriter()
+	if err := sst.Open(path); err != nil {
+		t.Fatal(err)
+	}
+	ts := mtc.clock.Now()
+	value := roachpb.MakeValueFromString("value")
+	value.InitChecksum(roachpb.Key("sst-a"))
+	if err := sst.Add(engine.MVCCKeyValue{
+		Key:   engine.MVCCKey{Key: roachpb.Key("sst-a"), Timestamp: ts},
+		Value: value.RawBytes,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sst.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := &roachpb.AddSSTableRequest{
+		Span: roachpb.Span{Key: roachpb.Key("sst-a"), EndKey: roachpb.Key("sst-b")},
+		Data: data,
+	}
+	if _, err := client.SendWrapped(rg1(mtc.stores[0]), nil, args); err != nil {
+		t.Fatal(err)
+	}
+
+	util.SucceedsSoon(t, func() error {
+		for i, eng := range mtc.engines {
+			v, _, err := engine.MVCCGet(context.Background(), eng, roachpb.Key("sst-a"),
+				mtc.clock.Now(), true, nil)
+			if err != nil {
+				return err
+			}
+			if v == nil {
+				return errors.Errorf("store %d: sstable not ingested", i)
+			}
+		}
+		return nil
+	})
+}
+
 // TestRestoreReplicas ensures that consensus group membership is properly
 // persisted to disk and restored when a node is stopped and restarted.
 func TestRestoreReplicas(t *testing.T) {
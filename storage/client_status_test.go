@@ -73,10 +73,18 @@ func TestComputeStatsForKeySpan(t *testing.T) {
 		{"a", "c", 1, 3},
 		{"b", "e", 2, 5},
 		{"e", "i", 2, 1},
+		// The stats of the ranges extending beyond the span only count the
+		// keys within it.
+		{"b", "c", 1, 3},
+		{"bb", "d", 2, 2},
+		{"a", "dd", 2, 4},
 	} {
 		start, end := tcase.startKey, tcase.endKey
-		stats, count := mtc.stores[0].ComputeStatsForKeySpan(
+		stats, count, err := mtc.stores[0].ComputeStatsForKeySpan(
 			roachpb.RKey(start), roachpb.RKey(end))
+		if err != nil {
+			t.Fatal(err)
+		}
 		if a, e := count, tcase.expectedRanges; a != e {
 			t.Errorf("Expected %d ranges in span [%s - %s], found %d", e, start, end, a)
 		}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/roachpb"
 	"github.com/cockroachdb/cockroach/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/protoutil"
 )
 
@@ -119,6 +120,16 @@ type Reader interface {
 	// skipped). The caller must invoke Iterator.Close() when finished with the
 	// iterator to free resources.
 	NewIterator(prefix bool) Iterator
+	// NewTimeBoundIterator is like NewIterator, but the returned iterator skips
+	// over sstables which contain no MVCC versions or intents with timestamps
+	// in the inclusive window [start, end]. This makes incremental scans
+	// proportional to the amount of data changed in the window rather than the
+	// total amount of data. The iterator may still return keys outside of the
+	// window (all of them, with versions of RocksDB without table filters), so
+	// callers must filter on the key timestamps. Intents returned by the
+	// iterator may already have been resolved, since the deletion of an intent
+	// isn't timestamped, and must be verified using a regular iterator.
+	NewTimeBoundIterator(start, end hlc.Timestamp) Iterator
 }
 
 // Writer is the write interface to an engine's data.
@@ -171,6 +182,17 @@ type Engine interface {
 	Flush() error
 	// GetStats retrieves stats from the engine.
 	GetStats() (*Stats, error)
+	// ClearRange removes all of the keys in the range [start,end) from the
+	// engine using a single range tombstone, making the cost of the deletion
+	// independent of the number of keys (versions of RocksDB without range
+	// tombstones delete the keys one by one). Like Clear, this removes the
+	// entries rather than writing MVCC deletion tombstones. It is not
+	// available on batches and is not atomic with respect to them.
+	ClearRange(start, end MVCCKey) error
+	// IngestExternalFile links the sstable in data, as built by a
+	// RocksDBSstFileWriter, directly into the engine. Unlike a Batch, the
+	// ingested keys bypass the write-ahead log and memtable.
+	IngestExternalFile(data []byte) error
 	// NewBatch returns a new instance of a batched engine which wraps
 	// this engine. Batched engines accumulate all mutations and apply
 	// them atomically on a call to Commit().
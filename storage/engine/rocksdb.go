@@ -458,6 +458,15 @@ func (r *RocksDB) Clear(key MVCCKey) error {
 	return dbClear(r.rdb, key)
 }
 
+// ClearRange removes the keys in the range [start,end) from the db using a
+// range tombstone.
+func (r *RocksDB) ClearRange(start, end MVCCKey) error {
+	if !start.Less(end) {
+		return errors.Errorf("invalid range [%s,%s)", start, end)
+	}
+	return statusToError(C.DBClearRange(r.rdb, goToCKey(start), goToCKey(end)))
+}
+
 // Iterate iterates from start to end keys, invoking f on each
 // key/value pair. See engine.Iterate for details.
 func (r *RocksDB) Iterate(start, end MVCCKey, f func(MVCCKeyValue) (bool, error)) error {
@@ -554,11 +563,25 @@ func (r *RocksDB) Checkpoint(dir string) error {
 	return statusToError(C.DBCheckpoint(r.rdb, goToCSlice([]byte(dir))))
 }
 
+// IngestExternalFile links the sstable in data into this rocksdb engine.
+func (r *RocksDB) IngestExternalFile(data []byte) error {
+	if len(data) == 0 {
+		return errors.Errorf("empty sstable")
+	}
+	return statusToError(C.DBIngestExternalFile(r.rdb, goToCSlice(data)))
+}
+
 // NewIterator returns an iterator over this rocksdb engine.
 func (r *RocksDB) NewIterator(prefix bool) Iterator {
 	return newRocksDBIterator(r.rdb, prefix, r)
 }
 
+// NewTimeBoundIterator returns an iterator over this rocksdb engine which
+// skips sstables with no data in the given timestamp window.
+func (r *RocksDB) NewTimeBoundIterator(start, end hlc.Timestamp) Iterator {
+	return newRocksDBTimeBoundIterator(r.rdb, start, end, r)
+}
+
 // NewSnapshot creates a snapshot handle from engine and returns a
 // read-only rocksDBSnapshot engine.
 func (r *RocksDB) NewSnapshot() Reader {
@@ -668,6 +691,13 @@ func (r *rocksDBSnapshot) NewIterator(prefix bool) Iterator {
 	return newRocksDBIterator(r.handle, prefix, r)
 }
 
+// NewTimeBoundIterator returns a new instance of an Iterator over the engine
+// using the snapshot handle which skips sstables with no data in the given
+// timestamp window.
+func (r *rocksDBSnapshot) NewTimeBoundIterator(start, end hlc.Timestamp) Iterator {
+	return newRocksDBTimeBoundIterator(r.handle, start, end, r)
+}
+
 // reusableIterator wraps rocksDBIterator and allows reuse of an iterator
 // for the lifetime of a batch.
 type reusableIterator struct {
@@ -717,6 +747,13 @@ func (r *distinctBatch) NewIterator(prefix bool) Iterator {
 	return iter
 }
 
+// NewTimeBoundIterator returns an iterator over the batch and underlying
+// engine which skips sstables with no data in the given timestamp window.
+// Unlike the iterators returned by NewIterator, it is not cached.
+func (r *distinctBatch) NewTimeBoundIterator(start, end hlc.Timestamp) Iterator {
+	return newRocksDBTimeBoundIterator(r.batch, start, end, r)
+}
+
 func (r *distinctBatch) Get(key MVCCKey) ([]byte, error) {
 	return dbGet(r.batch, key)
 }
@@ -961,6 +998,18 @@ func (r *rocksDBBatch) NewIterator(prefix bool) Iterator {
 	return iter
 }
 
+// NewTimeBoundIterator returns an iterator over the batch and underlying
+// engine which skips sstables with no data in the given timestamp window.
+// Unlike the iterators returned by NewIterator, it is not cached, so it only
+// reflects the writes to the batch performed before its creation.
+func (r *rocksDBBatch) NewTimeBoundIterator(start, end hlc.Timestamp) Iterator {
+	if r.distinctOpen {
+		panic("distinct batch open")
+	}
+	r.flushMutations()
+	return newRocksDBTimeBoundIterator(r.batch, start, end, r)
+}
+
 func (r *rocksDBBatch) Commit() error {
 	if r.batch == nil {
 		panic("this batch was already committed")
@@ -1059,6 +1108,25 @@ func newRocksDBIterator(rdb *C.DBEngine, prefix bool, engine Reader) Iterator {
 	return r
 }
 
+// newRocksDBTimeBoundIterator returns a new iterator over the supplied
+// RocksDB instance which skips sstables containing no data in the timestamp
+// window [start, end]. The caller must call rocksDBIterator.Close() when
+// finished with the iterator to free up resources.
+func newRocksDBTimeBoundIterator(
+	rdb *C.DBEngine, start, end hlc.Timestamp, engine Reader,
+) Iterator {
+	r := iterPool.Get().(*rocksDBIterator)
+	r.iter = C.DBNewTimeBoundIter(rdb, goToCTimestamp(start), goToCTimestamp(end))
+	r.engine = engine
+	return r
+}
+
+// timeBoundIteratorsSkipTables returns whether time-bound iterators skip
+// sstables, which depends on the version of RocksDB.
+func timeBoundIteratorsSkipTables() bool {
+	return bool(C.DBTimeBoundItersSkipTables())
+}
+
 func (r *rocksDBIterator) init(rdb *C.DBEngine, prefix bool, engine Reader) {
 	r.iter = C.DBNewIter(rdb, C.bool(prefix))
 	r.engine = engine
@@ -1232,6 +1300,13 @@ func goToCKey(key MVCCKey) C.DBKey {
 	}
 }
 
+func goToCTimestamp(ts hlc.Timestamp) C.DBTimestamp {
+	return C.DBTimestamp{
+		wall_time: C.int64_t(ts.WallTime),
+		logical:   C.int32_t(ts.Logical),
+	}
+}
+
 func cToGoKey(key C.DBKey) MVCCKey {
 	// When converting a C.DBKey to an MVCCKey, give the underlying slice an
 	// extra byte of capacity in anticipation of roachpb.Key.Next() being
@@ -1421,3 +1496,47 @@ func dbIterate(rdb *C.DBEngine, engine Reader, start, end MVCCKey,
 	// Check for any errors during iteration.
 	return it.Error()
 }
+
+// RocksDBSstFileWriter creates a file suitable for importing with
+// RocksDB's external file ingestion.
+type RocksDBSstFileWriter struct {
+	fw *C.DBSstFileWriter
+	// DataSize tracks the total key and value bytes added so far.
+	DataSize int64
+}
+
+// MakeRocksDBSstFileWriter creates a new RocksDBSstFileWriter. Open must be
+// called before keys are added.
+func MakeRocksDBSstFileWriter() RocksDBSstFileWriter {
+	return RocksDBSstFileWriter{fw: C.DBSstFileWriterNew()}
+}
+
+// Open creates the sstable at the given path.
+func (fw *RocksDBSstFileWriter) Open(path string) error {
+	if fw.fw == nil {
+		return errors.New("cannot call Open on a closed writer")
+	}
+	return statusToError(C.DBSstFileWriterOpen(fw.fw, goToCSlice([]byte(path))))
+}
+
+// Add puts a kv entry into the sstable being built. An error is returned if
+// it is not greater than any previously added entry (according to the
+// comparator configured during writer creation).
+func (fw *RocksDBSstFileWriter) Add(kv MVCCKeyValue) error {
+	if fw.fw == nil {
+		return errors.New("cannot call Add on a closed writer")
+	}
+	fw.DataSize += int64(len(kv.Key.Key)) + int64(len(kv.Value))
+	return statusToError(C.DBSstFileWriterAdd(fw.fw, goToCKey(kv.Key), goToCSlice(kv.Value)))
+}
+
+// Close finishes the sstable and frees the underlying writer. It is safe to
+// call Close more than once.
+func (fw *RocksDBSstFileWriter) Close() error {
+	if fw.fw == nil {
+		return nil
+	}
+	err := statusToError(C.DBSstFileWriterClose(fw.fw))
+	fw.fw = nil
+	return err
+}
@@ -28,10 +28,13 @@
 #include "rocksdb/merge_operator.h"
 #include "rocksdb/options.h"
 #include "rocksdb/slice_transform.h"
+#include "rocksdb/sst_file_writer.h"
 #include "rocksdb/statistics.h"
 #include "rocksdb/table.h"
+#include "rocksdb/table_properties.h"
 #include "rocksdb/utilities/checkpoint.h"
 #include "rocksdb/utilities/write_batch_with_index.h"
+#include "rocksdb/version.h"
 #include "cockroach/roachpb/data.pb.h"
 #include "cockroach/roachpb/internal.pb.h"
 #include "cockroach/storage/engine/enginepb/mvcc.pb.h"
@@ -62,6 +65,7 @@ struct DBEngine {
   virtual DBSlice BatchRepr() = 0;
   virtual DBStatus Get(DBKey key, DBString* value) = 0;
   virtual DBIterator* NewIter(bool prefix) = 0;
+  virtual DBIterator* NewTimeBoundIter(DBTimestamp min_ts, DBTimestamp max_ts) = 0;
   virtual DBStatus GetStats(DBStatsResult* stats) = 0;
 
   DBSSTable* GetSSTables(int* n);
@@ -101,6 +105,7 @@ struct DBImpl : public DBEngine {
   virtual DBSlice BatchRepr();
   virtual DBStatus Get(DBKey key, DBString* value);
   virtual DBIterator* NewIter(bool prefix);
+  virtual DBIterator* NewTimeBoundIter(DBTimestamp min_ts, DBTimestamp max_ts);
   virtual DBStatus GetStats(DBStatsResult* stats);
 };
 
@@ -121,6 +126,7 @@ struct DBBatch : public DBEngine {
   virtual DBSlice BatchRepr();
   virtual DBStatus Get(DBKey key, DBString* value);
   virtual DBIterator* NewIter(bool prefix);
+  virtual DBIterator* NewTimeBoundIter(DBTimestamp min_ts, DBTimestamp max_ts);
   virtual DBStatus GetStats(DBStatsResult* stats);
 };
 
@@ -145,6 +151,7 @@ struct DBSnapshot : public DBEngine {
   virtual DBSlice BatchRepr();
   virtual DBStatus Get(DBKey key, DBString* value);
   virtual DBIterator* NewIter(bool prefix);
+  virtual DBIterator* NewTimeBoundIter(DBTimestamp min_ts, DBTimestamp max_ts);
   virtual DBStatus GetStats(DBStatsResult* stats);
 };
 
@@ -152,6 +159,18 @@ struct DBIterator {
   std::unique_ptr<rocksdb::Iterator> rep;
 };
 
+struct DBSstFileWriter {
+  std::unique_ptr<rocksdb::Options> options;
+  rocksdb::SstFileWriter rep;
+  bool open;
+
+  DBSstFileWriter(rocksdb::Options* o)
+      : options(o),
+        rep(rocksdb::EnvOptions(), *o, o->comparator),
+        open(false) {
+  }
+};
+
 }  // extern "C"
 
 namespace {
@@ -178,6 +197,19 @@ rocksdb::Slice ToSlice(DBString s) {
 
 const int kMVCCVersionTimestampSize = 12;
 
+// Timestamps are encoded as <wall_time>[<logical>], the same as the
+// timestamp suffix of MVCC keys (excluding the NUL prefix). Encoded
+// timestamps sort lexicographically in timestamp order.
+std::string EncodeTimestamp(int64_t wall_time, int32_t logical) {
+  std::string s;
+  s.reserve(kMVCCVersionTimestampSize);
+  EncodeUint64(&s, uint64_t(wall_time));
+  if (logical != 0) {
+    EncodeUint32(&s, uint32_t(logical));
+  }
+  return s;
+}
+
 // MVCC keys are encoded as <key>[<wall_time>[<logical>]]<#timestamp-bytes>. A
 // custom RocksDB comparator (DBComparator) is used to maintain the desired
 // ordering as these keys do not sort lexicographically correctly.
@@ -459,6 +491,124 @@ class DBPrefixExtractor : public rocksdb::SliceTransform {
   }
 };
 
+// The names of the user collected sstable properties holding the
+// minimum and maximum encoded timestamps of the MVCC versions and
+// intents in the sstable.
+const char kTimestampMinProp[] = "crdb.ts.min";
+const char kTimestampMaxProp[] = "crdb.ts.max";
+
+// TimeBoundTblPropCollector records the minimum and maximum timestamps
+// of the MVCC versions and intents in an sstable so that time-bound
+// iterators can skip sstables containing no data in the window of
+// interest.
+class TimeBoundTblPropCollector : public rocksdb::TablePropertiesCollector {
+ public:
+  virtual const char* Name() const override {
+    return "TimeBoundTblPropCollector";
+  }
+
+  virtual rocksdb::Status Finish(rocksdb::UserCollectedProperties* properties) override {
+    (*properties)[kTimestampMinProp] = ts_min_;
+    (*properties)[kTimestampMaxProp] = ts_max_;
+    return rocksdb::Status::OK();
+  }
+
+  virtual rocksdb::Status AddUserKey(
+      const rocksdb::Slice& user_key, const rocksdb::Slice& value,
+      rocksdb::EntryType type, rocksdb::SequenceNumber seq,
+      uint64_t file_size) override {
+    rocksdb::Slice key;
+    rocksdb::Slice ts;
+    if (!SplitKey(user_key, &key, &ts)) {
+      return rocksdb::Status::OK();
+    }
+    if (!ts.empty()) {
+      ts.remove_prefix(1);  // The NUL prefix.
+      UpdateBounds(ts.ToString());
+      return rocksdb::Status::OK();
+    }
+    // An unversioned key. If it holds an intent, the intent's timestamp
+    // is included in the bounds. Note that the deletion of an intent
+    // when it is resolved is not timestamped and thus contributes no
+    // bounds.
+    if (type != rocksdb::kEntryPut) {
+      return rocksdb::Status::OK();
+    }
+    cockroach::storage::engine::enginepb::MVCCMetadata meta;
+    if (!meta.ParseFromArray(value.data(), value.size()) || !meta.has_txn()) {
+      return rocksdb::Status::OK();
+    }
+    const cockroach::util::hlc::Timestamp& txn_ts = meta.txn().timestamp();
+    UpdateBounds(EncodeTimestamp(txn_ts.wall_time(), txn_ts.logical()));
+    return rocksdb::Status::OK();
+  }
+
+  virtual rocksdb::UserCollectedProperties GetReadableProperties() const override {
+    return rocksdb::UserCollectedProperties{};
+  }
+
+ private:
+  void UpdateBounds(const std::string& ts) {
+    if (ts_min_.empty() || ts < ts_min_) {
+      ts_min_ = ts;
+    }
+    if (ts_max_.empty() || ts > ts_max_) {
+      ts_max_ = ts;
+    }
+  }
+
+  std::string ts_min_;
+  std::string ts_max_;
+};
+
+class TimeBoundTblPropCollectorFactory : public rocksdb::TablePropertiesCollectorFactory {
+ public:
+  virtual rocksdb::TablePropertiesCollector* CreateTablePropertiesCollector(
+      rocksdb::TablePropertiesCollectorFactory::Context context) override {
+    return new TimeBoundTblPropCollector();
+  }
+  virtual const char* Name() const override {
+    return "TimeBoundTblPropCollectorFactory";
+  }
+};
+
+// ReadOptions::table_filter was added in RocksDB 5.7. With older
+// versions, time-bound iterators don't skip any sstable.
+#define HAVE_TABLE_FILTER (ROCKSDB_MAJOR > 5 || (ROCKSDB_MAJOR == 5 && ROCKSDB_MINOR >= 7))
+
+// DB::DeleteRange was added in RocksDB 5.0. With older versions,
+// DBClearRange deletes the keys of the range one by one.
+#define HAVE_DELETE_RANGE (ROCKSDB_MAJOR >= 5)
+
+// TimeBoundReadOptions returns a copy of the supplied read options
+// which skips sstables containing no MVCC versions or intents with
+// timestamps in the inclusive window [min_ts, max_ts]. Sstables
+// written before their timestamp bounds were recorded are never
+// skipped.
+rocksdb::ReadOptions TimeBoundReadOptions(
+    const rocksdb::ReadOptions& read_opts, DBTimestamp min_ts, DBTimestamp max_ts) {
+  rocksdb::ReadOptions opts = read_opts;
+  opts.total_order_seek = true;
+#if HAVE_TABLE_FILTER
+  const std::string min = EncodeTimestamp(min_ts.wall_time, min_ts.logical);
+  const std::string max = EncodeTimestamp(max_ts.wall_time, max_ts.logical);
+  opts.table_filter = [min, max](const rocksdb::TableProperties& props) {
+    const rocksdb::UserCollectedProperties& user_props = props.user_collected_properties;
+    auto tbl_min = user_props.find(kTimestampMinProp);
+    auto tbl_max = user_props.find(kTimestampMaxProp);
+    if (tbl_min == user_props.end() || tbl_max == user_props.end()) {
+      return true;
+    }
+    if (tbl_max->second.empty()) {
+      // The sstable contains no MVCC versions or intents.
+      return false;
+    }
+    return tbl_max->second >= min && tbl_min->second <= max;
+  };
+#endif
+  return opts;
+}
+
 class DBBatchInserter : public rocksdb::WriteBatch::Handler {
  public:
   DBBatchInserter(rocksdb::WriteBatchWithIndex* batch)
@@ -1450,6 +1600,11 @@ DBStatus DBOpen(DBEngine **db, DBSlice dir, DBOptions db_opts) {
   std::shared_ptr<DBEventListener> event_listener(new DBEventListener);
   options.listeners.emplace_back(event_listener);
 
+  // Record the timestamp bounds of each sstable for use by time-bound
+  // iterators.
+  options.table_properties_collector_factories.emplace_back(
+      new TimeBoundTblPropCollectorFactory);
+
   std::unique_ptr<rocksdb::Env> memenv;
   if (dir.len == 0) {
     memenv.reset(rocksdb::NewMemEnv(rocksdb::Env::Default()));
@@ -1484,6 +1639,35 @@ DBStatus DBCompact(DBEngine* db) {
   return ToDBStatus(db->rep->CompactRange(rocksdb::CompactRangeOptions(), NULL, NULL));
 }
 
+DBStatus DBClearRange(DBEngine* db, DBKey start, DBKey end) {
+#if HAVE_DELETE_RANGE
+  return ToDBStatus(db->rep->DeleteRange(rocksdb::WriteOptions(), db->rep->DefaultColumnFamily(),
+                                         EncodeKey(start), EncodeKey(end)));
+#else
+  // Delete the keys in batches of bounded size.
+  const int kClearRangeBatchSize = 10000;
+  const std::string end_key = EncodeKey(end);
+  std::unique_ptr<rocksdb::Iterator> iter(db->rep->NewIterator(rocksdb::ReadOptions()));
+  rocksdb::WriteBatch batch;
+  for (iter->Seek(EncodeKey(start));
+       iter->Valid() && kComparator.Compare(iter->key(), end_key) < 0;
+       iter->Next()) {
+    batch.Delete(iter->key());
+    if (batch.Count() >= kClearRangeBatchSize) {
+      rocksdb::Status status = db->rep->Write(rocksdb::WriteOptions(), &batch);
+      if (!status.ok()) {
+        return ToDBStatus(status);
+      }
+      batch.Clear();
+    }
+  }
+  if (!iter->status().ok()) {
+    return ToDBStatus(iter->status());
+  }
+  return ToDBStatus(db->rep->Write(rocksdb::WriteOptions(), &batch));
+#endif
+}
+
 DBStatus DBCheckpoint(DBEngine* db, DBSlice dir) {
   rocksdb::Checkpoint* cp = nullptr;
   rocksdb::Status status = rocksdb::Checkpoint::Create(db->rep, &cp);
@@ -1672,6 +1856,26 @@ DBIterator* DBSnapshot::NewIter(bool prefix) {
   return iter;
 }
 
+DBIterator* DBImpl::NewTimeBoundIter(DBTimestamp min_ts, DBTimestamp max_ts) {
+  DBIterator* iter = new DBIterator;
+  iter->rep.reset(rep->NewIterator(TimeBoundReadOptions(read_opts, min_ts, max_ts)));
+  return iter;
+}
+
+DBIterator* DBBatch::NewTimeBoundIter(DBTimestamp min_ts, DBTimestamp max_ts) {
+  DBIterator* iter = new DBIterator;
+  rocksdb::Iterator* base = rep->NewIterator(TimeBoundReadOptions(read_opts, min_ts, max_ts));
+  rocksdb::WBWIIterator* delta = batch.NewIterator();
+  iter->rep.reset(new BaseDeltaIterator(base, delta, false));
+  return iter;
+}
+
+DBIterator* DBSnapshot::NewTimeBoundIter(DBTimestamp min_ts, DBTimestamp max_ts) {
+  DBIterator* iter = new DBIterator;
+  iter->rep.reset(rep->NewIterator(TimeBoundReadOptions(read_opts, min_ts, max_ts)));
+  return iter;
+}
+
 // GetStats retrieves a subset of RocksDB stats that are relevant to
 // CockroachDB.
 DBStatus DBImpl::GetStats(DBStatsResult* stats) {
@@ -1713,6 +1917,14 @@ DBIterator* DBNewIter(DBEngine* db, bool prefix) {
   return db->NewIter(prefix);
 }
 
+DBIterator* DBNewTimeBoundIter(DBEngine* db, DBTimestamp min_ts, DBTimestamp max_ts) {
+  return db->NewTimeBoundIter(min_ts, max_ts);
+}
+
+bool DBTimeBoundItersSkipTables() {
+  return HAVE_TABLE_FILTER;
+}
+
 void DBIterDestroy(DBIterator* iter) {
   delete iter;
 }
@@ -1970,3 +2182,56 @@ DBStatus DBGetStats(DBEngine* db, DBStatsResult* stats) {
 DBSSTable* DBGetSSTables(DBEngine* db, int* n) {
   return db->GetSSTables(n);
 }
+
+DBSstFileWriter* DBSstFileWriterNew() {
+  rocksdb::BlockBasedTableOptions table_options;
+  table_options.format_version = 2;
+
+  rocksdb::Options* options = new rocksdb::Options();
+  options->comparator = &kComparator;
+  options->table_factory.reset(rocksdb::NewBlockBasedTableFactory(table_options));
+  options->table_properties_collector_factories.emplace_back(
+      new TimeBoundTblPropCollectorFactory);
+  return new DBSstFileWriter(options);
+}
+
+DBStatus DBSstFileWriterOpen(DBSstFileWriter* fw, DBSlice path) {
+  rocksdb::Status status = fw->rep.Open(ToString(path));
+  if (!status.ok()) {
+    return ToDBStatus(status);
+  }
+  fw->open = true;
+  return kSuccess;
+}
+
+DBStatus DBSstFileWriterAdd(DBSstFileWriter* fw, DBKey key, DBSlice val) {
+  if (!fw->open) {
+    return FmtStatus("sstable writer is not open");
+  }
+  return ToDBStatus(fw->rep.Add(EncodeKey(key), ToSlice(val)));
+}
+
+DBStatus DBSstFileWriterClose(DBSstFileWriter* fw) {
+  rocksdb::Status status;
+  if (fw->open) {
+    status = fw->rep.Finish();
+  }
+  delete fw;
+  return ToDBStatus(status);
+}
+
+DBStatus DBIngestExternalFile(DBEngine* db, DBSlice data) {
+  static std::atomic<uint64_t> seq;
+  rocksdb::Env* env = db->rep->GetEnv();
+  const std::string path = db->rep->GetName() + "/ingest-" +
+      std::to_string(seq.fetch_add(1)) + ".sst";
+  rocksdb::Status status = rocksdb::WriteStringToFile(env, ToSlice(data), path, true /* sync */);
+  if (!status.ok()) {
+    return ToDBStatus(status);
+  }
+  status = db->rep->AddFile(path, true /* move_file */);
+  // On success the sstable has been linked into the database, so the
+  // staged file can be removed either way.
+  env->DeleteFile(path);
+  return ToDBStatus(status);
+}
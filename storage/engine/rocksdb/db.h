@@ -43,6 +43,11 @@ typedef struct {
   int32_t logical;
 } DBKey;
 
+typedef struct {
+  int64_t wall_time;
+  int32_t logical;
+} DBTimestamp;
+
 typedef struct {
   bool valid;
   DBKey key;
@@ -57,6 +62,7 @@ typedef DBString DBStatus;
 typedef struct DBCache DBCache;
 typedef struct DBEngine DBEngine;
 typedef struct DBIterator DBIterator;
+typedef struct DBSstFileWriter DBSstFileWriter;
 
 // DBOptions contains local database options.
 typedef struct {
@@ -100,6 +106,13 @@ DBStatus DBFlush(DBEngine* db);
 // Forces an immediate compaction over all keys.
 DBStatus DBCompact(DBEngine* db);
 
+// Deletes all of the keys in the range [start,end) by writing a single
+// range tombstone, or one deletion per key with versions of RocksDB
+// which don't support range tombstones. Note that unlike DBDelete,
+// this only supports a DBEngine opened with DBOpen and not a batch or
+// snapshot.
+DBStatus DBClearRange(DBEngine* db, DBKey start, DBKey end);
+
 // Checkpoint creates a point-in-time snapshot of the database,
 // hard-linking sstable files and copying the manifest and other
 // files.
@@ -152,6 +165,18 @@ DBEngine* DBNewBatch(DBEngine* db);
 // DBIterDestroy().
 DBIterator* DBNewIter(DBEngine* db, bool prefix);
 
+// Creates a new database iterator which skips over sstables that
+// contain no MVCC versions (or intents) with timestamps in the
+// inclusive window [min_ts, max_ts]. The iterator may still return
+// keys outside of the window. It is the callers responsibility to
+// call DBIterDestroy().
+DBIterator* DBNewTimeBoundIter(DBEngine* db, DBTimestamp min_ts, DBTimestamp max_ts);
+
+// Returns whether the iterators created by DBNewTimeBoundIter skip
+// sstables, which requires a version of RocksDB supporting table
+// filters. Otherwise they return all the keys.
+bool DBTimeBoundItersSkipTables();
+
 // Destroys an iterator, freeing up any associated memory.
 void DBIterDestroy(DBIterator* iter);
 
@@ -233,6 +258,28 @@ typedef struct {
 // table.
 DBSSTable* DBGetSSTables(DBEngine* db, int* n);
 
+// Creates a new SstFileWriter for building an sstable outside of a
+// database. The sstable uses the same key encoding, comparator and
+// table properties as the sstables of a DBEngine.
+DBSstFileWriter* DBSstFileWriterNew();
+
+// Opens the sstable to be built at the given path.
+DBStatus DBSstFileWriterOpen(DBSstFileWriter* fw, DBSlice path);
+
+// Adds a key/value to the sstable being built. Keys must be added in
+// strictly increasing order.
+DBStatus DBSstFileWriterAdd(DBSstFileWriter* fw, DBKey key, DBSlice val);
+
+// Finishes building the sstable, if one was opened, and frees the
+// SstFileWriter. The SstFileWriter may not be used after calling
+// DBSstFileWriterClose.
+DBStatus DBSstFileWriterClose(DBSstFileWriter* fw);
+
+// Links the sstable contained in data, which must have been built with a
+// DBSstFileWriter, into the database. The sstable is staged in the
+// database's environment and then moved into place.
+DBStatus DBIngestExternalFile(DBEngine* db, DBSlice data);
+
 #ifdef __cplusplus
 }  // extern "C"
 #endif
@@ -30,6 +30,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/roachpb"
 	"github.com/cockroachdb/cockroach/testutils"
+	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/leaktest"
 	"github.com/cockroachdb/cockroach/util/stop"
 )
@@ -430,3 +431,110 @@ func TestReadAmplification(t *testing.T) {
 		t.Errorf("got %d, expected %d", a, e)
 	}
 }
+
+// TestRocksDBTimeBoundIterator verifies that time-bound iterators skip
+// sstables with no data in their timestamp window, while still returning
+// keys which haven't been flushed to an sstable yet.
+func TestRocksDBTimeBoundIterator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop()
+	db := NewInMem(roachpb.Attributes{}, 1<<20, stopper)
+
+	ts := func(wallTime int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wallTime}
+	}
+	put := func(key string, wallTime int64) {
+		if err := db.Put(MVCCKey{Key: roachpb.Key(key), Timestamp: ts(wallTime)}, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Write an sstable with versions at timestamps between 5 and 7.
+	put("a", 5)
+	put("b", 6)
+	put("c", 7)
+	if err := db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// Write a version at timestamp 20 which remains in the memtable.
+	put("d", 20)
+
+	testCases := []struct {
+		start, end int64
+		expected   []string
+	}{
+		// The window overlaps the sstable, so all of its keys are returned,
+		// including those outside of the window.
+		{start: 6, end: 6, expected: []string{"a", "b", "c", "d"}},
+		{start: 1, end: 5, expected: []string{"a", "b", "c", "d"}},
+		{start: 7, end: 10, expected: []string{"a", "b", "c", "d"}},
+		// The window doesn't overlap the sstable.
+		{start: 1, end: 4, expected: []string{"d"}},
+		{start: 8, end: 30, expected: []string{"d"}},
+	}
+	for i, test := range testCases {
+		if !timeBoundIteratorsSkipTables() {
+			// No sstable is skipped.
+			test.expected = []string{"a", "b", "c", "d"}
+		}
+		iter := db.NewTimeBoundIterator(ts(test.start), ts(test.end))
+		var keys []string
+		for iter.Seek(MakeMVCCMetadataKey(roachpb.KeyMin)); iter.Valid(); iter.Next() {
+			keys = append(keys, string(iter.Key().Key))
+		}
+		if err := iter.Error(); err != nil {
+			t.Fatal(err)
+		}
+		iter.Close()
+		if !reflect.DeepEqual(keys, test.expected) {
+			t.Errorf("%d: expected keys %v in window [%d,%d]; got %v",
+				i, test.expected, test.start, test.end, keys)
+		}
+	}
+}
+
+// TestRocksDBClearRange verifies that ClearRange removes the keys in the
+// range, regardless of whether they're in the memtable or an sstable, and
+// leaves the keys outside of it untouched.
+func TestRocksDBClearRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop()
+	db := NewInMem(roachpb.Attributes{}, 1<<20, stopper)
+
+	put := func(key string) {
+		if err := db.Put(MakeMVCCMetadataKey(roachpb.Key(key)), []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("a")
+	put("b")
+	if err := db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	put("c")
+	put("d")
+
+	if err := db.ClearRange(MakeMVCCMetadataKey(roachpb.Key("b")),
+		MakeMVCCMetadataKey(roachpb.Key("d"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ClearRange(MakeMVCCMetadataKey(roachpb.Key("d")),
+		MakeMVCCMetadataKey(roachpb.Key("a"))); !testutils.IsError(err, "invalid range") {
+		t.Fatalf("expected invalid range error; got %v", err)
+	}
+
+	var keys []string
+	if err := db.Iterate(MakeMVCCMetadataKey(roachpb.KeyMin), MakeMVCCMetadataKey(roachpb.KeyMax),
+		func(kv MVCCKeyValue) (bool, error) {
+			keys = append(keys, string(kv.Key.Key))
+			return false, nil
+		}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a", "d"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected keys %v; got %v", expected, keys)
+	}
+}
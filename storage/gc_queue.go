@@ -301,7 +301,18 @@ func (gcq *gcQueue) process(
 		return errors.Errorf("could not find zone config for range %s: %s", repl, err)
 	}
 
-	gcKeys, info, err := RunGC(ctx, desc, snap, now, zone.GC,
+	// Don't garbage collect history which is still needed by the holder of a
+	// protected timestamp record.
+	protected, err := loadProtectedTimestamp(repl.store.DB(), roachpb.Span{
+		Key:    desc.StartKey.AsRawKey(),
+		EndKey: desc.EndKey.AsRawKey(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not load protected timestamps for range %s", repl)
+	}
+	policy := protectGCPolicy(now, zone.GC, protected)
+
+	gcKeys, info, err := RunGC(ctx, desc, snap, now, policy,
 		func(now hlc.Timestamp, txn *roachpb.Transaction, typ roachpb.PushTxnType) {
 			pushTxn(repl, now, txn, typ)
 		},
@@ -27,6 +27,7 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/keys"
 	"github.com/cockroachdb/cockroach/roachpb"
 	"github.com/cockroachdb/cockroach/storage/engine"
@@ -336,6 +337,68 @@ func TestGCQueueProcess(t *testing.T) {
 	}
 }
 
+// TestGCQueueProtectedTimestamp verifies that the GC queue doesn't garbage
+// collect versions which are protected by a protected timestamp record, and
+// resumes once the record is released.
+func TestGCQueueProtectedTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	tc.Start(t)
+	defer tc.Stop()
+
+	const now int64 = 48 * 60 * 60 * 1E9 // 2d past the epoch
+	tc.manualClock.Set(now)
+
+	ts1 := makeTS(now-2*24*60*60*1E9+1, 0) // 2d old, past the GC TTL
+	ts2 := makeTS(now-1E9, 0)               // 1s old
+	key := roachpb.Key("a")
+	for _, ts := range []hlc.Timestamp{ts1, ts2} {
+		pArgs := putArgs(key, []byte("value"))
+		if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: ts}, &pArgs); pErr != nil {
+			t.Fatal(pErr)
+		}
+	}
+
+	var id uuid.UUID
+	if err := tc.store.DB().Txn(func(txn *client.Txn) error {
+		var err error
+		id, err = ProtectTimestamp(txn, ts1, []roachpb.Span{{Key: key, EndKey: key.PrefixEnd()}}, "test")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, ok := tc.gossip.GetSystemConfig()
+	if !ok {
+		t.Fatal("config not set")
+	}
+	gcQ := newGCQueue(tc.store, tc.gossip)
+	versions := func() int {
+		if err := gcQ.process(context.Background(), tc.clock.Now(), tc.rng, cfg); err != nil {
+			t.Fatal(err)
+		}
+		kvs, err := engine.Scan(tc.store.Engine(), engine.MakeMVCCMetadataKey(key),
+			engine.MakeMVCCMetadataKey(key.PrefixEnd()), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(kvs)
+	}
+
+	if n := versions(); n != 2 {
+		t.Errorf("expected both versions to be protected; got %d", n)
+	}
+
+	if err := tc.store.DB().Txn(func(txn *client.Txn) error {
+		return ReleaseProtectedTimestamp(txn, id)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n := versions(); n != 1 {
+		t.Errorf("expected the old version to be garbage collected; got %d versions", n)
+	}
+}
+
 func TestGCQueueTransactionTable(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
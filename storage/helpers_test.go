@@ -22,12 +22,14 @@
 package storage
 
 import (
+	"math/rand"
 	"sync/atomic"
 
 	"github.com/cockroachdb/cockroach/internal/client"
 	"github.com/cockroachdb/cockroach/roachpb"
 	"github.com/cockroachdb/cockroach/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/util/hlc"
+	"github.com/cockroachdb/cockroach/util/timeutil"
 )
 
 // HandleRaftMessage delegates to handleRaftMessage.
@@ -162,3 +164,33 @@ func (r *Replica) GetTimestampCacheLowWater() hlc.Timestamp {
 	defer r.mu.Unlock()
 	return r.mu.tsCache.lowWater
 }
+
+// ForceMergeScanAndProcess iterates over all ranges and enqueues any that
+// should be merged with their right hand neighbor and then processes them.
+func (s *Store) ForceMergeScanAndProcess() {
+	s.mu.Lock()
+	replicas := make([]*Replica, 0, len(s.mu.replicas))
+	for _, r := range s.mu.replicas {
+		replicas = append(replicas, r)
+	}
+	s.mu.Unlock()
+
+	for _, r := range replicas {
+		s.mergeQueue.MaybeAdd(r, s.ctx.Clock.Now())
+	}
+
+	s.mergeQueue.DrainQueue(s.ctx.Clock)
+}
+
+// SetQPSForTesting sets the request rate of the replica measured by its load
+// splitter until the end of the current measurement window.
+func (r *Replica) SetQPSForTesting(qps float64) {
+	r.loadSplitter.mu.Lock()
+	defer r.loadSplitter.mu.Unlock()
+	now := timeutil.Now()
+	if r.loadSplitter.mu.windowStart.IsZero() {
+		r.loadSplitter.mu.rand = rand.New(rand.NewSource(now.UnixNano()))
+	}
+	r.loadSplitter.mu.windowStart = now
+	r.loadSplitter.mu.qps = qps
+}
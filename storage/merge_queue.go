@@ -0,0 +1,146 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/config"
+	"github.com/cockroachdb/cockroach/gossip"
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/util/hlc"
+	"github.com/cockroachdb/cockroach/util/log"
+	"github.com/cockroachdb/cockroach/util/timeutil"
+)
+
+const (
+	// mergeQueueMaxSize is the max size of the merge queue.
+	mergeQueueMaxSize = 100
+	// mergeQueueTimerDuration is the duration between merges of queued ranges.
+	mergeQueueTimerDuration = 1 * time.Second
+	// mergeQueueMaxQPS is the combined request rate of two ranges above which
+	// they aren't merged, so as not to concentrate their load on one range.
+	mergeQueueMaxQPS = 100
+)
+
+// mergeQueue manages a queue of ranges slated to be merged into their right
+// hand neighbor because they have shrunk below the minimum range size of
+// their zone, e.g. after a large deletion or after their table was dropped,
+// and serve few requests.
+type mergeQueue struct {
+	baseQueue
+}
+
+// newMergeQueue returns a new instance of mergeQueue.
+func newMergeQueue(store *Store, gossip *gossip.Gossip) *mergeQueue {
+	mq := &mergeQueue{}
+	mq.baseQueue = makeBaseQueue("merge", mq, store, gossip, queueConfig{
+		maxSize:              mergeQueueMaxSize,
+		needsLease:           true,
+		acceptsUnsplitRanges: false,
+	})
+	return mq
+}
+
+// shouldQueue determines whether a range should be queued for merging. This
+// is true if the range's size in bytes is below the minimum for its zone and
+// it can be merged with its right hand neighbor: both ranges must be
+// replicated on the same stores, must not be separated by a zone config
+// split key and their combined size must stay well below the maximum range
+// size of the zone, so that the merged range isn't immediately split again.
+// Their combined request rate must also be below mergeQueueMaxQPS.
+// The priority is higher the smaller the range is.
+func (*mergeQueue) shouldQueue(now hlc.Timestamp, rng *Replica,
+	sysCfg config.SystemConfig) (shouldQ bool, priority float64) {
+
+	desc := rng.Desc()
+	if desc.EndKey.Equal(roachpb.RKeyMax) {
+		// The final range has no right hand neighbor to merge with.
+		return
+	}
+
+	zone, err := sysCfg.GetZoneConfigForKey(desc.StartKey)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	size := rng.GetMVCCStats().Total()
+	if size >= zone.RangeMinBytes {
+		return
+	}
+
+	rightRng := rng.store.LookupReplica(desc.EndKey, nil)
+	if rightRng == nil {
+		// The right hand neighbor isn't collocated on this store.
+		return
+	}
+	rightDesc := rightRng.Desc()
+	if !replicaSetsEqual(desc.Replicas, rightDesc.Replicas) {
+		return
+	}
+	if len(sysCfg.ComputeSplitKeys(desc.StartKey, rightDesc.EndKey)) > 0 {
+		return
+	}
+	if size+rightRng.GetMVCCStats().Total() >= zone.RangeMaxBytes/2 {
+		return
+	}
+	wallNow := timeutil.Now()
+	if rng.loadSplitter.qps(wallNow)+rightRng.loadSplitter.qps(wallNow) >= mergeQueueMaxQPS {
+		return
+	}
+
+	priority = 1 - float64(size)/float64(zone.RangeMinBytes)
+	return true, priority
+}
+
+// process synchronously invokes admin merge on the range if it still
+// qualifies for merging with its right hand neighbor.
+func (mq *mergeQueue) process(
+	ctx context.Context,
+	now hlc.Timestamp,
+	rng *Replica,
+	sysCfg config.SystemConfig,
+) error {
+	// The range or its neighbor may have changed since it was queued.
+	if shouldQ, _ := mq.shouldQueue(now, rng, sysCfg); !shouldQ {
+		return nil
+	}
+
+	desc := rng.Desc()
+	log.Infof("merging %s with its right hand neighbor at key %s", rng, desc.EndKey)
+	log.Trace(ctx, fmt.Sprintf("merging with right hand neighbor at key %s", desc.EndKey))
+	if _, pErr := client.SendWrappedWith(rng, ctx, roachpb.Header{
+		Timestamp: now,
+	}, &roachpb.AdminMergeRequest{
+		Span: roachpb.Span{Key: desc.StartKey.AsRawKey()},
+	}); pErr != nil {
+		return pErr.GoError()
+	}
+	return nil
+}
+
+// timer returns interval between processing successive queued merges.
+func (*mergeQueue) timer() time.Duration {
+	return mergeQueueTimerDuration
+}
+
+// purgatoryChan returns nil.
+func (*mergeQueue) purgatoryChan() <-chan struct{} {
+	return nil
+}
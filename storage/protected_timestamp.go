@@ -0,0 +1,110 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/config"
+	"github.com/cockroachdb/cockroach/internal/client"
+	"github.com/cockroachdb/cockroach/keys"
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/storage/storagebase"
+	"github.com/cockroachdb/cockroach/util/hlc"
+	"github.com/cockroachdb/cockroach/util/uuid"
+)
+
+// ProtectTimestamp writes a record which prevents the garbage collection of
+// the MVCC history of the given spans at and after the given timestamp,
+// until it is released with ReleaseProtectedTimestamp. It is intended for
+// long-running operations such as backups, which would otherwise race the
+// GC TTL of the zones they read from.
+//
+// The record only protects data which has not yet been garbage collected,
+// so the timestamp should be well within the GC TTL of the spans' zones at
+// the time the record is written.
+func ProtectTimestamp(
+	txn *client.Txn, ts hlc.Timestamp, spans []roachpb.Span, description string,
+) (uuid.UUID, error) {
+	if ts == hlc.ZeroTimestamp {
+		return uuid.UUID{}, errors.New("cannot protect zero timestamp")
+	}
+	if len(spans) == 0 {
+		return uuid.UUID{}, errors.New("no spans to protect")
+	}
+	id := uuid.MakeV4()
+	record := storagebase.ProtectedTimestampRecord{
+		Timestamp:   ts,
+		Spans:       spans,
+		Description: description,
+	}
+	if err := txn.Put(keys.ProtectedTimestampKey(id), &record); err != nil {
+		return uuid.UUID{}, err
+	}
+	return id, nil
+}
+
+// ReleaseProtectedTimestamp removes the protected timestamp record with the
+// given ID, allowing the protected data to be garbage collected.
+func ReleaseProtectedTimestamp(txn *client.Txn, id uuid.UUID) error {
+	return txn.Del(keys.ProtectedTimestampKey(id))
+}
+
+// loadProtectedTimestamp returns the earliest timestamp protected by a
+// record overlapping the given span, or hlc.ZeroTimestamp if there is none.
+func loadProtectedTimestamp(db *client.DB, span roachpb.Span) (hlc.Timestamp, error) {
+	kvs, err := db.Scan(keys.ProtectedTimestampPrefix, keys.ProtectedTimestampPrefix.PrefixEnd(), 0)
+	if err != nil {
+		return hlc.ZeroTimestamp, err
+	}
+	var earliest hlc.Timestamp
+	for _, kv := range kvs {
+		var record storagebase.ProtectedTimestampRecord
+		if err := kv.ValueProto(&record); err != nil {
+			return hlc.ZeroTimestamp, err
+		}
+		for _, s := range record.Spans {
+			if !s.Overlaps(span) {
+				continue
+			}
+			if earliest == hlc.ZeroTimestamp || record.Timestamp.Less(earliest) {
+				earliest = record.Timestamp
+			}
+			break
+		}
+	}
+	return earliest, nil
+}
+
+// protectGCPolicy returns a copy of the policy whose TTL is extended, if
+// necessary, so that the GC threshold computed from it at now does not
+// exceed the protected timestamp.
+func protectGCPolicy(now hlc.Timestamp, policy config.GCPolicy, protected hlc.Timestamp) config.GCPolicy {
+	if protected == hlc.ZeroTimestamp {
+		return policy
+	}
+	ttlNanos := int64(policy.TTLSeconds) * 1E9
+	if now.WallTime-ttlNanos <= protected.WallTime {
+		return policy
+	}
+	ttlSeconds := (now.WallTime - protected.WallTime + 1E9 - 1) / 1E9
+	if ttlSeconds > math.MaxInt32 {
+		ttlSeconds = math.MaxInt32
+	}
+	policy.TTLSeconds = int32(ttlSeconds)
+	return policy
+}
@@ -49,6 +49,7 @@ import (
 	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/log"
 	"github.com/cockroachdb/cockroach/util/protoutil"
+	"github.com/cockroachdb/cockroach/util/timeutil"
 	"github.com/cockroachdb/cockroach/util/tracing"
 	"github.com/cockroachdb/cockroach/util/uuid"
 )
@@ -134,6 +135,9 @@ var updatesTimestampCacheMethods = [...]bool{
 	// replays. Replays for the same transaction key and timestamp will
 	// have Txn.WriteTooOld=true and must retry on EndTransaction.
 	roachpb.EndTransaction: true,
+	// RefreshRange reads the span at the timestamp the transaction was
+	// pushed to, which later writes must not go below.
+	roachpb.RefreshRange: true,
 }
 
 func updatesTimestampCache(r roachpb.Request) bool {
@@ -202,6 +206,10 @@ type Replica struct {
 	// updates to state.Desc should be duplicated here
 	rangeDesc atomic.Value
 
+	// loadSplitter tracks the request load of the replica in order to split
+	// ranges which receive a sustained high rate of requests.
+	loadSplitter loadSplitter
+
 	mu struct {
 		// Protects all fields in the mu struct.
 		sync.Mutex
@@ -823,6 +831,10 @@ func (r *Replica) Send(ctx context.Context, ba roachpb.BatchRequest) (*roachpb.B
 	ctx, cleanup := tracing.EnsureContext(ctx, r.store.Tracer())
 	defer cleanup()
 
+	if ba.IsWrite() || ba.IsReadOnly() {
+		r.recordLoad(ba)
+	}
+
 	// Differentiate between admin, read-only and write.
 	var pErr *roachpb.Error
 	if ba.IsWrite() {
@@ -2625,6 +2637,25 @@ func (r *Replica) exceedsDoubleSplitSizeLocked() bool {
 	return maxBytes > 0 && size > maxBytes*2
 }
 
+// recordLoad records the keys touched by the batch in the replica's load
+// splitter and adds the replica to the split queue if its load warrants a
+// split. The request rate is tracked even if load-based splitting is
+// disabled, since it is used to balance load across stores.
+func (r *Replica) recordLoad(ba roachpb.BatchRequest) {
+	threshold := r.store.ctx.LoadSplitQPSThreshold
+	splitEnabled := threshold > 0 && r.store.splitQueue != nil
+	if !splitEnabled {
+		threshold = math.MaxInt32
+	}
+	span, err := keys.Range(ba)
+	if err != nil {
+		return
+	}
+	if r.loadSplitter.record(timeutil.Now(), span, threshold) && splitEnabled {
+		r.store.splitQueue.MaybeAdd(r, r.store.Clock().Now())
+	}
+}
+
 // maybeAddToSplitQueue checks whether the current size of the range
 // exceeds the max size specified in the zone config. If yes, the
 // range is added to the split queue.
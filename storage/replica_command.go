@@ -24,7 +24,10 @@ import (
 	"crypto/sha512"
 	"encoding/binary"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
 	"reflect"
 	"time"
 
@@ -43,6 +46,8 @@ import (
 	"github.com/cockroachdb/cockroach/util/hlc"
 	"github.com/cockroachdb/cockroach/util/log"
 	"github.com/cockroachdb/cockroach/util/protoutil"
+	"github.com/cockroachdb/cockroach/util/retry"
+	"github.com/cockroachdb/cockroach/util/stop"
 	"github.com/cockroachdb/cockroach/util/timeutil"
 	"github.com/cockroachdb/cockroach/util/uuid"
 )
@@ -164,6 +169,18 @@ func (r *Replica) executeCmd(ctx context.Context, raftCmdID storagebase.CmdIDKey
 	case *roachpb.ChangeFrozenRequest:
 		resp := reply.(*roachpb.ChangeFrozenResponse)
 		*resp, err = r.ChangeFrozen(ctx, batch, ms, h, *tArgs)
+	case *roachpb.ExportRequest:
+		resp := reply.(*roachpb.ExportResponse)
+		*resp, err = r.Export(ctx, batch, h, *tArgs)
+	case *roachpb.AddSSTableRequest:
+		resp := reply.(*roachpb.AddSSTableResponse)
+		*resp, err = r.AddSSTable(ctx, batch, ms, h, *tArgs)
+	case *roachpb.ClearRangeRequest:
+		resp := reply.(*roachpb.ClearRangeResponse)
+		*resp, err = r.ClearRange(ctx, batch, ms, h, *tArgs)
+	case *roachpb.RefreshRangeRequest:
+		resp := reply.(*roachpb.RefreshRangeResponse)
+		*resp, err = r.RefreshRange(ctx, batch, h, *tArgs)
 	default:
 		err = errors.Errorf("unrecognized command %s", args.Method())
 	}
@@ -1738,12 +1755,39 @@ func (r *Replica) CheckConsistency(
 		}
 	}
 
-	return roachpb.CheckConsistencyResponse{}, nil
+	// Collect the checksums of the other replicas, so that the caller can see
+	// which of them diverge from the lease holder.
+	result := roachpb.CheckConsistencyResponse_Result{
+		RangeID:  desc.RangeID,
+		StartKey: desc.StartKey,
+		Checksum: c.checksum,
+	}
+	for _, replica := range desc.Replicas {
+		rc := roachpb.CheckConsistencyResponse_ReplicaChecksum{Replica: replica}
+		if replica.StoreID == r.store.StoreID() {
+			rc.Checksum = c.checksum
+		} else {
+			collectCtx, cancel := context.WithTimeout(ctx, collectChecksumTimeout)
+			checksum, err := r.store.ctx.StorePool.collectChecksum(collectCtx, replica, desc.RangeID, id)
+			cancel()
+			if err != nil {
+				log.Warningf("%s: unable to collect the checksum of replica %s: %s", r, replica, err)
+			}
+			rc.Checksum = checksum
+		}
+		result.Replicas = append(result.Replicas, rc)
+	}
+	return roachpb.CheckConsistencyResponse{
+		Results: []roachpb.CheckConsistencyResponse_Result{result},
+	}, nil
 }
 
 const (
 	replicaChecksumVersion    = 1
 	replicaChecksumGCInterval = time.Hour
+	// collectChecksumTimeout bounds the wait for the checksum of a replica
+	// other than the lease holder.
+	collectChecksumTimeout = time.Minute
 )
 
 // getChecksum waits for the result of ComputeChecksum and returns it.
@@ -1768,6 +1812,27 @@ func (r *Replica) getChecksum(id uuid.UUID) (replicaChecksum, bool) {
 	return c, ok
 }
 
+// collectChecksum returns the checksum computed for id. The replica may not
+// have started to compute it yet if it lags behind the lease holder, in which
+// case it waits until it has, or until ctx is done.
+func (r *Replica) collectChecksum(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	opts := retry.Options{
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Closer:         ctx.Done(),
+	}
+	for re := retry.Start(opts); re.Next(); {
+		if c, ok := r.getChecksum(id); ok {
+			if c.checksum == nil {
+				return nil, errors.Errorf("%s: unable to compute checksum for id = %v", r, id)
+			}
+			return c.checksum, nil
+		}
+	}
+	return nil, errors.Errorf("%s: no checksum for id = %v", r, id)
+}
+
 // computeChecksumDone adds the computed checksum, sets a deadline for GCing the
 // checksum, and sends out a notification.
 func (r *Replica) computeChecksumDone(id uuid.UUID, sha []byte, snapshot *roachpb.RaftSnapshotData) {
@@ -2051,6 +2116,280 @@ func (r *Replica) ChangeFrozen(
 	return resp, setFrozenStatus(batch, ms, r.Desc().RangeID, args.Frozen)
 }
 
+// Export writes the latest version of each key in the requested span, as of
+// the request timestamp, to an SSTable in the requested storage. If a
+// StartTime is given, only versions written after it are exported (including
+// deletions), which is suitable for an incremental export. An empty export
+// produces no file.
+func (r *Replica) Export(
+	ctx context.Context, batch engine.ReadWriter, h roachpb.Header, args roachpb.ExportRequest,
+) (roachpb.ExportResponse, error) {
+	var reply roachpb.ExportResponse
+	if args.Storage.LocalFile.Path == "" {
+		return reply, errors.Errorf("no export storage specified")
+	}
+
+	var iter engine.Iterator
+	if args.StartTime != hlc.ZeroTimestamp {
+		// A time-bound iterator may surface intents which have since been
+		// resolved; those are verified against the batch below.
+		iter = batch.NewTimeBoundIterator(args.StartTime.Next(), h.Timestamp)
+	} else {
+		iter = batch.NewIterator(false)
+	}
+	defer iter.Close()
+
+	path := filepath.Join(args.Storage.LocalFile.Path,
+		fmt.Sprintf("%d-%s.sst", r.RangeID, uuid.NewV4()))
+	sst := engine.MakeRocksDBSstFileWriter()
+	defer func() {
+		_ = sst.Close()
+	}()
+	if err := sst.Open(path); err != nil {
+		return reply, err
+	}
+
+	endKey := engine.MakeMVCCMetadataKey(args.EndKey)
+	for iter.Seek(engine.MakeMVCCMetadataKey(args.Key)); iter.Valid(); {
+		key := iter.Key()
+		if !key.Less(endKey) {
+			break
+		}
+		if !key.IsValue() {
+			var meta enginepb.MVCCMetadata
+			if err := iter.ValueProto(&meta); err != nil {
+				return reply, err
+			}
+			if meta.Txn != nil {
+				// Re-read the intent through the batch since it may be stale.
+				ok, _, _, err := batch.GetProto(key, &meta)
+				if err != nil {
+					return reply, err
+				}
+				if ok && meta.Txn != nil && !h.Timestamp.Less(meta.Timestamp) {
+					return reply, &roachpb.WriteIntentError{Intents: []roachpb.Intent{
+						{Span: roachpb.Span{Key: key.Key}, Status: roachpb.PENDING, Txn: *meta.Txn},
+					}}
+				}
+			} else if meta.IsInline() && args.StartTime == hlc.ZeroTimestamp {
+				if err := sst.Add(engine.MVCCKeyValue{Key: key, Value: iter.Value()}); err != nil {
+					return reply, err
+				}
+			}
+			iter.Next()
+			continue
+		}
+		if h.Timestamp.Less(key.Timestamp) {
+			// Skip versions newer than the export timestamp.
+			iter.Next()
+			continue
+		}
+		// This is the latest version as of the export timestamp. Deletions are
+		// only of interest to incremental exports.
+		if args.StartTime.Less(key.Timestamp) &&
+			(len(iter.Value()) > 0 || args.StartTime != hlc.ZeroTimestamp) {
+			if err := sst.Add(engine.MVCCKeyValue{Key: key, Value: iter.Value()}); err != nil {
+				return reply, err
+			}
+		}
+		iter.NextKey()
+	}
+	if err := iter.Error(); err != nil {
+		return reply, err
+	}
+
+	dataSize := sst.DataSize
+	if err := sst.Close(); err != nil {
+		return reply, err
+	}
+	if dataSize == 0 {
+		return reply, os.Remove(path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return reply, err
+	}
+	sum := sha512.Sum512(data)
+	reply.Files = []roachpb.ExportResponse_File{{
+		Span:     roachpb.Span{Key: args.Key, EndKey: args.EndKey},
+		Path:     path,
+		Sha512:   sum[:],
+		DataSize: dataSize,
+	}}
+	return reply, nil
+}
+
+// AddSSTable links the SSTable in the request directly into the replica's
+// engine. Like any other write, the command is proposed to Raft and executed
+// by every replica as it applies it, but the ingestion itself can't be part of
+// the command's batch: it is deferred until the batch, which records the stats
+// of the ingested data along with the applied index, has been committed. The
+// span must not contain any keys yet, since the engine places the file below
+// any existing data.
+func (r *Replica) AddSSTable(
+	ctx context.Context,
+	batch engine.ReadWriter,
+	ms *enginepb.MVCCStats,
+	h roachpb.Header,
+	args roachpb.AddSSTableRequest,
+) (roachpb.AddSSTableResponse, error) {
+	var reply roachpb.AddSSTableResponse
+	if len(args.Data) == 0 {
+		return reply, errors.Errorf("empty sstable")
+	}
+
+	start := engine.MakeMVCCMetadataKey(args.Key)
+	end := engine.MakeMVCCMetadataKey(args.EndKey)
+	iter := batch.NewIterator(false)
+	iter.Seek(start)
+	nonEmpty := iter.Valid() && iter.Key().Less(end)
+	iter.Close()
+	if nonEmpty {
+		return reply, errors.Errorf("cannot ingest sstable into non-empty span %s", args.Span)
+	}
+
+	// The stats of the ingested data are computed from a scratch engine, so
+	// that every replica adds the same stats to the range's.
+	stats, err := sstableStats(args, h.Timestamp.WallTime)
+	if err != nil {
+		return reply, err
+	}
+	ms.Add(stats)
+
+	data := args.Data
+	batch.(engine.Batch).Defer(func() {
+		if err := r.store.Engine().IngestExternalFile(data); err != nil {
+			log.Fatalc(ctx, "%s: unable to ingest sstable: %s", r, err)
+		}
+	})
+	return reply, nil
+}
+
+// sstableStats returns the stats of the data of an AddSSTable request.
+func sstableStats(args roachpb.AddSSTableRequest, nowNanos int64) (enginepb.MVCCStats, error) {
+	stopper := stop.NewStopper()
+	defer stopper.Stop()
+	scratch := engine.NewInMem(roachpb.Attributes{}, 1<<20, stopper)
+	if err := scratch.IngestExternalFile(args.Data); err != nil {
+		return enginepb.MVCCStats{}, err
+	}
+	iter := scratch.NewIterator(false)
+	defer iter.Close()
+	stats, err := iter.ComputeStats(engine.MVCCKey{Key: roachpb.KeyMin}, engine.MVCCKey{Key: roachpb.KeyMax}, nowNanos)
+	if err != nil {
+		return enginepb.MVCCStats{}, err
+	}
+	// The data outside of the span would not be accounted for by the range.
+	iter.Seek(engine.MVCCKey{Key: roachpb.KeyMin})
+	if iter.Valid() && bytes.Compare(iter.Key().Key, args.Key) < 0 {
+		return enginepb.MVCCStats{}, errors.Errorf("sstable key %s outside of span %s", iter.Key().Key, args.Span)
+	}
+	iter.Seek(engine.MakeMVCCMetadataKey(args.EndKey))
+	if iter.Valid() {
+		return enginepb.MVCCStats{}, errors.Errorf("sstable key %s outside of span %s", iter.Key().Key, args.Span)
+	}
+	return stats, nil
+}
+
+// ClearRange removes all of the data in the span, including all MVCC
+// versions and intents, using a single range tombstone. As with AddSSTable,
+// the tombstone can't be part of the command's batch: it is written once the
+// batch applying the Raft command, which records the updated stats, has been
+// committed.
+func (r *Replica) ClearRange(
+	ctx context.Context,
+	batch engine.ReadWriter,
+	ms *enginepb.MVCCStats,
+	h roachpb.Header,
+	args roachpb.ClearRangeRequest,
+) (roachpb.ClearRangeResponse, error) {
+	var reply roachpb.ClearRangeResponse
+	if bytes.Compare(args.Key, keys.LocalMax) < 0 {
+		return reply, errors.Errorf("cannot clear range-local span %s", args.Span)
+	}
+
+	// The stats for the span are subtracted from the range's stats. Computing
+	// them requires a scan of the span, which is still far cheaper than
+	// writing a deletion for every key.
+	start := engine.MakeMVCCMetadataKey(args.Key)
+	end := engine.MakeMVCCMetadataKey(args.EndKey)
+	iter := batch.NewIterator(false)
+	defer iter.Close()
+	stats, err := iter.ComputeStats(start, end, h.Timestamp.WallTime)
+	if err != nil {
+		return reply, err
+	}
+	ms.Subtract(stats)
+
+	batch.(engine.Batch).Defer(func() {
+		if err := r.store.Engine().ClearRange(start, end); err != nil {
+			log.Fatalc(ctx, "%s: unable to clear span %s: %s", r, args.Span, err)
+		}
+	})
+	return reply, nil
+}
+
+// RefreshRange returns an error if a value was written in the span at a
+// timestamp after the one at which the transaction read it and no later than
+// the batch timestamp, which is the timestamp the transaction was pushed to.
+// Intents of other transactions at those timestamps fail the refresh as well,
+// since they may still commit. The intents of the transaction itself are
+// ignored. As a read, a successful refresh updates the timestamp cache with
+// the batch timestamp, preventing later writes below it.
+func (r *Replica) RefreshRange(
+	ctx context.Context,
+	batch engine.ReadWriter,
+	h roachpb.Header,
+	args roachpb.RefreshRangeRequest,
+) (roachpb.RefreshRangeResponse, error) {
+	var reply roachpb.RefreshRangeResponse
+	if h.Txn == nil {
+		return reply, errTransactionUnsupported
+	}
+
+	iter := batch.NewIterator(false)
+	defer iter.Close()
+	end := engine.MakeMVCCMetadataKey(args.EndKey)
+	var meta enginepb.MVCCMetadata
+	// The key and timestamp of the provisional value of the last intent of
+	// the transaction, which are skipped.
+	var intentKey roachpb.Key
+	var intentTS hlc.Timestamp
+	for iter.Seek(engine.MakeMVCCMetadataKey(args.Key)); iter.Valid(); iter.Next() {
+		key := iter.Key()
+		if !key.Less(end) {
+			break
+		}
+		if !key.IsValue() {
+			if err := iter.ValueProto(&meta); err != nil {
+				return reply, err
+			}
+			if meta.Txn == nil {
+				// Inline values are not versioned.
+				continue
+			}
+			if roachpb.TxnIDEqual(meta.Txn.ID, h.Txn.ID) {
+				intentKey, intentTS = key.Key, meta.Timestamp
+				continue
+			}
+			if !h.Timestamp.Less(meta.Timestamp) {
+				return reply, errors.Errorf("encountered intent of transaction %s on key %s",
+					meta.Txn.ID.Short(), key.Key)
+			}
+			continue
+		}
+		if key.Timestamp.Equal(intentTS) && key.Key.Equal(intentKey) {
+			continue
+		}
+		if args.Timestamp.Less(key.Timestamp) && !h.Timestamp.Less(key.Timestamp) {
+			return reply, errors.Errorf("encountered recently written key %s @%s",
+				key.Key, key.Timestamp)
+		}
+	}
+	return reply, iter.Error()
+}
+
 // ReplicaSnapshotDiff is a part of a []ReplicaSnapshotDiff which represents a diff between
 // two replica snapshots. For now it's only a diff between their KV pairs.
 type ReplicaSnapshotDiff struct {
@@ -0,0 +1,167 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/roachpb"
+)
+
+const (
+	// loadSplitSampleSize is the number of request keys which are sampled
+	// as split key candidates during each measurement window.
+	loadSplitSampleSize = 20
+	// loadSplitWindow is the duration over which requests are counted. A range
+	// is only split due to load if its request rate exceeded the threshold for
+	// an entire window.
+	loadSplitWindow = 10 * time.Second
+	// loadSplitMaxImbalance is the maximum allowed difference between the
+	// fraction of requests on either side of a split key candidate.
+	loadSplitMaxImbalance = 0.5
+)
+
+// splitKeySample is a split key candidate along with the number of requests
+// that fell entirely to its left, to its right, or spanned it.
+type splitKeySample struct {
+	key                    roachpb.RKey
+	left, right, contained int
+}
+
+// loadSplitter tracks the rate of requests served by a replica as well as the
+// distribution of the keys they touch in order to find a key which splits
+// the load of a hot range roughly in half. The measured request rate is also
+// reported in the store's capacity to allow the allocator to balance load.
+type loadSplitter struct {
+	mu struct {
+		sync.Mutex
+		rand *rand.Rand
+		// windowStart is the start of the current measurement window.
+		windowStart time.Time
+		// count is the number of requests recorded in the current window.
+		count   int
+		samples []splitKeySample
+		// qps is the request rate measured over the last complete window.
+		qps float64
+		// splitKey is the key chosen at the end of the last window, or nil if
+		// the request rate was below the threshold or no key balanced the load.
+		splitKey roachpb.RKey
+	}
+}
+
+// record registers a request touching the given span at time now. At the
+// end of each measurement window, a split key is chosen if the request rate
+// during the window exceeded qpsThreshold. Returns true if a split key was
+// chosen by this call.
+func (ls *loadSplitter) record(now time.Time, span roachpb.RSpan, qpsThreshold int) bool {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.mu.windowStart.IsZero() {
+		ls.mu.windowStart = now
+		ls.mu.rand = rand.New(rand.NewSource(now.UnixNano()))
+	}
+	var chosen bool
+	if elapsed := now.Sub(ls.mu.windowStart); elapsed >= loadSplitWindow {
+		ls.mu.splitKey = nil
+		ls.mu.qps = float64(ls.mu.count) / elapsed.Seconds()
+		if ls.mu.qps >= float64(qpsThreshold) {
+			ls.mu.splitKey = ls.bestSplitKeyLocked()
+			chosen = ls.mu.splitKey != nil
+		}
+		ls.mu.windowStart = now
+		ls.mu.count = 0
+		ls.mu.samples = ls.mu.samples[:0]
+	}
+
+	ls.mu.count++
+	// Reservoir sampling of the request start keys.
+	if len(ls.mu.samples) < loadSplitSampleSize {
+		ls.mu.samples = append(ls.mu.samples, splitKeySample{key: span.Key})
+	} else if i := ls.mu.rand.Intn(ls.mu.count); i < loadSplitSampleSize {
+		ls.mu.samples[i] = splitKeySample{key: span.Key}
+	}
+
+	for i := range ls.mu.samples {
+		s := &ls.mu.samples[i]
+		switch {
+		case span.Key.Less(s.key) && (len(span.EndKey) == 0 || !s.key.Less(span.EndKey)):
+			s.left++
+		case !span.Key.Less(s.key):
+			s.right++
+		default:
+			s.contained++
+		}
+	}
+	return chosen
+}
+
+// bestSplitKeyLocked returns the sampled key which most evenly divides the
+// requests recorded during the current window, or nil if no key divides them
+// evenly enough. ls.mu must be held.
+func (ls *loadSplitter) bestSplitKeyLocked() roachpb.RKey {
+	var splitKey roachpb.RKey
+	bestImbalance := loadSplitMaxImbalance
+	for _, s := range ls.mu.samples {
+		total := s.left + s.right + s.contained
+		if s.left == 0 || s.right == 0 || s.contained > total/2 {
+			continue
+		}
+		imbalance := math.Abs(float64(s.left-s.right)) / float64(total)
+		if imbalance < bestImbalance {
+			bestImbalance = imbalance
+			splitKey = s.key
+		}
+	}
+	return splitKey
+}
+
+// splitKey returns the key at which the replica should be split to divide
+// its load, or nil if no split is needed.
+func (ls *loadSplitter) splitKey() roachpb.RKey {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.mu.splitKey
+}
+
+// qps returns the request rate of the replica as of now, measured over the
+// last complete window.
+func (ls *loadSplitter) qps(now time.Time) float64 {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.mu.windowStart.IsZero() {
+		return 0
+	}
+	if elapsed := now.Sub(ls.mu.windowStart); elapsed >= loadSplitWindow {
+		// No request has been recorded since the current window ended, so the
+		// rate measured during the last window is stale.
+		return float64(ls.mu.count) / elapsed.Seconds()
+	}
+	return ls.mu.qps
+}
+
+// reset discards all recorded requests and the current split key.
+func (ls *loadSplitter) reset() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.mu.windowStart = time.Time{}
+	ls.mu.count = 0
+	ls.mu.samples = ls.mu.samples[:0]
+	ls.mu.qps = 0
+	ls.mu.splitKey = nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2016 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/roachpb"
+	"github.com/cockroachdb/cockroach/util/leaktest"
+)
+
+// TestLoadSplitterSplitKey verifies that a split key is only chosen when the
+// request rate sustained over a window exceeds the threshold, and that the
+// chosen key divides the requests roughly in half.
+func TestLoadSplitterSplitKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	key := func(i int) roachpb.RKey {
+		return roachpb.RKey(fmt.Sprintf("k%03d", i))
+	}
+
+	testCases := []struct {
+		// requests is the number of requests per window, spread uniformly over
+		// the keys k000-k099.
+		requests  int
+		threshold int
+		split     bool
+	}{
+		{requests: 100, threshold: 100, split: false},
+		{requests: 1000, threshold: 100, split: true},
+		{requests: 1000, threshold: 0, split: true},
+	}
+
+	for i, test := range testCases {
+		var ls loadSplitter
+		start := time.Unix(0, 0)
+		// Record two windows' worth of requests; the split key is only chosen
+		// at the end of the first window.
+		var chosen bool
+		for j := 0; j <= 2*test.requests; j++ {
+			now := start.Add(time.Duration(j) * loadSplitWindow / time.Duration(test.requests))
+			if ls.record(now, roachpb.RSpan{Key: key(j % 100)}, test.threshold) {
+				chosen = true
+			}
+		}
+		splitKey := ls.splitKey()
+		if (splitKey != nil) != test.split || chosen != test.split {
+			t.Errorf("%d: expected split %t; got key %s (chosen %t)", i, test.split, splitKey, chosen)
+			continue
+		}
+		if test.split && (splitKey.Less(key(25)) || key(75).Less(splitKey)) {
+			t.Errorf("%d: expected split key near the middle of the keyspace; got %s", i, splitKey)
+		}
+
+		ls.reset()
+		if splitKey := ls.splitKey(); splitKey != nil {
+			t.Errorf("%d: expected no split key after reset; got %s", i, splitKey)
+		}
+	}
+}
+
+// TestLoadSplitterSingleKey verifies that no split key is chosen if all
+// requests are for the same key, since no split could divide the load.
+func TestLoadSplitterSingleKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var ls loadSplitter
+	start := time.Unix(0, 0)
+	for j := 0; j <= 2000; j++ {
+		now := start.Add(time.Duration(j) * loadSplitWindow / 1000)
+		ls.record(now, roachpb.RSpan{Key: roachpb.RKey("a")}, 10)
+	}
+	if splitKey := ls.splitKey(); splitKey != nil {
+		t.Errorf("expected no split key; got %s", splitKey)
+	}
+}
+
+// TestLoadSplitterQPS verifies that the request rate is measured over the
+// last complete window and decays once requests stop arriving.
+func TestLoadSplitterQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var ls loadSplitter
+	start := time.Unix(0, 0)
+	if qps := ls.qps(start); qps != 0 {
+		t.Errorf("expected no requests before the first record; got %f", qps)
+	}
+	// Record 100 requests per second for one window. The request recorded at
+	// the end of the window closes it.
+	requests := 100 * int(loadSplitWindow/time.Second)
+	for j := 0; j <= requests; j++ {
+		now := start.Add(time.Duration(j) * loadSplitWindow / time.Duration(requests))
+		ls.record(now, roachpb.RSpan{Key: roachpb.RKey("a")}, 0)
+	}
+	end := start.Add(loadSplitWindow)
+	if qps := ls.qps(end); qps != 100 {
+		t.Errorf("expected 100 qps; got %f", qps)
+	}
+	// Without further requests, the rate decays once the next window ends.
+	if qps := ls.qps(end.Add(10 * loadSplitWindow)); qps >= 1 {
+		t.Errorf("expected the request rate to decay; got %f", qps)
+	}
+
+	ls.reset()
+	if qps := ls.qps(end); qps != 0 {
+		t.Errorf("expected no requests after reset; got %f", qps)
+	}
+}
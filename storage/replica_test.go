@@ -18,9 +18,13 @@ package storage
 
 import (
 	"bytes"
+	"crypto/sha512"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
@@ -6263,3 +6267,267 @@ func TestReserveAndApplySnapshot(t *testing.T) {
 	}
 	checkReservations(t, 0)
 }
+
+// TestReplicaExport verifies that ExportRequest writes the requested span to
+// an SSTable and that incremental exports only include newer versions.
+func TestReplicaExport(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	tc.Start(t)
+	defer tc.Stop()
+
+	dir := util.CreateTempDir(t, "export")
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	ts1 := makeTS(1, 0)
+	ts2 := makeTS(2, 0)
+	ts3 := makeTS(3, 0)
+
+	for _, key := range []string{"a", "b"} {
+		pArgs := putArgs(roachpb.Key(key), []byte("value"))
+		if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: ts1}, &pArgs); pErr != nil {
+			t.Fatal(pErr)
+		}
+	}
+	dArgs := deleteArgs(roachpb.Key("a"))
+	if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: ts2}, &dArgs); pErr != nil {
+		t.Fatal(pErr)
+	}
+
+	export := func(startTime hlc.Timestamp) []roachpb.ExportResponse_File {
+		args := roachpb.ExportRequest{
+			Span:      roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("c")},
+			Storage:   roachpb.ExportStorage{LocalFile: roachpb.ExportStorage_LocalFilePath{Path: dir}},
+			StartTime: startTime,
+		}
+		reply, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: ts3}, &args)
+		if pErr != nil {
+			t.Fatal(pErr)
+		}
+		files := reply.(*roachpb.ExportResponse).Files
+		for _, f := range files {
+			data, err := ioutil.ReadFile(f.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sum := sha512.Sum512(data); !bytes.Equal(sum[:], f.Sha512) {
+				t.Errorf("checksum mismatch for %s", f.Path)
+			}
+			if f.DataSize <= 0 {
+				t.Errorf("expected positive data size for %s; got %d", f.Path, f.DataSize)
+			}
+		}
+		return files
+	}
+
+	// A full export contains only the live key "b".
+	full := export(hlc.ZeroTimestamp)
+	if len(full) != 1 {
+		t.Fatalf("expected 1 file; got %+v", full)
+	}
+	// An incremental export since ts1 contains the deletion of "a", which is
+	// smaller than the full export's live value.
+	incr := export(ts1)
+	if len(incr) != 1 {
+		t.Fatalf("expected 1 file; got %+v", incr)
+	}
+	if incr[0].DataSize >= full[0].DataSize {
+		t.Errorf("expected incremental export to be smaller: %d >= %d", incr[0].DataSize, full[0].DataSize)
+	}
+	// Nothing was written after ts3.
+	if files := export(ts3); len(files) != 0 {
+		t.Errorf("expected no files; got %+v", files)
+	}
+}
+
+// TestReplicaAddSSTable verifies that the keys in an ingested SSTable are
+// readable and accounted for in the range's stats.
+func TestReplicaAddSSTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	tc.Start(t)
+	defer tc.Stop()
+
+	dir := util.CreateTempDir(t, "sstable")
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	ts := makeTS(1, 0)
+	path := filepath.Join(dir, "data.sst")
+	sst := engine.MakeRocksDBSstFileWriter()
+	if err := sst.Open(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"sst-a", "sst-b"} {
+		value := roachpb.MakeValueFromString(key)
+		value.InitChecksum(roachpb.Key(key))
+		kv := engine.MVCCKeyValue{
+			Key:   engine.MVCCKey{Key: roachpb.Key(key), Timestamp: ts},
+			Value: value.RawBytes,
+		}
+		if err := sst.Add(kv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sst.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	liveCount := tc.rng.GetMVCCStats().LiveCount
+	args := roachpb.AddSSTableRequest{
+		Span: roachpb.Span{Key: roachpb.Key("sst-a"), EndKey: roachpb.Key("sst-c")},
+		Data: data,
+	}
+	if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: makeTS(2, 0)}, &args); pErr != nil {
+		t.Fatal(pErr)
+	}
+
+	gArgs := getArgs(roachpb.Key("sst-b"))
+	reply, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: makeTS(3, 0)}, &gArgs)
+	if pErr != nil {
+		t.Fatal(pErr)
+	}
+	if v := reply.(*roachpb.GetResponse).Value; v == nil {
+		t.Fatal("expected ingested key to be readable")
+	} else if b, err := v.GetBytes(); err != nil || string(b) != "sst-b" {
+		t.Fatalf("unexpected value %q (err=%v)", b, err)
+	}
+	if delta := tc.rng.GetMVCCStats().LiveCount - liveCount; delta != 2 {
+		t.Errorf("expected 2 new live keys; got %d", delta)
+	}
+
+	// The span of the ingested data must be empty.
+	if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: makeTS(4, 0)}, &args); !testutils.IsPError(pErr, "non-empty span") {
+		t.Fatalf("expected a non-empty span error; got %v", pErr)
+	}
+}
+
+// TestReplicaClearRange verifies that ClearRange removes all versions of the
+// keys in the span and updates the range's stats accordingly.
+func TestReplicaClearRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	tc.Start(t)
+	defer tc.Stop()
+
+	for i, key := range []string{"clear-a", "clear-b", "clear-b", "clear-c"} {
+		pArgs := putArgs(roachpb.Key(key), []byte("value"))
+		if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: makeTS(int64(i+1), 0)}, &pArgs); pErr != nil {
+			t.Fatal(pErr)
+		}
+	}
+
+	ms := tc.rng.GetMVCCStats()
+	args := roachpb.ClearRangeRequest{
+		Span: roachpb.Span{Key: roachpb.Key("clear-a"), EndKey: roachpb.Key("clear-c")},
+	}
+	if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: makeTS(10, 0)}, &args); pErr != nil {
+		t.Fatal(pErr)
+	}
+
+	for _, test := range []struct {
+		key    string
+		exists bool
+	}{
+		{"clear-a", false},
+		{"clear-b", false},
+		{"clear-c", true},
+	} {
+		// Read at an old timestamp to verify that no versions are left behind.
+		gArgs := getArgs(roachpb.Key(test.key))
+		reply, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: makeTS(5, 0)}, &gArgs)
+		if pErr != nil {
+			t.Fatal(pErr)
+		}
+		if exists := reply.(*roachpb.GetResponse).Value != nil; exists != test.exists {
+			t.Errorf("%s: expected exists=%t; got %t", test.key, test.exists, exists)
+		}
+	}
+
+	newMS := tc.rng.GetMVCCStats()
+	if delta := ms.LiveCount - newMS.LiveCount; delta != 2 {
+		t.Errorf("expected 2 fewer live keys; got %d", delta)
+	}
+	if delta := ms.ValCount - newMS.ValCount; delta != 3 {
+		t.Errorf("expected 3 fewer values; got %d", delta)
+	}
+}
+
+// TestReplicaRefreshRange verifies that RefreshRange fails if a value was
+// written or an intent of another transaction was laid down in the span
+// since the timestamp it refreshes from, ignoring the intents of the
+// refreshing transaction.
+func TestReplicaRefreshRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	tc.Start(t)
+	defer tc.Stop()
+
+	for _, kv := range []struct {
+		key string
+		ts  hlc.Timestamp
+	}{
+		{"refresh-a", makeTS(5, 0)},
+		{"refresh-b", makeTS(15, 0)},
+	} {
+		pArgs := putArgs(roachpb.Key(kv.key), []byte("value"))
+		if _, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: kv.ts}, &pArgs); pErr != nil {
+			t.Fatal(pErr)
+		}
+	}
+
+	writeIntent := func(txn *roachpb.Transaction, key string, ts hlc.Timestamp) {
+		txn.OrigTimestamp, txn.Timestamp = ts, ts
+		txn.Sequence++
+		pArgs := putArgs(roachpb.Key(key), []byte("value"))
+		if _, pErr := maybeWrapWithBeginTransaction(tc.Sender(), context.Background(),
+			roachpb.Header{Txn: txn}, &pArgs); pErr != nil {
+			t.Fatal(pErr)
+		}
+		txn.Writing = true
+	}
+	txn := newTransaction("test", roachpb.Key("refresh-d"), 1, enginepb.SERIALIZABLE, tc.clock)
+	writeIntent(txn, "refresh-d", makeTS(14, 0))
+	otherTxn := newTransaction("other", roachpb.Key("refresh-c"), 1, enginepb.SERIALIZABLE, tc.clock)
+	writeIntent(otherTxn, "refresh-c", makeTS(12, 0))
+
+	refreshTS := makeTS(20, 0)
+	txn.OrigTimestamp, txn.Timestamp = refreshTS, refreshTS
+	for i, test := range []struct {
+		start, end string
+		from       hlc.Timestamp
+		expErr     string
+	}{
+		{"refresh-a", "refresh-b", makeTS(1, 0), "encountered recently written key"},
+		{"refresh-a", "refresh-b", makeTS(10, 0), ""},
+		{"refresh-b", "refresh-c", makeTS(10, 0), "encountered recently written key"},
+		{"refresh-b", "refresh-c", makeTS(15, 0), ""},
+		{"refresh-c", "refresh-d", makeTS(15, 0), "encountered intent of transaction"},
+		{"refresh-d", "refresh-e", makeTS(10, 0), ""},
+	} {
+		txn.Sequence++
+		args := roachpb.RefreshRangeRequest{
+			Span:      roachpb.Span{Key: roachpb.Key(test.start), EndKey: roachpb.Key(test.end)},
+			Timestamp: test.from,
+		}
+		_, pErr := tc.SendWrappedWith(roachpb.Header{Timestamp: refreshTS, Txn: txn}, &args)
+		if test.expErr == "" {
+			if pErr != nil {
+				t.Errorf("%d: unexpected error: %s", i, pErr)
+			}
+		} else if !testutils.IsPError(pErr, test.expErr) {
+			t.Errorf("%d: expected error %q; got %v", i, test.expErr, pErr)
+		}
+	}
+}
@@ -106,8 +106,16 @@ func (rq *replicateQueue) shouldQueue(
 		return true, priority
 	}
 	// See if there is a rebalancing opportunity present.
-	shouldRebalance := rq.allocator.ShouldRebalance(repl.store.StoreID())
-	return shouldRebalance, 0
+	if rq.allocator.ShouldRebalance(repl.store.StoreID()) {
+		return true, 0
+	}
+	// See if the lease should be moved to another replica.
+	if lease, _ := repl.getLease(); lease != nil && lease.OwnedBy(repl.store.StoreID()) {
+		_, shouldTransfer := rq.allocator.TransferLeaseTarget(
+			zone.ReplicaAttrs[0], desc.Replicas, repl.store.StoreID())
+		return shouldTransfer, 0
+	}
+	return false, 0
 }
 
 func (rq *replicateQueue) process(
@@ -200,6 +208,16 @@ func (rq *replicateQueue) process(
 				log.Infof("%s: no suitable rebalance target", repl)
 			}
 			log.Trace(ctx, "no suitable rebalance target")
+			if target, ok := rq.allocator.TransferLeaseTarget(
+				zone.ReplicaAttrs[0], desc.Replicas, repl.store.StoreID()); ok {
+				if log.V(1) {
+					log.Infof("%s: transferring lease to %+v", repl, target)
+				}
+				log.Trace(ctx, fmt.Sprintf("transferring lease to %+v", target))
+				// The replica no longer holds the lease after the transfer, so it
+				// must not be re-queued.
+				return repl.AdminTransferLease(target)
+			}
 			// No action was necessary and no rebalance target was found. Return
 			// without re-queuing this replica.
 			return nil
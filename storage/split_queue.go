@@ -38,8 +38,8 @@ const (
 	splitQueueTimerDuration = 0 // zero duration to process splits greedily.
 )
 
-// splitQueue manages a queue of ranges slated to be split due to size
-// or along intersecting zone config boundaries.
+// splitQueue manages a queue of ranges slated to be split due to size,
+// along intersecting zone config boundaries or due to sustained load.
 type splitQueue struct {
 	baseQueue
 	db *client.DB
@@ -60,7 +60,8 @@ func newSplitQueue(store *Store, db *client.DB, gossip *gossip.Gossip) *splitQue
 
 // shouldQueue determines whether a range should be queued for
 // splitting. This is true if the range is intersected by a zone config
-// prefix or if the range's size in bytes exceeds the limit for the zone.
+// prefix, if the range's size in bytes exceeds the limit for the zone or
+// if the range's load splitter has found a key which divides its load.
 func (*splitQueue) shouldQueue(now hlc.Timestamp, rng *Replica,
 	sysCfg config.SystemConfig) (shouldQ bool, priority float64) {
 
@@ -83,6 +84,11 @@ func (*splitQueue) shouldQueue(now hlc.Timestamp, rng *Replica,
 		priority += ratio
 		shouldQ = true
 	}
+
+	if rng.loadSplitter.splitKey() != nil {
+		priority++
+		shouldQ = true
+	}
 	return
 }
 
@@ -124,6 +130,24 @@ func (sq *splitQueue) process(
 		}); pErr != nil {
 			return pErr.GoError()
 		}
+		return nil
+	}
+
+	// Finally handle case of splitting due to load.
+	if splitKey := rng.loadSplitter.splitKey(); splitKey != nil {
+		log.Infof("splitting %s at key %s due to load", rng, splitKey)
+		log.Trace(ctx, fmt.Sprintf("splitting at key %s due to load", splitKey))
+		// Reset the load splitter so that both sides of the split start
+		// measuring their load afresh.
+		rng.loadSplitter.reset()
+		if _, pErr := client.SendWrappedWith(rng, ctx, roachpb.Header{
+			Timestamp: now,
+		}, &roachpb.AdminSplitRequest{
+			Span:     roachpb.Span{Key: desc.StartKey.AsRawKey()},
+			SplitKey: splitKey.AsRawKey(),
+		}); pErr != nil {
+			return pErr.GoError()
+		}
 	}
 	return nil
 }
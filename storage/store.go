@@ -51,6 +51,7 @@ import (
 	"github.com/cockroachdb/cockroach/util/metric"
 	"github.com/cockroachdb/cockroach/util/retry"
 	"github.com/cockroachdb/cockroach/util/stop"
+	"github.com/cockroachdb/cockroach/util/timeutil"
 	"github.com/cockroachdb/cockroach/util/tracing"
 	"github.com/cockroachdb/cockroach/util/uuid"
 )
@@ -286,6 +287,7 @@ type Store struct {
 	rangeIDAlloc            *idAllocator             // Range ID allocator
 	gcQueue                 *gcQueue                 // Garbage collection queue
 	splitQueue              *splitQueue              // Range splitting queue
+	mergeQueue              *mergeQueue              // Range merging queue
 	verifyQueue             *verifyQueue             // Checksum verification queue
 	replicateQueue          *replicateQueue          // Replication queue
 	replicaGCQueue          *replicaGCQueue          // Replica GC queue
@@ -534,6 +536,14 @@ type StoreContext struct {
 	// Tracer is a request tracer.
 	Tracer opentracing.Tracer
 
+	// LoadSplitQPSThreshold is the rate of requests per second above which a
+	// range is split to divide its load. Disabled if <= 0.
+	LoadSplitQPSThreshold int
+
+	// MergeQueueEnabled enables the merge queue, which merges under-sized
+	// ranges into their right hand neighbor.
+	MergeQueueEnabled bool
+
 	// If LogRangeEvents is true, major changes to ranges will be logged into
 	// the range event log.
 	LogRangeEvents bool
@@ -866,11 +876,12 @@ func NewStore(ctx StoreContext, eng engine.Engine, nodeDesc *roachpb.NodeDescrip
 		s.scanner = newReplicaScanner(ctx.ScanInterval, ctx.ScanMaxIdleTime, newStoreRangeSet(s))
 		s.gcQueue = newGCQueue(s, s.ctx.Gossip)
 		s.splitQueue = newSplitQueue(s, s.db, s.ctx.Gossip)
+		s.mergeQueue = newMergeQueue(s, s.ctx.Gossip)
 		s.verifyQueue = newVerifyQueue(s, s.ctx.Gossip, s.ReplicaCount)
 		s.replicateQueue = newReplicateQueue(s, s.ctx.Gossip, s.allocator, s.ctx.Clock, s.ctx.AllocatorOptions)
 		s.replicaGCQueue = newReplicaGCQueue(s, s.db, s.ctx.Gossip)
 		s.raftLogQueue = newRaftLogQueue(s, s.db, s.ctx.Gossip)
-		s.scanner.AddQueues(s.gcQueue, s.splitQueue, s.verifyQueue, s.replicateQueue, s.replicaGCQueue, s.raftLogQueue)
+		s.scanner.AddQueues(s.gcQueue, s.splitQueue, s.mergeQueue, s.verifyQueue, s.replicateQueue, s.replicaGCQueue, s.raftLogQueue)
 
 		// Add consistency check scanner.
 		s.consistencyScanner = newReplicaScanner(ctx.ConsistencyCheckInterval, 0, newStoreRangeSet(s))
@@ -884,6 +895,9 @@ func NewStore(ctx StoreContext, eng engine.Engine, nodeDesc *roachpb.NodeDescrip
 	if ctx.TestingKnobs.DisableReplicateQueue {
 		s.setReplicateQueueActive(false)
 	}
+	if s.mergeQueue != nil && !ctx.MergeQueueEnabled {
+		s.setMergeQueueActive(false)
+	}
 
 	return s
 }
@@ -990,6 +1004,9 @@ func (s *Store) Start(stopper *stop.Stopper) error {
 		if q := s.splitQueue; q != nil {
 			q.Close()
 		}
+		if q := s.mergeQueue; q != nil {
+			q.Close()
+		}
 		if q := s.verifyQueue; q != nil {
 			q.Close()
 		}
@@ -1907,6 +1924,7 @@ func (s *Store) Descriptor() (*roachpb.StoreDescriptor, error) {
 		return nil, err
 	}
 	capacity.RangeCount = int32(s.ReplicaCount())
+	capacity.LeaseCount, capacity.QueriesPerSecond = s.leaseCountAndQPS()
 	// Initialize the store descriptor.
 	return &roachpb.StoreDescriptor{
 		StoreID:  s.Ident.StoreID,
@@ -1923,6 +1941,24 @@ func (s *Store) ReplicaCount() int {
 	return len(s.mu.replicas)
 }
 
+// leaseCountAndQPS returns the number of replicas on this store which hold
+// an active range lease, along with the combined request rate served by all
+// of the store's replicas.
+func (s *Store) leaseCountAndQPS() (int32, float64) {
+	var leaseCount int32
+	var qps float64
+	now := s.Clock().Now()
+	goNow := timeutil.Now()
+	newStoreRangeSet(s).Visit(func(r *Replica) bool {
+		if lease, _ := r.getLease(); lease != nil && lease.OwnedBy(s.StoreID()) && lease.Covers(now) {
+			leaseCount++
+		}
+		qps += r.loadSplitter.qps(goNow)
+		return true
+	})
+	return leaseCount, qps
+}
+
 // Send fetches a range based on the header's replica, assembles method, args &
 // reply into a Raft Cmd struct and executes the command using the fetched
 // range.
@@ -2648,22 +2684,63 @@ func (s *Store) ComputeMetrics() error {
 }
 
 // ComputeStatsForKeySpan computes the aggregated MVCCStats for all replicas on
-// this store which contain any keys in the supplied range.
-func (s *Store) ComputeStatsForKeySpan(startKey, endKey roachpb.RKey) (enginepb.MVCCStats, int) {
+// this store which contain any keys in the supplied range, and returns the
+// number of these replicas. The stats of the replicas entirely contained in the
+// range are the ones they maintain; only the keys that the replicas extending
+// beyond the range hold within it are iterated over.
+func (s *Store) ComputeStatsForKeySpan(
+	startKey, endKey roachpb.RKey,
+) (enginepb.MVCCStats, int, error) {
 	var output enginepb.MVCCStats
 	var count int
+	// The spans of the replicas partially contained in the range, whose stats
+	// are computed outside of the store lock.
+	var partial []roachpb.RSpan
 
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	s.visitReplicasLocked(startKey, endKey, func(repl *Replica) bool {
+		count++
+		desc := repl.Desc()
+		if desc.StartKey.Less(startKey) || endKey.Less(desc.EndKey) {
+			repl.mu.Lock()
+			empty := repl.mu.state.Stats.KeyCount == 0
+			repl.mu.Unlock()
+			if empty {
+				return true
+			}
+			span := roachpb.RSpan{Key: desc.StartKey, EndKey: desc.EndKey}
+			if span.Key.Less(startKey) {
+				span.Key = startKey
+			}
+			if endKey.Less(span.EndKey) {
+				span.EndKey = endKey
+			}
+			partial = append(partial, span)
+			return true
+		}
 		repl.mu.Lock()
 		output.Add(repl.mu.state.Stats)
 		repl.mu.Unlock()
-		count++
 		return true
 	})
+	s.mu.Unlock()
 
-	return output, count
+	if len(partial) > 0 {
+		iter := s.engine.NewIterator(false)
+		defer iter.Close()
+		nowNanos := s.Clock().PhysicalNow()
+		for _, span := range partial {
+			ms, err := iter.ComputeStats(
+				engine.MakeMVCCMetadataKey(span.Key.AsRawKey()),
+				engine.MakeMVCCMetadataKey(span.EndKey.AsRawKey()),
+				nowNanos)
+			if err != nil {
+				return enginepb.MVCCStats{}, 0, err
+			}
+			output.Add(ms)
+		}
+	}
+	return output, count, nil
 }
 
 // FrozenStatus returns all of the Store's Replicas which are frozen (if the
@@ -2697,6 +2774,18 @@ func (s *Store) Reserve(req roachpb.ReservationRequest) roachpb.ReservationRespo
 	return s.bookie.Reserve(req)
 }
 
+// CollectChecksum returns the checksum computed by the store's replica of the
+// range for the consistency check of id.
+func (s *Store) CollectChecksum(
+	ctx context.Context, rangeID roachpb.RangeID, id uuid.UUID,
+) ([]byte, error) {
+	r, err := s.GetReplica(rangeID)
+	if err != nil {
+		return nil, err
+	}
+	return r.collectChecksum(ctx, id)
+}
+
 // The methods below can be used to control a store's queues. Stopping a queue
 // is only meant to happen in tests.
 
@@ -2712,3 +2801,6 @@ func (s *Store) setReplicateQueueActive(active bool) {
 func (s *Store) setSplitQueueActive(active bool) {
 	s.splitQueue.SetDisabled(!active)
 }
+func (s *Store) setMergeQueueActive(active bool) {
+	s.mergeQueue.SetDisabled(!active)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/util/log"
 	"github.com/cockroachdb/cockroach/util/stop"
 	"github.com/cockroachdb/cockroach/util/timeutil"
+	"github.com/cockroachdb/cockroach/util/uuid"
 )
 
 const (
@@ -372,6 +373,10 @@ type StoreList struct {
 	// be rebalance targets (their used capacity percentage must be lower than
 	// maxFractionUsedThreshold).
 	candidateCount stat
+
+	// candidateLeases and candidateQPS track the lease count and request rate
+	// stats for the same stores as candidateCount.
+	candidateLeases, candidateQPS stat
 }
 
 // add includes the store descriptor to the list of stores and updates
@@ -382,6 +387,8 @@ func (sl *StoreList) add(s *roachpb.StoreDescriptor) {
 	sl.used.update(s.Capacity.FractionUsed())
 	if s.Capacity.FractionUsed() <= maxFractionUsedThreshold {
 		sl.candidateCount.update(float64(s.Capacity.RangeCount))
+		sl.candidateLeases.update(float64(s.Capacity.LeaseCount))
+		sl.candidateQPS.update(s.Capacity.QueriesPerSecond)
 	}
 }
 
@@ -498,3 +505,31 @@ func (sp *StorePool) reserve(
 	}
 	return nil
 }
+
+// collectChecksum asks the store of the replica for the checksum it computed
+// for the consistency check of id.
+func (sp *StorePool) collectChecksum(
+	ctx context.Context, replica roachpb.ReplicaDescriptor, rangeID roachpb.RangeID, id uuid.UUID,
+) ([]byte, error) {
+	desc := sp.getStoreDescriptor(replica.StoreID)
+	if desc == nil {
+		return nil, errors.Errorf("store %d does not exist in the store pool", replica.StoreID)
+	}
+	conn, err := sp.rpcContext.GRPCDial(desc.Node.Address.String())
+	if err != nil {
+		return nil, err
+	}
+	client := roachpb.NewInternalClient(conn)
+	resp, err := client.CollectChecksum(ctx, &roachpb.CollectChecksumRequest{
+		StoreRequestHeader: roachpb.StoreRequestHeader{
+			NodeID:  replica.NodeID,
+			StoreID: replica.StoreID,
+		},
+		RangeID:    rangeID,
+		ChecksumID: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Checksum, nil
+}
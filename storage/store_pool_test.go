@@ -455,6 +455,10 @@ func (f *fakeNodeServer) Reserve(_ context.Context, _ *roachpb.ReservationReques
 	return &roachpb.ReservationResponse{Reserved: f.reservationResponse}, f.reservationErr
 }
 
+func (f *fakeNodeServer) CollectChecksum(_ context.Context, _ *roachpb.CollectChecksumRequest) (*roachpb.CollectChecksumResponse, error) {
+	panic("unimplemented")
+}
+
 // newFakeNodeServer returns a fakeNodeServer designed to handle internal
 // node server RPCs, an rpc context used for the server and the fake server's
 // address.
@@ -957,10 +957,12 @@ func PrettyPrintValue(b []byte, sep string) string {
 	for len(b) > 0 {
 		bb, s, err := prettyPrintFirstValue(b)
 		if err != nil {
+			// The decoders don't reliably consume anything on error, so
+			// print the error and stop rather than loop on corrupt input.
 			fmt.Fprintf(&buf, "%s<%v>", sep, err)
-		} else {
-			fmt.Fprintf(&buf, "%s%s", sep, s)
+			break
 		}
+		fmt.Fprintf(&buf, "%s%s", sep, s)
 		b = bb
 	}
 	return buf.String()